@@ -0,0 +1,49 @@
+package browser
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// nativeTools lists the commands that open a URL in the default browser, in
+// order of preference
+var nativeTools = []string{"open", "xdg-open", "wslview", "explorer.exe"}
+
+// Open opens a URL in the browser: the one $BROWSER names when set, or the
+// system's default browser through its native opener (open, xdg-open, ...)
+func Open(url string) error {
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		if err := start(browser, url); err != nil {
+			return fmt.Errorf("$BROWSER (%s) failed: %w", browser, err)
+		}
+		return nil
+	}
+
+	for _, tool := range nativeTools {
+		if tool == "open" && runtime.GOOS != "darwin" {
+			continue
+		}
+		if _, err := exec.LookPath(tool); err != nil {
+			continue
+		}
+		if err := start(tool, url); err != nil {
+			return fmt.Errorf("%s failed: %w", tool, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no browser opener found (install xdg-open or set $BROWSER)")
+}
+
+// start runs a browser command on a URL without waiting for the browser to
+// close
+func start(command, url string) error {
+	cmd := exec.Command(command, url)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
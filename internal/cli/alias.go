@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+)
+
+// aliasExpansion is the result of resolving a user-defined alias
+type aliasExpansion struct {
+	Args  []string // Arguments for cobra when the alias runs a cwt command
+	Shell string   // Shell command when the alias starts with "!"
+}
+
+// resolveAlias expands args when the first command word is a configured alias.
+// Built-in commands always win, and aliases may refer to other aliases.
+// Args without an alias are returned unchanged.
+func resolveAlias(rootCmd *cobra.Command, args []string, aliases map[string]string) (aliasExpansion, error) {
+	idx := commandIndex(args)
+	if idx < 0 {
+		return aliasExpansion{Args: args}, nil
+	}
+
+	expanded := append([]string(nil), args...)
+	seen := make(map[string]bool)
+	for {
+		name := expanded[idx]
+		value, ok := aliases[name]
+		if !ok || isBuiltinCommand(rootCmd, name) {
+			return aliasExpansion{Args: expanded}, nil
+		}
+		if seen[name] {
+			return aliasExpansion{}, fmt.Errorf("alias loop detected while expanding '%s'", name)
+		}
+		seen[name] = true
+
+		if strings.HasPrefix(value, "!") {
+			return aliasExpansion{Shell: shellAliasCommand(value[1:], expanded[idx+1:])}, nil
+		}
+
+		words, err := splitAliasWords(value)
+		if err != nil {
+			return aliasExpansion{}, fmt.Errorf("invalid alias '%s': %w", name, err)
+		}
+
+		rest := append(words, expanded[idx+1:]...)
+		expanded = append(expanded[:idx:idx], rest...)
+	}
+}
+
+// commandIndex returns the position of the first command word in args,
+// skipping global flags and their values, or -1 if there is none
+func commandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return i
+		}
+		// Global flags that take a separate value
+		if arg == "--data-dir" || arg == "--base-branch" {
+			i++
+		}
+	}
+	return -1
+}
+
+// isBuiltinCommand reports whether name is a cwt subcommand or one of its aliases
+func isBuiltinCommand(rootCmd *cobra.Command, name string) bool {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	// Commands cobra adds on demand
+	return name == "help" || name == "completion" || name == cobra.ShellCompRequestCmd
+}
+
+// shellAliasCommand appends extra arguments to a shell alias, quoted so the
+// shell sees them as they were typed
+func shellAliasCommand(command string, extra []string) string {
+	command = strings.TrimSpace(command)
+	for _, arg := range extra {
+		command += " '" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return command
+}
+
+// splitAliasWords splits an alias into arguments, honoring single and double quotes
+func splitAliasWords(value string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+
+	for _, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// loadAliases reads aliases from the config files before flags are parsed,
+// honoring an explicit --data-dir so project aliases are found
+func loadAliases(args []string) map[string]string {
+	projectDir := config.DefaultDataDir
+	for i, arg := range args {
+		if arg == "--data-dir" && i+1 < len(args) {
+			projectDir = args[i+1]
+		} else if value, ok := strings.CutPrefix(arg, "--data-dir="); ok {
+			projectDir = value
+		}
+	}
+
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		// The command itself reports config errors once flags are parsed
+		return nil
+	}
+	return cfg.Aliases
+}
+
+// runShellAlias runs a shell alias in the current directory and exits with its status
+func runShellAlias(command string) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "Error: failed to run alias: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
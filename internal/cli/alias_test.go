@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveAlias(t *testing.T) {
+	aliases := map[string]string{
+		"pp":   "publish --pr",
+		"wip":  `publish -m "work in progress"`,
+		"ppp":  "pp --draft",
+		"t":    "!go test ./...",
+		"list": "status", // Shadowed by the built-in command
+		"loop": "loop",
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantArgs  string
+		wantShell string
+		wantErr   bool
+	}{
+		{name: "no alias", args: []string{"list"}, wantArgs: "list"},
+		{name: "no command", args: []string{"--help"}, wantArgs: "--help"},
+		{name: "command alias", args: []string{"pp", "my-session"}, wantArgs: "publish --pr my-session"},
+		{name: "after global flags", args: []string{"--data-dir", "/tmp/x", "pp", "s"}, wantArgs: "--data-dir /tmp/x publish --pr s"},
+		{name: "quoted words", args: []string{"wip", "s"}, wantArgs: "publish|-m|work in progress|s"},
+		{name: "chained alias", args: []string{"ppp", "s"}, wantArgs: "publish --pr --draft s"},
+		{name: "built-in wins", args: []string{"list"}, wantArgs: "list"},
+		{name: "shell alias", args: []string{"t", "-run", "it's"}, wantShell: `go test ./... '-run' 'it'\''s'`},
+		{name: "loop", args: []string{"loop"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAlias(NewRootCmd(), tt.args, aliases)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("resolveAlias() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveAlias() error = %v", err)
+			}
+			if got.Shell != tt.wantShell {
+				t.Errorf("Shell = %q, want %q", got.Shell, tt.wantShell)
+			}
+			if tt.wantShell != "" {
+				return
+			}
+
+			sep := " "
+			if strings.Contains(tt.wantArgs, "|") {
+				sep = "|"
+			}
+			if joined := strings.Join(got.Args, sep); joined != tt.wantArgs {
+				t.Errorf("Args = %q, want %q", joined, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newArchiveCmd() *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "archive <session-name>",
+		Short: "Move a finished session out of the active list, keeping its history",
+		Long: `Archive a CWT session into .cwt/archive/<session-id>/, keeping:
+- Session metadata (session.json)
+- Final diff of its branch against the base branch (changes.diff)
+- Commit log of its branch (commits.log)
+- Summary of its Claude conversations (transcript.md)
+- Timeline shown by 'cwt log' (events.jsonl)
+
+The tmux session and worktree are removed; the branch is kept, so the
+session can be brought back with 'cwt archive restore'. Sessions with
+uncommitted changes can't be archived.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runArchiveCmd(args[0], reason)
+		},
+	}
+
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the session is archived, shown in 'cwt archive list'")
+
+	cmd.AddCommand(newArchiveListCmd())
+	cmd.AddCommand(newArchiveRestoreCmd())
+
+	return cmd
+}
+
+func runArchiveCmd(name, reason string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	_, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+
+	if reason == "" {
+		reason = "archived by hand"
+	}
+	if err := sm.ArchiveSession(sessionID, reason); err != nil {
+		return fmt.Errorf("failed to archive session '%s': %w", name, err)
+	}
+
+	fmt.Printf("📦 Archived session '%s' to %s\n", name, filepath.Join(sm.ArchiveDir(), sessionID))
+	fmt.Printf("Restore with: cwt archive restore %s\n", name)
+	return nil
+}
+
+func newArchiveListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List archived sessions",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runArchiveListCmd(jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output archived sessions as JSON")
+
+	return cmd
+}
+
+func runArchiveListCmd(jsonOutput bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	archived, err := sm.ArchivedSessions()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if archived == nil {
+			archived = []types.ArchivedSession{}
+		}
+		return writeJSON(archived)
+	}
+
+	if len(archived) == 0 {
+		fmt.Println("No archived sessions.")
+		return nil
+	}
+
+	formatter := operations.NewStatusFormat()
+	fmt.Printf("Found %d archived session(s):\n", len(archived))
+	for i := len(archived) - 1; i >= 0; i-- {
+		record := archived[i]
+		fmt.Printf("\n📦 %s (branch: %s)\n", record.Core.Name, record.Branch)
+		fmt.Printf("   Archived: %s", formatter.FormatActivity(record.ArchivedAt))
+		if record.Reason != "" {
+			fmt.Printf(" (%s)", record.Reason)
+		}
+		fmt.Println()
+		fmt.Printf("   History:  %s\n", archiveHistory(sm, record))
+	}
+	return nil
+}
+
+// archiveHistory lists the history files kept for an archived session
+func archiveHistory(sm *state.Manager, record types.ArchivedSession) string {
+	dir := filepath.Join(sm.ArchiveDir(), record.Core.ID)
+	history := dir + string(filepath.Separator)
+	var files []string
+	for _, name := range []string{state.ArchiveDiffFile, state.ArchiveLogFile, state.ArchiveTranscriptFile, state.ArchiveEventsFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			files = append(files, name)
+		}
+	}
+	if len(files) == 0 {
+		return history
+	}
+	return fmt.Sprintf("%s{%s}", history, strings.Join(files, ","))
+}
+
+func newArchiveRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <session-name>",
+		Short: "Bring an archived session back into the active list",
+		Long: `Restore an archived CWT session: its branch is checked out in a new
+worktree and a tmux session resumes the Claude conversation it had when it
+was archived. When several archived sessions share the name, the most
+recently archived one is restored.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeArchivedNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runArchiveRestoreCmd(args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runArchiveRestoreCmd(name string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	record, err := findArchivedSession(sm, name)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.RestoreArchivedSession(record.Core.ID); err != nil {
+		return fmt.Errorf("failed to restore session '%s': %w", name, err)
+	}
+
+	fmt.Printf("✅ Restored session '%s' on branch %s\n", name, record.Branch)
+	fmt.Printf("Attach with: cwt attach %s\n", name)
+	return nil
+}
+
+// findArchivedSession returns the most recently archived session with a name
+func findArchivedSession(sm *state.Manager, name string) (types.ArchivedSession, error) {
+	archived, err := sm.ArchivedSessions()
+	if err != nil {
+		return types.ArchivedSession{}, err
+	}
+	for i := len(archived) - 1; i >= 0; i-- {
+		if archived[i].Core.Name == name {
+			return archived[i], nil
+		}
+	}
+	return types.ArchivedSession{}, fmt.Errorf("archived session '%s' not found", name)
+}
+
+// completeArchivedNames completes the name argument of 'cwt archive restore'
+func completeArchivedNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	sm, err := completionManager(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer sm.Close()
+
+	archived, err := sm.ArchivedSessions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, record := range archived {
+		if !seen[record.Core.Name] {
+			seen[record.Core.Name] = true
+			names = append(names, record.Core.Name)
+		}
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
@@ -22,9 +22,10 @@ tmux session names (cwt-{session-name}).
 
 If session-name is not provided, you will be prompted to select
 from available sessions.`,
-		Aliases: []string{"a"},
-		Args:    cobra.MaximumNArgs(1),
-		RunE:    runAttachCmd,
+		Aliases:           []string{"a"},
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE:              runAttachCmd,
 	}
 
 	return cmd
@@ -74,13 +75,28 @@ func runAttachCmd(cmd *cobra.Command, args []string) error {
 		sessionToAttach = selected
 	}
 
-	// Check if tmux session is alive
-	if !sessionToAttach.IsAlive {
+	// A paused session is resumed without asking; attaching is the request
+	if !sessionToAttach.IsAlive && sessionToAttach.Core.IsPaused() {
+		fmt.Printf("💤 Session '%s' is paused, resuming it...\n", sessionToAttach.Core.Name)
+		if err := sm.ResumeSession(sessionToAttach.Core.ID); err != nil {
+			return fmt.Errorf("failed to resume session: %w", err)
+		}
+	} else if !sessionToAttach.IsAlive {
 		fmt.Printf("⚠️  Tmux session for '%s' is not running.\n", sessionToAttach.Core.Name)
-		fmt.Printf("This might happen if:\n")
-		fmt.Printf("  • The Claude Code process exited\n")
-		fmt.Printf("  • The tmux session was manually terminated\n")
-		fmt.Printf("  • There was a system restart\n\n")
+		if exit := sessionToAttach.Exit; exit != nil {
+			// The exit hook recorded what Claude printed before it stopped
+			fmt.Printf("Claude %s, %s. Its last output was:\n", exit.Summary(),
+				operations.NewStatusFormat().FormatActivity(exit.Time))
+			for _, line := range exit.Tail(10) {
+				fmt.Printf("  │ %s\n", line)
+			}
+			fmt.Println()
+		} else {
+			fmt.Printf("This might happen if:\n")
+			fmt.Printf("  • The Claude Code process exited\n")
+			fmt.Printf("  • The tmux session was manually terminated\n")
+			fmt.Printf("  • There was a system restart\n\n")
+		}
 
 		// Ask user if they want to recreate the session
 		fmt.Printf("Do you want to recreate the tmux session? (y/N): ")
@@ -100,6 +116,8 @@ func runAttachCmd(cmd *cobra.Command, args []string) error {
 		fmt.Printf("✅ Session '%s' recreated successfully\n", sessionToAttach.Core.Name)
 	}
 
+	sm.RecordEvent(sessionToAttach.Core.ID, types.EventAttached, "Attached", nil)
+
 	// Attach to tmux session using shared operations function
 	return operations.AttachToTmuxSession(sessionToAttach.Core.Name, sessionToAttach.Core.TmuxSession)
 }
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+// batchErrorWidth limits errors in the live progress table to one line;
+// the full errors are listed once the batch is done
+const batchErrorWidth = 60
+
+func runNewBatchCmd(batchFile string, parallel int, ignoreLimits bool) error {
+	tasks, err := operations.ParseBatchFile(batchFile)
+	if err != nil {
+		return err
+	}
+
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+	if ignoreLimits {
+		sm.SetLimits(state.Limits{})
+	}
+
+	if parallel <= 0 {
+		parallel = appConfig.MaxParallel
+	}
+	parallel = min(parallel, len(tasks))
+
+	fmt.Printf("Creating %d sessions, %d at a time...\n\n", len(tasks), parallel)
+
+	table := newBatchTable(os.Stdout, tasks, isatty.IsTerminal(os.Stdout.Fd()))
+	table.draw()
+
+	// Ctrl+C rolls back the sessions being created and skips the rest
+	ctx, stop := interruptContext()
+	defer stop()
+
+	start := time.Now()
+	results := operations.NewSessionOperations(sm).CreateBatch(ctx, tasks, parallel, table.update)
+	stop()
+
+	var failed []operations.BatchResult
+	for _, result := range results {
+		if result.Status == operations.BatchFailed {
+			failed = append(failed, result)
+		}
+	}
+
+	fmt.Printf("\n✅ Created %d of %d sessions in %s\n", len(results)-len(failed), len(results), time.Since(start).Round(time.Second))
+	warnTokenBudget(sm)
+	if len(failed) > 0 {
+		fmt.Println("\n❌ Failed:")
+		var limitErr error
+		for _, result := range failed {
+			fmt.Printf("  • %s: %v\n", result.Task.Name, result.Err)
+			if errors.As(result.Err, new(*state.LimitError)) {
+				limitErr = result.Err
+			}
+		}
+		if limitErr != nil {
+			limitHint(limitErr)
+		}
+		return fmt.Errorf("%d of %d sessions failed", len(failed), len(results))
+	}
+
+	fmt.Println("\n💡 Attach with 'cwt attach <name>' or watch them all with 'cwt'")
+	return nil
+}
+
+// batchTable shows the progress of a batch, one row per session. On a
+// terminal the table is redrawn in place; otherwise each session gets a line
+// once it is done, so logs stay readable.
+type batchTable struct {
+	out       io.Writer
+	live      bool
+	results   []operations.BatchResult
+	nameWidth int
+	drawn     int // Rows drawn so far, to move back over when redrawing
+}
+
+func newBatchTable(out io.Writer, tasks []operations.BatchTask, live bool) *batchTable {
+	t := &batchTable{out: out, live: live}
+	for _, task := range tasks {
+		t.results = append(t.results, operations.BatchResult{Task: task, Status: operations.BatchPending})
+		t.nameWidth = max(t.nameWidth, len(task.Name))
+	}
+	return t
+}
+
+// update records a session's new status and shows it
+func (t *batchTable) update(index int, result operations.BatchResult) {
+	t.results[index] = result
+	if t.live {
+		t.draw()
+	} else if result.Status == operations.BatchCreated || result.Status == operations.BatchFailed {
+		fmt.Fprintln(t.out, t.row(result))
+	}
+}
+
+// draw redraws the whole table over the previous one
+func (t *batchTable) draw() {
+	if !t.live {
+		return
+	}
+	if t.drawn > 0 {
+		fmt.Fprintf(t.out, "\033[%dA", t.drawn)
+	}
+	for _, result := range t.results {
+		fmt.Fprintf(t.out, "\033[2K%s\n", t.row(result))
+	}
+	t.drawn = len(t.results)
+}
+
+func (t *batchTable) row(result operations.BatchResult) string {
+	var icon, status string
+	switch result.Status {
+	case operations.BatchPending:
+		icon, status = "⏸️ ", "pending"
+	case operations.BatchCreating:
+		icon, status = "⏳", "creating..."
+	case operations.BatchCreated:
+		icon, status = "✅", fmt.Sprintf("created in %s", result.Duration.Round(100*time.Millisecond))
+	case operations.BatchFailed:
+		icon, status = "❌", "failed: "+firstLine(result.Err.Error())
+		if t.live {
+			status = runewidth.Truncate(status, batchErrorWidth, "...")
+		}
+	}
+	return fmt.Sprintf("  %s %-*s  %s", icon, t.nameWidth, result.Task.Name, status)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
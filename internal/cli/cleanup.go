@@ -2,13 +2,16 @@ package cli
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/jlaneve/cwt-cli/internal/config"
 	"github.com/jlaneve/cwt-cli/internal/operations"
 	"github.com/spf13/cobra"
 )
 
 func newCleanupCmd() *cobra.Command {
 	var dryRun bool
+	var expired bool
 
 	cmd := &cobra.Command{
 		Use:   "cleanup",
@@ -18,13 +21,23 @@ func newCleanupCmd() *cobra.Command {
 - Unused git worktrees
 - Stale session metadata
 
-This helps maintain a clean state after crashes or manual tmux session termination.`,
+This helps maintain a clean state after crashes or manual tmux session termination.
+
+With --expired it applies the expiry policy from the config instead:
+sessions idle longer than expiry.archive_idle are archived (their worktree
+and tmux session are removed, their branch is kept), and archived sessions
+older than expiry.delete_archived are deleted along with their branch.
+A running 'cwt daemon' applies the same policy on its own.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if expired {
+				return runExpiredCleanup(dryRun)
+			}
 			return runCleanupCmd(dryRun)
 		},
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be cleaned up without actually doing it")
+	cmd.Flags().BoolVar(&expired, "expired", false, "Archive idle sessions and delete old archives per the expiry policy")
 
 	return cmd
 }
@@ -82,3 +95,70 @@ func runCleanupCmd(dryRun bool) error {
 
 	return nil
 }
+
+// expiryPolicy returns the session expiry policy configured in cfg
+func expiryPolicy(cfg *config.Config) operations.ExpiryPolicy {
+	return operations.ExpiryPolicy{
+		ArchiveIdle:    cfg.Expiry.ArchiveIdle,
+		DeleteArchived: cfg.Expiry.DeleteArchived,
+		WarnBefore:     cfg.Expiry.WarnBefore,
+	}
+}
+
+func runExpiredCleanup(dryRun bool) error {
+	policy := expiryPolicy(appConfig)
+	if !policy.Enabled() {
+		fmt.Println("No expiry policy is configured.")
+		fmt.Println("Set expiry.archive_idle and/or expiry.delete_archived in the config file, e.g.:")
+		fmt.Println("  expiry:")
+		fmt.Println("    archive_idle: 168h     # archive sessions idle for 7 days")
+		fmt.Println("    delete_archived: 720h  # delete archives after 30 days")
+		return nil
+	}
+
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	expiryOps := operations.NewExpiryOperations(sm, policy)
+	now := time.Now()
+	due, _, err := expiryOps.Plan(now)
+	if err != nil {
+		return fmt.Errorf("cleanup failed: %w", err)
+	}
+
+	if len(due) == 0 {
+		fmt.Println("✅ No sessions have expired.")
+		return nil
+	}
+
+	fmt.Printf("Expired sessions:\n")
+	for _, expiration := range due {
+		fmt.Printf("  ⏳ %s: %s\n", expiration.Name, expiration.Describe(now))
+	}
+	fmt.Println()
+
+	if dryRun {
+		fmt.Println("🔍 Dry run mode - no changes made.")
+		fmt.Println("Run 'cwt cleanup --expired' to apply the expiry policy.")
+		return nil
+	}
+
+	stats := expiryOps.Apply(due)
+	fmt.Printf("🧹 Expiry applied!\n")
+	fmt.Printf("  📦 Archived: %d\n", stats.Archived)
+	fmt.Printf("  🗑️  Deleted: %d\n", stats.Deleted)
+	if stats.Skipped > 0 {
+		fmt.Printf("  ⏸️  Skipped: %d (resolve what blocks them, e.g. commit their changes)\n", stats.Skipped)
+	}
+	if stats.Failed > 0 {
+		fmt.Printf("  ❌ Failed: %d\n", stats.Failed)
+		for _, errMsg := range stats.Errors {
+			fmt.Printf("    - %s\n", errMsg)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+// completeSessionNames completes the session name argument of commands that
+// take one. Only sessions.json is read so completion stays fast.
+func completeSessionNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterCompletions(sessionNames(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeManySessionNames completes the session names of commands that take
+// several, leaving out those already given
+func completeManySessionNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, name := range sessionNames(cmd) {
+		if !slices.Contains(args, name) {
+			names = append(names, name)
+		}
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionConfig loads the config for a completion function, since Cobra
+// skips the PersistentPreRunE hooks when completing
+func completionConfig(cmd *cobra.Command) error {
+	return loadConfig(cmd)
+}
+
+// completionManager creates a state manager for a completion function; the
+// caller closes it
+func completionManager(cmd *cobra.Command) (*state.Manager, error) {
+	if err := completionConfig(cmd); err != nil {
+		return nil, err
+	}
+	return state.NewManager(state.Config{DataDir: dataDir, BaseBranch: baseBranch}), nil
+}
+
+// sessionNames reads the names of the sessions for completion
+func sessionNames(cmd *cobra.Command) []string {
+	sm, err := completionManager(cmd)
+	if err != nil {
+		return nil
+	}
+	defer sm.Close()
+
+	cores, err := sm.CoreSessions()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, core := range cores {
+		names = append(names, core.Name)
+	}
+	return names
+}
+
+// completeBranches completes branch-valued flags from the repository's local
+// and remote-tracking branches
+func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	branches, err := git.NewRealChecker(baseBranch).ListBranches()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterCompletions(branches, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// filterCompletions returns the sorted candidates that start with prefix
+func filterCompletions(candidates []string, prefix string) []string {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestCompleteSessionNames(t *testing.T) {
+	dir := t.TempDir()
+	data, _ := json.Marshal(types.SessionData{Sessions: []types.CoreSession{
+		{ID: "1", Name: "fix-login"},
+		{ID: "2", Name: "feature-search"},
+		{ID: "3", Name: "fix-logout"},
+	}})
+	if err := os.WriteFile(filepath.Join(dir, "sessions.json"), data, 0644); err != nil {
+		t.Fatalf("failed to write sessions: %v", err)
+	}
+
+	cmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"__complete", "--data-dir", dir, "attach", "fix"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	// The last line is the shell directive
+	got := lines[:len(lines)-1]
+	if strings.Join(got, ",") != "fix-login,fix-logout" {
+		t.Errorf("completions = %v, want [fix-login fix-logout]", got)
+	}
+}
+
+func TestFilterCompletions(t *testing.T) {
+	got := filterCompletions([]string{"main", "origin/main", "feature", "mainline"}, "main")
+	if strings.Join(got, ",") != "main,mainline" {
+		t.Errorf("filterCompletions() = %v, want [main mainline]", got)
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+)
+
+// newContextCmd creates the 'cwt context' command
+func newContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Share context files, like a conventions document, with every session",
+		Long: `Register files of the repository that every session should follow, like an
+architecture or conventions document, so all of Claude's sessions stay
+aligned on them.
+
+Each session's worktree gets the shared files under ` + git.ContextDir + `,
+symlinked to the repository's copy so edits reach every session (or copied
+where symlinks aren't supported), and Claude's initial prompt in a session
+started with a task tells it to read them. The files there are left out of
+commits and diffs.
+
+Registering or removing a file updates the worktrees of the existing
+sessions too; only new sessions get the prompt.
+
+Examples:
+  cwt context set docs/architecture.md    # Share a file with every session
+  cwt context                             # List the shared files
+  cwt context remove docs/architecture.md # Stop sharing it`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListContext()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <file>...",
+		Short: "Share files with every session",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSetContext(args)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the files shared with every session",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListContext()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "remove <file>...",
+		Aliases: []string{"unset", "rm"},
+		Short:   "Stop sharing files with every session",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemoveContext(args)
+		},
+		ValidArgsFunction: completeContextFiles,
+	})
+
+	return cmd
+}
+
+func runSetContext(files []string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	added, err := sm.AddContextFiles(files)
+	if err != nil {
+		return fmt.Errorf("failed to share context files: %w", err)
+	}
+	for _, file := range added {
+		fmt.Printf("📚 Sharing %s with every session (%s/%s)\n", file, git.ContextDir, file)
+	}
+	return nil
+}
+
+func runRemoveContext(files []string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	if err := sm.RemoveContextFiles(files); err != nil {
+		return fmt.Errorf("failed to stop sharing context files: %w", err)
+	}
+	for _, file := range files {
+		fmt.Printf("Stopped sharing %s\n", file)
+	}
+	return nil
+}
+
+func runListContext() error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	files, err := sm.ContextFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("No shared context files.")
+		fmt.Println("\nShare one with every session with: cwt context set <file>")
+		return nil
+	}
+
+	fmt.Printf("Shared with every session (under %s):\n", git.ContextDir)
+	for _, file := range files {
+		fmt.Printf("  📚 %s\n", file)
+	}
+	return nil
+}
+
+// completeContextFiles completes the shared context files
+func completeContextFiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	sm, err := createStateManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer sm.Close()
+
+	files, _ := sm.ContextFiles()
+	return files, cobra.ShellCompDirectiveNoFileComp
+}
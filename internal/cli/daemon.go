@@ -0,0 +1,341 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/daemon"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// expiryCheckInterval is how often the daemon applies the expiry policy
+const expiryCheckInterval = time.Hour
+
+// limitsCheckInterval is how often the daemon records token usage and checks the limits
+const limitsCheckInterval = time.Minute
+
+// newDaemonCmd creates the 'cwt daemon' command
+func newDaemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run a background daemon that keeps session status up to date",
+		Long: `Run a long-running daemon that watches all sessions (Claude hooks, data
+directory changes, git and tmux polling) and serves their status over a
+Unix socket in the data directory.
+
+While the daemon is running, the TUI and CLI commands query it instead of
+re-deriving every session themselves. If it isn't running, they derive
+status directly as usual. Use --no-daemon to bypass a running daemon.
+
+When an expiry policy is configured, the daemon also archives idle sessions
+and deletes old archives, checking once an hour (see 'cwt cleanup --expired').
+It also runs the follow-up commands registered with 'cwt on', records the
+tokens Claude uses, warns when the monthly token budget runs low or
+Claude works in more sessions than the limits allow (see 'cwt limits'), and
+keeps track of the review and CI checks of published pull requests.
+
+On start, it reports sessions whose tmux session died or whose worktree or
+branch went missing, and cwt tmux sessions and worktrees no session owns,
+with the command that fixes each; the TUI offers to fix them too.
+
+The daemon runs in the foreground; start it in a spare terminal or with
+your process manager of choice.
+
+Examples:
+  cwt daemon          # Run the daemon in the foreground
+  cwt daemon status   # Check whether a daemon is running
+  cwt daemon stop     # Stop the running daemon`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemon()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show whether a daemon is running",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showDaemonStatus()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return stopDaemon()
+		},
+	})
+
+	return cmd
+}
+
+// reconcileHints are the commands that fix each kind of discrepancy
+var reconcileHints = map[state.DriftKind]string{
+	state.DriftDeadTmux:         "cwt attach %s",
+	state.DriftMissingWorktree:  "cwt repair %s",
+	state.DriftMissingBranch:    "cwt delete %s --force",
+	state.DriftOrphanedTmux:     "cwt cleanup",
+	state.DriftOrphanedWorktree: "cwt cleanup",
+}
+
+// printReconcileReport reports what drifted apart between the sessions and
+// their tmux sessions, worktrees and branches while no daemon was running
+func printReconcileReport(sm *state.Manager) {
+	report, err := sm.Reconcile()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to check the sessions: %v\n", err)
+		return
+	}
+	if report.Empty() {
+		return
+	}
+
+	fmt.Printf("\n⚠️  Found %d problem(s) with the sessions (the dashboard offers to fix them):\n", len(report.Discrepancies))
+	for _, d := range report.Discrepancies {
+		hint := reconcileHints[d.Kind]
+		if strings.Contains(hint, "%s") {
+			hint = fmt.Sprintf(hint, d.Session)
+		}
+		fmt.Printf("   %-60s %s\n", d.Describe(), hint)
+	}
+	fmt.Println()
+}
+
+func runDaemon() error {
+	// The daemon derives everything itself, so it must never use another daemon
+	sm, err := newStateManager(false)
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	server := daemon.NewServer(sm, daemon.Options{
+		GitInterval:  appConfig.Polling.GitInterval,
+		TmuxInterval: appConfig.Polling.TmuxInterval,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	expiry := make(chan operations.ExpiryPolicy)
+	go enforceExpiry(ctx, sm, expiryPolicy(appConfig), expiry)
+	go monitorLimits(ctx, sm, server)
+	go monitorPullRequests(ctx, sm)
+
+	// Polling intervals, the status cache and the expiry policy follow
+	// config file edits
+	if watcher, err := config.NewWatcher(dataDir); err == nil {
+		defer watcher.Close()
+		go reloadDaemonConfig(ctx, watcher, sm, server, expiry)
+	}
+
+	fmt.Printf("🛰️  cwt daemon serving %s (pid %d)\n", daemon.SocketPath(dataDir), os.Getpid())
+	fmt.Println("Press Ctrl+C to stop.")
+	printReconcileReport(sm)
+
+	if err := server.Run(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("cwt daemon stopped")
+	return nil
+}
+
+// reloadDaemonConfig applies config file changes to a running daemon
+func reloadDaemonConfig(ctx context.Context, watcher *config.Watcher, sm *state.Manager, server *daemon.Server, expiry chan<- operations.ExpiryPolicy) {
+	current := appConfig
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-watcher.Changes():
+		}
+
+		next, err := reloadConfig()
+		if err != nil {
+			fmt.Printf("⚠️  Keeping the current config: %v\n", err)
+			continue
+		}
+
+		cfg, changed := current.Reloaded(next)
+		if len(changed) == 0 {
+			continue
+		}
+		current = cfg
+
+		sm.SetStatusCacheTTL(cfg.StatusCacheTTL)
+		sm.SetLimits(stateLimits(cfg.Limits))
+		server.SetOptions(daemon.Options{
+			GitInterval:  cfg.Polling.GitInterval,
+			TmuxInterval: cfg.Polling.TmuxInterval,
+		})
+		if slices.Contains(changed, "expiry") {
+			select {
+			case expiry <- expiryPolicy(cfg):
+			case <-ctx.Done():
+				return
+			}
+		}
+		sm.NotifyConfigReloaded(changed)
+		fmt.Printf("🔄 Reloaded config: %s\n", strings.Join(changed, ", "))
+	}
+}
+
+// enforceExpiry applies the expiry policy on start, every
+// expiryCheckInterval and whenever a config reload changes the policy
+func enforceExpiry(ctx context.Context, sm *state.Manager, policy operations.ExpiryPolicy, updates <-chan operations.ExpiryPolicy) {
+	ticker := time.NewTicker(expiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if policy.Enabled() {
+			stats, err := operations.NewExpiryOperations(sm, policy).Enforce()
+			switch {
+			case err != nil:
+				fmt.Printf("⚠️  Expiry check failed: %v\n", err)
+			case stats.Archived > 0 || stats.Deleted > 0:
+				fmt.Printf("📦 Expiry: archived %d, deleted %d\n", stats.Archived, stats.Deleted)
+			}
+			if err == nil {
+				for _, errMsg := range stats.Errors {
+					fmt.Printf("⚠️  %s\n", errMsg)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case policy = <-updates:
+		}
+	}
+}
+
+// monitorLimits records the tokens Claude used every limitsCheckInterval,
+// warning once when the token budget runs low, once when it is used up, and
+// whenever Claude starts working in more sessions than allowed
+func monitorLimits(ctx context.Context, sm *state.Manager, server *daemon.Server) {
+	ticker := time.NewTicker(limitsCheckInterval)
+	defer ticker.Stop()
+
+	var warned string // Month and level of the last budget warning
+	overWorking := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		sessions := server.Snapshot()
+		budget, err := sm.RecordTokenUsage(sessions)
+		if err != nil {
+			fmt.Printf("⚠️  Recording token usage failed: %v\n", err)
+			continue
+		}
+		switch level := budget.Month + "/" + budgetLevel(budget); {
+		case budget.Exceeded() && warned != level:
+			fmt.Printf("🔴 Token budget used up: %s; new sessions are refused\n", budget.Summary())
+			warned = level
+		case budget.Warning() && warned != level:
+			fmt.Printf("🟡 Token budget running low: %s\n", budget.Summary())
+			warned = level
+		}
+
+		limit := sm.Limits().MaxWorking
+		working := state.WorkingSessions(sessions)
+		if limit > 0 && working > limit && !overWorking {
+			fmt.Printf("🟡 Claude is working in %d sessions, more than limits.max_working (%d)\n", working, limit)
+		}
+		overWorking = limit > 0 && working > limit
+	}
+}
+
+// monitorPullRequests checks where the sessions' open pull requests stand
+// every state.PullRequestCheckInterval, announcing the ones that changed
+func monitorPullRequests(ctx context.Context, sm *state.Manager) {
+	ticker := time.NewTicker(state.PullRequestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if changed := sm.RefreshPullRequests(0); changed > 0 {
+			fmt.Printf("🔀 %d pull request(s) changed status\n", changed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// budgetLevel names how much of the budget is used, for warning once per level
+func budgetLevel(budget types.TokenBudget) string {
+	switch {
+	case budget.Exceeded():
+		return "exceeded"
+	case budget.Warning():
+		return "warning"
+	default:
+		return "ok"
+	}
+}
+
+func showDaemonStatus() error {
+	client, err := daemon.Connect(dataDir)
+	if err != nil {
+		if errors.Is(err, daemon.ErrNotRunning) {
+			fmt.Println("🔴 cwt daemon is not running")
+			return nil
+		}
+		return err
+	}
+
+	status, err := client.Status()
+	if err != nil {
+		return fmt.Errorf("failed to query daemon: %w", err)
+	}
+
+	fmt.Println("🟢 cwt daemon is running")
+	fmt.Printf("   PID:       %d\n", status.PID)
+	fmt.Printf("   Socket:    %s\n", daemon.SocketPath(dataDir))
+	fmt.Printf("   Uptime:    %s\n", time.Since(status.StartedAt).Round(time.Second))
+	fmt.Printf("   Refreshed: %s ago\n", time.Since(status.DerivedAt).Round(time.Second))
+	fmt.Printf("   Sessions:  %d\n", status.SessionCount)
+	return nil
+}
+
+func stopDaemon() error {
+	client, err := daemon.Connect(dataDir)
+	if err != nil {
+		if errors.Is(err, daemon.ErrNotRunning) {
+			fmt.Println("cwt daemon is not running")
+			return nil
+		}
+		return err
+	}
+
+	if err := client.Shutdown(); err != nil {
+		return fmt.Errorf("failed to stop daemon: %w", err)
+	}
+
+	fmt.Println("✅ cwt daemon stopped")
+	return nil
+}
@@ -2,41 +2,75 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
 func newDeleteCmd() *cobra.Command {
-	var force bool
+	var opts deleteOptions
 
 	cmd := &cobra.Command{
-		Use:   "delete [session-name]",
-		Short: "Delete a session and clean up its resources",
-		Long: `Delete a CWT session, removing:
+		Use:   "delete [session-name|pattern...]",
+		Short: "Delete sessions and clean up their resources",
+		Long: `Delete CWT sessions, removing for each:
 - Tmux session
 - Git worktree
 - Session metadata
+- Git branch, once it is merged into the base branch
+
+A branch with commits the base branch lacks is always kept; --keep-branch
+keeps merged branches too.
+
+Sessions with uncommitted changes or commits not merged into the base branch
+are guarded: what would be lost is listed and a second confirmation asked
+for. --force skips both confirmations.
+
+Sessions are given by name or by glob pattern (quote it so the shell leaves
+it alone), or all at once with --all. Every session to delete and its
+resources are listed before a single confirmation.
 
 This operation cannot be undone.`,
-		Aliases: []string{"del", "rm"},
-		Args:    cobra.MaximumNArgs(1),
+		Example: `  cwt delete my-session          # Delete one session
+  cwt delete fix-a fix-b         # Delete several
+  cwt delete "feat-*" --dry-run  # List what a pattern would delete
+  cwt delete old --keep-branch   # Keep the session's branch
+  cwt delete --all --force       # Delete every session without asking`,
+		Aliases:           []string{"del", "rm"},
+		ValidArgsFunction: completeManySessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDeleteCmd(args, force)
+			if opts.All && len(args) > 0 {
+				return fmt.Errorf("--all can't be combined with session names")
+			}
+			return runDeleteCmd(args, opts)
 		},
 	}
 
-	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Skip the confirmation prompts, even for sessions with unmerged work")
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Delete every session")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "List what would be deleted without deleting it")
+	cmd.Flags().BoolVar(&opts.KeepBranch, "keep-branch", false, "Keep each session's git branch even when it is merged")
 
 	return cmd
 }
 
-func runDeleteCmd(args []string, force bool) error {
+// deleteOptions are the flags of cwt delete
+type deleteOptions struct {
+	Force      bool
+	All        bool
+	DryRun     bool
+	KeepBranch bool
+}
+
+func runDeleteCmd(args []string, opts deleteOptions) error {
 	sm, err := createStateManager()
 	if err != nil {
 		return err
@@ -57,47 +91,152 @@ func runDeleteCmd(args []string, force bool) error {
 		return fmt.Errorf("no sessions available to delete")
 	}
 
-	// Determine which session to delete
-	var sessionToDelete *string
-	var sessionID string
-
-	if len(args) > 0 {
-		// Session name provided - use operations layer
-		sessionName := args[0]
-		session, id, err := sessionOps.FindSessionByName(sessionName)
+	// Determine which sessions to delete
+	var toDelete []types.Session
+	switch {
+	case opts.All:
+		toDelete = sessions
+	case len(args) > 0:
+		toDelete, err = operations.MatchSessions(sessions, args)
 		if err != nil {
 			return err
 		}
-		sessionToDelete = &session.Core.Name
-		sessionID = id
-	} else {
+	default:
 		// Interactive selection
-		sessionName, id, err := promptForSessionSelection(sessions)
+		sessionName, _, err := promptForSessionSelection(sessions)
 		if err != nil {
 			return err
 		}
-		sessionToDelete = &sessionName
-		sessionID = id
+		toDelete, _ = operations.MatchSessions(sessions, []string{sessionName})
 	}
 
-	// Confirm deletion unless forced
-	if !force {
-		if !confirmDeletion(*sessionToDelete) {
+	// Work that isn't on the base branch yet, by session ID
+	unmerged := make(map[string]state.UnmergedWork)
+	for _, session := range toDelete {
+		work, err := sm.UnmergedWork(session.Core.ID)
+		if err != nil {
+			fmt.Printf("⚠️  %s: %v\n", session.Core.Name, err)
+		}
+		if !work.Empty() {
+			unmerged[session.Core.ID] = work
+		}
+	}
+
+	printDeletionPlan(os.Stdout, toDelete, opts.KeepBranch, unmerged)
+	if opts.DryRun {
+		fmt.Println("\nDry run: nothing was deleted.")
+		return nil
+	}
+
+	// Confirm deletion unless forced, and again for work that isn't merged
+	if !opts.Force {
+		reader := bufio.NewReader(os.Stdin)
+		if !confirmDeletion(reader, toDelete) {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
+		if len(unmerged) > 0 && !confirmUnmergedWork(reader, toDelete, unmerged) {
 			fmt.Println("Deletion cancelled.")
 			return nil
 		}
 	}
 
-	// Delete session using operations layer
-	fmt.Printf("Deleting session '%s'...\n", *sessionToDelete)
+	// Delete every session, reporting failures at the end
+	fmt.Println()
+	var failed []string
+	for _, session := range toDelete {
+		fmt.Printf("Deleting session '%s'...\n", session.Core.Name)
+		// Only work that was listed and confirmed may go; anything new since
+		// still stops the deletion
+		_, confirmed := unmerged[session.Core.ID]
+		result, err := sessionOps.DeleteSessionWithOptions(session.Core.ID, state.DeleteOptions{
+			KeepBranch: opts.KeepBranch,
+			Force:      opts.Force || confirmed,
+			// Only --force gets past a failing pre_delete hook
+			IgnoreHooks: opts.Force,
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to delete session '%s': %v\n", session.Core.Name, err)
+			var unmergedErr *state.UnmergedWorkError
+			if errors.As(err, &unmergedErr) {
+				fmt.Printf("   Delete it anyway with: cwt delete %s --force\n", session.Core.Name)
+			}
+			failed = append(failed, session.Core.Name)
+			continue
+		}
+		fmt.Printf("✅ Session '%s' deleted successfully!\n", session.Core.Name)
+		switch {
+		case result.BranchDeleted:
+			fmt.Printf("   🌿 Deleted branch '%s', which was merged into %s\n", result.Branch, baseBranch)
+		case result.BranchUnmerged:
+			fmt.Printf("   🌿 Kept branch '%s': it has commits not merged into %s (git branch -D %s to drop them)\n", result.Branch, baseBranch, result.Branch)
+		case opts.KeepBranch:
+			fmt.Printf("   🌿 Kept branch '%s'\n", result.Branch)
+		}
+	}
 
-	if err := sessionOps.DeleteSession(sessionID); err != nil {
-		return fmt.Errorf("failed to delete session: %w", err)
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete %d of %d session(s): %s", len(failed), len(toDelete), strings.Join(failed, ", "))
 	}
+	return nil
+}
 
-	fmt.Printf("✅ Session '%s' deleted successfully!\n", *sessionToDelete)
+// printDeletionPlan lists the sessions that will be deleted, the resources
+// each of them has and their work that isn't merged
+func printDeletionPlan(out io.Writer, sessions []types.Session, keepBranch bool, unmerged map[string]state.UnmergedWork) {
+	fmt.Fprintf(out, "%d session(s) will be deleted:\n", len(sessions))
+	for _, session := range sessions {
+		fmt.Fprintf(out, "\n  🏷️  %s\n", session.Core.Name)
 
-	return nil
+		tmuxState := "not running"
+		if session.IsAlive {
+			tmuxState = "running"
+		}
+		fmt.Fprintf(out, "     Tmux session: %s (%s)\n", session.Core.TmuxSession, tmuxState)
+
+		worktree := session.Core.WorktreePath
+		if changes := changedFileCount(session.GitStatus); changes > 0 {
+			worktree += fmt.Sprintf(" (⚠️  %d uncommitted change(s) will be lost)", changes)
+		} else if unmerged[session.Core.ID].Unchecked != "" {
+			worktree += " (⚠️  can't be checked for uncommitted changes)"
+		}
+		fmt.Fprintf(out, "     Worktree:     %s\n", worktree)
+		fmt.Fprintf(out, "     Metadata:     %s\n", session.Core.ID)
+
+		branch := "deleted if merged into " + baseBranch + ", kept otherwise"
+		if keepBranch {
+			branch = "kept"
+		}
+		fmt.Fprintf(out, "     Branch:       %s\n", branch)
+
+		if commits := unmerged[session.Core.ID].Commits; len(commits) > 0 {
+			fmt.Fprintf(out, "     Commits:      ⚠️  %d not merged into %s, kept on the branch\n", len(commits), baseBranch)
+			for i, commit := range commits {
+				if i == unmergedCommitsListed {
+					fmt.Fprintf(out, "                   ... and %d more\n", len(commits)-unmergedCommitsListed)
+					break
+				}
+				fmt.Fprintf(out, "                   %s %s\n", shortCommit(commit.Hash), commit.Subject)
+			}
+		}
+	}
+}
+
+// unmergedCommitsListed is how many unmerged commits the deletion plan lists
+// for each session
+const unmergedCommitsListed = 5
+
+// shortCommit abbreviates a commit hash
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// changedFileCount counts the files a worktree has uncommitted changes to
+func changedFileCount(status types.GitStatus) int {
+	return len(status.ModifiedFiles) + len(status.AddedFiles) + len(status.DeletedFiles) + len(status.UntrackedFiles) + len(status.ConflictedFiles)
 }
 
 func promptForSessionSelection(sessions []types.Session) (string, string, error) {
@@ -139,10 +278,31 @@ func promptForSessionSelection(sessions []types.Session) (string, string, error)
 	}
 }
 
-func confirmDeletion(sessionName string) bool {
-	reader := bufio.NewReader(os.Stdin)
+// confirmDeletion asks once before deleting every listed session
+func confirmDeletion(reader *bufio.Reader, sessions []types.Session) bool {
+	if len(sessions) == 1 {
+		fmt.Printf("\nAre you sure you want to delete session '%s'? This cannot be undone. (y/N): ", sessions[0].Core.Name)
+	} else {
+		fmt.Printf("\nAre you sure you want to delete these %d sessions? This cannot be undone. (y/N): ", len(sessions))
+	}
+	return readYes(reader)
+}
+
+// confirmUnmergedWork asks again before deleting sessions with work that
+// isn't on the base branch, saying what becomes of it
+func confirmUnmergedWork(reader *bufio.Reader, sessions []types.Session, unmerged map[string]state.UnmergedWork) bool {
+	fmt.Printf("\n⚠️  %d session(s) have work not merged into %s:\n", len(unmerged), baseBranch)
+	for _, session := range sessions {
+		if work, ok := unmerged[session.Core.ID]; ok {
+			fmt.Printf("   %s: %s\n", session.Core.Name, work.Describe(baseBranch))
+		}
+	}
+	fmt.Print("Uncommitted changes will be lost for good; unmerged commits stay on their branches.\nDelete anyway? (y/N): ")
+	return readYes(reader)
+}
 
-	fmt.Printf("Are you sure you want to delete session '%s'? This cannot be undone. (y/N): ", sessionName)
+// readYes reads an answer, reporting whether it was yes
+func readYes(reader *bufio.Reader) bool {
 	input, err := reader.ReadString('\n')
 	if err != nil {
 		return false
@@ -8,58 +8,112 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/operations"
 	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
+// diffOptions controls what 'cwt diff' compares and how it shows it
+type diffOptions struct {
+	against     string
+	mode        git.DiffMode
+	web         bool
+	stat        bool
+	nameOnly    bool
+	cached      bool
+	sinceReview bool
+
+	// Snapshots of the worktree when it was last reviewed and now, compared
+	// with --since-review
+	reviewed, current string
+}
+
 // newDiffCmd creates the 'cwt diff' command
 func newDiffCmd() *cobra.Command {
-	var against string
-	var web bool
-	var stat bool
-	var name bool
-	var cached bool
+	var opts diffOptions
+	var uncommitted, sessionOnly, includingBase bool
 
 	cmd := &cobra.Command{
 		Use:   "diff [session-name]",
 		Short: "Show detailed diff for session changes",
 		Long: `Show comprehensive diff view of changes in a session with rich formatting.
 
+Diff modes:
+  --including-base  Working tree vs the tip of the target branch (default).
+                    Changes made on the target since the session branched
+                    off show up reversed.
+  --session-only    Session branch vs its merge-base with the target: only
+                    what the session's commits changed.
+  --uncommitted     Working tree vs the session branch's HEAD: changes not
+                    committed yet.
+  --cached          Staged changes vs the session branch's HEAD.
+  --since-review    What changed in the worktree, committed or not, since
+                    its diff was last viewed.
+
+Viewing a session's full diff marks what it shows as reviewed, and
+'cwt status' then points out sessions that changed since, so an agent's
+next round of changes can be read on its own.
+
 Examples:
-  cwt diff my-session               # Show full diff for session
-  cwt diff my-session --stat        # Show diff statistics only
-  cwt diff my-session --against main # Compare against specific branch
-  cwt diff my-session --web          # Open diff in external viewer
-  cwt diff my-session --cached       # Show staged changes only
-  cwt diff                          # Interactive session selector`,
-		Args: cobra.MaximumNArgs(1),
+  cwt diff my-session                  # Show full diff for session
+  cwt diff my-session --session-only   # Show only what the session committed
+  cwt diff my-session --uncommitted    # Show changes not committed yet
+  cwt diff my-session --stat           # Show diff statistics only
+  cwt diff my-session --against main   # Compare against specific branch
+  cwt diff my-session --web            # Open diff in external viewer
+  cwt diff my-session --cached         # Show staged changes only
+  cwt diff my-session --since-review   # Show only what changed since the last look
+  cwt diff                             # Interactive session selector`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case uncommitted:
+				opts.mode = git.DiffUncommitted
+			case sessionOnly:
+				opts.mode = git.DiffSessionOnly
+			default:
+				opts.mode = git.DiffIncludingBase
+			}
+
 			sm, err := createStateManager()
 			if err != nil {
 				return err
 			}
 			defer sm.Close()
 
+			if opts.against == "" {
+				opts.against = sm.GetBaseBranch()
+			}
+
 			if len(args) == 0 {
-				return interactiveDiff(sm, against, web, stat, name, cached)
+				return interactiveDiff(sm, opts)
 			}
 
 			sessionName := args[0]
-			return showSessionDiff(sm, sessionName, against, web, stat, name, cached)
+			return showSessionDiff(sm, sessionName, opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&against, "against", "", "Compare against specific branch (default: base branch)")
-	cmd.Flags().BoolVar(&web, "web", false, "Open diff in external viewer")
-	cmd.Flags().BoolVar(&stat, "stat", false, "Show diff statistics only")
-	cmd.Flags().BoolVar(&name, "name-only", false, "Show only file names")
-	cmd.Flags().BoolVar(&cached, "cached", false, "Show staged changes only")
+	cmd.Flags().StringVar(&opts.against, "against", "", "Compare against specific branch (default: base branch)")
+	cmd.RegisterFlagCompletionFunc("against", completeBranches)
+	cmd.Flags().BoolVar(&includingBase, "including-base", false, "Compare the working tree with the tip of the target branch (default)")
+	cmd.Flags().BoolVar(&sessionOnly, "session-only", false, "Compare the session branch with its merge-base with the target")
+	cmd.Flags().BoolVar(&uncommitted, "uncommitted", false, "Compare the working tree with the session branch's HEAD")
+	cmd.Flags().BoolVar(&opts.web, "web", false, "Open diff in external viewer")
+	cmd.Flags().BoolVar(&opts.stat, "stat", false, "Show diff statistics only")
+	cmd.Flags().BoolVar(&opts.nameOnly, "name-only", false, "Show only file names")
+	cmd.Flags().BoolVar(&opts.cached, "cached", false, "Show staged changes only")
+	cmd.Flags().BoolVar(&opts.sinceReview, "since-review", false, "Show what changed since the session's diff was last viewed")
+	cmd.MarkFlagsMutuallyExclusive("including-base", "session-only", "uncommitted", "cached", "since-review")
+	cmd.MarkFlagsMutuallyExclusive("against", "since-review")
 
 	return cmd
 }
 
 // showSessionDiff displays the diff for a specific session
-func showSessionDiff(sm *state.Manager, sessionName, against string, web, stat, nameOnly, cached bool) error {
+func showSessionDiff(sm *state.Manager, sessionName string, opts diffOptions) error {
 	sessions, err := sm.DeriveFreshSessions()
 	if err != nil {
 		return fmt.Errorf("failed to load sessions: %w", err)
@@ -77,12 +131,15 @@ func showSessionDiff(sm *state.Manager, sessionName, against string, web, stat,
 	if targetSession == nil {
 		return fmt.Errorf("session '%s' not found", sessionName)
 	}
+	if err := operations.CheckWorktree(*targetSession); err != nil {
+		return err
+	}
 
-	return renderSessionDiff(*targetSession, against, web, stat, nameOnly, cached)
+	return reviewSessionDiff(sm, *targetSession, opts)
 }
 
 // interactiveDiff provides an interactive session selector for diff
-func interactiveDiff(sm *state.Manager, against string, web, stat, nameOnly, cached bool) error {
+func interactiveDiff(sm *state.Manager, opts diffOptions) error {
 	sessions, err := sm.DeriveFreshSessions()
 	if err != nil {
 		return fmt.Errorf("failed to load sessions: %w", err)
@@ -109,11 +166,56 @@ func interactiveDiff(sm *state.Manager, against string, web, stat, nameOnly, cac
 		return nil
 	}
 
-	return renderSessionDiff(*selectedSession, against, web, stat, nameOnly, cached)
+	return reviewSessionDiff(sm, *selectedSession, opts)
+}
+
+// reviewSessionDiff renders a session's diff and, once its full diff was
+// shown, marks the session reviewed
+func reviewSessionDiff(sm *state.Manager, session types.Session, opts diffOptions) error {
+	if opts.sinceReview {
+		reviewed, current, err := sm.ReviewedChanges(session.Core.ID)
+		if err != nil {
+			return err
+		}
+		opts.reviewed, opts.current = reviewed, current
+	}
+
+	if err := renderSessionDiff(session, opts); err != nil {
+		return err
+	}
+	if opts.stat || opts.nameOnly {
+		return nil
+	}
+	if err := sm.MarkReviewed(session.Core.ID); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	return nil
+}
+
+// diffArgs returns the git diff arguments selecting what opts compares
+func (opts diffOptions) diffArgs() []string {
+	if opts.cached {
+		return []string{"--cached"}
+	}
+	if opts.sinceReview {
+		return []string{opts.reviewed, opts.current}
+	}
+	return opts.mode.Args(opts.against)
+}
+
+// describe says what opts compares
+func (opts diffOptions) describe() string {
+	if opts.cached {
+		return "staged changes"
+	}
+	if opts.sinceReview {
+		return "worktree vs when its diff was last viewed"
+	}
+	return opts.mode.Describe(opts.against)
 }
 
 // renderSessionDiff renders the diff for a session
-func renderSessionDiff(session types.Session, against string, web, stat, nameOnly, cached bool) error {
+func renderSessionDiff(session types.Session, opts diffOptions) error {
 	// Change to session worktree directory
 	originalDir, err := os.Getwd()
 	if err != nil {
@@ -125,60 +227,50 @@ func renderSessionDiff(session types.Session, against string, web, stat, nameOnl
 		return fmt.Errorf("failed to change to worktree directory: %w", err)
 	}
 
-	// Determine comparison target
-	target := against
-	if target == "" {
-		target = "main" // Default base branch
-	}
+	args := opts.diffArgs()
 
 	// Open in external viewer if requested
-	if web {
-		return openDiffInExternalViewer(target, cached)
+	if opts.web {
+		return openDiffInExternalViewer(args)
 	}
 
 	// Show diff header
 	fmt.Printf("📋 Diff for session: %s\n", session.Core.Name)
 	fmt.Printf("📂 Path: %s\n", session.Core.WorktreePath)
-
-	if cached {
-		fmt.Printf("🔍 Comparing: staged changes\n")
-	} else {
-		fmt.Printf("🔍 Comparing: working tree vs %s\n", target)
-	}
+	fmt.Printf("🔍 Comparing: %s\n", opts.describe())
 
 	fmt.Println(strings.Repeat("=", 70))
 
 	// Show summary stats first
-	if err := showDiffStats(target, cached); err != nil {
+	if err := showDiffStats(args); err != nil {
 		fmt.Printf("Warning: failed to show diff stats: %v\n", err)
 	}
 
-	if stat {
+	if opts.stat {
 		return nil // Only show stats
 	}
 
 	fmt.Println(strings.Repeat("-", 70))
 
 	// Show file names only if requested
-	if nameOnly {
-		return showDiffFileNames(target, cached)
+	if opts.nameOnly {
+		return showDiffFileNames(args)
 	}
 
 	// Show full diff with syntax highlighting
-	return showFullDiff(target, cached)
+	return showFullDiff(args)
 }
 
-// showDiffStats shows diff statistics
-func showDiffStats(target string, cached bool) error {
-	var cmd *exec.Cmd
-
-	if cached {
-		cmd = exec.Command("git", "diff", "--cached", "--stat")
-	} else {
-		cmd = exec.Command("git", "diff", target, "--stat")
-	}
+// gitDiff builds a git diff command for the given revisions and options,
+// run in a session's worktree and leaving out the files cwt wrote there
+func gitDiff(args []string, options ...string) *exec.Cmd {
+	command := append(append([]string{"diff"}, args...), options...)
+	return exec.Command("git", append(command, git.WorkPathspec(".")...)...)
+}
 
-	output, err := cmd.Output()
+// showDiffStats shows diff statistics
+func showDiffStats(args []string) error {
+	output, err := gitDiff(args, "--stat").Output()
 	if err != nil {
 		return err
 	}
@@ -194,16 +286,8 @@ func showDiffStats(target string, cached bool) error {
 }
 
 // showDiffFileNames shows only the names of changed files
-func showDiffFileNames(target string, cached bool) error {
-	var cmd *exec.Cmd
-
-	if cached {
-		cmd = exec.Command("git", "diff", "--cached", "--name-status")
-	} else {
-		cmd = exec.Command("git", "diff", target, "--name-status")
-	}
-
-	output, err := cmd.Output()
+func showDiffFileNames(args []string) error {
+	output, err := gitDiff(args, "--name-status").Output()
 	if err != nil {
 		return fmt.Errorf("failed to get file names: %w", err)
 	}
@@ -237,14 +321,8 @@ func showDiffFileNames(target string, cached bool) error {
 }
 
 // showFullDiff shows the complete diff with syntax highlighting
-func showFullDiff(target string, cached bool) error {
-	var cmd *exec.Cmd
-
-	if cached {
-		cmd = exec.Command("git", "diff", "--cached", "--color=always")
-	} else {
-		cmd = exec.Command("git", "diff", target, "--color=always")
-	}
+func showFullDiff(args []string) error {
+	cmd := gitDiff(args, "--color=always")
 
 	// Try to use a pager if available (less, more, etc.)
 	if isInteractiveTerminal() {
@@ -265,7 +343,7 @@ func showFullDiff(target string, cached bool) error {
 }
 
 // openDiffInExternalViewer opens the diff in an external application
-func openDiffInExternalViewer(target string, cached bool) error {
+func openDiffInExternalViewer(args []string) error {
 	// Try different diff viewers in order of preference
 	viewers := []string{
 		"code --diff", // VSCode
@@ -277,33 +355,33 @@ func openDiffInExternalViewer(target string, cached bool) error {
 	for _, viewer := range viewers {
 		if cmd := strings.Fields(viewer); len(cmd) > 0 {
 			if _, err := exec.LookPath(cmd[0]); err == nil {
-				return openWithViewer(viewer, target, cached)
+				return openWithViewer(viewer, args)
 			}
 		}
 	}
 
 	// Fallback to system default
-	return openWithSystemDefault(target, cached)
+	return openWithSystemDefault(args)
 }
 
 // openWithViewer opens diff with a specific viewer
-func openWithViewer(viewer, target string, cached bool) error {
+func openWithViewer(viewer string, args []string) error {
 	// For now, just show the diff in terminal with a message
 	fmt.Printf("🔧 External viewer integration not yet implemented\n")
 	fmt.Printf("📋 Preferred viewer: %s\n", viewer)
 	fmt.Println("📋 Falling back to terminal diff:")
 	fmt.Println(strings.Repeat("-", 50))
 
-	return showFullDiff(target, cached)
+	return showFullDiff(args)
 }
 
 // openWithSystemDefault opens diff with system default application
-func openWithSystemDefault(target string, cached bool) error {
+func openWithSystemDefault(args []string) error {
 	fmt.Println("🔧 System default diff viewer not yet implemented")
 	fmt.Println("📋 Falling back to terminal diff:")
 	fmt.Println(strings.Repeat("-", 50))
 
-	return showFullDiff(target, cached)
+	return showFullDiff(args)
 }
 
 // Helper functions
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// defaultEventsInterval is how often 'cwt events --follow' looks for changes
+const defaultEventsInterval = 2 * time.Second
+
+func newEventsCmd() *cobra.Command {
+	var jsonOutput, follow bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Stream session state and events, for dashboards and scripts",
+		Long: `Print a snapshot of every session, then, with --follow, keep printing
+what changes: sessions added, updated and removed, and the events recorded
+in their timelines.
+
+With --json the stream is NDJSON, one record per line, ready to pipe into
+jq, Grafana Loki or a dashboard of your own. Every record has a version, a
+type and a time:
+
+  snapshot          every session, in the format of 'cwt status --json';
+                    always the first record, so consumers need no 'cwt list'
+  session_added     a new session, with its state in "session"
+  session_updated   a session whose status changed, with its new state
+  session_removed   a session deleted or archived
+  event             an event added to a session's timeline, in "event"
+
+Changes are found by polling every --interval. With 'cwt daemon' running
+that is cheap; without it each poll derives every session's status.
+
+Examples:
+  cwt events                                   # Snapshot of every session
+  cwt events --json --follow                   # Stream everything as NDJSON
+  cwt events --json -f | jq 'select(.type == "event")'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			return runEventsCmd(jsonOutput, follow, interval)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output NDJSON records")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep streaming changes until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", defaultEventsInterval, "How often to look for changes")
+
+	return cmd
+}
+
+func runEventsCmd(jsonOutput, follow bool, interval time.Duration) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	emit := printStreamRecord
+	if jsonOutput {
+		// One compact record per line
+		encoder := json.NewEncoder(os.Stdout)
+		emit = func(record types.StreamRecord) error {
+			if err := encoder.Encode(record); err != nil {
+				return fmt.Errorf("failed to write record: %w", err)
+			}
+			return nil
+		}
+	}
+
+	stream := operations.NewEventStream(sm)
+	if !follow {
+		snapshot, err := stream.Snapshot()
+		if err != nil {
+			return err
+		}
+		return emit(snapshot)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return stream.Follow(ctx, interval, emit)
+}
+
+// printStreamRecord prints a record of the event stream for people
+func printStreamRecord(record types.StreamRecord) error {
+	formatter := operations.NewStatusFormat()
+	at := record.Time.Local().Format("15:04:05")
+
+	switch record.Type {
+	case types.StreamSnapshot:
+		fmt.Printf("%s  📸 %d session(s)\n", at, len(record.Sessions))
+		for _, session := range record.Sessions {
+			fmt.Printf("          %s: %s\n", session.Name, streamSessionSummary(formatter, session))
+		}
+	case types.StreamSessionAdded:
+		fmt.Printf("%s  🆕 %s: %s\n", at, record.Name, streamSessionSummary(formatter, *record.Session))
+	case types.StreamSessionUpdated:
+		fmt.Printf("%s  🔄 %s: %s\n", at, record.Name, streamSessionSummary(formatter, *record.Session))
+	case types.StreamSessionRemoved:
+		fmt.Printf("%s  🗑️  %s removed\n", at, record.Name)
+	case types.StreamEvent:
+		fmt.Printf("%s  %s: %s\n", at, record.Name, formatter.FormatTimelineEvent(*record.Event))
+	}
+	return nil
+}
+
+// streamSessionSummary describes a streamed session's status in one line
+func streamSessionSummary(formatter *operations.StatusFormat, session types.SessionOutput) string {
+	return fmt.Sprintf("tmux: %s | claude: %s | git: %s",
+		formatter.FormatTmuxStatus(session.TmuxAlive),
+		formatter.FormatClaudeStatus(types.ClaudeStatus{State: session.Claude.State, StatusMessage: session.Claude.StatusMessage}),
+		streamGitStatus(session.Git))
+}
+
+// streamGitStatus summarizes a streamed session's working tree
+func streamGitStatus(git types.GitStatusOutput) string {
+	switch {
+	case git.Error != "":
+		return "❌ error"
+	case len(git.ConflictedFiles) > 0:
+		return fmt.Sprintf("⚠ %d conflicted", len(git.ConflictedFiles))
+	case git.HasChanges:
+		return fmt.Sprintf("🟡 %d changed", len(git.ModifiedFiles)+len(git.AddedFiles)+len(git.DeletedFiles)+len(git.UntrackedFiles))
+	default:
+		return "🟢 clean"
+	}
+}
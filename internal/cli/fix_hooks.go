@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,6 +8,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
 )
 
 func newFixHooksCmd() *cobra.Command {
@@ -44,13 +46,17 @@ func runFixHooksCmd(cmd *cobra.Command, args []string) error {
 		fmt.Println("No sessions found.")
 		return nil
 	}
+	if !appConfig.ClaudeHooks {
+		fmt.Println("Claude hooks are turned off (claude_hooks: false), so there are none to fix.")
+		return nil
+	}
 
 	// Get the correct cwt executable path
 	correctPath := getCwtExecutablePath()
 
 	fixed := 0
 	for _, session := range sessions {
-		settingsPath := filepath.Join(session.Core.WorktreePath, "settings.json")
+		settingsPath := filepath.Join(session.Core.WorktreePath, git.SettingsFile)
 
 		if updated, err := fixSettingsFile(settingsPath, session.Core.ID, correctPath); err != nil {
 			fmt.Printf("⚠️  Failed to fix hooks for session '%s': %v\n", session.Core.Name, err)
@@ -69,134 +75,15 @@ func runFixHooksCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// fixSettingsFile updates the settings.json file with correct hook paths
+// fixSettingsFile updates the settings.json file with correct hook paths,
+// keeping the project's own settings and hooks
 func fixSettingsFile(settingsPath, sessionID, correctPath string) (bool, error) {
 	// Check if settings file exists
 	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
 		return false, fmt.Errorf("settings.json not found")
 	}
 
-	// Read current settings
-	data, err := os.ReadFile(settingsPath)
-	if err != nil {
-		return false, fmt.Errorf("failed to read settings file: %w", err)
-	}
-
-	var settings map[string]interface{}
-	if err := json.Unmarshal(data, &settings); err != nil {
-		return false, fmt.Errorf("failed to parse settings JSON: %w", err)
-	}
-
-	// Check if hooks exist
-	hooks, ok := settings["hooks"].(map[string]interface{})
-	if !ok {
-		// No hooks section, create it
-		hooks = make(map[string]interface{})
-		settings["hooks"] = hooks
-	}
-
-	// Check if any hooks need updating
-	needsUpdate := false
-	expectedHooks := map[string]interface{}{
-		"Notification": []map[string]interface{}{
-			{
-				"matcher": "",
-				"hooks": []map[string]interface{}{
-					{
-						"type":    "command",
-						"command": fmt.Sprintf("%s __hook %s notification", correctPath, sessionID),
-					},
-				},
-			},
-		},
-		"Stop": []map[string]interface{}{
-			{
-				"matcher": "",
-				"hooks": []map[string]interface{}{
-					{
-						"type":    "command",
-						"command": fmt.Sprintf("%s __hook %s stop", correctPath, sessionID),
-					},
-				},
-			},
-		},
-		"PreToolUse": []map[string]interface{}{
-			{
-				"matcher": "",
-				"hooks": []map[string]interface{}{
-					{
-						"type":    "command",
-						"command": fmt.Sprintf("%s __hook %s pre_tool_use", correctPath, sessionID),
-					},
-				},
-			},
-		},
-		"PostToolUse": []map[string]interface{}{
-			{
-				"matcher": "",
-				"hooks": []map[string]interface{}{
-					{
-						"type":    "command",
-						"command": fmt.Sprintf("%s __hook %s post_tool_use", correctPath, sessionID),
-					},
-				},
-			},
-		},
-		"SubagentStop": []map[string]interface{}{
-			{
-				"matcher": "",
-				"hooks": []map[string]interface{}{
-					{
-						"type":    "command",
-						"command": fmt.Sprintf("%s __hook %s subagent_stop", correctPath, sessionID),
-					},
-				},
-			},
-		},
-		"PreCompact": []map[string]interface{}{
-			{
-				"matcher": "",
-				"hooks": []map[string]interface{}{
-					{
-						"type":    "command",
-						"command": fmt.Sprintf("%s __hook %s pre_compact", correctPath, sessionID),
-					},
-				},
-			},
-		},
-	}
-
-	for hookName, expectedHook := range expectedHooks {
-		currentHook, exists := hooks[hookName]
-		if !exists {
-			needsUpdate = true
-			hooks[hookName] = expectedHook
-		} else {
-			// Check if current hook matches expected structure
-			expectedJSON, _ := json.Marshal(expectedHook)
-			currentJSON, _ := json.Marshal(currentHook)
-			if string(expectedJSON) != string(currentJSON) {
-				needsUpdate = true
-				hooks[hookName] = expectedHook
-			}
-		}
-	}
-
-	if !needsUpdate {
-		return false, nil // No changes needed
-	}
-
-	// Write updated settings
-	updatedData, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return false, fmt.Errorf("failed to marshal updated settings: %w", err)
-	}
-
-	if err := os.WriteFile(settingsPath, updatedData, 0644); err != nil {
-		return false, fmt.Errorf("failed to write updated settings: %w", err)
-	}
-
-	return true, nil
+	return claude.MergeHookSettings(settingsPath, correctPath, sessionID)
 }
 
 // getCwtExecutablePath duplicates the logic from state manager for consistency
@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
@@ -19,7 +23,8 @@ func newHookCmd() *cobra.Command {
 		Hidden:  true,             // Don't show in help output
 		Short:   "Internal hook handler for Claude Code events",
 		Long: `This is an internal command used by Claude Code hooks.
-It receives session events and updates session state files.
+It receives session events, appends them to the session's event log
+and updates the derived session state snapshot.
 
 This command is automatically configured when creating sessions
 and should not be called manually.`,
@@ -50,22 +55,74 @@ func runHookCmd(cmd *cobra.Command, args []string) error {
 		lastMessage = msg
 	}
 
-	// Create session state update
-	state := &types.SessionState{
-		SessionID:     sessionID,
-		ClaudeState:   types.ParseClaudeStateFromEvent(eventType, eventData),
-		LastEvent:     eventType,
-		LastEventTime: time.Now(),
-		LastEventData: eventData,
-		LastMessage:   lastMessage,
-		LastUpdated:   time.Now(),
+	// Record the event; the session state snapshot is derived from the log
+	event := types.SessionEvent{
+		Type:        eventType,
+		Time:        time.Now(),
+		ClaudeState: types.ParseClaudeStateFromEvent(eventType, eventData),
+		Message:     lastMessage,
+		Data:        eventData,
 	}
 
 	// Save session state (using .cwt as default data directory)
 	dataDir := ".cwt"
-	if err := types.SaveSessionState(dataDir, state); err != nil {
+	if _, err := types.AppendSessionEvent(dataDir, sessionID, event); err != nil {
 		return fmt.Errorf("failed to save session state: %w", err)
 	}
 
 	return nil
 }
+
+// exitCaptureLines is how much scrollback is captured from an exited pane
+const exitCaptureLines = 100
+
+// newSessionExitedCmd creates the hidden command tmux runs when a session's pane exits
+func newSessionExitedCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "__session-exited [session-id] [tmux-session] [exit-status]",
+		Hidden: true,
+		Short:  "Internal handler for a session's tmux pane exiting",
+		Long: `This is an internal command run by the tmux pane-died hook.
+It captures the last output of the exited pane and its exit status so a dead
+session can show why Claude stopped. With auto_restart configured, a crashed
+Claude is resumed in the same pane instead.
+
+This command is automatically configured when creating sessions
+and should not be called manually.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runSessionExitedCmd,
+	}
+}
+
+func runSessionExitedCmd(cmd *cobra.Command, args []string) error {
+	sessionID, tmuxSession := args[0], args[1]
+
+	// tmux leaves the status empty when the process was killed by a signal
+	status, err := strconv.Atoi(args[2])
+	if err != nil {
+		status = -1
+	}
+
+	// The pane is kept open until this hook finishes, so its output is still there
+	output, _ := tmux.NewRealChecker().CaptureHistory(tmuxSession, exitCaptureLines)
+
+	exit := types.NewSessionExit(time.Now(), status, output)
+	if err := types.SaveSessionExit(dataDir, sessionID, exit); err != nil {
+		return fmt.Errorf("failed to record session exit: %w", err)
+	}
+
+	if appConfig.AutoRestart {
+		// Respawning the pane keeps the hook from killing the session; if
+		// the restart fails, the session is left dead as usual
+		sm := state.NewManager(state.Config{
+			DataDir:          dataDir,
+			BaseBranch:       baseBranch,
+			ClaudeExecutable: appConfig.ClaudeExecutable,
+		})
+		defer sm.Close()
+		if _, err := operations.NewSessionOperations(sm).RestartCrashedSession(sessionID, exit); err != nil {
+			return fmt.Errorf("failed to restart crashed session: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newLimitsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "limits",
+		Short: "Show the session creation limits and this month's token usage",
+		Long: `Show the guardrails against runaway agent spend and how close the project
+is to each. They are set in the limits section of the config:
+
+  limits:
+    sessions_per_hour: 10      # Sessions that may be created in any hour
+    max_working: 4             # Sessions Claude may be working in when creating another
+    monthly_tokens: 50000000   # Tokens Claude may use in a calendar month
+    warn_at: 0.8               # Share of the budget used at which to warn
+    session_cost: 5            # Estimated US dollars a session may cost before it is flagged
+
+Creating a session that goes over a limit fails, in the CLI and the TUI;
+'cwt new --ignore-limits' goes over them once. Token usage is read from
+Claude's transcripts and counts input, output and prompt cache writes.
+The session cost is estimated from the same transcripts at list prices;
+'cwt list' and 'cwt status --summary' flag the sessions over it.
+'cwt daemon' records usage as it grows and warns when the budget runs low
+or too many sessions are working at once.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLimitsCmd()
+		},
+	}
+}
+
+func runLimitsCmd() error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	sessions, err := sm.DeriveFreshSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	budget, err := sm.RecordTokenUsage(sessions)
+	if err != nil {
+		return err
+	}
+	created, err := sm.RecentCreations()
+	if err != nil {
+		return err
+	}
+
+	limits := appConfig.Limits
+	fmt.Println("🚦 Limits")
+	fmt.Printf("   Created in the last hour: %s\n", limitUsage(int64(created), int64(limits.SessionsPerHour)))
+	fmt.Printf("   Claude working now:       %s\n", limitUsage(int64(state.WorkingSessions(sessions)), int64(limits.MaxWorking)))
+	fmt.Printf("   Tokens this month:        %s\n", formatBudget(budget))
+	return nil
+}
+
+// limitUsage describes a count against its limit, where 0 is no limit
+func limitUsage(count, limit int64) string {
+	if limit <= 0 {
+		return fmt.Sprintf("%d (no limit)", count)
+	}
+	return fmt.Sprintf("%d of %d", count, limit)
+}
+
+// formatBudget describes the month's token usage, flagging a budget running low
+func formatBudget(budget types.TokenBudget) string {
+	switch {
+	case budget.Budget <= 0:
+		return budget.Summary() + " (no budget)"
+	case budget.Exceeded():
+		return "🔴 " + budget.Summary()
+	case budget.Warning():
+		return "🟡 " + budget.Summary()
+	default:
+		return "🟢 " + budget.Summary()
+	}
+}
+
+// stateLimits converts the limits config for the state manager
+func stateLimits(cfg config.LimitsConfig) state.Limits {
+	return state.Limits{
+		SessionsPerHour: cfg.SessionsPerHour,
+		MaxWorking:      cfg.MaxWorking,
+		MonthlyTokens:   cfg.MonthlyTokens,
+		WarnAt:          cfg.WarnAt,
+		SessionCost:     cfg.SessionCost,
+	}
+}
+
+// warnTokenBudget prints a warning when the month's token budget runs low
+func warnTokenBudget(sm *state.Manager) {
+	if sm.Limits().MonthlyTokens <= 0 {
+		return
+	}
+	if budget, err := sm.TokenBudget(); err == nil && budget.Warning() {
+		fmt.Printf("⚠️  Token budget running low: %s\n", budget.Summary())
+	}
+}
+
+// limitHint explains how to get past a limit a creation failed on
+func limitHint(err error) {
+	var limitErr *state.LimitError
+	if errors.As(err, &limitErr) {
+		fmt.Printf("💡 Raise %s in the config, or go over it once with --ignore-limits\n", limitErr.Limit)
+	}
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -10,11 +11,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/profile"
+	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
 func newListCmd() *cobra.Command {
 	var verbose bool
+	var jsonOutput bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -23,26 +27,31 @@ func newListCmd() *cobra.Command {
 - Tmux session alive status
 - Git working tree changes
 - Claude activity and availability
+- The pull request the session was published to
 
 Status is derived fresh from external systems for accuracy.`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runListCmd(verbose)
+			return runListCmd(verbose, jsonOutput)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed information")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output sessions as JSON")
 
 	return cmd
 }
 
-func runListCmd(verbose bool) error {
+func runListCmd(verbose, jsonOutput bool) error {
 	sm, err := createStateManager()
 	if err != nil {
 		return err
 	}
 	defer sm.Close()
 
+	// Pull requests the daemon hasn't checked lately are checked now
+	sm.RefreshPullRequests(state.PullRequestCheckInterval)
+
 	// Use operations layer for session retrieval and formatting
 	sessionOps := operations.NewSessionOperations(sm)
 	sessions, err := sessionOps.GetAllSessions()
@@ -50,6 +59,29 @@ func runListCmd(verbose bool) error {
 		return fmt.Errorf("failed to load sessions: %w", err)
 	}
 
+	// Sort watched sessions first and muted ones last, then by priority,
+	// then by creation time (newest first)
+	sort.Slice(sessions, func(i, j int) bool {
+		a, b := sessions[i].Core, sessions[j].Core
+		if a.AttentionRank() != b.AttentionRank() {
+			return a.AttentionRank() < b.AttentionRank()
+		}
+		if a.SessionPriority().Rank() != b.SessionPriority().Rank() {
+			return a.SessionPriority().Rank() < b.SessionPriority().Rank()
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+
+	usage := sessionUsage(sm, sessions)
+	if jsonOutput {
+		output := types.NewSessionListOutput(sessions)
+		for i, session := range sessions {
+			used := usage[session.Core.ID]
+			output.Sessions[i].Usage = &used
+		}
+		return writeJSON(output)
+	}
+
 	formatter := operations.NewStatusFormat()
 
 	if len(sessions) == 0 {
@@ -58,101 +90,209 @@ func runListCmd(verbose bool) error {
 		return nil
 	}
 
-	// Sort sessions by creation time (newest first)
-	sort.Slice(sessions, func(i, j int) bool {
-		return sessions[i].Core.CreatedAt.After(sessions[j].Core.CreatedAt)
-	})
-
+	limits := sm.Limits()
 	if verbose {
-		renderVerboseSessionList(sessions, formatter)
+		renderVerboseSessionList(sessions, usage, limits, formatter)
 	} else {
-		renderCompactSessionList(sessions, formatter)
+		renderCompactSessionList(sessions, usage, limits, formatter)
 	}
 
 	return nil
 }
 
-func renderCompactSessionList(sessions []types.Session, formatter *operations.StatusFormat) {
-	fmt.Printf("Found %d session(s):\n\n", len(sessions))
+func renderCompactSessionList(sessions []types.Session, usage map[string]types.Usage, limits state.Limits, formatter *operations.StatusFormat) {
+	defer profiler.Track(profile.PhaseRender)()
 
-	// Calculate max widths for each column based on content
-	maxNameLen := 4     // "NAME"
-	maxTmuxLen := 4     // "TMUX"
-	maxClaudeLen := 6   // "CLAUDE"
-	maxGitLen := 3      // "GIT"
-	maxActivityLen := 8 // "ACTIVITY"
+	fmt.Printf("Found %d session(s):\n\n", len(sessions))
 
-	// Pre-format all data to calculate actual widths
-	type rowData struct {
-		name     string
-		tmux     string
-		claude   string
-		git      string
-		activity string
+	// Fields from status providers become extra columns
+	extras := extraColumns(sessions)
+	headers := []string{"NAME", "TMUX", "CLAUDE", "GIT", "ACTIVITY"}
+	priorities := hasPriorities(sessions)
+	if priorities {
+		headers = slices.Insert(headers, 1, "PRIORITY")
+	}
+	followUps := hasFollowUps(sessions)
+	if followUps {
+		headers = append(headers, "FOLLOW-UP")
+	}
+	pullRequests := hasPullRequests(sessions)
+	if pullRequests {
+		headers = append(headers, "PR")
+	}
+	costs := hasUsage(usage)
+	if costs {
+		headers = append(headers, "COST")
+	}
+	for _, label := range extras {
+		headers = append(headers, strings.ToUpper(label))
 	}
 
-	rows := make([]rowData, len(sessions))
+	// Pre-format all data to calculate actual widths
+	rows := make([][]string, len(sessions))
 	for i, session := range sessions {
-		rows[i] = rowData{
-			name:     truncate(session.Core.Name, 30),
-			tmux:     formatter.FormatTmuxStatus(session.IsAlive),
-			claude:   formatter.FormatClaudeStatus(session.ClaudeStatus),
-			git:      formatter.FormatGitStatus(session.GitStatus),
-			activity: formatter.FormatActivity(session.LastActivity),
+		git := formatter.FormatGitStatus(session.GitStatus)
+		if session.ChangedSinceReview {
+			git += " 🆕"
 		}
-
-		// Update max lengths (using visual length)
-		if l := visualLength(rows[i].name); l > maxNameLen {
-			maxNameLen = l
+		rows[i] = []string{
+			truncate(session.Core.Name, 30) + attentionMark(session.Core),
+			formatter.FormatSessionTmuxStatus(session),
+			formatter.FormatClaudeStatus(session.ClaudeStatus),
+			git,
+			formatter.FormatActivity(session.LastActivity),
 		}
-		if l := visualLength(rows[i].tmux); l > maxTmuxLen {
-			maxTmuxLen = l
+		if priorities {
+			rows[i] = slices.Insert(rows[i], 1, formatter.FormatPriority(session.Core.SessionPriority()))
 		}
-		if l := visualLength(rows[i].claude); l > maxClaudeLen {
-			maxClaudeLen = l
+		if followUps {
+			followUp := "-"
+			if session.Core.FollowUp != nil {
+				followUp = truncate(formatter.FormatFollowUp(*session.Core.FollowUp), 30)
+			}
+			rows[i] = append(rows[i], followUp)
 		}
-		if l := visualLength(rows[i].git); l > maxGitLen {
-			maxGitLen = l
+		if pullRequests {
+			pr := "-"
+			if session.Core.PullRequest != nil {
+				pr = formatter.FormatPullRequest(*session.Core.PullRequest)
+			}
+			rows[i] = append(rows[i], pr)
+		}
+		if costs {
+			used := usage[session.Core.ID]
+			rows[i] = append(rows[i], formatter.FormatCost(used, limits.OverCost(used)))
+		}
+		values := make(map[string]string)
+		for _, field := range session.Extra {
+			values[field.Label()] = field.Value
 		}
-		if l := visualLength(rows[i].activity); l > maxActivityLen {
-			maxActivityLen = l
+		for _, label := range extras {
+			value, ok := values[label]
+			if !ok {
+				value = "-"
+			}
+			rows[i] = append(rows[i], truncate(value, 30))
 		}
 	}
 
-	// Add padding
-	maxNameLen += 2
-	maxTmuxLen += 2
-	maxClaudeLen += 2
-	maxGitLen += 2
-	maxActivityLen += 2
+	// Calculate max widths for each column based on content (using visual length)
+	widths := make([]int, len(headers))
+	for col, header := range headers {
+		widths[col] = visualLength(header)
+		for _, row := range rows {
+			if l := visualLength(row[col]); l > widths[col] {
+				widths[col] = l
+			}
+		}
+		// Add padding
+		widths[col] += 2
+	}
 
 	// Print header
-	fmt.Printf("%s  %s  %s  %s  %s\n",
-		padRight("NAME", maxNameLen),
-		padRight("TMUX", maxTmuxLen),
-		padRight("CLAUDE", maxClaudeLen),
-		padRight("GIT", maxGitLen),
-		padRight("ACTIVITY", maxActivityLen))
-
-	fmt.Printf("%s  %s  %s  %s  %s\n",
-		strings.Repeat("-", maxNameLen),
-		strings.Repeat("-", maxTmuxLen),
-		strings.Repeat("-", maxClaudeLen),
-		strings.Repeat("-", maxGitLen),
-		strings.Repeat("-", maxActivityLen))
+	rule := make([]string, len(headers))
+	for col, width := range widths {
+		rule[col] = strings.Repeat("-", width)
+	}
+	printRow(headers, widths)
+	fmt.Println(strings.Join(rule, "  "))
 
 	// Print rows
 	for _, row := range rows {
-		fmt.Printf("%s  %s  %s  %s  %s\n",
-			padRight(row.name, maxNameLen),
-			padRight(row.tmux, maxTmuxLen),
-			padRight(row.claude, maxClaudeLen),
-			padRight(row.git, maxGitLen),
-			padRight(row.activity, maxActivityLen))
+		printRow(row, widths)
+	}
+}
+
+// attentionMark flags watched and muted sessions after their names
+func attentionMark(core types.CoreSession) string {
+	switch {
+	case core.IsWatched():
+		return " 👀"
+	case core.IsMuted():
+		return " 🔇"
+	}
+	return ""
+}
+
+// hasPriorities reports whether any of the sessions has a priority other
+// than normal
+func hasPriorities(sessions []types.Session) bool {
+	for _, session := range sessions {
+		if session.Core.SessionPriority() != types.PriorityNormal {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFollowUps reports whether any of the sessions has a follow-up command
+func hasFollowUps(sessions []types.Session) bool {
+	for _, session := range sessions {
+		if session.Core.FollowUp != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPullRequests reports whether any of the sessions was published to a
+// pull request
+func hasPullRequests(sessions []types.Session) bool {
+	for _, session := range sessions {
+		if session.Core.PullRequest != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUsage reports whether the agent of any session used tokens
+func hasUsage(usage map[string]types.Usage) bool {
+	for _, used := range usage {
+		if !used.Empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionUsage reads what the agent of each session used, by session ID
+func sessionUsage(sm *state.Manager, sessions []types.Session) map[string]types.Usage {
+	usage := make(map[string]types.Usage, len(sessions))
+	for _, session := range sessions {
+		usage[session.Core.ID] = sm.SessionUsage(session.Core)
+	}
+	return usage
+}
+
+// extraColumns lists the fields status providers reported for any of the
+// sessions, in the order they first appear
+func extraColumns(sessions []types.Session) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, session := range sessions {
+		for _, field := range session.Extra {
+			if !seen[field.Label()] {
+				seen[field.Label()] = true
+				labels = append(labels, field.Label())
+			}
+		}
+	}
+	return labels
+}
+
+// printRow prints a table row, padding each cell to its column's width
+func printRow(cells []string, widths []int) {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		padded[i] = padRight(cell, widths[i])
 	}
+	fmt.Println(strings.Join(padded, "  "))
 }
 
-func renderVerboseSessionList(sessions []types.Session, formatter *operations.StatusFormat) {
+func renderVerboseSessionList(sessions []types.Session, usage map[string]types.Usage, limits state.Limits, formatter *operations.StatusFormat) {
+	defer profiler.Track(profile.PhaseRender)()
+
 	fmt.Printf("Found %d session(s):\n\n", len(sessions))
 
 	for i, session := range sessions {
@@ -164,15 +304,35 @@ func renderVerboseSessionList(sessions []types.Session, formatter *operations.St
 		fmt.Printf("   ID: %s\n", session.Core.ID)
 		fmt.Printf("   Created: %s\n", session.Core.CreatedAt.Format("2006-01-02 15:04:05"))
 		fmt.Printf("   Worktree: %s\n", session.Core.WorktreePath)
+		if session.Core.Task != "" {
+			fmt.Printf("   Task: %s\n", strings.SplitN(session.Core.Task, "\n", 2)[0])
+		}
+		if session.Core.Source != "" {
+			fmt.Printf("   Source: %s\n", session.Core.Source)
+		}
+		if priority := session.Core.SessionPriority(); priority != types.PriorityNormal {
+			fmt.Printf("   Priority: %s\n", formatter.FormatPriority(priority))
+		}
+		if session.Core.Attention != "" {
+			fmt.Printf("   Attention: %s%s\n", session.Core.Attention, attentionMark(session.Core))
+		}
 		fmt.Printf("   \n")
 
 		// Tmux status
 		fmt.Printf("   🖥️  Tmux: %s (session: %s)\n",
-			formatter.FormatTmuxStatus(session.IsAlive), session.Core.TmuxSession)
+			formatter.FormatSessionTmuxStatus(session), session.Core.TmuxSession)
+
+		if session.GitStatus.HasError() {
+			fmt.Printf("   🌿 Branch: %s\n", formatBranch(session))
+		} else {
+			fmt.Printf("   🌿 Branch: %s (%s)\n", formatBranch(session), formatter.FormatBranchSync(session))
+		}
 
 		// Git status
 		gitDetails := ""
-		if session.GitStatus.HasChanges {
+		if session.GitStatus.HasError() {
+			gitDetails = fmt.Sprintf(" (%s)", session.GitStatus.Error)
+		} else if session.GitStatus.HasChanges {
 			changes := []string{}
 			if len(session.GitStatus.ModifiedFiles) > 0 {
 				changes = append(changes, fmt.Sprintf("%d modified", len(session.GitStatus.ModifiedFiles)))
@@ -183,9 +343,29 @@ func renderVerboseSessionList(sessions []types.Session, formatter *operations.St
 			if len(session.GitStatus.DeletedFiles) > 0 {
 				changes = append(changes, fmt.Sprintf("%d deleted", len(session.GitStatus.DeletedFiles)))
 			}
-			gitDetails = fmt.Sprintf(" (%s)", strings.Join(changes, ", "))
+			if len(changes) > 0 {
+				gitDetails = fmt.Sprintf(" (%s)", strings.Join(changes, ", "))
+			}
 		}
 		fmt.Printf("   📁 Git: %s%s\n", formatter.FormatGitStatus(session.GitStatus), gitDetails)
+		if staging := formatter.FormatStaging(session.GitStatus); staging != "" {
+			fmt.Printf("   📥 Staging: %s\n", staging)
+		}
+		if session.GitStatus.HasConflicts() {
+			fmt.Printf("   ⚠ Conflicts: %s\n", formatFileList(conflictDescriptions(session.GitStatus.ConflictedFiles), 3))
+		}
+		if hint := operations.WorktreeHint(session); hint != "" {
+			fmt.Printf("      💡 %s\n", hint)
+		}
+		if hint := operations.RebaseHint(session); hint != "" {
+			fmt.Printf("      💡 %s\n", hint)
+		}
+		if warning := operations.ConflictWarning(session); warning != "" {
+			fmt.Printf("      ⚠️  %s\n", warning)
+		}
+		if hint := operations.ReviewHint(session); hint != "" {
+			fmt.Printf("      🆕 %s\n", hint)
+		}
 
 		// Claude status
 		claudeDetails := ""
@@ -202,20 +382,44 @@ func renderVerboseSessionList(sessions []types.Session, formatter *operations.St
 		if session.ClaudeStatus.StatusMessage != "" {
 			fmt.Printf("      Message: %s\n", session.ClaudeStatus.StatusMessage)
 		}
+		if hint := operations.ClaudeHint(session); hint != "" {
+			fmt.Printf("      💡 %s\n", hint)
+		}
+
+		if used := usage[session.Core.ID]; !used.Empty() {
+			fmt.Printf("   💰 Usage: %s\n", formatter.FormatUsage(used))
+			if limits.OverCost(used) {
+				fmt.Printf("      ⚠️  Over the session cost warning of %s (limits.session_cost)\n", types.FormatCost(limits.SessionCost))
+			}
+		}
 
 		// Last activity
 		fmt.Printf("   ⏰ Activity: %s\n", formatter.FormatActivity(session.LastActivity))
+
+		if followUp := session.Core.FollowUp; followUp != nil {
+			fmt.Printf("   🔁 Follow-up: %s (on %s: %s)\n", formatter.FormatFollowUp(*followUp), followUp.On, followUp.Command)
+		}
+		if coverage := formatter.FormatCoverage(session); coverage != "" {
+			fmt.Printf("   🧪 Coverage: %s\n", coverage)
+		}
+		if pr := session.Core.PullRequest; pr != nil {
+			fmt.Printf("   🔀 Pull request: %s (%s)\n", formatter.FormatPullRequest(*pr), pr.URL)
+		}
+
+		// Fields from status providers
+		for _, field := range session.Extra {
+			fmt.Printf("   🔌 %s: %s\n", field.Label(), field.Value)
+		}
 	}
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	if maxLen <= 3 {
-		return s[:maxLen]
+// truncate cuts s to at most maxWidth columns, marking the cut with "..."
+// when there is room for it
+func truncate(s string, maxWidth int) string {
+	if maxWidth <= 3 {
+		return runewidth.Truncate(s, maxWidth, "")
 	}
-	return s[:maxLen-3] + "..."
+	return runewidth.Truncate(s, maxWidth, "...")
 }
 
 // visualLength calculates the visual display width of a string using runewidth
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"fits", "auth", 30, "auth"},
+		{"ascii", "go test ./... -run TestLogin -count=1", 20, "go test ./... -ru..."},
+		{"follow-up with emoji", "✅ npm test — tous les tests réussis", 12, "✅ npm te..."},
+		{"status provider value", "进行中：等待代码审查的合并请求", 10, "进行中..."},
+		{"narrow", "feature", 3, "fea"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.s, tt.width)
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+			if !utf8.ValidString(got) || runewidth.StringWidth(got) > tt.width {
+				t.Errorf("truncate(%q, %d) = %q, want valid text at most %d columns wide", tt.s, tt.width, got, tt.width)
+			}
+		})
+	}
+}
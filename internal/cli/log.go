@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// logTimeFormat is how 'cwt log' prints the time of each event
+const logTimeFormat = "2006-01-02 15:04:05"
+
+func newLogCmd() *cobra.Command {
+	var jsonOutput, lifecycle bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "log <session-name>",
+		Short: "Show the timeline of what happened in a session",
+		Long: `Show a session's timeline, oldest first: when it was created, attached,
+committed, merged, published, paused or renamed, alongside the events
+Claude's hooks reported while it worked.
+
+The timeline is kept in .cwt/sessions/<session-id>/events.jsonl, and moves
+with the session into the archive.
+
+Examples:
+  cwt log my-session               # Everything that happened
+  cwt log my-session --lifecycle   # Only what was done to the session
+  cwt log my-session -n 20         # The last 20 events
+  cwt log my-session --json        # Machine-readable output`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogCmd(args[0], jsonOutput, lifecycle, limit)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output events as JSON")
+	cmd.Flags().BoolVar(&lifecycle, "lifecycle", false, "Leave out the events Claude's hooks reported")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Show only the last n events")
+
+	return cmd
+}
+
+func runLogCmd(name string, jsonOutput, lifecycle bool, limit int) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	_, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+
+	events, err := sm.Timeline(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to read timeline of session '%s': %w", name, err)
+	}
+	events = filterTimeline(events, lifecycle, limit)
+
+	if jsonOutput {
+		return writeJSON(events)
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("Nothing recorded for session '%s' yet.\n", name)
+		return nil
+	}
+
+	formatter := operations.NewStatusFormat()
+	fmt.Printf("📜 Timeline of session '%s'\n\n", name)
+	for _, event := range events {
+		fmt.Printf("  %s  %s\n", event.Time.Local().Format(logTimeFormat), formatter.FormatTimelineEvent(event))
+	}
+	return nil
+}
+
+// filterTimeline keeps the lifecycle events only, if asked to, and then the
+// last limit events when limit is positive
+func filterTimeline(events []types.SessionEvent, lifecycle bool, limit int) []types.SessionEvent {
+	if lifecycle {
+		filtered := []types.SessionEvent{}
+		for _, event := range events {
+			if types.IsLifecycleEvent(event.Type) {
+				filtered = append(filtered, event)
+			}
+		}
+		events = filtered
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events
+}
@@ -1,58 +1,163 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/operations"
 	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
 // newMergeCmd creates the 'cwt merge' command
 func newMergeCmd() *cobra.Command {
-	var target string
-	var squash bool
-	var dryRun bool
+	var opts mergeOptions
+	var jsonOutput bool
 
 	cmd := &cobra.Command{
 		Use:   "merge <session-name>",
 		Short: "Merge session changes back to target branch",
 		Long: `Safely integrate session changes back to target branches with conflict resolution.
 
+With --json, the output is a document describing the merge: the commits and
+files it brought in, the commit it created, or the files left conflicted.
+It can't prompt, so it needs --yes (or --dry-run).
+
+When the merge stops on conflicts, an interactive merge lists the conflicted
+files and offers to open them in your editor or git mergetool, then to
+continue or abort the merge. You can also leave it and come back later with
+--continue, which commits the merge once every conflict is resolved, or
+--abort, which puts the target branch back as it was.
+
+With --cleanup (or merge.cleanup in the config), a session merged cleanly is
+then deleted: its tmux session, worktree, branch and metadata. Its commits
+are on the target by then, so the branch goes even after a squash merge. A
+session with uncommitted changes, which the merge leaves out, is kept, as is
+one whose merge stopped on conflicts.
+
 Examples:
   cwt merge my-session              # Interactive merge to current branch
   cwt merge my-session --target main  # Merge to specific target branch
   cwt merge my-session --squash     # Squash merge for clean history
-  cwt merge my-session --dry-run    # Preview merge without executing`,
-		Args: cobra.ExactArgs(1),
+  cwt merge my-session --dry-run    # Preview merge without executing
+  cwt merge my-session --yes --json # Merge without asking, for scripts
+  cwt merge my-session --cleanup    # Delete the session once it is merged
+  cwt merge --continue              # Commit a merge once its conflicts are resolved
+  cwt merge --abort                 # Give up on a merge that stopped on conflicts`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			resuming := opts.Continue || opts.Abort
+			switch {
+			case opts.Continue && opts.Abort:
+				return fmt.Errorf("--continue and --abort can't be used together")
+			case !resuming && len(args) == 0:
+				return fmt.Errorf("requires a session name, or --continue or --abort")
+			}
+			if jsonOutput && !opts.Yes && !opts.DryRun && !resuming {
+				return fmt.Errorf("--json can't prompt for confirmation; add --yes or --dry-run")
+			}
+			if !cmd.Flags().Changed("cleanup") {
+				opts.Cleanup = appConfig.Merge.Cleanup
+			}
+
 			sm, err := createStateManager()
 			if err != nil {
 				return err
 			}
 			defer sm.Close()
 
-			sessionName := args[0]
-			return mergeSession(sm, sessionName, target, squash, dryRun)
+			var out io.Writer = os.Stdout
+			if jsonOutput {
+				out = io.Discard
+			}
+
+			var result types.MergeResultOutput
+			if resuming {
+				result, err = resumeMerge(sm, args, opts, out)
+			} else {
+				result, err = mergeSession(sm, args[0], opts, out)
+			}
+			if jsonOutput {
+				if err != nil {
+					result.Error = err.Error()
+				}
+				if jsonErr := writeJSON(result); jsonErr != nil {
+					return jsonErr
+				}
+			}
+			return err
 		},
 	}
 
-	cmd.Flags().StringVar(&target, "target", "", "Target branch to merge into (default: current branch)")
-	cmd.Flags().BoolVar(&squash, "squash", false, "Squash merge for clean history")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview merge without executing")
+	cmd.Flags().StringVar(&opts.Target, "target", "", "Target branch to merge into (default: current branch)")
+	cmd.RegisterFlagCompletionFunc("target", completeBranches)
+	cmd.Flags().BoolVar(&opts.Squash, "squash", false, "Squash merge for clean history")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Preview merge without executing")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Merge without asking (protected mode still needs --confirm)")
+	cmd.Flags().StringVar(&opts.ConfirmToken, "confirm", "", "Confirmation token printed by --dry-run in protected mode")
+	cmd.Flags().BoolVar(&opts.Cleanup, "cleanup", false, "Delete the session, its worktree and branch once it is merged (default: merge.cleanup from config)")
+	cmd.Flags().BoolVar(&opts.Continue, "continue", false, "Commit a merge that stopped on conflicts once they are resolved")
+	cmd.Flags().BoolVar(&opts.Abort, "abort", false, "Abort a merge that stopped on conflicts")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the result of the merge as JSON")
 
 	return cmd
 }
 
-// mergeSession merges a session's changes into the target branch
-func mergeSession(sm *state.Manager, sessionName, target string, squash, dryRun bool) error {
+// mergeOptions are the settings of a 'cwt merge'
+type mergeOptions struct {
+	Target       string // Branch to merge into; the current branch when empty
+	Squash       bool
+	DryRun       bool
+	Yes          bool // Skip the y/N prompt
+	ConfirmToken string
+	Continue     bool // Finish a merge that stopped on conflicts
+	Abort        bool // Undo a merge that stopped on conflicts
+	Cleanup      bool // Delete the session once it is merged cleanly
+}
+
+// errMergeConflicts is returned when conflicts stopped a merge halfway
+var errMergeConflicts = errors.New("merge conflicts detected. Resolve them, then run 'cwt merge --continue', or 'cwt merge --abort' to undo the merge")
+
+// resumeMerge continues or aborts the merge that stopped on conflicts; a
+// session named in args must be the one being merged
+func resumeMerge(sm *state.Manager, args []string, opts mergeOptions, out io.Writer) (types.MergeResultOutput, error) {
+	merge, err := loadPendingMerge(sm.GetDataDir())
+	if err != nil {
+		return types.MergeResultOutput{}, err
+	}
+	if merge == nil {
+		return types.NewMergeResultOutput("", "", "", false), fmt.Errorf("no cwt merge stopped on conflicts")
+	}
+	if len(args) > 0 && args[0] != merge.Session {
+		return types.NewMergeResultOutput(args[0], "", "", false),
+			fmt.Errorf("the merge stopped on conflicts is of session '%s', not '%s'", merge.Session, args[0])
+	}
+
+	if opts.Continue {
+		return continueMerge(sm, *merge, out)
+	}
+	result := types.NewMergeResultOutput(merge.Session, merge.SessionBranch, merge.Target, merge.Squash)
+	return result, abortMerge(sm, *merge, out)
+}
+
+// mergeSession merges a session's changes into the target branch, printing
+// its progress to out. The result describes as much as was done, even when
+// an error stopped the merge.
+func mergeSession(sm *state.Manager, sessionName string, opts mergeOptions, out io.Writer) (types.MergeResultOutput, error) {
+	result := types.NewMergeResultOutput(sessionName, sessionName, opts.Target, opts.Squash)
+	result.DryRun = opts.DryRun
+
 	sessions, err := sm.DeriveFreshSessions()
 	if err != nil {
-		return fmt.Errorf("failed to load sessions: %w", err)
+		return result, fmt.Errorf("failed to load sessions: %w", err)
 	}
 
 	// Find the session
@@ -65,52 +170,159 @@ func mergeSession(sm *state.Manager, sessionName, target string, squash, dryRun
 	}
 
 	if targetSession == nil {
-		return fmt.Errorf("session '%s' not found", sessionName)
+		return result, fmt.Errorf("session '%s' not found", sessionName)
+	}
+	if merge, err := loadPendingMerge(sm.GetDataDir()); err != nil {
+		return result, err
+	} else if merge != nil {
+		return result, fmt.Errorf("merging session '%s' into '%s' stopped on conflicts; finish it with 'cwt merge --continue' or undo it with 'cwt merge --abort'",
+			merge.Session, merge.Target)
+	}
+	sessionBranch := targetSession.BranchName()
+	result.SessionBranch = sessionBranch
+	if err := operations.CheckWorktree(*targetSession); err != nil {
+		return result, err
+	}
+	if err := operations.CheckConflicts(*targetSession); err != nil {
+		return result, err
 	}
 
 	// Determine target branch
+	target := opts.Target
 	if target == "" {
 		currentBranch, err := getCurrentBranch()
 		if err != nil {
-			return fmt.Errorf("failed to get current branch: %w", err)
+			return result, fmt.Errorf("failed to get current branch: %w", err)
 		}
 		target = currentBranch
 	}
-
-	sessionBranch := fmt.Sprintf("cwt-%s", sessionName)
+	result.Target = target
 
 	// Validate pre-merge conditions
 	if err := validateMergeConditions(target, sessionBranch); err != nil {
-		return err
+		return result, err
+	}
+
+	// Warn before anything is touched when the merge is bound to conflict
+	if warning := operations.ConflictWarning(*targetSession); warning != "" && target == targetSession.BaseBranch {
+		fmt.Fprintf(out, "⚠️  %s\n\n", warning)
 	}
 
 	// Show merge preview
-	if err := showMergePreview(sessionBranch, target); err != nil {
-		return fmt.Errorf("failed to show merge preview: %w", err)
+	if err := showMergePreview(out, sessionBranch, target); err != nil {
+		return result, fmt.Errorf("failed to show merge preview: %w", err)
 	}
+	result.Commits = branchCommits(target, sessionBranch)
+	result.Files = branchFiles(target, sessionBranch)
 
-	if dryRun {
-		fmt.Println("\nDry run completed. No changes were made.")
-		return nil
+	op := operations.MergeOperation(sessionName, sessionBranch, target, opts.Squash)
+	command := fmt.Sprintf("cwt merge %s --target %s", sessionName, target)
+	if opts.Squash {
+		command += " --squash"
 	}
 
-	// Confirm merge unless dry run
-	if !confirmMerge(sessionName, target, squash) {
-		fmt.Println("Merge cancelled")
-		return nil
+	if opts.DryRun {
+		if opts.Cleanup {
+			fmt.Fprintf(out, "\nOnce merged, session '%s' would be deleted with its worktree and branch.\n", sessionName)
+		}
+		fmt.Fprintln(out, "\nDry run completed. No changes were made.")
+		printConfirmationToken(out, op, command)
+		if appConfig.Protected {
+			result.ConfirmToken = op.Token()
+		}
+		return result, nil
+	}
+
+	// Confirm merge unless dry run; protected mode replaces the y/N prompt
+	confirmed, err := confirmProtected(op, opts.ConfirmToken, command+" --dry-run")
+	if err != nil {
+		return result, err
+	}
+	if !confirmed && !opts.Yes && !confirmMerge(sessionName, target, opts.Squash) {
+		fmt.Fprintln(out, "Merge cancelled")
+		return result, nil
 	}
 
 	// Perform the merge
-	if err := performMerge(sessionBranch, target, squash); err != nil {
-		return fmt.Errorf("merge failed: %w", err)
+	if err := performMerge(out, sessionBranch, target, opts.Squash); err != nil {
+		if !errors.Is(err, errMergeConflicts) {
+			return result, fmt.Errorf("merge failed: %w", err)
+		}
+		result.Conflicts = unmergedFiles()
+		merge := pendingMerge{
+			Session:       sessionName,
+			SessionBranch: sessionBranch,
+			Target:        target,
+			Squash:        opts.Squash,
+			StartedAt:     time.Now(),
+		}
+		if err := savePendingMerge(sm.GetDataDir(), merge); err != nil {
+			fmt.Fprintf(out, "Warning: failed to record merge in progress: %v\n", err)
+		}
+		// Walk through resolving them when someone is there to ask
+		if !opts.Yes && stdinIsTerminal() {
+			resolved, err := resolveConflicts(sm, merge, os.Stdin, out)
+			if err != nil {
+				return resolved, fmt.Errorf("merge failed: %w", err)
+			}
+			return resolved, nil
+		}
+		return result, fmt.Errorf("merge failed: %w", err)
+	}
+	result.Merged = true
+	result.Commit = headCommit()
+
+	fmt.Fprintf(out, "Successfully merged session '%s' into '%s'\n", sessionName, target)
+	sm.RecordEvent(targetSession.Core.ID, types.EventMerged, fmt.Sprintf("Merged into %s", target), map[string]interface{}{
+		"target": target,
+		"squash": opts.Squash,
+	})
+	if err := sm.RunPostMergeHooks(targetSession.Core.ID); err != nil {
+		fmt.Fprintf(out, "⚠️  %v\n", err)
 	}
 
-	fmt.Printf("Successfully merged session '%s' into '%s'\n", sessionName, target)
+	if opts.Cleanup {
+		result.Cleanup = cleanupMergedSession(sm, *targetSession, out)
+	}
 
-	// Update session status (this would require extending the Session type)
-	// For now, just print success message
+	// Let running TUIs pick up the merge immediately
+	sm.NotifyRefresh(sessionName, "merge")
 
-	return nil
+	return result, nil
+}
+
+// cleanupMergedSession deletes a session just merged cleanly: its tmux
+// session, worktree, metadata and, its commits being on the target now, its
+// branch. A session with uncommitted changes, which the merge left out, is
+// kept. Failing to clean up doesn't fail the merge; the result says why.
+func cleanupMergedSession(sm *state.Manager, session types.Session, out io.Writer) *types.CleanupOutput {
+	name := session.Core.Name
+	cleanup := &types.CleanupOutput{TmuxSession: session.Core.TmuxSession, Worktree: session.Core.WorktreePath}
+	if changes := changedFileCount(session.GitStatus); changes > 0 {
+		cleanup.Skipped = fmt.Sprintf("it has %d uncommitted change(s) the merge left out", changes)
+		fmt.Fprintf(out, "\n🧹 Kept session '%s': %s; delete it with 'cwt delete %s --force'\n", name, cleanup.Skipped, name)
+		return cleanup
+	}
+
+	deleted, err := sm.DeleteSessionWithOptions(session.Core.ID, state.DeleteOptions{Force: true, DeleteBranch: true})
+	if err != nil {
+		cleanup.Skipped = err.Error()
+		fmt.Fprintf(out, "\n⚠️  Failed to clean up session '%s': %v\n", name, err)
+		return cleanup
+	}
+	cleanup.Deleted = true
+
+	fmt.Fprintf(out, "\n🧹 Cleaned up session '%s':\n", name)
+	fmt.Fprintf(out, "   🖥️  Tmux session: %s\n", cleanup.TmuxSession)
+	fmt.Fprintf(out, "   📁 Worktree:     %s\n", cleanup.Worktree)
+	if deleted.BranchDeleted {
+		cleanup.Branch = deleted.Branch
+		fmt.Fprintf(out, "   🌿 Branch:       %s\n", deleted.Branch)
+	} else {
+		fmt.Fprintf(out, "   🌿 Branch:       %s (kept: failed to delete it)\n", deleted.Branch)
+	}
+	fmt.Fprintf(out, "   🗂️  Metadata:     %s\n", session.Core.ID)
+	return cleanup
 }
 
 // validateMergeConditions checks if merge can proceed safely
@@ -144,23 +356,23 @@ func validateMergeConditions(targetBranch, sessionBranch string) error {
 }
 
 // showMergePreview displays what will be merged
-func showMergePreview(sessionBranch, targetBranch string) error {
-	fmt.Printf("Merge Preview: %s -> %s\n", sessionBranch, targetBranch)
-	fmt.Println(strings.Repeat("=", 50))
+func showMergePreview(out io.Writer, sessionBranch, targetBranch string) error {
+	fmt.Fprintf(out, "Merge Preview: %s -> %s\n", sessionBranch, targetBranch)
+	fmt.Fprintln(out, strings.Repeat("=", 50))
 
 	// Show commit summary
 	cmd := exec.Command("git", "log", "--oneline", fmt.Sprintf("%s..%s", targetBranch, sessionBranch))
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = out
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to show commit summary: %w", err)
 	}
 
-	fmt.Println(strings.Repeat("=", 50))
+	fmt.Fprintln(out, strings.Repeat("=", 50))
 
 	// Show file changes summary
 	cmd = exec.Command("git", "diff", "--stat", targetBranch, sessionBranch)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = out
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to show file changes: %w", err)
@@ -186,7 +398,7 @@ func confirmMerge(sessionName, target string, squash bool) bool {
 }
 
 // performMerge executes the actual merge
-func performMerge(sessionBranch, targetBranch string, squash bool) error {
+func performMerge(out io.Writer, sessionBranch, targetBranch string, squash bool) error {
 	// Switch to target branch first
 	if err := switchBranch(targetBranch); err != nil {
 		return fmt.Errorf("failed to switch to target branch '%s': %w", targetBranch, err)
@@ -200,14 +412,14 @@ func performMerge(sessionBranch, targetBranch string, squash bool) error {
 		cmd = exec.Command("git", "merge", "--no-ff", sessionBranch, "-m", fmt.Sprintf("Merge session branch %s", sessionBranch))
 	}
 
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = out
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
 		// If merge failed, try to provide helpful error message
 		if exitError, ok := err.(*exec.ExitError); ok {
 			if exitError.ExitCode() == 1 {
-				return fmt.Errorf("merge conflicts detected. Please resolve conflicts and run 'git commit' to complete the merge")
+				return errMergeConflicts
 			}
 		}
 		return fmt.Errorf("merge command failed: %w", err)
@@ -215,9 +427,8 @@ func performMerge(sessionBranch, targetBranch string, squash bool) error {
 
 	// If squash merge, we need to commit the changes
 	if squash {
-		commitMsg := fmt.Sprintf("Squash merge session %s", strings.TrimPrefix(sessionBranch, "cwt-"))
-		cmd = exec.Command("git", "commit", "-m", commitMsg)
-		cmd.Stdout = os.Stdout
+		cmd = exec.Command("git", "commit", "-m", squashCommitMessage(sessionBranch))
+		cmd.Stdout = out
 		cmd.Stderr = os.Stderr
 
 		if err := cmd.Run(); err != nil {
@@ -246,3 +457,62 @@ func branchIsAhead(sourceBranch, targetBranch string) bool {
 	count := strings.TrimSpace(string(output))
 	return count != "0"
 }
+
+// branchCommits lists the commits on sourceBranch that targetBranch lacks,
+// oldest first
+func branchCommits(targetBranch, sourceBranch string) []types.CommitOutput {
+	commits := []types.CommitOutput{}
+	cmd := exec.Command("git", "log", "--reverse", "--format=%H%x09%s", fmt.Sprintf("%s..%s", targetBranch, sourceBranch))
+	output, err := cmd.Output()
+	if err != nil {
+		return commits
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, subject, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		commits = append(commits, types.CommitOutput{Hash: hash, Subject: subject})
+	}
+	return commits
+}
+
+// branchFiles lists the files sourceBranch changed since it forked from
+// targetBranch
+func branchFiles(targetBranch, sourceBranch string) []string {
+	cmd := exec.Command("git", "diff", "--name-only", fmt.Sprintf("%s...%s", targetBranch, sourceBranch))
+	output, err := cmd.Output()
+	if err != nil {
+		return []string{}
+	}
+	return outputLines(output)
+}
+
+// unmergedFiles lists the files a stopped merge left conflicted
+func unmergedFiles() []string {
+	output, err := exec.Command("git", "diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return []string{}
+	}
+	return outputLines(output)
+}
+
+// outputLines splits a command's output into its non-empty lines
+func outputLines(output []byte) []string {
+	lines := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// headCommit returns the hash of the commit checked out
+func headCommit() string {
+	output, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
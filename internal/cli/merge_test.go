@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	gitclient "github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+func TestMergeResultHelpers(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+	git("checkout", "-q", "-b", "cwt-auth")
+	os.WriteFile(filepath.Join(repo, "login file.go"), []byte("package auth\n"), 0644)
+	git("add", ".")
+	git("commit", "-q", "-m", "Add login")
+	git("checkout", "-q", "main")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	commits := branchCommits("main", "cwt-auth")
+	if len(commits) != 1 || commits[0].Subject != "Add login" || len(commits[0].Hash) != 40 {
+		t.Errorf("branchCommits() = %+v, want the session's commit", commits)
+	}
+	if files := branchFiles("main", "cwt-auth"); len(files) != 1 || files[0] != "login file.go" {
+		t.Errorf("branchFiles() = %q, want the file with its space kept", files)
+	}
+	if files := unmergedFiles(); len(files) != 0 {
+		t.Errorf("unmergedFiles() = %q, want none outside a merge", files)
+	}
+	if len(headCommit()) != 40 {
+		t.Errorf("headCommit() = %q, want a full hash", headCommit())
+	}
+}
+
+func TestMergeConflictWorkflow(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "login.go"), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.name", "test")
+	git("config", "user.email", "test@example.com")
+	write("package auth\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	git("checkout", "-q", "-b", "cwt-auth")
+	write("package auth // session\n")
+	git("commit", "-q", "-am", "Session change")
+	git("checkout", "-q", "main")
+	write("package auth // main\n")
+	git("commit", "-q", "-am", "Main change")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	dataDir := t.TempDir()
+	sm := state.NewManager(state.Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitclient.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	// A squash merge stops on the conflict
+	if err := performMerge(io.Discard, "cwt-auth", "main", true); !errors.Is(err, errMergeConflicts) {
+		t.Fatalf("performMerge() error = %v, want conflicts", err)
+	}
+	merge := pendingMerge{Session: "auth", SessionBranch: "cwt-auth", Target: "main", Squash: true}
+	if err := savePendingMerge(dataDir, merge); err != nil {
+		t.Fatalf("savePendingMerge() error = %v", err)
+	}
+	if pending, err := loadPendingMerge(dataDir); err != nil || pending == nil || pending.Session != "auth" {
+		t.Fatalf("loadPendingMerge() = %+v, %v; want the squash merge, which has no MERGE_HEAD", pending, err)
+	}
+
+	// Continuing is refused while the conflict remains, and quitting leaves the merge
+	var out bytes.Buffer
+	result, err := resolveConflicts(sm, merge, strings.NewReader("c\nq\n"), &out)
+	if !errors.Is(err, errMergeConflicts) || len(result.Conflicts) != 1 || result.Conflicts[0] != "login.go" {
+		t.Errorf("resolveConflicts() = %+v, %v; want the conflict left", result, err)
+	}
+	if !strings.Contains(out.String(), "still have conflicts: login.go") {
+		t.Errorf("output = %q, want the refusal to continue explained", out.String())
+	}
+
+	write("package auth // both\n")
+	git("add", "login.go")
+	result, err = continueMerge(sm, merge, io.Discard)
+	if err != nil || !result.Merged || len(result.Commit) != 40 {
+		t.Fatalf("continueMerge() = %+v, %v; want the merge committed", result, err)
+	}
+	if subject := git("log", "-1", "--format=%s"); subject != squashCommitMessage("cwt-auth") {
+		t.Errorf("commit subject = %q, want the squash merge's", subject)
+	}
+	if pending, _ := loadPendingMerge(dataDir); pending != nil {
+		t.Errorf("loadPendingMerge() = %+v after continuing, want none", pending)
+	}
+
+	// A plain merge is aborted with git merge --abort
+	git("reset", "-q", "--hard", "HEAD~1")
+	if err := performMerge(io.Discard, "cwt-auth", "main", false); !errors.Is(err, errMergeConflicts) {
+		t.Fatalf("performMerge() error = %v, want conflicts", err)
+	}
+	merge.Squash = false
+	savePendingMerge(dataDir, merge)
+	if err := abortMerge(sm, merge, io.Discard); err != nil {
+		t.Fatalf("abortMerge() error = %v", err)
+	}
+	if gitMergeInProgress() || hasUncommittedChanges() {
+		t.Error("aborting should restore the target branch")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, pendingMergeFileName)); !os.IsNotExist(err) {
+		t.Error("aborting should clear the merge in progress")
+	}
+
+	// A record of a merge finished with git itself is dropped
+	savePendingMerge(dataDir, merge)
+	if pending, _ := loadPendingMerge(dataDir); pending != nil {
+		t.Errorf("loadPendingMerge() = %+v with no merge under way, want none", pending)
+	}
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	conflicted := "a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> cwt-auth\n"
+	if !hasConflictMarkers(conflicted) {
+		t.Error("hasConflictMarkers() = false for a conflicted file")
+	}
+	if hasConflictMarkers("a\n=======\nb\n") {
+		t.Error("hasConflictMarkers() = true for a file with only a rule of equals signs")
+	}
+}
+
+func TestCleanupMergedSession(t *testing.T) {
+	gitChecker := gitclient.NewMockChecker()
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	for _, name := range []string{"dirty", "clean"} {
+		if err := sm.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+	sessions, _ := sm.DeriveFreshSessions()
+	dirty, clean := sessions[0], sessions[1]
+	// Squash merges leave the branch's commits off the base branch
+	gitChecker.Unmerged[clean.Core.Name] = true
+
+	dirty.GitStatus.ModifiedFiles = []string{"login.go"}
+	cleanup := cleanupMergedSession(sm, dirty, io.Discard)
+	if cleanup.Deleted || !strings.Contains(cleanup.Skipped, "1 uncommitted change(s)") {
+		t.Errorf("cleanup = %+v, want a session with uncommitted changes kept", cleanup)
+	}
+
+	var out bytes.Buffer
+	cleanup = cleanupMergedSession(sm, clean, &out)
+	if !cleanup.Deleted || cleanup.Branch != clean.Core.Name || cleanup.Worktree != clean.Core.WorktreePath {
+		t.Errorf("cleanup = %+v, want the session and its branch deleted", cleanup)
+	}
+	if !strings.Contains(out.String(), "Cleaned up session 'clean'") {
+		t.Errorf("output = %q, want a summary of what was removed", out.String())
+	}
+	if cores, _ := sm.CoreSessions(); len(cores) != 1 || cores[0].Name != "dirty" {
+		t.Errorf("sessions left = %+v, want only the dirty one", cores)
+	}
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// pendingMergeFileName is the file in the data directory recording a merge
+// that conflicts stopped, for 'cwt merge --continue' and '--abort'
+const pendingMergeFileName = "merge_in_progress.json"
+
+// pendingMerge is a merge of a session that stopped on conflicts
+type pendingMerge struct {
+	Session       string    `json:"session"`
+	SessionBranch string    `json:"session_branch"`
+	Target        string    `json:"target"`
+	Squash        bool      `json:"squash"`
+	StartedAt     time.Time `json:"started_at"`
+}
+
+func savePendingMerge(dataDir string, merge pendingMerge) error {
+	data, err := json.Marshal(merge)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dataDir, pendingMergeFileName), data, 0644)
+}
+
+// loadPendingMerge returns the merge stopped on conflicts, or nil when
+// there is none. A record left behind by a merge finished or aborted with
+// git itself is cleared.
+func loadPendingMerge(dataDir string) (*pendingMerge, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, pendingMergeFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read merge in progress: %w", err)
+	}
+
+	var merge pendingMerge
+	if err := json.Unmarshal(data, &merge); err != nil || !gitMergeInProgress() {
+		clearPendingMerge(dataDir)
+		return nil, nil
+	}
+	return &merge, nil
+}
+
+func clearPendingMerge(dataDir string) {
+	os.Remove(filepath.Join(dataDir, pendingMergeFileName))
+}
+
+// gitMergeInProgress reports whether the repository is in the middle of a
+// merge, or of a squash merge, which leaves no MERGE_HEAD
+func gitMergeInProgress() bool {
+	if exec.Command("git", "rev-parse", "--quiet", "--verify", "MERGE_HEAD").Run() == nil {
+		return true
+	}
+	output, err := exec.Command("git", "rev-parse", "--git-path", "SQUASH_MSG").Output()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(strings.TrimSpace(string(output)))
+	return err == nil
+}
+
+// squashCommitMessage is the message of the commit a squash merge creates
+func squashCommitMessage(sessionBranch string) string {
+	return fmt.Sprintf("Squash merge session branch %s", sessionBranch)
+}
+
+// continueMerge commits a merge stopped on conflicts once they are all
+// resolved, returning the files still conflicted otherwise
+func continueMerge(sm *state.Manager, merge pendingMerge, out io.Writer) (types.MergeResultOutput, error) {
+	result := types.NewMergeResultOutput(merge.Session, merge.SessionBranch, merge.Target, merge.Squash)
+	if conflicts := unmergedFiles(); len(conflicts) > 0 {
+		result.Conflicts = conflicts
+		return result, fmt.Errorf("%d file(s) still have conflicts: %s; resolve them and 'git add' them first",
+			len(conflicts), strings.Join(conflicts, ", "))
+	}
+
+	args := []string{"commit", "--no-edit"}
+	if merge.Squash {
+		args = []string{"commit", "-m", squashCommitMessage(merge.SessionBranch)}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return result, fmt.Errorf("failed to commit merge: %w", err)
+	}
+	clearPendingMerge(sm.GetDataDir())
+	result.Merged = true
+	result.Commit = headCommit()
+
+	fmt.Fprintf(out, "Successfully merged session '%s' into '%s'\n", merge.Session, merge.Target)
+	sm.RecordEventByName(merge.Session, types.EventMerged, fmt.Sprintf("Merged into %s", merge.Target), map[string]interface{}{
+		"target": merge.Target,
+		"squash": merge.Squash,
+	})
+	if err := runPostMergeHooksByName(sm, merge.Session); err != nil {
+		fmt.Fprintf(out, "⚠️  %v\n", err)
+	}
+	sm.NotifyRefresh(merge.Session, "merge")
+	return result, nil
+}
+
+// abortMerge undoes a merge stopped on conflicts, restoring the target
+// branch as it was before
+func abortMerge(sm *state.Manager, merge pendingMerge, out io.Writer) error {
+	// A squash merge has no MERGE_HEAD for 'git merge --abort' to go back to
+	args := []string{"merge", "--abort"}
+	if merge.Squash {
+		args = []string{"reset", "--merge"}
+	}
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to abort merge: %w\nOutput: %s", err, string(output))
+	}
+	clearPendingMerge(sm.GetDataDir())
+
+	fmt.Fprintf(out, "↩️  Aborted merging session '%s'; '%s' is back where it was\n", merge.Session, merge.Target)
+	sm.NotifyRefresh(merge.Session, "merge")
+	return nil
+}
+
+// resolveConflicts guides through a merge stopped on conflicts: it lists
+// the conflicted files and offers to edit them, run git mergetool, then
+// continue or abort the merge. Quitting leaves the merge for 'cwt merge
+// --continue' or '--abort'.
+func resolveConflicts(sm *state.Manager, merge pendingMerge, in io.Reader, out io.Writer) (types.MergeResultOutput, error) {
+	result := types.NewMergeResultOutput(merge.Session, merge.SessionBranch, merge.Target, merge.Squash)
+	reader := bufio.NewReader(in)
+
+	for {
+		conflicts := unmergedFiles()
+		result.Conflicts = conflicts
+
+		fmt.Fprintln(out)
+		if len(conflicts) == 0 {
+			fmt.Fprintln(out, "✅ All conflicts are resolved.")
+		} else {
+			fmt.Fprintf(out, "⚠️  Merging '%s' into '%s' stopped on conflicts in %d file(s):\n", merge.Session, merge.Target, len(conflicts))
+			for _, file := range conflicts {
+				fmt.Fprintf(out, "   %s\n", file)
+			}
+		}
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "  e. ✏️  Edit the conflicted files in your editor")
+		fmt.Fprintln(out, "  t. 🔧 Resolve them with git mergetool")
+		fmt.Fprintln(out, "  c. ✅ Continue: commit the merge")
+		fmt.Fprintln(out, "  a. ❌ Abort the merge")
+		fmt.Fprintln(out, "  q. ⏸️  Quit and finish later with 'cwt merge --continue' or 'cwt merge --abort'")
+		fmt.Fprint(out, "\nEnter your choice [e/t/c/a/q]: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return result, errMergeConflicts
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "e":
+			if len(conflicts) == 0 {
+				continue
+			}
+			if err := editConflicts(conflicts); err != nil {
+				fmt.Fprintf(out, "❌ %v\n", err)
+			}
+		case "t":
+			cmd := exec.Command("git", "mergetool")
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				fmt.Fprintf(out, "❌ git mergetool failed: %v\n", err)
+			}
+		case "c":
+			continued, err := continueMerge(sm, merge, out)
+			if err != nil {
+				fmt.Fprintf(out, "❌ %v\n", err)
+				continue
+			}
+			return continued, nil
+		case "a":
+			if err := abortMerge(sm, merge, out); err != nil {
+				return result, err
+			}
+			result.Conflicts = []string{}
+			return result, fmt.Errorf("merge aborted")
+		case "q", "":
+			return result, errMergeConflicts
+		default:
+			fmt.Fprintln(out, "Invalid choice.")
+		}
+	}
+}
+
+// editConflicts opens conflicted files in the configured editor, then
+// marks those left without conflict markers resolved
+func editConflicts(files []string) error {
+	editor := appConfig.EditorCommand()
+	cmd := exec.Command("sh", append([]string{"-c", editor + ` "$@"`, "sh"}, files...)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+
+	var resolved []string
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil && !hasConflictMarkers(string(data)) {
+			resolved = append(resolved, file)
+		}
+	}
+	if len(resolved) == 0 {
+		return nil
+	}
+	if output, err := exec.Command("git", append([]string{"add", "--"}, resolved...)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to mark resolved files: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// hasConflictMarkers reports whether a file's contents still hold the
+// markers git writes around a conflict
+func hasConflictMarkers(contents string) bool {
+	for _, line := range strings.Split(contents, "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// runPostMergeHooksByName runs the post_merge hooks of the session with a
+// name, if there still is one
+func runPostMergeHooksByName(sm *state.Manager, sessionName string) error {
+	cores, err := sm.CoreSessions()
+	if err != nil {
+		return err
+	}
+	for _, core := range cores {
+		if core.Name == sessionName {
+			return sm.RunPostMergeHooks(core.ID)
+		}
+	}
+	return nil
+}
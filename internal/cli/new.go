@@ -2,69 +2,265 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/agent"
+	"github.com/jlaneve/cwt-cli/internal/daemon"
 	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
 )
 
 func newNewCmd() *cobra.Command {
+	var fromIssue, batchFile, priority, recipe, claudeArgs, model, agentName string
+	var copyUntracked []string
+	var parallel int
+	var ignoreLimits bool
+
 	cmd := &cobra.Command{
-		Use:   "new [session-name]",
+		Use:   "new [session-name] [task-description]",
 		Short: "Create a new session with isolated git worktree and tmux session",
 		Long: `Create a new CWT session with:
 - Isolated git worktree in .cwt/worktrees/[session-name]
 - New tmux session running Claude Code
 - Session metadata persistence
 
-If session-name is not provided, you will be prompted interactively.`,
-		Args: cobra.MaximumNArgs(1),
-		RunE: runNewCmd,
+If a task description is given, it is saved with the session and sent to
+Claude as its initial prompt.
+
+With --from-issue, the task is taken from a GitHub issue (requires the
+GitHub CLI) and the issue link is recorded as the session's source.
+
+With --batch, many sessions are created at once from a file, up to
+max_parallel (or --parallel) at a time, showing a progress table. A plain
+file has one task per line and names each session after its task; a .yaml
+file lists sessions with a name and a prompt:
+
+  - name: auth-feature
+    prompt: Add user authentication
+  - name: fix-flaky-tests
+    prompt: Find and fix the flaky tests in ./internal/...
+
+--priority sets the session's priority, high, normal or low, which orders
+'cwt list' and decides which waiting sessions the TUI alerts about. Change it
+later with 'cwt priority'.
+
+--recipe creates a session that runs end to end from a recipe (see 'cwt
+recipe'). The built-in deps-update starts Claude updating the dependencies
+and fixing what breaks, lets the session sit idle longer before the expiry
+policy archives it, and once Claude is done runs test_command and, if the
+tests pass, publishes the session as a pull request (a follow-up; the daemon
+runs it). The session is named after the recipe and the day unless a name is
+given, and a task given is added to the recipe's prompt.
+
+Files git doesn't check out, like .env or local config, are copied from
+this checkout into the new worktree when they match a glob of copy_untracked
+in the config, or one given with --copy-untracked. A matching directory is
+copied whole and symlinks, like a linked node_modules, as symlinks.
+
+Claude starts with the arguments of claude_args in the config, followed by
+those of --claude-args, quoted as in a shell, and --model. The session keeps
+them, so Claude is started the same way when it's resumed or restarted.
+
+--agent runs another coding agent than Claude: aider, codex or opencode
+(default: agent in the config). --claude-args and --model are then passed to
+it, after the args set for it in the agents section of the config. Its status
+is read from its transcripts and its tmux pane, as it has no hooks; aider
+gets the task typed in once it starts.
+
+Creating a session that would go over a limit configured in the limits
+section of the config fails; --ignore-limits goes over it (see 'cwt limits').
+
+If session-name is not provided, you will be prompted interactively.
+
+Examples:
+  cwt new                                      # Prompt for name and task
+  cwt new auth-feature                         # Start Claude without a task
+  cwt new auth-feature "Add user authentication" # Start Claude on a task
+  cwt new --from-issue 123                     # Session "issue-123" working on issue #123
+  cwt new hotfix "Fix the login crash" --priority high
+  cwt new --recipe deps-update                 # Update dependencies, PR when the tests pass
+  cwt new api --copy-untracked '.env*'         # Bring the local env files along
+  cwt new spike "Sketch a cache" --model opus --claude-args '--permission-mode plan'
+  cwt new parser "Port the parser" --agent codex
+  cwt new --batch tasks.txt                    # One session per line of tasks.txt
+  cwt new --batch tasks.yaml --parallel 2      # Named sessions, two at a time`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchFile != "" {
+				if len(args) > 0 || fromIssue != "" {
+					return fmt.Errorf("--batch takes its sessions from the file; don't pass a session name or --from-issue")
+				}
+				if priority != "" {
+					return fmt.Errorf("--priority sets one session's priority; set those of batch sessions with 'cwt priority'")
+				}
+				if recipe != "" {
+					return fmt.Errorf("--recipe creates one session; it can't be combined with --batch")
+				}
+				if len(copyUntracked) > 0 {
+					return fmt.Errorf("--copy-untracked is for one session; set copy_untracked in the config for batch sessions")
+				}
+				if claudeArgs != "" || model != "" {
+					return fmt.Errorf("--claude-args and --model are for one session; set claude_args in the config for batch sessions")
+				}
+				if agentName != "" {
+					return fmt.Errorf("--agent is for one session; set agent in the config for batch sessions")
+				}
+				return runNewBatchCmd(batchFile, parallel, ignoreLimits)
+			}
+			parsed, err := types.ParsePriority(priority)
+			if err != nil {
+				return err
+			}
+			var found *operations.Recipe
+			if recipe != "" {
+				if found, err = useRecipe(cmd, recipe); err != nil {
+					return err
+				}
+			}
+			launchArgs, err := claudeLaunchArgs(claudeArgs, model)
+			if err != nil {
+				return err
+			}
+			return runNewCmd(args, fromIssue, found, parsed, copyUntracked, agentName, launchArgs, ignoreLimits)
+		},
 	}
 
+	cmd.Flags().StringVar(&fromIssue, "from-issue", "", "Create the session from a GitHub issue number or URL")
+	cmd.Flags().StringVar(&batchFile, "batch", "", "Create a session for each task in a file (one per line, or YAML with names and prompts)")
+	cmd.Flags().IntVar(&parallel, "parallel", 0, "Sessions to create at once with --batch (default: max_parallel from config)")
+	cmd.Flags().StringVar(&priority, "priority", "", "Session priority: high, normal or low (default: normal)")
+	cmd.Flags().BoolVar(&ignoreLimits, "ignore-limits", false, "Create sessions even if that goes over the configured limits")
+	cmd.Flags().StringVar(&recipe, "recipe", "", "Create the session from a recipe (see 'cwt recipe')")
+	cmd.Flags().StringSliceVar(&copyUntracked, "copy-untracked", nil, "Copy files matching a glob, like .env, from this checkout into the worktree (repeatable)")
+	cmd.Flags().StringVar(&claudeArgs, "claude-args", "", "Extra arguments to start Claude with, quoted as in a shell, like '--permission-mode plan'")
+	cmd.Flags().StringVar(&model, "model", "", "Model the agent runs, like opus or sonnet")
+	cmd.Flags().StringVar(&agentName, "agent", "", fmt.Sprintf("Coding agent the session runs, one of %s (default: agent from config)", strings.Join(agent.Names(), ", ")))
+	cmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	cmd.RegisterFlagCompletionFunc("recipe", completeRecipes)
+	cmd.RegisterFlagCompletionFunc("agent", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return agent.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+
 	return cmd
 }
 
-func runNewCmd(cmd *cobra.Command, args []string) error {
+// claudeLaunchArgs returns the arguments of --claude-args and --model
+func claudeLaunchArgs(claudeArgs, model string) ([]string, error) {
+	args, err := utils.ShellSplit(claudeArgs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --claude-args: %w", err)
+	}
+	if model != "" {
+		args = append(args, "--model", model)
+	}
+	return args, nil
+}
+
+func runNewCmd(args []string, fromIssue string, recipe *operations.Recipe, priority types.Priority, copyUntracked []string, agentName string, claudeArgs []string, ignoreLimits bool) error {
 	sm, err := createStateManager()
 	if err != nil {
 		return err
 	}
 	defer sm.Close()
+	if ignoreLimits {
+		sm.SetLimits(state.Limits{})
+	}
+
+	// Get session name and optional task
+	var sessionName, task string
+	var opts state.CreateOptions
+	if fromIssue != "" {
+		issue, err := operations.FetchIssue(fromIssue)
+		if err != nil {
+			return err
+		}
+		sessionName = issue.SessionName()
+		task = issue.Task()
+		opts.Source = issue.URL
 
-	// Get session name
-	var sessionName string
-	if len(args) > 0 {
+		// Explicit arguments override the values derived from the issue
+		if len(args) > 0 {
+			sessionName = args[0]
+		}
+		if len(args) > 1 {
+			task = args[1]
+		}
+	} else if len(args) > 0 {
 		sessionName = args[0]
+		if len(args) > 1 {
+			task = args[1]
+		}
+	} else if recipe != nil {
+		sessionName = recipe.SessionName(time.Now())
 	} else {
-		sessionName, err = promptForSessionName()
+		reader := bufio.NewReader(os.Stdin)
+		sessionName, err = promptForSessionName(reader)
 		if err != nil {
 			return err
 		}
+		task, err = promptForTask(reader)
+		if err != nil {
+			return err
+		}
+	}
+
+	opts.Task = task
+	if recipe != nil {
+		if err := applyRecipe(&opts, *recipe, sessionName); err != nil {
+			return err
+		}
 	}
 
 	// Create session using operations layer
 	fmt.Printf("Creating session '%s'...\n", sessionName)
 
+	opts.Priority = priority
+	opts.CopyUntracked = copyUntracked
+	opts.Agent = agentName
+	opts.ClaudeArgs = claudeArgs
+	ctx, stop := interruptContext()
+	defer stop()
+	progress := newProgressLine(os.Stdout, isatty.IsTerminal(os.Stdout.Fd()))
+	opts.Progress = progress.Update
+
 	sessionOps := operations.NewSessionOperations(sm)
-	if err := sessionOps.CreateSession(sessionName); err != nil {
+	err = sessionOps.CreateSessionContext(ctx, sessionName, opts)
+	progress.Stop()
+	stop()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("cancelled creating session '%s'; its worktree and branch were removed", sessionName)
+		}
+		limitHint(err)
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
 	// Success message
 	fmt.Printf("✅ Session '%s' created successfully!\n", sessionName)
+	warnTokenBudget(sm)
+	if recipe != nil {
+		if _, err := daemon.Connect(sm.GetDataDir()); errors.Is(err, daemon.ErrNotRunning) {
+			fmt.Println("⚠️  The daemon runs the tests and publishes the session, and it isn't running; start it with: cwt daemon")
+		}
+	}
 
 	// Attach to the newly created session
+	sm.RecordEventByName(sessionName, types.EventAttached, "Attached", nil)
 	tmuxSessionName := fmt.Sprintf("cwt-%s", sessionName)
 	return operations.AttachToTmuxSession(sessionName, tmuxSessionName)
 }
 
-func promptForSessionName() (string, error) {
-	reader := bufio.NewReader(os.Stdin)
-
+func promptForSessionName(reader *bufio.Reader) (string, error) {
 	for {
 		fmt.Print("Enter session name: ")
 		input, err := reader.ReadString('\n')
@@ -81,3 +277,13 @@ func promptForSessionName() (string, error) {
 		return sessionName, nil
 	}
 }
+
+func promptForTask(reader *bufio.Reader) (string, error) {
+	fmt.Print("Enter task description (optional): ")
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(input), nil
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/daemon"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// plainShellWord matches arguments that need no quoting in a shell command
+var plainShellWord = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+func newOnCmd() *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "on <session-name> [complete -- <command>...]",
+		Short: "Run a command in a session's worktree when it completes",
+		Long: `Register a follow-up: a command run once in a session's worktree when
+Claude reports the session's task complete, like running its tests.
+
+The daemon runs the command with sh and records its exit status and the end
+of its output with the session, where 'cwt list', 'cwt show' and the TUI
+show it. Registering a follow-up replaces the session's previous one. A
+session that is already complete runs it the next time it completes.
+
+Without a command, shows the session's follow-up and its result.
+
+Examples:
+  cwt on feature complete -- make test             # Run the tests when Claude is done
+  cwt on feature complete -- 'go vet ./... && go test ./...'
+  cwt on feature                                   # Show the follow-up and its result
+  cwt on feature --clear                           # Remove the follow-up`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			switch {
+			case len(args) == 0:
+				return fmt.Errorf("requires a session name")
+			case dash == -1 && len(args) > 1:
+				return fmt.Errorf("put the command after --, like: cwt on %s complete -- make test", args[0])
+			case dash != -1 && dash != 2:
+				return fmt.Errorf("expected: cwt on <session-name> complete -- <command>")
+			case dash != -1 && len(args) == dash:
+				return fmt.Errorf("no command given after --")
+			case dash != -1 && clear:
+				return fmt.Errorf("--clear can't be combined with a command")
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return completeSessionNames(cmd, args, toComplete)
+			case 1:
+				return []string{string(types.FollowUpOnComplete)}, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dash := cmd.ArgsLenAtDash(); dash != -1 {
+				return runSetFollowUp(args[0], types.FollowUpTrigger(args[1]), shellJoin(args[dash:]))
+			}
+			return runShowFollowUp(args[0], clear)
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "Remove the session's follow-up")
+
+	return cmd
+}
+
+func runSetFollowUp(name string, on types.FollowUpTrigger, command string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	_, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+	if err := sm.SetFollowUp(sessionID, on, command); err != nil {
+		return fmt.Errorf("failed to register follow-up: %w", err)
+	}
+
+	fmt.Printf("🔁 '%s' runs `%s` when it is %s\n", name, command, on)
+	if _, err := daemon.Connect(dataDir); errors.Is(err, daemon.ErrNotRunning) {
+		fmt.Println("⚠️  The daemon runs follow-ups, and it isn't running; start it with: cwt daemon")
+	}
+	return nil
+}
+
+func runShowFollowUp(name string, clear bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	session, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+
+	followUp := session.Core.FollowUp
+	if followUp == nil {
+		fmt.Printf("'%s' has no follow-up. Add one with: cwt on %s complete -- <command>\n", name, name)
+		return nil
+	}
+
+	if clear {
+		if err := sm.ClearFollowUp(sessionID); err != nil {
+			return fmt.Errorf("failed to remove follow-up: %w", err)
+		}
+		fmt.Printf("🗑️  Removed the follow-up of '%s'\n", name)
+		return nil
+	}
+
+	printFollowUp(*session)
+	return nil
+}
+
+// printFollowUp prints a session's follow-up, its result and the end of its
+// output
+func printFollowUp(session types.Session) {
+	formatter := operations.NewStatusFormat()
+	followUp := *session.Core.FollowUp
+	fmt.Printf("🔁 On %s: %s\n", followUp.On, followUp.Command)
+	fmt.Printf("   Status: %s\n", formatter.FormatFollowUp(followUp))
+	if followUp.Result != nil {
+		fmt.Printf("   Finished: %s\n", followUp.Result.FinishedAt.Format("2006-01-02 15:04:05"))
+	}
+	if coverage := formatter.FormatCoverage(session); coverage != "" {
+		fmt.Printf("   Coverage: %s\n", coverage)
+	}
+	for _, line := range followUp.Tail(10) {
+		fmt.Printf("   │ %s\n", line)
+	}
+}
+
+// shellJoin joins command arguments into a command for sh, quoting those
+// that need it. A single argument is taken as a shell command as it is, so
+// 'make test && make lint' keeps its meaning.
+func shellJoin(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	words := make([]string, len(args))
+	for i, arg := range args {
+		if plainShellWord.MatchString(arg) {
+			words[i] = arg
+		} else {
+			words[i] = utils.ShellQuote(arg)
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/browser"
+	"github.com/jlaneve/cwt-cli/internal/clients/forge"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// openShellInit defines cwtcd, which changes the shell's directory to a
+// session's worktree; a child process can't change its parent's directory
+const openShellInit = `# cwt: 'cwtcd <session>' changes to a session's worktree
+cwtcd() {
+  local dir
+  dir="$(cwt open --dir "$@")" && cd "$dir"
+}
+`
+
+// openTarget is what 'cwt open' opens of a session
+type openTarget int
+
+const (
+	openEditor openTarget = iota
+	openPullRequest
+	openDir
+	openBranchURL
+)
+
+// newOpenCmd creates the 'cwt open' command
+func newOpenCmd() *cobra.Command {
+	var editor, pr, dir, branchURL, shellInit bool
+
+	cmd := &cobra.Command{
+		Use:   "open [session-name]",
+		Short: "Open a session's worktree in your editor, or its pull request or branch in the browser",
+		Long: `Jump to what belongs to a session: its worktree in your editor, the pull
+request it was published to, or its branch on the forge hosting origin.
+
+By default the worktree is opened in the editor set with ide in the config,
+like code or cursor, falling back to editor, $VISUAL and $EDITOR. Set it in
+~/.config/cwt/config.yaml to keep it to yourself.
+
+--dir prints the worktree's path. A program can't change the directory of
+the shell that runs it, so to cd there add the cwtcd function --shell-init
+prints to your shell's startup file (bash or zsh):
+
+  eval "$(cwt open --shell-init)"
+
+Browsers are opened with $BROWSER when set, or the system's default.
+
+If session-name is not provided, you will be prompted to select
+from available sessions.
+
+Examples:
+  cwt open my-session               # Open the worktree in your editor
+  cwt open my-session --pr          # Open its pull request
+  cwt open my-session --branch-url  # Open its branch on GitHub, GitLab or Bitbucket
+  cwt open my-session --dir         # Print the worktree's path
+  cwtcd my-session                  # cd into the worktree, after --shell-init`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if shellInit {
+				if len(args) > 0 {
+					return fmt.Errorf("--shell-init prints the shell helper; it takes no session")
+				}
+				fmt.Print(openShellInit)
+				return nil
+			}
+
+			target := openEditor
+			switch {
+			case pr:
+				target = openPullRequest
+			case dir:
+				target = openDir
+			case branchURL:
+				target = openBranchURL
+			}
+			return runOpenCmd(args, target)
+		},
+	}
+
+	cmd.Flags().BoolVar(&editor, "editor", false, "Open the worktree in your editor (the default)")
+	cmd.Flags().BoolVar(&pr, "pr", false, "Open the session's pull request in the browser")
+	cmd.Flags().BoolVar(&dir, "dir", false, "Print the worktree's path")
+	cmd.Flags().BoolVar(&branchURL, "branch-url", false, "Open the session's branch on the forge in the browser")
+	cmd.Flags().BoolVar(&shellInit, "shell-init", false, "Print the cwtcd shell function, which cds into a worktree")
+	cmd.MarkFlagsMutuallyExclusive("editor", "pr", "dir", "branch-url", "shell-init")
+
+	return cmd
+}
+
+func runOpenCmd(args []string, target openTarget) error {
+	if target == openDir && len(args) == 0 {
+		// The selector would end up in the path a shell captures
+		return fmt.Errorf("--dir needs a session name")
+	}
+
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	sessions, err := sm.DeriveFreshSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no sessions found")
+	}
+
+	var session *types.Session
+	if len(args) > 0 {
+		for i := range sessions {
+			if sessions[i].Core.Name == args[0] {
+				session = &sessions[i]
+				break
+			}
+		}
+		if session == nil {
+			return fmt.Errorf("session '%s' not found", args[0])
+		}
+	} else {
+		session, err = SelectSession(sessions, WithTitle("Select a session to open:"))
+		if err != nil {
+			return fmt.Errorf("failed to select session: %w", err)
+		}
+		if session == nil {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	worktree, err := filepath.Abs(session.Core.WorktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve worktree path: %w", err)
+	}
+
+	switch target {
+	case openDir:
+		fmt.Println(worktree)
+		return nil
+	case openPullRequest:
+		url, err := sessionPullRequestURL(*session)
+		if err != nil {
+			return err
+		}
+		return openURL(url)
+	case openBranchURL:
+		url, err := forge.BranchURL(".", appConfig.Forge, session.BranchName())
+		if err != nil {
+			return fmt.Errorf("failed to find the branch's page: %w", err)
+		}
+		return openURL(url)
+	}
+
+	if _, err := os.Stat(worktree); err != nil {
+		return fmt.Errorf("worktree of session '%s' is missing; repair it with: cwt repair %s", session.Core.Name, session.Core.Name)
+	}
+	editor := appConfig.IDECommand()
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, "sh", worktree)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	return nil
+}
+
+// sessionPullRequestURL returns the URL of a session's pull request: the
+// one it was published to, or else the open one the forge has for its branch
+func sessionPullRequestURL(session types.Session) (string, error) {
+	if pr := session.Core.PullRequest; pr != nil {
+		return pr.URL, nil
+	}
+
+	// The repository rather than the worktree, which may be missing
+	host, err := forge.ForRepository(".", appConfig.Forge)
+	if err == nil {
+		err = host.Available()
+	}
+	if err != nil {
+		return "", fmt.Errorf("can't look up the pull request: %w", err)
+	}
+	url, err := host.PullRequestURL(".", session.BranchName())
+	if err != nil || url == "" {
+		return "", fmt.Errorf("session '%s' has no pull request; open one with: cwt publish %s --pr", session.Core.Name, session.Core.Name)
+	}
+	return url, nil
+}
+
+// openURL opens a URL in the browser, printing it so it can be followed
+// where no browser can be opened
+func openURL(url string) error {
+	fmt.Printf("Opening %s\n", url)
+	if err := browser.Open(url); err != nil {
+		return fmt.Errorf("failed to open the browser: %w", err)
+	}
+	return nil
+}
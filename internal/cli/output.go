@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jlaneve/cwt-cli/internal/profile"
+)
+
+// writeJSON writes v to stdout as indented JSON for scripting (e.g. piping into jq)
+func writeJSON(v interface{}) error {
+	defer profiler.Track(profile.PhaseRender)()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newPauseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause <session-name>...",
+		Short: "Stop a session's tmux session and Claude, keeping its worktree",
+		Long: `Pause CWT sessions to free the resources their Claude processes use.
+
+The tmux session and Claude are stopped; the worktree, branch and session
+metadata are kept. The Claude conversation ID is saved with the session, so
+'cwt resume' continues the same conversation, even weeks later.
+
+Paused sessions are left alone by 'cwt cleanup' and by session expiry.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPauseCmd(args)
+		},
+	}
+
+	return cmd
+}
+
+func runPauseCmd(names []string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	sessionOps := operations.NewSessionOperations(sm)
+
+	var failed int
+	for _, name := range names {
+		_, sessionID, err := sessionOps.FindSessionByName(name)
+		if err == nil {
+			err = sm.PauseSession(sessionID)
+		}
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("💤 Paused session '%s'\n", name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to pause %d of %d sessions", failed, len(names))
+	}
+	fmt.Println("Resume with: cwt resume <session-name>")
+	return nil
+}
+
+func newResumeCmd() *cobra.Command {
+	var attach bool
+
+	cmd := &cobra.Command{
+		Use:   "resume <session-name>...",
+		Short: "Restart a paused session, continuing its Claude conversation",
+		Long: `Resume paused CWT sessions.
+
+A new tmux session is started in each session's worktree running
+'claude -r <conversation-id>' with the conversation saved when it was paused.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeManySessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return runResumeCmd(args[0], attach)
+			}
+			if attach {
+				return fmt.Errorf("--attach attaches to one session; resume it on its own")
+			}
+			var failed int
+			for _, name := range args {
+				if err := runResumeCmd(name, attach); err != nil {
+					fmt.Printf("❌ %v\n", err)
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to resume %d of %d sessions", failed, len(args))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&attach, "attach", "a", false, "Attach to the session once it is resumed")
+
+	return cmd
+}
+
+func runResumeCmd(name string, attach bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	session, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.ResumeSession(sessionID); err != nil {
+		return fmt.Errorf("failed to resume session '%s': %w", name, err)
+	}
+
+	if session.Core.ClaudeSessionID != "" {
+		fmt.Printf("▶️  Resumed session '%s' (conversation %s)\n", name, session.Core.ClaudeSessionID)
+	} else {
+		fmt.Printf("▶️  Resumed session '%s' with a new conversation\n", name)
+	}
+
+	if attach {
+		sm.RecordEvent(sessionID, types.EventAttached, "Attached", nil)
+		return operations.AttachToTmuxSession(name, session.Core.TmuxSession)
+	}
+	return nil
+}
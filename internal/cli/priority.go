@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newPriorityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "priority <session-name> [high|normal|low]",
+		Short: "Show or change a session's priority",
+		Long: `Show or change the priority of a session, for triage when many run at once.
+
+'cwt list' and the TUI's priority sort put high-priority sessions first and
+low-priority ones last, and the TUI alerts about high-priority sessions
+waiting for input. Sessions are normal priority unless created with
+'cwt new --priority' or changed here.
+
+Examples:
+  cwt priority auth-feature           # Show its priority
+  cwt priority auth-feature high      # Put it first
+  cwt priority spike low              # Put it last`,
+		Args: cobra.RangeArgs(1, 2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return completeSessionNames(cmd, args, toComplete)
+			case 1:
+				return completePriorities(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return runShowPriority(args[0])
+			}
+			priority, err := types.ParsePriority(args[1])
+			if err != nil {
+				return err
+			}
+			return runSetPriority(args[0], priority)
+		},
+	}
+
+	return cmd
+}
+
+// completePriorities completes priority names
+func completePriorities(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := make([]string, len(types.Priorities))
+	for i, priority := range types.Priorities {
+		names[i] = string(priority)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func runShowPriority(name string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	session, _, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("'%s' is %s priority\n", name, operations.NewStatusFormat().FormatPriority(session.Core.SessionPriority()))
+	return nil
+}
+
+func runSetPriority(name string, priority types.Priority) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	_, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+	if err := sm.SetSessionPriority(sessionID, priority); err != nil {
+		return fmt.Errorf("failed to set priority of '%s': %w", name, err)
+	}
+
+	fmt.Printf("✅ '%s' is now %s priority\n", name, operations.NewStatusFormat().FormatPriority(priority))
+	return nil
+}
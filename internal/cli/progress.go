@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// progressWidth is how much of a progress step fits on its line
+const progressWidth = 72
+
+// spinnerFrames animate the progress line while a step runs
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// interruptContext returns a context cancelled by Ctrl+C, so a long
+// operation can roll back instead of the process just exiting. Calling stop
+// restores the default Ctrl+C handling.
+func interruptContext() (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// progressLine shows the current step of a long operation. On a terminal it
+// is one line redrawn with a spinner; otherwise each step is printed once,
+// leaving out the meters git redraws as it goes.
+type progressLine struct {
+	out      io.Writer
+	terminal bool
+
+	mu    sync.Mutex
+	step  string
+	frame int
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// newProgressLine starts showing progress on out
+func newProgressLine(out io.Writer, terminal bool) *progressLine {
+	p := &progressLine{out: out, terminal: terminal}
+	if terminal {
+		p.stop = make(chan struct{})
+		p.done = make(chan struct{})
+		go p.spin()
+	}
+	return p
+}
+
+// spin redraws the line until Stop is called
+func (p *progressLine) spin() {
+	defer close(p.done)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame = (p.frame + 1) % len(spinnerFrames)
+			p.draw()
+			p.mu.Unlock()
+		}
+	}
+}
+
+// draw redraws the line; p.mu must be held
+func (p *progressLine) draw() {
+	if p.step == "" {
+		return
+	}
+	step := runewidth.Truncate(p.step, progressWidth, "…")
+	fmt.Fprintf(p.out, "\r\033[K%s %s (Ctrl+C to cancel)", spinnerFrames[p.frame], step)
+}
+
+// Update shows a new step
+func (p *progressLine) Update(step string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.terminal {
+		p.step = step
+		p.draw()
+		return
+	}
+	if isProgressMeter(step) || step == p.step {
+		return
+	}
+	p.step = step
+	fmt.Fprintf(p.out, "   %s\n", step)
+}
+
+// Stop stops the spinner and clears its line
+func (p *progressLine) Stop() {
+	if !p.terminal {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	fmt.Fprint(p.out, "\r\033[K")
+}
+
+// isProgressMeter reports whether a line is an intermediate state of a
+// meter git redraws, like "Updating files:  45% (450/1000)"
+func isProgressMeter(line string) bool {
+	return strings.Contains(line, "%") && !strings.HasSuffix(line, "done.")
+}
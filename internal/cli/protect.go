@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+)
+
+// printConfirmationToken shows how to run a dry-run operation in protected mode
+func printConfirmationToken(out io.Writer, op operations.ProtectedOperation, command string) {
+	if !appConfig.Protected {
+		return
+	}
+	fmt.Fprintln(out, "\n🔒 Protected mode is on. To run this exact operation, use:")
+	fmt.Fprintf(out, "  %s --confirm %s\n", command, op.Token())
+}
+
+// confirmProtected reports whether a protected operation may run. It returns
+// false with no error when protected mode is off, so the command asks for
+// its usual confirmation instead.
+func confirmProtected(op operations.ProtectedOperation, token string, dryRunCommand string) (bool, error) {
+	if !appConfig.Protected {
+		return false, nil
+	}
+
+	if token != "" {
+		if token != op.Token() {
+			return false, fmt.Errorf("confirmation token does not match; the repository may have changed since the dry run. Run '%s' for a new token", dryRunCommand)
+		}
+		return true, nil
+	}
+
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("protected mode: run '%s' and pass the token it prints with --confirm", dryRunCommand)
+	}
+	if err := readConfirmationPhrase(os.Stdin, op.Phrase); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readConfirmationPhrase asks the user to type phrase exactly
+func readConfirmationPhrase(in io.Reader, phrase string) error {
+	fmt.Printf("🔒 Protected mode. Type '%s' to continue: ", phrase)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return fmt.Errorf("confirmation cancelled")
+	}
+	if strings.TrimSpace(answer) != phrase {
+		return fmt.Errorf("confirmation phrase did not match; nothing was changed")
+	}
+	return nil
+}
+
+func stdinIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd())
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+)
+
+func TestConfirmProtected(t *testing.T) {
+	saved := appConfig
+	defer func() { appConfig = saved }()
+
+	op := operations.ProtectedOperation{Phrase: "switch to main"}
+
+	appConfig = config.Default()
+	if confirmed, err := confirmProtected(op, "", "cwt switch --dry-run"); confirmed || err != nil {
+		t.Errorf("without protected mode: confirmed=%v err=%v, want the usual prompt", confirmed, err)
+	}
+
+	appConfig.Protected = true
+	if confirmed, err := confirmProtected(op, op.Token(), "cwt switch --dry-run"); !confirmed || err != nil {
+		t.Errorf("matching token: confirmed=%v err=%v, want confirmed", confirmed, err)
+	}
+	if _, err := confirmProtected(op, "0123456789ab", "cwt switch --dry-run"); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("wrong token should be rejected, got %v", err)
+	}
+	// Tests don't run on a terminal, so there is no one to type the phrase
+	if _, err := confirmProtected(op, "", "cwt switch --dry-run"); err == nil || !strings.Contains(err.Error(), "--confirm") {
+		t.Errorf("missing token without a terminal should explain --confirm, got %v", err)
+	}
+}
+
+func TestReadConfirmationPhrase(t *testing.T) {
+	if err := readConfirmationPhrase(strings.NewReader("merge fix into main\n"), "merge fix into main"); err != nil {
+		t.Errorf("exact phrase rejected: %v", err)
+	}
+	if err := readConfirmationPhrase(strings.NewReader("y\n"), "merge fix into main"); err == nil {
+		t.Error("a plain 'y' should not confirm a protected operation")
+	}
+	if err := readConfirmationPhrase(strings.NewReader(""), "merge fix into main"); err == nil {
+		t.Error("no input should not confirm")
+	}
+}
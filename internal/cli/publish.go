@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -10,54 +11,117 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/forge"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/operations"
 	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
+const (
+	maxPullRequestTitle   = 72   // Characters of a generated pull request title
+	maxPullRequestSummary = 3000 // Characters of Claude's final reply quoted in a pull request
+	maxPullRequestFiles   = 30   // Files listed in a pull request's changes
+)
+
 // newPublishCmd creates the 'cwt publish' command
 func newPublishCmd() *cobra.Command {
-	var draft bool
-	var pr bool
-	var localOnly bool
-	var message string
+	var opts publishOptions
+	var jsonOutput bool
 
 	cmd := &cobra.Command{
 		Use:   "publish <session-name>",
 		Short: "Commit all session changes and publish the branch",
 		Long: `Commit all session changes and publish the branch for collaboration or backup.
 
+With --pr or --draft, a pull request is opened on the forge hosting origin,
+told from its URL or set with forge in the config: GitHub through the gh
+CLI, GitLab through the glab CLI, or Bitbucket Cloud through its API with
+BITBUCKET_TOKEN, or BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD. Its
+title comes from the session's task, and its body from Claude's conversation in the worktree: its final
+summary, the files it edited, the tools it used, and the result of the
+session's follow-up. --edit opens them in your editor first; the first line
+is the title, the rest the body.
+
+With --json, the output is a document describing what was published: the
+commit created, whether the branch was pushed, and the pull request URL.
+
 Examples:
   cwt publish my-session                # Commit all changes + push branch
-  cwt publish my-session --draft        # Push as draft PR (if GitHub CLI available)
+  cwt publish my-session --draft        # Push and open a draft PR
   cwt publish my-session --pr           # Create PR automatically
+  cwt publish my-session --pr --edit    # Review the PR title and body in $EDITOR first
   cwt publish my-session --local        # Commit only, no push
-  cwt publish my-session -m "Custom commit message"  # Use custom commit message`,
-		Args: cobra.ExactArgs(1),
+  cwt publish my-session -m "Custom commit message"  # Use custom commit message
+  cwt publish my-session --json         # Machine-readable result`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Edit && !opts.PR && !opts.Draft {
+				return fmt.Errorf("--edit edits the pull request; use it with --pr or --draft")
+			}
+			if opts.Edit && opts.LocalOnly {
+				return fmt.Errorf("--edit can't be combined with --local, which creates no pull request")
+			}
+
 			sm, err := createStateManager()
 			if err != nil {
 				return err
 			}
 			defer sm.Close()
 
+			var out io.Writer = os.Stdout
+			if jsonOutput {
+				out = io.Discard
+			}
+
 			sessionName := args[0]
-			return publishSession(sm, sessionName, message, draft, pr, localOnly)
+			result, err := publishSession(sm, sessionName, opts, out)
+			if err == nil {
+				// Let running TUIs pick up the new commit immediately
+				sm.NotifyRefresh(sessionName, "publish")
+			}
+			if jsonOutput {
+				if err != nil {
+					result.Error = err.Error()
+				}
+				if jsonErr := writeJSON(result); jsonErr != nil {
+					return jsonErr
+				}
+			}
+			return err
 		},
 	}
 
-	cmd.Flags().BoolVar(&draft, "draft", false, "Push as draft PR (requires GitHub CLI)")
-	cmd.Flags().BoolVar(&pr, "pr", false, "Create PR automatically (requires GitHub CLI)")
-	cmd.Flags().BoolVar(&localOnly, "local", false, "Commit only, no push")
-	cmd.Flags().StringVarP(&message, "message", "m", "", "Custom commit message")
+	cmd.Flags().BoolVar(&opts.Draft, "draft", false, "Push and open a draft pull request on the forge hosting origin")
+	cmd.Flags().BoolVar(&opts.PR, "pr", false, "Push and open a pull request on the forge hosting origin (GitHub, GitLab or Bitbucket)")
+	cmd.Flags().BoolVar(&opts.Edit, "edit", false, "Edit the pull request's title and body in $EDITOR before creating it")
+	cmd.Flags().BoolVar(&opts.LocalOnly, "local", false, "Commit only, no push")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Custom commit message")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the result of publishing as JSON")
 
 	return cmd
 }
 
-// publishSession commits and publishes a session's changes
-func publishSession(sm *state.Manager, sessionName, customMessage string, draft, pr, localOnly bool) error {
+// publishOptions are the settings of a 'cwt publish'
+type publishOptions struct {
+	Message   string // Commit message; generated when empty
+	Draft     bool
+	PR        bool
+	Edit      bool // Edit the pull request's title and body before creating it
+	LocalOnly bool
+}
+
+// publishSession commits and publishes a session's changes, printing its
+// progress to out. The result describes as much as was done, even when an
+// error stopped it.
+func publishSession(sm *state.Manager, sessionName string, opts publishOptions, out io.Writer) (types.PublishResultOutput, error) {
+	result := types.NewPublishResultOutput(sessionName, sessionName)
+
 	sessions, err := sm.DeriveFreshSessions()
 	if err != nil {
-		return fmt.Errorf("failed to load sessions: %w", err)
+		return result, fmt.Errorf("failed to load sessions: %w", err)
 	}
 
 	// Find the session
@@ -70,76 +134,137 @@ func publishSession(sm *state.Manager, sessionName, customMessage string, draft,
 	}
 
 	if targetSession == nil {
-		return fmt.Errorf("session '%s' not found", sessionName)
+		return result, fmt.Errorf("session '%s' not found", sessionName)
+	}
+	sessionBranch := targetSession.BranchName()
+	result.Branch = sessionBranch
+	if err := operations.CheckWorktree(*targetSession); err != nil {
+		return result, err
+	}
+	if err := operations.CheckConflicts(*targetSession); err != nil {
+		return result, err
 	}
 
 	if !targetSession.IsAlive {
-		fmt.Printf("Warning: Session '%s' is not currently active\n", sessionName)
+		warning := fmt.Sprintf("Session '%s' is not currently active", sessionName)
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+		result.Warnings = append(result.Warnings, warning)
 	}
 
 	worktreePath := targetSession.Core.WorktreePath
-	sessionBranch := fmt.Sprintf("cwt-%s", sessionName)
+
+	// Record the commit, the push and the pull request opened, once back
+	// where the data directory is
+	var pushedCommit string
+	defer func() {
+		if result.Commit != nil {
+			sm.RecordEvent(targetSession.Core.ID, types.EventCommitted, result.Commit.Subject, nil)
+		}
+		if result.PRURL != "" {
+			if err := sm.SetPullRequest(targetSession.Core.ID, types.NewPullRequest(result.PRURL)); err != nil {
+				fmt.Fprintf(out, "Warning: failed to record the pull request: %v\n", err)
+			}
+		}
+		if result.Pushed {
+			recordPublish(sm, *targetSession, pushedCommit, result.PRURL, out)
+		}
+	}()
 
 	// Switch to the session's worktree directory
 	originalDir, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return result, fmt.Errorf("failed to get current directory: %w", err)
 	}
 	defer os.Chdir(originalDir)
 
 	if err := os.Chdir(worktreePath); err != nil {
-		return fmt.Errorf("failed to change to worktree directory: %w", err)
+		return result, fmt.Errorf("failed to change to worktree directory: %w", err)
+	}
+
+	// push pushes the branch, recording it in the session's timeline
+	push := func() error {
+		if err := pushBranch(out, *targetSession, opts, &result); err != nil {
+			return err
+		}
+		if result.Pushed {
+			pushedCommit = headCommit()
+		}
+		return nil
 	}
 
 	// Check if there are changes to commit
 	if !hasChangesToCommit() {
-		fmt.Printf("No changes to commit in session '%s'\n", sessionName)
-		if !localOnly {
+		fmt.Fprintf(out, "No changes to commit in session '%s'\n", sessionName)
+		if !opts.LocalOnly {
 			// Still try to push in case there are unpushed commits
-			return pushBranch(sessionBranch, draft, pr)
+			return result, push()
 		}
-		return nil
+		return result, nil
 	}
 
 	// Generate commit message
-	commitMessage := customMessage
+	commitMessage := opts.Message
 	if commitMessage == "" {
 		commitMessage = generateCommitMessage(sessionName, worktreePath)
 	}
 
 	// Stage and commit changes
-	if err := stageAndCommit(commitMessage); err != nil {
-		return fmt.Errorf("failed to commit changes: %w", err)
+	if err := stageAndCommit(out, commitMessage); err != nil {
+		return result, fmt.Errorf("failed to commit changes: %w", err)
 	}
+	result.Commit = &types.CommitOutput{Hash: headCommit(), Subject: commitSubject(commitMessage)}
+	result.Files = headCommitFiles()
 
-	fmt.Printf("Committed changes in session '%s'\n", sessionName)
+	fmt.Fprintf(out, "Committed changes in session '%s'\n", sessionName)
 
 	// Push if not local-only
-	if !localOnly {
-		if err := pushBranch(sessionBranch, draft, pr); err != nil {
-			return fmt.Errorf("failed to push branch: %w", err)
+	if !opts.LocalOnly {
+		if err := push(); err != nil {
+			return result, fmt.Errorf("failed to push branch: %w", err)
 		}
 	}
 
-	return nil
+	return result, nil
+}
+
+// recordPublish adds a push to the session's publish history and timeline
+func recordPublish(sm *state.Manager, session types.Session, commit, prURL string, out io.Writer) {
+	if prURL == "" && session.Core.PullRequest != nil {
+		prURL = session.Core.PullRequest.URL
+	}
+	publish, err := sm.RecordPublish(session.Core.ID, commit, prURL)
+	if err != nil {
+		fmt.Fprintf(out, "Warning: failed to record the push: %v\n", err)
+		return
+	}
+	sm.RecordEvent(session.Core.ID, types.EventPublished,
+		fmt.Sprintf("Pushed %s as revision %d (%s)", session.BranchName(), publish.Revision, shortCommit(commit)), nil)
+}
+
+// commitSubject returns the first line of a commit message
+func commitSubject(message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return subject
 }
 
 // hasChangesToCommit checks if there are changes to commit
 func hasChangesToCommit() bool {
+	pathspec := git.WorkPathspec(".")
+
 	// Check for staged changes
-	cmd := exec.Command("git", "diff", "--cached", "--quiet")
+	cmd := exec.Command("git", append([]string{"diff", "--cached", "--quiet"}, pathspec...)...)
 	if cmd.Run() != nil {
 		return true // Has staged changes
 	}
 
 	// Check for unstaged changes
-	cmd = exec.Command("git", "diff", "--quiet")
+	cmd = exec.Command("git", append([]string{"diff", "--quiet"}, pathspec...)...)
 	if cmd.Run() != nil {
 		return true // Has unstaged changes
 	}
 
 	// Check for untracked files
-	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	cmd = exec.Command("git", append([]string{"ls-files", "--others", "--exclude-standard"}, pathspec...)...)
 	output, err := cmd.Output()
 	if err == nil && len(strings.TrimSpace(string(output))) > 0 {
 		return true // Has untracked files
@@ -250,16 +375,16 @@ func analyzeChanges() string {
 }
 
 // stageAndCommit stages all changes and commits them
-func stageAndCommit(message string) error {
-	// Add all changes (including untracked files)
-	cmd := exec.Command("git", "add", ".")
+func stageAndCommit(out io.Writer, message string) error {
+	// Add all changes (including untracked files) but cwt's own
+	cmd := exec.Command("git", append([]string{"add"}, git.WorkPathspec(".")...)...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
 
 	// Commit changes
 	cmd = exec.Command("git", "commit", "-m", message)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = out
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
@@ -268,34 +393,51 @@ func stageAndCommit(message string) error {
 	return nil
 }
 
-// pushBranch pushes the branch and optionally creates PR
-func pushBranch(branch string, draft, pr bool) error {
+// pushBranch pushes a session's branch and optionally creates PR, recording
+// what was done in result
+func pushBranch(out io.Writer, session types.Session, opts publishOptions, result *types.PublishResultOutput) error {
+	branch := session.BranchName()
+	draft, pr := opts.Draft, opts.PR
+
 	// Check if remote exists
 	if !hasRemote() {
-		fmt.Println("No remote repository configured, skipping push")
+		fmt.Fprintln(out, "No remote repository configured, skipping push")
+		result.Warnings = append(result.Warnings, "No remote repository configured, so the branch was not pushed")
 		return nil
 	}
 
 	// Push branch with upstream tracking
-	fmt.Printf("Pushing branch '%s'...\n", branch)
+	fmt.Fprintf(out, "Pushing branch '%s'...\n", branch)
 	cmd := exec.Command("git", "push", "-u", "origin", branch)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = out
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to push branch: %w", err)
 	}
+	result.Pushed = true
+	result.Remote = "origin"
 
-	fmt.Printf("Successfully pushed branch '%s'\n", branch)
+	fmt.Fprintf(out, "Successfully pushed branch '%s'\n", branch)
 
-	// Create PR if requested and GitHub CLI is available
-	if (draft || pr) && hasGitHubCLI() {
-		return createPullRequest(branch, draft)
-	} else if draft || pr {
-		fmt.Println("GitHub CLI not found, skipping PR creation")
-		fmt.Printf("You can manually create a PR for branch '%s'\n", branch)
+	if !draft && !pr {
+		return nil
 	}
 
-	return nil
+	// Open the pull request on the forge hosting origin, if it can be reached
+	host, err := forge.ForRepository(session.Core.WorktreePath, appConfig.Forge)
+	if err == nil {
+		err = host.Available()
+	}
+	if err != nil {
+		fmt.Fprintf(out, "Skipping pull request: %v\n", err)
+		fmt.Fprintf(out, "You can manually create a PR for branch '%s'\n", branch)
+		result.Warnings = append(result.Warnings, fmt.Sprintf("No pull request was created: %v", err))
+		return nil
+	}
+
+	url, err := createPullRequest(out, host, session, draft, opts.Edit)
+	result.PRURL = url
+	return err
 }
 
 // hasRemote checks if a remote repository is configured
@@ -305,42 +447,164 @@ func hasRemote() bool {
 	return err == nil && len(strings.TrimSpace(string(output))) > 0
 }
 
-// hasGitHubCLI checks if GitHub CLI is available
-func hasGitHubCLI() bool {
-	cmd := exec.Command("gh", "--version")
-	return cmd.Run() == nil
+// createPullRequest opens a pull request for a session's branch on a
+// forge, returning its URL. With edit, its title and body are opened in the
+// editor first.
+func createPullRequest(out io.Writer, host forge.Forge, session types.Session, draft, edit bool) (string, error) {
+	branch := session.BranchName()
+	transcript := latestTranscript(session.Core.WorktreePath)
+	title := pullRequestTitle(session, transcript)
+	body := pullRequestBody(session, transcript, time.Now())
+	if edit {
+		var err error
+		if title, body, err = editPullRequest(title, body); err != nil {
+			return "", err
+		}
+	}
+
+	fmt.Fprintf(out, "Creating pull request for branch '%s' on %s...\n", branch, host.Name())
+	url, err := host.CreatePullRequest(session.Core.WorktreePath, forge.PullRequest{
+		Title:  title,
+		Body:   body,
+		Branch: branch,
+		Base:   session.BaseBranch,
+		Draft:  draft,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+	if url != "" {
+		fmt.Fprintf(out, "Created pull request: %s\n", url)
+	}
+	return url, nil
 }
 
-// createPullRequest creates a pull request using GitHub CLI
-func createPullRequest(branch string, draft bool) error {
-	sessionName := strings.TrimPrefix(branch, "cwt-")
-	title := fmt.Sprintf("feat(%s): Session changes", sessionName)
+// latestTranscript summarizes the most recent Claude conversation in a
+// worktree, or returns nil when there is none to read
+func latestTranscript(worktree string) *claude.TranscriptSummary {
+	transcripts, err := claude.NewSessionScanner().FindSessionsForDirectory(worktree)
+	if err != nil || len(transcripts) == 0 {
+		return nil
+	}
+	summary, err := claude.SummarizeTranscript(transcripts[0].FilePath)
+	if err != nil {
+		return nil
+	}
+	return &summary
+}
 
-	body := fmt.Sprintf(`## Summary
-Changes from CWT session: %s
+// pullRequestTitle titles a session's pull request after the first line of
+// its task, or of the first prompt of its conversation
+func pullRequestTitle(session types.Session, transcript *claude.TranscriptSummary) string {
+	task := session.Core.Task
+	if task == "" && transcript != nil {
+		task = transcript.FirstPrompt
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(task), "\n")
+	title := strings.Join(strings.Fields(line), " ")
+	if title == "" {
+		return fmt.Sprintf("feat(%s): Session changes", session.Core.Name)
+	}
 
-## Generated Context
-- Session branch: %s
-- Created: %s
+	if len(title) > maxPullRequestTitle {
+		title = title[:maxPullRequestTitle]
+		if space := strings.LastIndex(title, " "); space > maxPullRequestTitle/2 {
+			title = title[:space]
+		}
+		title = strings.ToValidUTF8(title, "") + "…"
+	}
+	return title
+}
 
-🤖 Generated with [Claude Code](https://claude.ai/code)`,
-		sessionName,
-		branch,
-		time.Now().Format("2006-01-02 15:04:05"))
+// pullRequestBody describes a session's changes for its pull request: what
+// Claude said it did and the files it edited, from its transcript when
+// there is one, then the result of its follow-up command and the coverage
+// it measured
+func pullRequestBody(session types.Session, transcript *claude.TranscriptSummary, created time.Time) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "## Summary\nChanges from CWT session: %s\n", session.Core.Name)
+	if transcript != nil && transcript.LastReply != "" {
+		reply := transcript.LastReply
+		if len(reply) > maxPullRequestSummary {
+			reply = strings.ToValidUTF8(reply[:maxPullRequestSummary], "") + "…"
+		}
+		fmt.Fprintf(&body, "\n%s\n", reply)
+	}
 
-	args := []string{"pr", "create", "--title", title, "--body", body}
-	if draft {
-		args = append(args, "--draft")
+	if transcript != nil && (len(transcript.Files) > 0 || len(transcript.ToolCalls) > 0) {
+		body.WriteString("\n## Changes\n")
+		for i, file := range transcript.Files {
+			if i == maxPullRequestFiles {
+				fmt.Fprintf(&body, "- …and %d more\n", len(transcript.Files)-i)
+				break
+			}
+			if rel, err := filepath.Rel(session.Core.WorktreePath, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+			fmt.Fprintf(&body, "- `%s`\n", file)
+		}
+		if tools := transcript.Tools(); len(tools) > 0 {
+			fmt.Fprintf(&body, "\nTools used: %s\n", strings.Join(tools, ", "))
+		}
 	}
 
-	fmt.Printf("Creating pull request for branch '%s'...\n", branch)
-	cmd := exec.Command("gh", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if followUp := session.Core.FollowUp; followUp != nil && followUp.Result != nil {
+		fmt.Fprintf(&body, "\n## Tests\n- `%s`: %s\n", followUp.Command, followUp.Summary())
+		if coverage := operations.NewStatusFormat().FormatCoverage(session); coverage != "" {
+			fmt.Fprintf(&body, "- Coverage: %s\n", coverage)
+		}
+	}
+
+	fmt.Fprintf(&body, "\n## Generated Context\n- Session branch: %s\n- Created: %s\n",
+		session.BranchName(), created.Format("2006-01-02 15:04:05"))
+	body.WriteString("\n🤖 Generated with [Claude Code](https://claude.ai/code)")
+	return body.String()
+}
+
+// editPullRequest opens a pull request's title and body in the editor and
+// returns them as saved: the first line is the title, the rest the body
+func editPullRequest(title, body string) (string, string, error) {
+	file, err := os.CreateTemp("", "cwt-pr-*.md")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create pull request file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = fmt.Fprintf(file, "%s\n\n%s\n", title, body)
+	file.Close()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write pull request file: %w", err)
+	}
 
+	editor := appConfig.EditorCommand()
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", file.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to create pull request: %w", err)
+		return "", "", fmt.Errorf("editor %s failed: %w", editor, err)
 	}
 
-	return nil
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read pull request file: %w", err)
+	}
+	title, body = splitPullRequest(string(data))
+	if title == "" {
+		return "", "", fmt.Errorf("pull request title is empty; not creating it")
+	}
+	return title, body, nil
+}
+
+// splitPullRequest reads an edited pull request: its first non-blank line
+// is the title and the rest the body
+func splitPullRequest(text string) (string, string) {
+	title, body, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
+}
+
+// headCommitFiles lists the files changed by the commit checked out
+func headCommitFiles() []string {
+	output, err := exec.Command("git", "diff-tree", "--no-commit-id", "--name-only", "-r", "HEAD").Output()
+	if err != nil {
+		return []string{}
+	}
+	return outputLines(output)
 }
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestPullRequestBody(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	session := types.Session{Core: types.CoreSession{Name: "auth"}, BaseBranch: "main"}
+
+	body := pullRequestBody(session, nil, created)
+	if !strings.Contains(body, "Changes from CWT session: auth") || !strings.Contains(body, "- Created: 2026-03-01 09:30:00") {
+		t.Errorf("body = %q, want the session and when it was created", body)
+	}
+	if strings.Contains(body, "## Tests") {
+		t.Errorf("body = %q, want no tests section without a follow-up result", body)
+	}
+
+	session.Core.FollowUp = &types.FollowUp{
+		On:        types.FollowUpOnComplete,
+		Command:   "make test",
+		StartedAt: &created,
+		Result: &types.FollowUpResult{
+			Coverage:     &types.Coverage{Covered: 80, Total: 100},
+			BaseCoverage: &types.Coverage{Covered: 75, Total: 100},
+		},
+	}
+	body = pullRequestBody(session, nil, created)
+	for _, want := range []string{"## Tests", "- `make test`: passed", "- Coverage: 80.0% (+5.0% vs main)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	}
+}
+
+func TestPullRequestBody_FromTranscript(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "auth", WorktreePath: "/repo/.cwt/worktrees/auth"}}
+	transcript := &claude.TranscriptSummary{
+		ToolCalls: map[string]int{"Edit": 3, "Bash": 1},
+		Files:     []string{"/repo/.cwt/worktrees/auth/login.go", "/tmp/scratch.txt"},
+		LastReply: "Added a login form backed by JWT sessions.",
+	}
+
+	body := pullRequestBody(session, transcript, time.Now())
+	for _, want := range []string{
+		"Changes from CWT session: auth\n\nAdded a login form backed by JWT sessions.\n",
+		"## Changes\n- `login.go`\n- `/tmp/scratch.txt`\n",
+		"Tools used: Edit ×3, Bash ×1",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	}
+}
+
+func TestPullRequestTitle(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "auth"}}
+	if got := pullRequestTitle(session, nil); got != "feat(auth): Session changes" {
+		t.Errorf("title without a task = %q", got)
+	}
+
+	transcript := &claude.TranscriptSummary{FirstPrompt: "Fix  the login\ncrash on Safari"}
+	if got := pullRequestTitle(session, transcript); got != "Fix the login" {
+		t.Errorf("title from the first prompt = %q", got)
+	}
+
+	session.Core.Task = strings.Repeat("word ", 30)
+	got := pullRequestTitle(session, transcript)
+	if !strings.HasSuffix(got, "word…") || len(got) > maxPullRequestTitle+len("…") {
+		t.Errorf("long title = %q, want it cut at a word", got)
+	}
+}
+
+func TestSplitPullRequest(t *testing.T) {
+	title, body := splitPullRequest("\n  Add login \n\n## Summary\nDone.\n\n")
+	if title != "Add login" || body != "## Summary\nDone." {
+		t.Errorf("split = %q, %q", title, body)
+	}
+	if title, _ := splitPullRequest(" \n\n"); title != "" {
+		t.Errorf("title of an emptied file = %q, want none", title)
+	}
+}
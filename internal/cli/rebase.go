@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+)
+
+func newRebaseCmd() *cobra.Command {
+	var onto string
+	var abort bool
+
+	cmd := &cobra.Command{
+		Use:   "rebase <session-name>",
+		Short: "Rebase a session's branch onto the latest base branch",
+		Long: `Rebase a session's branch onto the base branch in the session's worktree,
+bringing in what was merged there since the session started. 'cwt status'
+shows how many commits a session is behind its base branch.
+
+The worktree must have no uncommitted changes. When conflicts stop the
+rebase, the conflicted files are listed and the rebase is left in progress:
+resolve them in the worktree (or ask Claude to) and run
+'git rebase --continue' there, or put the branch back where it was with
+--abort.
+
+Examples:
+  cwt rebase my-session                  # Rebase onto the base branch
+  cwt rebase my-session --onto develop   # Rebase onto another branch
+  cwt rebase my-session --abort          # Abandon a rebase stopped on conflicts`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if abort && onto != "" {
+				return fmt.Errorf("--abort and --onto can't be used together")
+			}
+			return runRebaseCmd(args[0], onto, abort)
+		},
+	}
+
+	cmd.Flags().StringVar(&onto, "onto", "", "Branch or commit to rebase onto (default: the base branch)")
+	cmd.RegisterFlagCompletionFunc("onto", completeBranches)
+	cmd.Flags().BoolVar(&abort, "abort", false, "Abort a rebase stopped on conflicts")
+
+	return cmd
+}
+
+func runRebaseCmd(name, onto string, abort bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	session, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+	if err := operations.CheckWorktree(*session); err != nil {
+		return err
+	}
+
+	if abort {
+		if err := sm.AbortRebase(sessionID); err != nil {
+			return err
+		}
+		fmt.Printf("↩️  Aborted the rebase of '%s'; its branch is back where it was\n", name)
+		return nil
+	}
+
+	result, err := sm.RebaseSession(sessionID, onto)
+	if err != nil {
+		var conflictErr *git.RebaseConflictError
+		if !errors.As(err, &conflictErr) {
+			return err
+		}
+		fmt.Printf("⚠️  Rebasing '%s' onto %s stopped on conflicts in:\n", name, conflictErr.Onto)
+		for _, file := range conflictErr.Files {
+			fmt.Printf("   %s\n", file)
+		}
+		fmt.Printf("\n💡 Resolve them in %s and run 'git rebase --continue' there,\n", session.Core.WorktreePath)
+		fmt.Printf("   or put the branch back with: cwt rebase %s --abort\n", name)
+		return fmt.Errorf("rebase of '%s' stopped on conflicts", name)
+	}
+
+	switch {
+	case !result.Updated:
+		fmt.Printf("✅ '%s' is already up to date with %s\n", name, result.Onto)
+	case result.Behind > 0:
+		fmt.Printf("⤴️  Rebased '%s' onto %s, bringing in %d commit(s)\n", name, result.Onto, result.Behind)
+	default:
+		fmt.Printf("⤴️  Rebased '%s' onto %s\n", name, result.Onto)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// newRecipeCmd creates the 'cwt recipe' command
+func newRecipeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipe",
+		Short: "List the recipes sessions can be created from with 'cwt new --recipe'",
+		Long: `List the session recipes: bundles of a prompt, settings and what to do once
+Claude is done, that 'cwt new --recipe <name>' creates a session from.
+
+Besides the built-in recipes, each directory under ~/.config/cwt/recipes
+(yours) and .cwt/recipes (the project's) is a recipe named after it. A
+project recipe replaces one of yours, or a built-in one, of the same name.
+A recipe directory holds:
+
+  recipe.yaml   description: Bump the Node version
+                prompt: ...          # or in prompt.md
+                idle_timeout: 48h    # before the expiry policy archives the session
+                gates:               # run when Claude is done; all must pass
+                  - test             # test_command from the config
+                  - npm run lint     # or any shell command, in the worktree
+                post_actions:        # run in order once the gates pass
+                  - publish          # or publish-draft, or !<shell command>
+                config:              # config settings while creating the session
+                  test_command: npm test
+                env:                 # environment of the session's tmux session
+                  NODE_ENV: test
+  prompt.md     The prompt, when recipe.yaml has none
+
+The prompt is a Go template that can use {{.Session}}, {{.Task}} (the task
+given to 'cwt new') and {{.BaseBranch}}. A task the prompt doesn't place is
+added after it. Gates and post-actions run as the session's follow-up, which
+the daemon runs.
+
+Share a recipe by copying its directory, or committing it under .cwt/recipes.
+
+Examples:
+  cwt recipe                      # List the recipes
+  cwt recipe show deps-update     # Show what a recipe does
+  cwt new --recipe deps-update    # Create a session from it`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListRecipes()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the recipes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListRecipes()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:               "show <recipe>",
+		Short:             "Show a recipe's prompt, settings, gates and post-actions",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRecipes,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShowRecipe(args[0])
+		},
+	})
+
+	return cmd
+}
+
+// loadRecipes loads the built-in recipes, then the config's, then the
+// user's, then the project's
+func loadRecipes() (*operations.RecipeRegistry, error) {
+	return operations.NewRecipeRegistry(appConfig.Recipes, config.UserRecipesDir(), config.ProjectRecipesDir(dataDir))
+}
+
+func runListRecipes() error {
+	registry, err := loadRecipes()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Recipes (cwt new --recipe <name>):")
+	for _, recipe := range registry.List() {
+		fmt.Printf("  🧪 %-20s %s\n", recipe.Name, recipe.Description)
+		if recipe.Source != operations.RecipeBuiltin {
+			fmt.Printf("     %-20s from %s\n", "", recipe.Source)
+		}
+	}
+	return nil
+}
+
+func runShowRecipe(name string) error {
+	registry, err := loadRecipes()
+	if err != nil {
+		return err
+	}
+	recipe, err := registry.Find(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🧪 %s\n", recipe.Name)
+	if recipe.Description != "" {
+		fmt.Printf("   %s\n", recipe.Description)
+	}
+	fmt.Printf("   Source:        %s\n", recipe.Source)
+	if recipe.IdleTimeout > 0 {
+		fmt.Printf("   Idle timeout:  %s\n", recipe.IdleTimeout)
+	}
+	if len(recipe.Gates) > 0 {
+		fmt.Printf("   Gates:         %s\n", strings.Join(recipe.Gates, ", "))
+	}
+	if len(recipe.PostActions) > 0 {
+		fmt.Printf("   Post-actions:  %s\n", strings.Join(recipe.PostActions, ", "))
+	}
+	if len(recipe.Env) > 0 {
+		names := make([]string, 0, len(recipe.Env))
+		for name := range recipe.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("   Env:\n")
+		for _, name := range names {
+			fmt.Printf("     %s=%s\n", name, recipe.Env[name])
+		}
+	}
+	if len(recipe.Config) > 0 {
+		data, err := yaml.Marshal(recipe.Config)
+		if err == nil {
+			fmt.Printf("   Config:\n")
+			for _, line := range outputLines(data) {
+				fmt.Printf("     %s\n", line)
+			}
+		}
+	}
+	fmt.Printf("\n%s\n", recipe.Prompt)
+	return nil
+}
+
+// useRecipe finds the recipe a session is created from and applies its
+// config overrides. Flags given on the command line still win over them.
+func useRecipe(cmd *cobra.Command, name string) (*operations.Recipe, error) {
+	registry, err := loadRecipes()
+	if err != nil {
+		return nil, err
+	}
+	recipe, err := registry.Find(name)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := recipe.ConfigOverrides()
+	if err != nil || overrides == nil {
+		return &recipe, err
+	}
+	cfg, err := appConfig.Overridden(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config of recipe '%s': %w", recipe.Name, err)
+	}
+	if cmd.Flags().Changed("base-branch") {
+		cfg.BaseBranch = baseBranch
+	} else {
+		baseBranch = cfg.BaseBranch
+	}
+	appConfig = cfg
+	return &recipe, nil
+}
+
+// applyRecipe sets up a session to be created from a recipe: its task, its
+// idle timeout and the follow-up that runs its gates and post-actions
+func applyRecipe(opts *state.CreateOptions, recipe operations.Recipe, sessionName string) error {
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	command := utils.GetCWTCommand()
+	for i, word := range command {
+		command[i] = utils.ShellQuote(word)
+	}
+	followUp, err := recipe.FollowUp(sessionName, appConfig.TestCommand, strings.Join(command, " "), repoDir)
+	if err != nil {
+		return err
+	}
+	task, err := recipe.Task(operations.RecipeData{Session: sessionName, Task: opts.Task, BaseBranch: baseBranch})
+	if err != nil {
+		return err
+	}
+
+	opts.Task = task
+	opts.Template = recipe.Name
+	opts.IdleTimeout = recipe.IdleTimeout
+	opts.FollowUp = followUp
+	opts.Env = recipe.Env
+	return nil
+}
+
+// completeRecipes completes recipe names
+func completeRecipes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if err := completionConfig(cmd); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	registry, err := loadRecipes()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterCompletions(registry.Names(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+)
+
+func newRenameCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <session-name> <new-name>",
+		Short: "Rename a session and its branch and tmux session",
+		Long: `Rename a CWT session everywhere its name is used:
+- Git branch
+- Tmux session (renamed in place if it is running, so Claude keeps working)
+- Session metadata
+
+The worktree directory keeps its name, since Claude files its conversations
+under the worktree's path. If any step fails, the steps already done are undone.`,
+		Aliases:           []string{"mv"},
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRenameCmd(args[0], args[1])
+		},
+	}
+
+	return cmd
+}
+
+func runRenameCmd(name, newName string) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	_, sessionID, err := operations.NewSessionOperations(sm).FindSessionByName(name)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.RenameSession(sessionID, newName); err != nil {
+		return fmt.Errorf("failed to rename session '%s': %w", name, err)
+	}
+
+	fmt.Printf("✅ Renamed session '%s' to '%s'\n", name, newName)
+	return nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newRepairCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "repair [session-name]...",
+		Short: "Recreate the broken worktree of a session from its branch",
+		Long: `Repair sessions whose worktree is broken: the directory was deleted by
+hand, or restored from a backup without its .git link. 'cwt status' marks
+them 💔 broken.
+
+The worktree is checked out again from the session's branch, so committed
+work is all there. A leftover directory is moved aside to
+<worktree>.broken-<time> rather than deleted, since it may hold changes
+that were never committed. A running tmux session is restarted in the new
+worktree, continuing the Claude conversation.
+
+A session whose branch is gone too can't be repaired; delete it instead.
+
+Examples:
+  cwt repair my-session     # Repair one session
+  cwt repair --all          # Repair every broken session`,
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) > 0) {
+				return fmt.Errorf("name the sessions to repair, or use --all")
+			}
+			return runRepairCmd(args, all)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Repair every session with a broken worktree")
+
+	return cmd
+}
+
+func runRepairCmd(names []string, all bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	sessions, err := sm.DeriveFreshSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var targets []types.Session
+	if all {
+		for _, session := range sessions {
+			if session.GitStatus.WorktreeBroken() {
+				targets = append(targets, session)
+			}
+		}
+		if len(targets) == 0 {
+			fmt.Println("✅ No session has a broken worktree")
+			return nil
+		}
+	} else {
+		byName := make(map[string]types.Session, len(sessions))
+		for _, session := range sessions {
+			byName[session.Core.Name] = session
+		}
+		for _, name := range names {
+			session, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("session '%s' not found", name)
+			}
+			targets = append(targets, session)
+		}
+	}
+
+	var failed int
+	for _, session := range targets {
+		name := session.Core.Name
+		result, err := sm.RepairWorktree(session.Core.ID)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("🛠️  Recreated the worktree of '%s' from branch %s\n", name, result.Branch)
+		if result.MovedAside != "" {
+			fmt.Printf("   The broken directory was moved to %s\n", result.MovedAside)
+		}
+		if result.Restarted {
+			fmt.Printf("   Restarted its tmux session in the new worktree\n")
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to repair %d of %d sessions", failed, len(targets))
+	}
+	return nil
+}
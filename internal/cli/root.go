@@ -2,16 +2,38 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/statusprovider"
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/daemon"
+	"github.com/jlaneve/cwt-cli/internal/logging"
+	"github.com/jlaneve/cwt-cli/internal/profile"
 	"github.com/jlaneve/cwt-cli/internal/state"
 )
 
 var (
 	dataDir    string
 	baseBranch string
+	noCache    bool
+	noDaemon   bool
+	verbose    bool
+	debug      bool
+	logFile    string
+	profiling  bool
+
+	// profiler times the command's work, for --profile and the TUI's
+	// timings overlay; nil when nothing is timed
+	profiler     *profile.Recorder
+	profileStart time.Time
+
+	// appConfig is the effective configuration (config files + flags),
+	// loaded before any command runs
+	appConfig = config.Default()
 )
 
 // NewRootCmd creates the root command for the CWT CLI
@@ -24,6 +46,13 @@ with isolated git worktrees. Think of it as a project management system where yo
 the engineering manager and Claude Code sessions are your engineers working on isolated tasks.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if profiling {
+				profiler = profile.NewRecorder()
+				profileStart = time.Now()
+			}
+			return loadConfig(cmd)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// When no subcommand is provided, launch TUI
 			return runTuiCmd(cmd, args)
@@ -34,8 +63,15 @@ the engineering manager and Claude Code sessions are your engineers working on i
 	rootCmd.SetHelpTemplate(getCustomHelpTemplate())
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", ".cwt", "Directory for storing session data")
-	rootCmd.PersistentFlags().StringVar(&baseBranch, "base-branch", "main", "Base branch for creating worktrees")
+	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", config.DefaultDataDir, "Directory for storing session data (overrides config)")
+	rootCmd.PersistentFlags().StringVar(&baseBranch, "base-branch", config.DefaultBaseBranch, "Base branch for creating worktrees (overrides config)")
+	rootCmd.RegisterFlagCompletionFunc("base-branch", completeBranches)
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Derive git/tmux/Claude status fresh instead of using the status cache")
+	rootCmd.PersistentFlags().BoolVar(&noDaemon, "no-daemon", false, "Derive status in this process even if 'cwt daemon' is running")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Log what cwt does, not just warnings and errors")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Log everything cwt does, for troubleshooting")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append the log to this file instead of stderr")
+	rootCmd.PersistentFlags().BoolVar(&profiling, "profile", false, "Print how long the command spent loading state, in git, tmux and Claude calls and rendering")
 
 	// Add subcommands with annotations for grouping
 
@@ -43,7 +79,19 @@ the engineering manager and Claude Code sessions are your engineers working on i
 	sessionMgmt := []*cobra.Command{
 		addAnnotation(newNewCmd(), "session-mgmt"),
 		addAnnotation(newAttachCmd(), "session-mgmt"),
+		addAnnotation(newOpenCmd(), "session-mgmt"),
 		addAnnotation(newDeleteCmd(), "session-mgmt"),
+		addAnnotation(newRenameCmd(), "session-mgmt"),
+		addAnnotation(newPauseCmd(), "session-mgmt"),
+		addAnnotation(newResumeCmd(), "session-mgmt"),
+		addAnnotation(newRepairCmd(), "session-mgmt"),
+		addAnnotation(newOnCmd(), "session-mgmt"),
+		addAnnotation(newPriorityCmd(), "session-mgmt"),
+		addAnnotation(newWatchCmd(), "session-mgmt"),
+		addAnnotation(newMuteCmd(), "session-mgmt"),
+		addAnnotation(newContextCmd(), "session-mgmt"),
+		addAnnotation(newRecipeCmd(), "session-mgmt"),
+		addAnnotation(newArchiveCmd(), "session-mgmt"),
 		addAnnotation(newCleanupCmd(), "session-mgmt"),
 	}
 
@@ -51,6 +99,8 @@ the engineering manager and Claude Code sessions are your engineers working on i
 	sessionWorkflow := []*cobra.Command{
 		addAnnotation(newSwitchCmd(), "session-workflow"),
 		addAnnotation(newMergeCmd(), "session-workflow"),
+		addAnnotation(newRebaseCmd(), "session-workflow"),
+		addAnnotation(newSyncCmd(), "session-workflow"),
 		addAnnotation(newPublishCmd(), "session-workflow"),
 	}
 
@@ -58,18 +108,28 @@ the engineering manager and Claude Code sessions are your engineers working on i
 	info := []*cobra.Command{
 		addAnnotation(newListCmd(), "info"),
 		addAnnotation(newStatusCmd(), "info"),
+		addAnnotation(newShowCmd(), "info"),
+		addAnnotation(newLogCmd(), "info"),
+		addAnnotation(newStandupCmd(), "info"),
+		addAnnotation(newEventsCmd(), "info"),
 		addAnnotation(newDiffCmd(), "info"),
+		addAnnotation(newSearchCmd(), "info"),
+		addAnnotation(newLimitsCmd(), "info"),
 	}
 
 	// Interface & Utilities
 	interface_utils := []*cobra.Command{
 		addAnnotation(newTuiCmd(), "interface"),
+		addAnnotation(newDaemonCmd(), "interface"),
+		addAnnotation(newShutdownCmd(), "interface"),
 		addAnnotation(newFixHooksCmd(), "interface"),
+		addAnnotation(newTutorialCmd(), "interface"),
 	}
 
 	// Hidden/Internal commands (no annotation needed)
 	hidden := []*cobra.Command{
-		newHookCmd(), // Hidden internal command
+		newHookCmd(),          // Hidden internal command
+		newSessionExitedCmd(), // Run by tmux when a session's pane exits
 	}
 
 	// Add all commands
@@ -92,15 +152,128 @@ the engineering manager and Claude Code sessions are your engineers working on i
 	return rootCmd
 }
 
-// createStateManager creates a StateManager with the current configuration
+// loadConfig reads the user and project config files and resolves the
+// global options, giving explicitly set flags precedence over config values
+func loadConfig(cmd *cobra.Command) error {
+	cfg, err := config.Load(dataDir)
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("data-dir") {
+		cfg.DataDir = dataDir
+	} else {
+		dataDir = cfg.DataDir
+	}
+	if flags.Changed("base-branch") {
+		cfg.BaseBranch = baseBranch
+	} else {
+		baseBranch = cfg.BaseBranch
+	}
+	if noCache {
+		cfg.StatusCacheTTL = 0
+	}
+
+	appConfig = cfg
+	return configureLogging(cfg)
+}
+
+// configureLogging sets up the log from the flags, then the CWT_LOG and
+// CWT_LOG_FILE environment variables, then the config file
+func configureLogging(cfg *config.Config) error {
+	opts := logging.Options{Level: logging.DefaultLevel, File: cfg.Log.File}
+
+	levelName := cfg.Log.Level
+	if env := os.Getenv(logging.EnvLevel); env != "" {
+		levelName = env
+	}
+	if levelName != "" {
+		level, err := logging.ParseLevel(levelName)
+		if err != nil {
+			return err
+		}
+		opts.Level = level
+	}
+	switch {
+	case debug:
+		opts.Level = slog.LevelDebug
+	case verbose:
+		opts.Level = slog.LevelInfo
+	}
+
+	if env := os.Getenv(logging.EnvFile); env != "" {
+		opts.File = env
+	}
+	if logFile != "" {
+		opts.File = logFile
+	}
+
+	return logging.Configure(opts)
+}
+
+// reloadConfig re-reads the config files for a long-running command,
+// keeping the overrides from global flags
+func reloadConfig() (*config.Config, error) {
+	cfg, err := config.Load(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if noCache {
+		cfg.StatusCacheTTL = 0
+	}
+	return cfg, nil
+}
+
+// createStateManager creates a StateManager with the current configuration,
+// serving sessions from a running daemon when one is available
 func createStateManager() (*state.Manager, error) {
-	config := state.Config{
-		DataDir:    dataDir,
-		BaseBranch: baseBranch,
+	return newStateManager(!noDaemon && !noCache)
+}
+
+// newStateManager creates a StateManager, optionally backed by the daemon
+func newStateManager(useDaemon bool) (*state.Manager, error) {
+	smConfig := state.Config{
+		DataDir:          dataDir,
+		BaseBranch:       baseBranch,
+		GitBackend:       appConfig.GitBackend,
+		ClaudeExecutable: appConfig.ClaudeExecutable,
+		StatusCacheTTL:   appConfig.StatusCacheTTL,
+		GitHooksInstall:  appConfig.GitHooks.Install,
+		NoClaudeHooks:    !appConfig.ClaudeHooks,
+		CoverageProfile:  appConfig.CoverageProfile,
+		Limits:           stateLimits(appConfig.Limits),
+		Forge:            appConfig.Forge,
+		Env:              appConfig.Env,
+		CopyUntracked:    appConfig.CopyUntracked,
+		ClaudeArgs:       appConfig.ClaudeArgs,
+		Agent:            appConfig.Agent,
+		Agents:           stateAgents(appConfig.Agents),
+		Profile:          profiler,
+		Hooks: state.Hooks{
+			PostCreate:    appConfig.Hooks.PostCreate,
+			PreDelete:     appConfig.Hooks.PreDelete,
+			PostMerge:     appConfig.Hooks.PostMerge,
+			Timeout:       appConfig.Hooks.Timeout,
+			WarnOnFailure: appConfig.Hooks.OnFailure == config.HookFailureWarn,
+		},
+		StatusProviders: statusprovider.NewRealChecker(statusprovider.Options{
+			Discover: appConfig.StatusProviders.Discover,
+			Commands: appConfig.StatusProviders.Commands,
+			Timeout:  appConfig.StatusProviders.Timeout,
+			Interval: appConfig.StatusProviders.Interval,
+		}),
 		// Use real checkers (default behavior)
 	}
 
-	sm := state.NewManager(config)
+	if useDaemon {
+		// Fall back to deriving sessions directly if no daemon is running
+		if client, err := daemon.Connect(dataDir); err == nil {
+			smConfig.Provider = client
+		}
+	}
+
+	sm := state.NewManager(smConfig)
 
 	// Validate git repository by trying to derive sessions
 	_, err := sm.DeriveFreshSessions()
@@ -119,6 +292,15 @@ func createStateManager() (*state.Manager, error) {
 	return sm, nil
 }
 
+// stateAgents converts the agents config for the state manager
+func stateAgents(cfg map[string]config.AgentConfig) map[string]state.AgentSettings {
+	agents := make(map[string]state.AgentSettings, len(cfg))
+	for name, agent := range cfg {
+		agents[name] = state.AgentSettings{Executable: agent.Executable, Args: agent.Args}
+	}
+	return agents
+}
+
 // addAnnotation adds a group annotation to a command
 func addAnnotation(cmd *cobra.Command, group string) *cobra.Command {
 	if cmd.Annotations == nil {
@@ -168,7 +350,25 @@ Workflow Examples:
 // Execute runs the root command
 func Execute() {
 	rootCmd := NewRootCmd()
-	if err := rootCmd.Execute(); err != nil {
+
+	// Expand user-defined aliases before cobra dispatches the command
+	args := os.Args[1:]
+	expansion, err := resolveAlias(rootCmd, args, loadAliases(args))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if expansion.Shell != "" {
+		runShellAlias(expansion.Shell)
+	}
+	rootCmd.SetArgs(expansion.Args)
+
+	err = rootCmd.Execute()
+	if profiling && profiler != nil {
+		// On stderr, so it doesn't get in the way of JSON output
+		profile.WriteReport(os.Stderr, profiler.Snapshot(), time.Since(profileStart))
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+)
+
+// searchTextWidth keeps each reported match on one line
+const searchTextWidth = 100
+
+func newSearchCmd() *cobra.Command {
+	var opts operations.SearchOptions
+	var sessionsOnly bool
+	var maxMatches int
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "search <pattern>",
+		Short: "Find which sessions changed a symbol, file or string",
+		Long: `Search the changes of every session for a string, reporting which sessions
+touch it. A session's changes are everything it did since branching off the
+base branch: committed, uncommitted and untracked files. Changed file paths
+are matched too, so a file name finds every session that modified it.
+
+With --transcripts, the sessions' Claude transcripts are searched as well:
+prompts, replies, tool calls and their results.
+
+Examples:
+  cwt search "TODO(auth)"               # Which sessions added or removed a TODO
+  cwt search validateToken -l           # Only list the sessions
+  cwt search 'func (New|Make)Client' -E # Match a regular expression
+  cwt search auth/token.go              # Which sessions modified a file
+  cwt search auth/ --files              # Quickly match only changed file paths
+  cwt search "rate limit" -i -t         # Include what Claude said and did`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Pattern = args[0]
+			if opts.Base == "" {
+				opts.Base = baseBranch
+			}
+			return runSearchCmd(opts, sessionsOnly, maxMatches, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.Regex, "regex", "E", false, "Treat the pattern as a regular expression")
+	cmd.Flags().BoolVarP(&opts.IgnoreCase, "ignore-case", "i", false, "Match regardless of case")
+	cmd.Flags().BoolVarP(&opts.Transcripts, "transcripts", "t", false, "Also search Claude transcripts")
+	cmd.Flags().BoolVar(&opts.FilesOnly, "files", false, "Only match changed file paths (fast, no diffs)")
+	cmd.Flags().StringVar(&opts.Base, "against", "", "Compare sessions against specific branch (default: base branch)")
+	cmd.RegisterFlagCompletionFunc("against", completeBranches)
+	cmd.Flags().BoolVarP(&sessionsOnly, "sessions-only", "l", false, "Only list the names of matching sessions")
+	cmd.Flags().IntVarP(&maxMatches, "max", "m", 10, "Matches to show per session (0 for all)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output matches as JSON")
+
+	return cmd
+}
+
+func runSearchCmd(opts operations.SearchOptions, sessionsOnly bool, maxMatches int, jsonOutput bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	results, err := operations.NewSessionOperations(sm).Search(opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if results == nil {
+			results = []operations.SessionSearchResult{}
+		}
+		return writeJSON(results)
+	}
+
+	var matched int
+	for _, result := range results {
+		if len(result.Matches) > 0 {
+			matched++
+		}
+	}
+
+	if sessionsOnly {
+		for _, result := range results {
+			if len(result.Matches) > 0 {
+				fmt.Println(result.Session)
+			}
+		}
+		return nil
+	}
+
+	if matched == 0 {
+		fmt.Printf("No sessions match %q\n", opts.Pattern)
+	} else {
+		fmt.Printf("🔍 %d %s match %q\n", matched, plural(matched, "session", "sessions"), opts.Pattern)
+	}
+
+	for _, result := range results {
+		fmt.Println()
+		if result.Error != "" {
+			fmt.Printf("⚠️  %s: %s\n", result.Session, result.Error)
+			if len(result.Matches) == 0 {
+				continue
+			}
+		}
+
+		fmt.Printf("📂 %s (%d %s)\n", result.Session, len(result.Matches), plural(len(result.Matches), "match", "matches"))
+		shown := result.Matches
+		if maxMatches > 0 && len(shown) > maxMatches {
+			shown = shown[:maxMatches]
+		}
+		for _, match := range shown {
+			fmt.Printf("   %s\n", formatSearchMatch(match))
+		}
+		if hidden := len(result.Matches) - len(shown); hidden > 0 {
+			fmt.Printf("   … and %d more (use --max 0 to show all)\n", hidden)
+		}
+	}
+
+	return nil
+}
+
+// formatSearchMatch describes a match on one line, marking added and removed
+// lines the way a diff does
+func formatSearchMatch(match operations.SearchMatch) string {
+	text := runewidth.Truncate(match.Text, searchTextWidth, "...")
+
+	switch match.Source {
+	case operations.SearchFile:
+		return fmt.Sprintf("📄 %s", match.File)
+	case operations.SearchAdded:
+		return fmt.Sprintf("+ %s:%d  %s", match.File, match.Line, text)
+	case operations.SearchRemoved:
+		return fmt.Sprintf("- %s:%d  %s", match.File, match.Line, text)
+	case operations.SearchTranscript:
+		return fmt.Sprintf("💬 %s", text)
+	default:
+		return strings.TrimSpace(fmt.Sprintf("%s %s", match.File, text))
+	}
+}
+
+func plural(count int, one, many string) string {
+	if count == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+)
+
+func TestFormatSearchMatch_TruncatesByWidth(t *testing.T) {
+	match := operations.SearchMatch{Source: operations.SearchTranscript, Text: strings.Repeat("修复登录错误 🐛 ", 20)}
+	line := formatSearchMatch(match)
+	if !utf8.ValidString(line) {
+		t.Errorf("formatSearchMatch() = %q, cut inside a character", line)
+	}
+	text := strings.TrimPrefix(line, "💬 ")
+	if width := runewidth.StringWidth(text); width > searchTextWidth {
+		t.Errorf("match text is %d columns wide, want at most %d", width, searchTextWidth)
+	}
+	if !strings.HasSuffix(text, "...") {
+		t.Errorf("formatSearchMatch() = %q, want the cut marked", line)
+	}
+
+	match.Text = "short"
+	if got := formatSearchMatch(match); got != "💬 short" {
+		t.Errorf("formatSearchMatch() = %q, want short text left alone", got)
+	}
+}
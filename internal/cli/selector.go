@@ -258,6 +258,10 @@ func getSessionStatusIndicator(session types.Session) string {
 		indicators = append(indicators, "✅")
 	case types.ClaudeIdle:
 		indicators = append(indicators, "💤")
+	case types.ClaudeNotStarted:
+		indicators = append(indicators, "⚪")
+	case types.ClaudeNoAgent:
+		indicators = append(indicators, "🚫")
 	default:
 		indicators = append(indicators, "❓")
 	}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/profile"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// newShowCmd creates the 'cwt show' command
+func newShowCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "show [session-name]",
+		Short: "Show metadata and status for a single session",
+		Long: `Show everything CWT knows about a session: its task, who created it,
+the issue or PR it came from, the template used, and its current status.
+
+If session-name is not provided, you will be prompted to select
+from available sessions.
+
+Examples:
+  cwt show my-session          # Show session details
+  cwt show my-session --json   # Machine-readable output`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShowCmd(args, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output session as JSON")
+
+	return cmd
+}
+
+func runShowCmd(args []string, jsonOutput bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	sessions, err := sm.DeriveFreshSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	if len(sessions) == 0 {
+		return fmt.Errorf("no sessions found")
+	}
+
+	var session *types.Session
+	if len(args) > 0 {
+		for i := range sessions {
+			if sessions[i].Core.Name == args[0] {
+				session = &sessions[i]
+				break
+			}
+		}
+		if session == nil {
+			return fmt.Errorf("session '%s' not found", args[0])
+		}
+	} else {
+		session, err = SelectSession(sessions, WithTitle("Select a session to show:"))
+		if err != nil {
+			return fmt.Errorf("failed to select session: %w", err)
+		}
+		if session == nil {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	if jsonOutput {
+		return writeJSON(types.NewSessionOutput(*session))
+	}
+
+	renderSessionDetails(*session)
+	return nil
+}
+
+// publishesListed is how many pushes of a session's branch show lists,
+// the latest first
+const publishesListed = 5
+
+// renderSessionDetails prints metadata followed by the derived status
+func renderSessionDetails(session types.Session) {
+	defer profiler.Track(profile.PhaseRender)()
+
+	formatter := operations.NewStatusFormat()
+
+	fmt.Printf("🏷️  %s\n", session.Core.Name)
+	fmt.Printf("   ID:        %s\n", session.Core.ID)
+	fmt.Printf("   Created:   %s\n", session.Core.CreatedAt.Format("2006-01-02 15:04:05"))
+	if session.Core.CreatedBy != "" {
+		fmt.Printf("   Creator:   %s\n", session.Core.CreatedBy)
+	}
+	if session.Core.Source != "" {
+		fmt.Printf("   Source:    %s\n", session.Core.Source)
+	}
+	if session.Core.Template != "" {
+		fmt.Printf("   Template:  %s\n", session.Core.Template)
+	}
+	if len(session.Core.ClaudeArgs) > 0 {
+		fmt.Printf("   Args:      %s\n", strings.Join(session.Core.ClaudeArgs, " "))
+	}
+	if len(session.Core.Tags) > 0 {
+		fmt.Printf("   Tags:      %s\n", strings.Join(session.Core.Tags, ", "))
+	}
+	fmt.Printf("   Priority:  %s\n", formatter.FormatPriority(session.Core.SessionPriority()))
+	if session.Core.Attention != "" {
+		fmt.Printf("   Attention: %s%s\n", session.Core.Attention, attentionMark(session.Core))
+	}
+	fmt.Printf("   Worktree:  %s\n", session.Core.WorktreePath)
+	fmt.Printf("   Branch:    %s\n", formatBranch(session))
+	fmt.Printf("   Tmux:      %s (session: %s)\n", formatter.FormatSessionTmuxStatus(session), session.Core.TmuxSession)
+	if session.Core.IsPaused() {
+		fmt.Printf("   Paused:    %s (resume with: cwt resume %s)\n", formatter.FormatActivity(*session.Core.PausedAt), session.Core.Name)
+	}
+	if exit := session.Exit; exit != nil {
+		fmt.Printf("   Exited:    %s, %s\n", exit.Summary(), formatter.FormatActivity(exit.Time))
+		for _, line := range exit.Tail(5) {
+			fmt.Printf("              │ %s\n", line)
+		}
+	}
+	fmt.Printf("   Git:       %s\n", formatter.FormatGitStatus(session.GitStatus))
+	if session.GitStatus.HasError() {
+		fmt.Printf("              %s\n", session.GitStatus.Error)
+	}
+	if hint := operations.WorktreeHint(session); hint != "" {
+		fmt.Printf("              💡 %s\n", hint)
+	}
+	if hint := operations.RebaseHint(session); hint != "" {
+		fmt.Printf("              💡 %s\n", hint)
+	}
+	if warning := operations.ConflictWarning(session); warning != "" {
+		fmt.Printf("              ⚠️  %s\n", warning)
+	}
+	if hint := operations.ReviewHint(session); hint != "" {
+		fmt.Printf("              🆕 %s\n", hint)
+	}
+	for _, file := range session.GitStatus.ConflictedFiles {
+		fmt.Printf("              ⚠ %s\n", file)
+	}
+	// Labeled with the agent's name, like "Claude:" or "Aider:"
+	agent := session.Core.AgentName()
+	agentLabel := strings.ToUpper(agent[:1]) + agent[1:] + ":"
+	fmt.Printf("   %-10s %s\n", agentLabel, formatter.FormatClaudeStatus(session.ClaudeStatus))
+	if hint := operations.ClaudeHint(session); hint != "" {
+		fmt.Printf("              💡 %s\n", hint)
+	}
+	fmt.Printf("   Activity:  %s\n", formatter.FormatActivity(session.LastActivity))
+	if pr := session.Core.PullRequest; pr != nil {
+		fmt.Printf("   PR:        %s (%s)\n", formatter.FormatPullRequest(*pr), pr.URL)
+	}
+	if publishes := session.Core.Publishes; len(publishes) > 0 {
+		fmt.Printf("   Published: %s\n", formatter.FormatPublish(publishes[len(publishes)-1]))
+		for i := len(publishes) - 2; i >= 0 && i >= len(publishes)-publishesListed; i-- {
+			fmt.Printf("              %s\n", formatter.FormatPublish(publishes[i]))
+		}
+		if earlier := len(publishes) - publishesListed; earlier > 0 {
+			fmt.Printf("              ... and %d earlier\n", earlier)
+		}
+		if hint := operations.PublishHint(session); hint != "" {
+			fmt.Printf("              ⚠️  %s\n", hint)
+		}
+	}
+	if followUp := session.Core.FollowUp; followUp != nil {
+		fmt.Printf("   Follow-up: %s (on %s: %s)\n", formatter.FormatFollowUp(*followUp), followUp.On, followUp.Command)
+		if coverage := formatter.FormatCoverage(session); coverage != "" {
+			fmt.Printf("   Coverage:  %s\n", coverage)
+		}
+		if followUp.Result != nil && !followUp.Succeeded() {
+			for _, line := range followUp.Tail(5) {
+				fmt.Printf("              │ %s\n", line)
+			}
+		}
+	}
+
+	if session.Core.Task != "" {
+		fmt.Printf("\n   Task:\n")
+		for _, line := range strings.Split(session.Core.Task, "\n") {
+			fmt.Printf("     %s\n", line)
+		}
+	}
+}
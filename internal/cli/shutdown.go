@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/daemon"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+// newShutdownCmd creates the 'cwt shutdown' command
+func newShutdownCmd() *cobra.Command {
+	var killTmux, keep bool
+
+	cmd := &cobra.Command{
+		Use:   "shutdown",
+		Short: "Stop the daemon, TUIs and every session, like before a reboot",
+		Long: `Wind cwt down in one go, before rebooting or handing the machine to CI,
+instead of stopping sessions one by one.
+
+Running TUIs exit (when they watch the data directory, see auto_refresh),
+the daemon stops, and every running session is paused: its tmux session and
+Claude are stopped and its conversation is saved, so 'cwt resume' continues
+it later. Worktrees, branches and session metadata are kept.
+
+--kill-tmux ends the tmux sessions without pausing them; 'cwt attach' starts
+them again, resuming the last conversation when it can find it. --keep
+leaves the sessions running and only stops the daemon and TUIs.
+
+Examples:
+  cwt shutdown               # Pause every session and stop the daemon and TUIs
+  cwt shutdown --kill-tmux   # End the tmux sessions instead of pausing them
+  cwt shutdown --keep        # Only stop the daemon and TUIs`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShutdownCmd(killTmux, keep)
+		},
+	}
+
+	cmd.Flags().BoolVar(&killTmux, "kill-tmux", false, "End the sessions' tmux sessions instead of pausing them")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Leave the sessions running; only stop the daemon and TUIs")
+	cmd.MarkFlagsMutuallyExclusive("kill-tmux", "keep")
+
+	return cmd
+}
+
+func runShutdownCmd(killTmux, keep bool) error {
+	// Without the daemon, so stopping it doesn't leave the manager asking it
+	sm, err := newStateManager(false)
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	// TUIs go first, so they don't react to the sessions stopping
+	if err := state.WriteRefreshSignal(sm.GetDataDir(), state.RefreshSignal{Reason: state.RefreshShutdown, Time: sm.Now()}); err != nil {
+		fmt.Printf("⚠️  Failed to ask running TUIs to exit: %v\n", err)
+	} else {
+		fmt.Println("✅ Asked running TUIs to exit")
+	}
+
+	var daemonStopped bool
+	if client, err := daemon.Connect(sm.GetDataDir()); err == nil {
+		if err := client.Shutdown(); err != nil {
+			fmt.Printf("⚠️  Failed to stop the daemon: %v\n", err)
+		} else {
+			fmt.Println("✅ Stopped the daemon")
+			daemonStopped = true
+		}
+	} else if !errors.Is(err, daemon.ErrNotRunning) {
+		fmt.Printf("⚠️  Failed to reach the daemon: %v\n", err)
+	}
+
+	if keep {
+		fmt.Println("Sessions were left running.")
+		if daemonStopped {
+			fmt.Println("\nStart the daemon again with: cwt daemon")
+		}
+		return nil
+	}
+
+	result, err := sm.ShutdownSessions(killTmux)
+	for _, name := range result.Stopped {
+		if killTmux {
+			fmt.Printf("⏹️  Stopped session '%s'\n", name)
+		} else {
+			fmt.Printf("💤 Paused session '%s'\n", name)
+		}
+	}
+	if len(result.Stopped) == 0 && err == nil {
+		fmt.Println("No sessions were running.")
+	}
+
+	if daemonStopped || len(result.Stopped) > 0 {
+		fmt.Println("\nWhen you are back:")
+	}
+	if daemonStopped {
+		fmt.Println("  cwt daemon                 # Start the daemon again")
+	}
+	if len(result.Stopped) > 0 {
+		if killTmux {
+			fmt.Println("  cwt attach <session-name>  # Start a session again, resuming its conversation")
+		} else {
+			fmt.Printf("  cwt resume %s\n", strings.Join(result.Stopped, " "))
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to stop some sessions:\n%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// standupSummaryLength bounds the part of Claude's last reply a standup quotes
+const standupSummaryLength = 300
+
+func newStandupCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "standup",
+		Short: "Summarize what every session did, as markdown for standup notes",
+		Long: `Write a markdown summary of the sessions active since a point in time, ready
+to paste into standup notes. For each session it gives the task, where it
+stands, the commits its branch got, what was done to it (merges, publishes,
+follow-ups), the end of Claude's last reply and anything blocking it, like
+Claude waiting for an answer.
+
+Sessions with no activity in the period are only named at the end.
+
+--since takes "yesterday" (the default, from the start of yesterday),
+"today", a duration like 36h or 3d, or a date like 2024-05-17.
+
+Examples:
+  cwt standup                    # Since the start of yesterday
+  cwt standup --since 3d         # Since three days ago, like after a weekend
+  cwt standup | pbcopy           # Straight into the clipboard`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := parseSince(since, time.Now())
+			if err != nil {
+				return err
+			}
+			return runStandupCmd(os.Stdout, from)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "yesterday", "Start of the period: yesterday, today, a duration like 36h or 3d, or a date")
+
+	return cmd
+}
+
+// parseSince reads when a period starts: "yesterday" or "today" from the
+// start of that day, a duration before now like 36h or 3d, or a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch value := strings.TrimSpace(value); {
+	case value == "today":
+		return midnight, nil
+	case value == "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case strings.HasSuffix(value, "d"):
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	default:
+		if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+			return now.Add(-duration), nil
+		}
+		if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use yesterday, today, a duration like 36h or 3d, or a date like 2006-01-02", value)
+}
+
+// standupEntry is what a session did during a standup's period
+type standupEntry struct {
+	Session types.Session
+	Commits []types.CommitOutput // Commits its branch got, oldest first
+	Events  []types.SessionEvent // What was done to it
+	Summary string               // The end of Claude's last reply
+}
+
+// active reports whether anything happened to the session during the period
+func (e standupEntry) active(since time.Time) bool {
+	return len(e.Commits) > 0 || len(e.Events) > 0 || e.Summary != "" ||
+		e.Session.LastActivity.After(since) || e.Session.Core.CreatedAt.After(since)
+}
+
+func runStandupCmd(out io.Writer, since time.Time) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	sessions, err := operations.NewSessionOperations(sm).GetAllSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Core.CreatedAt.Before(sessions[j].Core.CreatedAt)
+	})
+
+	scanner := claude.NewSessionScanner()
+	entries := make([]standupEntry, 0, len(sessions))
+	for _, session := range sessions {
+		entry := standupEntry{
+			Session: session,
+			Commits: commitsSince(sm.GetBaseBranch(), session.BranchName(), since),
+			Summary: lastReplySince(scanner, session.Core.WorktreePath, since),
+		}
+		if events, err := sm.Timeline(session.Core.ID); err == nil {
+			entry.Events = standupEvents(events, since)
+		}
+		entries = append(entries, entry)
+	}
+
+	writeStandup(out, entries, since)
+	return nil
+}
+
+// commitsSince lists the commits a session's branch got since a time that
+// the base branch lacks, oldest first
+func commitsSince(baseBranch, branch string, since time.Time) []types.CommitOutput {
+	cmd := exec.Command("git", "log", "--reverse", "--since="+since.Format(time.RFC3339), "--format=%H%x09%s",
+		fmt.Sprintf("%s..%s", baseBranch, branch))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var commits []types.CommitOutput
+	for _, line := range outputLines(output) {
+		if hash, subject, ok := strings.Cut(line, "\t"); ok {
+			commits = append(commits, types.CommitOutput{Hash: hash, Subject: subject})
+		}
+	}
+	return commits
+}
+
+// lastReplySince returns the start of the last reply of the Claude
+// conversation in a worktree, if it went on since a time
+func lastReplySince(scanner *claude.SessionScanner, worktree string, since time.Time) string {
+	transcripts, err := scanner.FindSessionsForDirectory(worktree)
+	if err != nil || len(transcripts) == 0 || transcripts[0].LastSeen.Before(since) {
+		return ""
+	}
+	summary, err := claude.SummarizeTranscript(transcripts[0].FilePath)
+	if err != nil {
+		return ""
+	}
+
+	reply := strings.Join(strings.Fields(summary.LastReply), " ")
+	if len(reply) > standupSummaryLength {
+		reply = strings.ToValidUTF8(reply[:standupSummaryLength], "") + "…"
+	}
+	return reply
+}
+
+// standupEvents keeps the events of a timeline since a time that say what
+// was done to the session, leaving out attaching to it
+func standupEvents(events []types.SessionEvent, since time.Time) []types.SessionEvent {
+	var kept []types.SessionEvent
+	for _, event := range events {
+		if event.Time.Before(since) || !types.IsLifecycleEvent(event.Type) || event.Type == types.EventAttached {
+			continue
+		}
+		kept = append(kept, event)
+	}
+	return kept
+}
+
+// standupBlockers lists what keeps a session from moving on without help
+func standupBlockers(session types.Session) []string {
+	var blockers []string
+	if session.ClaudeStatus.State == types.ClaudeWaiting {
+		blocker := "Claude is waiting for input"
+		if message := session.ClaudeStatus.StatusMessage; message != "" {
+			blocker += ": " + message
+		}
+		blockers = append(blockers, blocker)
+	}
+	if hint := operations.WorktreeHint(session); hint != "" {
+		blockers = append(blockers, hint)
+	}
+	if session.GitStatus.HasConflicts() {
+		blockers = append(blockers, fmt.Sprintf("Conflicts in %d file(s)", len(session.GitStatus.ConflictedFiles)))
+	}
+	if warning := operations.ConflictWarning(session); warning != "" {
+		blockers = append(blockers, warning)
+	}
+	if followUp := session.Core.FollowUp; followUp != nil && followUp.Result != nil && !followUp.Succeeded() {
+		blockers = append(blockers, fmt.Sprintf("Follow-up `%s` failed: %s", followUp.Command, followUp.Summary()))
+	}
+	if exit := session.Exit; exit != nil && !session.IsAlive {
+		blockers = append(blockers, "Claude exited: "+exit.Summary())
+	}
+	return blockers
+}
+
+// writeStandup writes the standup report in markdown: a section for each
+// session active since the start of the period, then the quiet ones by name
+func writeStandup(out io.Writer, entries []standupEntry, since time.Time) {
+	formatter := operations.NewStatusFormat()
+	fmt.Fprintf(out, "# Standup: %s\n\n", time.Now().Format("Monday, January 2"))
+	fmt.Fprintf(out, "_Since %s_\n", since.Format("Mon Jan 2 15:04"))
+
+	var quiet []string
+	for _, entry := range entries {
+		session := entry.Session
+		if !entry.active(since) {
+			quiet = append(quiet, session.Core.Name)
+			continue
+		}
+
+		fmt.Fprintf(out, "\n## %s\n\n", session.Core.Name)
+		if session.Core.Task != "" {
+			fmt.Fprintf(out, "- **Task:** %s\n", strings.SplitN(session.Core.Task, "\n", 2)[0])
+		}
+		fmt.Fprintf(out, "- **State:** Claude %s; git %s; %s\n",
+			formatter.FormatClaudeStatus(session.ClaudeStatus),
+			formatter.FormatGitStatus(session.GitStatus),
+			formatter.FormatBranchSync(session))
+
+		if len(entry.Commits) > 0 {
+			fmt.Fprintf(out, "- **Commits:**\n")
+			for _, commit := range entry.Commits {
+				fmt.Fprintf(out, "  - `%s` %s\n", shortCommit(commit.Hash), commit.Subject)
+			}
+		}
+		if len(entry.Events) > 0 {
+			fmt.Fprintf(out, "- **Done:**\n")
+			for _, event := range entry.Events {
+				detail, _, _ := strings.Cut(event.Message, "\n")
+				if detail == "" {
+					detail = event.Type
+				}
+				fmt.Fprintf(out, "  - %s (%s)\n", detail, event.Time.Local().Format("Mon 15:04"))
+			}
+		}
+		if entry.Summary != "" {
+			fmt.Fprintf(out, "- **Summary:** %s\n", entry.Summary)
+		}
+		if blockers := standupBlockers(session); len(blockers) > 0 {
+			fmt.Fprintf(out, "- **Blockers:**\n")
+			for _, blocker := range blockers {
+				fmt.Fprintf(out, "  - %s\n", blocker)
+			}
+		}
+	}
+
+	if len(quiet) == len(entries) {
+		fmt.Fprintf(out, "\nNo session activity in this period.\n")
+	}
+	if len(quiet) > 0 {
+		fmt.Fprintf(out, "\n_No activity: %s_\n", strings.Join(quiet, ", "))
+	}
+}
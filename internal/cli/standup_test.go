@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 20, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"today", time.Date(2024, 5, 20, 0, 0, 0, 0, time.Local)},
+		{"yesterday", time.Date(2024, 5, 19, 0, 0, 0, 0, time.Local)},
+		{"3d", now.AddDate(0, 0, -3)},
+		{"36h", now.Add(-36 * time.Hour)},
+		{"2024-05-17", time.Date(2024, 5, 17, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "last week", "-2h", "xd"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q) should fail", value)
+		}
+	}
+}
+
+func TestWriteStandup(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+	old := since.Add(-time.Hour)
+	entries := []standupEntry{
+		{
+			Session: types.Session{
+				Core:         types.CoreSession{Name: "auth", Task: "Add login\nwith details", CreatedAt: old},
+				ClaudeStatus: types.ClaudeStatus{State: types.ClaudeWaiting, StatusMessage: "Allow Bash(rm -rf build)?"},
+				BaseBranch:   "main",
+				GitStatus:    types.GitStatus{CommitCount: 1},
+				LastActivity: time.Now(),
+			},
+			Commits: []types.CommitOutput{{Hash: "0123456789abcdef", Subject: "Add login form"}},
+			Events:  []types.SessionEvent{{Type: types.EventPublished, Message: "Pushed auth to origin", Time: time.Now()}},
+			Summary: "Login works; tests pass.",
+		},
+		{
+			Session: types.Session{Core: types.CoreSession{Name: "stale", CreatedAt: old}, LastActivity: old},
+		},
+	}
+
+	var out bytes.Buffer
+	writeStandup(&out, entries, since)
+	report := out.String()
+	for _, want := range []string{
+		"## auth",
+		"- **Task:** Add login\n",
+		"  - `0123456` Add login form",
+		"  - Pushed auth to origin (",
+		"- **Summary:** Login works; tests pass.",
+		"  - Claude is waiting for input: Allow Bash(rm -rf build)?",
+		"_No activity: stale_",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report = %q, want %q", report, want)
+		}
+	}
+	if strings.Contains(report, "## stale") {
+		t.Error("a session with no activity should only be named")
+	}
+}
@@ -2,8 +2,9 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/profile"
 	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
@@ -19,6 +21,8 @@ import (
 func newStatusCmd() *cobra.Command {
 	var summary bool
 	var branch bool
+	var jsonOutput bool
+	var watch bool
 
 	cmd := &cobra.Command{
 		Use:   "status",
@@ -29,25 +33,44 @@ This command provides detailed information about:
 - Session states and activity
 - Git changes and commit counts  
 - Branch relationships and merge status
+- Pull requests: open or merged, their review and CI checks
 - Overall project health
 
 Examples:
   cwt status               # Detailed status for all sessions
   cwt status --summary     # Summary view with statistics
-  cwt status --branch      # Include branch relationship info`,
+  cwt status --branch      # Include branch relationship info
+  cwt status --json        # Machine-readable output for scripts
+  cwt status --watch       # Keep the detailed status up to date; enter expands a session`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if watch && (jsonOutput || summary) {
+				return fmt.Errorf("--watch can't be combined with --json or --summary")
+			}
+
 			sm, err := createStateManager()
 			if err != nil {
 				return err
 			}
 			defer sm.Close()
 
+			// Pull requests the daemon hasn't checked lately are checked now
+			sm.RefreshPullRequests(state.PullRequestCheckInterval)
+
+			if jsonOutput {
+				return showStatusJSON(sm, summary)
+			}
+			if watch {
+				return watchStatus(sm, branch)
+			}
+
 			return showEnhancedStatus(sm, summary, branch)
 		},
 	}
 
 	cmd.Flags().BoolVar(&summary, "summary", false, "Show summary of all changes across sessions")
 	cmd.Flags().BoolVar(&branch, "branch", false, "Include branch relationship information")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output status as JSON")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Keep the status up to date as sessions change")
 
 	return cmd
 }
@@ -70,63 +93,173 @@ func showEnhancedStatus(sm *state.Manager, summary, showBranch bool) error {
 		return sessions[i].LastActivity.After(sessions[j].LastActivity)
 	})
 
+	expirations, err := planExpirations(sm, sessions)
+	if err != nil {
+		return err
+	}
+
 	if summary {
-		return showStatusSummary(sessions)
+		return showStatusSummary(sessions, sessionUsage(sm, sessions), sm.Limits(), expirations)
 	}
 
-	return showDetailedStatus(sessions, showBranch)
+	return showDetailedStatus(sessions, showBranch, expirations)
 }
 
-// showStatusSummary shows a high-level summary of all sessions
-func showStatusSummary(sessions []types.Session) error {
-	formatter := operations.NewStatusFormat()
-	fmt.Println("📊 Session Summary")
-	fmt.Println(strings.Repeat("=", 50))
+// planExpirations returns the sessions and archived sessions the expiry
+// policy acts on now or soon, soonest first
+func planExpirations(sm *state.Manager, sessions []types.Session) ([]operations.Expiration, error) {
+	policy := expiryPolicy(appConfig)
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	archived, err := sm.ArchivedSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	due, upcoming := policy.Plan(sessions, archived, time.Now())
+	return append(due, upcoming...), nil
+}
+
+// showStatusJSON emits session status as a versioned JSON document
+func showStatusJSON(sm *state.Manager, summary bool) error {
+	sessions, err := sm.DeriveFreshSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	// Sort sessions by last activity (most recent first)
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActivity.After(sessions[j].LastActivity)
+	})
+
+	output := types.NewSessionListOutput(sessions)
+	if summary {
+		usage := sessionUsage(sm, sessions)
+		for i, session := range sessions {
+			used := usage[session.Core.ID]
+			output.Sessions[i].Usage = &used
+		}
+		stats := calculateStatusSummary(sessions, usage, sm.Limits())
+		output.Summary = &stats
+	}
+
+	return writeJSON(output)
+}
 
-	// Calculate statistics
-	var alive, dead, hasChanges, published, merged int
-	var totalModified, totalAdded, totalDeleted int
+// calculateStatusSummary aggregates statistics across all sessions, with
+// what their agents used by session ID
+func calculateStatusSummary(sessions []types.Session, usage map[string]types.Usage, limits state.Limits) types.StatusSummaryOutput {
+	stats := types.StatusSummaryOutput{Total: len(sessions)}
 
 	for _, session := range sessions {
+		used := usage[session.Core.ID]
+		stats.Usage = stats.Usage.Add(used)
+		if limits.OverCost(used) {
+			stats.OverCost++
+		}
+
 		if session.IsAlive {
-			alive++
+			stats.Active++
 		} else {
-			dead++
+			stats.Inactive++
 		}
 
-		if session.GitStatus.HasChanges {
-			hasChanges++
-			totalModified += len(session.GitStatus.ModifiedFiles)
-			totalAdded += len(session.GitStatus.AddedFiles)
-			totalDeleted += len(session.GitStatus.DeletedFiles)
+		if session.GitStatus.HasConflicts() {
+			stats.WithConflicts++
+		}
+		if session.GitStatus.WorktreeBroken() {
+			stats.Broken++
+		}
+		if session.GitStatus.BehindCount > 0 && !session.GitStatus.HasError() {
+			stats.Behind++
+		}
+		if len(session.GitStatus.BaseConflicts) > 0 {
+			stats.WillConflict++
+		}
+		if session.ChangedSinceReview {
+			stats.ChangedSinceReview++
+		}
+		if session.GitStatus.HasError() {
+			stats.GitErrors++
+		} else if session.GitStatus.HasChanges {
+			stats.WithChanges++
+			stats.ModifiedFiles += len(session.GitStatus.ModifiedFiles)
+			stats.AddedFiles += len(session.GitStatus.AddedFiles)
+			stats.DeletedFiles += len(session.GitStatus.DeletedFiles)
 		}
 
 		// Check if published (has remote tracking)
 		if isSessionPublished(session) {
-			published++
+			stats.Published++
 		}
 
-		// Check if merged (would need additional logic)
+		// Check if its pull request was merged
 		if isSessionMerged(session) {
-			merged++
+			stats.Merged++
 		}
 	}
 
+	stats.Clean = stats.Total - stats.WithChanges - stats.GitErrors
+	return stats
+}
+
+// showStatusSummary shows a high-level summary of all sessions
+func showStatusSummary(sessions []types.Session, usage map[string]types.Usage, limits state.Limits, expirations []operations.Expiration) error {
+	defer profiler.Track(profile.PhaseRender)()
+
+	formatter := operations.NewStatusFormat()
+	fmt.Println("📊 Session Summary")
+	fmt.Println(strings.Repeat("=", 50))
+
+	stats := calculateStatusSummary(sessions, usage, limits)
+
 	// Display statistics
-	fmt.Printf("Total Sessions:    %d\n", len(sessions))
-	fmt.Printf("  • Active:        %d\n", alive)
-	fmt.Printf("  • Inactive:      %d\n", dead)
+	fmt.Printf("Total Sessions:    %d\n", stats.Total)
+	fmt.Printf("  • Active:        %d\n", stats.Active)
+	fmt.Printf("  • Inactive:      %d\n", stats.Inactive)
 	fmt.Printf("\n")
 	fmt.Printf("Change Summary:\n")
-	fmt.Printf("  • With Changes:  %d\n", hasChanges)
-	fmt.Printf("  • Clean:         %d\n", len(sessions)-hasChanges)
-	fmt.Printf("  • Published:     %d\n", published)
-	fmt.Printf("  • Merged:        %d\n", merged)
+	fmt.Printf("  • With Changes:  %d\n", stats.WithChanges)
+	fmt.Printf("  • Clean:         %d\n", stats.Clean)
+	if stats.GitErrors > 0 {
+		fmt.Printf("  • Git Errors:    %d\n", stats.GitErrors)
+	}
+	if stats.WithConflicts > 0 {
+		fmt.Printf("  • ⚠ Conflicts:   %d\n", stats.WithConflicts)
+	}
+	if stats.Broken > 0 {
+		fmt.Printf("  • 💔 Broken:      %d (repair with: cwt repair --all)\n", stats.Broken)
+	}
+	if stats.Behind > 0 {
+		fmt.Printf("  • ⬇️  Behind base: %d (rebase with: cwt rebase <session>)\n", stats.Behind)
+	}
+	if stats.WillConflict > 0 {
+		fmt.Printf("  • ⚠️  Will conflict: %d (with the base branch if merged now)\n", stats.WillConflict)
+	}
+	if stats.ChangedSinceReview > 0 {
+		fmt.Printf("  • 🆕 New since review: %d (see with: cwt diff <session> --since-review)\n", stats.ChangedSinceReview)
+	}
+	fmt.Printf("  • Published:     %d\n", stats.Published)
+	fmt.Printf("  • Merged:        %d\n", stats.Merged)
 	fmt.Printf("\n")
 	fmt.Printf("File Changes:\n")
-	fmt.Printf("  • Modified:      %d\n", totalModified)
-	fmt.Printf("  • Added:         %d\n", totalAdded)
-	fmt.Printf("  • Deleted:       %d\n", totalDeleted)
+	fmt.Printf("  • Modified:      %d\n", stats.ModifiedFiles)
+	fmt.Printf("  • Added:         %d\n", stats.AddedFiles)
+	fmt.Printf("  • Deleted:       %d\n", stats.DeletedFiles)
+
+	if !stats.Usage.Empty() {
+		fmt.Printf("\n")
+		fmt.Printf("Agent Usage:\n")
+		fmt.Printf("  • Used:          %s\n", formatter.FormatUsage(stats.Usage))
+		if stats.OverCost > 0 {
+			fmt.Printf("  • ⚠️  Over cost:   %d (more than %s each, limits.session_cost)\n", stats.OverCost, types.FormatCost(limits.SessionCost))
+		}
+		if spenders := topSpenders(sessions, usage, 3); len(spenders) > 0 {
+			fmt.Printf("  • Most costly:   %s\n", strings.Join(spenders, ", "))
+		}
+	}
 
 	// Show most recent activity
 	if len(sessions) > 0 {
@@ -140,32 +273,89 @@ func showStatusSummary(sessions []types.Session) error {
 		}
 	}
 
+	if len(expirations) > 0 {
+		now := time.Now()
+		fmt.Printf("\n")
+		fmt.Printf("Expiring Soon:\n")
+		for _, expiration := range expirations {
+			fmt.Printf("  • %s: %s\n", expiration.Name, expiration.Describe(now))
+		}
+	}
+
 	return nil
 }
 
+// topSpenders describes the sessions estimated to cost the most, up to n
+func topSpenders(sessions []types.Session, usage map[string]types.Usage, n int) []string {
+	spenders := slices.Clone(sessions)
+	spenders = slices.DeleteFunc(spenders, func(session types.Session) bool {
+		return usage[session.Core.ID].Cost <= 0
+	})
+	sort.SliceStable(spenders, func(i, j int) bool {
+		return usage[spenders[i].Core.ID].Cost > usage[spenders[j].Core.ID].Cost
+	})
+	var lines []string
+	for _, session := range spenders[:min(n, len(spenders))] {
+		lines = append(lines, fmt.Sprintf("%s %s", session.Core.Name, types.FormatCost(usage[session.Core.ID].Cost)))
+	}
+	return lines
+}
+
 // showDetailedStatus shows detailed information for each session
-func showDetailedStatus(sessions []types.Session, showBranch bool) error {
+func showDetailedStatus(sessions []types.Session, showBranch bool, expirations []operations.Expiration) error {
+	defer profiler.Track(profile.PhaseRender)()
+
 	fmt.Printf("📋 Session Status (%d sessions)\n", len(sessions))
 	fmt.Println(strings.Repeat("=", 70))
 
+	archiving, deleting := splitExpirations(expirations)
+
 	for i, session := range sessions {
 		if i > 0 {
 			fmt.Println()
 		}
 
-		renderSessionStatus(session, showBranch)
+		renderSessionStatus(session, showBranch, archiving[session.Core.ID])
+	}
+
+	if len(deleting) > 0 {
+		now := time.Now()
+		fmt.Println()
+		fmt.Println("🗄️  Archived sessions expiring soon:")
+		for _, expiration := range deleting {
+			fmt.Printf("   • %s: %s\n", expiration.Name, expiration.Describe(now))
+		}
 	}
 
 	return nil
 }
 
-// renderSessionStatus renders detailed status for a single session
-func renderSessionStatus(session types.Session, showBranch bool) {
-	formatter := operations.NewStatusFormat()
-	// Session header
-	fmt.Printf("🏷️  %s", session.Core.Name)
+// splitExpirations separates the sessions about to be archived, by session
+// ID, from the archived sessions about to be deleted. Archived sessions
+// aren't listed, so their deletions are shown at the end.
+func splitExpirations(expirations []operations.Expiration) (map[string]*operations.Expiration, []operations.Expiration) {
+	archiving := make(map[string]*operations.Expiration)
+	var deleting []operations.Expiration
+	for i, expiration := range expirations {
+		if expiration.Action == operations.ExpiryArchive {
+			archiving[expiration.SessionID] = &expirations[i]
+		} else {
+			deleting = append(deleting, expiration)
+		}
+	}
+	return archiving, deleting
+}
 
-	// Show main status indicators
+// renderSessionStatus renders detailed status for a single session, flagging
+// it when the expiry policy will soon archive it
+func renderSessionStatus(session types.Session, showBranch bool, expiration *operations.Expiration) {
+	fmt.Println(sessionStatusHeader(session))
+	writeSessionDetails(os.Stdout, session, showBranch, expiration)
+}
+
+// sessionStatusHeader is the line naming a session, with its main status
+// indicators
+func sessionStatusHeader(session types.Session) string {
 	statusIndicators := []string{}
 
 	if session.IsAlive {
@@ -174,7 +364,13 @@ func renderSessionStatus(session types.Session, showBranch bool) {
 		statusIndicators = append(statusIndicators, "🔴 inactive")
 	}
 
-	if session.GitStatus.HasChanges {
+	if session.GitStatus.WorktreeBroken() {
+		statusIndicators = append(statusIndicators, "💔 broken")
+	} else if session.GitStatus.HasError() {
+		statusIndicators = append(statusIndicators, "❌ git error")
+	} else if session.GitStatus.HasConflicts() {
+		statusIndicators = append(statusIndicators, "⚠ conflicts")
+	} else if session.GitStatus.HasChanges {
 		changeCount := len(session.GitStatus.ModifiedFiles) + len(session.GitStatus.AddedFiles) + len(session.GitStatus.DeletedFiles)
 		statusIndicators = append(statusIndicators, fmt.Sprintf("📝 %d changes", changeCount))
 	} else {
@@ -185,65 +381,141 @@ func renderSessionStatus(session types.Session, showBranch bool) {
 		statusIndicators = append(statusIndicators, "📤 published")
 	}
 
-	fmt.Printf(" (%s)\n", strings.Join(statusIndicators, ", "))
+	return fmt.Sprintf("🏷️  %s (%s)", session.Core.Name, strings.Join(statusIndicators, ", "))
+}
+
+// writeSessionDetails writes the lines under a session's header: its task,
+// activity, Claude and git status, pull request and path
+func writeSessionDetails(w io.Writer, session types.Session, showBranch bool, expiration *operations.Expiration) {
+	formatter := operations.NewStatusFormat()
+
+	// Show the task the session was started with
+	if session.Core.Task != "" {
+		fmt.Fprintf(w, "   🎯 Task: %s\n", strings.SplitN(session.Core.Task, "\n", 2)[0])
+	}
+	if session.Core.Source != "" {
+		fmt.Fprintf(w, "   🔗 Source: %s\n", session.Core.Source)
+	}
+	if session.Core.CreatedBy != "" {
+		fmt.Fprintf(w, "   👤 Created by: %s\n", session.Core.CreatedBy)
+	}
 
 	// Show activity timing
-	fmt.Printf("   ⏰ Last activity: %s\n", formatter.FormatActivity(session.LastActivity))
+	fmt.Fprintf(w, "   ⏰ Last activity: %s\n", formatter.FormatActivity(session.LastActivity))
+	if exit := session.Exit; exit != nil {
+		fmt.Fprintf(w, "   💀 Exited: %s, %s\n", exit.Summary(), formatter.FormatActivity(exit.Time))
+	}
+	if expiration != nil {
+		fmt.Fprintf(w, "   ⏳ Expires: %s\n", expiration.Describe(time.Now()))
+	}
 
 	// Show Claude status
 	claudeIcon := getClaudeIcon(session.ClaudeStatus.State)
-	fmt.Printf("   %s Claude: %s", claudeIcon, string(session.ClaudeStatus.State))
+	fmt.Fprintf(w, "   %s Claude: %s", claudeIcon, string(session.ClaudeStatus.State))
 
 	if session.ClaudeStatus.StatusMessage != "" {
-		fmt.Printf(" - %s", session.ClaudeStatus.StatusMessage)
+		fmt.Fprintf(w, " - %s", session.ClaudeStatus.StatusMessage)
 	}
 
 	if !session.ClaudeStatus.LastMessage.IsZero() {
 		age := time.Since(session.ClaudeStatus.LastMessage)
-		fmt.Printf(" (last: %s ago)", formatter.FormatDuration(age))
+		fmt.Fprintf(w, " (last: %s ago)", formatter.FormatDuration(age))
+	}
+	fmt.Fprintln(w)
+	if hint := operations.ClaudeHint(session); hint != "" {
+		fmt.Fprintf(w, "   💡 %s\n", hint)
 	}
-	fmt.Println()
 
 	// Show detailed git status
-	if session.GitStatus.HasChanges {
-		fmt.Printf("   📁 Git changes:\n")
+	if hint := operations.WorktreeHint(session); hint != "" {
+		fmt.Fprintf(w, "   💔 %s\n", session.GitStatus.Error)
+		fmt.Fprintf(w, "   💡 %s\n", hint)
+	} else if session.GitStatus.HasError() {
+		fmt.Fprintf(w, "   ❌ Git error: %s\n", session.GitStatus.Error)
+	} else if session.GitStatus.HasChanges {
+		fmt.Fprintf(w, "   📁 Git changes:\n")
+
+		if len(session.GitStatus.ConflictedFiles) > 0 {
+			fmt.Fprintf(w, "      ⚠ Conflicts: %s\n",
+				formatFileList(conflictDescriptions(session.GitStatus.ConflictedFiles), 3))
+		}
 
 		if len(session.GitStatus.ModifiedFiles) > 0 {
-			fmt.Printf("      📝 Modified: %s\n",
+			fmt.Fprintf(w, "      📝 Modified: %s\n",
 				formatFileList(session.GitStatus.ModifiedFiles, 3))
 		}
 
 		if len(session.GitStatus.AddedFiles) > 0 {
-			fmt.Printf("      ➕ Added: %s\n",
+			fmt.Fprintf(w, "      ➕ Added: %s\n",
 				formatFileList(session.GitStatus.AddedFiles, 3))
 		}
 
 		if len(session.GitStatus.DeletedFiles) > 0 {
-			fmt.Printf("      ➖ Deleted: %s\n",
+			fmt.Fprintf(w, "      ➖ Deleted: %s\n",
 				formatFileList(session.GitStatus.DeletedFiles, 3))
 		}
 
 		if len(session.GitStatus.UntrackedFiles) > 0 {
-			fmt.Printf("      ❓ Untracked: %s\n",
+			fmt.Fprintf(w, "      ❓ Untracked: %s\n",
 				formatFileList(session.GitStatus.UntrackedFiles, 3))
 		}
+
+		if len(session.GitStatus.StagedFiles) > 0 {
+			fmt.Fprintf(w, "      📥 Staged: %s\n",
+				formatFileList(session.GitStatus.StagedFiles, 3))
+		}
+
+		if len(session.GitStatus.UnstagedFiles) > 0 {
+			fmt.Fprintf(w, "      ✏️  Unstaged: %s\n",
+				formatFileList(session.GitStatus.UnstagedFiles, 3))
+		}
+	}
+
+	// Show how far the branch is from its base and upstream
+	if git := session.GitStatus; git.CommitCount > 0 || git.BehindCount > 0 || git.Upstream != "" {
+		fmt.Fprintf(w, "   📊 Commits: %s\n", formatter.FormatBranchSync(session))
+	}
+	if hint := operations.RebaseHint(session); hint != "" {
+		fmt.Fprintf(w, "   💡 %s\n", hint)
+	}
+	if warning := operations.ConflictWarning(session); warning != "" {
+		fmt.Fprintf(w, "   ⚠️  %s\n", warning)
+	}
+	if hint := operations.ReviewHint(session); hint != "" {
+		fmt.Fprintf(w, "   🆕 %s\n", hint)
+	}
+	if hint := operations.PublishHint(session); hint != "" {
+		fmt.Fprintf(w, "   ⚠️  %s\n", hint)
+	}
+	if coverage := formatter.FormatCoverage(session); coverage != "" {
+		fmt.Fprintf(w, "   🧪 Coverage: %s\n", coverage)
+	}
+	if pr := session.Core.PullRequest; pr != nil {
+		fmt.Fprintf(w, "   🔀 Pull request: %s\n", formatter.FormatPullRequest(*pr))
+		fmt.Fprintf(w, "      %s", pr.URL)
+		if pr.CheckedAt != nil {
+			fmt.Fprintf(w, " (checked %s)", formatter.FormatActivity(*pr.CheckedAt))
+		}
+		fmt.Fprintln(w)
+		if pr.Error != "" {
+			fmt.Fprintf(w, "      ⚠️  Couldn't check it: %s\n", pr.Error)
+		}
 	}
 
-	// Show commit count if available
-	if session.GitStatus.CommitCount > 0 {
-		fmt.Printf("   📊 Commits ahead: %d\n", session.GitStatus.CommitCount)
+	// Show fields added by status providers
+	for _, field := range session.Extra {
+		fmt.Fprintf(w, "   🔌 %s: %s\n", field.Label(), field.Value)
 	}
 
 	// Show branch information if requested
 	if showBranch {
-		branchName := fmt.Sprintf("cwt-%s", session.Core.Name)
-		if branchInfo := getBranchInfo(session.Core.WorktreePath, branchName); branchInfo != "" {
-			fmt.Printf("   🌿 Branch: %s\n", branchInfo)
+		if branchInfo := getBranchInfo(session); branchInfo != "" {
+			fmt.Fprintf(w, "   🌿 Branch: %s\n", branchInfo)
 		}
 	}
 
 	// Show path for easy access
-	fmt.Printf("   📂 Path: %s\n", session.Core.WorktreePath)
+	fmt.Fprintf(w, "   📂 Path: %s\n", session.Core.WorktreePath)
 }
 
 // Helper functions
@@ -258,6 +530,10 @@ func getClaudeIcon(state types.ClaudeState) string {
 		return "✅"
 	case types.ClaudeIdle:
 		return "💤"
+	case types.ClaudeNotStarted:
+		return "⚪"
+	case types.ClaudeNoAgent:
+		return "🚫"
 	default:
 		return "❓"
 	}
@@ -277,60 +553,49 @@ func formatFileList(files []string, maxShow int) string {
 	return fmt.Sprintf("%s... (+%d more)", strings.Join(shown, ", "), remaining)
 }
 
-func isSessionPublished(session types.Session) bool {
-	// This is a simplified check - in a full implementation,
-	// you'd check if the branch has been pushed to remote
-	branchName := fmt.Sprintf("cwt-%s", session.Core.Name)
-
-	// Change to worktree directory to check remote tracking
-	originalDir, err := os.Getwd()
-	if err != nil {
-		return false
-	}
-	defer os.Chdir(originalDir)
-
-	if err := os.Chdir(session.Core.WorktreePath); err != nil {
-		return false
+// conflictDescriptions describes each conflicted file and its conflict
+func conflictDescriptions(files []types.ConflictedFile) []string {
+	descriptions := make([]string, len(files))
+	for i, file := range files {
+		descriptions[i] = file.String()
 	}
+	return descriptions
+}
 
-	// Check if branch has remote tracking
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", fmt.Sprintf("%s@{upstream}", branchName))
-	return cmd.Run() == nil
+// isSessionPublished reports whether the session's branch tracks a remote
+// branch, which 'cwt publish' sets up when it pushes
+func isSessionPublished(session types.Session) bool {
+	return session.GitStatus.Upstream != ""
 }
 
+// isSessionMerged reports whether the pull request the session was
+// published to got merged
 func isSessionMerged(session types.Session) bool {
-	// This is a simplified check - in a full implementation,
-	// you'd check if the session branch has been merged into main
-	return false // Placeholder for now
+	pr := session.Core.PullRequest
+	return pr != nil && pr.Status.State == types.PullRequestMerged
 }
 
-func getBranchInfo(worktreePath, branchName string) string {
-	// Change to worktree directory
-	originalDir, err := os.Getwd()
-	if err != nil {
-		return ""
+// getBranchInfo describes a session's branch, its base branch, how far it
+// is behind and ahead of the base and the upstream it tracks
+func getBranchInfo(session types.Session) string {
+	info := formatBranch(session)
+	if session.GitStatus.HasError() {
+		return info
 	}
-	defer os.Chdir(originalDir)
 
-	if err := os.Chdir(worktreePath); err != nil {
-		return ""
-	}
-
-	// Get branch status relative to main
-	cmd := exec.Command("git", "rev-list", "--count", "--left-right", "main..."+branchName)
-	output, err := cmd.Output()
-	if err != nil {
-		return branchName
+	info += fmt.Sprintf(" (↓%d ↑%d)", session.GitStatus.BehindCount, session.GitStatus.CommitCount)
+	if session.GitStatus.Upstream != "" {
+		info += ", tracking " + session.GitStatus.Upstream
 	}
+	return info
+}
 
-	parts := strings.Fields(strings.TrimSpace(string(output)))
-	if len(parts) == 2 {
-		behind := parts[0]
-		ahead := parts[1]
-		return fmt.Sprintf("%s (↓%s ↑%s)", branchName, behind, ahead)
+// formatBranch names a session's branch and the base branch it merges into
+func formatBranch(session types.Session) string {
+	if session.BaseBranch == "" {
+		return session.BranchName()
 	}
-
-	return branchName
+	return fmt.Sprintf("%s → %s", session.BranchName(), session.BaseBranch)
 }
 
 // Helper functions are imported from list.go - removed duplicates
@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+	"github.com/mattn/go-isatty"
+
+	"github.com/jlaneve/cwt-cli/internal/daemon"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// statusWatchSettle is how long the data directory has to be quiet before
+// the status is reloaded, so a burst of writes reloads it once
+const statusWatchSettle = 200 * time.Millisecond
+
+// statusWatchModel is 'cwt status --watch': the detailed status, one line a
+// session until expanded, kept up to date as sessions change
+type statusWatchModel struct {
+	sm         *state.Manager
+	showBranch bool
+	watcher    *fsnotify.Watcher // Nil when the data directory can't be watched
+
+	sessions    []types.Session
+	expirations []operations.Expiration
+	updated     time.Time
+	err         error
+
+	cursor   string          // ID of the selected session
+	expanded map[string]bool // By session ID
+	height   int
+}
+
+type (
+	statusWatchLoadedMsg struct {
+		sessions    []types.Session
+		expirations []operations.Expiration
+		err         error
+	}
+	statusWatchChangedMsg  struct{}
+	statusWatchTickMsg     struct{}
+	statusWatchShutdownMsg struct{}
+)
+
+// watchStatus shows the detailed status until the user quits, updating it
+// when the data directory changes, as when Claude's hooks fire or a session
+// is created, and every git poll interval for changes in the worktrees
+func watchStatus(sm *state.Manager, showBranch bool) error {
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return fmt.Errorf("--watch needs a terminal; run cwt status without it")
+	}
+
+	m := statusWatchModel{sm: sm, showBranch: showBranch, expanded: make(map[string]bool)}
+	if watcher, err := watchDataDir(sm.GetDataDir()); err != nil {
+		// Polling still keeps the status fresh
+		fmt.Fprintf(os.Stderr, "Warning: failed to watch for changes: %v\n", err)
+	} else {
+		m.watcher = watcher
+		defer watcher.Close()
+	}
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+// watchDataDir watches the data directory and its session-state directory,
+// where sessions.json and Claude's hook events are written
+func watchDataDir(dataDir string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	stateDir := filepath.Join(dataDir, "session-state")
+	os.MkdirAll(stateDir, 0755)
+	for _, dir := range []string{dataDir, stateDir} {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	return watcher, nil
+}
+
+func (m statusWatchModel) Init() tea.Cmd {
+	return tea.Batch(m.load(), m.waitForChange(), m.tick())
+}
+
+// load derives the sessions, most recently active first
+func (m statusWatchModel) load() tea.Cmd {
+	sm := m.sm
+	return func() tea.Msg {
+		sessions, err := sm.DeriveFreshSessions()
+		if err != nil {
+			return statusWatchLoadedMsg{err: fmt.Errorf("failed to load sessions: %w", err)}
+		}
+		sort.Slice(sessions, func(i, j int) bool {
+			return sessions[i].LastActivity.After(sessions[j].LastActivity)
+		})
+		expirations, err := planExpirations(sm, sessions)
+		return statusWatchLoadedMsg{sessions: sessions, expirations: expirations, err: err}
+	}
+}
+
+// waitForChange waits for a change to the data directory that affects the
+// sessions, then for the directory to settle
+func (m statusWatchModel) waitForChange() tea.Cmd {
+	if m.watcher == nil {
+		return nil
+	}
+	watcher := m.watcher
+	dataDir := m.sm.GetDataDir()
+	return func() tea.Msg {
+		var settle <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return nil
+				}
+				base := filepath.Base(event.Name)
+				if daemon.IgnoredFile(base) {
+					continue
+				}
+				if base == state.RefreshFileName {
+					if signal, err := state.ReadRefreshSignal(dataDir); err == nil && signal != nil && signal.Reason == state.RefreshShutdown {
+						return statusWatchShutdownMsg{}
+					}
+				}
+				settle = time.After(statusWatchSettle)
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return nil
+				}
+			case <-settle:
+				return statusWatchChangedMsg{}
+			}
+		}
+	}
+}
+
+// tick asks for a reload with fresh git status every git poll interval
+func (m statusWatchModel) tick() tea.Cmd {
+	interval := appConfig.Polling.GitInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return statusWatchTickMsg{} })
+}
+
+func (m statusWatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+
+	case statusWatchLoadedMsg:
+		m.err = msg.err
+		if msg.err == nil || msg.sessions != nil {
+			m.sessions = msg.sessions
+			m.expirations = msg.expirations
+			m.updated = time.Now()
+		}
+		if m.index() < 0 && len(m.sessions) > 0 {
+			m.cursor = m.sessions[0].Core.ID
+		}
+		return m, nil
+
+	case statusWatchChangedMsg:
+		return m, tea.Batch(m.load(), m.waitForChange())
+
+	case statusWatchTickMsg:
+		m.sm.InvalidateStatus("")
+		return m, tea.Batch(m.load(), m.tick())
+
+	case statusWatchShutdownMsg:
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m statusWatchModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if i := m.index(); i > 0 {
+			m.cursor = m.sessions[i-1].Core.ID
+		}
+	case "down", "j":
+		if i := m.index(); i >= 0 && i < len(m.sessions)-1 {
+			m.cursor = m.sessions[i+1].Core.ID
+		}
+	case "enter", " ":
+		if m.index() >= 0 {
+			m.expanded[m.cursor] = !m.expanded[m.cursor]
+		}
+	case "e":
+		// Expand all, or collapse all when all are expanded
+		all := true
+		for _, session := range m.sessions {
+			all = all && m.expanded[session.Core.ID]
+		}
+		for _, session := range m.sessions {
+			m.expanded[session.Core.ID] = !all
+		}
+	}
+	return m, nil
+}
+
+// index returns the position of the selected session, or -1
+func (m statusWatchModel) index() int {
+	for i, session := range m.sessions {
+		if session.Core.ID == m.cursor {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m statusWatchModel) View() string {
+	if m.updated.IsZero() && m.err == nil {
+		return "Loading sessions..."
+	}
+
+	var lines []string
+	selected := 0
+	archiving, deleting := splitExpirations(m.expirations)
+	for _, session := range m.sessions {
+		marker := "  "
+		if session.Core.ID == m.cursor {
+			marker = "▶ "
+			selected = len(lines)
+		}
+		lines = append(lines, marker+sessionStatusHeader(session))
+		if m.expanded[session.Core.ID] {
+			var details strings.Builder
+			writeSessionDetails(&details, session, m.showBranch, archiving[session.Core.ID])
+			for _, line := range strings.Split(strings.TrimSuffix(details.String(), "\n"), "\n") {
+				lines = append(lines, "  "+line)
+			}
+		}
+	}
+	if len(m.sessions) == 0 {
+		lines = append(lines, "No sessions found. Create one with: cwt new [session-name]")
+	}
+	if len(deleting) > 0 {
+		now := time.Now()
+		lines = append(lines, "", "🗄️  Archived sessions expiring soon:")
+		for _, expiration := range deleting {
+			lines = append(lines, fmt.Sprintf("   • %s: %s", expiration.Name, expiration.Describe(now)))
+		}
+	}
+
+	header := []string{
+		fmt.Sprintf("📋 Session Status (%d sessions) · updated %s", len(m.sessions), m.updated.Format("15:04:05")),
+		strings.Repeat("=", 70),
+	}
+	footer := []string{"", "↑/↓ move · enter expand · e expand all · q quit"}
+	if m.err != nil {
+		footer = append([]string{"", "❌ " + m.err.Error()}, footer...)
+	}
+
+	// Scroll so the selected session stays in view
+	if room := m.height - len(header) - len(footer); m.height > 0 && len(lines) > room && room > 0 {
+		start := 0
+		if selected >= room {
+			start = selected - room + 1
+		}
+		lines = lines[start:min(start+room, len(lines))]
+	}
+
+	return strings.Join(append(append(header, lines...), footer...), "\n")
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestStatusWatchModel(t *testing.T) {
+	var sessions []types.Session
+	for _, name := range []string{"auth", "docs", "api"} {
+		sessions = append(sessions, types.Session{Core: types.CoreSession{
+			ID:           "id-" + name,
+			Name:         name,
+			WorktreePath: "/worktrees/" + name,
+			Task:         "work on " + name,
+		}})
+	}
+
+	var model tea.Model = statusWatchModel{expanded: make(map[string]bool)}
+	model, _ = model.Update(statusWatchLoadedMsg{sessions: sessions})
+	press := func(key string) {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		model, _ = model.Update(msg)
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "▶ 🏷️  auth") || strings.Contains(view, "work on") {
+		t.Errorf("sessions should start collapsed with the first selected:\n%s", view)
+	}
+
+	press("down")
+	press("enter")
+	view = model.View()
+	if !strings.Contains(view, "▶ 🏷️  docs") || !strings.Contains(view, "🎯 Task: work on docs") ||
+		strings.Contains(view, "work on auth") {
+		t.Errorf("only docs should be expanded:\n%s", view)
+	}
+
+	// The selection follows the session when the order changes
+	model, _ = model.Update(statusWatchLoadedMsg{sessions: []types.Session{sessions[2], sessions[1]}})
+	if m := model.(statusWatchModel); m.cursor != "id-docs" {
+		t.Errorf("cursor = %q, want id-docs", m.cursor)
+	}
+
+	press("e")
+	view = model.View()
+	if !strings.Contains(view, "work on api") || !strings.Contains(view, "work on docs") {
+		t.Errorf("e should expand every session:\n%s", view)
+	}
+
+	// A short terminal scrolls to keep the selection in view
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+	view = model.View()
+	if !strings.Contains(view, "▶ 🏷️  docs") || len(strings.Split(view, "\n")) > 8 {
+		t.Errorf("the view should fit the terminal and show the selection:\n%s", view)
+	}
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("q should quit")
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/operations"
 	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
@@ -18,6 +20,8 @@ import (
 // newSwitchCmd creates the 'cwt switch' command
 func newSwitchCmd() *cobra.Command {
 	var back bool
+	var dryRun bool
+	var confirmToken string
 
 	cmd := &cobra.Command{
 		Use:   "switch [session-name]",
@@ -28,8 +32,10 @@ This allows you to test changes or do manual work on the session branch.
 Examples:
   cwt switch my-session     # Switch to my-session branch
   cwt switch --back         # Return to previous branch
-  cwt switch                # Interactive session selector`,
-		Args: cobra.MaximumNArgs(1),
+  cwt switch                # Interactive session selector
+  cwt switch my-session --dry-run  # Show the switch (and its token in protected mode)`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sm, err := createStateManager()
 			if err != nil {
@@ -37,26 +43,60 @@ Examples:
 			}
 			defer sm.Close()
 
+			opts := switchOptions{dryRun: dryRun, confirmToken: confirmToken}
 			if back {
-				return switchBack()
+				switched, err := switchBack(opts)
+				if err != nil {
+					return err
+				}
+				if switched {
+					sm.NotifyRefresh("", "switch")
+				}
+				return nil
 			}
 
 			if len(args) == 0 {
-				return interactiveSwitch(sm)
+				return interactiveSwitch(sm, opts)
 			}
 
 			sessionName := args[0]
-			return switchToSession(sm, sessionName)
+			return switchToSession(sm, sessionName, opts)
 		},
 	}
 
 	cmd.Flags().BoolVar(&back, "back", false, "Return to previous branch")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the switch without checking anything out")
+	cmd.Flags().StringVar(&confirmToken, "confirm", "", "Confirmation token printed by --dry-run in protected mode")
 
 	return cmd
 }
 
+// switchOptions controls how a switch is previewed and confirmed
+type switchOptions struct {
+	dryRun       bool
+	confirmToken string
+}
+
+// confirmSwitch previews a checkout on a dry run, and otherwise asks for
+// confirmation in protected mode. It reports whether to go ahead.
+func confirmSwitch(from, to, command string, opts switchOptions) (bool, error) {
+	op := operations.SwitchOperation(to)
+
+	if opts.dryRun {
+		fmt.Printf("Would switch from %s to %s\n", from, to)
+		fmt.Println("Dry run completed. No changes were made.")
+		printConfirmationToken(os.Stdout, op, command)
+		return false, nil
+	}
+
+	if _, err := confirmProtected(op, opts.confirmToken, command+" --dry-run"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // switchToSession switches to a session's branch
-func switchToSession(sm *state.Manager, sessionName string) error {
+func switchToSession(sm *state.Manager, sessionName string, opts switchOptions) error {
 	sessions, err := sm.DeriveFreshSessions()
 	if err != nil {
 		return fmt.Errorf("failed to load sessions: %w", err)
@@ -81,6 +121,12 @@ func switchToSession(sm *state.Manager, sessionName string) error {
 		return fmt.Errorf("failed to get current branch: %w", err)
 	}
 
+	sessionBranch := targetSession.BranchName()
+	proceed, err := confirmSwitch(currentBranch, sessionBranch, "cwt switch "+sessionName, opts)
+	if err != nil || !proceed {
+		return err
+	}
+
 	// Check for uncommitted changes and handle them interactively
 	if hasUncommittedChanges() {
 		if err := handleUncommittedChanges(); err != nil {
@@ -94,7 +140,6 @@ func switchToSession(sm *state.Manager, sessionName string) error {
 	}
 
 	// Switch to session branch
-	sessionBranch := fmt.Sprintf("cwt-%s", sessionName)
 	if err := switchBranch(sessionBranch); err != nil {
 		return fmt.Errorf("failed to switch to branch '%s': %w", sessionBranch, err)
 	}
@@ -102,23 +147,35 @@ func switchToSession(sm *state.Manager, sessionName string) error {
 	fmt.Printf("Switched to session branch: %s\n", sessionBranch)
 	fmt.Printf("Use 'cwt switch --back' to return to %s\n", currentBranch)
 
+	// Let running TUIs pick up the branch switch immediately
+	sm.NotifyRefresh(sessionName, "switch")
+
 	return nil
 }
 
-// switchBack returns to the previous branch
-func switchBack() error {
+// switchBack returns to the previous branch, reporting whether it switched
+func switchBack(opts switchOptions) (bool, error) {
 	previousBranch, err := loadPreviousBranch()
 	if err != nil {
-		return fmt.Errorf("no previous branch saved: %w", err)
+		return false, fmt.Errorf("no previous branch saved: %w", err)
+	}
+
+	currentBranch, err := getCurrentBranch()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	proceed, err := confirmSwitch(currentBranch, previousBranch, "cwt switch --back", opts)
+	if err != nil || !proceed {
+		return false, err
 	}
 
 	// Check for uncommitted changes
 	if hasUncommittedChanges() {
-		return fmt.Errorf("cannot switch: you have uncommitted changes. Please commit or stash them first")
+		return false, fmt.Errorf("cannot switch: you have uncommitted changes. Please commit or stash them first")
 	}
 
 	if err := switchBranch(previousBranch); err != nil {
-		return fmt.Errorf("failed to switch back to '%s': %w", previousBranch, err)
+		return false, fmt.Errorf("failed to switch back to '%s': %w", previousBranch, err)
 	}
 
 	fmt.Printf("Switched back to: %s\n", previousBranch)
@@ -128,11 +185,11 @@ func switchBack() error {
 		fmt.Printf("Warning: failed to clear previous branch: %v\n", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 // interactiveSwitch provides an interactive session selector
-func interactiveSwitch(sm *state.Manager) error {
+func interactiveSwitch(sm *state.Manager, opts switchOptions) error {
 	sessions, err := sm.DeriveFreshSessions()
 	if err != nil {
 		return fmt.Errorf("failed to load sessions: %w", err)
@@ -153,7 +210,7 @@ func interactiveSwitch(sm *state.Manager) error {
 		return nil
 	}
 
-	return switchToSession(sm, selectedSession.Core.Name)
+	return switchToSession(sm, selectedSession.Core.Name, opts)
 }
 
 // Helper functions for git operations
@@ -336,7 +393,7 @@ func commitChanges() error {
 	fmt.Println("💾 Committing changes...")
 
 	// Add all changes
-	if err := exec.Command("git", "add", ".").Run(); err != nil {
+	if err := exec.Command("git", append([]string{"add"}, git.WorkPathspec(".")...)...).Run(); err != nil {
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
 
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+)
+
+func newSyncCmd() *cobra.Command {
+	var strategy string
+	var only []string
+	var noFetch bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Bring every session's branch up to date with the base branch",
+		Long: `Fetch origin, then rebase every session's branch onto the updated base
+branch (origin's, when it has one), or merge the base branch into it with
+--strategy merge. Each session is updated in its own worktree.
+
+Sessions that are paused, have a broken worktree or have uncommitted
+changes are skipped. A session whose update hits conflicts is put back as it
+was, so no worktree is left halfway through a rebase or merge; update it by
+hand with 'cwt rebase <session>' to resolve them.
+
+Examples:
+  cwt sync                          # Rebase every session onto origin's base branch
+  cwt sync --strategy merge         # Merge the base branch into every session
+  cwt sync --only "feat-*"          # Only sessions matching a pattern
+  cwt sync --no-fetch               # Use the local base branch as it is`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsed, err := operations.ParseSyncStrategy(strategy)
+			if err != nil {
+				return err
+			}
+			return runSyncCmd(operations.SyncOptions{Strategy: parsed, Only: only}, !noFetch)
+		},
+	}
+
+	cmd.Flags().StringVar(&strategy, "strategy", string(operations.SyncRebase), "How to update branches: rebase or merge")
+	cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(operations.SyncRebase), string(operations.SyncMerge)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Only sync sessions matching these names or patterns")
+	cmd.RegisterFlagCompletionFunc("only", completeSessionNames)
+	cmd.Flags().BoolVar(&noFetch, "no-fetch", false, "Don't fetch origin first")
+
+	return cmd
+}
+
+func runSyncCmd(opts operations.SyncOptions, fetch bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	if fetch {
+		if hasRemote() {
+			opts.Remote = "origin"
+			fmt.Println("📡 Fetching origin...")
+		} else {
+			fmt.Println("No remote repository configured, syncing with the local base branch")
+		}
+	}
+
+	var counts = make(map[operations.SyncOutcome]int)
+	onto, results, err := operations.NewSessionOperations(sm).SyncSessions(opts, func(result operations.SyncResult) {
+		counts[result.Outcome]++
+		printSyncResult(result)
+	})
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No sessions to sync")
+		return nil
+	}
+
+	fmt.Printf("\nSynced with %s (%s): %d updated, %d up to date, %d with conflicts, %d skipped, %d failed\n",
+		onto, opts.Strategy,
+		counts[operations.SyncUpdated], counts[operations.SyncUpToDate], counts[operations.SyncConflicts],
+		counts[operations.SyncSkipped], counts[operations.SyncFailed])
+	if counts[operations.SyncConflicts] > 0 {
+		fmt.Println("💡 Resolve conflicts one session at a time with: cwt rebase <session>")
+	}
+	if failed := counts[operations.SyncConflicts] + counts[operations.SyncFailed]; failed > 0 {
+		return fmt.Errorf("%d of %d sessions could not be synced", failed, len(results))
+	}
+	return nil
+}
+
+// printSyncResult prints what syncing did to one session
+func printSyncResult(result operations.SyncResult) {
+	switch result.Outcome {
+	case operations.SyncUpdated:
+		fmt.Printf("✅ %s: updated\n", result.Session)
+	case operations.SyncUpToDate:
+		fmt.Printf("✨ %s: already up to date\n", result.Session)
+	case operations.SyncSkipped:
+		fmt.Printf("⏭️  %s: skipped, %s\n", result.Session, result.Reason)
+	case operations.SyncConflicts:
+		fmt.Printf("⚠️  %s: conflicts in %s; left as it was\n", result.Session, strings.Join(result.Files, ", "))
+		if result.Err != nil {
+			fmt.Printf("   ❌ %v\n", result.Err)
+		}
+	default:
+		fmt.Printf("❌ %s: %v\n", result.Session, result.Err)
+	}
+}
@@ -2,13 +2,18 @@ package cli
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/jlaneve/cwt-cli/internal/demo"
+	"github.com/jlaneve/cwt-cli/internal/profile"
 	"github.com/jlaneve/cwt-cli/internal/tui"
 )
 
 func newTuiCmd() *cobra.Command {
+	var demoMode bool
+
 	cmd := &cobra.Command{
 		Use:   "tui",
 		Short: "Launch the interactive TUI dashboard",
@@ -19,23 +24,58 @@ The TUI provides:
 - Interactive session management
 - Visual indicators for tmux, git, and Claude status
 - Quick session creation and deletion
-- Session attachment capabilities`,
+- Session attachment capabilities
+
+With --demo the dashboard shows simulated sessions cycling through realistic
+states instead of real ones, for screenshots, talks and UI development.`,
 		Aliases: []string{"ui", "dashboard"},
-		RunE:    runTuiCmd,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if demoMode {
+				return runDemoTui()
+			}
+			return runTuiCmd(cmd, args)
+		},
 	}
 
+	cmd.Flags().BoolVar(&demoMode, "demo", false, "Show simulated sessions instead of real ones")
+
 	return cmd
 }
 
+// runDemoTui launches the dashboard on simulated sessions
+func runDemoTui() error {
+	sm, cleanup, err := demo.NewManager()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Simulated state changes have no files to watch, so poll quickly instead
+	cfg := *appConfig
+	cfg.AutoRefresh = false
+	cfg.Polling.GitInterval = time.Second
+	cfg.Polling.TmuxInterval = time.Second
+
+	// The demo's settings are fixed, so config edits are not applied
+	if err := tui.Run(sm, &cfg, nil); err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	return nil
+}
+
 func runTuiCmd(cmd *cobra.Command, args []string) error {
+	// Always timed, for the timings overlay
+	if profiler == nil {
+		profiler = profile.NewRecorder()
+	}
 	sm, err := createStateManager()
 	if err != nil {
 		return err
 	}
 	// Note: StateManager will be closed by the TUI when it exits
 
-	// Launch TUI
-	if err := tui.Run(sm); err != nil {
+	// Launch TUI, applying config file edits while it runs
+	if err := tui.Run(sm, appConfig, reloadConfig); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
 
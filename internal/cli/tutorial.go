@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// tutorialSession is the name of the session the tutorial asks the user to create
+const tutorialSession = "hello-cwt"
+
+// tutorialState is what the tutorial observes about the throwaway repo
+type tutorialState struct {
+	sessions []types.Session
+	merged   bool // The tutorial session's branch is merged into main
+}
+
+// session returns the tutorial session if it exists
+func (s tutorialState) session() *types.Session {
+	for i := range s.sessions {
+		if s.sessions[i].Core.Name == tutorialSession {
+			return &s.sessions[i]
+		}
+	}
+	return nil
+}
+
+// tutorialStep is one exercise. Steps without a done check are confirmed by
+// pressing Enter because they change no state cwt can observe.
+type tutorialStep struct {
+	title        string
+	instructions []string
+	hint         string // Shown when the user presses Enter before the step is done
+	done         func(tutorialState) bool
+}
+
+func tutorialSteps(repo string) []tutorialStep {
+	return []tutorialStep{
+		{
+			title: "Create a session",
+			instructions: []string{
+				"Each session is an isolated git worktree with its own Claude running in tmux.",
+				"In a second terminal, run:",
+				"",
+				"  cd " + repo,
+				fmt.Sprintf("  cwt new %s \"Create hello.txt that says hello from Claude\"", tutorialSession),
+			},
+			hint: fmt.Sprintf("Waiting for a session named '%s' to appear.", tutorialSession),
+			done: func(s tutorialState) bool {
+				return s.session() != nil
+			},
+		},
+		{
+			title: "Watch the dashboard",
+			instructions: []string{
+				"Run 'cwt' in the second terminal to open the dashboard.",
+				"Watch the session's Claude status change while it works. Press 'a' to",
+				"attach and answer any prompts, then Ctrl-b d to detach.",
+				"",
+				"No Claude? Create a file in the worktree yourself:",
+				fmt.Sprintf("  echo hello > .cwt/worktrees/%s/hello.txt", tutorialSession),
+			},
+			hint: "Waiting for the session's worktree to have changes.",
+			done: func(s tutorialState) bool {
+				session := s.session()
+				return session != nil && (session.GitStatus.HasChanges || session.GitStatus.CommitCount > 0)
+			},
+		},
+		{
+			title: "Review the diff",
+			instructions: []string{
+				"Always review what an agent changed before you keep it. Run:",
+				"",
+				fmt.Sprintf("  cwt diff %s", tutorialSession),
+				"",
+				"or press 'v' on the session in the dashboard.",
+				"Press Enter here once you've looked at the diff.",
+			},
+		},
+		{
+			title: "Publish the session",
+			instructions: []string{
+				"Publishing commits everything in the worktree to the session branch.",
+				"The tutorial repo has no remote, so commit locally:",
+				"",
+				fmt.Sprintf("  cwt publish %s --local", tutorialSession),
+			},
+			hint: "Waiting for the session's changes to be committed.",
+			done: func(s tutorialState) bool {
+				session := s.session()
+				return session != nil && !session.GitStatus.HasChanges && session.GitStatus.CommitCount > 0
+			},
+		},
+		{
+			title: "Merge the work",
+			instructions: []string{
+				"Bring the session's commits back into main:",
+				"",
+				fmt.Sprintf("  cwt merge %s --target main", tutorialSession),
+			},
+			hint: fmt.Sprintf("Waiting for branch '%s' to be merged into main.", tutorialSession),
+			done: func(s tutorialState) bool {
+				return s.merged
+			},
+		},
+		{
+			title: "Clean up",
+			instructions: []string{
+				"The session's work is merged, so its worktree and tmux session can go:",
+				"",
+				fmt.Sprintf("  cwt delete %s", tutorialSession),
+			},
+			hint: "Waiting for the session to be deleted.",
+			done: func(s tutorialState) bool {
+				return s.session() == nil
+			},
+		},
+	}
+}
+
+func newTutorialCmd() *cobra.Command {
+	var keep bool
+
+	cmd := &cobra.Command{
+		Use:   "tutorial",
+		Short: "Learn the cwt workflow in a throwaway repository",
+		Long: `Walk through the cwt workflow step by step in a throwaway git repository:
+create a session, watch it in the dashboard, review its diff, publish it,
+merge it and clean up.
+
+Each step completes when cwt sees it happen, so you run real commands in a
+second terminal while the tutorial follows along. The repository is removed
+when the tutorial ends unless --keep is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTutorial(keep)
+		},
+	}
+
+	cmd.Flags().BoolVar(&keep, "keep", false, "Keep the tutorial repository when the tutorial ends")
+
+	return cmd
+}
+
+func runTutorial(keep bool) error {
+	repo, err := createTutorialRepo()
+	if err != nil {
+		return err
+	}
+
+	// Session worktree paths are relative to the repository
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if err := os.Chdir(repo); err != nil {
+		return fmt.Errorf("failed to enter tutorial repository: %w", err)
+	}
+	defer os.Chdir(originalDir)
+
+	sm := state.NewManager(state.Config{
+		DataDir:          config.DefaultDataDir,
+		BaseBranch:       "main",
+		ClaudeExecutable: appConfig.ClaudeExecutable,
+	})
+	defer sm.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Println("🎓 Welcome to the cwt tutorial!")
+	fmt.Printf("A throwaway repository was created at %s\n", repo)
+	fmt.Println("Press Ctrl-C at any time to stop.")
+
+	completed := runTutorialSteps(ctx, sm, tutorialSteps(repo))
+	if completed {
+		fmt.Println("\n🎉 Tutorial complete! You've run the whole session lifecycle.")
+		fmt.Println("Next: run 'cwt new' in one of your own repositories.")
+	} else {
+		fmt.Println("\nTutorial stopped.")
+	}
+
+	if keep {
+		fmt.Printf("The tutorial repository was kept at %s\n", repo)
+		return nil
+	}
+
+	// Remove sessions first so no tmux session outlives the repository
+	deleteTutorialSessions(sm)
+	os.Chdir(originalDir)
+	if err := os.RemoveAll(repo); err != nil {
+		return fmt.Errorf("failed to remove tutorial repository: %w", err)
+	}
+	fmt.Println("🧹 Removed the tutorial repository.")
+	return nil
+}
+
+// runTutorialSteps presents each step in turn and reports whether all were completed
+func runTutorialSteps(ctx context.Context, sm *state.Manager, steps []tutorialStep) bool {
+	enter := make(chan struct{})
+	go func() {
+		reader := bufio.NewReader(os.Stdin)
+		for {
+			if _, err := reader.ReadString('\n'); err != nil {
+				return
+			}
+			enter <- struct{}{}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i, step := range steps {
+		fmt.Printf("\n── Step %d/%d: %s ──\n", i+1, len(steps), step.title)
+		for _, line := range step.instructions {
+			fmt.Println(line)
+		}
+
+		for done := false; !done; {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-enter:
+				if step.done == nil {
+					done = true
+				} else if done = step.done(observeTutorial(sm)); !done {
+					fmt.Println("⏳ " + step.hint)
+				}
+			case <-ticker.C:
+				if step.done != nil {
+					done = step.done(observeTutorial(sm))
+				}
+			}
+		}
+		fmt.Printf("✅ %s\n", step.title)
+	}
+
+	return true
+}
+
+// observeTutorial derives the current state of the tutorial repository
+func observeTutorial(sm *state.Manager) tutorialState {
+	sm.InvalidateStatus("")
+	sessions, err := sm.DeriveFreshSessions()
+	if err != nil {
+		return tutorialState{}
+	}
+
+	merged := exec.Command("git", "merge-base", "--is-ancestor", tutorialSession, "main").Run() == nil
+	return tutorialState{sessions: sessions, merged: merged}
+}
+
+// createTutorialRepo creates a git repository with one commit on main
+func createTutorialRepo() (string, error) {
+	repo, err := os.MkdirTemp("", "cwt-tutorial-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create tutorial directory: %w", err)
+	}
+
+	readme := "# cwt tutorial\n\nA throwaway repository for learning cwt.\n"
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte(readme), 0644); err != nil {
+		os.RemoveAll(repo)
+		return "", fmt.Errorf("failed to write tutorial README: %w", err)
+	}
+
+	commands := [][]string{
+		{"init", "-q"},
+		{"symbolic-ref", "HEAD", "refs/heads/main"},
+		{"add", "README.md"},
+		// Don't depend on the user having a git identity configured
+		{"-c", "user.name=cwt tutorial", "-c", "user.email=tutorial@cwt.invalid", "commit", "-q", "-m", "Initial commit"},
+	}
+	for _, args := range commands {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			os.RemoveAll(repo)
+			return "", fmt.Errorf("failed to set up tutorial repository: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	return repo, nil
+}
+
+// deleteTutorialSessions deletes any sessions left in the tutorial
+// repository, along with whatever work they hold
+func deleteTutorialSessions(sm *state.Manager) {
+	cores, err := sm.CoreSessions()
+	if err != nil {
+		return
+	}
+	for _, core := range cores {
+		if _, err := sm.DeleteSessionWithOptions(core.ID, state.DeleteOptions{Force: true, IgnoreHooks: true}); err != nil {
+			fmt.Printf("Warning: failed to delete session '%s': %v\n", core.Name, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestTutorialSteps_DetectProgress(t *testing.T) {
+	steps := tutorialSteps("/tmp/repo")
+
+	session := func(git types.GitStatus) []types.Session {
+		return []types.Session{{Core: types.CoreSession{Name: tutorialSession}, GitStatus: git}}
+	}
+
+	// The state reached after each step, in order
+	progress := []tutorialState{
+		{sessions: session(types.GitStatus{})},
+		{sessions: session(types.GitStatus{HasChanges: true})},
+		{sessions: session(types.GitStatus{HasChanges: true})},
+		{sessions: session(types.GitStatus{CommitCount: 1})},
+		{sessions: session(types.GitStatus{CommitCount: 1}), merged: true},
+		{merged: true},
+	}
+	if len(progress) != len(steps) {
+		t.Fatalf("test covers %d steps, tutorial has %d", len(progress), len(steps))
+	}
+
+	for i, step := range steps {
+		if step.done == nil {
+			continue // Confirmed with Enter
+		}
+		if i > 0 && step.done(progress[i-1]) {
+			t.Errorf("step %q is already done before the user acts", step.title)
+		}
+		if !step.done(progress[i]) {
+			t.Errorf("step %q not detected as done", step.title)
+		}
+	}
+}
+
+func TestCreateTutorialRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo, err := createTutorialRepo()
+	if err != nil {
+		t.Fatalf("createTutorialRepo() error = %v", err)
+	}
+	defer os.RemoveAll(repo)
+
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = repo
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("tutorial repository has no commits: %v", err)
+	}
+	if got := string(output); got != "main\n" {
+		t.Errorf("tutorial repository is on %q, want main", got)
+	}
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newWatchCmd() *cobra.Command {
+	var off bool
+
+	cmd := &cobra.Command{
+		Use:   "watch [session-name]...",
+		Short: "Pin sessions to the watch list, or show it",
+		Long: `Put sessions on the watch list, for the work you care about right now.
+
+Watched sessions stay at the top of 'cwt list' and the TUI, whatever the sort
+order, and the TUI alerts when one waits for input, like it does for
+high-priority sessions. Watching a muted session unmutes it.
+
+Without a session name, lists the watched and muted sessions.
+
+Examples:
+  cwt watch auth-feature api-refactor     # Pin two sessions
+  cwt watch auth-feature --off            # Take one off the watch list
+  cwt watch                               # Show what is watched and muted`,
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if off {
+					return fmt.Errorf("name the sessions to take off the watch list")
+				}
+				return runShowWatchList()
+			}
+			return runSetAttention(args, types.AttentionWatched, off)
+		},
+	}
+
+	cmd.Flags().BoolVar(&off, "off", false, "Take the sessions off the watch list")
+
+	return cmd
+}
+
+func newMuteCmd() *cobra.Command {
+	var off bool
+
+	cmd := &cobra.Command{
+		Use:   "mute <session-name>...",
+		Short: "Mute sessions: list them last and never alert about them",
+		Long: `Mute sessions you don't need to hear about for now, like long-running
+experiments.
+
+Muted sessions sink to the bottom of 'cwt list', are collapsed into one line
+in the TUI ('z' shows them), and never raise its alerts, even when they wait
+for input. Muting a watched session takes it off the watch list.
+
+Examples:
+  cwt mute spike-graphql                  # Mute a session
+  cwt mute spike-graphql --off            # Unmute it`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeSessionNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSetAttention(args, types.AttentionMuted, off)
+		},
+	}
+
+	cmd.Flags().BoolVar(&off, "off", false, "Unmute the sessions")
+
+	return cmd
+}
+
+// runSetAttention watches or mutes sessions, or with off undoes that for
+// those that are
+func runSetAttention(names []string, attention types.Attention, off bool) error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	sessionOps := operations.NewSessionOperations(sm)
+
+	var failed int
+	for _, name := range names {
+		session, sessionID, err := sessionOps.FindSessionByName(name)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+
+		switch {
+		case off && session.Core.Attention != attention:
+			fmt.Printf("'%s' is not %s\n", name, attention)
+			continue
+		case off:
+			err = sm.SetSessionAttention(sessionID, "")
+		default:
+			err = sm.SetSessionAttention(sessionID, attention)
+		}
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Println(describeAttentionChange(name, attention, off))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to update %d of %d sessions", failed, len(names))
+	}
+	return nil
+}
+
+// describeAttentionChange says what watching or muting a session did
+func describeAttentionChange(name string, attention types.Attention, off bool) string {
+	switch {
+	case attention == types.AttentionWatched && off:
+		return fmt.Sprintf("Took '%s' off the watch list", name)
+	case attention == types.AttentionWatched:
+		return fmt.Sprintf("👀 Watching '%s'", name)
+	case off:
+		return fmt.Sprintf("🔔 Unmuted '%s'", name)
+	default:
+		return fmt.Sprintf("🔇 Muted '%s'", name)
+	}
+}
+
+func runShowWatchList() error {
+	sm, err := createStateManager()
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	cores, err := sm.CoreSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var watched, muted []string
+	for _, core := range cores {
+		switch {
+		case core.IsWatched():
+			watched = append(watched, core.Name)
+		case core.IsMuted():
+			muted = append(muted, core.Name)
+		}
+	}
+	sort.Strings(watched)
+	sort.Strings(muted)
+
+	if len(watched) == 0 && len(muted) == 0 {
+		fmt.Println("No sessions are watched or muted.")
+		fmt.Println("Watch one with: cwt watch <session-name>")
+		return nil
+	}
+	for _, group := range []struct {
+		title string
+		names []string
+	}{
+		{"👀 Watched", watched},
+		{"🔇 Muted", muted},
+	} {
+		if len(group.names) == 0 {
+			continue
+		}
+		fmt.Printf("%s:\n", group.title)
+		for _, name := range group.names {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	return nil
+}
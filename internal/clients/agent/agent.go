@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// Checker derives the status of the agent a session runs from what it
+// leaves behind, like its transcripts and its tmux pane
+type Checker interface {
+	GetStatus(worktreePath string) types.ClaudeStatus
+	FindSessionID(worktreePath string) (string, error) // Conversation to resume
+	TokenUsage(worktreePath, month string) int64       // Tokens used in a UsageMonth
+	SessionUsage(worktreePath string) types.Usage      // Used by all conversations in the worktree
+}
+
+// Launch says how to start an agent
+type Launch struct {
+	Command string // Shell command starting it, "" when it isn't installed
+	Typed   string // Prompt to type into it once it runs, when its command line can't take one
+}
+
+// Agent is a coding agent sessions can run in their tmux session
+type Agent interface {
+	// Name is what the config and 'cwt new --agent' call it
+	Name() string
+
+	// Executables lists the commands it may be installed as, most likely first
+	Executables() []string
+
+	// Launch returns how to start it from executable with args, resuming
+	// conversationID and sending prompt when they aren't empty
+	Launch(executable string, args []string, conversationID, prompt string) Launch
+
+	// ManagedArgs are the flags and subcommands cwt passes itself, or that
+	// would keep the agent from running in the session's tmux pane
+	ManagedArgs() []string
+
+	// NewChecker creates the Checker deriving its status
+	NewChecker(tmuxChecker tmux.Checker) Checker
+}
+
+// The agents sessions can run
+var (
+	Claude   Agent = claudeAgent{}
+	Aider    Agent = aiderAgent{}
+	Codex    Agent = codexAgent{}
+	OpenCode Agent = opencodeAgent{}
+)
+
+// All lists the agents sessions can run, Claude first
+var All = []Agent{Claude, Aider, Codex, OpenCode}
+
+// Names returns the names of the agents sessions can run
+func Names() []string {
+	names := make([]string, len(All))
+	for i, agent := range All {
+		names[i] = agent.Name()
+	}
+	return names
+}
+
+// Lookup returns the agent called name; "" is Claude, the agent sessions
+// ran before there was a choice
+func Lookup(name string) (Agent, error) {
+	if name == "" {
+		return Claude, nil
+	}
+	for _, agent := range All {
+		if agent.Name() == name {
+			return agent, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown agent %q (valid: %v)", name, Names())
+}
+
+// IsClaude reports whether name is Claude's
+func IsClaude(name string) bool {
+	return name == "" || name == Claude.Name()
+}
+
+// FindExecutable returns the first of an agent's executables found in PATH, or ""
+func FindExecutable(agent Agent) string {
+	for _, executable := range agent.Executables() {
+		if _, err := exec.LookPath(executable); err == nil {
+			return executable
+		}
+	}
+	return ""
+}
+
+// ValidateArgs checks the arguments a session starts an agent with
+func ValidateArgs(agent Agent, args []string) error {
+	for _, arg := range args {
+		flag, _, _ := strings.Cut(arg, "=")
+		if slices.Contains(agent.ManagedArgs(), flag) {
+			return fmt.Errorf("%s argument %s can't be set: cwt starts and resumes its conversation itself", agent.Name(), flag)
+		}
+	}
+	return nil
+}
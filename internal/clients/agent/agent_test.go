@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLaunch(t *testing.T) {
+	tests := []struct {
+		agent          Agent
+		conversationID string
+		wantCommand    string
+		wantTyped      string
+	}{
+		{Claude, "", `claude '--model' 'x' 'Fix it'`, ""},
+		{Claude, "abc", `claude '--model' 'x' -r abc 'Fix it'`, ""},
+		{Aider, "", `aider '--model' 'x'`, "Fix it"},
+		{Aider, ".aider.chat.history.md", `aider '--model' 'x' '--restore-chat-history'`, "Fix it"},
+		{Codex, "", `codex '--model' 'x' 'Fix it'`, ""},
+		{Codex, "abc", `codex 'resume' '--model' 'x' 'abc' 'Fix it'`, ""},
+		{OpenCode, "ses_1", `opencode '--model' 'x' '--session' 'ses_1' '--prompt' 'Fix it'`, ""},
+	}
+	for _, tt := range tests {
+		args := []string{"--model", "x"}
+		launch := tt.agent.Launch(tt.agent.Name(), args, tt.conversationID, "Fix it")
+		if launch.Command != tt.wantCommand || launch.Typed != tt.wantTyped {
+			t.Errorf("%s.Launch(%q) = %+v, want %q typing %q", tt.agent.Name(), tt.conversationID, launch, tt.wantCommand, tt.wantTyped)
+		}
+		if len(args) != 2 {
+			t.Errorf("%s.Launch() changed the args it was given: %q", tt.agent.Name(), args)
+		}
+	}
+
+	if launch := Aider.Launch("aider", nil, "", "Fix it\n\nSee notes.md"); launch.Typed != "Fix it See notes.md" {
+		t.Errorf("a typed prompt should fit on one line, got %q", launch.Typed)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if found, err := Lookup(""); err != nil || found != Claude {
+		t.Errorf(`Lookup("") = %v, %v, want Claude`, found, err)
+	}
+	if found, err := Lookup("codex"); err != nil || found != Codex {
+		t.Errorf(`Lookup("codex") = %v, %v`, found, err)
+	}
+	if _, err := Lookup("cursor"); err == nil || !strings.Contains(err.Error(), "aider") {
+		t.Errorf("expected an unknown agent to list the known ones, got %v", err)
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	if err := ValidateArgs(Claude, []string{"--model", "opus"}); err != nil {
+		t.Errorf("ValidateArgs() error = %v", err)
+	}
+	if err := ValidateArgs(Claude, []string{"--resume=abc"}); err == nil {
+		t.Error("expected Claude's --resume to be rejected")
+	}
+	if err := ValidateArgs(Codex, []string{"exec"}); err == nil {
+		t.Error("expected codex exec, which doesn't stay in the pane, to be rejected")
+	}
+	// Flags are the agent's own
+	if err := ValidateArgs(Aider, []string{"-c", "aider.conf.yml"}); err != nil {
+		t.Errorf("ValidateArgs() error = %v", err)
+	}
+}
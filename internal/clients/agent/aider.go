@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// aiderHistory is the chat history aider keeps in the root of the repository it runs in
+const aiderHistory = ".aider.chat.history.md"
+
+// aiderAgent runs aider. Its command line can't take a prompt without
+// exiting once it is answered, so the prompt is typed in. It resumes by
+// restoring the chat history of the worktree.
+type aiderAgent struct{}
+
+func (aiderAgent) Name() string {
+	return "aider"
+}
+
+func (aiderAgent) Executables() []string {
+	return []string{"aider"}
+}
+
+func (aiderAgent) Launch(executable string, args []string, conversationID, prompt string) Launch {
+	if conversationID != "" {
+		args = append(args[:len(args):len(args)], "--restore-chat-history")
+	}
+	command := executable
+	if len(args) > 0 {
+		command += " " + utils.ShellQuoteArgs(args)
+	}
+	// A typed newline would send the prompt before its end
+	return Launch{Command: command, Typed: strings.Join(strings.Fields(prompt), " ")}
+}
+
+func (aiderAgent) ManagedArgs() []string {
+	return []string{"--message", "--msg", "-m", "--message-file", "-f", "--restore-chat-history"}
+}
+
+func (aiderAgent) NewChecker(tmuxChecker tmux.Checker) Checker {
+	return &transcriptChecker{
+		name:        "aider",
+		tmuxChecker: tmuxChecker,
+		transcripts: aiderTranscripts{},
+		waiting:     []*regexp.Regexp{regexp.MustCompile(`\(Y\)es/\(N\)o`)},
+	}
+}
+
+// aiderTranscripts reads aider's chat history, which holds every
+// conversation in the worktree
+type aiderTranscripts struct{}
+
+var (
+	// aiderStarted heads each conversation in the chat history
+	aiderStarted = regexp.MustCompile(`^# aider chat started at (\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})`)
+	// aiderTokens reports the tokens of each request, like "> Tokens: 2.1k sent, 150 received."
+	aiderTokens = regexp.MustCompile(`^> Tokens: ([\d.]+[kM]?) sent, ([\d.]+[kM]?) received`)
+	// aiderCost follows the tokens with what the request cost, like "Cost: $0.01 message"
+	aiderCost = regexp.MustCompile(`Cost: \$([\d.]+) message`)
+)
+
+func (aiderTranscripts) latest(worktreePath string) (*conversation, error) {
+	info, err := os.Stat(filepath.Join(worktreePath, aiderHistory))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &conversation{ID: aiderHistory, Updated: info.ModTime()}, nil
+}
+
+func (aiderTranscripts) tokens(worktreePath, month string) int64 {
+	file, err := os.Open(filepath.Join(worktreePath, aiderHistory))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var tokens int64
+	var current string // Month of the conversation being read
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := aiderStarted.FindStringSubmatch(line); match != nil {
+			started, err := time.ParseInLocation(time.DateTime, match[1], time.Local)
+			if err == nil {
+				current = claude.UsageMonth(started)
+			}
+			continue
+		}
+		if current != month {
+			continue
+		}
+		if match := aiderTokens.FindStringSubmatch(line); match != nil {
+			tokens += aiderCount(match[1]) + aiderCount(match[2])
+		}
+	}
+	return tokens
+}
+
+func (aiderTranscripts) usage(worktreePath string) types.Usage {
+	file, err := os.Open(filepath.Join(worktreePath, aiderHistory))
+	if err != nil {
+		return types.Usage{}
+	}
+	defer file.Close()
+
+	var usage types.Usage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		match := aiderTokens.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		usage.InputTokens += aiderCount(match[1])
+		usage.OutputTokens += aiderCount(match[2])
+		if cost := aiderCost.FindStringSubmatch(line); cost != nil {
+			if dollars, err := strconv.ParseFloat(cost[1], 64); err == nil {
+				usage.Cost += dollars
+			}
+		}
+	}
+	return usage
+}
+
+// aiderCount parses a token count as aider prints it, like 150, 2.1k or 1.2M
+func aiderCount(count string) int64 {
+	scale := 1.0
+	switch count[len(count)-1] {
+	case 'k':
+		scale, count = 1e3, count[:len(count)-1]
+	case 'M':
+		scale, count = 1e6, count[:len(count)-1]
+	}
+	n, err := strconv.ParseFloat(count, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(n * scale)
+}
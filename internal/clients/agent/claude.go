@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"os"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// claudeAgent runs Claude Code, whose hooks and transcripts cwt reads
+type claudeAgent struct{}
+
+func (claudeAgent) Name() string {
+	return "claude"
+}
+
+func (claudeAgent) Executables() []string {
+	return []string{
+		"claude",
+		os.ExpandEnv("$HOME/.claude/local/claude"),
+		os.ExpandEnv("$HOME/.claude/local/node_modules/.bin/claude"),
+		"/usr/local/bin/claude",
+	}
+}
+
+func (claudeAgent) Launch(executable string, args []string, conversationID, prompt string) Launch {
+	command := executable
+	if len(args) > 0 {
+		command += " " + utils.ShellQuoteArgs(args)
+	}
+	if conversationID != "" {
+		command += " -r " + conversationID
+	}
+	if prompt != "" {
+		command += " " + utils.ShellQuote(prompt)
+	}
+	return Launch{Command: command}
+}
+
+func (claudeAgent) ManagedArgs() []string {
+	return []string{"-r", "--resume", "-c", "--continue", "-p", "--print"}
+}
+
+func (claudeAgent) NewChecker(tmuxChecker tmux.Checker) Checker {
+	return claude.NewRealChecker(tmuxChecker)
+}
@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// codexAgent runs OpenAI's Codex CLI, which records each conversation as a
+// rollout under $CODEX_HOME/sessions
+type codexAgent struct{}
+
+func (codexAgent) Name() string {
+	return "codex"
+}
+
+func (codexAgent) Executables() []string {
+	return []string{"codex"}
+}
+
+func (codexAgent) Launch(executable string, args []string, conversationID, prompt string) Launch {
+	var argv []string
+	if conversationID != "" {
+		argv = append(argv, "resume")
+	}
+	argv = append(argv, args...)
+	if conversationID != "" {
+		argv = append(argv, conversationID)
+	}
+	if prompt != "" {
+		argv = append(argv, prompt)
+	}
+	command := executable
+	if len(argv) > 0 {
+		command += " " + utils.ShellQuoteArgs(argv)
+	}
+	return Launch{Command: command}
+}
+
+func (codexAgent) ManagedArgs() []string {
+	return []string{"resume", "exec"}
+}
+
+func (codexAgent) NewChecker(tmuxChecker tmux.Checker) Checker {
+	home := os.Getenv("CODEX_HOME")
+	if home == "" {
+		home = os.ExpandEnv("$HOME/.codex")
+	}
+	return &transcriptChecker{
+		name:        "codex",
+		tmuxChecker: tmuxChecker,
+		transcripts: newCodexTranscripts(filepath.Join(home, "sessions")),
+		waiting: []*regexp.Regexp{
+			regexp.MustCompile(`Would you like to (run|make|apply)`),
+			regexp.MustCompile(`Allow command\?`),
+		},
+	}
+}
+
+// codexRollout is what the first line of a rollout says about its conversation
+type codexRollout struct {
+	ID  string
+	Cwd string
+}
+
+// codexTranscripts reads Codex's rollouts. Their first line, which says
+// where the conversation runs, is read once per rollout.
+type codexTranscripts struct {
+	dir string
+
+	mu       sync.Mutex
+	rollouts map[string]codexRollout // By path
+}
+
+func newCodexTranscripts(dir string) *codexTranscripts {
+	return &codexTranscripts{dir: dir, rollouts: make(map[string]codexRollout)}
+}
+
+// rolloutsIn returns the rollouts of conversations run in a worktree
+func (c *codexTranscripts) rolloutsIn(worktreePath string) ([]string, error) {
+	dirs := sameDirs(worktreePath)
+	var paths []string
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), "rollout-") || filepath.Ext(path) != ".jsonl" {
+			return nil
+		}
+		if rollout, ok := c.rollout(path); ok && dirs[filepath.Clean(rollout.Cwd)] {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// rollout reads the session_meta line a rollout starts with
+func (c *codexTranscripts) rollout(path string) (codexRollout, bool) {
+	c.mu.Lock()
+	rollout, ok := c.rollouts[path]
+	c.mu.Unlock()
+	if ok {
+		return rollout, rollout.Cwd != ""
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return codexRollout{}, false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if !scanner.Scan() {
+		return codexRollout{}, false // Still being written
+	}
+	var line struct {
+		Type    string `json:"type"`
+		Payload struct {
+			ID  string `json:"id"`
+			Cwd string `json:"cwd"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+		return codexRollout{}, false // Still being written
+	}
+	// Rollouts of older versions, which don't say where they ran, are remembered as such
+	if line.Type == "session_meta" {
+		rollout = codexRollout{ID: line.Payload.ID, Cwd: line.Payload.Cwd}
+	}
+	c.mu.Lock()
+	c.rollouts[path] = rollout
+	c.mu.Unlock()
+	return rollout, rollout.Cwd != ""
+}
+
+func (c *codexTranscripts) latest(worktreePath string) (*conversation, error) {
+	paths, err := c.rolloutsIn(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	var latest *conversation
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if latest == nil || info.ModTime().After(latest.Updated) {
+			rollout, _ := c.rollout(path)
+			latest = &conversation{ID: rollout.ID, Updated: info.ModTime()}
+		}
+	}
+	return latest, nil
+}
+
+// tokens adds up the tokens of the conversations last active in month, as
+// the last token count of each rollout reports them
+func (c *codexTranscripts) tokens(worktreePath, month string) int64 {
+	paths, err := c.rolloutsIn(worktreePath)
+	if err != nil {
+		return 0
+	}
+	var tokens int64
+	for _, path := range paths {
+		count, at := codexTokenCount(path)
+		if !at.IsZero() && claude.UsageMonth(at) == month {
+			tokens += count.TotalTokens
+		}
+	}
+	return tokens
+}
+
+// usage adds up the last token counts of the worktree's rollouts. Codex
+// doesn't record what its models charge, so no cost is estimated.
+func (c *codexTranscripts) usage(worktreePath string) types.Usage {
+	paths, err := c.rolloutsIn(worktreePath)
+	if err != nil {
+		return types.Usage{}
+	}
+	var usage types.Usage
+	for _, path := range paths {
+		count, _ := codexTokenCount(path)
+		// Cached input is part of the input Codex counts
+		usage = usage.Add(types.Usage{
+			InputTokens:     count.InputTokens - count.CachedInputTokens,
+			OutputTokens:    count.OutputTokens,
+			CacheReadTokens: count.CachedInputTokens,
+		})
+	}
+	return usage
+}
+
+// codexTokens is the usage a token count event of a rollout reports
+type codexTokens struct {
+	InputTokens       int64 `json:"input_tokens"`
+	CachedInputTokens int64 `json:"cached_input_tokens"`
+	OutputTokens      int64 `json:"output_tokens"`
+	TotalTokens       int64 `json:"total_tokens"`
+}
+
+// codexTokenCount returns the usage of the last token count event of a
+// rollout and when it was recorded
+func codexTokenCount(path string) (codexTokens, time.Time) {
+	file, err := os.Open(path)
+	if err != nil {
+		return codexTokens{}, time.Time{}
+	}
+	defer file.Close()
+
+	var total codexTokens
+	var at time.Time
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if !strings.Contains(scanner.Text(), `"token_count"`) {
+			continue
+		}
+		var line struct {
+			Timestamp time.Time `json:"timestamp"`
+			Payload   struct {
+				Type string `json:"type"`
+				Info *struct {
+					TotalTokenUsage codexTokens `json:"total_token_usage"`
+				} `json:"info"`
+			} `json:"payload"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &line) != nil || line.Payload.Type != "token_count" || line.Payload.Info == nil {
+			continue
+		}
+		total, at = line.Payload.Info.TotalTokenUsage, line.Timestamp
+	}
+	return total, at
+}
+
+// sameDirs returns the paths an agent may have recorded for the directory
+// it ran in: the absolute worktree path, and where its symlinks lead
+func sameDirs(worktreePath string) map[string]bool {
+	dirs := make(map[string]bool)
+	if abs, err := filepath.Abs(worktreePath); err == nil {
+		dirs[abs] = true
+	}
+	if resolved, err := filepath.EvalSymlinks(worktreePath); err == nil {
+		if abs, err := filepath.Abs(resolved); err == nil {
+			dirs[abs] = true
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// opencodeAgent runs opencode, which stores each session as JSON under
+// $XDG_DATA_HOME/opencode/storage
+type opencodeAgent struct{}
+
+func (opencodeAgent) Name() string {
+	return "opencode"
+}
+
+func (opencodeAgent) Executables() []string {
+	return []string{"opencode"}
+}
+
+func (opencodeAgent) Launch(executable string, args []string, conversationID, prompt string) Launch {
+	argv := args[:len(args):len(args)]
+	if conversationID != "" {
+		argv = append(argv, "--session", conversationID)
+	}
+	if prompt != "" {
+		argv = append(argv, "--prompt", prompt)
+	}
+	command := executable
+	if len(argv) > 0 {
+		command += " " + utils.ShellQuoteArgs(argv)
+	}
+	return Launch{Command: command}
+}
+
+func (opencodeAgent) ManagedArgs() []string {
+	return []string{"-s", "--session", "-c", "--continue", "--prompt", "run"}
+}
+
+func (opencodeAgent) NewChecker(tmuxChecker tmux.Checker) Checker {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = os.ExpandEnv("$HOME/.local/share")
+	}
+	return &transcriptChecker{
+		name:        "opencode",
+		tmuxChecker: tmuxChecker,
+		transcripts: opencodeTranscripts{dir: filepath.Join(dataHome, "opencode", "storage")},
+		waiting:     []*regexp.Regexp{regexp.MustCompile(`Permission required`)},
+	}
+}
+
+// opencodeTranscripts reads opencode's storage: a JSON file per session
+// under session/, and one per message under message/<session ID>/
+type opencodeTranscripts struct {
+	dir string
+}
+
+// opencodeSession is the part of a stored session cwt reads
+type opencodeSession struct {
+	ID        string `json:"id"`
+	Directory string `json:"directory"`
+	Time      struct {
+		Updated int64 `json:"updated"` // Unix milliseconds
+	} `json:"time"`
+}
+
+// sessionsIn returns the sessions run in a worktree
+func (o opencodeTranscripts) sessionsIn(worktreePath string) ([]opencodeSession, error) {
+	dirs := sameDirs(worktreePath)
+	var sessions []opencodeSession
+	err := filepath.WalkDir(filepath.Join(o.dir, "session"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		var session opencodeSession
+		if readJSON(path, &session) && session.ID != "" && dirs[filepath.Clean(session.Directory)] {
+			sessions = append(sessions, session)
+		}
+		return nil
+	})
+	return sessions, err
+}
+
+func (o opencodeTranscripts) latest(worktreePath string) (*conversation, error) {
+	sessions, err := o.sessionsIn(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	var latest *conversation
+	for _, session := range sessions {
+		updated := time.UnixMilli(session.Time.Updated)
+		if latest == nil || updated.After(latest.Updated) {
+			latest = &conversation{ID: session.ID, Updated: updated}
+		}
+	}
+	return latest, nil
+}
+
+// opencodeMessage is the part of a stored message cwt reads
+type opencodeMessage struct {
+	Time struct {
+		Created int64 `json:"created"` // Unix milliseconds
+	} `json:"time"`
+	Cost   float64 `json:"cost"` // In US dollars
+	Tokens struct {
+		Input     int64 `json:"input"`
+		Output    int64 `json:"output"`
+		Reasoning int64 `json:"reasoning"`
+		Cache     struct {
+			Read  int64 `json:"read"`
+			Write int64 `json:"write"`
+		} `json:"cache"`
+	} `json:"tokens"`
+}
+
+// messagesIn returns the messages of the sessions run in a worktree
+func (o opencodeTranscripts) messagesIn(worktreePath string) []opencodeMessage {
+	sessions, err := o.sessionsIn(worktreePath)
+	if err != nil {
+		return nil
+	}
+	var messages []opencodeMessage
+	for _, session := range sessions {
+		entries, err := os.ReadDir(filepath.Join(o.dir, "message", session.ID))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			var message opencodeMessage
+			if readJSON(filepath.Join(o.dir, "message", session.ID, entry.Name()), &message) {
+				messages = append(messages, message)
+			}
+		}
+	}
+	return messages
+}
+
+func (o opencodeTranscripts) tokens(worktreePath, month string) int64 {
+	var tokens int64
+	for _, message := range o.messagesIn(worktreePath) {
+		if claude.UsageMonth(time.UnixMilli(message.Time.Created)) != month {
+			continue
+		}
+		used := message.Tokens
+		tokens += used.Input + used.Output + used.Reasoning + used.Cache.Read + used.Cache.Write
+	}
+	return tokens
+}
+
+func (o opencodeTranscripts) usage(worktreePath string) types.Usage {
+	var usage types.Usage
+	for _, message := range o.messagesIn(worktreePath) {
+		used := message.Tokens
+		usage = usage.Add(types.Usage{
+			InputTokens:      used.Input,
+			OutputTokens:     used.Output + used.Reasoning,
+			CacheWriteTokens: used.Cache.Write,
+			CacheReadTokens:  used.Cache.Read,
+			Cost:             message.Cost,
+		})
+	}
+	return usage
+}
+
+// readJSON decodes a JSON file into v, reporting whether it could
+func readJSON(path string, v any) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// activeWindow is how recently an agent must have written its transcript
+// to count as working
+const activeWindow = 30 * time.Second
+
+// conversation is what an agent recorded of its latest conversation in a worktree
+type conversation struct {
+	ID      string    // What resumes it
+	Updated time.Time // When the agent last wrote to it
+}
+
+// transcripts parses what an agent records of its conversations
+type transcripts interface {
+	// latest returns the latest conversation in a worktree, nil when there is none
+	latest(worktreePath string) (*conversation, error)
+	// tokens adds up the tokens used in a month by the conversations in a worktree
+	tokens(worktreePath, month string) int64
+	// usage adds up what the conversations in a worktree used
+	usage(worktreePath string) types.Usage
+}
+
+// transcriptChecker derives the status of an agent without hooks from its
+// transcripts, for when it last did something, and its tmux pane, for
+// whether it asks something
+type transcriptChecker struct {
+	name        string
+	tmuxChecker tmux.Checker
+	transcripts transcripts
+	waiting     []*regexp.Regexp // Questions it asks in its pane
+}
+
+// GetStatus derives the agent's state in a worktree: working while it
+// writes its transcript, waiting once it stops or asks in its pane
+func (c *transcriptChecker) GetStatus(worktreePath string) types.ClaudeStatus {
+	status := types.ClaudeStatus{
+		State:        types.ClaudeUnknown,
+		Availability: types.AvailVeryStale,
+	}
+	latest, err := c.transcripts.latest(worktreePath)
+	if err != nil {
+		return status
+	}
+	if latest == nil {
+		status.State = types.ClaudeNotStarted
+		return status
+	}
+
+	status.SessionID = latest.ID
+	status.LastMessage = latest.Updated
+	status.Availability = claude.Availability(latest.Updated)
+	status.State = types.ClaudeWaiting
+	if time.Since(latest.Updated) < activeWindow {
+		status.State = types.ClaudeWorking
+	}
+	if status.State == types.ClaudeWorking && c.asks(worktreePath) {
+		status.State = types.ClaudeWaiting
+	}
+	return status
+}
+
+// asks reports whether the agent's pane shows a question to the user
+func (c *transcriptChecker) asks(worktreePath string) bool {
+	if c.tmuxChecker == nil {
+		return false
+	}
+	tmuxSession := "cwt-" + filepath.Base(worktreePath)
+	if !c.tmuxChecker.IsSessionAlive(tmuxSession) {
+		return false
+	}
+	output, err := c.tmuxChecker.CaptureOutput(tmuxSession)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range c.waiting {
+		if pattern.MatchString(output) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindSessionID returns what resumes the agent's latest conversation in a worktree
+func (c *transcriptChecker) FindSessionID(worktreePath string) (string, error) {
+	latest, err := c.transcripts.latest(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	if latest == nil {
+		return "", fmt.Errorf("no %s conversation found for worktree %s", c.name, worktreePath)
+	}
+	return latest.ID, nil
+}
+
+// TokenUsage adds up the tokens used in a month by the agent in a worktree
+func (c *transcriptChecker) TokenUsage(worktreePath, month string) int64 {
+	return c.transcripts.tokens(worktreePath, month)
+}
+
+// SessionUsage adds up what the agent used in a worktree
+func (c *transcriptChecker) SessionUsage(worktreePath string) types.Usage {
+	return c.transcripts.usage(worktreePath)
+}
@@ -0,0 +1,161 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func writeFile(t *testing.T, path, contents string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranscriptChecker_GetStatus(t *testing.T) {
+	worktree := filepath.Join(t.TempDir(), "auth")
+	history := filepath.Join(worktree, aiderHistory)
+	tmuxChecker := tmux.NewMockChecker()
+	checker := Aider.NewChecker(tmuxChecker)
+
+	if status := checker.GetStatus(worktree); status.State != types.ClaudeNotStarted {
+		t.Errorf("without a transcript the state should be not started, got %s", status.State)
+	}
+	if _, err := checker.FindSessionID(worktree); err == nil {
+		t.Error("expected no conversation to resume")
+	}
+
+	writeFile(t, history, "# aider chat started at 2025-01-02 10:00:00\n", time.Now())
+	if status := checker.GetStatus(worktree); status.State != types.ClaudeWorking || status.Availability != types.AvailCurrent {
+		t.Errorf("an agent writing its transcript should be working, got %+v", status)
+	}
+	tmuxChecker.SetAlive("cwt-auth", true)
+	tmuxChecker.SetOutput("cwt-auth", "Create new file notes.md? (Y)es/(N)o [Yes]:")
+	if status := checker.GetStatus(worktree); status.State != types.ClaudeWaiting {
+		t.Errorf("an agent asking in its pane should be waiting, got %s", status.State)
+	}
+
+	tmuxChecker.SetOutput("cwt-auth", "> ")
+	writeFile(t, history, "# aider chat started at 2025-01-02 10:00:00\n", time.Now().Add(-time.Hour))
+	if status := checker.GetStatus(worktree); status.State != types.ClaudeWaiting {
+		t.Errorf("an agent done writing should be waiting for input, got %s", status.State)
+	}
+	if id, err := checker.FindSessionID(worktree); err != nil || id != aiderHistory {
+		t.Errorf("FindSessionID() = %q, %v", id, err)
+	}
+}
+
+func TestAiderTranscripts_Tokens(t *testing.T) {
+	worktree := t.TempDir()
+	writeFile(t, filepath.Join(worktree, aiderHistory), `
+# aider chat started at 2025-01-30 10:00:00
+
+#### Add a cache
+
+> Tokens: 2.1k sent, 150 received. Cost: $0.01 message, $0.01 session.
+
+# aider chat started at 2025-02-01 09:00:00
+
+> Tokens: 1.2M sent, 3k received.
+`, time.Now())
+
+	january := claude.UsageMonth(time.Date(2025, 1, 30, 10, 0, 0, 0, time.Local))
+	february := claude.UsageMonth(time.Date(2025, 2, 1, 9, 0, 0, 0, time.Local))
+	if got := (aiderTranscripts{}).tokens(worktree, january); got != 2250 {
+		t.Errorf("January tokens = %d, want 2250", got)
+	}
+	if got := (aiderTranscripts{}).tokens(worktree, february); got != 1203000 {
+		t.Errorf("February tokens = %d, want 1203000", got)
+	}
+	want := types.Usage{InputTokens: 1202100, OutputTokens: 3150, Cost: 0.01}
+	if got := (aiderTranscripts{}).usage(worktree); got != want {
+		t.Errorf("usage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCodexTranscripts(t *testing.T) {
+	root := t.TempDir()
+	worktree := filepath.Join(root, "repo", ".cwt", "worktrees", "auth")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sessions := filepath.Join(root, "codex", "sessions")
+	meta := func(id, cwd string) string {
+		return `{"timestamp":"2025-03-01T10:00:00Z","type":"session_meta","payload":{"id":"` + id + `","cwd":"` + cwd + `"}}` + "\n"
+	}
+	tokenCount := func(timestamp string, total int) string {
+		usage := `"input_tokens":` + strconv.Itoa(total*3/4) + `,"cached_input_tokens":` + strconv.Itoa(total/2) +
+			`,"output_tokens":` + strconv.Itoa(total/4) + `,"total_tokens":` + strconv.Itoa(total)
+		return `{"timestamp":"` + timestamp + `","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{` + usage + `}}}}` + "\n"
+	}
+	now := time.Now()
+	writeFile(t, filepath.Join(sessions, "2025/03/01/rollout-a.jsonl"),
+		meta("old", worktree)+tokenCount("2025-03-01T10:01:00Z", 100)+tokenCount("2025-03-01T10:02:00Z", 400), now.Add(-2*time.Hour))
+	writeFile(t, filepath.Join(sessions, "2025/03/02/rollout-b.jsonl"),
+		meta("new", worktree)+tokenCount("2025-03-02T10:01:00Z", 50), now.Add(-time.Hour))
+	writeFile(t, filepath.Join(sessions, "2025/03/02/rollout-c.jsonl"),
+		meta("elsewhere", root)+tokenCount("2025-03-02T10:01:00Z", 9000), now)
+
+	transcripts := newCodexTranscripts(sessions)
+	latest, err := transcripts.latest(worktree)
+	if err != nil || latest == nil || latest.ID != "new" {
+		t.Fatalf("latest() = %+v, %v, want the newer rollout of the worktree", latest, err)
+	}
+	march := claude.UsageMonth(time.Date(2025, 3, 2, 10, 1, 0, 0, time.UTC))
+	if got := transcripts.tokens(worktree, march); got != 450 {
+		t.Errorf("tokens() = %d, want the last counts of the worktree's rollouts, 450", got)
+	}
+	want := types.Usage{InputTokens: 112, OutputTokens: 112, CacheReadTokens: 225}
+	if got := transcripts.usage(worktree); got != want {
+		t.Errorf("usage() = %+v, want %+v", got, want)
+	}
+
+	if latest, err := newCodexTranscripts(filepath.Join(root, "missing")).latest(worktree); err != nil || latest != nil {
+		t.Errorf("without any rollouts latest() = %+v, %v", latest, err)
+	}
+}
+
+func TestOpencodeTranscripts(t *testing.T) {
+	root := t.TempDir()
+	worktree := filepath.Join(root, "auth")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	storage := filepath.Join(root, "storage")
+	updated := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	session := func(id, dir string, updated time.Time) string {
+		return `{"id":"` + id + `","directory":"` + dir + `","time":{"created":0,"updated":` + strconv.Itoa(int(updated.UnixMilli())) + `}}`
+	}
+	writeFile(t, filepath.Join(storage, "session", "p1", "ses_a.json"), session("ses_a", worktree, updated.Add(-time.Hour)), updated)
+	writeFile(t, filepath.Join(storage, "session", "p1", "ses_b.json"), session("ses_b", worktree, updated), updated)
+	writeFile(t, filepath.Join(storage, "session", "p2", "ses_c.json"), session("ses_c", root, updated.Add(time.Hour)), updated)
+	writeFile(t, filepath.Join(storage, "message", "ses_b", "msg_1.json"),
+		`{"role":"assistant","time":{"created":`+strconv.Itoa(int(updated.UnixMilli()))+`},"cost":0.25,"tokens":{"input":10,"output":20,"reasoning":5,"cache":{"read":100,"write":0}}}`, updated)
+	writeFile(t, filepath.Join(storage, "message", "ses_b", "msg_0.json"), `{"role":"user","time":{"created":1}}`, updated)
+
+	transcripts := opencodeTranscripts{dir: storage}
+	latest, err := transcripts.latest(worktree)
+	if err != nil || latest == nil || latest.ID != "ses_b" || !latest.Updated.Equal(updated) {
+		t.Fatalf("latest() = %+v, %v, want ses_b", latest, err)
+	}
+	if got := transcripts.tokens(worktree, claude.UsageMonth(updated)); got != 135 {
+		t.Errorf("tokens() = %d, want 135", got)
+	}
+	want := types.Usage{InputTokens: 10, OutputTokens: 25, CacheReadTokens: 100, Cost: 0.25}
+	if got := transcripts.usage(worktree); got != want {
+		t.Errorf("usage() = %+v, want %+v", got, want)
+	}
+}
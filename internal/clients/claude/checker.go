@@ -1,13 +1,9 @@
 package claude
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
@@ -18,6 +14,8 @@ import (
 type Checker interface {
 	GetStatus(worktreePath string) types.ClaudeStatus
 	FindSessionID(worktreePath string) (string, error)
+	TokenUsage(worktreePath, month string) int64  // Tokens used in a UsageMonth
+	SessionUsage(worktreePath string) types.Usage // Used by all conversations in the worktree
 }
 
 // RealChecker implements Checker using actual Claude session detection
@@ -43,7 +41,12 @@ func (r *RealChecker) GetStatus(worktreePath string) types.ClaudeStatus {
 
 	// Use scanner to find the most recent Claude session
 	claudeSession, err := r.scanner.GetMostRecentSession(worktreePath)
-	if err != nil || claudeSession == nil {
+	if err != nil {
+		return status
+	}
+	if claudeSession == nil {
+		// Claude writes a transcript as soon as it starts
+		status.State = types.ClaudeNotStarted
 		return status
 	}
 
@@ -55,7 +58,7 @@ func (r *RealChecker) GetStatus(worktreePath string) types.ClaudeStatus {
 	if err != nil {
 		// Fallback to session metadata if JSONL parsing fails
 		status.LastMessage = claudeSession.LastSeen
-		status.Availability = r.calculateAvailability(claudeSession.LastSeen)
+		status.Availability = Availability(claudeSession.LastSeen)
 		return status
 	}
 
@@ -74,7 +77,7 @@ func (r *RealChecker) GetStatus(worktreePath string) types.ClaudeStatus {
 	}
 
 	// Calculate availability from timestamp
-	status.Availability = r.calculateAvailability(lastMessage.Timestamp)
+	status.Availability = Availability(lastMessage.Timestamp)
 
 	return status
 }
@@ -95,70 +98,16 @@ func (r *RealChecker) FindSessionID(worktreePath string) (string, error) {
 }
 
 func (r *RealChecker) parseLastMessage(jsonlPath string) (types.ClaudeMessage, error) {
-	file, err := os.Open(jsonlPath)
+	t, err := r.scanner.transcripts.get(jsonlPath)
 	if err != nil {
 		return types.ClaudeMessage{}, err
 	}
-	defer file.Close()
-
-	var lastMessage types.ClaudeMessage
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
 
-		var rawMessage map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &rawMessage); err != nil {
-			continue
-		}
-
-		// Parse message structure
-		if msg, ok := rawMessage["message"].(map[string]interface{}); ok {
-			claudeMsg := types.ClaudeMessage{}
-
-			if role, ok := msg["role"].(string); ok {
-				claudeMsg.Role = role
-			}
-
-			if content, ok := msg["content"].([]interface{}); ok {
-				for _, c := range content {
-					if contentMap, ok := c.(map[string]interface{}); ok {
-						contentItem := types.Content{}
-						if contentType, ok := contentMap["type"].(string); ok {
-							contentItem.Type = contentType
-						}
-						if text, ok := contentMap["text"].(string); ok {
-							contentItem.Text = text
-						}
-						if name, ok := contentMap["name"].(string); ok {
-							contentItem.Name = name
-						}
-						claudeMsg.Content = append(claudeMsg.Content, contentItem)
-					}
-				}
-			}
-
-			// Parse timestamp
-			if timestamp, ok := rawMessage["timestamp"].(string); ok {
-				if parsed, err := time.Parse(time.RFC3339, timestamp); err == nil {
-					claudeMsg.Timestamp = parsed
-				}
-			}
-
-			if claudeMsg.Role == "assistant" {
-				lastMessage = claudeMsg
-			}
-		}
-	}
-
-	if lastMessage.Role == "" {
+	if t.LastAssistant == nil {
 		return types.ClaudeMessage{}, fmt.Errorf("no assistant messages found in JSONL")
 	}
 
-	return lastMessage, nil
+	return *t.LastAssistant, nil
 }
 
 func (r *RealChecker) determineStateFromMessage(message types.ClaudeMessage) types.ClaudeState {
@@ -212,7 +161,8 @@ func (r *RealChecker) deriveTmuxSessionName(worktreePath string) string {
 	return fmt.Sprintf("cwt-%s", base)
 }
 
-func (r *RealChecker) calculateAvailability(timestamp time.Time) types.Availability {
+// Availability says how fresh status last updated at timestamp is
+func Availability(timestamp time.Time) types.Availability {
 	if timestamp.IsZero() {
 		return types.AvailVeryStale
 	}
@@ -231,9 +181,38 @@ func (r *RealChecker) calculateAvailability(timestamp time.Time) types.Availabil
 	}
 }
 
+// TokenUsage adds up the tokens used in a month by all Claude sessions
+// that ran in a worktree
+func (r *RealChecker) TokenUsage(worktreePath, month string) int64 {
+	sessions, err := r.scanner.FindSessionsForDirectory(worktreePath)
+	if err != nil {
+		return 0
+	}
+	var tokens int64
+	for _, session := range sessions {
+		tokens += session.Tokens[month]
+	}
+	return tokens
+}
+
+// SessionUsage adds up what all Claude sessions that ran in a worktree used
+func (r *RealChecker) SessionUsage(worktreePath string) types.Usage {
+	sessions, err := r.scanner.FindSessionsForDirectory(worktreePath)
+	if err != nil {
+		return types.Usage{}
+	}
+	var usage types.Usage
+	for _, session := range sessions {
+		usage = usage.Add(session.Usage)
+	}
+	return usage
+}
+
 // MockChecker implements Checker for testing
 type MockChecker struct {
 	Statuses map[string]types.ClaudeStatus
+	Usage    map[string]int64       // Tokens by worktree path, whatever the month
+	Sessions map[string]types.Usage // Session usage by worktree path
 	Delay    time.Duration
 }
 
@@ -241,6 +220,8 @@ type MockChecker struct {
 func NewMockChecker() *MockChecker {
 	return &MockChecker{
 		Statuses: make(map[string]types.ClaudeStatus),
+		Usage:    make(map[string]int64),
+		Sessions: make(map[string]types.Usage),
 	}
 }
 
@@ -268,6 +249,16 @@ func (m *MockChecker) FindSessionID(worktreePath string) (string, error) {
 	return fmt.Sprintf("mock-session-%s", filepath.Base(worktreePath)), nil
 }
 
+// TokenUsage returns the mocked token usage
+func (m *MockChecker) TokenUsage(worktreePath, month string) int64 {
+	return m.Usage[worktreePath]
+}
+
+// SessionUsage returns the mocked session usage
+func (m *MockChecker) SessionUsage(worktreePath string) types.Usage {
+	return m.Sessions[worktreePath]
+}
+
 // SetStatus sets the Claude status for testing
 func (m *MockChecker) SetStatus(worktreePath string, status types.ClaudeStatus) {
 	m.Statuses[worktreePath] = status
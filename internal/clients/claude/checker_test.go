@@ -0,0 +1,16 @@
+package claude
+
+import (
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestRealChecker_GetStatus_NotStarted(t *testing.T) {
+	checker := &RealChecker{scanner: &SessionScanner{claudeDir: t.TempDir(), transcripts: newTranscriptCache()}}
+
+	status := checker.GetStatus(t.TempDir())
+	if status.State != types.ClaudeNotStarted {
+		t.Errorf("GetStatus() state = %s, want %s for a worktree without transcripts", status.State, types.ClaudeNotStarted)
+	}
+}
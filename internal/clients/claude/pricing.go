@@ -0,0 +1,71 @@
+package claude
+
+import (
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// modelPrice is what a model charges per million tokens, in US dollars.
+// Writes to the prompt cache cost 1.25 times input, reads a tenth of it.
+type modelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// modelPrices are the list prices of Claude models, matched against the
+// model a transcript names by prefix, in order. Models of a family not
+// listed by version are priced like its latest one.
+var modelPrices = []struct {
+	prefix string
+	price  modelPrice
+}{
+	{"claude-opus-4-5", modelPrice{5, 25}},
+	{"claude-opus-4-1", modelPrice{15, 75}},
+	{"claude-opus-4-2025", modelPrice{15, 75}},
+	{"claude-3-opus", modelPrice{15, 75}},
+	{"claude-3-5-haiku", modelPrice{0.8, 4}},
+	{"claude-3-haiku", modelPrice{0.25, 1.25}},
+}
+
+// familyPrices price the models of each family modelPrices doesn't list
+var familyPrices = []struct {
+	family string
+	price  modelPrice
+}{
+	{"opus", modelPrice{5, 25}},
+	{"sonnet", modelPrice{3, 15}},
+	{"haiku", modelPrice{1, 5}},
+}
+
+// priceOf returns the price of a model, and false for models that aren't
+// Claude's, like the placeholder of messages Claude Code makes up itself
+func priceOf(model string) (modelPrice, bool) {
+	for _, known := range modelPrices {
+		if strings.HasPrefix(model, known.prefix) {
+			return known.price, true
+		}
+	}
+	if !strings.HasPrefix(model, "claude-") {
+		return modelPrice{}, false
+	}
+	for _, known := range familyPrices {
+		if strings.Contains(model, known.family) {
+			return known.price, true
+		}
+	}
+	return modelPrice{}, false
+}
+
+// estimateCost returns what a model charges for usage, in US dollars
+func estimateCost(model string, usage types.Usage) float64 {
+	price, ok := priceOf(model)
+	if !ok {
+		return 0
+	}
+	perToken := func(perMillion float64) float64 { return perMillion / 1_000_000 }
+	return float64(usage.InputTokens)*perToken(price.Input) +
+		float64(usage.CacheWriteTokens)*perToken(price.Input*1.25) +
+		float64(usage.CacheReadTokens)*perToken(price.Input*0.1) +
+		float64(usage.OutputTokens)*perToken(price.Output)
+}
@@ -0,0 +1,195 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ProjectFiles are where Claude reads a project's instructions, settings,
+// slash commands and subagents from, relative to the project's root
+var ProjectFiles = []string{
+	"CLAUDE.md",
+	"CLAUDE.local.md",
+	".claude/CLAUDE.md",
+	".claude/settings.json",
+	".claude/settings.local.json",
+	".claude/commands",
+	".claude/agents",
+}
+
+// importPattern matches the @path imports of a CLAUDE.md file
+var importPattern = regexp.MustCompile(`(^|[\s(])@([^\s()]+)`)
+
+// Relocation maps the files of a project's main checkout onto one of its
+// worktrees, which sits elsewhere and may lack the files git doesn't track
+type Relocation struct {
+	RepoDir     string // Absolute path of the main checkout
+	WorktreeDir string // Absolute path of the worktree
+}
+
+// Relocate adjusts a project file at rel in the worktree so the paths in it
+// lead where they do from the main checkout. Instructions have their
+// imports adjusted and settings their paths; other files are left alone.
+// It reports whether anything changed.
+func (r Relocation) Relocate(rel string, data []byte) ([]byte, bool, error) {
+	switch filepath.Base(rel) {
+	case "CLAUDE.md", "CLAUDE.local.md":
+		return r.relocateImports(rel, data)
+	case "settings.json", "settings.local.json":
+		return r.relocateSettings(data)
+	}
+	return data, false, nil
+}
+
+// relocateImports rewrites the imports of instructions at rel that the
+// worktree can't resolve the way the main checkout does: those leaving the
+// repository, whose relative path differs from the worktree, and those of
+// files the worktree lacks, like untracked notes. They become absolute
+// paths into the main checkout. Imports in code are left alone, as Claude
+// doesn't read them.
+func (r Relocation) relocateImports(rel string, data []byte) ([]byte, bool, error) {
+	dir := filepath.Join(r.RepoDir, filepath.Dir(filepath.FromSlash(rel)))
+	lines := strings.Split(string(data), "\n")
+	var fenced, changed bool
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		var out strings.Builder
+		last := 0
+		for _, match := range importPattern.FindAllStringSubmatchIndex(line, -1) {
+			start, end := match[4], match[5]
+			// Inside an inline code span
+			if strings.Count(line[:start], "`")%2 == 1 {
+				continue
+			}
+			path := strings.TrimRight(line[start:end], ".,;:!?")
+			end = start + len(path)
+			if relocated, ok := r.relocateImport(dir, path); ok {
+				out.WriteString(line[last:start])
+				out.WriteString(relocated)
+				last = end
+			}
+		}
+		if last > 0 {
+			out.WriteString(line[last:])
+			lines[i] = out.String()
+			changed = true
+		}
+	}
+	if !changed {
+		return data, false, nil
+	}
+	return []byte(strings.Join(lines, "\n")), true, nil
+}
+
+// relocateImport returns the absolute path an import made from dir in the
+// main checkout should become in the worktree, if it has to change
+func (r Relocation) relocateImport(dir, path string) (string, bool) {
+	if strings.HasPrefix(path, "~") || filepath.IsAbs(path) ||
+		(!strings.Contains(path, "/") && filepath.Ext(path) == "") {
+		return "", false
+	}
+	source := filepath.Join(dir, filepath.FromSlash(path))
+	if _, err := os.Stat(source); err != nil {
+		return "", false
+	}
+	if rel, err := filepath.Rel(r.RepoDir, source); err == nil && filepath.IsLocal(rel) {
+		if _, err := os.Stat(filepath.Join(r.WorktreeDir, rel)); err == nil {
+			return "", false
+		}
+	}
+	return source, true
+}
+
+// relocateSettings points the absolute paths into the main checkout that
+// settings hold, like a hook's script, at the worktree, and makes the
+// additional directories that lie outside the repository absolute
+func (r Relocation) relocateSettings(data []byte) ([]byte, bool, error) {
+	settings, err := parseSettings(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var changed bool
+	var walk func(value interface{}) interface{}
+	walk = func(value interface{}) interface{} {
+		switch value := value.(type) {
+		case string:
+			relocated := r.relocatePath(value)
+			changed = changed || relocated != value
+			return relocated
+		case []interface{}:
+			for i := range value {
+				value[i] = walk(value[i])
+			}
+		case map[string]interface{}:
+			for key := range value {
+				value[key] = walk(value[key])
+			}
+		}
+		return value
+	}
+	walk(settings)
+
+	if permissions, ok := settings["permissions"].(map[string]interface{}); ok {
+		dirs, _ := permissions["additionalDirectories"].([]interface{})
+		for i, value := range dirs {
+			dir, ok := value.(string)
+			if !ok || dir == "" || filepath.IsAbs(dir) || strings.HasPrefix(dir, "~") {
+				continue
+			}
+			if rel, err := filepath.Rel(r.RepoDir, filepath.Join(r.RepoDir, dir)); err == nil && !filepath.IsLocal(rel) {
+				dirs[i] = filepath.Join(r.RepoDir, dir)
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return data, false, nil
+	}
+	encoded, err := encodeSettings(settings)
+	return encoded, err == nil, err
+}
+
+// relocatePath replaces the main checkout's path in s with the worktree's,
+// wherever it is a whole path or a path's start. A worktree inside the main
+// checkout's path is left as it is, so relocating twice changes nothing.
+func (r Relocation) relocatePath(s string) string {
+	var out strings.Builder
+	for {
+		i := strings.Index(s, r.RepoDir)
+		if i < 0 {
+			break
+		}
+		rest := s[i+len(r.RepoDir):]
+		switch {
+		case strings.HasPrefix(s[i:], r.WorktreeDir) && pathEnds(s[i+len(r.WorktreeDir):]):
+			out.WriteString(s[:i+len(r.WorktreeDir)])
+			s = s[i+len(r.WorktreeDir):]
+		case pathEnds(rest):
+			out.WriteString(s[:i] + r.WorktreeDir)
+			s = rest
+		default:
+			out.WriteString(s[:i+len(r.RepoDir)])
+			s = rest
+		}
+	}
+	out.WriteString(s)
+	return out.String()
+}
+
+// pathEnds reports whether what follows a directory's path in a string
+// leaves it a path or a path's start, rather than a longer name
+func pathEnds(rest string) bool {
+	return rest == "" || strings.ContainsRune("/ \t\"':;", rune(rest[0]))
+}
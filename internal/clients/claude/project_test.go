@@ -0,0 +1,86 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRelocation_Imports(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	worktree := filepath.Join(repo, ".cwt", "worktrees", "auth")
+	for _, path := range []string{
+		"shared/style.md",
+		"repo/docs/api.md",
+		"repo/notes.md",
+		"repo/.cwt/worktrees/auth/docs/api.md",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, path), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	relocation := Relocation{RepoDir: repo, WorktreeDir: worktree}
+
+	instructions := strings.Join([]string{
+		"See @../shared/style.md and @docs/api.md.",
+		"Private: @notes.md, mention @alice, missing @gone.md",
+		"`@../shared/style.md` stays in code",
+		"```",
+		"@../shared/style.md",
+		"```",
+	}, "\n")
+	data, changed, err := relocation.Relocate("CLAUDE.md", []byte(instructions))
+	if err != nil || !changed {
+		t.Fatalf("Relocate() = %v, %v", changed, err)
+	}
+	want := strings.Join([]string{
+		"See @" + filepath.Join(root, "shared", "style.md") + " and @docs/api.md.",
+		"Private: @" + filepath.Join(repo, "notes.md") + ", mention @alice, missing @gone.md",
+		"`@../shared/style.md` stays in code",
+		"```",
+		"@../shared/style.md",
+		"```",
+	}, "\n")
+	if string(data) != want {
+		t.Errorf("Relocate() =\n%s\nwant\n%s", data, want)
+	}
+
+	if _, changed, _ := relocation.Relocate("CLAUDE.md", data); changed {
+		t.Error("relocating twice should change nothing")
+	}
+	if _, changed, _ := relocation.Relocate(".claude/commands/review.md", []byte(instructions)); changed {
+		t.Error("only instructions have their imports adjusted")
+	}
+}
+
+func TestRelocation_Settings(t *testing.T) {
+	relocation := Relocation{RepoDir: "/src/repo", WorktreeDir: "/src/repo/.cwt/worktrees/auth"}
+	settings := `{
+  "hooks": {"PostToolUse": [{"matcher": "Edit", "hooks": [{"type": "command", "command": "/src/repo/scripts/lint.sh && /src/repo-tools/x"}]}]},
+  "permissions": {"additionalDirectories": ["../shared", "docs", "/opt/data"]}
+}`
+	data, changed, err := relocation.Relocate(".claude/settings.local.json", []byte(settings))
+	if err != nil || !changed {
+		t.Fatalf("Relocate() = %v, %v", changed, err)
+	}
+	for _, want := range []string{
+		`"/src/repo/.cwt/worktrees/auth/scripts/lint.sh && /src/repo-tools/x"`,
+		`"/src/shared"`, `"docs"`, `"/opt/data"`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("relocated settings lack %s:\n%s", want, data)
+		}
+	}
+	if _, changed, _ := relocation.Relocate(".claude/settings.local.json", data); changed {
+		t.Error("relocating twice should change nothing")
+	}
+
+	if _, _, err := relocation.Relocate(".claude/settings.json", []byte("{broken")); err == nil {
+		t.Error("expected invalid settings to be an error")
+	}
+}
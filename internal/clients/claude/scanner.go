@@ -1,14 +1,14 @@
 package claude
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
 // ClaudeSession represents a Claude Code session from JSONL
@@ -18,18 +18,23 @@ type ClaudeSession struct {
 	LastSeen     time.Time `json:"lastSeen"`
 	FilePath     string    `json:"filePath"`
 	MessageCount int       `json:"messageCount"`
+
+	Tokens map[string]int64 `json:"-"` // Tokens used, by UsageMonth
+	Usage  types.Usage      `json:"-"` // Used over the whole session
 }
 
 // SessionScanner discovers Claude Code sessions
 type SessionScanner struct {
-	claudeDir string
+	claudeDir   string
+	transcripts *transcriptCache // Parsed transcripts, re-read only when appended to
 }
 
 // NewSessionScanner creates a new Claude session scanner
 func NewSessionScanner() *SessionScanner {
 	homeDir, _ := os.UserHomeDir()
 	return &SessionScanner{
-		claudeDir: filepath.Join(homeDir, ".claude"),
+		claudeDir:   filepath.Join(homeDir, ".claude"),
+		transcripts: newTranscriptCache(),
 	}
 }
 
@@ -94,63 +99,29 @@ func (s *SessionScanner) GetMostRecentSession(targetDir string) (*ClaudeSession,
 
 // parseSessionFile extracts session metadata from a JSONL file
 func (s *SessionScanner) parseSessionFile(filePath, targetDir string) (*ClaudeSession, error) {
-	file, err := os.Open(filePath)
+	t, err := s.transcripts.get(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	var sessionID string
-	var cwd string
-	var lastSeen time.Time
-	var messageCount int
-
-	// Read each line of JSONL
-	for scanner.Scan() {
-		var line map[string]interface{}
-		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
-			continue // Skip invalid JSON lines
-		}
-
-		// Extract session metadata from first message
-		if sessionID == "" {
-			if sid, ok := line["sessionId"].(string); ok {
-				sessionID = sid
-			}
-			if c, ok := line["cwd"].(string); ok {
-				cwd = c
-			}
-		}
-
-		// Track last activity timestamp
-		if timestampStr, ok := line["timestamp"].(string); ok {
-			if t, err := time.Parse(time.RFC3339, timestampStr); err == nil {
-				if t.After(lastSeen) {
-					lastSeen = t
-				}
-			}
-		}
-
-		messageCount++
-	}
 
 	// Only return session if it matches our target directory exactly
-	if cwd != targetDir {
+	if t.CWD != targetDir {
 		return nil, nil
 	}
 
 	// Must have valid session ID and recent activity
-	if sessionID == "" || lastSeen.IsZero() {
+	if t.SessionID == "" || t.LastSeen.IsZero() {
 		return nil, nil
 	}
 
 	return &ClaudeSession{
-		SessionID:    sessionID,
-		CWD:          cwd,
-		LastSeen:     lastSeen,
+		SessionID:    t.SessionID,
+		CWD:          t.CWD,
+		LastSeen:     t.LastSeen,
 		FilePath:     filePath,
-		MessageCount: messageCount,
+		MessageCount: t.MessageCount,
+		Tokens:       t.Tokens,
+		Usage:        t.Usage,
 	}, nil
 }
 
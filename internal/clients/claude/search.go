@@ -0,0 +1,119 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// maxTranscriptLine bounds a single JSONL entry; tool results holding whole
+// files can be large
+const maxTranscriptLine = 16 * 1024 * 1024
+
+// TranscriptMatch is a line of a transcript message matching a search
+type TranscriptMatch struct {
+	Entry int // 1-based line of the JSONL file
+	Text  string
+}
+
+// SearchTranscript returns the lines of the messages in a transcript that
+// match. Prompts, replies, tool inputs and tool results are searched; the
+// JSON around them is not, so matches read as they appeared in the session.
+func SearchTranscript(path string, match func(string) bool) ([]TranscriptMatch, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	var matches []TranscriptMatch
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxTranscriptLine)
+	for entry := 1; scanner.Scan(); entry++ {
+		var raw struct {
+			Message struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil {
+			continue // Skip invalid JSON lines
+		}
+
+		for _, text := range contentTexts(raw.Message.Content) {
+			for _, line := range strings.Split(text, "\n") {
+				if match(line) {
+					matches = append(matches, TranscriptMatch{
+						Entry: entry,
+						Text:  strings.TrimSpace(line),
+					})
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return matches, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return matches, nil
+}
+
+// contentTexts returns the readable text of a message's content, which is
+// either a plain string or a list of text, tool_use and tool_result items
+func contentTexts(content json.RawMessage) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return []string{text}
+	}
+
+	var items []struct {
+		Type    string          `json:"type"`
+		Text    string          `json:"text"`
+		Input   json.RawMessage `json:"input"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil
+	}
+
+	var texts []string
+	for _, item := range items {
+		switch item.Type {
+		case "text":
+			texts = append(texts, item.Text)
+		case "tool_use":
+			texts = append(texts, toolInputTexts(item.Input)...)
+		case "tool_result":
+			texts = append(texts, contentTexts(item.Content)...)
+		}
+	}
+	return texts
+}
+
+// toolInputTexts returns the string arguments of a tool call, such as the
+// old and new strings of an edit or the command of a Bash call
+func toolInputTexts(input json.RawMessage) []string {
+	var args map[string]interface{}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var texts []string
+	for _, key := range keys {
+		if s, ok := args[key].(string); ok {
+			texts = append(texts, s)
+		}
+	}
+	return texts
+}
@@ -0,0 +1,35 @@
+package claude
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearchTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	appendToFile(t, path, `{"sessionId":"s1","message":{"role":"user","content":"Fix the TODO(auth) in login.go"}}
+not json
+{"message":{"role":"assistant","content":[{"type":"text","text":"Looking at it.\nThe TODO(auth) is about expiry."},{"type":"tool_use","name":"Edit","input":{"file_path":"login.go","old_string":"// TODO(auth): expiry","new_string":"checkExpiry()"}}]}}
+{"message":{"role":"user","content":[{"type":"tool_result","content":[{"type":"text","text":"no TODO here"}]}]}}
+`)
+
+	matches, err := SearchTranscript(path, func(s string) bool { return strings.Contains(s, "TODO(auth)") })
+	if err != nil {
+		t.Fatalf("SearchTranscript() error = %v", err)
+	}
+
+	want := []TranscriptMatch{
+		{Entry: 1, Text: "Fix the TODO(auth) in login.go"},
+		{Entry: 3, Text: "The TODO(auth) is about expiry."},
+		{Entry: 3, Text: "// TODO(auth): expiry"},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("matches = %+v, want %+v", matches, want)
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("match %d = %+v, want %+v", i, matches[i], want[i])
+		}
+	}
+}
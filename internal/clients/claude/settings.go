@@ -0,0 +1,221 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HookEvents are the Claude hook events cwt listens to, with the event
+// names 'cwt __hook' takes for them
+var HookEvents = []struct{ Claude, Cwt string }{
+	{"Notification", "notification"},
+	{"Stop", "stop"},
+	{"PreToolUse", "pre_tool_use"},
+	{"PostToolUse", "post_tool_use"},
+	{"SubagentStop", "subagent_stop"},
+	{"PreCompact", "pre_compact"},
+}
+
+// IsCwtHookCommand reports whether a hook command is one cwt added
+func IsCwtHookCommand(command string) bool {
+	return strings.Contains(command, " __hook ")
+}
+
+// MergeHookSettings adds cwt's hooks for a session to the Claude settings
+// file at path, creating it if need be. Whatever else the file holds, the
+// project's own hooks included, is kept; cwt hooks already there, like
+// those with a stale executable path, are replaced. It reports whether the
+// file changed.
+func MergeHookSettings(path, cwtPath, sessionID string) (bool, error) {
+	settings, original, err := readSettings(path)
+	if err != nil {
+		return false, err
+	}
+	hooks, err := settingsHooks(settings)
+	if err != nil {
+		return false, err
+	}
+	stripCwtHooks(hooks)
+
+	for _, event := range HookEvents {
+		groups, _ := hooks[event.Claude].([]interface{})
+		hooks[event.Claude] = append(groups, map[string]interface{}{
+			"matcher": "",
+			"hooks": []interface{}{
+				map[string]interface{}{
+					"type":    "command",
+					"command": fmt.Sprintf("%s __hook %s %s", cwtPath, sessionID, event.Cwt),
+				},
+			},
+		})
+	}
+	settings["hooks"] = hooks
+
+	return writeSettings(path, settings, original)
+}
+
+// RemoveHookSettings takes cwt's hooks back out of the Claude settings file
+// at path, leaving the project's settings as they were merged into. A file
+// left with nothing in it is removed, as is its .claude directory if that
+// is then empty.
+func RemoveHookSettings(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	settings, original, err := readSettings(path)
+	if err != nil {
+		return err
+	}
+	hooks, err := settingsHooks(settings)
+	if err != nil {
+		return err
+	}
+	if !stripCwtHooks(hooks) {
+		return nil
+	}
+	if len(hooks) == 0 {
+		delete(settings, "hooks")
+	} else {
+		settings["hooks"] = hooks
+	}
+
+	if len(settings) == 0 {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove Claude settings: %w", err)
+		}
+		// Only removed when empty, so nothing else in it is lost
+		os.Remove(filepath.Dir(path))
+		return nil
+	}
+	_, err = writeSettings(path, settings, original)
+	return err
+}
+
+// readSettings reads a Claude settings file, returning an empty one when
+// there is none. A file that isn't valid JSON is an error rather than
+// something to overwrite.
+func readSettings(path string) (map[string]interface{}, []byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]interface{}{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Claude settings: %w", err)
+	}
+
+	settings, err := parseSettings(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Claude settings %s: %w", path, err)
+	}
+	return settings, data, nil
+}
+
+// parseSettings parses Claude settings; empty ones are an empty object
+func parseSettings(data []byte) (map[string]interface{}, error) {
+	settings := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return settings, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are kept as written rather than turned into floats
+	decoder.UseNumber()
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	return settings, nil
+}
+
+// settingsHooks returns the hooks of Claude settings, by event
+func settingsHooks(settings map[string]interface{}) (map[string]interface{}, error) {
+	switch hooks := settings["hooks"].(type) {
+	case nil:
+		return map[string]interface{}{}, nil
+	case map[string]interface{}:
+		return hooks, nil
+	}
+	return nil, fmt.Errorf("unexpected \"hooks\" in Claude settings: want an object")
+}
+
+// stripCwtHooks removes cwt's hook commands from hooks, dropping matcher
+// groups and events left without any. It reports whether there were any.
+func stripCwtHooks(hooks map[string]interface{}) bool {
+	var stripped bool
+	for event, value := range hooks {
+		groups, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		var kept []interface{}
+		for _, value := range groups {
+			group, ok := value.(map[string]interface{})
+			commands, isList := group["hooks"].([]interface{})
+			if !ok || !isList {
+				kept = append(kept, value)
+				continue
+			}
+			var remaining []interface{}
+			for _, value := range commands {
+				command, _ := value.(map[string]interface{})
+				if text, _ := command["command"].(string); IsCwtHookCommand(text) {
+					stripped = true
+					continue
+				}
+				remaining = append(remaining, value)
+			}
+			if len(remaining) == 0 && len(commands) > 0 {
+				continue
+			}
+			if len(remaining) < len(commands) {
+				group["hooks"] = remaining
+			}
+			kept = append(kept, group)
+		}
+		if len(kept) == 0 {
+			delete(hooks, event)
+		} else {
+			hooks[event] = kept
+		}
+	}
+	return stripped
+}
+
+// writeSettings writes Claude settings unless they are what the file holds
+// already, reporting whether it wrote them
+func writeSettings(path string, settings map[string]interface{}, original []byte) (bool, error) {
+	data, err := encodeSettings(settings)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(bytes.TrimSpace(original), data) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, fmt.Errorf("failed to create .claude directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return false, fmt.Errorf("failed to write Claude settings: %w", err)
+	}
+	return true, nil
+}
+
+// encodeSettings formats Claude settings as cwt writes them
+func encodeSettings(settings map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// Hook commands are shell: && is to stay &&, not become \u0026\u0026
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(settings); err != nil {
+		return nil, fmt.Errorf("failed to marshal Claude settings: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
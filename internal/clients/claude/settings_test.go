@@ -0,0 +1,125 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeHookSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	project := `{
+  "model": "opus",
+  "cleanupPeriodDays": 30,
+  "hooks": {
+    "PostToolUse": [
+      {"matcher": "Edit", "hooks": [{"type": "command", "command": "make fmt && make lint"}]}
+    ]
+  }
+}`
+	if err := os.WriteFile(path, []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := MergeHookSettings(path, "/old/cwt", "session-1")
+	if err != nil || !changed {
+		t.Fatalf("MergeHookSettings() = %v, %v", changed, err)
+	}
+	// Merging again with a new executable replaces cwt's hooks
+	if _, err := MergeHookSettings(path, "/usr/bin/cwt", "session-1"); err != nil {
+		t.Fatalf("MergeHookSettings() error = %v", err)
+	}
+	if changed, err := MergeHookSettings(path, "/usr/bin/cwt", "session-1"); err != nil || changed {
+		t.Errorf("merging the same hooks again = %v, %v, want no change", changed, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "/old/cwt") || strings.Contains(string(data), `\u0026`) {
+		t.Errorf("unexpected settings:\n%s", data)
+	}
+	var settings struct {
+		Model             string          `json:"model"`
+		CleanupPeriodDays json.RawMessage `json:"cleanupPeriodDays"`
+		Hooks             map[string][]struct {
+			Matcher string `json:"matcher"`
+			Hooks   []struct {
+				Command string `json:"command"`
+			} `json:"hooks"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Model != "opus" || string(settings.CleanupPeriodDays) != "30" {
+		t.Errorf("project settings were not kept: %+v", settings)
+	}
+	if len(settings.Hooks) != len(HookEvents) {
+		t.Errorf("expected hooks for %d events, got %d", len(HookEvents), len(settings.Hooks))
+	}
+	post := settings.Hooks["PostToolUse"]
+	if len(post) != 2 || post[0].Hooks[0].Command != "make fmt && make lint" ||
+		post[1].Hooks[0].Command != "/usr/bin/cwt __hook session-1 post_tool_use" {
+		t.Errorf("PostToolUse should keep the project's hook, then add cwt's: %+v", post)
+	}
+
+	if err := RemoveHookSettings(path); err != nil {
+		t.Fatalf("RemoveHookSettings() error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "__hook") || !strings.Contains(string(data), "make fmt && make lint") {
+		t.Errorf("only cwt's hooks should be removed:\n%s", data)
+	}
+	if strings.Contains(string(data), `"Stop"`) {
+		t.Errorf("events left without hooks should be removed:\n%s", data)
+	}
+}
+
+func TestRemoveHookSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".claude", "settings.json")
+
+	// Settings cwt created are removed with their directory
+	if _, err := MergeHookSettings(path, "cwt", "session-1"); err != nil {
+		t.Fatalf("MergeHookSettings() error = %v", err)
+	}
+	if err := RemoveHookSettings(path); err != nil {
+		t.Fatalf("RemoveHookSettings() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(path)); !os.IsNotExist(err) {
+		t.Errorf(".claude should be removed, got %v", err)
+	}
+	if err := RemoveHookSettings(path); err != nil {
+		t.Errorf("RemoveHookSettings() without settings error = %v", err)
+	}
+
+	// A project's own empty settings are left alone
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveHookSettings(path); err != nil {
+		t.Fatalf("RemoveHookSettings() error = %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "{}\n" {
+		t.Errorf("project settings changed: %q, %v", data, err)
+	}
+
+	// Invalid settings aren't overwritten
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MergeHookSettings(path, "cwt", "session-1"); err == nil {
+		t.Error("expected invalid settings to be an error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{not json" {
+		t.Errorf("invalid settings were overwritten: %q", data)
+	}
+}
@@ -0,0 +1,200 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSummaryText bounds the prompt and reply quoted in a summary
+const maxSummaryText = 2000
+
+// TranscriptSummary is an overview of one Claude conversation, kept when
+// its session is archived
+type TranscriptSummary struct {
+	SessionID   string
+	Started     time.Time
+	Ended       time.Time
+	Prompts     int            // Messages the user sent, not counting tool results
+	Replies     int            // Assistant messages with text
+	ToolCalls   map[string]int // Tool uses by tool name
+	Files       []string       // Files Claude's tools wrote, in the order first written
+	FirstPrompt string
+	LastReply   string
+}
+
+// fileWritingTools are the tools that change the file named in their input
+var fileWritingTools = map[string]bool{"Edit": true, "MultiEdit": true, "Write": true, "NotebookEdit": true}
+
+// SummarizeTranscript reads a Claude JSONL transcript into a summary
+func SummarizeTranscript(path string) (TranscriptSummary, error) {
+	summary := TranscriptSummary{ToolCalls: make(map[string]int)}
+	written := make(map[string]bool)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return summary, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxTranscriptLine)
+	for scanner.Scan() {
+		var entry struct {
+			Type      string `json:"type"`
+			SessionID string `json:"sessionId"`
+			Timestamp string `json:"timestamp"`
+			Message   struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip invalid JSON lines
+		}
+
+		if summary.SessionID == "" {
+			summary.SessionID = entry.SessionID
+		}
+		if timestamp, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+			if summary.Started.IsZero() || timestamp.Before(summary.Started) {
+				summary.Started = timestamp
+			}
+			if timestamp.After(summary.Ended) {
+				summary.Ended = timestamp
+			}
+		}
+
+		text, tools := messageParts(entry.Message.Content)
+		switch entry.Type {
+		case "user":
+			if text != "" {
+				summary.Prompts++
+				if summary.FirstPrompt == "" {
+					summary.FirstPrompt = text
+				}
+			}
+		case "assistant":
+			if text != "" {
+				summary.Replies++
+				summary.LastReply = text
+			}
+			for _, tool := range tools {
+				summary.ToolCalls[tool.name]++
+				if tool.file != "" && fileWritingTools[tool.name] && !written[tool.file] {
+					written[tool.file] = true
+					summary.Files = append(summary.Files, tool.file)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return summary, nil
+}
+
+// toolUse is a tool called in a message, with the file it acts on if any
+type toolUse struct {
+	name string
+	file string
+}
+
+// messageParts returns the text of a message's content and the tools it
+// calls. Tool results sent back as user messages have no text.
+func messageParts(content json.RawMessage) (string, []toolUse) {
+	if len(content) == 0 {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return strings.TrimSpace(text), nil
+	}
+
+	var items []struct {
+		Type  string `json:"type"`
+		Text  string `json:"text"`
+		Name  string `json:"name"`
+		Input struct {
+			FilePath     string `json:"file_path"`
+			NotebookPath string `json:"notebook_path"`
+		} `json:"input"`
+	}
+	if err := json.Unmarshal(content, &items); err != nil {
+		return "", nil
+	}
+
+	var texts []string
+	var tools []toolUse
+	for _, item := range items {
+		switch item.Type {
+		case "text":
+			texts = append(texts, item.Text)
+		case "tool_use":
+			file := item.Input.FilePath
+			if file == "" {
+				file = item.Input.NotebookPath
+			}
+			tools = append(tools, toolUse{name: item.Name, file: file})
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n")), tools
+}
+
+// Tools lists the tools used with how often, like "Edit ×3", the most
+// used first
+func (s TranscriptSummary) Tools() []string {
+	tools := make([]string, 0, len(s.ToolCalls))
+	for tool := range s.ToolCalls {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if s.ToolCalls[tools[i]] != s.ToolCalls[tools[j]] {
+			return s.ToolCalls[tools[i]] > s.ToolCalls[tools[j]]
+		}
+		return tools[i] < tools[j]
+	})
+	for i, tool := range tools {
+		tools[i] = fmt.Sprintf("%s ×%d", tool, s.ToolCalls[tool])
+	}
+	return tools
+}
+
+// Markdown renders the summary as a section of a markdown document
+func (s TranscriptSummary) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Conversation %s\n\n", s.SessionID)
+	if !s.Started.IsZero() {
+		fmt.Fprintf(&b, "- Started: %s\n", s.Started.Format(time.RFC3339))
+		fmt.Fprintf(&b, "- Ended: %s\n", s.Ended.Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "- Prompts: %d, replies: %d\n", s.Prompts, s.Replies)
+
+	if tools := s.Tools(); len(tools) > 0 {
+		fmt.Fprintf(&b, "- Tools: %s\n", strings.Join(tools, ", "))
+	}
+
+	for _, part := range []struct{ title, text string }{
+		{"First prompt", s.FirstPrompt},
+		{"Last reply", s.LastReply},
+	} {
+		if part.text == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", part.title)
+		text := part.text
+		if len(text) > maxSummaryText {
+			text = strings.ToValidUTF8(text[:maxSummaryText], "") + "…"
+		}
+		for _, line := range strings.Split(text, "\n") {
+			fmt.Fprintf(&b, "> %s\n", line)
+		}
+	}
+
+	return b.String()
+}
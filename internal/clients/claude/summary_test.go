@@ -0,0 +1,40 @@
+package claude
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	appendToFile(t, path, `{"type":"user","sessionId":"s1","timestamp":"2026-10-01T10:00:00Z","message":{"role":"user","content":"Add login"}}
+{"type":"assistant","timestamp":"2026-10-01T10:01:00Z","message":{"role":"assistant","content":[{"type":"text","text":"Adding it."},{"type":"tool_use","name":"Edit","input":{"file_path":"/w/login.go"}},{"type":"tool_use","name":"Bash","input":{}}]}}
+{"type":"user","timestamp":"2026-10-01T10:02:00Z","message":{"role":"user","content":[{"type":"tool_result","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-10-01T10:03:00Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Read","input":{"file_path":"/w/main.go"}},{"type":"tool_use","name":"Write","input":{"file_path":"/w/login_test.go"}},{"type":"tool_use","name":"Edit","input":{"file_path":"/w/login.go"}},{"type":"text","text":"Login is done."}]}}
+`)
+
+	summary, err := SummarizeTranscript(path)
+	if err != nil {
+		t.Fatalf("SummarizeTranscript() error = %v", err)
+	}
+	if summary.SessionID != "s1" || summary.Prompts != 1 || summary.Replies != 2 {
+		t.Errorf("summary = %+v, want 1 prompt and 2 replies of s1", summary)
+	}
+	if summary.FirstPrompt != "Add login" || summary.LastReply != "Login is done." {
+		t.Errorf("first prompt %q, last reply %q", summary.FirstPrompt, summary.LastReply)
+	}
+	if strings.Join(summary.Files, ",") != "/w/login.go,/w/login_test.go" {
+		t.Errorf("files = %v, want those Edit and Write changed, once each", summary.Files)
+	}
+	if summary.Ended.Sub(summary.Started).Minutes() != 3 {
+		t.Errorf("conversation ran from %v to %v, want 3 minutes", summary.Started, summary.Ended)
+	}
+
+	markdown := summary.Markdown()
+	for _, want := range []string{"## Conversation s1", "Tools: Edit ×2, Bash ×1, Read ×1, Write ×1", "> Add login", "> Login is done."} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown is missing %q:\n%s", want, markdown)
+		}
+	}
+}
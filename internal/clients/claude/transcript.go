@@ -0,0 +1,244 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// usageKeys are the token counts of a message's usage that are tallied.
+// Reads from the prompt cache cost a fraction of the rest and aren't counted.
+var usageKeys = []string{"input_tokens", "cache_creation_input_tokens", "output_tokens"}
+
+// UsageMonth returns the calendar month, like "2026-10", that tokens used at
+// t count toward
+func UsageMonth(t time.Time) string {
+	return t.Local().Format("2006-01")
+}
+
+// transcript is the parsed summary of a Claude JSONL transcript. Offset is
+// the number of bytes consumed so far; a line Claude is still writing is left
+// unconsumed and parsed on the next update.
+type transcript struct {
+	Size    int64
+	ModTime time.Time
+	Offset  int64
+
+	SessionID     string
+	CWD           string
+	LastSeen      time.Time
+	MessageCount  int
+	LastAssistant *types.ClaudeMessage
+
+	Tokens      map[string]int64 // Tokens used, by UsageMonth
+	Usage       types.Usage      // Used over the whole transcript
+	lastUsageID string           // Message whose usage was counted last
+}
+
+// transcriptCache caches transcript parses keyed by file path and re-reads
+// only the lines appended since the last parse, so long transcripts stay cheap
+type transcriptCache struct {
+	mu    sync.Mutex
+	files map[string]*transcript
+}
+
+func newTranscriptCache() *transcriptCache {
+	return &transcriptCache{
+		files: make(map[string]*transcript),
+	}
+}
+
+// get returns an up-to-date parse of the transcript at path. The returned
+// value is a copy and safe to use after the cache changes.
+func (c *transcriptCache) get(path string) (transcript, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		c.mu.Lock()
+		delete(c.files, path)
+		c.mu.Unlock()
+		return transcript{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.files[path]
+	if ok && t.Size == info.Size() && t.ModTime.Equal(info.ModTime()) {
+		return t.clone(), nil
+	}
+
+	// A shrunk file was rewritten rather than appended to; start over
+	if !ok || info.Size() < t.Offset {
+		t = &transcript{}
+	}
+
+	if err := t.readFrom(path); err != nil {
+		delete(c.files, path)
+		return transcript{}, err
+	}
+	t.Size = info.Size()
+	t.ModTime = info.ModTime()
+	c.files[path] = t
+
+	return t.clone(), nil
+}
+
+// clone copies t so the copy's token counts don't change with later parses
+func (t *transcript) clone() transcript {
+	copied := *t
+	copied.Tokens = maps.Clone(t.Tokens)
+	return copied
+}
+
+// readFrom parses the complete lines appended after t.Offset
+func (t *transcript) readFrom(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(t.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		// A final line without a newline is only consumed once it is valid
+		// JSON; otherwise Claude is still writing it
+		complete := len(line) > 0 && (line[len(line)-1] == '\n' || json.Valid(line))
+		if complete {
+			t.Offset += int64(len(line))
+			t.applyLine(line)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// applyLine folds one JSONL entry into the summary
+func (t *transcript) applyLine(line []byte) {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return // Skip invalid JSON lines
+	}
+	t.MessageCount++
+
+	// Session metadata comes from the first entry that carries it
+	if t.SessionID == "" {
+		if sid, ok := raw["sessionId"].(string); ok {
+			t.SessionID = sid
+		}
+		if cwd, ok := raw["cwd"].(string); ok {
+			t.CWD = cwd
+		}
+	}
+
+	var timestamp time.Time
+	if timestampStr, ok := raw["timestamp"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, timestampStr); err == nil {
+			timestamp = parsed
+			if parsed.After(t.LastSeen) {
+				t.LastSeen = parsed
+			}
+		}
+	}
+
+	if msg, ok := raw["message"].(map[string]interface{}); ok {
+		t.countUsage(msg, timestamp)
+		if claudeMsg := parseMessage(msg, timestamp); claudeMsg.Role == "assistant" {
+			t.LastAssistant = &claudeMsg
+		}
+	}
+}
+
+// countUsage adds the tokens a message used to its month and to the
+// transcript's usage. Claude writes a message with several content blocks
+// as several entries repeating the message's ID and usage, so only the
+// first of them is counted.
+func (t *transcript) countUsage(msg map[string]interface{}, timestamp time.Time) {
+	usage, ok := msg["usage"].(map[string]interface{})
+	if !ok || timestamp.IsZero() {
+		return
+	}
+	if id, _ := msg["id"].(string); id != "" {
+		if id == t.lastUsageID {
+			return
+		}
+		t.lastUsageID = id
+	}
+
+	count := func(key string) int64 {
+		n, _ := usage[key].(float64)
+		return int64(n)
+	}
+	used := types.Usage{
+		InputTokens:      count("input_tokens"),
+		OutputTokens:     count("output_tokens"),
+		CacheWriteTokens: count("cache_creation_input_tokens"),
+		CacheReadTokens:  count("cache_read_input_tokens"),
+	}
+	model, _ := msg["model"].(string)
+	used.Cost = estimateCost(model, used)
+	t.Usage = t.Usage.Add(used)
+
+	var tokens int64
+	for _, key := range usageKeys {
+		if count, ok := usage[key].(float64); ok {
+			tokens += int64(count)
+		}
+	}
+	if tokens == 0 {
+		return
+	}
+	if t.Tokens == nil {
+		t.Tokens = make(map[string]int64)
+	}
+	t.Tokens[UsageMonth(timestamp)] += tokens
+}
+
+// parseMessage converts a raw transcript message into a ClaudeMessage
+func parseMessage(msg map[string]interface{}, timestamp time.Time) types.ClaudeMessage {
+	claudeMsg := types.ClaudeMessage{Timestamp: timestamp}
+
+	if role, ok := msg["role"].(string); ok {
+		claudeMsg.Role = role
+	}
+
+	if content, ok := msg["content"].([]interface{}); ok {
+		for _, c := range content {
+			if contentMap, ok := c.(map[string]interface{}); ok {
+				contentItem := types.Content{}
+				if contentType, ok := contentMap["type"].(string); ok {
+					contentItem.Type = contentType
+				}
+				if text, ok := contentMap["text"].(string); ok {
+					contentItem.Text = text
+				}
+				if name, ok := contentMap["name"].(string); ok {
+					contentItem.Name = name
+				}
+				claudeMsg.Content = append(claudeMsg.Content, contentItem)
+			}
+		}
+	}
+
+	return claudeMsg
+}
@@ -0,0 +1,198 @@
+package claude
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+const (
+	userLine      = `{"sessionId":"abc","cwd":"/work","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":[{"type":"text","text":"hi"}]}}` + "\n"
+	assistantLine = `{"sessionId":"abc","cwd":"/work","timestamp":"2025-01-01T10:01:00Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Edit"}]}}` + "\n"
+	laterLine     = `{"sessionId":"abc","cwd":"/work","timestamp":"2025-01-01T10:05:00Z","message":{"role":"assistant","content":[{"type":"text","text":"done"}]}}` + "\n"
+)
+
+func appendToFile(t *testing.T, path, data string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open transcript: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatalf("failed to append transcript: %v", err)
+	}
+}
+
+func TestTranscriptCache_Incremental(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	cache := newTranscriptCache()
+
+	appendToFile(t, path, userLine+assistantLine)
+
+	first, err := cache.get(path)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if first.SessionID != "abc" || first.CWD != "/work" {
+		t.Errorf("get() metadata = %q/%q, want abc//work", first.SessionID, first.CWD)
+	}
+	if first.MessageCount != 2 {
+		t.Errorf("MessageCount = %d, want 2", first.MessageCount)
+	}
+	if first.LastAssistant == nil || first.LastAssistant.Content[0].Type != "tool_use" {
+		t.Fatalf("LastAssistant = %+v, want tool_use message", first.LastAssistant)
+	}
+
+	// A partially written line is left for the next update
+	appendToFile(t, path, laterLine[:40])
+	partial, err := cache.get(path)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if partial.Offset != first.Offset || partial.MessageCount != 2 {
+		t.Errorf("partial line consumed: offset %d -> %d, count %d", first.Offset, partial.Offset, partial.MessageCount)
+	}
+
+	// Completing it parses only the appended bytes
+	appendToFile(t, path, laterLine[40:])
+	second, err := cache.get(path)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if second.MessageCount != 3 {
+		t.Errorf("MessageCount = %d, want 3", second.MessageCount)
+	}
+	if second.LastAssistant == nil || second.LastAssistant.Content[0].Text != "done" {
+		t.Errorf("LastAssistant = %+v, want the appended message", second.LastAssistant)
+	}
+	if !second.LastSeen.After(first.LastSeen) {
+		t.Errorf("LastSeen = %v, want later than %v", second.LastSeen, first.LastSeen)
+	}
+}
+
+func TestTranscriptCache_Rewritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	cache := newTranscriptCache()
+
+	appendToFile(t, path, userLine+assistantLine+laterLine)
+	if _, err := cache.get(path); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	// A shorter file means the transcript was replaced, not appended to
+	if err := os.WriteFile(path, []byte(userLine), 0644); err != nil {
+		t.Fatalf("failed to rewrite transcript: %v", err)
+	}
+	rewritten, err := cache.get(path)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if rewritten.MessageCount != 1 {
+		t.Errorf("MessageCount = %d, want 1 after rewrite", rewritten.MessageCount)
+	}
+	if rewritten.LastAssistant != nil {
+		t.Errorf("LastAssistant = %+v, want nil after rewrite", rewritten.LastAssistant)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove transcript: %v", err)
+	}
+	if _, err := cache.get(path); err == nil {
+		t.Error("get() on removed file should return error")
+	}
+}
+
+func TestTranscriptCache_TokenUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	cache := newTranscriptCache()
+
+	usageLine := func(timestamp, id string, input, output int) string {
+		return fmt.Sprintf(`{"sessionId":"abc","cwd":"/work","timestamp":%q,"message":{"id":%q,"role":"assistant",`+
+			`"usage":{"input_tokens":%d,"cache_creation_input_tokens":10,"cache_read_input_tokens":5000,"output_tokens":%d}}}`+"\n",
+			timestamp, id, input, output)
+	}
+	// The second entry repeats the first message's usage for another content block
+	appendToFile(t, path, usageLine("2025-01-15T10:00:00Z", "msg_1", 100, 20)+
+		usageLine("2025-01-15T10:00:01Z", "msg_1", 100, 20)+
+		usageLine("2025-01-15T11:00:00Z", "msg_2", 300, 50)+
+		usageLine("2025-02-15T10:00:00Z", "msg_3", 1000, 200))
+
+	parsed, err := cache.get(path)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	january := UsageMonth(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	february := UsageMonth(time.Date(2025, 2, 15, 10, 0, 0, 0, time.UTC))
+	if got := parsed.Tokens[january]; got != 130+360 {
+		t.Errorf("January tokens = %d, want %d", got, 130+360)
+	}
+	if got := parsed.Tokens[february]; got != 1210 {
+		t.Errorf("February tokens = %d, want 1210", got)
+	}
+
+	// Copies keep their counts while the cache parses more
+	appendToFile(t, path, usageLine("2025-02-15T11:00:00Z", "msg_4", 90, 0))
+	if _, err := cache.get(path); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if got := parsed.Tokens[february]; got != 1210 {
+		t.Errorf("earlier copy's February tokens = %d, want 1210", got)
+	}
+}
+
+func TestTranscriptCache_SessionUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	cache := newTranscriptCache()
+
+	usageLine := func(id, model string) string {
+		return fmt.Sprintf(`{"sessionId":"abc","cwd":"/work","timestamp":"2025-01-15T10:00:00Z","message":{"id":%q,"model":%q,"role":"assistant",`+
+			`"usage":{"input_tokens":1000000,"cache_creation_input_tokens":1000000,"cache_read_input_tokens":1000000,"output_tokens":1000000}}}`+"\n",
+			id, model)
+	}
+	appendToFile(t, path, usageLine("msg_1", "claude-sonnet-4-5-20250929")+
+		usageLine("msg_1", "claude-sonnet-4-5-20250929")+
+		usageLine("msg_2", "<synthetic>"))
+
+	parsed, err := cache.get(path)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	want := types.Usage{InputTokens: 2e6, OutputTokens: 2e6, CacheWriteTokens: 2e6, CacheReadTokens: 2e6}
+	cost := parsed.Usage.Cost
+	parsed.Usage.Cost = 0
+	if parsed.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", parsed.Usage, want)
+	}
+	// Sonnet's million tokens of each: $3 in, $3.75 cache writes, $0.30 cache reads and $15 out.
+	// The message Claude Code made up itself costs nothing.
+	if math.Abs(cost-22.05) > 1e-9 {
+		t.Errorf("Cost = %v, want 22.05", cost)
+	}
+}
+
+func TestPriceOf(t *testing.T) {
+	tests := []struct {
+		model string
+		want  modelPrice
+		ok    bool
+	}{
+		{"claude-opus-4-1-20250805", modelPrice{15, 75}, true},
+		{"claude-opus-4-20250514", modelPrice{15, 75}, true},
+		{"claude-opus-4-5-20251101", modelPrice{5, 25}, true},
+		{"claude-sonnet-4-20250514", modelPrice{3, 15}, true},
+		{"claude-3-5-haiku-20241022", modelPrice{0.8, 4}, true},
+		{"claude-haiku-4-5", modelPrice{1, 5}, true},
+		{"<synthetic>", modelPrice{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := priceOf(tt.model); got != tt.want || ok != tt.ok {
+			t.Errorf("priceOf(%q) = %v, %v, want %v, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// DefaultBitbucketAPI is the Bitbucket Cloud REST API
+const DefaultBitbucketAPI = "https://api.bitbucket.org/2.0"
+
+// Bitbucket opens pull requests with the Bitbucket Cloud REST API. It signs
+// in with an access token in BITBUCKET_TOKEN, or a username and app password
+// in BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD.
+type Bitbucket struct {
+	API       string // REST API root
+	Workspace string
+	Repo      string
+
+	token, username, password string
+	client                    *http.Client
+}
+
+// NewBitbucket returns the Bitbucket forge of a repository, with
+// credentials from the environment
+func NewBitbucket(repo Repository) (*Bitbucket, error) {
+	workspace, slug, ok := strings.Cut(repo.Path, "/")
+	if !ok || workspace == "" || slug == "" || strings.Contains(slug, "/") {
+		return nil, fmt.Errorf("invalid Bitbucket repository %q: want workspace/repository", repo.Path)
+	}
+	return &Bitbucket{
+		API:       DefaultBitbucketAPI,
+		Workspace: workspace,
+		Repo:      slug,
+		token:     os.Getenv("BITBUCKET_TOKEN"),
+		username:  os.Getenv("BITBUCKET_USERNAME"),
+		password:  os.Getenv("BITBUCKET_APP_PASSWORD"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (b *Bitbucket) Name() string { return "Bitbucket" }
+
+func (b *Bitbucket) Available() error {
+	if b.token == "" && (b.username == "" || b.password == "") {
+		return fmt.Errorf("no Bitbucket credentials: set BITBUCKET_TOKEN, or BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD")
+	}
+	return nil
+}
+
+// bitbucketPullRequest is the part of the API's pull request read back
+type bitbucketPullRequest struct {
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+func (b *Bitbucket) CreatePullRequest(dir string, pr PullRequest) (string, error) {
+	request := map[string]interface{}{
+		"title":       pr.Title,
+		"description": pr.Body,
+		"source":      map[string]interface{}{"branch": map[string]string{"name": pr.Branch}},
+		"draft":       pr.Draft,
+	}
+	if pr.Base != "" {
+		request["destination"] = map[string]interface{}{"branch": map[string]string{"name": pr.Base}}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	var created bitbucketPullRequest
+	if err := b.call(http.MethodPost, b.pullRequestsURL(), bytes.NewReader(body), &created); err != nil {
+		return "", fmt.Errorf("failed to create Bitbucket pull request: %w", err)
+	}
+	return created.Links.HTML.Href, nil
+}
+
+func (b *Bitbucket) PullRequestURL(dir, branch string) (string, error) {
+	query := url.Values{"q": {fmt.Sprintf(`source.branch.name="%s" AND state="OPEN"`, branch)}}
+	var page struct {
+		Values []bitbucketPullRequest `json:"values"`
+	}
+	if err := b.call(http.MethodGet, b.pullRequestsURL()+"?"+query.Encode(), nil, &page); err != nil {
+		return "", fmt.Errorf("failed to look up Bitbucket pull requests: %w", err)
+	}
+	if len(page.Values) == 0 || page.Values[0].Links.HTML.Href == "" {
+		return "", fmt.Errorf("no pull request found for branch %s", branch)
+	}
+	return page.Values[0].Links.HTML.Href, nil
+}
+
+func (b *Bitbucket) PullRequestStatus(dir, prURL string) (types.PullRequestStatus, error) {
+	number := types.PullRequestNumber(prURL)
+	if number == 0 {
+		return types.PullRequestStatus{}, fmt.Errorf("can't read the pull request number of %s", prURL)
+	}
+	endpoint := fmt.Sprintf("%s/%d", b.pullRequestsURL(), number)
+
+	var pr struct {
+		State        string `json:"state"`
+		Draft        bool   `json:"draft"`
+		Participants []struct {
+			Role  string `json:"role"`
+			State string `json:"state"` // approved, changes_requested or null
+		} `json:"participants"`
+	}
+	if err := b.call(http.MethodGet, endpoint, nil, &pr); err != nil {
+		return types.PullRequestStatus{}, fmt.Errorf("failed to look up Bitbucket pull request: %w", err)
+	}
+	var statuses struct {
+		Values []struct {
+			State string `json:"state"` // SUCCESSFUL, FAILED, INPROGRESS or STOPPED
+		} `json:"values"`
+	}
+	if err := b.call(http.MethodGet, endpoint+"/statuses", nil, &statuses); err != nil {
+		return types.PullRequestStatus{}, fmt.Errorf("failed to look up Bitbucket build statuses: %w", err)
+	}
+
+	status := types.PullRequestStatus{Draft: pr.Draft}
+	switch pr.State {
+	case "MERGED":
+		status.State = types.PullRequestMerged
+	case "DECLINED", "SUPERSEDED":
+		status.State = types.PullRequestClosed
+	default:
+		status.State = types.PullRequestOpen
+	}
+
+	// Changes requested by any reviewer win over approvals by others
+	for _, participant := range pr.Participants {
+		if participant.Role != "REVIEWER" {
+			continue
+		}
+		switch {
+		case participant.State == "changes_requested":
+			status.Review = types.ReviewChangesRequested
+		case participant.State == "approved" && status.Review != types.ReviewChangesRequested:
+			status.Review = types.ReviewApproved
+		case status.Review == "":
+			status.Review = types.ReviewRequested
+		}
+	}
+
+	states := make([]string, len(statuses.Values))
+	for i, value := range statuses.Values {
+		states[i] = value.State
+	}
+	status.Checks = checksState(states, []string{"SUCCESSFUL"}, []string{"INPROGRESS"})
+	return status, nil
+}
+
+func (b *Bitbucket) pullRequestsURL() string {
+	return fmt.Sprintf("%s/repositories/%s/%s/pullrequests",
+		strings.TrimSuffix(b.API, "/"), url.PathEscape(b.Workspace), url.PathEscape(b.Repo))
+}
+
+// call sends an API request and decodes its JSON response into result
+func (b *Bitbucket) call(method, endpoint string, body io.Reader, result interface{}) error {
+	if err := b.Available(); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	} else {
+		req.SetBasicAuth(b.username, b.password)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiError struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiError) == nil && apiError.Error.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, apiError.Error.Message)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,106 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestBitbucket_PullRequests(t *testing.T) {
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/acme/widgets/pullrequests" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"links":{"html":{"href":"https://bitbucket.org/acme/widgets/pull-requests/3"}}}`))
+		case http.MethodGet:
+			if r.URL.Query().Get("q") != `source.branch.name="auth" AND state="OPEN"` {
+				w.Write([]byte(`{"values":[]}`))
+				return
+			}
+			w.Write([]byte(`{"values":[{"links":{"html":{"href":"https://bitbucket.org/acme/widgets/pull-requests/3"}}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("BITBUCKET_TOKEN", "secret")
+	bitbucket, err := NewBitbucket(Repository{Host: "bitbucket.org", Path: "acme/widgets"})
+	if err != nil {
+		t.Fatalf("NewBitbucket() error = %v", err)
+	}
+	bitbucket.API = server.URL
+
+	url, err := bitbucket.CreatePullRequest(t.TempDir(), PullRequest{Title: "Add login", Body: "Done.", Branch: "auth", Base: "main", Draft: true})
+	if err != nil || url != "https://bitbucket.org/acme/widgets/pull-requests/3" {
+		t.Fatalf("CreatePullRequest() = %q, %v", url, err)
+	}
+	if created["title"] != "Add login" || created["draft"] != true ||
+		created["destination"].(map[string]interface{})["branch"].(map[string]interface{})["name"] != "main" {
+		t.Errorf("request = %v, want the title, draft flag and base branch", created)
+	}
+
+	if url, err := bitbucket.PullRequestURL("", "auth"); err != nil || url == "" {
+		t.Errorf("PullRequestURL(auth) = %q, %v", url, err)
+	}
+	if _, err := bitbucket.PullRequestURL("", "other"); err == nil {
+		t.Error("PullRequestURL() should fail for a branch without a pull request")
+	}
+}
+
+func TestBitbucket_NeedsCredentials(t *testing.T) {
+	for _, name := range []string{"BITBUCKET_TOKEN", "BITBUCKET_USERNAME", "BITBUCKET_APP_PASSWORD"} {
+		t.Setenv(name, "")
+	}
+	bitbucket, _ := NewBitbucket(Repository{Host: "bitbucket.org", Path: "acme/widgets"})
+	if bitbucket.Available() == nil {
+		t.Error("Available() should fail without credentials")
+	}
+	if _, err := bitbucket.CreatePullRequest("", PullRequest{Title: "x", Branch: "auth"}); err == nil {
+		t.Error("CreatePullRequest() should fail without credentials")
+	}
+}
+
+func TestBitbucket_PullRequestStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/acme/widgets/pullrequests/3":
+			w.Write([]byte(`{"state": "OPEN", "draft": false, "participants": [
+				{"role": "PARTICIPANT", "state": null},
+				{"role": "REVIEWER", "state": "approved"},
+				{"role": "REVIEWER", "state": null}
+			]}`))
+		case "/repositories/acme/widgets/pullrequests/3/statuses":
+			w.Write([]byte(`{"values": [{"state": "SUCCESSFUL"}, {"state": "INPROGRESS"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("BITBUCKET_TOKEN", "secret")
+	bitbucket, _ := NewBitbucket(Repository{Host: "bitbucket.org", Path: "acme/widgets"})
+	bitbucket.API = server.URL
+
+	status, err := bitbucket.PullRequestStatus("", "https://bitbucket.org/acme/widgets/pull-requests/3")
+	if err != nil {
+		t.Fatalf("PullRequestStatus() error = %v", err)
+	}
+	want := types.PullRequestStatus{State: types.PullRequestOpen, Review: types.ReviewApproved, Checks: types.ChecksPending}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+	if _, err := bitbucket.PullRequestStatus("", "https://bitbucket.org/acme/widgets/pull-requests/4"); err == nil {
+		t.Error("PullRequestStatus() should fail for an unknown pull request")
+	}
+}
@@ -0,0 +1,221 @@
+// Package forge opens pull requests on the service hosting a repository
+// and follows their review and CI checks: GitHub through the gh CLI, GitLab
+// through the glab CLI, and Bitbucket Cloud through its REST API. Which one hosts a repository is told from the
+// URL of its origin remote, or set with forge in the config.
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/logging"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// logger is the forges' diagnostic log
+var logger = logging.For("forge")
+
+// Kinds of forge, chosen with forge in the config
+const (
+	KindGitHub    = "github"
+	KindGitLab    = "gitlab"
+	KindBitbucket = "bitbucket"
+)
+
+// Forge creates and finds the pull requests of a repository. GitLab calls
+// them merge requests.
+type Forge interface {
+	// Name names the forge for messages, like "GitHub"
+	Name() string
+	// Available returns why pull requests can't be created, like a missing
+	// CLI or credentials, or nil when they can
+	Available() error
+	// CreatePullRequest opens a pull request of a pushed branch and returns
+	// its URL. dir is a checkout of the repository.
+	CreatePullRequest(dir string, pr PullRequest) (string, error)
+	// PullRequestURL returns the URL of the open pull request of a branch
+	PullRequestURL(dir, branch string) (string, error)
+	// PullRequestStatus asks where the pull request at a URL stands: open
+	// or not, its review and its CI checks
+	PullRequestStatus(dir, url string) (types.PullRequestStatus, error)
+}
+
+// PullRequest describes a pull request to open
+type PullRequest struct {
+	Title  string
+	Body   string
+	Branch string // Branch with the changes
+	Base   string // Branch to merge into; the forge's default when empty
+	Draft  bool
+}
+
+// Repository is where a remote URL points: the forge's host and the
+// repository's path on it, like "acme/widgets"
+type Repository struct {
+	Host string
+	Path string
+}
+
+// ParseRemote reads the host and path of a git remote URL in any of the
+// forms git accepts: https://host/path.git, ssh://git@host/path.git or
+// git@host:path.git
+func ParseRemote(remote string) (Repository, error) {
+	remote = strings.TrimSpace(remote)
+	var repo Repository
+	if strings.Contains(remote, "://") {
+		parsed, err := url.Parse(remote)
+		if err != nil {
+			return repo, fmt.Errorf("invalid remote URL %q: %w", remote, err)
+		}
+		repo.Host, repo.Path = parsed.Hostname(), parsed.Path
+	} else if userHost, path, ok := strings.Cut(remote, ":"); ok {
+		_, host, found := strings.Cut(userHost, "@")
+		if !found {
+			host = userHost
+		}
+		repo.Host, repo.Path = host, path
+	}
+
+	repo.Path = strings.TrimSuffix(strings.Trim(repo.Path, "/"), ".git")
+	if repo.Host == "" || repo.Path == "" {
+		return repo, fmt.Errorf("can't read the host and repository of remote URL %q", remote)
+	}
+	return repo, nil
+}
+
+// Detect tells the kind of forge hosting a repository from its host name,
+// or returns "" when the host doesn't say
+func Detect(host string) string {
+	host = strings.ToLower(host)
+	for _, kind := range []string{KindGitHub, KindGitLab, KindBitbucket} {
+		if strings.Contains(host, kind) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// New returns the forge of a kind for a repository
+func New(kind string, repo Repository) (Forge, error) {
+	switch kind {
+	case KindGitHub:
+		return GitHub{}, nil
+	case KindGitLab:
+		return GitLab{}, nil
+	case KindBitbucket:
+		return NewBitbucket(repo)
+	}
+	return nil, fmt.Errorf("unknown forge %q (valid: %s, %s, %s)", kind, KindGitHub, KindGitLab, KindBitbucket)
+}
+
+// ForRepository returns the forge hosting the origin remote of the
+// repository checked out in dir. kind overrides detection from origin's URL,
+// for forges on hosts of their own like a company GitLab.
+func ForRepository(dir, kind string) (Forge, error) {
+	repo, kind, err := origin(dir, kind)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("using forge", "kind", kind, "host", repo.Host, "repository", repo.Path)
+	return New(kind, repo)
+}
+
+// BranchURL returns the web page of a branch on the forge hosting the
+// origin remote of the repository checked out in dir. kind overrides
+// detection from origin's URL, as with ForRepository.
+func BranchURL(dir, kind, branch string) (string, error) {
+	repo, kind, err := origin(dir, kind)
+	if err != nil {
+		return "", err
+	}
+	return branchURL(kind, repo, branch)
+}
+
+// branchURL builds the web page of a branch on a forge
+func branchURL(kind string, repo Repository, branch string) (string, error) {
+	segments := strings.Split(branch, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	escaped := strings.Join(segments, "/")
+
+	base := "https://" + repo.Host + "/" + repo.Path
+	switch kind {
+	case KindGitHub:
+		return base + "/tree/" + escaped, nil
+	case KindGitLab:
+		return base + "/-/tree/" + escaped, nil
+	case KindBitbucket:
+		return base + "/branch/" + escaped, nil
+	}
+	return "", fmt.Errorf("unknown forge %q (valid: %s, %s, %s)", kind, KindGitHub, KindGitLab, KindBitbucket)
+}
+
+// origin reads where the origin remote of the repository checked out in
+// dir points, and which kind of forge hosts it unless kind is given
+func origin(dir, kind string) (Repository, string, error) {
+	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return Repository{}, "", fmt.Errorf("no origin remote")
+	}
+	repo, err := ParseRemote(string(output))
+	if err != nil {
+		return Repository{}, "", err
+	}
+
+	if kind == "" {
+		kind = Detect(repo.Host)
+		if kind == "" {
+			return Repository{}, "", fmt.Errorf("can't tell which forge hosts %s; set forge in the config to %s, %s or %s",
+				repo.Host, KindGitHub, KindGitLab, KindBitbucket)
+		}
+	}
+	return repo, kind, nil
+}
+
+// lastURL returns the last line of a command's output that is a URL, which
+// is where gh and glab print the pull request they created
+func lastURL(output string) string {
+	var found string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "https://") || strings.HasPrefix(line, "http://") {
+			found = line
+		}
+	}
+	return found
+}
+
+// cliAvailable returns an error naming a forge's CLI when it isn't on PATH
+func cliAvailable(command, name string) error {
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Errorf("%s CLI (%s) not found in PATH", name, command)
+	}
+	return nil
+}
+
+// checksState sums up the states of a pull request's CI checks: failing
+// when any is neither passed nor pending, pending while any still runs, and
+// passing otherwise. It is "" when there are no checks.
+func checksState(states, passed, pending []string) string {
+	if len(states) == 0 {
+		return ""
+	}
+	checks := types.ChecksPassing
+	for _, state := range states {
+		state = strings.ToUpper(state)
+		switch {
+		case slices.Contains(passed, state):
+		case slices.Contains(pending, state):
+			checks = types.ChecksPending
+		default:
+			return types.ChecksFailing
+		}
+	}
+	return checks
+}
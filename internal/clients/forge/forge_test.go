@@ -0,0 +1,131 @@
+package forge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   Repository
+	}{
+		{"https://github.com/acme/widgets.git", Repository{"github.com", "acme/widgets"}},
+		{"https://user@bitbucket.org/acme/widgets.git\n", Repository{"bitbucket.org", "acme/widgets"}},
+		{"git@gitlab.com:acme/platform/widgets.git", Repository{"gitlab.com", "acme/platform/widgets"}},
+		{"ssh://git@gitlab.example.com:2222/acme/widgets", Repository{"gitlab.example.com", "acme/widgets"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRemote(tt.remote)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRemote(%q) = %+v, %v; want %+v", tt.remote, got, err, tt.want)
+		}
+	}
+
+	for _, remote := range []string{"", "/srv/git/widgets.git", "https://github.com/"} {
+		if _, err := ParseRemote(remote); err == nil {
+			t.Errorf("ParseRemote(%q) should fail", remote)
+		}
+	}
+}
+
+func TestDetectAndNew(t *testing.T) {
+	for host, want := range map[string]string{
+		"github.com":         "GitHub",
+		"GitLab.example.com": "GitLab",
+		"bitbucket.org":      "Bitbucket",
+	} {
+		forge, err := New(Detect(host), Repository{Host: host, Path: "acme/widgets"})
+		if err != nil || forge.Name() != want {
+			t.Errorf("forge of %s = %v, %v; want %s", host, forge, err, want)
+		}
+	}
+
+	if kind := Detect("git.example.com"); kind != "" {
+		t.Errorf("Detect(git.example.com) = %q, want none", kind)
+	}
+	if _, err := New("gitea", Repository{}); err == nil || !strings.Contains(err.Error(), "unknown forge") {
+		t.Errorf("New(gitea) error = %v", err)
+	}
+	if _, err := New(KindBitbucket, Repository{Host: "bitbucket.org", Path: "widgets"}); err == nil {
+		t.Error("a Bitbucket repository needs a workspace")
+	}
+}
+
+func TestBranchURL(t *testing.T) {
+	repo := Repository{"example.com", "acme/widgets"}
+	tests := []struct {
+		kind string
+		want string
+	}{
+		{KindGitHub, "https://example.com/acme/widgets/tree/feature/a%20b"},
+		{KindGitLab, "https://example.com/acme/widgets/-/tree/feature/a%20b"},
+		{KindBitbucket, "https://example.com/acme/widgets/branch/feature/a%20b"},
+	}
+	for _, tt := range tests {
+		got, err := branchURL(tt.kind, repo, "feature/a b")
+		if err != nil || got != tt.want {
+			t.Errorf("branchURL(%s) = %q, %v, want %q", tt.kind, got, err, tt.want)
+		}
+	}
+	if _, err := branchURL("gitea", repo, "main"); err == nil {
+		t.Error("expected an error for an unknown forge")
+	}
+}
+
+func TestLastURL(t *testing.T) {
+	output := "Creating merge request for feature into main\n\nhttps://gitlab.com/acme/widgets/-/merge_requests/7\n"
+	if got := lastURL(output); got != "https://gitlab.com/acme/widgets/-/merge_requests/7" {
+		t.Errorf("lastURL() = %q", got)
+	}
+}
+
+func TestParseGitHubStatus(t *testing.T) {
+	status, err := parseGitHubStatus([]byte(`{"state": "OPEN", "isDraft": false, "reviewDecision": "CHANGES_REQUESTED",
+		"statusCheckRollup": [
+			{"status": "COMPLETED", "conclusion": "SUCCESS"},
+			{"status": "IN_PROGRESS", "conclusion": ""},
+			{"state": "SUCCESS"}
+		]}`))
+	if err != nil {
+		t.Fatalf("parseGitHubStatus() error = %v", err)
+	}
+	want := types.PullRequestStatus{State: types.PullRequestOpen, Review: types.ReviewChangesRequested, Checks: types.ChecksPending}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+
+	status, _ = parseGitHubStatus([]byte(`{"state": "OPEN", "isDraft": true, "reviewDecision": "",
+		"reviewRequests": [{"login": "alice"}],
+		"statusCheckRollup": [{"status": "COMPLETED", "conclusion": "FAILURE"}, {"status": "QUEUED"}]}`))
+	want = types.PullRequestStatus{State: types.PullRequestOpen, Draft: true, Review: types.ReviewRequested, Checks: types.ChecksFailing}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+
+	status, _ = parseGitHubStatus([]byte(`{"state": "MERGED", "reviewDecision": "APPROVED", "statusCheckRollup": []}`))
+	want = types.PullRequestStatus{State: types.PullRequestMerged, Review: types.ReviewApproved}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+}
+
+func TestParseGitLabStatus(t *testing.T) {
+	status, err := parseGitLabStatus([]byte(`{"state": "opened", "draft": false, "detailed_merge_status": "not_approved",
+		"head_pipeline": {"status": "success"}}`))
+	if err != nil {
+		t.Fatalf("parseGitLabStatus() error = %v", err)
+	}
+	want := types.PullRequestStatus{State: types.PullRequestOpen, Review: types.ReviewRequested, Checks: types.ChecksPassing}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+
+	status, _ = parseGitLabStatus([]byte(`{"state": "closed", "head_pipeline": {"status": "failed"}}`))
+	want = types.PullRequestStatus{State: types.PullRequestClosed, Checks: types.ChecksFailing}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+}
@@ -0,0 +1,121 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// GitHub opens pull requests with the GitHub CLI, gh
+type GitHub struct{}
+
+func (GitHub) Name() string { return "GitHub" }
+
+func (GitHub) Available() error {
+	return cliAvailable("gh", "GitHub")
+}
+
+func (GitHub) CreatePullRequest(dir string, pr PullRequest) (string, error) {
+	args := []string{"pr", "create", "--title", pr.Title, "--body", pr.Body, "--head", pr.Branch}
+	if pr.Base != "" {
+		args = append(args, "--base", pr.Base)
+	}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("gh pr create failed: %w", err)
+	}
+	return lastURL(output.String()), nil
+}
+
+func (GitHub) PullRequestURL(dir, branch string) (string, error) {
+	cmd := exec.Command("gh", "pr", "view", branch, "--json", "url", "--jq", ".url")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no pull request found for branch %s: %w", branch, err)
+	}
+	url := strings.TrimSpace(string(output))
+	if url == "" {
+		return "", fmt.Errorf("no pull request found for branch %s", branch)
+	}
+	return url, nil
+}
+
+func (GitHub) PullRequestStatus(dir, url string) (types.PullRequestStatus, error) {
+	cmd := exec.Command("gh", "pr", "view", url, "--json", "state,isDraft,reviewDecision,reviewRequests,statusCheckRollup")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return types.PullRequestStatus{}, fmt.Errorf("gh pr view failed: %w", err)
+	}
+	return parseGitHubStatus(output)
+}
+
+// parseGitHubStatus reads the status of a pull request from the JSON of
+// gh pr view
+func parseGitHubStatus(data []byte) (types.PullRequestStatus, error) {
+	var pr struct {
+		State          string            `json:"state"`
+		IsDraft        bool              `json:"isDraft"`
+		ReviewDecision string            `json:"reviewDecision"`
+		ReviewRequests []json.RawMessage `json:"reviewRequests"`
+		Checks         []struct {
+			Status     string `json:"status"`     // Of a check run: QUEUED, IN_PROGRESS, COMPLETED...
+			Conclusion string `json:"conclusion"` // Of a completed check run: SUCCESS, FAILURE...
+			State      string `json:"state"`      // Of a commit status: PENDING, SUCCESS, FAILURE, ERROR
+		} `json:"statusCheckRollup"`
+	}
+	if err := json.Unmarshal(data, &pr); err != nil {
+		return types.PullRequestStatus{}, fmt.Errorf("failed to read pull request: %w", err)
+	}
+
+	status := types.PullRequestStatus{Draft: pr.IsDraft}
+	switch pr.State {
+	case "MERGED":
+		status.State = types.PullRequestMerged
+	case "CLOSED":
+		status.State = types.PullRequestClosed
+	default:
+		status.State = types.PullRequestOpen
+	}
+
+	switch pr.ReviewDecision {
+	case "APPROVED":
+		status.Review = types.ReviewApproved
+	case "CHANGES_REQUESTED":
+		status.Review = types.ReviewChangesRequested
+	case "REVIEW_REQUIRED":
+		status.Review = types.ReviewRequested
+	default:
+		if len(pr.ReviewRequests) > 0 {
+			status.Review = types.ReviewRequested
+		}
+	}
+
+	var states []string
+	for _, check := range pr.Checks {
+		switch {
+		case check.State != "":
+			states = append(states, check.State)
+		case check.Status != "COMPLETED":
+			states = append(states, "PENDING")
+		default:
+			states = append(states, check.Conclusion)
+		}
+	}
+	status.Checks = checksState(states, []string{"SUCCESS", "NEUTRAL", "SKIPPED"}, []string{"PENDING", "EXPECTED"})
+	return status, nil
+}
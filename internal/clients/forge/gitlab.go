@@ -0,0 +1,112 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// GitLab opens merge requests with the GitLab CLI, glab
+type GitLab struct{}
+
+func (GitLab) Name() string { return "GitLab" }
+
+func (GitLab) Available() error {
+	return cliAvailable("glab", "GitLab")
+}
+
+func (GitLab) CreatePullRequest(dir string, pr PullRequest) (string, error) {
+	args := []string{"mr", "create", "--title", pr.Title, "--description", pr.Body,
+		"--source-branch", pr.Branch, "--yes"}
+	if pr.Base != "" {
+		args = append(args, "--target-branch", pr.Base)
+	}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command("glab", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("glab mr create failed: %w", err)
+	}
+	return lastURL(output.String()), nil
+}
+
+func (GitLab) PullRequestURL(dir, branch string) (string, error) {
+	cmd := exec.Command("glab", "mr", "view", branch, "--output", "json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no merge request found for branch %s: %w", branch, err)
+	}
+
+	var mr struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := json.Unmarshal(output, &mr); err != nil || mr.WebURL == "" {
+		return "", fmt.Errorf("no merge request found for branch %s", branch)
+	}
+	return mr.WebURL, nil
+}
+
+func (GitLab) PullRequestStatus(dir, url string) (types.PullRequestStatus, error) {
+	number := types.PullRequestNumber(url)
+	if number == 0 {
+		return types.PullRequestStatus{}, fmt.Errorf("can't read the merge request number of %s", url)
+	}
+	cmd := exec.Command("glab", "mr", "view", strconv.Itoa(number), "--output", "json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return types.PullRequestStatus{}, fmt.Errorf("glab mr view failed: %w", err)
+	}
+	return parseGitLabStatus(output)
+}
+
+// parseGitLabStatus reads the status of a merge request from the JSON of
+// glab mr view
+func parseGitLabStatus(data []byte) (types.PullRequestStatus, error) {
+	var mr struct {
+		State               string `json:"state"`
+		Draft               bool   `json:"draft"`
+		DetailedMergeStatus string `json:"detailed_merge_status"`
+		HeadPipeline        *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	if err := json.Unmarshal(data, &mr); err != nil {
+		return types.PullRequestStatus{}, fmt.Errorf("failed to read merge request: %w", err)
+	}
+
+	status := types.PullRequestStatus{Draft: mr.Draft}
+	switch mr.State {
+	case "merged":
+		status.State = types.PullRequestMerged
+	case "closed", "locked":
+		status.State = types.PullRequestClosed
+	default:
+		status.State = types.PullRequestOpen
+	}
+
+	switch {
+	case mr.DetailedMergeStatus == "requested_changes":
+		status.Review = types.ReviewChangesRequested
+	case mr.DetailedMergeStatus == "not_approved":
+		status.Review = types.ReviewRequested
+	}
+
+	if mr.HeadPipeline != nil {
+		status.Checks = checksState([]string{mr.HeadPipeline.Status}, []string{"SUCCESS", "SKIPPED", "MANUAL"},
+			[]string{"CREATED", "WAITING_FOR_RESOURCE", "PREPARING", "PENDING", "RUNNING", "SCHEDULED"})
+	}
+	return status, nil
+}
@@ -0,0 +1,18 @@
+package git
+
+// Backends that run git operations, chosen with git_backend in the config
+const (
+	BackendExec  = "exec"   // Run the git command for everything
+	BackendGoGit = "go-git" // Read status, branches and worktrees in process with go-git
+)
+
+// NewChecker creates the Checker of a backend. The go-git backend falls back
+// to running git for what go-git can't do.
+func NewChecker(backend, baseBranch string) Checker {
+	real := NewRealChecker(baseBranch)
+	if backend != BackendGoGit {
+		return real
+	}
+
+	return &GoGitChecker{RealChecker: real}
+}
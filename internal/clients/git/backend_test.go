@@ -0,0 +1,16 @@
+package git
+
+import "testing"
+
+func TestNewChecker(t *testing.T) {
+	if _, ok := NewChecker(BackendExec, "develop").(*RealChecker); !ok {
+		t.Error("NewChecker(exec) should run git")
+	}
+	if checker, ok := NewChecker("", "").(*RealChecker); !ok || checker.BaseBranch != "main" {
+		t.Errorf("NewChecker(\"\") = %+v, want git run against main", checker)
+	}
+
+	if checker, ok := NewChecker(BackendGoGit, "develop").(*GoGitChecker); !ok || checker.BaseBranch != "develop" {
+		t.Errorf("NewChecker(go-git) = %+v, want go-git falling back to git against develop", checker)
+	}
+}
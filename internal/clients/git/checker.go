@@ -1,26 +1,62 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/jlaneve/cwt-cli/internal/logging"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
+// logger is the git client's diagnostic log
+var logger = logging.For("git")
+
 // Checker defines the interface for git operations
 type Checker interface {
-	GetStatus(worktreePath string) types.GitStatus
-	CreateWorktree(branchName, worktreePath string) error
+	GetStatus(worktreePath string) (types.GitStatus, error)
+	CommittedChanges(worktreePath string) ([]types.ChangedFile, error)
+	CreateWorktree(ctx context.Context, branchName, worktreePath string, progress func(step string)) error
+	AddWorktree(branchName, worktreePath string) error
+	AddDetachedWorktree(commit, worktreePath string) error
+	PopulateWorktree(ctx context.Context, worktreePath string, progress func(step string)) error
+	InstallHooks(ctx context.Context, worktreePath, command string) (HookManager, error)
 	RemoveWorktree(worktreePath string) error
 	IsValidRepository(repoPath string) error
 	ListWorktrees() ([]WorktreeInfo, error)
 	BranchExists(branchName string) bool
+	ListBranches() ([]string, error)
+	DeleteBranch(branchName string) error
+	BranchMerged(branchName string) (bool, error)
+	UnmergedCommits(branchName string) ([]types.CommitOutput, error)
+	RenameBranch(branchName, newName string) error
 	CommitChanges(worktreePath, message string) error
+	CommitStaged(worktreePath, message string) error
+	ApplyToIndex(worktreePath, patch string, reverse bool) error
+	StageFile(worktreePath, path string) error
+	UnstageFile(worktreePath, path string) error
+	HideLocalChanges(worktreePath string, paths []string) error
+	Rebase(worktreePath, onto string) error
+	AbortRebase(worktreePath string) error
+	MergeInto(worktreePath, from string) error
+	AbortMerge(worktreePath string) error
+	Fetch(remote string) error
+	PredictConflicts(branch, into string) ([]string, error)
+	SnapshotWorktree(worktreePath string) (string, error)
+	PinSnapshot(tree, ref string) error
+	DeleteRef(ref string) error
 	CheckoutBranch(branchName string) error
+	GetCurrentBranch(worktreePath string) (string, error)
+	ResolveCommit(rev string) (string, error)
+	BranchDiff(branchName string) (string, error)
+	BranchLog(branchName string) (string, error)
 }
 
 // WorktreeInfo represents information about a git worktree
@@ -30,9 +66,33 @@ type WorktreeInfo struct {
 	Bare   bool
 }
 
+// StatusError is returned by GetStatus when a worktree's status cannot be read
+type StatusError struct {
+	Kind types.GitErrorKind
+	Path string
+	Err  error
+}
+
+func (e *StatusError) Error() string {
+	switch e.Kind {
+	case types.GitErrorMissingWorktree:
+		return fmt.Sprintf("worktree not found: %s", e.Path)
+	case types.GitErrorNotRepository:
+		return fmt.Sprintf("not a git repository: %s", e.Path)
+	default:
+		return fmt.Sprintf("git status failed in %s: %v", e.Path, e.Err)
+	}
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
 // RealChecker implements Checker using actual git commands
 type RealChecker struct {
 	BaseBranch string // Default branch to create worktrees from
+
+	mergeTreeUnsupported atomic.Bool // Set once git turns out too old for PredictConflicts
 }
 
 // NewRealChecker creates a new RealChecker
@@ -43,69 +103,284 @@ func NewRealChecker(baseBranch string) *RealChecker {
 	return &RealChecker{BaseBranch: baseBranch}
 }
 
-// GetStatus checks the git status of a worktree
-func (r *RealChecker) GetStatus(worktreePath string) types.GitStatus {
+// conflictCodes maps the porcelain status codes of unmerged files to the
+// kind of conflict they are in
+var conflictCodes = map[string]types.ConflictKind{
+	"UU": types.ConflictBothModified,
+	"AA": types.ConflictBothAdded,
+	"DD": types.ConflictBothDeleted,
+	"AU": types.ConflictAddedByUs,
+	"UA": types.ConflictAddedByThem,
+	"DU": types.ConflictDeletedByUs,
+	"UD": types.ConflictDeletedByThem,
+}
+
+// GetStatus checks the git status of a worktree: its changed files, staged
+// and unstaged, its branch and how far that is from the base branch and the
+// branch's upstream. It runs as often as sessions are refreshed, so it takes
+// just two git commands: one status and one rev-list.
+func (r *RealChecker) GetStatus(worktreePath string) (types.GitStatus, error) {
 	status := types.GitStatus{}
 
 	if !r.pathExists(worktreePath) {
-		return status
+		return status, &StatusError{Kind: types.GitErrorMissingWorktree, Path: worktreePath}
 	}
 
-	// Get porcelain status
-	cmd := exec.Command("git", "status", "--porcelain")
+	// Porcelain v2 gives the branch and its upstream along with the files;
+	// -z keeps paths unquoted
+	cmd := exec.Command("git", "status", "--porcelain=v2", "--branch", "-z")
 	cmd.Dir = worktreePath
 	output, err := cmd.Output()
 	if err != nil {
-		return status
+		return status, classifyStatusError(worktreePath, err)
 	}
+	parseStatusV2(string(output), &status)
+
+	// Count commits ahead of and behind the base branch
+	status.BehindCount, status.CommitCount, _ = revListCounts(worktreePath, r.BaseBranch+"...HEAD")
 
-	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		// No changes
-		return status
+	return status, nil
+}
+
+// revListCounts counts the commits on either side of a symmetric difference
+// like "main...HEAD": those only reachable from the left and from the right
+func revListCounts(worktreePath, symmetricDiff string) (left, right int, err error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", symmetricDiff)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count commits of %s: %w", symmetricDiff, err)
+	}
+	if _, err := fmt.Sscanf(string(output), "%d %d", &left, &right); err != nil {
+		return 0, 0, fmt.Errorf("failed to count commits of %s: %w", symmetricDiff, err)
 	}
+	return left, right, nil
+}
 
-	for _, line := range lines {
-		if len(line) < 3 {
+// parseStatusV2 fills status from the output of
+// 'git status --porcelain=v2 --branch -z'
+func parseStatusV2(output string, status *types.GitStatus) {
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if entry == "" {
 			continue
 		}
 
-		statusCode := line[:2]
-		filename := line[3:]
+		switch entry[0] {
+		case '#':
+			parseBranchHeader(entry, status)
 
-		// Ignore Claude-related files and directories
-		if strings.HasPrefix(filename, ".claude/") || filename == ".claude" {
-			continue
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			if fields := strings.SplitN(entry, " ", 9); len(fields) == 9 {
+				addStatusChange(status, fields[1], fields[8])
+			}
+
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <score> <path>, then the
+			// path it was renamed or copied from as the next entry
+			if fields := strings.SplitN(entry, " ", 10); len(fields) == 10 {
+				addStatusChange(status, fields[1], fields[9])
+			}
+			i++
+
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(entry, " ", 11)
+			if len(fields) != 11 || isClaudePath(fields[10]) {
+				continue
+			}
+			kind, ok := conflictCodes[fields[1]]
+			if !ok {
+				kind = types.ConflictBothModified
+			}
+			status.HasChanges = true
+			status.ConflictedFiles = append(status.ConflictedFiles, types.ConflictedFile{Path: fields[10], Kind: kind})
+
+		case '?':
+			if path := strings.TrimPrefix(entry, "? "); !isClaudePath(path) {
+				status.HasChanges = true
+				status.UntrackedFiles = append(status.UntrackedFiles, path)
+			}
 		}
+	}
+}
 
-		// We have a non-Claude change
-		status.HasChanges = true
-
-		switch {
-		case strings.HasPrefix(statusCode, "M") || strings.HasPrefix(statusCode, " M"):
-			status.ModifiedFiles = append(status.ModifiedFiles, filename)
-		case strings.HasPrefix(statusCode, "A"):
-			status.AddedFiles = append(status.AddedFiles, filename)
-		case strings.HasPrefix(statusCode, "??"):
-			status.UntrackedFiles = append(status.UntrackedFiles, filename)
-		case strings.HasPrefix(statusCode, "D") || strings.HasPrefix(statusCode, " D"):
-			status.DeletedFiles = append(status.DeletedFiles, filename)
+// parseBranchHeader reads a "# branch." header line of porcelain v2 status
+func parseBranchHeader(header string, status *types.GitStatus) {
+	key, value, _ := strings.Cut(strings.TrimPrefix(header, "# "), " ")
+	switch key {
+	case "branch.head":
+		if value != "(detached)" {
+			status.Branch = value
 		}
+	case "branch.upstream":
+		status.Upstream = value
+	case "branch.ab":
+		fmt.Sscanf(value, "+%d -%d", &status.UpstreamAhead, &status.UpstreamBehind)
+	}
+}
+
+// addStatusChange files a changed path under its kind of change and under
+// staged, unstaged or both. xy is the index and working tree status, with
+// '.' for no change.
+func addStatusChange(status *types.GitStatus, xy, path string) {
+	if len(xy) != 2 || isClaudePath(path) {
+		return
+	}
+	status.HasChanges = true
+
+	index, worktree := xy[0], xy[1]
+	if index != '.' {
+		status.StagedFiles = append(status.StagedFiles, path)
+	}
+	if worktree != '.' {
+		status.UnstagedFiles = append(status.UnstagedFiles, path)
 	}
 
-	// Count commits ahead of base branch
-	cmd = exec.Command("git", "rev-list", "--count", fmt.Sprintf("%s..HEAD", r.BaseBranch))
+	change := index
+	if change == '.' {
+		change = worktree
+	}
+	switch change {
+	case 'M', 'T':
+		status.ModifiedFiles = append(status.ModifiedFiles, path)
+	case 'A', 'R', 'C':
+		status.AddedFiles = append(status.AddedFiles, path)
+	case 'D':
+		status.DeletedFiles = append(status.DeletedFiles, path)
+	}
+}
+
+// isClaudePath reports whether a path is Claude's own settings, which don't
+// count as changes to the session's work
+func isClaudePath(path string) bool {
+	return path == ".claude" || strings.HasPrefix(path, ".claude/")
+}
+
+// SettingsFile is where cwt writes a session's Claude hooks in its worktree
+const SettingsFile = ".claude/settings.json"
+
+// ContextDir is where a worktree gets the shared context files registered
+// with 'cwt context set'
+const ContextDir = ".claude/cwt-context"
+
+// CwtWrittenFiles lists the files in a worktree that cwt wrote rather than
+// the session's work: its Claude settings, while they carry cwt's hooks,
+// and the shared context files. They are left out of commits and diffs.
+func CwtWrittenFiles(worktreePath string) []string {
+	var files []string
+	data, err := os.ReadFile(filepath.Join(worktreePath, SettingsFile))
+	if err == nil && strings.Contains(string(data), " __hook ") {
+		files = append(files, SettingsFile)
+	}
+	if _, err := os.Lstat(filepath.Join(worktreePath, ContextDir)); err == nil {
+		files = append(files, ContextDir)
+	}
+	return files
+}
+
+// WorkPathspec returns the pathspec, "--" included, that ends a git add or
+// diff run in a worktree so it covers the worktree without the files cwt
+// wrote there
+func WorkPathspec(worktreePath string) []string {
+	pathspec := []string{"--", "."}
+	for _, file := range CwtWrittenFiles(worktreePath) {
+		pathspec = append(pathspec, ":(exclude)"+file)
+	}
+	return pathspec
+}
+
+// CommittedChanges lists the files changed by a worktree's commits since
+// its branch left the base branch
+func (r *RealChecker) CommittedChanges(worktreePath string) ([]types.ChangedFile, error) {
+	cmd := exec.Command("git", "diff", "--name-status", "--no-renames", "-z", r.BaseBranch+"...HEAD")
 	cmd.Dir = worktreePath
-	output, err = cmd.Output()
-	if err == nil {
-		fmt.Sscanf(string(output), "%d", &status.CommitCount)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list committed changes in %s: %w", worktreePath, err)
+	}
+
+	// -z separates the status and path of each file with NULs
+	var changes []types.ChangedFile
+	fields := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		change := types.FileModified
+		switch fields[i] {
+		case "A":
+			change = types.FileAdded
+		case "D":
+			change = types.FileDeleted
+		}
+		changes = append(changes, types.ChangedFile{Path: fields[i+1], Change: change, Committed: true})
+	}
+	return changes, nil
+}
+
+// BranchDiff returns the patch of a branch's changes since it left the base
+// branch, ignoring anything the base branch gained since
+func (r *RealChecker) BranchDiff(branchName string) (string, error) {
+	cmd := exec.Command("git", "diff", "--no-color", "--no-ext-diff", r.BaseBranch+"..."+branchName)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff branch %s: %w", branchName, err)
+	}
+	return string(output), nil
+}
+
+// BranchLog returns the log of the commits on a branch that aren't on the
+// base branch, newest first
+func (r *RealChecker) BranchLog(branchName string) (string, error) {
+	cmd := exec.Command("git", "log", "--no-color", "--stat", r.BaseBranch+".."+branchName)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list commits of branch %s: %w", branchName, err)
+	}
+	return string(output), nil
+}
+
+// UnmergedCommits lists the commits on a local branch that the base branch
+// lacks, oldest first
+func (r *RealChecker) UnmergedCommits(branchName string) ([]types.CommitOutput, error) {
+	cmd := exec.Command("git", "log", "--reverse", "--format=%H%x09%s", r.BaseBranch+"..refs/heads/"+branchName)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmerged commits of branch %s: %w", branchName, err)
+	}
+
+	var commits []types.CommitOutput
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, subject, ok := strings.Cut(line, "\t")
+		if ok {
+			commits = append(commits, types.CommitOutput{Hash: hash, Subject: subject})
+		}
+	}
+	return commits, nil
+}
+
+// classifyStatusError turns a failed git status invocation into a StatusError
+func classifyStatusError(worktreePath string, err error) *StatusError {
+	kind := types.GitErrorCommandFailed
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		stderr := strings.TrimSpace(string(exitErr.Stderr))
+		if strings.Contains(stderr, "not a git repository") {
+			kind = types.GitErrorNotRepository
+		}
+		if stderr != "" {
+			err = fmt.Errorf("%s: %w", stderr, err)
+		}
 	}
 
-	return status
+	return &StatusError{Kind: kind, Path: worktreePath, Err: err}
 }
 
-// CreateWorktree creates a new git worktree with a new branch
-func (r *RealChecker) CreateWorktree(branchName, worktreePath string) error {
+// CreateWorktree creates a new git worktree with a new branch. The files are
+// checked out in a second step, whose progress git reports on big
+// repositories. When ctx is cancelled or a step fails, the worktree and branch
+// are removed again.
+func (r *RealChecker) CreateWorktree(ctx context.Context, branchName, worktreePath string, progress func(step string)) error {
 	// Check if worktree directory already exists
 	if r.pathExists(worktreePath) {
 		return fmt.Errorf("worktree directory already exists: %s", worktreePath)
@@ -123,18 +398,79 @@ func (r *RealChecker) CreateWorktree(branchName, worktreePath string) error {
 	}
 
 	// Create worktree with new branch
-	cmd := exec.Command("git", "worktree", "add", "-b", branchName, worktreePath, r.BaseBranch)
+	logger.Info("creating worktree", "branch", branchName, "path", worktreePath, "base", r.BaseBranch)
+	cmd := exec.CommandContext(ctx, "git", "worktree", "add", "--no-checkout", "-b", branchName, worktreePath, r.BaseBranch)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		r.discardWorktree(branchName, worktreePath)
 		return fmt.Errorf("failed to create worktree %s: %w\nOutput: %s", worktreePath, err, string(output))
 	}
 
+	if err := runGitStreaming(ctx, worktreePath, progress, "checkout", "--progress", "--force"); err != nil {
+		r.discardWorktree(branchName, worktreePath)
+		return fmt.Errorf("failed to check out worktree %s: %w", worktreePath, err)
+	}
+
+	return nil
+}
+
+// discardWorktree removes what a failed or cancelled CreateWorktree left
+// behind. Errors are ignored, since any step may not have happened yet.
+func (r *RealChecker) discardWorktree(branchName, worktreePath string) {
+	logger.Info("discarding worktree", "branch", branchName, "path", worktreePath)
+	exec.Command("git", "worktree", "remove", "--force", worktreePath).Run()
+	os.RemoveAll(worktreePath)
+	exec.Command("git", "worktree", "prune").Run()
+	exec.Command("git", "branch", "-D", branchName).Run()
+}
+
+// AddWorktree creates a git worktree checking out an existing branch
+func (r *RealChecker) AddWorktree(branchName, worktreePath string) error {
+	if r.pathExists(worktreePath) {
+		return fmt.Errorf("worktree directory already exists: %s", worktreePath)
+	}
+
+	if !r.BranchExists(branchName) {
+		return fmt.Errorf("branch '%s' does not exist", branchName)
+	}
+
+	parentDir := filepath.Dir(worktreePath)
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory %s: %w", parentDir, err)
+	}
+
+	// A worktree deleted by hand is still registered, which makes git refuse
+	// to check its branch out again
+	exec.Command("git", "worktree", "prune").Run()
+
+	cmd := exec.Command("git", "worktree", "add", worktreePath, branchName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create worktree %s: %w\nOutput: %s", worktreePath, err, string(output))
+	}
+
+	return nil
+}
+
+// AddDetachedWorktree creates a git worktree with a commit checked out and
+// no branch, for looking at a revision without touching any branch
+func (r *RealChecker) AddDetachedWorktree(commit, worktreePath string) error {
+	if r.pathExists(worktreePath) {
+		return fmt.Errorf("worktree directory already exists: %s", worktreePath)
+	}
+
+	cmd := exec.Command("git", "worktree", "add", "--detach", worktreePath, commit)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create worktree %s: %w\nOutput: %s", worktreePath, err, string(output))
+	}
 	return nil
 }
 
 // RemoveWorktree removes a git worktree
 func (r *RealChecker) RemoveWorktree(worktreePath string) error {
 	// Remove the worktree
+	logger.Info("removing worktree", "path", worktreePath)
 	cmd := exec.Command("git", "worktree", "remove", worktreePath, "--force")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -230,21 +566,137 @@ func (r *RealChecker) BranchExists(branchName string) bool {
 	return false
 }
 
-// CommitChanges stages all changes and commits them with the given message
+// ListBranches returns the short names of all local and remote-tracking branches
+func (r *RealChecker) ListBranches() ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads", "refs/remotes")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(string(output), "\n") {
+		branch := strings.TrimSpace(line)
+		// Skip symbolic refs such as origin/HEAD
+		if branch == "" || strings.HasSuffix(branch, "/HEAD") {
+			continue
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// DeleteBranch deletes a local branch, even if it hasn't been merged
+func (r *RealChecker) DeleteBranch(branchName string) error {
+	logger.Info("deleting branch", "branch", branchName)
+	cmd := exec.Command("git", "branch", "-D", branchName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w\nOutput: %s", branchName, err, string(output))
+	}
+	return nil
+}
+
+// BranchMerged reports whether every commit of a local branch is already on
+// the base branch, so deleting the branch loses nothing
+func (r *RealChecker) BranchMerged(branchName string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", "refs/heads/"+branchName, r.BaseBranch)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to compare branch %s with %s: %w\nOutput: %s", branchName, r.BaseBranch, err, string(output))
+}
+
+// RenameBranch renames a local branch, including in a worktree that has it
+// checked out
+func (r *RealChecker) RenameBranch(branchName, newName string) error {
+	cmd := exec.Command("git", "branch", "-m", branchName, newName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to rename branch %s: %w\nOutput: %s", branchName, err, string(output))
+	}
+	return nil
+}
+
+// ApplyToIndex applies a patch to a worktree's index without touching its
+// files, staging the changes it contains or, with reverse, unstaging them
+func (r *RealChecker) ApplyToIndex(worktreePath, patch string, reverse bool) error {
+	args := []string{"apply", "--cached"}
+	if reverse {
+		args = append(args, "--reverse")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = worktreePath
+	cmd.Stdin = strings.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to apply patch to index: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// StageFile stages every change to one file, including its deletion
+func (r *RealChecker) StageFile(worktreePath, path string) error {
+	cmd := exec.Command("git", "add", "--all", "--", path)
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage %s: %w\nOutput: %s", path, err, string(output))
+	}
+	return nil
+}
+
+// UnstageFile removes one file's staged changes from the index, leaving the
+// worktree as it is
+func (r *RealChecker) UnstageFile(worktreePath, path string) error {
+	cmd := exec.Command("git", "reset", "-q", "--", path)
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to unstage %s: %w\nOutput: %s", path, err, string(output))
+	}
+	return nil
+}
+
+// HideLocalChanges makes git ignore changes to tracked files in a worktree,
+// so they show up in neither its status nor its commits. Only that
+// worktree's index is marked; other worktrees are unaffected.
+func (r *RealChecker) HideLocalChanges(worktreePath string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	cmd := exec.Command("git", append([]string{"update-index", "--skip-worktree", "--"}, paths...)...)
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to hide changes to %s: %w\nOutput: %s", strings.Join(paths, ", "), err, string(output))
+	}
+	return nil
+}
+
+// CommitChanges stages all changes but the files cwt wrote and commits them
+// with the given message
 func (r *RealChecker) CommitChanges(worktreePath, message string) error {
 	// Stage all changes
-	cmd := exec.Command("git", "add", ".")
+	cmd := exec.Command("git", append([]string{"add"}, WorkPathspec(worktreePath)...)...)
 	cmd.Dir = worktreePath
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to stage changes: %w\nOutput: %s", err, string(output))
 	}
 
+	return r.CommitStaged(worktreePath, message)
+}
+
+// CommitStaged commits what is already staged, leaving other changes in the worktree
+func (r *RealChecker) CommitStaged(worktreePath, message string) error {
 	// Get git user configuration
 	name, email := r.getGitUserConfig()
 
 	// Create commit
-	cmd = exec.Command("git", "commit", "-m", message)
+	cmd := exec.Command("git", "commit", "-m", message)
 	if name != "" {
 		cmd.Env = append(os.Environ(), fmt.Sprintf("GIT_AUTHOR_NAME=%s", name))
 		cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_COMMITTER_NAME=%s", name))
@@ -254,7 +706,7 @@ func (r *RealChecker) CommitChanges(worktreePath, message string) error {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_COMMITTER_EMAIL=%s", email))
 	}
 	cmd.Dir = worktreePath
-	output, err = cmd.CombinedOutput()
+	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to commit changes: %w\nOutput: %s", err, string(output))
 	}
@@ -262,6 +714,177 @@ func (r *RealChecker) CommitChanges(worktreePath, message string) error {
 	return nil
 }
 
+// RebaseConflictError is returned by Rebase when conflicts stopped the
+// rebase, leaving it in progress in the worktree
+type RebaseConflictError struct {
+	Onto  string
+	Files []string
+}
+
+func (e *RebaseConflictError) Error() string {
+	return fmt.Sprintf("rebase onto %s stopped on conflicts in %d file(s): %s", e.Onto, len(e.Files), strings.Join(e.Files, ", "))
+}
+
+// Rebase rebases the branch checked out in a worktree onto another branch
+// or commit. When conflicts stop it, the rebase is left in progress to be
+// resolved or aborted, and a RebaseConflictError lists the conflicted files.
+func (r *RealChecker) Rebase(worktreePath, onto string) error {
+	cmd := exec.Command("git", "rebase", onto)
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if !rebaseInProgress(worktreePath) {
+		return fmt.Errorf("failed to rebase onto %s: %w\nOutput: %s", onto, err, string(output))
+	}
+	return &RebaseConflictError{Onto: onto, Files: unmergedFiles(worktreePath)}
+}
+
+// unmergedFiles lists the files a merge or rebase left conflicted in a worktree
+func unmergedFiles(worktreePath string) []string {
+	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U", "-z")
+	cmd.Dir = worktreePath
+	unmerged, _ := cmd.Output()
+	var files []string
+	for _, path := range strings.Split(string(unmerged), "\x00") {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// rebaseInProgress reports whether a rebase stopped halfway in a worktree
+func rebaseInProgress(worktreePath string) bool {
+	return gitPathExists(worktreePath, "rebase-merge") || gitPathExists(worktreePath, "rebase-apply")
+}
+
+// mergeInProgress reports whether a merge stopped on conflicts in a worktree
+func mergeInProgress(worktreePath string) bool {
+	return gitPathExists(worktreePath, "MERGE_HEAD")
+}
+
+// gitPathExists reports whether a file exists in a worktree's git directory
+func gitPathExists(worktreePath, name string) bool {
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	path := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(worktreePath, path)
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// AbortRebase abandons the rebase in progress in a worktree, putting its
+// branch back where it was
+func (r *RealChecker) AbortRebase(worktreePath string) error {
+	if !rebaseInProgress(worktreePath) {
+		return fmt.Errorf("no rebase in progress in %s", worktreePath)
+	}
+	cmd := exec.Command("git", "rebase", "--abort")
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to abort rebase: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// MergeConflictError is returned by MergeInto when conflicts stopped the
+// merge, leaving it in progress in the worktree
+type MergeConflictError struct {
+	From  string
+	Files []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("merge of %s stopped on conflicts in %d file(s): %s", e.From, len(e.Files), strings.Join(e.Files, ", "))
+}
+
+// MergeInto merges another branch or commit into the branch checked out in
+// a worktree. When conflicts stop it, the merge is left in progress to be
+// resolved or aborted, and a MergeConflictError lists the conflicted files.
+func (r *RealChecker) MergeInto(worktreePath, from string) error {
+	cmd := exec.Command("git", "merge", "--no-edit", from)
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if !mergeInProgress(worktreePath) {
+		return fmt.Errorf("failed to merge %s: %w\nOutput: %s", from, err, string(output))
+	}
+	return &MergeConflictError{From: from, Files: unmergedFiles(worktreePath)}
+}
+
+// AbortMerge abandons the merge in progress in a worktree, putting its
+// branch and files back as they were
+func (r *RealChecker) AbortMerge(worktreePath string) error {
+	if !mergeInProgress(worktreePath) {
+		return fmt.Errorf("no merge in progress in %s", worktreePath)
+	}
+	cmd := exec.Command("git", "merge", "--abort")
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to abort merge: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// Fetch fetches a remote's branches, updating its remote-tracking branches
+func (r *RealChecker) Fetch(remote string) error {
+	logger.Info("fetching", "remote", remote)
+	cmd := exec.Command("git", "fetch", "--quiet", remote)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w\nOutput: %s", remote, err, string(output))
+	}
+	return nil
+}
+
+// ErrMergeTreeUnsupported is returned by PredictConflicts when git is too
+// old to merge without a worktree; 'git merge-tree --write-tree' needs 2.38
+var ErrMergeTreeUnsupported = errors.New("predicting conflicts needs git 2.38 or later")
+
+// PredictConflicts merges branch into into in memory with 'git merge-tree',
+// touching no worktree or ref, and returns the files that would conflict,
+// or none when the merge would be clean
+func (r *RealChecker) PredictConflicts(branch, into string) ([]string, error) {
+	if r.mergeTreeUnsupported.Load() {
+		return nil, ErrMergeTreeUnsupported
+	}
+
+	cmd := exec.Command("git", "merge-tree", "--write-tree", "--name-only", "--no-messages", "-z", into, branch)
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil, nil
+	case !errors.As(err, &exitErr):
+		return nil, fmt.Errorf("failed to merge %s into %s: %w", branch, into, err)
+	case exitErr.ExitCode() == 129: // Usage error: no --write-tree before git 2.38
+		r.mergeTreeUnsupported.Store(true)
+		return nil, ErrMergeTreeUnsupported
+	case exitErr.ExitCode() != 1:
+		return nil, fmt.Errorf("failed to merge %s into %s: %w\nOutput: %s", branch, into, err, exitErr.Stderr)
+	}
+
+	// Exit status 1 means conflicts: the tree written, then the conflicted
+	// files, each ending in a NUL
+	entries := strings.Split(string(output), "\x00")
+	var files []string
+	for _, file := range entries[1:] {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
 // CheckoutBranch switches to the specified branch
 func (r *RealChecker) CheckoutBranch(branchName string) error {
 	cmd := exec.Command("git", "checkout", branchName)
@@ -272,6 +895,27 @@ func (r *RealChecker) CheckoutBranch(branchName string) error {
 	return nil
 }
 
+// GetCurrentBranch returns the branch checked out in the given worktree
+func (r *RealChecker) GetCurrentBranch(worktreePath string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch in %s: %w", worktreePath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResolveCommit returns the hash of the commit a branch name or other
+// revision points to
+func (r *RealChecker) ResolveCommit(rev string) (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // getGitUserConfig gets the git user name and email from config
 func (r *RealChecker) getGitUserConfig() (string, string) {
 	var name, email string
@@ -301,39 +945,103 @@ func (r *RealChecker) getGitUserConfig() (string, string) {
 
 // MockChecker implements Checker for testing
 type MockChecker struct {
-	Statuses   map[string]types.GitStatus
-	Worktrees  map[string]bool
-	ShouldFail map[string]bool
-	Delay      time.Duration
-	ValidRepo  bool
+	Statuses     map[string]types.GitStatus
+	StatusErrors map[string]error
+	Worktrees    map[string]bool
+	ShouldFail   map[string]bool
+	Delay        time.Duration
+	ValidRepo    bool
+	Branches     map[string]string
+	Deleted      []string                        // Branches removed with DeleteBranch
+	Unmerged     map[string]bool                 // Branches BranchMerged reports commits on; others are merged
+	Ahead        map[string][]types.CommitOutput // Commits UnmergedCommits reports for each branch
+	Renamed      map[string]string               // New name of each branch renamed with RenameBranch
+	Committed    map[string][]types.ChangedFile
+	DiffCalls    int                 // Number of CommittedChanges calls
+	Diffs        map[string]string   // Patch BranchDiff returns for each branch
+	Logs         map[string]string   // Log BranchLog returns for each branch
+	HookInstalls map[string]string   // Command InstallHooks ran in each worktree ("" to detect)
+	FailHooks    bool                // Make InstallHooks fail
+	Populated    map[string]bool     // Worktrees PopulateWorktree ran in
+	Existing     map[string]bool     // Branches BranchExists reports; others don't exist
+	Rebased      map[string]string   // What each worktree was rebased onto
+	RebaseFails  map[string][]string // Files Rebase leaves conflicted in each worktree
+	Aborted      []string            // Worktrees whose rebase or merge was aborted
+	Commits      map[string]string   // Commit ResolveCommit returns for each revision
+	Detached     []string            // Commits checked out with AddDetachedWorktree
+	MergedFrom   map[string]string   // What was merged into each worktree
+	MergeFails   map[string][]string // Files MergeInto leaves conflicted in each worktree
+	Fetched      []string            // Remotes fetched
+	Predicted    map[string][]string // Files PredictConflicts reports for each branch
+	Predictions  int                 // Number of PredictConflicts calls
+	Snapshots    map[string]string   // Tree SnapshotWorktree returns for each worktree
+	Pinned       map[string]string   // Tree each ref was pinned to
+	Hidden       map[string][]string // Files whose changes HideLocalChanges hid in each worktree
 }
 
 // NewMockChecker creates a new MockChecker
 func NewMockChecker() *MockChecker {
 	return &MockChecker{
-		Statuses:   make(map[string]types.GitStatus),
-		Worktrees:  make(map[string]bool),
-		ShouldFail: make(map[string]bool),
-		ValidRepo:  true,
+		Statuses:     make(map[string]types.GitStatus),
+		StatusErrors: make(map[string]error),
+		Worktrees:    make(map[string]bool),
+		ShouldFail:   make(map[string]bool),
+		ValidRepo:    true,
+		Branches:     make(map[string]string),
+		Renamed:      make(map[string]string),
+		Unmerged:     make(map[string]bool),
+		Ahead:        make(map[string][]types.CommitOutput),
+		Committed:    make(map[string][]types.ChangedFile),
+		Diffs:        make(map[string]string),
+		Logs:         make(map[string]string),
+		HookInstalls: make(map[string]string),
+		Populated:    make(map[string]bool),
+		Existing:     make(map[string]bool),
+		Rebased:      make(map[string]string),
+		RebaseFails:  make(map[string][]string),
+		Commits:      make(map[string]string),
+		MergedFrom:   make(map[string]string),
+		MergeFails:   make(map[string][]string),
+		Predicted:    make(map[string][]string),
+		Snapshots:    make(map[string]string),
+		Pinned:       make(map[string]string),
+		Hidden:       make(map[string][]string),
 	}
 }
 
 // GetStatus returns the mocked status
-func (m *MockChecker) GetStatus(worktreePath string) types.GitStatus {
+func (m *MockChecker) GetStatus(worktreePath string) (types.GitStatus, error) {
 	if m.Delay > 0 {
 		time.Sleep(m.Delay)
 	}
+	if err, exists := m.StatusErrors[worktreePath]; exists {
+		return types.GitStatus{}, err
+	}
 	status, exists := m.Statuses[worktreePath]
 	if !exists {
-		return types.GitStatus{} // Empty status
+		return types.GitStatus{}, nil // Empty status
+	}
+	return status, nil
+}
+
+// CommittedChanges returns the mocked committed changes of a worktree
+func (m *MockChecker) CommittedChanges(worktreePath string) ([]types.ChangedFile, error) {
+	m.DiffCalls++
+	if m.ShouldFail[worktreePath] {
+		return nil, fmt.Errorf("mock diff failure for worktree %s", worktreePath)
 	}
-	return status
+	return m.Committed[worktreePath], nil
 }
 
-// CreateWorktree mocks worktree creation
-func (m *MockChecker) CreateWorktree(branchName, worktreePath string) error {
+// CreateWorktree mocks worktree creation. A Delay can be cut short by
+// cancelling ctx.
+func (m *MockChecker) CreateWorktree(ctx context.Context, branchName, worktreePath string, progress func(step string)) error {
 	if m.Delay > 0 {
-		time.Sleep(m.Delay)
+		select {
+		case <-time.After(m.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 	if m.ShouldFail[worktreePath] {
 		return fmt.Errorf("mock create failure for worktree %s", worktreePath)
@@ -342,6 +1050,68 @@ func (m *MockChecker) CreateWorktree(branchName, worktreePath string) error {
 	return nil
 }
 
+// AddWorktree mocks checking out an existing branch in a new worktree
+func (m *MockChecker) AddWorktree(branchName, worktreePath string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock add failure for worktree %s", worktreePath)
+	}
+	m.Worktrees[worktreePath] = true
+	m.Branches[worktreePath] = branchName
+	delete(m.StatusErrors, worktreePath)
+	return nil
+}
+
+// AddDetachedWorktree mocks checking out a commit in a new worktree, creating
+// its directory so commands can run there
+func (m *MockChecker) AddDetachedWorktree(commit, worktreePath string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock add failure for worktree %s", worktreePath)
+	}
+	if err := os.MkdirAll(worktreePath, 0755); err != nil {
+		return err
+	}
+	m.Worktrees[worktreePath] = true
+	m.Detached = append(m.Detached, commit)
+	return nil
+}
+
+// PopulateWorktree records that a worktree's checkout was completed
+func (m *MockChecker) PopulateWorktree(ctx context.Context, worktreePath string, progress func(step string)) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock populate failure for worktree %s", worktreePath)
+	}
+	m.Populated[worktreePath] = true
+	return nil
+}
+
+// InstallHooks records the hook install step run in a worktree
+func (m *MockChecker) InstallHooks(ctx context.Context, worktreePath, command string) (HookManager, error) {
+	m.HookInstalls[worktreePath] = command
+	if m.FailHooks {
+		return HookManager{}, fmt.Errorf("mock hook install failure for worktree %s", worktreePath)
+	}
+	if command == "" {
+		return HookManager{}, nil
+	}
+	return HookManager{Name: "custom", Command: command}, nil
+}
+
+// BranchDiff returns the mocked patch of a branch
+func (m *MockChecker) BranchDiff(branchName string) (string, error) {
+	if m.ShouldFail[branchName] {
+		return "", fmt.Errorf("mock diff failure for branch %s", branchName)
+	}
+	return m.Diffs[branchName], nil
+}
+
+// BranchLog returns the mocked log of a branch
+func (m *MockChecker) BranchLog(branchName string) (string, error) {
+	if m.ShouldFail[branchName] {
+		return "", fmt.Errorf("mock log failure for branch %s", branchName)
+	}
+	return m.Logs[branchName], nil
+}
+
 // RemoveWorktree mocks worktree removal
 func (m *MockChecker) RemoveWorktree(worktreePath string) error {
 	if m.Delay > 0 {
@@ -385,6 +1155,11 @@ func (m *MockChecker) SetStatus(worktreePath string, status types.GitStatus) {
 	m.Statuses[worktreePath] = status
 }
 
+// SetStatusError makes GetStatus fail for a worktree
+func (m *MockChecker) SetStatusError(worktreePath string, err error) {
+	m.StatusErrors[worktreePath] = err
+}
+
 // SetWorktreeExists sets whether a worktree exists
 func (m *MockChecker) SetWorktreeExists(worktreePath string, exists bool) {
 	if exists {
@@ -404,13 +1179,69 @@ func (m *MockChecker) SetDelay(delay time.Duration) {
 	m.Delay = delay
 }
 
-// BranchExists returns whether a branch exists (always false for mock)
+// BranchExists returns whether a branch is in Existing
 func (m *MockChecker) BranchExists(branchName string) bool {
 	if m.Delay > 0 {
 		time.Sleep(m.Delay)
 	}
-	// Mock implementation - can be configured if needed
-	return false
+	return m.Existing[branchName]
+}
+
+// ListBranches returns the distinct branches checked out in mocked worktrees
+func (m *MockChecker) ListBranches() ([]string, error) {
+	if m.Delay > 0 {
+		time.Sleep(m.Delay)
+	}
+	seen := make(map[string]bool)
+	var branches []string
+	for _, branch := range m.Branches {
+		if !seen[branch] {
+			seen[branch] = true
+			branches = append(branches, branch)
+		}
+	}
+	sort.Strings(branches)
+	return branches, nil
+}
+
+// DeleteBranch records the deleted branch
+func (m *MockChecker) DeleteBranch(branchName string) error {
+	if m.ShouldFail[branchName] {
+		return fmt.Errorf("mock delete failure for branch %s", branchName)
+	}
+	m.Deleted = append(m.Deleted, branchName)
+	return nil
+}
+
+// BranchMerged returns the mocked merge state
+func (m *MockChecker) BranchMerged(branchName string) (bool, error) {
+	if m.ShouldFail[branchName] {
+		return false, fmt.Errorf("mock merge check failure for branch %s", branchName)
+	}
+	return !m.Unmerged[branchName], nil
+}
+
+// UnmergedCommits returns the mocked commits a branch has over the base branch
+func (m *MockChecker) UnmergedCommits(branchName string) ([]types.CommitOutput, error) {
+	if m.ShouldFail[branchName] {
+		return nil, fmt.Errorf("mock log failure for branch %s", branchName)
+	}
+	return m.Ahead[branchName], nil
+}
+
+// RenameBranch records the branch's new name and renames it in the
+// worktrees that have it checked out
+func (m *MockChecker) RenameBranch(branchName, newName string) error {
+	if m.ShouldFail[branchName] || m.ShouldFail[newName] {
+		return fmt.Errorf("mock rename failure for branch %s", branchName)
+	}
+	m.Renamed[branchName] = newName
+	for path, branch := range m.Branches {
+		if branch == branchName {
+			m.Branches[path] = newName
+		}
+	}
+	return nil
 }
 
 // CommitChanges mocks committing changes
@@ -424,6 +1255,140 @@ func (m *MockChecker) CommitChanges(worktreePath, message string) error {
 	return nil
 }
 
+// CommitStaged mocks committing staged changes
+func (m *MockChecker) CommitStaged(worktreePath, message string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock commit failure for worktree %s", worktreePath)
+	}
+	return nil
+}
+
+// ApplyToIndex mocks applying a patch to the index
+func (m *MockChecker) ApplyToIndex(worktreePath, patch string, reverse bool) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock apply failure for worktree %s", worktreePath)
+	}
+	return nil
+}
+
+// StageFile mocks staging a file
+func (m *MockChecker) StageFile(worktreePath, path string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock stage failure for worktree %s", worktreePath)
+	}
+	return nil
+}
+
+// UnstageFile mocks unstaging a file
+func (m *MockChecker) UnstageFile(worktreePath, path string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock unstage failure for worktree %s", worktreePath)
+	}
+	return nil
+}
+
+// HideLocalChanges records the files whose changes were hidden
+func (m *MockChecker) HideLocalChanges(worktreePath string, paths []string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock hide failure for worktree %s", worktreePath)
+	}
+	m.Hidden[worktreePath] = append(m.Hidden[worktreePath], paths...)
+	return nil
+}
+
+// Rebase mocks rebasing a worktree's branch, stopping on the conflicts
+// set in RebaseFails
+func (m *MockChecker) Rebase(worktreePath, onto string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock rebase failure for worktree %s", worktreePath)
+	}
+	if files, ok := m.RebaseFails[worktreePath]; ok {
+		return &RebaseConflictError{Onto: onto, Files: files}
+	}
+	m.Rebased[worktreePath] = onto
+	// The rebased branch points at a new commit
+	branch, _ := m.GetCurrentBranch(worktreePath)
+	m.Commits[branch] = fmt.Sprintf("%s-rebased-onto-%s", branch, onto)
+	return nil
+}
+
+// AbortRebase mocks aborting a rebase
+func (m *MockChecker) AbortRebase(worktreePath string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock abort failure for worktree %s", worktreePath)
+	}
+	m.Aborted = append(m.Aborted, worktreePath)
+	return nil
+}
+
+// MergeInto mocks merging into a worktree's branch, stopping on the
+// conflicts set in MergeFails
+func (m *MockChecker) MergeInto(worktreePath, from string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock merge failure for worktree %s", worktreePath)
+	}
+	if files, ok := m.MergeFails[worktreePath]; ok {
+		return &MergeConflictError{From: from, Files: files}
+	}
+	m.MergedFrom[worktreePath] = from
+	branch, _ := m.GetCurrentBranch(worktreePath)
+	m.Commits[branch] = fmt.Sprintf("%s-merged-with-%s", branch, from)
+	return nil
+}
+
+// AbortMerge mocks aborting a merge
+func (m *MockChecker) AbortMerge(worktreePath string) error {
+	if m.ShouldFail[worktreePath] {
+		return fmt.Errorf("mock abort failure for worktree %s", worktreePath)
+	}
+	m.Aborted = append(m.Aborted, worktreePath)
+	return nil
+}
+
+// Fetch mocks fetching a remote
+func (m *MockChecker) Fetch(remote string) error {
+	if m.ShouldFail[remote] {
+		return fmt.Errorf("mock fetch failure for %s", remote)
+	}
+	m.Fetched = append(m.Fetched, remote)
+	return nil
+}
+
+// PredictConflicts returns the mocked conflicts of merging a branch
+func (m *MockChecker) PredictConflicts(branch, into string) ([]string, error) {
+	m.Predictions++
+	if m.ShouldFail[branch] {
+		return nil, fmt.Errorf("mock merge-tree failure for %s", branch)
+	}
+	return m.Predicted[branch], nil
+}
+
+// SnapshotWorktree returns the mocked snapshot of a worktree
+func (m *MockChecker) SnapshotWorktree(worktreePath string) (string, error) {
+	if m.ShouldFail[worktreePath] {
+		return "", fmt.Errorf("mock snapshot failure for worktree %s", worktreePath)
+	}
+	if tree, ok := m.Snapshots[worktreePath]; ok {
+		return tree, nil
+	}
+	return "tree-" + filepath.Base(worktreePath), nil
+}
+
+// PinSnapshot mocks pinning a snapshot
+func (m *MockChecker) PinSnapshot(tree, ref string) error {
+	m.Pinned[ref] = tree
+	return nil
+}
+
+// DeleteRef mocks deleting a ref
+func (m *MockChecker) DeleteRef(ref string) error {
+	if _, ok := m.Pinned[ref]; !ok {
+		return fmt.Errorf("mock ref %s not found", ref)
+	}
+	delete(m.Pinned, ref)
+	return nil
+}
+
 // CheckoutBranch mocks checking out a branch
 func (m *MockChecker) CheckoutBranch(branchName string) error {
 	if m.Delay > 0 {
@@ -432,3 +1397,29 @@ func (m *MockChecker) CheckoutBranch(branchName string) error {
 	// Mock implementation - always succeeds unless configured otherwise
 	return nil
 }
+
+// GetCurrentBranch returns the mocked branch, defaulting to the worktree directory name
+func (m *MockChecker) GetCurrentBranch(worktreePath string) (string, error) {
+	if m.Delay > 0 {
+		time.Sleep(m.Delay)
+	}
+	if m.ShouldFail[worktreePath] {
+		return "", fmt.Errorf("mock branch lookup failure for worktree %s", worktreePath)
+	}
+	if branch, ok := m.Branches[worktreePath]; ok {
+		return branch, nil
+	}
+	return filepath.Base(worktreePath), nil
+}
+
+// ResolveCommit returns the mocked commit of a revision, set in Commits,
+// defaulting to the revision itself
+func (m *MockChecker) ResolveCommit(rev string) (string, error) {
+	if m.ShouldFail[rev] {
+		return "", fmt.Errorf("mock resolve failure for %s", rev)
+	}
+	if commit, ok := m.Commits[rev]; ok {
+		return commit, nil
+	}
+	return rev, nil
+}
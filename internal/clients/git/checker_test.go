@@ -0,0 +1,855 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestRealChecker_GetStatus_Errors(t *testing.T) {
+	// Skip if git is not available
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	checker := NewRealChecker("main")
+
+	tests := []struct {
+		name string
+		path string
+		want types.GitErrorKind
+	}{
+		{
+			name: "missing worktree",
+			path: filepath.Join(t.TempDir(), "does-not-exist"),
+			want: types.GitErrorMissingWorktree,
+		},
+		{
+			name: "not a repository",
+			path: t.TempDir(),
+			want: types.GitErrorNotRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Keep git from discovering a repository above the temp dir
+			t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(tt.path))
+
+			_, err := checker.GetStatus(tt.path)
+			if err == nil {
+				t.Fatal("GetStatus() error = nil, want error")
+			}
+
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("GetStatus() error = %T, want *StatusError", err)
+			}
+			if statusErr.Kind != tt.want {
+				t.Errorf("StatusError.Kind = %q, want %q", statusErr.Kind, tt.want)
+			}
+		})
+	}
+}
+
+func TestMockChecker_GetStatusError(t *testing.T) {
+	mock := NewMockChecker()
+
+	if _, err := mock.GetStatus("/tmp/wt"); err != nil {
+		t.Errorf("GetStatus() error = %v, want nil (default)", err)
+	}
+
+	mock.SetStatusError("/tmp/wt", errors.New("boom"))
+	if _, err := mock.GetStatus("/tmp/wt"); err == nil {
+		t.Error("GetStatus() error = nil, want error after SetStatusError")
+	}
+}
+
+func TestRealChecker_Staging(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return string(output)
+	}
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	writeFile := func(content []string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "file.txt"), []byte(strings.Join(content, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	writeFile(lines)
+	git("add", "file.txt")
+	git("commit", "-q", "-m", "initial")
+
+	// Two changes far enough apart to be separate hunks
+	changed := append([]string(nil), lines...)
+	changed[0] = "first"
+	changed[19] = "last"
+	writeFile(changed)
+
+	diff := git("diff", "--no-color")
+	firstHunk := diff[:strings.Index(diff, "@@ -")]
+	rest := diff[len(firstHunk):]
+	second := strings.Index(rest[1:], "@@ -") + 1
+	firstHunk += rest[:second]
+
+	checker := NewRealChecker("main")
+	if err := checker.ApplyToIndex(repo, firstHunk, false); err != nil {
+		t.Fatalf("ApplyToIndex() error = %v", err)
+	}
+	if staged := git("diff", "--cached", "--no-color"); !strings.Contains(staged, "+first") || strings.Contains(staged, "+last") {
+		t.Errorf("only the first hunk should be staged, got:\n%s", staged)
+	}
+
+	if err := checker.ApplyToIndex(repo, firstHunk, true); err != nil {
+		t.Fatalf("ApplyToIndex(reverse) error = %v", err)
+	}
+	if staged := git("diff", "--cached"); staged != "" {
+		t.Errorf("nothing should be staged after reversing, got:\n%s", staged)
+	}
+
+	if err := checker.StageFile(repo, "file.txt"); err != nil {
+		t.Fatalf("StageFile() error = %v", err)
+	}
+	if unstaged := git("diff"); unstaged != "" {
+		t.Errorf("the whole file should be staged, unstaged:\n%s", unstaged)
+	}
+
+	if err := checker.UnstageFile(repo, "file.txt"); err != nil {
+		t.Fatalf("UnstageFile() error = %v", err)
+	}
+	if staged := git("diff", "--cached"); staged != "" {
+		t.Errorf("nothing should be staged after UnstageFile, got:\n%s", staged)
+	}
+}
+
+func TestRealChecker_CommitChanges_SkipsCwtFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return string(output)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(repo, path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "user.name", "test")
+	git("config", "user.email", "test@example.com")
+	write("file.txt", "one\n")
+	git("add", "file.txt")
+	git("commit", "-q", "-m", "initial")
+
+	write("file.txt", "two\n")
+	write(SettingsFile, `{"hooks": {"Stop": [{"command": "cwt __hook abc stop"}]}}`)
+	if got := CwtWrittenFiles(repo); len(got) != 1 || got[0] != SettingsFile {
+		t.Fatalf("CwtWrittenFiles() = %v, want [%s]", got, SettingsFile)
+	}
+
+	checker := NewRealChecker("main")
+	if err := checker.CommitChanges(repo, "work"); err != nil {
+		t.Fatalf("CommitChanges() error = %v", err)
+	}
+	if committed := git("show", "--name-only", "--format=", "HEAD"); strings.TrimSpace(committed) != "file.txt" {
+		t.Errorf("committed files = %q, want only file.txt", committed)
+	}
+
+	// Nor do the shared context files
+	write(ContextDir+"/docs/architecture.md", "# Architecture\n")
+	write("file.txt", "three\n")
+	if err := checker.CommitChanges(repo, "context"); err != nil {
+		t.Fatalf("CommitChanges() error = %v", err)
+	}
+	if committed := git("show", "--name-only", "--format=", "HEAD"); strings.TrimSpace(committed) != "file.txt" {
+		t.Errorf("committed files = %q, want only file.txt", committed)
+	}
+	os.RemoveAll(filepath.Join(repo, ContextDir))
+
+	// Settings without cwt's hooks are the user's own and get committed
+	write(SettingsFile, `{"permissions": {}}`)
+	write("file.txt", "four\n")
+	if got := CwtWrittenFiles(repo); len(got) != 0 {
+		t.Fatalf("CwtWrittenFiles() = %v, want none", got)
+	}
+	if err := checker.CommitChanges(repo, "settings"); err != nil {
+		t.Fatalf("CommitChanges() error = %v", err)
+	}
+	if committed := git("show", "--name-only", "--format=", "HEAD"); !strings.Contains(committed, SettingsFile) {
+		t.Errorf("committed files = %q, want %s", committed, SettingsFile)
+	}
+}
+
+func TestRealChecker_Rebase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	worktree := filepath.Join(t.TempDir(), "work")
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(dir, "add", file)
+		run(dir, "commit", "-q", "-m", file+": "+content)
+	}
+
+	run(repo, "init", "-q", "-b", "main")
+	run(repo, "config", "user.name", "test")
+	run(repo, "config", "user.email", "test@example.com")
+	commit(repo, "shared.txt", "base")
+	run(repo, "worktree", "add", "-q", "-b", "work", worktree)
+	commit(worktree, "work.txt", "work")
+	commit(repo, "main.txt", "main")
+
+	checker := NewRealChecker("main")
+	if err := checker.Rebase(worktree, "main"); err != nil {
+		t.Fatalf("Rebase() error = %v", err)
+	}
+	if behind := run(worktree, "rev-list", "--count", "HEAD..main"); behind != "0" {
+		t.Errorf("%s commits behind main after rebasing, want 0", behind)
+	}
+
+	// Both sides change the same line
+	commit(worktree, "shared.txt", "work")
+	commit(repo, "shared.txt", "main")
+	before := run(worktree, "rev-parse", "HEAD")
+
+	err := checker.Rebase(worktree, "main")
+	var conflictErr *RebaseConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Rebase() = %v, want a RebaseConflictError", err)
+	}
+	if len(conflictErr.Files) != 1 || conflictErr.Files[0] != "shared.txt" || conflictErr.Onto != "main" {
+		t.Errorf("RebaseConflictError = %+v, want shared.txt conflicted onto main", conflictErr)
+	}
+
+	if err := checker.AbortRebase(worktree); err != nil {
+		t.Fatalf("AbortRebase() error = %v", err)
+	}
+	if after := run(worktree, "rev-parse", "HEAD"); after != before {
+		t.Errorf("HEAD = %s after aborting, want %s", after, before)
+	}
+	if err := checker.AbortRebase(worktree); err == nil {
+		t.Error("AbortRebase() without a rebase in progress should fail")
+	}
+}
+
+func TestRealChecker_MergeInto(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	worktree := filepath.Join(t.TempDir(), "work")
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(dir, "add", file)
+		run(dir, "commit", "-q", "-m", file+": "+content)
+	}
+
+	run(repo, "init", "-q", "-b", "main")
+	run(repo, "config", "user.name", "test")
+	run(repo, "config", "user.email", "test@example.com")
+	commit(repo, "shared.txt", "base")
+	run(repo, "worktree", "add", "-q", "-b", "work", worktree)
+	commit(worktree, "work.txt", "work")
+	commit(repo, "main.txt", "main")
+	workCommit := run(worktree, "rev-parse", "HEAD")
+
+	checker := NewRealChecker("main")
+	if err := checker.MergeInto(worktree, "main"); err != nil {
+		t.Fatalf("MergeInto() error = %v", err)
+	}
+	if behind := run(worktree, "rev-list", "--count", "HEAD..main"); behind != "0" {
+		t.Errorf("%s commits behind main after merging, want 0", behind)
+	}
+	// Merging keeps the branch's own commits
+	run(worktree, "merge-base", "--is-ancestor", workCommit, "HEAD")
+
+	// Both sides change the same line
+	commit(worktree, "shared.txt", "work")
+	commit(repo, "shared.txt", "main")
+	before := run(worktree, "rev-parse", "HEAD")
+
+	err := checker.MergeInto(worktree, "main")
+	var conflictErr *MergeConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("MergeInto() = %v, want a MergeConflictError", err)
+	}
+	if len(conflictErr.Files) != 1 || conflictErr.Files[0] != "shared.txt" || conflictErr.From != "main" {
+		t.Errorf("MergeConflictError = %+v, want shared.txt conflicted merging main", conflictErr)
+	}
+
+	if err := checker.AbortMerge(worktree); err != nil {
+		t.Fatalf("AbortMerge() error = %v", err)
+	}
+	if after := run(worktree, "rev-parse", "HEAD"); after != before {
+		t.Errorf("HEAD = %s after aborting, want %s", after, before)
+	}
+	if status := run(worktree, "status", "--porcelain"); status != "" {
+		t.Errorf("worktree not clean after aborting:\n%s", status)
+	}
+}
+
+func TestRealChecker_PredictConflicts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	commit := func(file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", file)
+		run("commit", "-q", "-m", file+": "+content)
+	}
+
+	run("init", "-q", "-b", "main")
+	commit("shared.txt", "base")
+	run("checkout", "-q", "-b", "work")
+	commit("work.txt", "work")
+	run("checkout", "-q", "main")
+	commit("main.txt", "main")
+
+	checker := NewRealChecker("main")
+	files, err := checker.PredictConflicts("work", "main")
+	if errors.Is(err, ErrMergeTreeUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil || len(files) != 0 {
+		t.Fatalf("PredictConflicts() = %v, %v; want a clean merge", files, err)
+	}
+
+	// Both sides change the same line
+	run("checkout", "-q", "work")
+	commit("shared.txt", "work")
+	run("checkout", "-q", "main")
+	commit("shared.txt", "main")
+
+	files, err = checker.PredictConflicts("work", "main")
+	if err != nil || len(files) != 1 || files[0] != "shared.txt" {
+		t.Errorf("PredictConflicts() = %v, %v; want shared.txt", files, err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".git", "MERGE_HEAD")); err == nil {
+		t.Error("predicting conflicts started a merge")
+	}
+}
+
+func TestRealChecker_SnapshotWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	write("tracked.txt", "one")
+	run("add", "tracked.txt")
+	run("commit", "-q", "-m", "initial")
+
+	checker := NewRealChecker("main")
+	clean, err := checker.SnapshotWorktree(repo)
+	if err != nil {
+		t.Fatalf("SnapshotWorktree() error = %v", err)
+	}
+	if head := run("rev-parse", "HEAD^{tree}"); clean != head {
+		t.Errorf("snapshot of a clean worktree = %s, want HEAD's tree %s", clean, head)
+	}
+
+	write("tracked.txt", "two")
+	write("untracked.txt", "new")
+	changed, err := checker.SnapshotWorktree(repo)
+	if err != nil || changed == clean {
+		t.Fatalf("SnapshotWorktree() = %s, %v; want a new tree for the changes", changed, err)
+	}
+	if files := run("ls-tree", "--name-only", changed); files != "tracked.txt\nuntracked.txt" {
+		t.Errorf("snapshot holds %q, want the untracked file too", files)
+	}
+	if status := run("status", "--porcelain"); status != "M tracked.txt\n?? untracked.txt" {
+		t.Errorf("status after snapshotting = %q, want the index untouched", status)
+	}
+	if again, _ := checker.SnapshotWorktree(repo); again != changed {
+		t.Errorf("snapshot of the same files = %s, want %s", again, changed)
+	}
+
+	if err := checker.PinSnapshot(changed, "refs/cwt/reviewed/test"); err != nil {
+		t.Fatalf("PinSnapshot() error = %v", err)
+	}
+	if pinned := run("rev-parse", "refs/cwt/reviewed/test^{tree}"); pinned != changed {
+		t.Errorf("pinned tree = %s, want %s", pinned, changed)
+	}
+	if err := checker.DeleteRef("refs/cwt/reviewed/test"); err != nil {
+		t.Fatalf("DeleteRef() error = %v", err)
+	}
+	if refs := run("for-each-ref", "refs/cwt"); refs != "" {
+		t.Errorf("refs left after DeleteRef: %s", refs)
+	}
+}
+
+func TestRealChecker_AddDetachedWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	checker := NewRealChecker("main")
+	commit, err := checker.ResolveCommit("main")
+	if err != nil || commit != git("rev-parse", "HEAD") {
+		t.Fatalf("ResolveCommit() = %q, %v; want the head of main", commit, err)
+	}
+	if _, err := checker.ResolveCommit("missing"); err == nil {
+		t.Error("ResolveCommit() of a missing branch should fail")
+	}
+
+	worktree := filepath.Join(t.TempDir(), "base")
+	if err := checker.AddDetachedWorktree(commit, worktree); err != nil {
+		t.Fatalf("AddDetachedWorktree() error = %v", err)
+	}
+	if branch, _ := checker.GetCurrentBranch(worktree); branch != "HEAD" {
+		t.Errorf("branch = %q, want HEAD detached", branch)
+	}
+	if err := checker.RemoveWorktree(worktree); err != nil {
+		t.Errorf("RemoveWorktree() error = %v", err)
+	}
+}
+
+func TestRealChecker_BranchMerged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+	git("branch", "merged")
+	git("checkout", "-q", "-b", "ahead")
+	git("commit", "-q", "--allow-empty", "-m", "work")
+	git("checkout", "-q", "main")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	checker := NewRealChecker("main")
+	for branch, want := range map[string]bool{"merged": true, "ahead": false} {
+		merged, err := checker.BranchMerged(branch)
+		if err != nil || merged != want {
+			t.Errorf("BranchMerged(%s) = %v, %v; want %v", branch, merged, err, want)
+		}
+	}
+	if _, err := checker.BranchMerged("missing"); err == nil {
+		t.Error("Expected error for a branch that doesn't exist")
+	}
+
+	commits, err := checker.UnmergedCommits("ahead")
+	if err != nil || len(commits) != 1 || commits[0].Subject != "work" || len(commits[0].Hash) != 40 {
+		t.Errorf("UnmergedCommits(ahead) = %+v, %v; want the one commit", commits, err)
+	}
+	if commits, err := checker.UnmergedCommits("merged"); err != nil || len(commits) != 0 {
+		t.Errorf("UnmergedCommits(merged) = %+v, %v; want none", commits, err)
+	}
+}
+
+func TestRealChecker_AddWorktree_DeletedByHand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+	worktree := filepath.Join(repo, ".cwt", "worktrees", "feature")
+	git("worktree", "add", "-q", "-b", "feature", worktree, "main")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	// git still has the worktree registered, so the branch counts as checked out
+	if err := os.RemoveAll(worktree); err != nil {
+		t.Fatal(err)
+	}
+	checker := NewRealChecker("main")
+	if _, err := checker.GetStatus(worktree); err == nil {
+		t.Fatal("GetStatus() of a deleted worktree should fail")
+	}
+	if err := checker.AddWorktree("feature", worktree); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	if status, err := checker.GetStatus(worktree); err != nil || status.Branch != "feature" {
+		t.Errorf("GetStatus() = %+v, %v; want the worktree back on feature", status, err)
+	}
+}
+
+func TestRealChecker_GetStatus_Conflicts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil && args[0] != "merge" {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("edited.txt", "base")
+	write("removed.txt", "base")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	git("checkout", "-q", "-b", "feature")
+	write("edited.txt", "feature")
+	write("added.txt", "feature")
+	git("rm", "-q", "removed.txt")
+	git("add", ".")
+	git("commit", "-q", "-m", "feature")
+
+	git("checkout", "-q", "main")
+	write("edited.txt", "main")
+	write("added.txt", "main")
+	write("removed.txt", "main")
+	git("add", ".")
+	git("commit", "-q", "-m", "main")
+	git("merge", "-q", "feature") // Stops on the conflicts
+
+	status, err := NewRealChecker("main").GetStatus(repo)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	want := map[string]types.ConflictKind{
+		"edited.txt":  types.ConflictBothModified,
+		"added.txt":   types.ConflictBothAdded,
+		"removed.txt": types.ConflictDeletedByThem,
+	}
+	if !status.HasConflicts() || len(status.ConflictedFiles) != len(want) {
+		t.Fatalf("ConflictedFiles = %+v, want %v", status.ConflictedFiles, want)
+	}
+	for _, file := range status.ConflictedFiles {
+		if want[file.Path] != file.Kind {
+			t.Errorf("%s conflict = %s, want %s", file.Path, file.Kind, want[file.Path])
+		}
+	}
+	if len(status.ModifiedFiles)+len(status.AddedFiles)+len(status.DeletedFiles) != 0 {
+		t.Errorf("status = %+v, want conflicted files listed only as conflicts", status)
+	}
+}
+
+func TestRealChecker_GetStatus_BranchAndStaging(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("staged.txt", "base")
+	write("both.txt", "base")
+	write("unstaged.txt", "base")
+	write("old name.txt", "base")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	git("checkout", "-q", "-b", "feature")
+	git("branch", "-q", "--set-upstream-to=main")
+	write("feature.txt", "feature")
+	git("add", ".")
+	git("commit", "-q", "-m", "feature one")
+	git("commit", "-q", "--allow-empty", "-m", "feature two")
+	git("checkout", "-q", "main")
+	git("commit", "-q", "--allow-empty", "-m", "main moved on")
+	git("checkout", "-q", "feature")
+
+	// A clean worktree still reports where its branch stands
+	checker := NewRealChecker("main")
+	status, err := checker.GetStatus(repo)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.HasChanges || status.Branch != "feature" || status.CommitCount != 2 || status.BehindCount != 1 {
+		t.Errorf("status = %+v, want a clean feature branch 2 ahead of and 1 behind main", status)
+	}
+	if status.Upstream != "main" || status.UpstreamAhead != 2 || status.UpstreamBehind != 1 {
+		t.Errorf("upstream = %s ↑%d ↓%d, want main ↑2 ↓1", status.Upstream, status.UpstreamAhead, status.UpstreamBehind)
+	}
+
+	write("staged.txt", "staged")
+	write("both.txt", "staged")
+	git("add", "staged.txt", "both.txt")
+	write("both.txt", "then edited")
+	write("unstaged.txt", "edited")
+	git("mv", "old name.txt", "new name.txt")
+	write("untracked.txt", "new")
+	if err := os.MkdirAll(filepath.Join(repo, ".claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write(".claude/settings.json", "{}")
+
+	status, err = checker.GetStatus(repo)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	check := func(name string, got, want []string) {
+		t.Helper()
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	check("StagedFiles", status.StagedFiles, []string{"both.txt", "new name.txt", "staged.txt"})
+	check("UnstagedFiles", status.UnstagedFiles, []string{"both.txt", "unstaged.txt"})
+	check("ModifiedFiles", status.ModifiedFiles, []string{"both.txt", "staged.txt", "unstaged.txt"})
+	check("AddedFiles", status.AddedFiles, []string{"new name.txt"})
+	check("UntrackedFiles", status.UntrackedFiles, []string{"untracked.txt"})
+
+	git("checkout", "-q", "--detach")
+	if status, err := checker.GetStatus(repo); err != nil || status.Branch != "" {
+		t.Errorf("GetStatus() = %q, %v, want no branch on a detached HEAD", status.Branch, err)
+	}
+}
+
+// statusTestRepo creates a repository on main with worktrees on their own
+// branches, each with a commit and uncommitted changes, returning the
+// worktree paths
+func statusTestRepo(tb testing.TB, worktrees int) []string {
+	tb.Helper()
+	repo := tb.TempDir()
+	git := func(dir string, args ...string) {
+		tb.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			tb.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(path, content string) {
+		tb.Helper()
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	git(repo, "init", "-q", "-b", "main")
+	for i := 0; i < 20; i++ {
+		write(filepath.Join(repo, fmt.Sprintf("file%d.txt", i)), "base")
+	}
+	git(repo, "add", ".")
+	git(repo, "commit", "-q", "-m", "initial")
+
+	var paths []string
+	for i := 0; i < worktrees; i++ {
+		path := filepath.Join(tb.TempDir(), fmt.Sprintf("session%d", i))
+		git(repo, "worktree", "add", "-q", "-b", fmt.Sprintf("session%d", i), path, "main")
+		write(filepath.Join(path, "file0.txt"), "committed")
+		git(path, "commit", "-q", "-am", "work")
+		write(filepath.Join(path, "file1.txt"), "edited")
+		write(filepath.Join(path, "new.txt"), "untracked")
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestRealChecker_GetStatus_GitInvocations(t *testing.T) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not found in PATH")
+	}
+	worktree := statusTestRepo(t, 1)[0]
+
+	// Count the git commands a status takes with a git that logs each call
+	bin := t.TempDir()
+	calls := filepath.Join(t.TempDir(), "calls")
+	shim := fmt.Sprintf("#!/bin/sh\necho \"$1\" >> %q\nexec %q \"$@\"\n", calls, gitPath)
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte(shim), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	status, err := NewRealChecker("main").GetStatus(worktree)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.Branch != "session0" || status.CommitCount != 1 || len(status.UnstagedFiles) != 1 || len(status.UntrackedFiles) != 1 {
+		t.Errorf("status = %+v, want session0 a commit ahead with an edit and an untracked file", status)
+	}
+
+	logged, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(logged)); strings.Join(got, " ") != "status rev-list" {
+		t.Errorf("GetStatus() ran git %q, want one status and one rev-list", got)
+	}
+}
+
+// BenchmarkRealChecker_GetStatus measures reading the status of every
+// session in a project with many of them
+func BenchmarkRealChecker_GetStatus(b *testing.B) {
+	if _, err := exec.LookPath("git"); err != nil {
+		b.Skip("git not found in PATH")
+	}
+
+	for _, sessions := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("sessions=%d", sessions), func(b *testing.B) {
+			worktrees := statusTestRepo(b, sessions)
+			checker := NewRealChecker("main")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, worktree := range worktrees {
+					if _, err := checker.GetStatus(worktree); err != nil {
+						b.Fatalf("GetStatus() error = %v", err)
+					}
+				}
+			}
+		})
+	}
+}
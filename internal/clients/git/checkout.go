@@ -0,0 +1,135 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// UsesSubmodules reports whether a worktree's checkout declares submodules
+func UsesSubmodules(worktreePath string) bool {
+	_, err := os.Stat(filepath.Join(worktreePath, ".gitmodules"))
+	return err == nil
+}
+
+// UsesLFS reports whether a worktree's checkout tracks files with Git LFS
+func UsesLFS(worktreePath string) bool {
+	data, err := os.ReadFile(filepath.Join(worktreePath, ".gitattributes"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.Contains(line, "filter=lfs") {
+			return true
+		}
+	}
+	return false
+}
+
+// PopulateWorktree completes the checkout of a new worktree: git worktree add
+// leaves submodules empty and, when the LFS smudge filter doesn't run, LFS
+// files as pointers. progress, if set, is called with each step and with the
+// lines git prints while it runs. Cancelling ctx stops git.
+func (r *RealChecker) PopulateWorktree(ctx context.Context, worktreePath string, progress func(step string)) error {
+	report := func(step string) {
+		logger.Info(step, "worktree", worktreePath)
+		if progress != nil {
+			progress(step)
+		}
+	}
+
+	if UsesSubmodules(worktreePath) {
+		report("Initializing submodules")
+		if err := runGitStreaming(ctx, worktreePath, progress, "submodule", "update", "--init", "--recursive", "--progress"); err != nil {
+			return fmt.Errorf("failed to initialize submodules: %w", err)
+		}
+	}
+
+	if UsesLFS(worktreePath) {
+		if err := exec.Command("git", "lfs", "version").Run(); err != nil {
+			return fmt.Errorf("the repository uses Git LFS but git-lfs is not installed; install it from https://git-lfs.com and run 'git lfs install'")
+		}
+
+		report("Fetching LFS objects")
+		if err := runGitStreaming(ctx, worktreePath, progress, "lfs", "fetch"); err != nil {
+			return fmt.Errorf("failed to fetch LFS objects: %w", err)
+		}
+		report("Checking out LFS files")
+		if err := runGitStreaming(ctx, worktreePath, progress, "lfs", "checkout"); err != nil {
+			return fmt.Errorf("failed to check out LFS files: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// progressTailLines is how much of a failed command's output its error keeps
+const progressTailLines = 20
+
+// progressWriter splits git's output into lines, passing each to progress.
+// Progress meters redraw their line with a carriage return, so that ends a
+// line too.
+type progressWriter struct {
+	progress func(string)
+	partial  []byte
+	tail     []string
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b != '\n' && b != '\r' {
+			w.partial = append(w.partial, b)
+			continue
+		}
+		w.flush()
+	}
+	return len(p), nil
+}
+
+// flush reports the line written so far, if any
+func (w *progressWriter) flush() {
+	line := strings.TrimSpace(string(w.partial))
+	w.partial = w.partial[:0]
+	if line == "" {
+		return
+	}
+	if w.progress != nil {
+		w.progress(line)
+	}
+	if len(w.tail) == progressTailLines {
+		w.tail = w.tail[1:]
+	}
+	w.tail = append(w.tail, line)
+}
+
+// runGitStreaming runs a git command in dir, passing each line it prints to
+// progress as it goes. The last lines are returned in the error when it
+// fails. Cancelling ctx kills git.
+func runGitStreaming(ctx context.Context, dir string, progress func(string), args ...string) error {
+	logger.Debug("running git", "dir", dir, "args", args)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	// The same writer for both makes exec call it from one goroutine
+	output := &progressWriter{progress: progress}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err := cmd.Run()
+	output.flush()
+	if err != nil {
+		logger.Debug("git failed", "args", args, "error", err, "output", strings.Join(output.tail, "\n"))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("git %s: %w\nOutput: %s", strings.Join(args, " "), err, strings.Join(output.tail, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUsesLFS(t *testing.T) {
+	tests := []struct {
+		attributes string
+		want       bool
+	}{
+		{"", false},
+		{"*.png binary\n", false},
+		{"# *.psd filter=lfs diff=lfs merge=lfs -text\n", false},
+		{"*.txt text\n*.psd filter=lfs diff=lfs merge=lfs -text\n", true},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if tt.attributes != "" {
+			os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(tt.attributes), 0644)
+		}
+		if got := UsesLFS(dir); got != tt.want {
+			t.Errorf("UsesLFS(%q) = %v, want %v", tt.attributes, got, tt.want)
+		}
+	}
+}
+
+func TestRealChecker_PopulateWorktree_Submodules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+	// Submodules cloned from local paths need the file protocol
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	root := t.TempDir()
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	library := filepath.Join(root, "library")
+	os.MkdirAll(library, 0755)
+	git(library, "init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(library, "lib.go"), []byte("package lib\n"), 0644)
+	git(library, "add", "lib.go")
+	git(library, "commit", "-q", "-m", "library")
+
+	repo := filepath.Join(root, "repo")
+	os.MkdirAll(repo, 0755)
+	git(repo, "init", "-q", "-b", "main")
+	git(repo, "submodule", "add", "-q", library, "vendor/library")
+	git(repo, "commit", "-q", "-m", "add submodule")
+
+	// git worktree add leaves the submodule directory empty
+	worktree := filepath.Join(root, "worktree")
+	git(repo, "worktree", "add", "-q", "-b", "session", worktree, "main")
+	libFile := filepath.Join(worktree, "vendor", "library", "lib.go")
+	if _, err := os.Stat(libFile); err == nil {
+		t.Fatal("expected the new worktree's submodule to be empty")
+	}
+
+	var steps []string
+	checker := NewRealChecker("main")
+	if err := checker.PopulateWorktree(context.Background(), worktree, func(step string) { steps = append(steps, step) }); err != nil {
+		t.Fatalf("PopulateWorktree() error = %v", err)
+	}
+
+	if _, err := os.Stat(libFile); err != nil {
+		t.Errorf("submodule not checked out: %v", err)
+	}
+	if len(steps) == 0 || steps[0] != "Initializing submodules" {
+		t.Errorf("progress steps = %v, want submodule initialization reported", steps)
+	}
+	if !strings.Contains(strings.Join(steps, "\n"), "vendor/library") {
+		t.Errorf("progress steps = %v, want git's output for the submodule", steps)
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var lines []string
+	w := &progressWriter{progress: func(line string) { lines = append(lines, line) }}
+
+	// Meters redraw with \r and may arrive split across writes
+	w.Write([]byte("Updating files:  50% (1/2)\rUpdating fi"))
+	w.Write([]byte("les: 100% (2/2), done.\n\nHEAD is now at abc123"))
+	w.flush()
+
+	want := []string{"Updating files:  50% (1/2)", "Updating files: 100% (2/2), done.", "HEAD is now at abc123"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if strings.Join(w.tail, "|") != strings.Join(want, "|") {
+		t.Errorf("tail = %q, want %q", w.tail, want)
+	}
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffMode selects what a session's diff compares
+type DiffMode string
+
+const (
+	// DiffUncommitted compares the working tree with the session branch's
+	// HEAD: the changes not committed yet, staged or not
+	DiffUncommitted DiffMode = "uncommitted"
+	// DiffSessionOnly compares the session branch's HEAD with its merge-base
+	// with the target: what the session's commits changed, ignoring anything
+	// the target gained since the session branched off
+	DiffSessionOnly DiffMode = "session"
+	// DiffIncludingBase compares the working tree with the tip of the target,
+	// so changes made on the target since the session branched off show up
+	// reversed
+	DiffIncludingBase DiffMode = "including-base"
+)
+
+// DiffModes lists the modes in the order a toggle cycles through them
+var DiffModes = []DiffMode{DiffIncludingBase, DiffSessionOnly, DiffUncommitted}
+
+// ParseDiffMode returns the mode with the given name
+func ParseDiffMode(name string) (DiffMode, error) {
+	for _, mode := range DiffModes {
+		if string(mode) == name {
+			return mode, nil
+		}
+	}
+	names := make([]string, len(DiffModes))
+	for i, mode := range DiffModes {
+		names[i] = string(mode)
+	}
+	return "", fmt.Errorf("unknown diff mode %q (want one of: %s)", name, strings.Join(names, ", "))
+}
+
+// Args returns the revisions to pass to git diff, run in the session's
+// worktree, to compare what the mode describes
+func (d DiffMode) Args(target string) []string {
+	switch d {
+	case DiffUncommitted:
+		return []string{"HEAD"}
+	case DiffSessionOnly:
+		return []string{target + "...HEAD"}
+	default:
+		return []string{target}
+	}
+}
+
+// Describe says what the mode compares, for diff headers
+func (d DiffMode) Describe(target string) string {
+	switch d {
+	case DiffUncommitted:
+		return "working tree vs session branch HEAD"
+	case DiffSessionOnly:
+		return fmt.Sprintf("session branch vs merge-base with %s", target)
+	default:
+		return fmt.Sprintf("working tree vs %s", target)
+	}
+}
+
+// Next returns the mode a toggle switches to
+func (d DiffMode) Next() DiffMode {
+	for i, mode := range DiffModes {
+		if mode == d {
+			return DiffModes[(i+1)%len(DiffModes)]
+		}
+	}
+	return DiffModes[0]
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffMode_Args(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return string(output)
+	}
+	commit := func(file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, file), []byte(file+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", file)
+		git("commit", "-q", "-m", file)
+	}
+
+	// main gains base.txt after the session branches off; the session
+	// commits committed.txt and leaves pending.txt uncommitted
+	git("init", "-q", "-b", "main")
+	commit("initial.txt")
+	git("checkout", "-q", "-b", "session")
+	commit("committed.txt")
+	git("checkout", "-q", "main")
+	commit("base.txt")
+	git("checkout", "-q", "session")
+	os.WriteFile(filepath.Join(repo, "committed.txt"), []byte("pending\n"), 0644)
+
+	tests := []struct {
+		mode DiffMode
+		want []string
+	}{
+		{DiffUncommitted, []string{"committed.txt"}},
+		{DiffSessionOnly, []string{"committed.txt"}},
+		{DiffIncludingBase, []string{"base.txt", "committed.txt"}},
+	}
+	for _, tt := range tests {
+		args := append([]string{"diff", "--name-only"}, tt.mode.Args("main")...)
+		files := strings.Fields(git(args...))
+		if !reflect.DeepEqual(files, tt.want) {
+			t.Errorf("%s diff changed %v, want %v", tt.mode, files, tt.want)
+		}
+	}
+
+	// Only the uncommitted diff shows the pending edit
+	if diff := git(append([]string{"diff"}, DiffSessionOnly.Args("main")...)...); strings.Contains(diff, "+pending") {
+		t.Error("the session-only diff should leave out uncommitted changes")
+	}
+	if diff := git(append([]string{"diff"}, DiffUncommitted.Args("main")...)...); !strings.Contains(diff, "+pending") {
+		t.Error("the uncommitted diff should show uncommitted changes")
+	}
+}
+
+func TestParseDiffMode(t *testing.T) {
+	for _, mode := range DiffModes {
+		if parsed, err := ParseDiffMode(string(mode)); err != nil || parsed != mode {
+			t.Errorf("ParseDiffMode(%q) = %q, %v", mode, parsed, err)
+		}
+		if mode.Next() == mode {
+			t.Errorf("%s.Next() should switch modes", mode)
+		}
+	}
+	if _, err := ParseDiffMode("staged"); err == nil {
+		t.Error("ParseDiffMode() should reject unknown modes")
+	}
+}
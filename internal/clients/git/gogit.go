@@ -0,0 +1,358 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// errNeedsGit is returned when go-git can't answer as git would, so the
+// question is put to git instead
+var errNeedsGit = errors.New("needs git")
+
+// GoGitChecker reads worktree status, branches and worktrees in process with
+// go-git instead of running git on every refresh. Everything else, and
+// anything go-git can't read the way git does, is left to the embedded
+// RealChecker.
+//
+// go-git doesn't read the global gitignore (core.excludesFile) and lists
+// the files of untracked directories one by one, where git shows the
+// directory.
+type GoGitChecker struct {
+	*RealChecker
+}
+
+// openRepository opens the repository path is in, linked worktrees included
+func openRepository(path string) (*gogit.Repository, error) {
+	return gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+}
+
+// GetStatus reads the git status of a worktree like RealChecker.GetStatus.
+// Worktrees with merge conflicts, whose kind go-git doesn't tell, are left
+// to git, as are commit counts across merge commits.
+func (g *GoGitChecker) GetStatus(worktreePath string) (types.GitStatus, error) {
+	if !g.pathExists(worktreePath) {
+		return types.GitStatus{}, &StatusError{Kind: types.GitErrorMissingWorktree, Path: worktreePath}
+	}
+
+	status, err := g.readStatus(worktreePath)
+	if err != nil {
+		logger.Debug("go-git status failed, running git", "worktree", worktreePath, "error", err)
+		return g.RealChecker.GetStatus(worktreePath)
+	}
+	return status, nil
+}
+
+func (g *GoGitChecker) readStatus(worktreePath string) (types.GitStatus, error) {
+	status := types.GitStatus{}
+
+	repo, err := openRepository(worktreePath)
+	if err != nil {
+		return status, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return status, err
+	}
+	files, err := worktree.Status()
+	if err != nil {
+		return status, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		file := files[path]
+		switch {
+		case file.Staging == gogit.Unmodified && file.Worktree == gogit.Unmodified:
+			continue
+		case file.Staging == gogit.UpdatedButUnmerged || file.Worktree == gogit.UpdatedButUnmerged:
+			return status, fmt.Errorf("%s is conflicted: %w", path, errNeedsGit)
+		case file.Worktree == gogit.Untracked:
+			if !isClaudePath(path) {
+				status.HasChanges = true
+				status.UntrackedFiles = append(status.UntrackedFiles, path)
+			}
+		default:
+			addStatusChange(&status, string([]byte{statusCode(file.Staging), statusCode(file.Worktree)}), path)
+		}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return status, err
+	}
+	if head.Name().IsBranch() {
+		status.Branch = head.Name().Short()
+	}
+
+	// Count commits ahead of and behind the base branch
+	if base, err := repo.ResolveRevision(plumbing.Revision(g.BaseBranch)); err == nil {
+		status.CommitCount, status.BehindCount, err = aheadBehind(repo, head.Hash(), *base)
+		if err != nil {
+			status.BehindCount, status.CommitCount, _ = revListCounts(worktreePath, g.BaseBranch+"...HEAD")
+		}
+	}
+
+	if status.Branch != "" {
+		g.readUpstream(repo, worktreePath, head, &status)
+	}
+	return status, nil
+}
+
+// readUpstream fills in the upstream of the branch checked out at head, and
+// how far the branch is from it when the upstream exists
+func (g *GoGitChecker) readUpstream(repo *gogit.Repository, worktreePath string, head *plumbing.Reference, status *types.GitStatus) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return
+	}
+	branch, ok := cfg.Branches[status.Branch]
+	if !ok || branch.Remote == "" || branch.Merge == "" {
+		return
+	}
+
+	upstreamRef := branch.Merge
+	status.Upstream = branch.Merge.Short()
+	if branch.Remote != "." {
+		upstreamRef = plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short())
+		status.Upstream = branch.Remote + "/" + branch.Merge.Short()
+	}
+
+	upstream, err := repo.Reference(upstreamRef, true)
+	if err != nil {
+		// Like git, no counts for an upstream that is gone
+		return
+	}
+	status.UpstreamAhead, status.UpstreamBehind, err = aheadBehind(repo, head.Hash(), upstream.Hash())
+	if err != nil {
+		status.UpstreamBehind, status.UpstreamAhead, _ = revListCounts(worktreePath, "@{upstream}...HEAD")
+	}
+}
+
+// statusCode converts a go-git file status code to its porcelain letter
+func statusCode(code gogit.StatusCode) byte {
+	if code == gogit.Unmodified {
+		return '.'
+	}
+	return byte(code)
+}
+
+// aheadBehind counts the commits reachable from local but not other, and
+// the other way round. Only histories without merge commits between the
+// tips and their merge base are counted; others return errNeedsGit.
+func aheadBehind(repo *gogit.Repository, local, other plumbing.Hash) (ahead, behind int, err error) {
+	if local == other {
+		return 0, 0, nil
+	}
+	localCommit, err := repo.CommitObject(local)
+	if err != nil {
+		return 0, 0, err
+	}
+	otherCommit, err := repo.CommitObject(other)
+	if err != nil {
+		return 0, 0, err
+	}
+	bases, err := localCommit.MergeBase(otherCommit)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(bases) != 1 {
+		return 0, 0, fmt.Errorf("%d merge bases: %w", len(bases), errNeedsGit)
+	}
+
+	if ahead, err = countCommits(repo, local, bases[0].Hash); err != nil {
+		return 0, 0, err
+	}
+	if behind, err = countCommits(repo, other, bases[0].Hash); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// countCommits counts the commits from tip back to its ancestor stop,
+// following a history without merge commits
+func countCommits(repo *gogit.Repository, tip, stop plumbing.Hash) (int, error) {
+	count := 0
+	for hash := tip; hash != stop; count++ {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return 0, err
+		}
+		if commit.NumParents() != 1 {
+			return 0, fmt.Errorf("commit %s has %d parents: %w", hash, commit.NumParents(), errNeedsGit)
+		}
+		hash = commit.ParentHashes[0]
+	}
+	return count, nil
+}
+
+// GetCurrentBranch returns the branch checked out in the given worktree, or
+// "HEAD" when it is detached
+func (g *GoGitChecker) GetCurrentBranch(worktreePath string) (string, error) {
+	repo, err := openRepository(worktreePath)
+	if err != nil {
+		return g.RealChecker.GetCurrentBranch(worktreePath)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return g.RealChecker.GetCurrentBranch(worktreePath)
+	}
+	if !head.Name().IsBranch() {
+		return "HEAD", nil
+	}
+	return head.Name().Short(), nil
+}
+
+// ListBranches returns the short names of all local and remote-tracking branches
+func (g *GoGitChecker) ListBranches() ([]string, error) {
+	branches, err := g.readBranches()
+	if err != nil {
+		logger.Debug("go-git branch listing failed, running git", "error", err)
+		return g.RealChecker.ListBranches()
+	}
+	return branches, nil
+}
+
+func (g *GoGitChecker) readBranches() ([]string, error) {
+	repo, err := openRepository(".")
+	if err != nil {
+		return nil, err
+	}
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+
+	var local, remote []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		// Skip symbolic refs such as origin/HEAD
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		switch {
+		case ref.Name().IsBranch():
+			local = append(local, ref.Name().Short())
+		case ref.Name().IsRemote():
+			remote = append(remote, ref.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// In the order of git for-each-ref: local branches, then remote ones
+	sort.Strings(local)
+	sort.Strings(remote)
+	return append(local, remote...), nil
+}
+
+// BranchExists checks if a git branch exists (local or remote)
+func (g *GoGitChecker) BranchExists(branchName string) bool {
+	repo, err := openRepository(".")
+	if err != nil {
+		return g.RealChecker.BranchExists(branchName)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(branchName), false); err == nil {
+		return true
+	}
+
+	// Like 'git branch -r --list "*<name>"'
+	refs, err := repo.References()
+	if err != nil {
+		return g.RealChecker.BranchExists(branchName)
+	}
+	defer refs.Close()
+	found := false
+	refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().IsRemote() && strings.HasSuffix(ref.Name().Short(), branchName) {
+			found = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return found
+}
+
+// ListWorktrees returns all git worktrees, read from the repository's
+// administrative files rather than 'git worktree list'
+func (g *GoGitChecker) ListWorktrees() ([]WorktreeInfo, error) {
+	worktrees, err := readWorktrees(".")
+	if err != nil {
+		logger.Debug("reading worktrees failed, running git", "error", err)
+		return g.RealChecker.ListWorktrees()
+	}
+	return worktrees, nil
+}
+
+// readWorktrees lists the worktrees of the repository whose main worktree is
+// repoDir: the main one first, then those in .git/worktrees. A bare
+// repository, or repoDir not being the top of the main worktree, is left to
+// git.
+func readWorktrees(repoDir string) ([]WorktreeInfo, error) {
+	root, err := filepath.Abs(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	gitDir := filepath.Join(root, ".git")
+	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s is not the main worktree: %w", root, errNeedsGit)
+	}
+
+	main := WorktreeInfo{Path: root}
+	if main.Branch, err = readHeadBranch(filepath.Join(gitDir, "HEAD")); err != nil {
+		return nil, err
+	}
+	worktrees := []WorktreeInfo{main}
+
+	entries, err := os.ReadDir(filepath.Join(gitDir, "worktrees"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return worktrees, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		adminDir := filepath.Join(gitDir, "worktrees", entry.Name())
+		link, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+		if err != nil {
+			continue
+		}
+		linked := WorktreeInfo{Path: filepath.Dir(strings.TrimSpace(string(link)))}
+		if linked.Branch, err = readHeadBranch(filepath.Join(adminDir, "HEAD")); err != nil {
+			return nil, err
+		}
+		worktrees = append(worktrees, linked)
+	}
+	return worktrees, nil
+}
+
+// readHeadBranch reads the full name of the branch a HEAD file points to,
+// or "" when HEAD is detached
+func readHeadBranch(headFile string) (string, error) {
+	data, err := os.ReadFile(headFile)
+	if err != nil {
+		return "", err
+	}
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "ref: ")
+	if !ok {
+		return "", nil
+	}
+	return ref, nil
+}
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGoGitChecker_GetStatus_MatchesGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	worktrees := statusTestRepo(t, 2)
+	real := NewRealChecker("main")
+	checker := NewChecker(BackendGoGit, "main")
+	if _, ok := checker.(*GoGitChecker); !ok {
+		t.Fatalf("NewChecker(go-git) = %T, want a GoGitChecker", checker)
+	}
+
+	for _, worktree := range worktrees {
+		want, err := real.GetStatus(worktree)
+		if err != nil {
+			t.Fatalf("RealChecker.GetStatus() error = %v", err)
+		}
+		got, err := checker.GetStatus(worktree)
+		if err != nil {
+			t.Fatalf("GoGitChecker.GetStatus() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GoGitChecker.GetStatus() = %+v, want what git reports: %+v", got, want)
+		}
+	}
+}
+
+func TestGoGitChecker_Branches_MatchGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	worktrees := statusTestRepo(t, 2)
+	commonDir, err := exec.Command("git", "-C", worktrees[0], "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(filepath.Dir(strings.TrimSpace(string(commonDir))))
+
+	real := NewRealChecker("main")
+	checker := NewChecker(BackendGoGit, "main")
+
+	wantBranches, err := real.ListBranches()
+	if err != nil {
+		t.Fatalf("RealChecker.ListBranches() error = %v", err)
+	}
+	if got, err := checker.ListBranches(); err != nil || !reflect.DeepEqual(got, wantBranches) {
+		t.Errorf("GoGitChecker.ListBranches() = %v, %v, want %v", got, err, wantBranches)
+	}
+	for _, branch := range []string{"main", "session1", "missing"} {
+		if got, want := checker.BranchExists(branch), real.BranchExists(branch); got != want {
+			t.Errorf("GoGitChecker.BranchExists(%q) = %v, want %v", branch, got, want)
+		}
+	}
+
+	wantWorktrees, err := real.ListWorktrees()
+	if err != nil {
+		t.Fatalf("RealChecker.ListWorktrees() error = %v", err)
+	}
+	if got, err := readWorktrees("."); err != nil || !reflect.DeepEqual(got, wantWorktrees) {
+		t.Errorf("readWorktrees() = %+v, %v, want %+v", got, err, wantWorktrees)
+	}
+
+	for _, worktree := range worktrees {
+		want, err := real.GetCurrentBranch(worktree)
+		if err != nil {
+			t.Fatalf("RealChecker.GetCurrentBranch() error = %v", err)
+		}
+		if got, err := checker.GetCurrentBranch(worktree); err != nil || got != want {
+			t.Errorf("GoGitChecker.GetCurrentBranch(%s) = %q, %v, want %q", worktree, got, err, want)
+		}
+	}
+}
+
+func BenchmarkGoGitChecker_GetStatus(b *testing.B) {
+	if _, err := exec.LookPath("git"); err != nil {
+		b.Skip("git not found in PATH")
+	}
+
+	for _, sessions := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("sessions=%d", sessions), func(b *testing.B) {
+			worktrees := statusTestRepo(b, sessions)
+			checker := NewChecker(BackendGoGit, "main")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, worktree := range worktrees {
+					if _, err := checker.GetStatus(worktree); err != nil {
+						b.Fatalf("GetStatus() error = %v", err)
+					}
+				}
+			}
+		})
+	}
+}
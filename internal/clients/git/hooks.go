@@ -0,0 +1,122 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// hookInstallTimeout bounds a hook manager's install step, which may
+// download the manager on first use
+const hookInstallTimeout = 2 * time.Minute
+
+// HookManager is a tool that installs a repository's git hooks, like husky
+// or lefthook. New worktrees don't get the hooks it generates, so commits
+// made in them would skip the checks CI runs.
+type HookManager struct {
+	Name    string // Tool name, or "custom" for a configured command
+	Command string // Shell command installing the hooks, run in the worktree
+}
+
+// DetectHookManager finds the hook manager a worktree is set up for from the
+// files that configure it
+func DetectHookManager(worktreePath string) (HookManager, bool) {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(worktreePath, name))
+		return err == nil
+	}
+
+	for _, name := range []string{"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml", "lefthook.toml", "lefthook.json"} {
+		if exists(name) {
+			command := "lefthook install"
+			if _, err := exec.LookPath("lefthook"); err != nil && exists("package.json") {
+				command = "npx --no-install lefthook install"
+			}
+			return HookManager{Name: "lefthook", Command: command}, true
+		}
+	}
+
+	if exists(".husky") {
+		return HookManager{Name: "husky", Command: huskyCommand(worktreePath)}, true
+	}
+
+	if exists(".pre-commit-config.yaml") {
+		return HookManager{Name: "pre-commit", Command: "pre-commit install"}, true
+	}
+
+	return HookManager{}, false
+}
+
+// huskyCommand returns the install step of the husky version the project
+// uses: husky 9 installs with "husky", older versions with "husky install"
+func huskyCommand(worktreePath string) string {
+	data, err := os.ReadFile(filepath.Join(worktreePath, "package.json"))
+	if err == nil {
+		var pkg struct {
+			Scripts map[string]string `json:"scripts"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && strings.Contains(pkg.Scripts["prepare"], "husky install") {
+			return "npx --no-install husky install"
+		}
+	}
+	return "npx --no-install husky"
+}
+
+// InstallHooks runs a hook manager's install step in a new worktree: the
+// given command, or else the install step of the detected hook manager. It
+// then checks that a core.hooksPath inside the worktree exists, since git
+// silently runs no hooks when it doesn't. The returned manager's Name is
+// empty when there was nothing to install.
+func (r *RealChecker) InstallHooks(ctx context.Context, worktreePath, command string) (HookManager, error) {
+	manager := HookManager{Name: "custom", Command: command}
+	if command == "" {
+		detected, ok := DetectHookManager(worktreePath)
+		if ok {
+			manager = detected
+		} else {
+			manager = HookManager{}
+		}
+	}
+
+	if manager.Command != "" {
+		logger.Info("installing git hooks", "manager", manager.Name, "command", manager.Command, "worktree", worktreePath)
+		ctx, cancel := context.WithTimeout(ctx, hookInstallTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", manager.Command)
+		cmd.Dir = worktreePath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return manager, fmt.Errorf("failed to install %s hooks with %q: %w\nOutput: %s", manager.Name, manager.Command, err, string(output))
+		}
+	}
+
+	if hooksPath, ok := missingHooksPath(worktreePath); ok {
+		return manager, fmt.Errorf("core.hooksPath %s does not exist in worktree %s, so no hooks would run; set git_hooks.install to the command that generates it", hooksPath, worktreePath)
+	}
+	return manager, nil
+}
+
+// missingHooksPath returns core.hooksPath when it is relative, so each
+// worktree resolves it inside itself, and the directory is missing there
+func missingHooksPath(worktreePath string) (string, bool) {
+	cmd := exec.Command("git", "config", "--get", "core.hooksPath")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false // Not set
+	}
+
+	hooksPath := strings.TrimSpace(string(output))
+	if hooksPath == "" || filepath.IsAbs(hooksPath) || strings.HasPrefix(hooksPath, "~") {
+		return "", false
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, hooksPath)); err == nil {
+		return "", false
+	}
+	return hooksPath, true
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectHookManager(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"none", map[string]string{"README.md": ""}, ""},
+		{"lefthook", map[string]string{"lefthook.yml": ""}, "lefthook"},
+		{"husky", map[string]string{".husky/pre-commit": "", "package.json": `{"scripts":{"prepare":"husky"}}`}, "npx --no-install husky"},
+		{"husky 8", map[string]string{".husky/pre-commit": "", "package.json": `{"scripts":{"prepare":"husky install"}}`}, "npx --no-install husky install"},
+		{"pre-commit", map[string]string{".pre-commit-config.yaml": ""}, "pre-commit install"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				os.MkdirAll(filepath.Dir(path), 0755)
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			manager, ok := DetectHookManager(dir)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("DetectHookManager() = %+v, want none", manager)
+				}
+				return
+			}
+			if !ok || !strings.Contains(manager.Command, tt.want) {
+				t.Errorf("DetectHookManager() = %+v, want a command containing %q", manager, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealChecker_InstallHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	repo := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, output)
+	}
+	exec.Command("git", "-C", repo, "config", "core.hooksPath", ".hooks/_").Run()
+
+	checker := NewRealChecker("main")
+
+	// Git would silently run no hooks from a missing hooks path
+	if _, err := checker.InstallHooks(context.Background(), repo, ""); err == nil || !strings.Contains(err.Error(), ".hooks/_") {
+		t.Errorf("InstallHooks() error = %v, want the missing core.hooksPath reported", err)
+	}
+
+	manager, err := checker.InstallHooks(context.Background(), repo, "mkdir -p .hooks/_")
+	if err != nil {
+		t.Fatalf("InstallHooks() error = %v", err)
+	}
+	if manager.Name != "custom" {
+		t.Errorf("manager = %+v, want the configured command", manager)
+	}
+
+	if _, err := checker.InstallHooks(context.Background(), repo, "exit 3"); err == nil {
+		t.Error("InstallHooks() should fail when the install step fails")
+	}
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SnapshotWorktree records the files of a worktree as they are, committed or
+// not and untracked ones included, as a git tree object and returns its
+// hash. The worktree's index is left alone: files are staged into a copy.
+// The same contents always give the same hash, so comparing hashes tells
+// whether anything changed between two snapshots.
+func (r *RealChecker) SnapshotWorktree(worktreePath string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "index")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find index of %s: %w", worktreePath, err)
+	}
+	indexPath := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(worktreePath, indexPath)
+	}
+
+	temp, err := os.CreateTemp("", "cwt-snapshot-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot index: %w", err)
+	}
+	temp.Close()
+	defer os.Remove(temp.Name())
+	// Starting from the real index spares rehashing files that didn't change
+	if index, err := os.ReadFile(indexPath); err == nil {
+		if err := os.WriteFile(temp.Name(), index, 0600); err != nil {
+			return "", fmt.Errorf("failed to copy index: %w", err)
+		}
+	} else {
+		os.Remove(temp.Name())
+	}
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+temp.Name())
+	cmd = exec.Command("git", append([]string{"add", "--all"}, WorkPathspec(worktreePath)...)...)
+	cmd.Dir = worktreePath
+	cmd.Env = env
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to snapshot %s: %w\nOutput: %s", worktreePath, err, string(output))
+	}
+
+	cmd = exec.Command("git", "write-tree")
+	cmd.Dir = worktreePath
+	cmd.Env = env
+	output, err = cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot %s: %w", worktreePath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PinSnapshot points ref at a commit of a snapshot's tree, so git keeps the
+// snapshot however long it is needed
+func (r *RealChecker) PinSnapshot(tree, ref string) error {
+	cmd := exec.Command("git", "commit-tree", "-m", "cwt snapshot", tree)
+	// No user identity is needed for a commit no branch will ever hold
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=cwt", "GIT_AUTHOR_EMAIL=cwt@localhost",
+		"GIT_COMMITTER_NAME=cwt", "GIT_COMMITTER_EMAIL=cwt@localhost")
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to pin snapshot %s: %w", tree, err)
+	}
+
+	commit := strings.TrimSpace(string(output))
+	if output, err := exec.Command("git", "update-ref", ref, commit).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to pin snapshot %s: %w\nOutput: %s", tree, err, string(output))
+	}
+	return nil
+}
+
+// DeleteRef deletes a ref such as one a snapshot was pinned with
+func (r *RealChecker) DeleteRef(ref string) error {
+	if output, err := exec.Command("git", "update-ref", "-d", ref).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete %s: %w\nOutput: %s", ref, err, string(output))
+	}
+	return nil
+}
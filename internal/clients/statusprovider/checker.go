@@ -0,0 +1,291 @@
+// Package statusprovider runs external status providers: programs that add
+// fields to a session's status, like the state of its Jira ticket or of its
+// deployment. A provider is any executable named cwt-status-<name> on PATH,
+// or one configured by path. It gets the session as JSON on stdin, in the
+// format of 'cwt status --json', and prints a JSON object of the fields to
+// add, like {"ticket": "PROJ-12", "state": "In Review"}. Run with the panel
+// argument, it prints text for the dashboard's provider panel instead, like
+// a coverage report or the ticket's description.
+package statusprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/logging"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// logger is the status providers' diagnostic log
+var logger = logging.For("statusprovider")
+
+// Prefix names the executables discovered as status providers
+const Prefix = "cwt-status-"
+
+// Checker defines the interface for querying status providers
+type Checker interface {
+	GetFields(session types.Session) []types.StatusField
+}
+
+// Options configure which providers run and how
+type Options struct {
+	Discover bool          // Run every cwt-status-* executable on PATH
+	Commands []string      // Further providers, by path
+	Timeout  time.Duration // How long a provider may take for one session (0 means no limit)
+	Interval time.Duration // How long a provider's answer is reused (0 runs it every time)
+}
+
+// Provider is an executable reporting status fields
+type Provider struct {
+	Name string // Qualifies its fields, "jira" for cwt-status-jira
+	Path string
+}
+
+// RealChecker implements Checker by running the provider executables
+type RealChecker struct {
+	providers []Provider
+	timeout   time.Duration
+	interval  time.Duration
+
+	mu      sync.Mutex
+	answers map[string]answer // Last answer of each provider for each session
+}
+
+// answer is what a provider reported for a session, and when
+type answer struct {
+	fields []types.StatusField
+	at     time.Time
+}
+
+// NewRealChecker creates a RealChecker, discovering the providers on PATH
+// once when opts.Discover is set
+func NewRealChecker(opts Options) *RealChecker {
+	var providers []Provider
+	if opts.Discover {
+		providers = Discover(os.Getenv("PATH"))
+	}
+	for _, command := range opts.Commands {
+		providers = append(providers, Provider{Name: providerName(command), Path: command})
+	}
+
+	return &RealChecker{
+		providers: providers,
+		timeout:   opts.Timeout,
+		interval:  opts.Interval,
+		answers:   make(map[string]answer),
+	}
+}
+
+// Providers returns the providers the checker runs
+func (r *RealChecker) Providers() []Provider {
+	return r.providers
+}
+
+// Discover finds the cwt-status-* executables in the directories of a PATH
+// value. Like the shell, the first directory providing a name wins.
+func Discover(pathList string) []Provider {
+	var providers []Provider
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), Prefix) || seen[entry.Name()] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			seen[entry.Name()] = true
+			providers = append(providers, Provider{Name: providerName(path), Path: path})
+		}
+	}
+	return providers
+}
+
+// FindProvider looks up a provider by name among the configured commands,
+// then on PATH
+func FindProvider(name string, commands []string) (Provider, error) {
+	for _, command := range commands {
+		if providerName(command) == name {
+			return Provider{Name: name, Path: command}, nil
+		}
+	}
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Provider{}, fmt.Errorf("status provider %q not found: no %s%s on PATH", name, Prefix, name)
+	}
+	return Provider{Name: name, Path: path}, nil
+}
+
+// providerName derives a provider's name from its executable
+func providerName(path string) string {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimPrefix(name, Prefix)
+}
+
+// GetFields runs every provider for a session, reusing answers younger than
+// the interval. Providers that fail are logged and left out.
+func (r *RealChecker) GetFields(session types.Session) []types.StatusField {
+	if len(r.providers) == 0 {
+		return nil
+	}
+
+	results := make([][]types.StatusField, len(r.providers))
+	var wg sync.WaitGroup
+	for i, provider := range r.providers {
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+			results[i] = r.providerFields(provider, session)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	var fields []types.StatusField
+	for _, result := range results {
+		fields = append(fields, result...)
+	}
+	return fields
+}
+
+// providerFields returns a provider's answer for a session, running it when
+// there is no recent one
+func (r *RealChecker) providerFields(provider Provider, session types.Session) []types.StatusField {
+	key := provider.Path + "\x00" + session.Core.ID
+
+	r.mu.Lock()
+	cached, ok := r.answers[key]
+	r.mu.Unlock()
+	if ok && r.interval > 0 && time.Since(cached.at) < r.interval {
+		return cached.fields
+	}
+
+	fields, err := r.run(provider, session)
+	if err != nil {
+		// Remember the failure too, so a broken provider isn't rerun on
+		// every refresh
+		logger.Warn("status provider failed", "provider", provider.Name, "session", session.Core.Name, "error", err)
+	}
+
+	r.mu.Lock()
+	r.answers[key] = answer{fields: fields, at: time.Now()}
+	r.mu.Unlock()
+	return fields
+}
+
+// run runs a provider with the session on stdin and parses its fields
+func (r *RealChecker) run(provider Provider, session types.Session) ([]types.StatusField, error) {
+	output, err := execute(provider, session, r.timeout)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFields(provider.Name, output)
+}
+
+// RunPanel runs a provider with the panel argument, returning the text it
+// prints for a session
+func RunPanel(provider Provider, session types.Session, timeout time.Duration) (string, error) {
+	output, err := execute(provider, session, timeout, "panel")
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// execute runs a provider with the session on stdin, returning its output
+func execute(provider Provider, session types.Session, timeout time.Duration, args ...string) ([]byte, error) {
+	input, err := json.Marshal(types.NewSessionOutput(session))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, provider.Path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	// Don't wait on children that outlive a killed provider holding its output
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// ParseFields parses a provider's output: a JSON object whose entries become
+// fields, sorted by name. Strings are kept as they are and other values
+// written as JSON; null values and empty output add nothing.
+func ParseFields(providerName string, output []byte) ([]types.StatusField, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(output, &values); err != nil {
+		return nil, fmt.Errorf("output is not a JSON object: %w", err)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []types.StatusField
+	for _, name := range names {
+		raw := bytes.TrimSpace(values[name])
+		if string(raw) == "null" {
+			continue
+		}
+		value := string(raw)
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			value = text
+		}
+		fields = append(fields, types.StatusField{Provider: providerName, Name: name, Value: value})
+	}
+	return fields, nil
+}
+
+// MockChecker implements Checker for testing
+type MockChecker struct {
+	Fields map[string][]types.StatusField // Fields reported for each session, by name
+}
+
+// NewMockChecker creates a new MockChecker
+func NewMockChecker() *MockChecker {
+	return &MockChecker{Fields: make(map[string][]types.StatusField)}
+}
+
+// GetFields returns the mocked fields
+func (m *MockChecker) GetFields(session types.Session) []types.StatusField {
+	return m.Fields[session.Core.Name]
+}
@@ -0,0 +1,131 @@
+package statusprovider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// writeProvider writes an executable shell script
+func writeProvider(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("failed to write provider: %v", err)
+	}
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeProvider(t, first, "cwt-status-jira", "exit 0\n")
+	writeProvider(t, second, "cwt-status-jira", "exit 0\n")
+	writeProvider(t, second, "cwt-status-deploy", "exit 0\n")
+	writeProvider(t, second, "cwt-other", "exit 0\n")
+	if err := os.WriteFile(filepath.Join(second, "cwt-status-notes"), []byte("not executable"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	providers := Discover(strings.Join([]string{first, "", filepath.Join(first, "missing"), second}, string(os.PathListSeparator)))
+	want := []Provider{
+		{Name: "jira", Path: filepath.Join(first, "cwt-status-jira")},
+		{Name: "deploy", Path: filepath.Join(second, "cwt-status-deploy")},
+	}
+	if !reflect.DeepEqual(providers, want) {
+		t.Errorf("Discover() = %+v, want %+v", providers, want)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("jira", []byte(`{"state": "In Review", "points": 3, "blocked": false, "owner": null}`))
+	if err != nil {
+		t.Fatalf("ParseFields() error = %v", err)
+	}
+	want := []types.StatusField{
+		{Provider: "jira", Name: "blocked", Value: "false"},
+		{Provider: "jira", Name: "points", Value: "3"},
+		{Provider: "jira", Name: "state", Value: "In Review"},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ParseFields() = %+v, want %+v", fields, want)
+	}
+
+	if fields, err := ParseFields("jira", []byte("  \n")); err != nil || fields != nil {
+		t.Errorf("ParseFields(empty) = %+v, %v; want no fields", fields, err)
+	}
+	if _, err := ParseFields("jira", []byte("In Review")); err == nil {
+		t.Error("Expected error for output that isn't a JSON object")
+	}
+}
+
+func TestRealChecker_GetFields(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "runs")
+	// Answers with the session name read from stdin, counting its runs
+	writeProvider(t, dir, "cwt-status-echo", `echo run >> `+counter+`
+name=$(sed -n 's/.*"name":"\([^"]*\)".*/\1/p')
+echo "{\"session\": \"$name\"}"
+`)
+	writeProvider(t, dir, "cwt-status-broken", "echo oops >&2\nexit 1\n")
+	slow := writeProvider(t, dir, "slow", "sleep 5\n")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	checker := NewRealChecker(Options{
+		Discover: true,
+		Commands: []string{slow},
+		Timeout:  500 * time.Millisecond,
+		Interval: time.Minute,
+	})
+	if len(checker.Providers()) < 3 {
+		t.Fatalf("Providers() = %+v, want the two discovered and the configured one", checker.Providers())
+	}
+
+	session := types.Session{Core: types.CoreSession{ID: "1", Name: "feature"}}
+	start := time.Now()
+	fields := checker.GetFields(session)
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("GetFields() took %s, want the slow provider cut off", elapsed)
+	}
+	want := []types.StatusField{{Provider: "echo", Name: "session", Value: "feature"}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("GetFields() = %+v, want %+v", fields, want)
+	}
+
+	// Within the interval the answer is reused
+	checker.GetFields(session)
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("failed to read run count: %v", err)
+	}
+	if n := strings.Count(string(runs), "run"); n != 1 {
+		t.Errorf("provider ran %d times, want 1", n)
+	}
+}
+
+func TestFindProviderAndRunPanel(t *testing.T) {
+	dir := t.TempDir()
+	configured := writeProvider(t, dir, "coverage.sh", "echo \"report $1\"\n")
+	writeProvider(t, dir, "cwt-status-jira", "echo ticket\n")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	provider, err := FindProvider("coverage", []string{configured})
+	if err != nil || provider.Path != configured {
+		t.Fatalf("FindProvider(coverage) = %+v, %v; want the configured command", provider, err)
+	}
+	text, err := RunPanel(provider, types.Session{}, time.Second)
+	if err != nil || text != "report panel\n" {
+		t.Errorf("RunPanel() = %q, %v; want the provider run with the panel argument", text, err)
+	}
+
+	if provider, err := FindProvider("jira", nil); err != nil || provider.Path != filepath.Join(dir, "cwt-status-jira") {
+		t.Errorf("FindProvider(jira) = %+v, %v; want the executable on PATH", provider, err)
+	}
+	if _, err := FindProvider("missing", nil); err == nil {
+		t.Error("Expected error for a provider that doesn't exist")
+	}
+}
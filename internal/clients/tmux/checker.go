@@ -1,21 +1,39 @@
 package tmux
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/logging"
 )
 
+// logger is the tmux client's diagnostic log
+var logger = logging.For("tmux")
+
 // Checker defines the interface for tmux operations
 type Checker interface {
 	IsSessionAlive(sessionName string) bool
+	CheckSessionsAlive(sessionNames []string) (map[string]bool, error)
 	CaptureOutput(sessionName string) (string, error)
-	CreateSession(name, workdir, command string) error
+	CaptureHistory(sessionName string, lines int) (string, error)
+	CreateSession(name, workdir, command string, env ...string) error
 	KillSession(sessionName string) error
+	RenameSession(sessionName, newName string) error
 	ListSessions() ([]string, error)
+	SendKeys(sessionName, text string) error
+	SetExitHook(sessionName, command string) error
+	RespawnSession(sessionName, workdir, command string, env ...string) error
+	OpenWindow(sessionName string) error
 }
 
+// ErrNotInTmux is returned by OpenWindow when cwt isn't running inside tmux
+var ErrNotInTmux = errors.New("not running inside tmux")
+
 // RealChecker implements Checker using actual tmux commands
 type RealChecker struct{}
 
@@ -31,6 +49,30 @@ func (r *RealChecker) IsSessionAlive(sessionName string) bool {
 	return err == nil
 }
 
+// CheckSessionsAlive reports liveness for many sessions with a single
+// list-sessions call instead of one has-session call per session
+func (r *RealChecker) CheckSessionsAlive(sessionNames []string) (map[string]bool, error) {
+	running, err := r.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	return diffAlive(sessionNames, running), nil
+}
+
+// diffAlive marks each of the known session names as alive if it is running
+func diffAlive(sessionNames, running []string) map[string]bool {
+	runningSet := make(map[string]bool, len(running))
+	for _, name := range running {
+		runningSet[name] = true
+	}
+
+	alive := make(map[string]bool, len(sessionNames))
+	for _, name := range sessionNames {
+		alive[name] = runningSet[name]
+	}
+	return alive
+}
+
 // CaptureOutput captures the current pane output from a tmux session
 func (r *RealChecker) CaptureOutput(sessionName string) (string, error) {
 	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p")
@@ -41,19 +83,35 @@ func (r *RealChecker) CaptureOutput(sessionName string) (string, error) {
 	return string(output), nil
 }
 
-// CreateSession creates a new tmux session with the specified command
-func (r *RealChecker) CreateSession(name, workdir, command string) error {
+// CaptureHistory captures the visible pane plus up to lines of scrollback.
+// Once a pane has died, what it printed last may already be in scrollback.
+func (r *RealChecker) CaptureHistory(sessionName string, lines int) (string, error) {
+	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p", "-S", fmt.Sprintf("-%d", lines))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture tmux output for session %s: %w", sessionName, err)
+	}
+	return string(output), nil
+}
+
+// CreateSession creates a new tmux session with the specified command and
+// environment variables, given as KEY=value
+func (r *RealChecker) CreateSession(name, workdir, command string, env ...string) error {
 	args := []string{
 		"new-session",
 		"-d",       // detached
 		"-s", name, // session name
 		"-c", workdir, // working directory
 	}
+	for _, variable := range env {
+		args = append(args, "-e", variable)
+	}
 
 	if command != "" {
 		args = append(args, command)
 	}
 
+	logger.Info("creating tmux session", "session", name, "dir", workdir, "command", command)
 	cmd := exec.Command("tmux", args...)
 	err := cmd.Run()
 	if err != nil {
@@ -64,7 +122,7 @@ func (r *RealChecker) CreateSession(name, workdir, command string) error {
 	mouseCmd := exec.Command("tmux", "set-option", "-t", name, "mouse", "on")
 	if err := mouseCmd.Run(); err != nil {
 		// Non-fatal error - session still usable without mouse mode
-		fmt.Printf("Warning: Failed to enable mouse mode for session %s: %v\n", name, err)
+		logger.Warn("failed to enable mouse mode", "session", name, "error", err)
 	}
 
 	return nil
@@ -72,6 +130,7 @@ func (r *RealChecker) CreateSession(name, workdir, command string) error {
 
 // KillSession terminates a tmux session
 func (r *RealChecker) KillSession(sessionName string) error {
+	logger.Info("killing tmux session", "session", sessionName)
 	cmd := exec.Command("tmux", "kill-session", "-t", sessionName)
 	err := cmd.Run()
 	if err != nil {
@@ -80,6 +139,114 @@ func (r *RealChecker) KillSession(sessionName string) error {
 	return nil
 }
 
+// RenameSession renames a tmux session; clients attached to it stay attached
+func (r *RealChecker) RenameSession(sessionName, newName string) error {
+	cmd := exec.Command("tmux", "rename-session", "-t", sessionName, newName)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to rename tmux session %s: %w", sessionName, err)
+	}
+	return nil
+}
+
+// SendKeys types text into a session's active pane and presses Enter
+func (r *RealChecker) SendKeys(sessionName, text string) error {
+	// -l sends the text literally so words like "Enter" aren't treated as keys
+	cmd := exec.Command("tmux", "send-keys", "-t", sessionName, "-l", text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to send keys to tmux session %s: %w", sessionName, err)
+	}
+
+	cmd = exec.Command("tmux", "send-keys", "-t", sessionName, "Enter")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to send keys to tmux session %s: %w", sessionName, err)
+	}
+	return nil
+}
+
+// SetExitHook runs a shell command when the process in the session's pane
+// exits, and then kills the session unless the command respawned the pane.
+// The dead pane is kept until then so the command can capture its output;
+// #{pane_dead_status} in the command expands to the exit status.
+func (r *RealChecker) SetExitHook(sessionName, command string) error {
+	cmd := exec.Command("tmux", "set-option", "-w", "-t", sessionName, "remain-on-exit", "on")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to keep exited pane for tmux session %s: %w", sessionName, err)
+	}
+
+	kill := "kill-session -t " + tmuxQuote(sessionName)
+	hook := fmt.Sprintf("run-shell %s ; if-shell -F '#{pane_dead}' %s", tmuxQuote(command), tmuxQuote(kill))
+	cmd = exec.Command("tmux", "set-hook", "-t", sessionName, "pane-died", hook)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to set exit hook for tmux session %s: %w", sessionName, err)
+	}
+	return nil
+}
+
+// RespawnSession restarts the exited pane of a session with a new command
+// and environment variables, given as KEY=value, keeping the session itself
+func (r *RealChecker) RespawnSession(sessionName, workdir, command string, env ...string) error {
+	args := []string{"respawn-pane", "-k", "-t", sessionName, "-c", workdir}
+	for _, variable := range env {
+		args = append(args, "-e", variable)
+	}
+	cmd := exec.Command("tmux", append(args, command)...)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to respawn tmux session %s: %w", sessionName, err)
+	}
+	return nil
+}
+
+// OpenWindow shows a session's window in the tmux session cwt is running
+// in, next to the current window, by linking it rather than attaching. Both
+// sessions keep running; if the window is already linked it is selected.
+func (r *RealChecker) OpenWindow(sessionName string) error {
+	pane := os.Getenv("TMUX_PANE")
+	if os.Getenv("TMUX") == "" || pane == "" {
+		return ErrNotInTmux
+	}
+
+	current, err := tmuxDisplay(pane, "#{session_id}")
+	if err != nil {
+		return err
+	}
+	window, err := tmuxDisplay(sessionName+":", "#{window_id}")
+	if err != nil {
+		return fmt.Errorf("failed to find window of tmux session %s: %w", sessionName, err)
+	}
+
+	output, err := exec.Command("tmux", "list-windows", "-t", current, "-F", "#{window_id}").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list windows: %w", err)
+	}
+	if slices.Contains(strings.Fields(string(output)), window) {
+		cmd := exec.Command("tmux", "select-window", "-t", current+":"+window)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to select window of tmux session %s: %w", sessionName, err)
+		}
+		return nil
+	}
+
+	cmd := exec.Command("tmux", "link-window", "-a", "-s", window, "-t", current+":")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to open tmux session %s in a window: %w", sessionName, err)
+	}
+	return nil
+}
+
+// tmuxDisplay expands a tmux format for a target
+func tmuxDisplay(target, format string) (string, error) {
+	output, err := exec.Command("tmux", "display-message", "-p", "-t", target, format).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to query tmux target %s: %w", target, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// tmuxQuote quotes s as a single argument in a tmux command string
+func tmuxQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(s) + `"`
+}
+
 // ListSessions returns a list of all active tmux sessions
 func (r *RealChecker) ListSessions() ([]string, error) {
 	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
@@ -104,9 +271,19 @@ type MockChecker struct {
 	AliveSessions    map[string]bool
 	Output           map[string]string
 	CreatedSessions  []string
+	SessionCommands  map[string]string // Command each session was created with
 	KilledSessions   []string
+	SentKeys         map[string][]string // Text sent to each session, in order
+	ExitHooks        map[string]string   // Exit hook command of each session
+	Respawned        map[string]string   // Command each session was last respawned with
+	SessionEnv       map[string][]string // Environment each session was last created or respawned with
+	OpenedWindows    []string            // Sessions opened as windows, in order
+	NotInTmux        bool                // Make OpenWindow fail as if cwt ran outside tmux
 	ShouldFailCreate bool
+	ShouldFailRename bool
 	Delay            time.Duration
+	ListCalls        int // Number of ListSessions/CheckSessionsAlive calls
+	AliveCalls       int // Number of IsSessionAlive calls
 }
 
 // NewMockChecker creates a new MockChecker
@@ -115,7 +292,12 @@ func NewMockChecker() *MockChecker {
 		AliveSessions:   make(map[string]bool),
 		Output:          make(map[string]string),
 		CreatedSessions: []string{},
+		SessionCommands: make(map[string]string),
 		KilledSessions:  []string{},
+		SentKeys:        make(map[string][]string),
+		ExitHooks:       make(map[string]string),
+		Respawned:       make(map[string]string),
+		SessionEnv:      make(map[string][]string),
 	}
 }
 
@@ -124,9 +306,19 @@ func (m *MockChecker) IsSessionAlive(sessionName string) bool {
 	if m.Delay > 0 {
 		time.Sleep(m.Delay)
 	}
+	m.AliveCalls++
 	return m.AliveSessions[sessionName]
 }
 
+// CheckSessionsAlive returns the mocked status for each session in one call
+func (m *MockChecker) CheckSessionsAlive(sessionNames []string) (map[string]bool, error) {
+	running, err := m.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	return diffAlive(sessionNames, running), nil
+}
+
 // CaptureOutput returns the mocked output
 func (m *MockChecker) CaptureOutput(sessionName string) (string, error) {
 	if m.Delay > 0 {
@@ -139,8 +331,13 @@ func (m *MockChecker) CaptureOutput(sessionName string) (string, error) {
 	return output, nil
 }
 
+// CaptureHistory returns the mocked output
+func (m *MockChecker) CaptureHistory(sessionName string, lines int) (string, error) {
+	return m.CaptureOutput(sessionName)
+}
+
 // CreateSession mocks session creation
-func (m *MockChecker) CreateSession(name, workdir, command string) error {
+func (m *MockChecker) CreateSession(name, workdir, command string, env ...string) error {
 	if m.Delay > 0 {
 		time.Sleep(m.Delay)
 	}
@@ -148,6 +345,8 @@ func (m *MockChecker) CreateSession(name, workdir, command string) error {
 		return fmt.Errorf("mock create failure for session %s", name)
 	}
 	m.CreatedSessions = append(m.CreatedSessions, name)
+	m.SessionCommands[name] = command
+	m.SessionEnv[name] = env
 	m.AliveSessions[name] = true
 	return nil
 }
@@ -162,6 +361,58 @@ func (m *MockChecker) KillSession(sessionName string) error {
 	return nil
 }
 
+// RenameSession moves a running session's mocked state to its new name
+func (m *MockChecker) RenameSession(sessionName, newName string) error {
+	if m.ShouldFailRename || !m.AliveSessions[sessionName] {
+		return fmt.Errorf("failed to rename tmux session %s", sessionName)
+	}
+	delete(m.AliveSessions, sessionName)
+	m.AliveSessions[newName] = true
+	if output, ok := m.Output[sessionName]; ok {
+		delete(m.Output, sessionName)
+		m.Output[newName] = output
+	}
+	return nil
+}
+
+// SendKeys records the text sent to a session, failing if it isn't running
+func (m *MockChecker) SendKeys(sessionName, text string) error {
+	if !m.AliveSessions[sessionName] {
+		return fmt.Errorf("failed to send keys to tmux session %s: session not running", sessionName)
+	}
+	m.SentKeys[sessionName] = append(m.SentKeys[sessionName], text)
+	return nil
+}
+
+// SetExitHook records the exit hook command of a session
+func (m *MockChecker) SetExitHook(sessionName, command string) error {
+	m.ExitHooks[sessionName] = command
+	return nil
+}
+
+// RespawnSession records the new command and marks the session alive
+func (m *MockChecker) RespawnSession(sessionName, workdir, command string, env ...string) error {
+	if m.ShouldFailCreate {
+		return fmt.Errorf("mock respawn failure for session %s", sessionName)
+	}
+	m.Respawned[sessionName] = command
+	m.SessionEnv[sessionName] = env
+	m.AliveSessions[sessionName] = true
+	return nil
+}
+
+// OpenWindow records the session opened as a window
+func (m *MockChecker) OpenWindow(sessionName string) error {
+	if m.NotInTmux {
+		return ErrNotInTmux
+	}
+	if !m.AliveSessions[sessionName] {
+		return fmt.Errorf("failed to find window of tmux session %s: session not running", sessionName)
+	}
+	m.OpenedWindows = append(m.OpenedWindows, sessionName)
+	return nil
+}
+
 // SetSessionAlive sets the alive status for a session
 func (m *MockChecker) SetSessionAlive(sessionName string, alive bool) {
 	m.AliveSessions[sessionName] = alive
@@ -172,6 +423,7 @@ func (m *MockChecker) ListSessions() ([]string, error) {
 	if m.Delay > 0 {
 		time.Sleep(m.Delay)
 	}
+	m.ListCalls++
 	sessions := make([]string, 0)
 	for name, alive := range m.AliveSessions {
 		if alive {
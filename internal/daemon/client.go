@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// DefaultTimeout bounds every call to the daemon so a wedged daemon can't
+// hang CLI commands; callers fall back to direct derivation instead
+const DefaultTimeout = 2 * time.Second
+
+// ErrNotRunning is returned by Connect when no daemon is listening
+var ErrNotRunning = errors.New("daemon is not running")
+
+// Client talks to a running daemon. It implements state.SessionProvider.
+type Client struct {
+	socketPath string
+	timeout    time.Duration
+}
+
+// Connect returns a client for the daemon serving dataDir, or ErrNotRunning
+// if none answers
+func Connect(dataDir string) (*Client, error) {
+	client := &Client{
+		socketPath: SocketPath(dataDir),
+		timeout:    DefaultTimeout,
+	}
+
+	if _, err := client.Status(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+
+	return client, nil
+}
+
+// Status returns information about the running daemon
+func (c *Client) Status() (*Status, error) {
+	resp, err := c.call(Request{Method: MethodPing})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status == nil {
+		return nil, fmt.Errorf("daemon returned no status")
+	}
+	return resp.Status, nil
+}
+
+// Sessions returns all sessions as last derived by the daemon
+func (c *Client) Sessions() ([]types.Session, error) {
+	resp, err := c.call(Request{Method: MethodSessions})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Sessions == nil {
+		return []types.Session{}, nil
+	}
+	return resp.Sessions, nil
+}
+
+// Session returns a single session by ID
+func (c *Client) Session(sessionID string) (types.Session, error) {
+	resp, err := c.call(Request{Method: MethodSession, SessionID: sessionID})
+	if err != nil {
+		return types.Session{}, err
+	}
+	if resp.Session == nil {
+		return types.Session{}, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+	return *resp.Session, nil
+}
+
+// Invalidate asks the daemon to re-derive a session ("" for all) and waits
+// until the fresh state is being served
+func (c *Client) Invalidate(sessionID string) error {
+	_, err := c.call(Request{Method: MethodInvalidate, SessionID: sessionID})
+	return err
+}
+
+// Shutdown asks the daemon to exit
+func (c *Client) Shutdown() error {
+	_, err := c.call(Request{Method: MethodShutdown})
+	return err
+}
+
+// call sends one request over a fresh connection and decodes the reply
+func (c *Client) call(req Request) (*Response, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, c.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send daemon request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read daemon response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+
+	return &resp, nil
+}
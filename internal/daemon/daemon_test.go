@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestConnect_NotRunning(t *testing.T) {
+	_, err := Connect(t.TempDir())
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Connect() error = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestServer_ServesSessions(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	gitChecker := git.NewMockChecker()
+
+	sm := state.NewManager(state.Config{
+		DataDir:        dataDir,
+		TmuxChecker:    tmux.NewMockChecker(),
+		GitChecker:     gitChecker,
+		ClaudeChecker:  claude.NewMockChecker(),
+		BaseBranch:     "main",
+		StatusCacheTTL: time.Hour,
+	})
+	if err := sm.CreateSession("served"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	server := NewServer(sm, Options{GitInterval: time.Hour, TmuxInterval: time.Hour})
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Run(context.Background())
+	}()
+
+	client := waitForDaemon(t, dataDir)
+
+	sessions, err := client.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Core.Name != "served" {
+		t.Fatalf("Sessions() = %+v, want the 'served' session", sessions)
+	}
+	sessionID := sessions[0].Core.ID
+
+	// A second daemon for the same data dir must refuse to start
+	if err := NewServer(sm, Options{}).Run(context.Background()); err == nil {
+		t.Error("Run() should fail while another daemon is running")
+	}
+
+	// Invalidate makes the daemon re-derive past its status cache
+	gitChecker.SetStatus(sessions[0].Core.WorktreePath, types.GitStatus{HasChanges: true})
+	if err := client.Invalidate(sessionID); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	session, err := client.Session(sessionID)
+	if err != nil {
+		t.Fatalf("Session() error = %v", err)
+	}
+	if !session.GitStatus.HasChanges {
+		t.Error("Expected fresh git status after Invalidate")
+	}
+
+	if err := client.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not stop after Shutdown")
+	}
+
+	if _, err := Connect(dataDir); err == nil {
+		t.Error("Connect() should fail after shutdown")
+	}
+}
+
+// waitForDaemon polls until the daemon accepts connections
+func waitForDaemon(t *testing.T, dataDir string) *Client {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if client, err := Connect(dataDir); err == nil {
+			return client
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatal("daemon did not start")
+	return nil
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// SocketFileName is the Unix socket the daemon listens on inside the data directory
+const SocketFileName = "daemon.sock"
+
+// Request methods understood by the daemon
+const (
+	MethodPing       = "ping"
+	MethodSessions   = "sessions"
+	MethodSession    = "session"
+	MethodInvalidate = "invalidate"
+	MethodShutdown   = "shutdown"
+)
+
+// Request is a single call sent to the daemon as one line of JSON
+type Request struct {
+	Method    string `json:"method"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// Response is the daemon's reply to a Request
+type Response struct {
+	Error    string          `json:"error,omitempty"`
+	Status   *Status         `json:"status,omitempty"`
+	Sessions []types.Session `json:"sessions,omitempty"`
+	Session  *types.Session  `json:"session,omitempty"`
+}
+
+// Status describes a running daemon
+type Status struct {
+	PID          int       `json:"pid"`
+	StartedAt    time.Time `json:"started_at"`
+	DerivedAt    time.Time `json:"derived_at"` // When the served sessions were last derived
+	SessionCount int       `json:"session_count"`
+}
+
+// SocketPath returns the daemon socket path for a data directory
+func SocketPath(dataDir string) string {
+	return filepath.Join(dataDir, SocketFileName)
+}
@@ -0,0 +1,337 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/jlaneve/cwt-cli/internal/logging"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// logger is the daemon's diagnostic log
+var logger = logging.For("daemon")
+
+// Options controls how often the daemon re-derives state on its own
+type Options struct {
+	GitInterval  time.Duration // Full re-derivation including git status
+	TmuxInterval time.Duration // Re-derivation to pick up tmux liveness changes
+}
+
+// Server keeps derived session state up to date and serves it over a Unix socket
+type Server struct {
+	sm      *state.Manager
+	dataDir string
+	opts    Options
+
+	mu        sync.RWMutex
+	sessions  []types.Session
+	derivedAt time.Time
+	startedAt time.Time
+
+	refreshMu sync.Mutex // Serializes re-derivations
+	ctx       context.Context
+	cancel    context.CancelFunc
+	followUps sync.WaitGroup // Follow-up commands running
+
+	optsChanged chan Options // New polling intervals from a config reload
+}
+
+// NewServer creates a daemon server for the sessions managed by sm.
+// sm must not itself use a daemon as its session provider.
+func NewServer(sm *state.Manager, opts Options) *Server {
+	return &Server{
+		sm:          sm,
+		dataDir:     sm.GetDataDir(),
+		opts:        opts.withDefaults(),
+		optsChanged: make(chan Options, 1),
+	}
+}
+
+func (o Options) withDefaults() Options {
+	if o.GitInterval <= 0 {
+		o.GitInterval = 10 * time.Second
+	}
+	if o.TmuxInterval <= 0 {
+		o.TmuxInterval = 30 * time.Second
+	}
+	return o
+}
+
+// SetOptions changes the polling intervals of a running server
+func (s *Server) SetOptions(opts Options) {
+	opts = opts.withDefaults()
+	for {
+		select {
+		case s.optsChanged <- opts:
+			return
+		default:
+			// Replace an update the poller hasn't picked up yet
+			select {
+			case <-s.optsChanged:
+			default:
+			}
+		}
+	}
+}
+
+// Run serves requests until ctx is cancelled or a shutdown request arrives.
+// It fails if another daemon is already serving the same data directory.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// Running follow-ups are killed on the way out, and their results
+	// recorded before returning
+	defer s.followUps.Wait()
+	defer cancel()
+	s.ctx, s.cancel = ctx, cancel
+
+	socketPath := SocketPath(s.dataDir)
+	if _, err := Connect(s.dataDir); err == nil {
+		return fmt.Errorf("a daemon is already running for %s", s.dataDir)
+	}
+
+	// A socket left behind by a crashed daemon would make Listen fail
+	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+	defer os.Remove(socketPath)
+
+	s.startedAt = time.Now()
+	if err := s.refresh(); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go s.watch(ctx)
+	go s.poll(ctx)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+// Snapshot returns the sessions currently being served
+func (s *Server) Snapshot() []types.Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessions
+}
+
+// refresh re-derives all sessions and swaps them in atomically
+func (s *Server) refresh() error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	sessions, err := s.sm.DeriveFreshSessions()
+	if err != nil {
+		return fmt.Errorf("failed to derive sessions: %w", err)
+	}
+
+	s.mu.Lock()
+	s.sessions = sessions
+	s.derivedAt = time.Now()
+	s.mu.Unlock()
+
+	s.runFollowUps(sessions)
+	return nil
+}
+
+// runFollowUps starts the follow-up commands of sessions that reached their
+// trigger. Each runs once: RunFollowUp claims it before running it.
+func (s *Server) runFollowUps(sessions []types.Session) {
+	if s.ctx == nil {
+		return
+	}
+	for _, session := range state.DueFollowUps(sessions) {
+		s.followUps.Add(1)
+		go func(sessionID, name string) {
+			defer s.followUps.Done()
+			followUp, err := s.sm.RunFollowUp(s.ctx, sessionID)
+			switch {
+			case err != nil:
+				logger.Warn("follow-up failed", "session", name, "error", err)
+			case followUp != nil:
+				logger.Info("follow-up finished", "session", name, "command", followUp.Command, "result", followUp.Summary())
+			}
+		}(session.Core.ID, session.Core.Name)
+	}
+}
+
+// handle answers a single request on conn
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(DefaultTimeout))
+
+	var req Request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		return
+	}
+
+	json.NewEncoder(conn).Encode(s.dispatch(req))
+}
+
+func (s *Server) dispatch(req Request) Response {
+	switch req.Method {
+	case MethodPing:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return Response{Status: &Status{
+			PID:          os.Getpid(),
+			StartedAt:    s.startedAt,
+			DerivedAt:    s.derivedAt,
+			SessionCount: len(s.sessions),
+		}}
+
+	case MethodSessions:
+		return Response{Sessions: s.Snapshot()}
+
+	case MethodSession:
+		for _, session := range s.Snapshot() {
+			if session.Core.ID == req.SessionID {
+				return Response{Session: &session}
+			}
+		}
+		return Response{Error: fmt.Sprintf("session with ID %s not found", req.SessionID)}
+
+	case MethodInvalidate:
+		s.sm.InvalidateStatus(req.SessionID)
+		if err := s.refresh(); err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{}
+
+	case MethodShutdown:
+		s.cancel()
+		return Response{}
+
+	default:
+		return Response{Error: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+// poll periodically re-derives state that has no file to watch: git working
+// tree changes and tmux sessions exiting
+func (s *Server) poll(ctx context.Context) {
+	gitTicker := time.NewTicker(s.opts.GitInterval)
+	defer gitTicker.Stop()
+	tmuxTicker := time.NewTicker(s.opts.TmuxInterval)
+	defer tmuxTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-gitTicker.C:
+			s.sm.InvalidateStatus("")
+			s.refresh()
+		case <-tmuxTicker.C:
+			s.refresh()
+		case opts := <-s.optsChanged:
+			s.opts = opts
+			gitTicker.Reset(opts.GitInterval)
+			tmuxTicker.Reset(opts.TmuxInterval)
+		}
+	}
+}
+
+// watch re-derives state when the data directory changes: session CRUD,
+// Claude hook events and refresh broadcasts from other cwt processes
+func (s *Server) watch(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return // Polling still keeps state fresh
+	}
+	defer watcher.Close()
+
+	watcher.Add(s.dataDir)
+	watcher.Add(filepath.Join(s.dataDir, "session-state"))
+
+	// Debounce bursts of events (e.g. atomic writes) into one refresh
+	var timer *time.Timer
+	invalidate := false
+	var pendingMu sync.Mutex
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			base := filepath.Base(event.Name)
+			if IgnoredFile(base) {
+				continue
+			}
+			if base == "session-state" && event.Op&fsnotify.Create != 0 {
+				watcher.Add(event.Name)
+			}
+
+			pendingMu.Lock()
+			if base == state.RefreshFileName {
+				// Another process changed git or tmux state; cached status is stale
+				invalidate = true
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(200*time.Millisecond, func() {
+				pendingMu.Lock()
+				drop := invalidate
+				invalidate = false
+				pendingMu.Unlock()
+
+				if drop {
+					s.sm.InvalidateStatus("")
+				}
+				s.refresh()
+			})
+			pendingMu.Unlock()
+		case <-watcher.Errors:
+			// Keep watching; polling covers anything missed
+		}
+	}
+}
+
+// IgnoredFile reports whether a data dir file never affects derived state
+func IgnoredFile(base string) bool {
+	return strings.HasSuffix(base, ".tmp") ||
+		strings.HasSuffix(base, ".lock") ||
+		base == SocketFileName ||
+		base == state.StatusCacheFileName ||
+		base == state.UsageFileName ||
+		base == types.SessionEventLogName // Followed by a snapshot write
+}
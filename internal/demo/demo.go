@@ -0,0 +1,256 @@
+// Package demo simulates sessions for the dashboard's demo mode, so the TUI
+// can be shown and developed without git worktrees, tmux or Claude running.
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// phase is one stretch of a simulated session's life
+type phase struct {
+	duration time.Duration
+	claude   types.ClaudeState
+	message  string
+	alive    bool
+	files    []string // Modified files in the worktree
+	commits  int
+}
+
+// script is the looping life of one simulated session
+type script struct {
+	name   string
+	task   string
+	phases []phase
+}
+
+// scripts are the simulated sessions, chosen so the dashboard shows every
+// state at once and keeps changing
+var scripts = []script{
+	{
+		name: "auth-refactor",
+		task: "Move session handling into the auth package",
+		phases: []phase{
+			{duration: 8 * time.Second, claude: types.ClaudeWorking, message: "Reading internal/auth/session.go", alive: true},
+			{duration: 12 * time.Second, claude: types.ClaudeWorking, message: "Editing internal/auth/session.go", alive: true, files: []string{"internal/auth/session.go"}},
+			{duration: 10 * time.Second, claude: types.ClaudeWaiting, message: "Claude needs your permission to use Bash", alive: true, files: []string{"internal/auth/session.go"}},
+			{duration: 10 * time.Second, claude: types.ClaudeWorking, message: "Running go test ./internal/auth/...", alive: true, files: []string{"internal/auth/session.go", "internal/auth/session_test.go"}},
+			{duration: 15 * time.Second, claude: types.ClaudeComplete, message: "Moved session handling and updated tests", alive: true, files: []string{"internal/auth/session.go", "internal/auth/session_test.go"}},
+		},
+	},
+	{
+		name: "fix-flaky-tests",
+		task: "Find out why TestUpload fails intermittently",
+		phases: []phase{
+			{duration: 10 * time.Second, claude: types.ClaudeWaiting, message: "Should I add a retry or fix the race in the uploader?", alive: true},
+			{duration: 15 * time.Second, claude: types.ClaudeWorking, message: "Editing upload/uploader.go", alive: true, files: []string{"upload/uploader.go"}},
+			{duration: 20 * time.Second, claude: types.ClaudeComplete, message: "Fixed a data race on the progress counter", alive: true, files: []string{"upload/uploader.go"}},
+		},
+	},
+	{
+		name: "add-dark-mode",
+		task: "Add a dark theme to the settings page",
+		phases: []phase{
+			{duration: 20 * time.Second, claude: types.ClaudeWorking, message: "Editing web/src/theme.ts", alive: true, files: []string{"web/src/theme.ts", "web/src/settings.tsx"}},
+			{duration: 15 * time.Second, claude: types.ClaudeIdle, alive: true, files: []string{"web/src/theme.ts", "web/src/settings.tsx"}, commits: 1},
+		},
+	},
+	{
+		name: "update-deps",
+		task: "Update dependencies and fix breaking changes",
+		phases: []phase{
+			{duration: time.Minute, claude: types.ClaudeComplete, message: "Updated 12 dependencies", alive: false, files: []string{"go.mod", "go.sum"}},
+		},
+	},
+	{
+		name: "docs-api",
+		task: "Document the public API",
+		phases: []phase{
+			{duration: 25 * time.Second, claude: types.ClaudeIdle, alive: true, commits: 3},
+			{duration: 10 * time.Second, claude: types.ClaudeWorking, message: "Editing docs/api.md", alive: true, files: []string{"docs/api.md"}, commits: 3},
+		},
+	},
+}
+
+// at returns the phase a script is in after elapsed time, and how long it
+// has been in that phase
+func (s script) at(elapsed time.Duration) (phase, time.Duration) {
+	var total time.Duration
+	for _, p := range s.phases {
+		total += p.duration
+	}
+
+	elapsed %= total
+	for _, p := range s.phases {
+		if elapsed < p.duration {
+			return p, elapsed
+		}
+		elapsed -= p.duration
+	}
+	return s.phases[len(s.phases)-1], 0
+}
+
+// Simulator answers status queries for the simulated sessions from their
+// scripts, based on how long the demo has been running
+type Simulator struct {
+	start   time.Time
+	now     func() time.Time
+	dataDir string
+	byPath  map[string]script
+	byTmux  map[string]script
+}
+
+// NewSimulator creates a simulator whose session data lives in dataDir
+func NewSimulator(dataDir string) *Simulator {
+	s := &Simulator{
+		start:   time.Now(),
+		now:     time.Now,
+		dataDir: dataDir,
+		byPath:  make(map[string]script),
+		byTmux:  make(map[string]script),
+	}
+	for _, sc := range scripts {
+		s.byPath[s.worktreePath(sc.name)] = sc
+		s.byTmux["cwt-"+sc.name] = sc
+	}
+	return s
+}
+
+func (s *Simulator) worktreePath(name string) string {
+	return filepath.Join(s.dataDir, "worktrees", name)
+}
+
+// phase returns the current phase of a script
+func (s *Simulator) phase(sc script) phase {
+	p, _ := sc.at(s.now().Sub(s.start))
+	return p
+}
+
+// NewManager creates a throwaway data directory seeded with the simulated
+// sessions and a state manager that derives their status from the simulator.
+// The returned cleanup function removes the data directory.
+func NewManager() (*state.Manager, func(), error) {
+	dataDir, err := os.MkdirTemp("", "cwt-demo-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create demo data directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dataDir) }
+
+	sim := NewSimulator(dataDir)
+	if err := sim.writeSessions(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	sm := state.NewManager(state.Config{
+		DataDir:       dataDir,
+		TmuxChecker:   &tmuxChecker{MockChecker: tmux.NewMockChecker(), sim: sim},
+		GitChecker:    &gitChecker{MockChecker: git.NewMockChecker(), sim: sim},
+		ClaudeChecker: &claudeChecker{MockChecker: claude.NewMockChecker(), sim: sim},
+	})
+	return sm, cleanup, nil
+}
+
+// writeSessions saves the simulated sessions as if cwt had created them
+func (s *Simulator) writeSessions() error {
+	var data types.SessionData
+	for i, sc := range scripts {
+		data.Sessions = append(data.Sessions, types.CoreSession{
+			ID:           fmt.Sprintf("demo-%d", i+1),
+			Name:         sc.name,
+			WorktreePath: s.worktreePath(sc.name),
+			TmuxSession:  "cwt-" + sc.name,
+			CreatedAt:    s.start.Add(-time.Duration(len(scripts)-i) * 17 * time.Minute),
+			Task:         sc.task,
+		})
+	}
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode demo sessions: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dataDir, "sessions.json"), encoded, 0644); err != nil {
+		return fmt.Errorf("failed to write demo sessions: %w", err)
+	}
+	return nil
+}
+
+// tmuxChecker reports scripted liveness; other operations are mocked
+type tmuxChecker struct {
+	*tmux.MockChecker
+	sim *Simulator
+}
+
+func (c *tmuxChecker) IsSessionAlive(sessionName string) bool {
+	sc, ok := c.sim.byTmux[sessionName]
+	return ok && c.sim.phase(sc).alive
+}
+
+func (c *tmuxChecker) CheckSessionsAlive(sessionNames []string) (map[string]bool, error) {
+	alive := make(map[string]bool, len(sessionNames))
+	for _, name := range sessionNames {
+		alive[name] = c.IsSessionAlive(name)
+	}
+	return alive, nil
+}
+
+func (c *tmuxChecker) CaptureOutput(sessionName string) (string, error) {
+	sc, ok := c.sim.byTmux[sessionName]
+	if !ok {
+		return "", fmt.Errorf("demo session %s not found", sessionName)
+	}
+	return c.sim.phase(sc).message, nil
+}
+
+// gitChecker reports scripted working tree changes; other operations are mocked
+type gitChecker struct {
+	*git.MockChecker
+	sim *Simulator
+}
+
+func (c *gitChecker) GetStatus(worktreePath string) (types.GitStatus, error) {
+	sc, ok := c.sim.byPath[worktreePath]
+	if !ok {
+		return types.GitStatus{}, nil
+	}
+	p := c.sim.phase(sc)
+	return types.GitStatus{
+		HasChanges:    len(p.files) > 0,
+		ModifiedFiles: p.files,
+		CommitCount:   p.commits,
+	}, nil
+}
+
+func (c *gitChecker) GetCurrentBranch(worktreePath string) (string, error) {
+	return filepath.Base(worktreePath), nil
+}
+
+// claudeChecker reports scripted Claude states; other operations are mocked
+type claudeChecker struct {
+	*claude.MockChecker
+	sim *Simulator
+}
+
+func (c *claudeChecker) GetStatus(worktreePath string) types.ClaudeStatus {
+	sc, ok := c.sim.byPath[worktreePath]
+	if !ok {
+		return types.ClaudeStatus{State: types.ClaudeUnknown, Availability: types.AvailVeryStale}
+	}
+	now := c.sim.now()
+	p, inPhase := sc.at(now.Sub(c.sim.start))
+	return types.ClaudeStatus{
+		State:         p.claude,
+		Availability:  types.AvailCurrent,
+		LastMessage:   now.Add(-inPhase),
+		StatusMessage: p.message,
+	}
+}
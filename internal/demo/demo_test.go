@@ -0,0 +1,74 @@
+package demo
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestNewManager_DerivesSimulatedSessions(t *testing.T) {
+	sm, cleanup, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	dataDir := sm.GetDataDir()
+
+	sessions, err := sm.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	if len(sessions) != len(scripts) {
+		t.Fatalf("got %d sessions, want %d", len(sessions), len(scripts))
+	}
+
+	states := make(map[types.ClaudeState]bool)
+	dead := 0
+	for _, session := range sessions {
+		states[session.ClaudeStatus.State] = true
+		if !session.IsAlive {
+			dead++
+		}
+	}
+	if len(states) < 3 {
+		t.Errorf("demo starts with Claude states %v, want a varied dashboard", states)
+	}
+	if dead == 0 {
+		t.Error("demo should include a dead session")
+	}
+
+	cleanup()
+	if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+		t.Errorf("cleanup left %s behind", dataDir)
+	}
+}
+
+func TestSimulator_CyclesThroughPhases(t *testing.T) {
+	sim := NewSimulator(t.TempDir())
+	now := sim.start
+	sim.now = func() time.Time { return now }
+
+	sc := scripts[0]
+	seen := make(map[types.ClaudeState]bool)
+	var total time.Duration
+	for _, p := range sc.phases {
+		total += p.duration
+	}
+
+	for elapsed := time.Duration(0); elapsed < total; elapsed += time.Second {
+		now = sim.start.Add(elapsed)
+		seen[sim.phase(sc).claude] = true
+	}
+	for _, want := range []types.ClaudeState{types.ClaudeWorking, types.ClaudeWaiting, types.ClaudeComplete} {
+		if !seen[want] {
+			t.Errorf("%s never reached %s", sc.name, want)
+		}
+	}
+
+	// The script loops
+	now = sim.start.Add(total)
+	if got := sim.phase(sc); got.claude != sc.phases[0].claude || got.message != sc.phases[0].message {
+		t.Errorf("after one loop phase = %+v, want the first phase again", got)
+	}
+}
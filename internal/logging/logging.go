@@ -0,0 +1,180 @@
+// Package logging is cwt's diagnostic log. Each package gets a logger tagged
+// with its component from For; where the log goes and how much of it is kept
+// is configured once at startup with Configure, from the --verbose, --debug
+// and --log-file flags, the CWT_LOG and CWT_LOG_FILE environment variables
+// and the log section of the config file.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Environment variables that configure the log when no flag does
+const (
+	EnvLevel = "CWT_LOG"      // Level: debug, info, warn or error
+	EnvFile  = "CWT_LOG_FILE" // File the log is appended to instead of stderr
+)
+
+// DefaultLevel keeps the log quiet unless something went wrong
+const DefaultLevel = slog.LevelWarn
+
+// Options configure the log
+type Options struct {
+	Level slog.Level
+	File  string // Appended to, creating its directory; empty logs to stderr
+}
+
+var (
+	mu      sync.Mutex
+	options = Options{Level: DefaultLevel}
+	file    *os.File // Open log file, when logging to one
+
+	// current is the handler loggers from For write through, swapped by Configure
+	current atomic.Pointer[slog.Handler]
+)
+
+func init() {
+	handler := newHandler(os.Stderr, DefaultLevel)
+	current.Store(&handler)
+}
+
+// newHandler writes records at level or above to w
+func newHandler(w io.Writer, level slog.Level) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+}
+
+// Configure sends the log to opts.File, or stderr, keeping records at
+// opts.Level or above. Loggers already returned by For follow the change.
+func Configure(opts Options) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var out io.Writer = os.Stderr
+	var opened *os.File
+	if opts.File != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.File), 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		out, opened = f, f
+	}
+
+	handler := newHandler(out, opts.Level)
+	current.Store(&handler)
+	if file != nil {
+		file.Close()
+	}
+	file, options = opened, opts
+	return nil
+}
+
+// ToFileIfStderr moves the log to path when it is going to stderr, for
+// full-screen interfaces that stderr output would draw over
+func ToFileIfStderr(path string) error {
+	mu.Lock()
+	opts := options
+	mu.Unlock()
+
+	if opts.File != "" {
+		return nil
+	}
+	opts.File = path
+	return Configure(opts)
+}
+
+// CurrentOptions returns how the log is configured
+func CurrentOptions() Options {
+	mu.Lock()
+	defer mu.Unlock()
+	return options
+}
+
+// Close closes the log file, sending anything logged afterwards to stderr
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	handler := newHandler(os.Stderr, options.Level)
+	current.Store(&handler)
+	options.File = ""
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	return err
+}
+
+// ParseLevel parses a level name: debug, info, warn (or warning) or error
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", name)
+}
+
+// For returns the logger of a component, like "git" or "tui"
+func For(component string) *slog.Logger {
+	return slog.New(&switchHandler{}).With("component", component)
+}
+
+// switchHandler writes through whichever handler Configure set last, so
+// package-level loggers created before Configure runs still follow it
+type switchHandler struct {
+	// wrap re-applies the attributes and groups added with With and WithGroup
+	wrap func(slog.Handler) slog.Handler
+}
+
+func (h *switchHandler) handler() slog.Handler {
+	handler := *current.Load()
+	if h.wrap != nil {
+		handler = h.wrap(handler)
+	}
+	return handler
+}
+
+func (h *switchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return (*current.Load()).Enabled(ctx, level)
+}
+
+func (h *switchHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler().Handle(ctx, record)
+}
+
+func (h *switchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prev := h.wrap
+	return &switchHandler{wrap: func(handler slog.Handler) slog.Handler {
+		if prev != nil {
+			handler = prev(handler)
+		}
+		return handler.WithAttrs(attrs)
+	}}
+}
+
+func (h *switchHandler) WithGroup(name string) slog.Handler {
+	prev := h.wrap
+	return &switchHandler{wrap: func(handler slog.Handler) slog.Handler {
+		if prev != nil {
+			handler = prev(handler)
+		}
+		return handler.WithGroup(name)
+	}}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{" warning ", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"chatty", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v (error %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() {
+		Close()
+		Configure(Options{Level: DefaultLevel})
+	})
+
+	// Loggers created before Configure follow it
+	logger := For("git").With("worktree", "feature")
+
+	path := filepath.Join(t.TempDir(), "logs", "cwt.log")
+	if err := Configure(Options{Level: slog.LevelInfo, File: path}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	logger.Debug("hidden")
+	logger.Info("creating worktree", "branch", "feature")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	log := string(data)
+	if strings.Contains(log, "hidden") {
+		t.Errorf("log = %q, want records below the level left out", log)
+	}
+	for _, want := range []string{"level=INFO", `msg="creating worktree"`, "component=git", "worktree=feature", "branch=feature"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log = %q, want it to contain %q", log, want)
+		}
+	}
+}
+
+func TestToFileIfStderr(t *testing.T) {
+	t.Cleanup(func() {
+		Close()
+		Configure(Options{Level: DefaultLevel})
+	})
+
+	dir := t.TempDir()
+	if err := Configure(Options{Level: slog.LevelDebug}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if err := ToFileIfStderr(filepath.Join(dir, "tui.log")); err != nil {
+		t.Fatalf("ToFileIfStderr() error = %v", err)
+	}
+	if got := CurrentOptions(); got.File != filepath.Join(dir, "tui.log") || got.Level != slog.LevelDebug {
+		t.Errorf("options = %+v, want the log moved to the file at the same level", got)
+	}
+
+	// A file chosen explicitly is kept
+	if err := ToFileIfStderr(filepath.Join(dir, "other.log")); err != nil {
+		t.Fatalf("ToFileIfStderr() error = %v", err)
+	}
+	if got := CurrentOptions(); got.File != filepath.Join(dir, "tui.log") {
+		t.Errorf("options = %+v, want the existing log file kept", got)
+	}
+}
@@ -0,0 +1,195 @@
+package operations
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+// maxDerivedNameLength keeps session names derived from tasks readable
+const maxDerivedNameLength = 40
+
+// BatchTask is one session to create in a batch
+type BatchTask struct {
+	Name   string `yaml:"name"`
+	Prompt string `yaml:"prompt"`
+}
+
+// ParseBatchFile reads the sessions to create from a batch file. YAML files
+// (.yaml or .yml) hold a list of names and prompts; any other file has one
+// task per line, with blank lines and lines starting with # ignored.
+// Sessions without a name are named after their task.
+func ParseBatchFile(path string) ([]BatchTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+
+	var tasks []BatchTask
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &tasks); err != nil {
+			return nil, fmt.Errorf("invalid batch file %s: %w", path, err)
+		}
+	default:
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			tasks = append(tasks, BatchTask{Prompt: line})
+		}
+	}
+
+	return normalizeBatch(tasks)
+}
+
+// normalizeBatch names unnamed tasks after their prompt and makes sure no
+// two sessions in the batch end up with the same name
+func normalizeBatch(tasks []BatchTask) ([]BatchTask, error) {
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("batch file has no tasks")
+	}
+
+	used := make(map[string]bool)
+	for i := range tasks {
+		tasks[i].Name = strings.TrimSpace(tasks[i].Name)
+		tasks[i].Prompt = strings.TrimSpace(tasks[i].Prompt)
+		if name := tasks[i].Name; name != "" {
+			if used[name] {
+				return nil, fmt.Errorf("session name '%s' appears more than once in the batch", name)
+			}
+			used[name] = true
+		}
+	}
+
+	for i := range tasks {
+		if tasks[i].Name != "" {
+			continue
+		}
+		if tasks[i].Prompt == "" {
+			return nil, fmt.Errorf("batch entry %d has neither a name nor a prompt", i+1)
+		}
+
+		base := SessionNameForTask(tasks[i].Prompt)
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[name] = true
+		tasks[i].Name = name
+	}
+	return tasks, nil
+}
+
+// SessionNameForTask derives a session name from a task description, like
+// "add-user-authentication" from "Add user authentication"
+func SessionNameForTask(task string) string {
+	words := strings.FieldsFunc(strings.ToLower(task), func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+	})
+
+	var name string
+	for _, word := range words {
+		next := word
+		if name != "" {
+			next = name + "-" + word
+		}
+		if len(next) > maxDerivedNameLength {
+			break
+		}
+		name = next
+	}
+
+	if name == "" || strings.Trim(name, "0123456789-") == "" {
+		// Nothing usable, or only numbers, which aren't valid session names
+		name = "task-" + name
+	}
+	return strings.Trim(name, "-")
+}
+
+// BatchStatus is where a batch session is in its creation
+type BatchStatus string
+
+const (
+	BatchPending  BatchStatus = "pending"
+	BatchCreating BatchStatus = "creating"
+	BatchCreated  BatchStatus = "created"
+	BatchFailed   BatchStatus = "failed"
+)
+
+// BatchResult is the state of one session of a batch
+type BatchResult struct {
+	Task     BatchTask
+	Status   BatchStatus
+	Err      error
+	Duration time.Duration
+}
+
+// CreateBatch creates the sessions of a batch, at most parallel at a time.
+// progress, if set, is called with each session's index and result whenever
+// its status changes; calls are never concurrent. Failures don't stop the
+// rest of the batch, but cancelling ctx rolls back the sessions being
+// created and fails the ones not started yet.
+func (s *SessionOperations) CreateBatch(ctx context.Context, tasks []BatchTask, parallel int, progress func(index int, result BatchResult)) []BatchResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]BatchResult, len(tasks))
+	for i, task := range tasks {
+		results[i] = BatchResult{Task: task, Status: BatchPending}
+	}
+
+	var mu sync.Mutex
+	update := func(index int, result BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[index] = result
+		if progress != nil {
+			progress(index, result)
+		}
+	}
+
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(index int, task BatchTask) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := ctx.Err(); err != nil {
+				update(index, BatchResult{Task: task, Status: BatchFailed, Err: err})
+				return
+			}
+
+			update(index, BatchResult{Task: task, Status: BatchCreating})
+			start := s.stateManager.Now()
+			err := s.CreateSessionContext(ctx, task.Name, state.CreateOptions{Task: task.Prompt})
+
+			result := BatchResult{Task: task, Status: BatchCreated, Duration: s.stateManager.Now().Sub(start)}
+			if err != nil {
+				result.Status = BatchFailed
+				result.Err = err
+			}
+			logger.Info("batch task finished", "session", task.Name, "status", result.Status, "duration", result.Duration, "error", err)
+			update(index, result)
+		}(i, task)
+	}
+	wg.Wait()
+
+	return results
+}
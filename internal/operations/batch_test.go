@@ -0,0 +1,110 @@
+package operations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+func TestSessionNameForTask(t *testing.T) {
+	tests := []struct {
+		task string
+		want string
+	}{
+		{"Add user authentication", "add-user-authentication"},
+		{"Fix bug #123: login fails!", "fix-bug-123-login-fails"},
+		{"Refactor the database layer so that every query goes through one place", "refactor-the-database-layer-so-that"},
+		{"2024", "task-2024"},
+		{"???", "task"},
+		{"Übersetzung aktualisieren", "bersetzung-aktualisieren"},
+	}
+
+	for _, tt := range tests {
+		if got := SessionNameForTask(tt.task); got != tt.want {
+			t.Errorf("SessionNameForTask(%q) = %q, want %q", tt.task, got, tt.want)
+		}
+	}
+}
+
+func TestParseBatchFile(t *testing.T) {
+	dir := t.TempDir()
+
+	text := filepath.Join(dir, "tasks.txt")
+	os.WriteFile(text, []byte("# today\nAdd login\n\nFix tests\nAdd login\n"), 0644)
+	tasks, err := ParseBatchFile(text)
+	if err != nil {
+		t.Fatalf("ParseBatchFile(txt) error = %v", err)
+	}
+	want := []BatchTask{
+		{Name: "add-login", Prompt: "Add login"},
+		{Name: "fix-tests", Prompt: "Fix tests"},
+		{Name: "add-login-2", Prompt: "Add login"},
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("tasks = %+v, want %+v", tasks, want)
+	}
+	for i := range want {
+		if tasks[i] != want[i] {
+			t.Errorf("task %d = %+v, want %+v", i, tasks[i], want[i])
+		}
+	}
+
+	yamlFile := filepath.Join(dir, "tasks.yaml")
+	os.WriteFile(yamlFile, []byte("- name: auth\n  prompt: Add authentication\n- prompt: Update dependencies\n- name: spike\n"), 0644)
+	tasks, err = ParseBatchFile(yamlFile)
+	if err != nil {
+		t.Fatalf("ParseBatchFile(yaml) error = %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].Name != "auth" || tasks[1].Name != "update-dependencies" || tasks[2].Prompt != "" {
+		t.Errorf("tasks = %+v", tasks)
+	}
+
+	os.WriteFile(yamlFile, []byte("- name: auth\n- name: auth\n"), 0644)
+	if _, err := ParseBatchFile(yamlFile); err == nil {
+		t.Error("duplicate names in a batch should be rejected")
+	}
+
+	os.WriteFile(text, []byte("# nothing yet\n"), 0644)
+	if _, err := ParseBatchFile(text); err == nil {
+		t.Error("a batch without tasks should be rejected")
+	}
+}
+
+func TestSessionOperations_CreateBatch(t *testing.T) {
+	manager := state.NewManager(state.Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	tasks := []BatchTask{
+		{Name: "first", Prompt: "Do the first thing"},
+		{Name: "main"}, // Reserved, so it fails
+		{Name: "third"},
+	}
+
+	var updates []BatchStatus
+	results := NewSessionOperations(manager).CreateBatch(context.Background(), tasks, 1, func(index int, result BatchResult) {
+		updates = append(updates, result.Status)
+	})
+
+	if results[0].Status != BatchCreated || results[1].Status != BatchFailed || results[1].Err == nil || results[2].Status != BatchCreated {
+		t.Errorf("results = %+v, want the reserved name to fail and the rest created", results)
+	}
+	if len(updates) != 6 || updates[0] != BatchCreating {
+		t.Errorf("progress updates = %v, want creating and a result for each session", updates)
+	}
+
+	cores, _ := manager.CoreSessions()
+	if len(cores) != 2 || cores[0].Task != "Do the first thing" {
+		t.Errorf("sessions = %+v, want first (with its task) and third", cores)
+	}
+}
@@ -57,6 +57,7 @@ func (c *CleanupOperations) FindAndCleanupStaleResources(dryRun bool) (*CleanupS
 			stats.Failed++
 			errMsg := fmt.Sprintf("Failed to delete session %s: %v", session.Core.Name, err)
 			stats.Errors = append(stats.Errors, errMsg)
+			logger.Info("failed to clean up stale session", "session", session.Core.Name, "error", err)
 		} else {
 			stats.Cleaned++
 		}
@@ -80,6 +81,7 @@ func (c *CleanupOperations) FindAndCleanupStaleResources(dryRun bool) (*CleanupS
 			stats.Failed++
 			errMsg := fmt.Sprintf("Failed to kill tmux session %s: %v", tmuxSession, err)
 			stats.Errors = append(stats.Errors, errMsg)
+			logger.Info("failed to kill orphaned tmux session", "session", tmuxSession, "error", err)
 		} else {
 			stats.Cleaned++
 		}
@@ -103,6 +105,7 @@ func (c *CleanupOperations) FindAndCleanupStaleResources(dryRun bool) (*CleanupS
 			stats.Failed++
 			errMsg := fmt.Sprintf("Failed to remove worktree %s: %v", worktree, err)
 			stats.Errors = append(stats.Errors, errMsg)
+			logger.Info("failed to remove orphaned worktree", "worktree", worktree, "error", err)
 		} else {
 			stats.Cleaned++
 		}
@@ -163,10 +166,11 @@ func (c *CleanupOperations) findOrphanedWorktrees() ([]string, error) {
 		return nil, fmt.Errorf("failed to get current sessions: %w", err)
 	}
 
-	// Create a map of active session names
+	// Create a map of the worktree directories of active sessions, which
+	// renamed sessions keep under their old name
 	activeNames := make(map[string]bool)
 	for _, session := range sessions {
-		activeNames[session.Core.Name] = true
+		activeNames[filepath.Base(session.Core.WorktreePath)] = true
 	}
 
 	// Find orphaned worktrees
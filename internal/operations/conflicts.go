@@ -0,0 +1,42 @@
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// conflictsListed is how many conflicted files a ConflictsError names
+const conflictsListed = 5
+
+// ConflictsError is returned when a session can't be published or merged
+// because its worktree is in the middle of a merge, rebase or cherry-pick
+// with conflicts left to resolve
+type ConflictsError struct {
+	Session string
+	Files   []types.ConflictedFile
+}
+
+func (e *ConflictsError) Error() string {
+	names := make([]string, 0, conflictsListed)
+	for i, file := range e.Files {
+		if i == conflictsListed {
+			names = append(names, fmt.Sprintf("and %d more", len(e.Files)-conflictsListed))
+			break
+		}
+		names = append(names, file.String())
+	}
+	return fmt.Sprintf("session '%s' has unresolved conflicts in %d file(s): %s; "+
+		"resolve them in its worktree and 'git add' them, or abort with 'git merge --abort' (or 'git rebase --abort')",
+		e.Session, len(e.Files), strings.Join(names, ", "))
+}
+
+// CheckConflicts returns a ConflictsError if a session's worktree has
+// unresolved merge conflicts
+func CheckConflicts(session types.Session) error {
+	if !session.GitStatus.HasConflicts() {
+		return nil
+	}
+	return &ConflictsError{Session: session.Core.Name, Files: session.GitStatus.ConflictedFiles}
+}
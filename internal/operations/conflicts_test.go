@@ -0,0 +1,31 @@
+package operations
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestCheckConflicts(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "feature"}}
+	if err := CheckConflicts(session); err != nil {
+		t.Errorf("CheckConflicts() = %v, want nil without conflicts", err)
+	}
+
+	session.GitStatus.ConflictedFiles = []types.ConflictedFile{
+		{Path: "main.go", Kind: types.ConflictBothModified},
+		{Path: "go.sum", Kind: types.ConflictDeletedByUs},
+	}
+	err := CheckConflicts(session)
+	var conflictsErr *ConflictsError
+	if !errors.As(err, &conflictsErr) || len(conflictsErr.Files) != 2 {
+		t.Fatalf("CheckConflicts() = %v, want a ConflictsError for both files", err)
+	}
+	for _, want := range []string{"'feature'", "main.go (both modified)", "go.sum (deleted by us)", "git merge --abort"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't mention %q", err, want)
+		}
+	}
+}
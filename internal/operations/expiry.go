@@ -0,0 +1,225 @@
+package operations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// ExpiryPolicy decides when idle sessions are archived and when archived
+// sessions are deleted. A zero duration turns that policy off.
+type ExpiryPolicy struct {
+	ArchiveIdle    time.Duration // Archive sessions with no activity for this long
+	DeleteArchived time.Duration // Delete archived sessions this long after archiving
+	WarnBefore     time.Duration // How early an expiration counts as upcoming
+}
+
+// Enabled reports whether either policy is turned on
+func (p ExpiryPolicy) Enabled() bool {
+	return p.ArchiveIdle > 0 || p.DeleteArchived > 0
+}
+
+// ExpiryAction is what happens to a session when it expires
+type ExpiryAction string
+
+const (
+	ExpiryArchive ExpiryAction = "archive" // Move an idle session to the archive
+	ExpiryDelete  ExpiryAction = "delete"  // Delete an archived session and its branch
+)
+
+// Expiration is a session the policy acts on now or soon
+type Expiration struct {
+	SessionID string
+	Name      string
+	Action    ExpiryAction
+	Due       time.Time
+	Blocked   string // Why the action can't run, like uncommitted changes
+}
+
+// Describe says what will happen and when, relative to now
+func (e Expiration) Describe(now time.Time) string {
+	verb := "archived"
+	if e.Action == ExpiryDelete {
+		verb = "deleted with its branch"
+	}
+
+	var when string
+	if until := e.Due.Sub(now); until > 0 {
+		when = "in " + NewStatusFormat().FormatDuration(until)
+	} else {
+		when = "now"
+	}
+
+	description := fmt.Sprintf("%s %s", verb, when)
+	if e.Blocked != "" {
+		description += fmt.Sprintf(" (blocked: %s)", e.Blocked)
+	}
+	return description
+}
+
+// SessionExpiration returns when an active session will be archived, if
+// that is due now or within the warning window. Paused sessions are idle on
+// purpose and never expire, and a session's own idle timeout extends the
+// policy's.
+func (p ExpiryPolicy) SessionExpiration(session types.Session, now time.Time) (Expiration, bool) {
+	if p.ArchiveIdle <= 0 || session.Core.IsPaused() {
+		return Expiration{}, false
+	}
+
+	due := session.LastActivity.Add(max(p.ArchiveIdle, session.Core.IdleTimeout))
+	if due.Sub(now) > p.WarnBefore {
+		return Expiration{}, false
+	}
+
+	expiration := Expiration{
+		SessionID: session.Core.ID,
+		Name:      session.Core.Name,
+		Action:    ExpiryArchive,
+		Due:       due,
+	}
+	switch {
+	case session.GitStatus.HasChanges:
+		expiration.Blocked = "uncommitted changes"
+	case session.GitStatus.HasError() && session.GitStatus.ErrorKind != types.GitErrorMissingWorktree:
+		expiration.Blocked = "git status can't be read"
+	}
+	return expiration, true
+}
+
+// ArchiveExpiration returns when an archived session will be deleted, if
+// that is due now or within the warning window
+func (p ExpiryPolicy) ArchiveExpiration(archived types.ArchivedSession, now time.Time) (Expiration, bool) {
+	if p.DeleteArchived <= 0 {
+		return Expiration{}, false
+	}
+
+	due := archived.ArchivedAt.Add(p.DeleteArchived)
+	if due.Sub(now) > p.WarnBefore {
+		return Expiration{}, false
+	}
+
+	return Expiration{
+		SessionID: archived.Core.ID,
+		Name:      archived.Core.Name,
+		Action:    ExpiryDelete,
+		Due:       due,
+	}, true
+}
+
+// Plan splits the expirations of sessions and archived sessions into those
+// due now and those coming up within the warning window, soonest first
+func (p ExpiryPolicy) Plan(sessions []types.Session, archived []types.ArchivedSession, now time.Time) (due, upcoming []Expiration) {
+	add := func(expiration Expiration) {
+		if expiration.Due.After(now) {
+			upcoming = append(upcoming, expiration)
+		} else {
+			due = append(due, expiration)
+		}
+	}
+
+	for _, session := range sessions {
+		if expiration, ok := p.SessionExpiration(session, now); ok {
+			add(expiration)
+		}
+	}
+	for _, record := range archived {
+		if expiration, ok := p.ArchiveExpiration(record, now); ok {
+			add(expiration)
+		}
+	}
+
+	for _, list := range [][]Expiration{due, upcoming} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Due.Before(list[j].Due) })
+	}
+	return due, upcoming
+}
+
+// ExpiryStats tracks the results of enforcing the expiry policy
+type ExpiryStats struct {
+	Archived int
+	Deleted  int
+	Skipped  int
+	Failed   int
+	Errors   []string
+}
+
+// ExpiryOperations provides business logic for the session expiry policy
+type ExpiryOperations struct {
+	stateManager *state.Manager
+	policy       ExpiryPolicy
+}
+
+// NewExpiryOperations creates a new ExpiryOperations instance
+func NewExpiryOperations(sm *state.Manager, policy ExpiryPolicy) *ExpiryOperations {
+	return &ExpiryOperations{
+		stateManager: sm,
+		policy:       policy,
+	}
+}
+
+// Plan returns the expirations due now and those coming up soon
+func (e *ExpiryOperations) Plan(now time.Time) (due, upcoming []Expiration, err error) {
+	sessions, err := e.stateManager.DeriveFreshSessions()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	archived, err := e.stateManager.ArchivedSessions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	due, upcoming = e.policy.Plan(sessions, archived, now)
+	return due, upcoming, nil
+}
+
+// Apply archives or deletes each due session. Blocked expirations are
+// skipped and stay due until whatever blocks them is resolved.
+func (e *ExpiryOperations) Apply(due []Expiration) *ExpiryStats {
+	stats := &ExpiryStats{
+		Errors: make([]string, 0),
+	}
+
+	for _, expiration := range due {
+		if expiration.Blocked != "" {
+			stats.Skipped++
+			continue
+		}
+
+		var err error
+		switch expiration.Action {
+		case ExpiryArchive:
+			reason := fmt.Sprintf("idle for %s", NewStatusFormat().FormatDuration(e.policy.ArchiveIdle))
+			if err = e.stateManager.ArchiveSession(expiration.SessionID, reason); err == nil {
+				stats.Archived++
+			}
+		case ExpiryDelete:
+			if err = e.stateManager.DeleteArchivedSession(expiration.SessionID); err == nil {
+				stats.Deleted++
+			}
+		}
+
+		if err != nil {
+			stats.Failed++
+			stats.Errors = append(stats.Errors, fmt.Sprintf("Failed to %s session %s: %v", expiration.Action, expiration.Name, err))
+		}
+	}
+
+	return stats
+}
+
+// Enforce archives and deletes every session that is due now
+func (e *ExpiryOperations) Enforce() (*ExpiryStats, error) {
+	if !e.policy.Enabled() {
+		return &ExpiryStats{Errors: make([]string, 0)}, nil
+	}
+
+	due, _, err := e.Plan(e.stateManager.Now())
+	if err != nil {
+		return nil, err
+	}
+	return e.Apply(due), nil
+}
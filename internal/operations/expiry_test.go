@@ -0,0 +1,136 @@
+package operations
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/clock"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestExpiryPolicy_Plan(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	policy := ExpiryPolicy{ArchiveIdle: 7 * day, DeleteArchived: 30 * day, WarnBefore: day}
+
+	session := func(name string, idle time.Duration, changes bool) types.Session {
+		return types.Session{
+			Core:         types.CoreSession{ID: name, Name: name},
+			LastActivity: now.Add(-idle),
+			GitStatus:    types.GitStatus{HasChanges: changes},
+		}
+	}
+	sessions := []types.Session{
+		session("active", time.Hour, false),
+		session("idle", 8*day, false),
+		session("dirty", 10*day, true),
+		session("soon", 7*day-time.Hour, false),
+	}
+	archived := []types.ArchivedSession{
+		{Core: types.CoreSession{ID: "old", Name: "old"}, ArchivedAt: now.Add(-35 * day)},
+		{Core: types.CoreSession{ID: "recent", Name: "recent"}, ArchivedAt: now.Add(-2 * day)},
+	}
+
+	due, upcoming := policy.Plan(sessions, archived, now)
+
+	var dueNames []string
+	for _, expiration := range due {
+		dueNames = append(dueNames, expiration.Name)
+	}
+	if want := []string{"old", "dirty", "idle"}; len(dueNames) != len(want) || dueNames[0] != want[0] || dueNames[1] != want[1] || dueNames[2] != want[2] {
+		t.Errorf("due = %v, want %v", dueNames, want)
+	}
+	if due[0].Action != ExpiryDelete || due[2].Action != ExpiryArchive {
+		t.Errorf("unexpected actions: %+v", due)
+	}
+	if due[1].Blocked == "" {
+		t.Error("a session with uncommitted changes should be blocked")
+	}
+
+	unreadable := session("unreadable", 8*day, false)
+	unreadable.GitStatus.ErrorKind = types.GitErrorCommandFailed
+	if expiration, ok := policy.SessionExpiration(unreadable, now); !ok || expiration.Blocked == "" {
+		t.Errorf("a session whose git status can't be read should be blocked, got %+v", expiration)
+	}
+	missing := session("missing", 8*day, false)
+	missing.GitStatus.ErrorKind = types.GitErrorMissingWorktree
+	if expiration, ok := policy.SessionExpiration(missing, now); !ok || expiration.Blocked != "" {
+		t.Errorf("a session whose worktree is gone should not be blocked, got %+v", expiration)
+	}
+
+	if len(upcoming) != 1 || upcoming[0].Name != "soon" {
+		t.Fatalf("upcoming = %+v, want only 'soon'", upcoming)
+	}
+	if got := upcoming[0].Describe(now); got != "archived in 1 hour" {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	if (ExpiryPolicy{WarnBefore: day}).Enabled() {
+		t.Error("a policy without durations should be disabled")
+	}
+	if due, upcoming := (ExpiryPolicy{}).Plan(sessions, archived, now); len(due)+len(upcoming) != 0 {
+		t.Error("a disabled policy should not expire anything")
+	}
+}
+
+func TestExpiryPolicy_SessionIdleTimeout(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	policy := ExpiryPolicy{ArchiveIdle: 2 * day, WarnBefore: day}
+
+	session := types.Session{
+		Core:         types.CoreSession{ID: "deps", Name: "deps", IdleTimeout: 5 * day},
+		LastActivity: now.Add(-3 * day),
+	}
+	if _, ok := policy.SessionExpiration(session, now); ok {
+		t.Error("a session's longer idle timeout should extend the policy's")
+	}
+
+	session.LastActivity = now.Add(-6 * day)
+	if _, ok := policy.SessionExpiration(session, now); !ok {
+		t.Error("a session idle past its own timeout should expire")
+	}
+
+	session.Core.IdleTimeout = time.Hour
+	session.LastActivity = now.Add(-3 * day)
+	if _, ok := policy.SessionExpiration(session, now); !ok {
+		t.Error("a shorter idle timeout should not shorten the policy's")
+	}
+}
+
+func TestExpiryOperations_Enforce(t *testing.T) {
+	day := 24 * time.Hour
+	mockClock := clock.NewMockClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	manager := state.NewManager(state.Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		Clock:         mockClock,
+	})
+	defer manager.Close()
+	if err := manager.CreateSession("idle"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	expiry := NewExpiryOperations(manager, ExpiryPolicy{ArchiveIdle: 7 * day, DeleteArchived: 30 * day})
+	mockClock.Advance(7*day - time.Minute)
+	if stats, err := expiry.Enforce(); err != nil || stats.Archived != 0 {
+		t.Fatalf("Enforce() = %+v, %v, want nothing archived before the session is idle long enough", stats, err)
+	}
+
+	mockClock.Advance(time.Minute)
+	if stats, err := expiry.Enforce(); err != nil || stats.Archived != 1 {
+		t.Fatalf("Enforce() = %+v, %v, want the idle session archived", stats, err)
+	}
+
+	mockClock.Advance(30 * day)
+	if stats, err := expiry.Enforce(); err != nil || stats.Deleted != 1 {
+		t.Fatalf("Enforce() = %+v, %v, want the archived session deleted", stats, err)
+	}
+}
@@ -5,17 +5,26 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jlaneve/cwt-cli/internal/clock"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
 // StatusFormat defines how to format session status information
 type StatusFormat struct {
-	// Add configuration options if needed in the future
+	Clock clock.Clock // Tells the time activity is aged against (default: the system clock)
 }
 
 // NewStatusFormat creates a new StatusFormat instance
 func NewStatusFormat() *StatusFormat {
-	return &StatusFormat{}
+	return &StatusFormat{Clock: clock.NewRealClock()}
+}
+
+// now returns the current time of the formatter's clock
+func (f *StatusFormat) now() time.Time {
+	if f.Clock == nil {
+		return time.Now()
+	}
+	return f.Clock.Now()
 }
 
 // FormatTmuxStatus formats the tmux status with appropriate emoji and color
@@ -26,6 +35,15 @@ func (f *StatusFormat) FormatTmuxStatus(isAlive bool) string {
 	return "🔴 dead"
 }
 
+// FormatSessionTmuxStatus formats a session's tmux status, telling a
+// paused session apart from a dead one
+func (f *StatusFormat) FormatSessionTmuxStatus(session types.Session) string {
+	if !session.IsAlive && session.Core.IsPaused() {
+		return "💤 paused"
+	}
+	return f.FormatTmuxStatus(session.IsAlive)
+}
+
 // FormatClaudeStatus formats the Claude status with appropriate emoji and details
 func (f *StatusFormat) FormatClaudeStatus(claudeStatus types.ClaudeStatus) string {
 	switch claudeStatus.State {
@@ -40,6 +58,10 @@ func (f *StatusFormat) FormatClaudeStatus(claudeStatus types.ClaudeStatus) strin
 		return "✅ complete"
 	case types.ClaudeIdle:
 		return "🟡 idle"
+	case types.ClaudeNotStarted:
+		return "⚪ not started"
+	case types.ClaudeNoAgent:
+		return "🚫 no claude"
 	case types.ClaudeUnknown:
 		return "❓ unknown"
 	default:
@@ -49,6 +71,19 @@ func (f *StatusFormat) FormatClaudeStatus(claudeStatus types.ClaudeStatus) strin
 
 // FormatGitStatus formats the git status with file change information
 func (f *StatusFormat) FormatGitStatus(gitStatus types.GitStatus) string {
+	if gitStatus.WorktreeBroken() {
+		return "💔 broken"
+	}
+	if gitStatus.HasError() {
+		return "❌ error"
+	}
+
+	if conflicts := len(gitStatus.ConflictedFiles); conflicts == 1 {
+		return "⚠ 1 conflict"
+	} else if conflicts > 1 {
+		return fmt.Sprintf("⚠ %d conflicts", conflicts)
+	}
+
 	if !gitStatus.HasChanges {
 		return "🟢 clean"
 	}
@@ -78,13 +113,250 @@ func (f *StatusFormat) FormatGitStatus(gitStatus types.GitStatus) string {
 	return fmt.Sprintf("🟡 %s", strings.Join(parts, ", "))
 }
 
+// FormatDivergence formats how many commits a branch is ahead of and behind
+// another, like "↑3 ↓1", or "up to date" when they point at the same commit
+func (f *StatusFormat) FormatDivergence(ahead, behind int) string {
+	switch {
+	case ahead == 0 && behind == 0:
+		return "up to date"
+	case behind == 0:
+		return fmt.Sprintf("↑%d", ahead)
+	case ahead == 0:
+		return fmt.Sprintf("↓%d", behind)
+	default:
+		return fmt.Sprintf("↑%d ↓%d", ahead, behind)
+	}
+}
+
+// FormatBranchSync formats where a session's branch stands against its base
+// branch and, when it tracks one, its upstream
+func (f *StatusFormat) FormatBranchSync(session types.Session) string {
+	git := session.GitStatus
+	sync := f.FormatDivergence(git.CommitCount, git.BehindCount)
+	if session.BaseBranch != "" {
+		sync += " vs " + session.BaseBranch
+	}
+	if git.Upstream != "" {
+		sync += fmt.Sprintf(", %s vs %s", f.FormatDivergence(git.UpstreamAhead, git.UpstreamBehind), git.Upstream)
+	}
+	return sync
+}
+
+// FormatStaging counts the staged and unstaged files of a working tree, or
+// returns "" when there are neither
+func (f *StatusFormat) FormatStaging(gitStatus types.GitStatus) string {
+	var parts []string
+	if n := len(gitStatus.StagedFiles); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d staged", n))
+	}
+	if n := len(gitStatus.UnstagedFiles); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d unstaged", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ClaudeHint suggests the next step for a session Claude hasn't started in,
+// or returns "" when there is nothing to suggest
+func ClaudeHint(session types.Session) string {
+	switch session.ClaudeStatus.State {
+	case types.ClaudeNotStarted:
+		return fmt.Sprintf("Claude hasn't started yet: attach with 'cwt attach %s' and start claude", session.Core.Name)
+	case types.ClaudeNoAgent:
+		return "Claude is not installed: install Claude Code, or set claude_executable in the config"
+	default:
+		return ""
+	}
+}
+
+// WorktreeHint suggests how to repair a session whose worktree is broken,
+// or returns "" when it isn't
+func WorktreeHint(session types.Session) string {
+	if !session.GitStatus.WorktreeBroken() {
+		return ""
+	}
+	return fmt.Sprintf("Worktree is broken: recreate it from branch %s with 'cwt repair %s'", session.BranchName(), session.Core.Name)
+}
+
+// RebaseHint suggests rebasing a session whose branch is behind its base
+// branch, or returns "" when it isn't
+func RebaseHint(session types.Session) string {
+	behind := session.GitStatus.BehindCount
+	if behind == 0 || session.GitStatus.HasError() {
+		return ""
+	}
+	return fmt.Sprintf("Behind %s by %d commit(s): bring them in with 'cwt rebase %s'", session.BaseBranch, behind, session.Core.Name)
+}
+
+// ReviewHint points out a session whose worktree changed since its diff
+// was last viewed, or returns "" when it didn't
+func ReviewHint(session types.Session) string {
+	if !session.ChangedSinceReview {
+		return ""
+	}
+	return fmt.Sprintf("New changes since its diff was last viewed: see just those with 'cwt diff %s --since-review'", session.Core.Name)
+}
+
+// PublishHint warns that a session's worktree changed since its branch was
+// last pushed, so its pull request lacks the latest work, or returns "" when
+// it didn't
+func PublishHint(session types.Session) string {
+	last := session.Core.LastPublish()
+	if !session.ChangedSincePublish || last == nil {
+		return ""
+	}
+	target := "the pushed branch"
+	if last.PullRequest != "" {
+		target = "the pull request"
+	}
+	return fmt.Sprintf("Changed since revision %d was pushed, so %s lacks the latest work: publish again with 'cwt publish %s'",
+		last.Revision, target, session.Core.Name)
+}
+
+// ConflictWarning warns that merging a session's branch into its base
+// branch would conflict, naming the files, or returns "" when it wouldn't
+func ConflictWarning(session types.Session) string {
+	files := session.GitStatus.BaseConflicts
+	if len(files) == 0 {
+		return ""
+	}
+	names := files
+	if len(names) > conflictsListed {
+		names = append(names[:conflictsListed:conflictsListed], fmt.Sprintf("and %d more", len(files)-conflictsListed))
+	}
+	return fmt.Sprintf("Will conflict with %s in %d file(s): %s; resolve them now with 'cwt rebase %s'",
+		session.BaseBranch, len(files), strings.Join(names, ", "), session.Core.Name)
+}
+
+// FormatCoverage formats the coverage measured by a session's follow-up
+// and how it changed from the base branch's, or returns "" when its
+// follow-up measured none
+func (f *StatusFormat) FormatCoverage(session types.Session) string {
+	followUp := session.Core.FollowUp
+	if followUp == nil || followUp.Result == nil || followUp.Result.Coverage == nil {
+		return ""
+	}
+	return followUp.Result.Coverage.Describe(followUp.Result.BaseCoverage, session.BaseBranch)
+}
+
+// FormatCost formats a session's estimated cost, "n/a" when its agent's
+// prices are unknown, flagged when it went over the cost warning
+func (f *StatusFormat) FormatCost(usage types.Usage, overCost bool) string {
+	switch {
+	case usage.Empty():
+		return "-"
+	case usage.Cost == 0:
+		return "n/a"
+	case overCost:
+		return "⚠️ " + types.FormatCost(usage.Cost)
+	default:
+		return types.FormatCost(usage.Cost)
+	}
+}
+
+// FormatUsage details a session's usage, like "1.2M tokens (800k in,
+// 400k out, 3.1M cached), ~$4.20"
+func (f *StatusFormat) FormatUsage(usage types.Usage) string {
+	details := fmt.Sprintf("%s in, %s out", types.FormatTokens(usage.InputTokens+usage.CacheWriteTokens), types.FormatTokens(usage.OutputTokens))
+	if usage.CacheReadTokens > 0 {
+		details += fmt.Sprintf(", %s cached", types.FormatTokens(usage.CacheReadTokens))
+	}
+	summary := fmt.Sprintf("%s tokens (%s)", types.FormatTokens(usage.Tokens()), details)
+	if usage.Cost > 0 {
+		summary += ", ~" + types.FormatCost(usage.Cost)
+	}
+	return summary
+}
+
+// FormatFollowUp formats the progress of a session's follow-up command
+func (f *StatusFormat) FormatFollowUp(followUp types.FollowUp) string {
+	switch {
+	case followUp.Pending():
+		return "⏳ " + followUp.Summary()
+	case followUp.Running():
+		return "🔄 " + followUp.Summary()
+	case followUp.Succeeded():
+		return "✅ " + followUp.Summary()
+	default:
+		return "❌ " + followUp.Summary()
+	}
+}
+
+// FormatPriority formats a session's priority, marking the ones off normal
+func (f *StatusFormat) FormatPriority(priority types.Priority) string {
+	switch priority {
+	case types.PriorityHigh:
+		return "🔺 high"
+	case types.PriorityLow:
+		return "🔽 low"
+	}
+	return string(types.PriorityNormal)
+}
+
+// FormatPublish formats one push of a session's branch: its revision, the
+// commit pushed, when, and the pull request it went to
+func (f *StatusFormat) FormatPublish(publish types.Publish) string {
+	commit := publish.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	text := fmt.Sprintf("rev %d: %s, %s", publish.Revision, commit, f.FormatActivity(publish.At))
+	if publish.PullRequest != "" {
+		if number := types.PullRequestNumber(publish.PullRequest); number > 0 {
+			text += fmt.Sprintf(" (PR #%d)", number)
+		} else {
+			text += " (PR)"
+		}
+	}
+	return text
+}
+
+// FormatPullRequest formats where a session's pull request stands: open or
+// not, then for an open one its review and CI checks
+func (f *StatusFormat) FormatPullRequest(pr types.PullRequest) string {
+	parts := []string{fmt.Sprintf("#%d", pr.Number)}
+	if pr.Number == 0 {
+		parts[0] = "PR"
+	}
+	switch {
+	case pr.Status.State == types.PullRequestMerged:
+		return strings.Join(append(parts, "🟣 merged"), " ")
+	case pr.Status.State == types.PullRequestClosed:
+		return strings.Join(append(parts, "⚫ closed"), " ")
+	case pr.Status.Draft:
+		parts = append(parts, "📝 draft")
+	default:
+		parts = append(parts, "🟢 open")
+	}
+
+	switch pr.Status.Review {
+	case types.ReviewApproved:
+		parts = append(parts, "✅ approved")
+	case types.ReviewChangesRequested:
+		parts = append(parts, "❗ changes requested")
+	case types.ReviewRequested:
+		parts = append(parts, "👀 review requested")
+	}
+	switch pr.Status.Checks {
+	case types.ChecksPassing:
+		parts = append(parts, "CI ✓")
+	case types.ChecksFailing:
+		parts = append(parts, "CI ✗")
+	case types.ChecksPending:
+		parts = append(parts, "CI …")
+	}
+	return strings.Join(parts, " ")
+}
+
 // FormatActivity formats the last activity time
 func (f *StatusFormat) FormatActivity(lastActivity time.Time) string {
 	if lastActivity.IsZero() {
 		return "never"
 	}
 
-	duration := time.Since(lastActivity)
+	duration := f.now().Sub(lastActivity)
+	if duration < time.Minute {
+		return f.FormatDuration(duration)
+	}
 	return f.FormatDuration(duration) + " ago"
 }
 
@@ -113,9 +385,63 @@ func (f *StatusFormat) FormatDuration(duration time.Duration) string {
 	}
 }
 
+// timelineIcons marks the events of a session's timeline by type
+var timelineIcons = map[string]string{
+	types.EventCreated:    "🆕",
+	types.EventAttached:   "🔗",
+	types.EventCommitted:  "📝",
+	types.EventMerged:     "🔀",
+	types.EventPublished:  "🚀",
+	types.EventPaused:     "💤",
+	types.EventResumed:    "▶️",
+	types.EventRenamed:    "✏️",
+	types.EventArchived:   "📦",
+	types.EventRestored:   "♻️",
+	types.EventRepaired:   "🛠️",
+	types.EventRebased:    "⤴️",
+	types.EventMergedBase: "🔃",
+	types.EventFollowUp:   "🔁",
+	types.EventApproved:   "👍",
+	types.EventStopped:    "⏹️",
+	types.EventHook:       "🪝",
+	RecoveredEvent:        "🩹",
+	"notification":        "🔔",
+	"stop":                "✅",
+	"preToolUse":          "🔧",
+	"postToolUse":         "🔧",
+}
+
+// FormatTimelineEvent describes one event of a session's timeline, without
+// its time
+func (f *StatusFormat) FormatTimelineEvent(event types.SessionEvent) string {
+	icon := timelineIcons[event.Type]
+	if icon == "" {
+		icon = "•"
+	}
+
+	detail := event.Message
+	if detail == "" {
+		tool, _ := event.Data["tool_name"].(string)
+		switch {
+		case event.Type == "preToolUse" && tool != "":
+			detail = "Using " + tool
+		case event.Type == "postToolUse" && tool != "":
+			detail = "Used " + tool
+		case event.Type == "stop":
+			detail = "Claude finished its turn"
+		}
+	}
+
+	detail, _, _ = strings.Cut(detail, "\n")
+	if detail == "" {
+		return fmt.Sprintf("%s %s", icon, event.Type)
+	}
+	return fmt.Sprintf("%s %-12s %s", icon, event.Type, detail)
+}
+
 // FormatSessionSummary creates a one-line summary of a session's status
 func (f *StatusFormat) FormatSessionSummary(session types.Session) string {
-	tmux := f.FormatTmuxStatus(session.IsAlive)
+	tmux := f.FormatSessionTmuxStatus(session)
 	claude := f.FormatClaudeStatus(session.ClaudeStatus)
 	git := f.FormatGitStatus(session.GitStatus)
 	activity := f.FormatActivity(session.LastActivity)
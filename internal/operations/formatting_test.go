@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jlaneve/cwt-cli/internal/clock"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
@@ -68,6 +69,16 @@ func TestStatusFormat_FormatClaudeStatus(t *testing.T) {
 			status:   types.ClaudeStatus{State: types.ClaudeUnknown},
 			expected: "❓ unknown",
 		},
+		{
+			name:     "not started",
+			status:   types.ClaudeStatus{State: types.ClaudeNotStarted},
+			expected: "⚪ not started",
+		},
+		{
+			name:     "no claude",
+			status:   types.ClaudeStatus{State: types.ClaudeNoAgent},
+			expected: "🚫 no claude",
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,6 +144,34 @@ func TestStatusFormat_FormatGitStatus(t *testing.T) {
 			},
 			expected: "🟡 changes",
 		},
+		{
+			name: "status could not be read",
+			status: types.GitStatus{
+				Error:     "git status failed in /tmp/wt: exit status 128",
+				ErrorKind: types.GitErrorCommandFailed,
+			},
+			expected: "❌ error",
+		},
+		{
+			name: "worktree missing",
+			status: types.GitStatus{
+				Error:     "worktree not found: /tmp/missing",
+				ErrorKind: types.GitErrorMissingWorktree,
+			},
+			expected: "💔 broken",
+		},
+		{
+			name: "merge conflicts",
+			status: types.GitStatus{
+				HasChanges:    true,
+				ModifiedFiles: []string{"test.go"},
+				ConflictedFiles: []types.ConflictedFile{
+					{Path: "main.go", Kind: types.ConflictBothModified},
+					{Path: "go.mod", Kind: types.ConflictDeletedByThem},
+				},
+			},
+			expected: "⚠ 2 conflicts",
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,6 +184,97 @@ func TestStatusFormat_FormatGitStatus(t *testing.T) {
 	}
 }
 
+func TestStatusFormat_FormatBranchSync(t *testing.T) {
+	formatter := NewStatusFormat()
+
+	tests := []struct {
+		name     string
+		session  types.Session
+		expected string
+	}{
+		{"even with base", types.Session{BaseBranch: "main"}, "up to date vs main"},
+		{"ahead", types.Session{BaseBranch: "main", GitStatus: types.GitStatus{CommitCount: 3}}, "↑3 vs main"},
+		{"behind", types.Session{BaseBranch: "main", GitStatus: types.GitStatus{BehindCount: 2}}, "↓2 vs main"},
+		{"diverged with upstream", types.Session{BaseBranch: "main", GitStatus: types.GitStatus{
+			CommitCount: 3, BehindCount: 1, Upstream: "origin/auth", UpstreamAhead: 1,
+		}}, "↑3 ↓1 vs main, ↑1 vs origin/auth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatter.FormatBranchSync(tt.session); result != tt.expected {
+				t.Errorf("FormatBranchSync() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	staging := formatter.FormatStaging(types.GitStatus{StagedFiles: []string{"a", "b"}, UnstagedFiles: []string{"b"}})
+	if staging != "2 staged, 1 unstaged" {
+		t.Errorf("FormatStaging() = %q, want %q", staging, "2 staged, 1 unstaged")
+	}
+
+	behind := types.Session{Core: types.CoreSession{Name: "auth"}, BaseBranch: "main", GitStatus: types.GitStatus{BehindCount: 2}}
+	if hint := RebaseHint(behind); hint != "Behind main by 2 commit(s): bring them in with 'cwt rebase auth'" {
+		t.Errorf("RebaseHint() = %q", hint)
+	}
+	if hint := RebaseHint(types.Session{BaseBranch: "main", GitStatus: types.GitStatus{CommitCount: 3}}); hint != "" {
+		t.Errorf("RebaseHint() = %q for a session ahead of main, want none", hint)
+	}
+}
+
+func TestReviewHint(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "auth", Review: &types.Review{Tree: "t1"}}}
+	if hint := ReviewHint(session); hint != "" {
+		t.Errorf("ReviewHint() = %q for a session unchanged since review, want none", hint)
+	}
+	session.ChangedSinceReview = true
+	if hint := ReviewHint(session); !strings.Contains(hint, "'cwt diff auth --since-review'") {
+		t.Errorf("ReviewHint() = %q", hint)
+	}
+}
+
+func TestPublishHint(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "auth", Publishes: []types.Publish{
+		{Revision: 1, Commit: "c1"},
+		{Revision: 2, Commit: "c2", PullRequest: "https://github.com/o/r/pull/12"},
+	}}}
+	if hint := PublishHint(session); hint != "" {
+		t.Errorf("PublishHint() = %q for a session unchanged since pushed, want none", hint)
+	}
+	session.ChangedSincePublish = true
+	hint := PublishHint(session)
+	if !strings.Contains(hint, "revision 2") || !strings.Contains(hint, "the pull request lacks") ||
+		!strings.Contains(hint, "'cwt publish auth'") {
+		t.Errorf("PublishHint() = %q", hint)
+	}
+
+	formatted := NewStatusFormat().FormatPublish(types.Publish{Revision: 2, Commit: "0123456789abcdef",
+		PullRequest: "https://github.com/o/r/pull/12", At: time.Now()})
+	if !strings.HasPrefix(formatted, "rev 2: 0123456, ") || !strings.HasSuffix(formatted, " (PR #12)") {
+		t.Errorf("FormatPublish() = %q", formatted)
+	}
+}
+
+func TestConflictWarning(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "auth"}, BaseBranch: "main"}
+	if warning := ConflictWarning(session); warning != "" {
+		t.Errorf("ConflictWarning() = %q without predicted conflicts, want none", warning)
+	}
+
+	session.GitStatus.BaseConflicts = []string{"login.go", "auth.go"}
+	if warning := ConflictWarning(session); warning != "Will conflict with main in 2 file(s): login.go, auth.go; resolve them now with 'cwt rebase auth'" {
+		t.Errorf("ConflictWarning() = %q", warning)
+	}
+
+	session.GitStatus.BaseConflicts = []string{"a", "b", "c", "d", "e", "f", "g"}
+	if warning := ConflictWarning(session); !strings.Contains(warning, "7 file(s): a, b, c, d, e, and 2 more;") {
+		t.Errorf("ConflictWarning() = %q, want the list cut short", warning)
+	}
+	if len(session.GitStatus.BaseConflicts) != 7 || session.GitStatus.BaseConflicts[5] != "f" {
+		t.Errorf("ConflictWarning() changed the session's files: %v", session.GitStatus.BaseConflicts)
+	}
+}
+
 func TestStatusFormat_FormatDuration(t *testing.T) {
 	formatter := NewStatusFormat()
 
@@ -173,9 +303,9 @@ func TestStatusFormat_FormatDuration(t *testing.T) {
 }
 
 func TestStatusFormat_FormatActivity(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
 	formatter := NewStatusFormat()
-
-	now := time.Now()
+	formatter.Clock = clock.NewMockClock(now)
 
 	tests := []struct {
 		name         string
@@ -183,6 +313,7 @@ func TestStatusFormat_FormatActivity(t *testing.T) {
 		expected     string
 	}{
 		{"never active", time.Time{}, "never"},
+		{"just now", now.Add(-10 * time.Second), "just now"},
 		{"5 minutes ago", now.Add(-5 * time.Minute), "5 minutes ago"},
 		{"1 hour ago", now.Add(-1 * time.Hour), "1 hour ago"},
 		{"2 days ago", now.Add(-48 * time.Hour), "2 days ago"},
@@ -273,3 +404,18 @@ func TestStatusFormat_FormatSessionList(t *testing.T) {
 		t.Errorf("FormatSessionList(detailed) missing session ID, got: %q", result)
 	}
 }
+
+func TestStatusFormat_FormatPullRequest(t *testing.T) {
+	formatter := NewStatusFormat()
+	pr := types.PullRequest{Number: 12, Status: types.PullRequestStatus{
+		State: types.PullRequestOpen, Review: types.ReviewRequested, Checks: types.ChecksPassing,
+	}}
+	if got := formatter.FormatPullRequest(pr); got != "#12 🟢 open 👀 review requested CI ✓" {
+		t.Errorf("FormatPullRequest() = %q", got)
+	}
+
+	pr.Status.State = types.PullRequestMerged
+	if got := formatter.FormatPullRequest(pr); got != "#12 🟣 merged" {
+		t.Errorf("FormatPullRequest() = %q, want only the merge", got)
+	}
+}
@@ -0,0 +1,50 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Issue holds the GitHub issue fields used to seed a session
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+}
+
+// FetchIssue loads an issue by number or URL using the GitHub CLI
+func FetchIssue(ref string) (*Issue, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("GitHub CLI (gh) not found in PATH")
+	}
+
+	cmd := exec.Command("gh", "issue", "view", ref, "--json", "number,title,body,url")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue %s: %w", ref, err)
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(output, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue %s: %w", ref, err)
+	}
+
+	return &issue, nil
+}
+
+// SessionName returns the default session name for the issue
+func (i *Issue) SessionName() string {
+	return fmt.Sprintf("issue-%d", i.Number)
+}
+
+// Task returns the initial Claude prompt for working on the issue
+func (i *Issue) Task() string {
+	task := fmt.Sprintf("Resolve GitHub issue #%d: %s", i.Number, i.Title)
+	if body := strings.TrimSpace(i.Body); body != "" {
+		task += "\n\n" + body
+	}
+	return task
+}
@@ -0,0 +1,26 @@
+package operations
+
+import "testing"
+
+func TestIssueSessionNameAndTask(t *testing.T) {
+	issue := &Issue{
+		Number: 42,
+		Title:  "Login fails on Safari",
+		Body:   "  Steps to reproduce...  ",
+		URL:    "https://github.com/org/repo/issues/42",
+	}
+
+	if got := issue.SessionName(); got != "issue-42" {
+		t.Errorf("SessionName() = %q, expected %q", got, "issue-42")
+	}
+
+	expected := "Resolve GitHub issue #42: Login fails on Safari\n\nSteps to reproduce..."
+	if got := issue.Task(); got != expected {
+		t.Errorf("Task() = %q, expected %q", got, expected)
+	}
+
+	issue.Body = ""
+	if got := issue.Task(); got != "Resolve GitHub issue #42: Login fails on Safari" {
+		t.Errorf("Task() without body = %q", got)
+	}
+}
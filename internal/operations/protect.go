@@ -0,0 +1,55 @@
+package operations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ProtectedOperation is a command that changes the main repository's
+// checkout or branches. In protected mode it runs only after the user types
+// its phrase, or with the token a dry run printed for it.
+type ProtectedOperation struct {
+	Phrase string   // What the user types to confirm, like "merge my-session into main"
+	Refs   []string // Refs the operation acts on; moving any of them changes the token
+}
+
+// SwitchOperation describes checking out a session's branch
+func SwitchOperation(branch string) ProtectedOperation {
+	return ProtectedOperation{
+		Phrase: fmt.Sprintf("switch to %s", branch),
+		Refs:   []string{"HEAD", branch},
+	}
+}
+
+// MergeOperation describes merging a session's branch into target
+func MergeOperation(sessionName, sessionBranch, target string, squash bool) ProtectedOperation {
+	phrase := fmt.Sprintf("merge %s into %s", sessionName, target)
+	if squash {
+		phrase = "squash " + phrase
+	}
+	return ProtectedOperation{Phrase: phrase, Refs: []string{sessionBranch, target}}
+}
+
+// Token returns the confirmation token for the operation in the current
+// repository state, so a token can't be reused once the refs have moved
+func (op ProtectedOperation) Token() string {
+	hash := sha256.New()
+	io.WriteString(hash, op.Phrase)
+	for _, ref := range op.Refs {
+		fmt.Fprintf(hash, "\x00%s=%s", ref, resolveRef(ref))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// resolveRef returns the commit a ref points to, or "" if it doesn't exist
+func resolveRef(ref string) string {
+	output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", ref).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,21 @@
+package operations
+
+import "testing"
+
+func TestProtectedOperation_Token(t *testing.T) {
+	merge := MergeOperation("fix", "cwt-test-no-such-branch", "main", false)
+	squash := MergeOperation("fix", "cwt-test-no-such-branch", "main", true)
+
+	if merge.Token() != merge.Token() {
+		t.Error("token should be stable for the same operation and state")
+	}
+	if merge.Token() == squash.Token() {
+		t.Error("different operations should have different tokens")
+	}
+	if len(merge.Token()) != 12 {
+		t.Errorf("token %q should be 12 characters", merge.Token())
+	}
+	if squash.Phrase != "squash merge fix into main" {
+		t.Errorf("Phrase = %q, want the squash named", squash.Phrase)
+	}
+}
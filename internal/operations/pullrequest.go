@@ -0,0 +1,19 @@
+package operations
+
+import (
+	"github.com/jlaneve/cwt-cli/internal/clients/forge"
+)
+
+// GetPullRequestURL returns the URL of the pull request of a branch checked
+// out in the given worktree, on the forge hosting origin. forgeKind
+// overrides detecting the forge from origin's URL.
+func GetPullRequestURL(worktreePath, branch, forgeKind string) (string, error) {
+	host, err := forge.ForRepository(worktreePath, forgeKind)
+	if err != nil {
+		return "", err
+	}
+	if err := host.Available(); err != nil {
+		return "", err
+	}
+	return host.PullRequestURL(worktreePath, branch)
+}
@@ -0,0 +1,319 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// Files of a recipe directory
+const (
+	RecipeFileName       = "recipe.yaml" // Description, settings, gates and post-actions
+	RecipePromptFileName = "prompt.md"   // Prompt template, when recipe.yaml has none
+)
+
+// Sources of the recipes that don't come from a recipe directory
+const (
+	RecipeBuiltin = "built-in" // The recipes cwt ships with
+	RecipeConfig  = "config"   // The recipes of the config, like the shared config's
+)
+
+// Gates and post-actions with a meaning of their own; any other gate is a
+// shell command, and a post-action starting with "!" runs a shell command,
+// both in the session's worktree
+const (
+	RecipeGateTest           = "test"          // The config's test_command
+	RecipeActionPublish      = "publish"       // Push and open a pull request
+	RecipeActionPublishDraft = "publish-draft" // Push and open a draft pull request
+)
+
+// Recipe is a kind of session cwt runs end to end: the task Claude starts
+// on, settings of its own, and what happens once Claude is done. Recipes
+// are directories holding a recipe.yaml, and the prompt in prompt.md.
+type Recipe struct {
+	Name        string            `yaml:"-"`
+	Description string            `yaml:"description"`
+	Prompt      string            `yaml:"prompt"`       // Template of Claude's task; see RecipeData
+	IdleTimeout time.Duration     `yaml:"idle_timeout"` // How long the session may sit idle before the expiry policy archives it
+	Gates       []string          `yaml:"gates"`        // Checks run when Claude completes, all of which must pass
+	PostActions []string          `yaml:"post_actions"` // Run in order once the gates pass
+	Config      map[string]any    `yaml:"config"`       // Config settings overridden while creating the session
+	Env         map[string]string `yaml:"env"`          // Extra environment of the session's tmux session
+	Source      string            `yaml:"-"`            // Directory the recipe was loaded from, or RecipeBuiltin
+}
+
+// RecipeData is what a recipe's prompt template can refer to
+type RecipeData struct {
+	Session    string // Name of the session
+	Task       string // Task given along with the recipe; added after the prompt when the template doesn't use it
+	BaseBranch string // Branch the session starts from
+}
+
+// builtinRecipes are the recipes cwt ships with
+var builtinRecipes = []Recipe{
+	{
+		Name:        "deps-update",
+		Description: "Update dependencies, fix what breaks, and open a PR once the tests pass",
+		Prompt: `Update this project's dependencies to their latest compatible versions, with
+its package manager (like go get -u ./... and go mod tidy, npm update, or
+cargo update). Then build the project, run its tests and fix whatever the
+updates broke. Keep the fixes to what the updates require, without other
+changes. Finish with a short summary of what was updated and what had to
+be fixed.`,
+		// Updates wait on slow installs and builds, and on a review after
+		IdleTimeout: 72 * time.Hour,
+		Gates:       []string{RecipeGateTest},
+		PostActions: []string{RecipeActionPublish},
+		Source:      RecipeBuiltin,
+	},
+}
+
+// RecipeRegistry holds the recipes sessions can be created from: the
+// built-in ones, then those of the config, then those of each recipe
+// directory, where a recipe replaces an earlier one of the same name
+type RecipeRegistry struct {
+	recipes []Recipe
+}
+
+// NewRecipeRegistry loads the built-in recipes, those the config defines
+// by name and those of the given directories, in order. Directories that
+// don't exist are skipped.
+func NewRecipeRegistry(configured map[string]map[string]any, dirs ...string) (*RecipeRegistry, error) {
+	byName := make(map[string]Recipe)
+	for _, recipe := range builtinRecipes {
+		byName[recipe.Name] = recipe
+	}
+	for name, settings := range configured {
+		recipe, err := configRecipe(name, settings)
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = recipe
+	}
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read recipe directory %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			recipe, err := LoadRecipe(filepath.Join(dir, entry.Name()))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			byName[recipe.Name] = recipe
+		}
+	}
+
+	registry := &RecipeRegistry{}
+	for _, recipe := range byName {
+		registry.recipes = append(registry.recipes, recipe)
+	}
+	sort.Slice(registry.recipes, func(i, j int) bool {
+		return registry.recipes[i].Name < registry.recipes[j].Name
+	})
+	return registry, nil
+}
+
+// LoadRecipe reads the recipe in a directory, named after the directory.
+// The error wraps os.ErrNotExist when the directory holds no recipe.yaml.
+func LoadRecipe(dir string) (Recipe, error) {
+	path := filepath.Join(dir, RecipeFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Recipe{}, fmt.Errorf("failed to read recipe %s: %w", path, err)
+	}
+
+	var recipe Recipe
+	if err := yaml.Unmarshal(data, &recipe); err != nil {
+		return Recipe{}, fmt.Errorf("invalid recipe %s: %w", path, err)
+	}
+	recipe.Name = filepath.Base(dir)
+	recipe.Source = dir
+
+	if strings.TrimSpace(recipe.Prompt) == "" {
+		prompt, err := os.ReadFile(filepath.Join(dir, RecipePromptFileName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return Recipe{}, fmt.Errorf("failed to read prompt of recipe '%s': %w", recipe.Name, err)
+		}
+		recipe.Prompt = strings.TrimSpace(string(prompt))
+	}
+
+	if err := recipe.validate(); err != nil {
+		return Recipe{}, fmt.Errorf("invalid recipe %s: %w", path, err)
+	}
+	return recipe, nil
+}
+
+// configRecipe reads a recipe the config defines, whose settings are those
+// of a recipe.yaml
+func configRecipe(name string, settings map[string]any) (Recipe, error) {
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return Recipe{}, fmt.Errorf("invalid recipe '%s' of the config: %w", name, err)
+	}
+	var recipe Recipe
+	if err := yaml.Unmarshal(data, &recipe); err != nil {
+		return Recipe{}, fmt.Errorf("invalid recipe '%s' of the config: %w", name, err)
+	}
+	recipe.Name = name
+	recipe.Source = RecipeConfig
+	if err := recipe.validate(); err != nil {
+		return Recipe{}, fmt.Errorf("invalid recipe '%s' of the config: %w", name, err)
+	}
+	return recipe, nil
+}
+
+// validate rejects a recipe that can't create a session
+func (r Recipe) validate() error {
+	if strings.TrimSpace(r.Prompt) == "" {
+		return fmt.Errorf("no prompt: set prompt or write %s", RecipePromptFileName)
+	}
+	if _, err := template.New(r.Name).Parse(r.Prompt); err != nil {
+		return fmt.Errorf("invalid prompt template: %w", err)
+	}
+	if r.IdleTimeout < 0 {
+		return fmt.Errorf("negative idle_timeout")
+	}
+	for _, gate := range r.Gates {
+		if strings.TrimSpace(gate) == "" {
+			return fmt.Errorf("empty gate")
+		}
+	}
+	for _, action := range r.PostActions {
+		switch {
+		case action == RecipeActionPublish, action == RecipeActionPublishDraft:
+		case strings.HasPrefix(action, "!") && strings.TrimSpace(action[1:]) != "":
+		default:
+			return fmt.Errorf("unknown post-action %q (valid: %s, %s, or !<shell command>)",
+				action, RecipeActionPublish, RecipeActionPublishDraft)
+		}
+	}
+	for name := range r.Env {
+		if !utils.IsEnvName(name) {
+			return fmt.Errorf("invalid env variable name %q", name)
+		}
+	}
+	if _, ok := r.Config["data_dir"]; ok {
+		return fmt.Errorf("config can't override data_dir")
+	}
+	return nil
+}
+
+// List returns the recipes, sorted by name
+func (r *RecipeRegistry) List() []Recipe {
+	return r.recipes
+}
+
+// Names lists the names of the recipes, for completion and messages
+func (r *RecipeRegistry) Names() []string {
+	names := make([]string, len(r.recipes))
+	for i, recipe := range r.recipes {
+		names[i] = recipe.Name
+	}
+	return names
+}
+
+// Find returns the recipe with a name
+func (r *RecipeRegistry) Find(name string) (Recipe, error) {
+	for _, recipe := range r.recipes {
+		if recipe.Name == name {
+			return recipe, nil
+		}
+	}
+	return Recipe{}, fmt.Errorf("unknown recipe '%s' (available: %s)", name, strings.Join(r.Names(), ", "))
+}
+
+// SessionName names a session created from the recipe without a name,
+// after the recipe and the day
+func (r Recipe) SessionName(now time.Time) string {
+	return fmt.Sprintf("%s-%s", r.Name, now.Format("20060102"))
+}
+
+// Task renders Claude's task in a session created from the recipe. A task
+// given along with the recipe that the template doesn't place is added
+// after it.
+func (r Recipe) Task(data RecipeData) (string, error) {
+	tmpl, err := template.New(r.Name).Parse(r.Prompt)
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt template of recipe '%s': %w", r.Name, err)
+	}
+	var task strings.Builder
+	if err := tmpl.Execute(&task, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt of recipe '%s': %w", r.Name, err)
+	}
+
+	extra := strings.TrimSpace(data.Task)
+	if extra == "" || strings.Contains(r.Prompt, ".Task") {
+		return task.String(), nil
+	}
+	return task.String() + "\n\nAlso: " + extra, nil
+}
+
+// ConfigOverrides returns the recipe's config settings as a YAML document,
+// or nil when it overrides none
+func (r Recipe) ConfigOverrides() ([]byte, error) {
+	if len(r.Config) == 0 {
+		return nil, nil
+	}
+	data, err := yaml.Marshal(r.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config of recipe '%s': %w", r.Name, err)
+	}
+	return data, nil
+}
+
+// FollowUp returns the command run in the session's worktree when Claude
+// completes: each gate, then the post-actions if all gates pass.
+// testCommand is the config's test_command, cwt the shell command running
+// cwt and repoDir the repository it runs from. It returns "" when the
+// recipe has neither gates nor post-actions.
+func (r Recipe) FollowUp(session, testCommand, cwt, repoDir string) (string, error) {
+	var steps []string
+	for _, gate := range r.Gates {
+		if gate == RecipeGateTest {
+			if strings.TrimSpace(testCommand) == "" {
+				return "", fmt.Errorf("recipe '%s' runs the tests when Claude is done; set test_command in the config", r.Name)
+			}
+			gate = testCommand
+		}
+		steps = append(steps, "( "+gate+" )")
+	}
+
+	for _, action := range r.PostActions {
+		switch action {
+		case RecipeActionPublish, RecipeActionPublishDraft:
+			flag := "--pr"
+			if action == RecipeActionPublishDraft {
+				flag = "--draft"
+			}
+			// Publishing finds the session from the repository, not its worktree
+			steps = append(steps, fmt.Sprintf("( cd %s && %s publish %s %s )",
+				utils.ShellQuote(repoDir), cwt, utils.ShellQuote(session), flag))
+		default:
+			steps = append(steps, "( "+strings.TrimPrefix(action, "!")+" )")
+		}
+	}
+	return strings.Join(steps, " && "), nil
+}
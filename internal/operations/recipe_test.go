@@ -0,0 +1,162 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecipeRegistry_Find(t *testing.T) {
+	registry, err := NewRecipeRegistry(nil, filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("NewRecipeRegistry() error = %v", err)
+	}
+	recipe, err := registry.Find("deps-update")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(recipe.Gates) == 0 || len(recipe.PostActions) == 0 || recipe.IdleTimeout <= 0 {
+		t.Errorf("deps-update should test, publish and wait longer: %+v", recipe)
+	}
+	if _, err := registry.Find("nope"); err == nil || !strings.Contains(err.Error(), "deps-update") {
+		t.Errorf("an unknown recipe should list the available ones, got %v", err)
+	}
+}
+
+func writeRecipe(t *testing.T, dir, name string, files map[string]string) {
+	t.Helper()
+	recipeDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(recipeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for file, contents := range files {
+		if err := os.WriteFile(filepath.Join(recipeDir, file), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNewRecipeRegistry_LoadsDirectories(t *testing.T) {
+	user, project := t.TempDir(), t.TempDir()
+	writeRecipe(t, user, "node-bump", map[string]string{
+		RecipeFileName: "description: Bump Node\nidle_timeout: 48h\ngates: [test, npm run lint]\npost_actions: [publish-draft]\nconfig:\n  test_command: npm test\n",
+		"prompt.md":    "Bump Node in {{.Session}}.\n",
+	})
+	writeRecipe(t, user, "deps-update", map[string]string{RecipeFileName: "description: Mine\nprompt: Update mine.\n"})
+	writeRecipe(t, project, "deps-update", map[string]string{RecipeFileName: "description: Project's\nprompt: Update ours.\n"})
+	writeRecipe(t, project, "notes", map[string]string{"README.md": "not a recipe"})
+
+	registry, err := NewRecipeRegistry(nil, user, project)
+	if err != nil {
+		t.Fatalf("NewRecipeRegistry() error = %v", err)
+	}
+	if got := strings.Join(registry.Names(), ","); got != "deps-update,node-bump" {
+		t.Errorf("Names() = %s", got)
+	}
+
+	deps, _ := registry.Find("deps-update")
+	if deps.Description != "Project's" || deps.Source != filepath.Join(project, "deps-update") {
+		t.Errorf("the project's recipe should win: %+v", deps)
+	}
+
+	node, _ := registry.Find("node-bump")
+	if node.Prompt != "Bump Node in {{.Session}}." || node.IdleTimeout != 48*time.Hour {
+		t.Errorf("unexpected recipe: %+v", node)
+	}
+	overrides, err := node.ConfigOverrides()
+	if err != nil || !strings.Contains(string(overrides), "test_command: npm test") {
+		t.Errorf("ConfigOverrides() = %q, %v", overrides, err)
+	}
+}
+
+func TestNewRecipeRegistry_ConfigRecipes(t *testing.T) {
+	project := t.TempDir()
+	writeRecipe(t, project, "lint-fix", map[string]string{RecipeFileName: "description: Project's\nprompt: Lint ours.\n"})
+	configured := map[string]map[string]any{
+		"lint-fix": {"description": "Team's", "prompt": "Lint."},
+		"release":  {"description": "Cut a release", "prompt": "Release {{.Session}}.", "idle_timeout": "24h", "gates": []any{"test"}},
+	}
+
+	registry, err := NewRecipeRegistry(configured, project)
+	if err != nil {
+		t.Fatalf("NewRecipeRegistry() error = %v", err)
+	}
+	release, _ := registry.Find("release")
+	if release.Source != RecipeConfig || release.IdleTimeout != 24*time.Hour || len(release.Gates) != 1 {
+		t.Errorf("unexpected recipe: %+v", release)
+	}
+	if lint, _ := registry.Find("lint-fix"); lint.Description != "Project's" {
+		t.Errorf("the project's recipe directory should win: %+v", lint)
+	}
+
+	configured["broken"] = map[string]any{"description": "No prompt"}
+	if _, err := NewRecipeRegistry(configured); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected the invalid recipe to be named, got %v", err)
+	}
+}
+
+func TestLoadRecipe_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"no-prompt":  "description: Nothing to do\n",
+		"bad-action": "prompt: Do it\npost_actions: [deploy]\n",
+		"bad-prompt": "prompt: 'Do {{.Session'\n",
+		"data-dir":   "prompt: Do it\nconfig:\n  data_dir: /tmp\n",
+		"bad-env":    "prompt: Do it\nenv:\n  1BAD: x\n",
+	}
+	for name, contents := range tests {
+		writeRecipe(t, dir, name, map[string]string{RecipeFileName: contents})
+		if _, err := LoadRecipe(filepath.Join(dir, name)); err == nil {
+			t.Errorf("expected recipe %s to be rejected", name)
+		}
+	}
+}
+
+func TestRecipe_SessionNameAndTask(t *testing.T) {
+	recipe := Recipe{Name: "deps-update", Prompt: "Update things."}
+
+	if got := recipe.SessionName(time.Date(2025, 3, 7, 9, 0, 0, 0, time.UTC)); got != "deps-update-20250307" {
+		t.Errorf("SessionName() = %q", got)
+	}
+	if got, _ := recipe.Task(RecipeData{Task: "  "}); got != "Update things." {
+		t.Errorf("Task() = %q", got)
+	}
+	if got, _ := recipe.Task(RecipeData{Task: "Skip react"}); got != "Update things.\n\nAlso: Skip react" {
+		t.Errorf("Task() = %q", got)
+	}
+
+	templated := Recipe{Name: "t", Prompt: "Work on {{.Task}} in {{.Session}} off {{.BaseBranch}}."}
+	got, err := templated.Task(RecipeData{Session: "s1", Task: "auth", BaseBranch: "main"})
+	if err != nil || got != "Work on auth in s1 off main." {
+		t.Errorf("Task() = %q, %v", got, err)
+	}
+}
+
+func TestRecipe_FollowUp(t *testing.T) {
+	recipe := Recipe{Name: "deps-update", Gates: []string{RecipeGateTest}, PostActions: []string{RecipeActionPublish}}
+
+	got, err := recipe.FollowUp("deps 1", "go test ./...", "cwt", "/src/my repo")
+	if err != nil {
+		t.Fatalf("FollowUp() error = %v", err)
+	}
+	if want := "( go test ./... ) && ( cd '/src/my repo' && cwt publish 'deps 1' --pr )"; got != want {
+		t.Errorf("FollowUp() = %q, want %q", got, want)
+	}
+
+	if _, err := recipe.FollowUp("deps", "", "cwt", "/src"); err == nil {
+		t.Error("the test gate needs a test command")
+	}
+
+	custom := Recipe{Name: "c", Gates: []string{"make lint"}, PostActions: []string{RecipeActionPublishDraft, "!make notify"}}
+	got, err = custom.FollowUp("c", "", "cwt", "/src")
+	if want := "( make lint ) && ( cd '/src' && cwt publish 'c' --draft ) && ( make notify )"; err != nil || got != want {
+		t.Errorf("FollowUp() = %q, %v, want %q", got, err, want)
+	}
+
+	got, err = Recipe{Name: "plain"}.FollowUp("plain", "", "cwt", "/src")
+	if err != nil || got != "" {
+		t.Errorf("a recipe without steps has no follow-up, got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// RecoveredEvent is the session event recorded when a crashed Claude is restarted
+const RecoveredEvent = "recovered"
+
+// minRestartInterval stops a session that crashes again right after being
+// restarted from restarting in a loop
+const minRestartInterval = 5 * time.Minute
+
+// recoveryPrompt is sent to the resumed conversation so Claude doesn't
+// assume whatever it was doing when it crashed was finished
+func recoveryPrompt(exit types.SessionExit) string {
+	return fmt.Sprintf("Your previous run %s and was restarted automatically. "+
+		"Before continuing, check the state of the task you were working on: "+
+		"verify which of your last changes were actually made, then carry on "+
+		"or tell me what is left.", exit.Summary())
+}
+
+// RestartCrashedSession resumes Claude's conversation in the pane of a
+// session that just crashed, asking it to verify the state of its last task.
+// It reports false without restarting when the exit wasn't a crash, when
+// there is no conversation to resume, or when the session was already
+// restarted moments before crashing again.
+func (s *SessionOperations) RestartCrashedSession(sessionID string, exit types.SessionExit) (bool, error) {
+	if exit.Cause != types.ExitCrash {
+		// A logout or an exhausted quota would only fail again
+		return false, nil
+	}
+
+	cores, err := s.stateManager.CoreSessions()
+	if err != nil {
+		return false, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	var core *types.CoreSession
+	for i := range cores {
+		if cores[i].ID == sessionID {
+			core = &cores[i]
+			break
+		}
+	}
+	if core == nil {
+		return false, fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	dataDir := s.stateManager.GetDataDir()
+	if recovered, ok := lastRecovery(dataDir, sessionID); ok && exit.Time.Sub(recovered) < minRestartInterval {
+		logger.Info("not restarting session that crashed again right after a restart", "session", core.Name)
+		return false, nil
+	}
+
+	if s.stateManager.AgentExecutable(*core) == "" {
+		return false, fmt.Errorf("%s executable not found in PATH", core.AgentName())
+	}
+	conversationID, err := s.stateManager.AgentChecker(*core).FindSessionID(core.WorktreePath)
+	if err != nil || conversationID == "" {
+		logger.Info("not restarting session with no conversation to resume", "session", core.Name, "error", err)
+		return false, nil
+	}
+
+	logger.Info("restarting crashed session", "session", core.Name, "exit", exit.Summary())
+
+	launch := s.stateManager.AgentLaunch(*core, conversationID, recoveryPrompt(exit))
+	if err := s.stateManager.GetTmuxChecker().RespawnSession(core.TmuxSession, core.WorktreePath, launch.Command, s.stateManager.SessionEnv(*core)...); err != nil {
+		return false, err
+	}
+	s.stateManager.TypePrompt(*core, launch)
+
+	types.RemoveSessionExit(dataDir, sessionID)
+	event := types.SessionEvent{
+		Type:        RecoveredEvent,
+		Time:        s.stateManager.Now(),
+		ClaudeState: "working",
+		Message:     fmt.Sprintf("Restarted after %s %s", core.AgentName(), exit.Summary()),
+	}
+	if _, err := types.AppendSessionEvent(dataDir, sessionID, event); err != nil {
+		return true, fmt.Errorf("failed to record recovery: %w", err)
+	}
+
+	s.stateManager.InvalidateStatus(sessionID)
+	return true, nil
+}
+
+// lastRecovery returns when a session was last restarted after a crash
+func lastRecovery(dataDir, sessionID string) (time.Time, bool) {
+	events, err := types.LoadSessionEvents(dataDir, sessionID)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == RecoveredEvent {
+			return events[i].Time, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,78 @@
+package operations
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestSessionOperations_RestartCrashedSession(t *testing.T) {
+	tmuxChecker := tmux.NewMockChecker()
+	manager := state.NewManager(state.Config{
+		DataDir:          filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       git.NewMockChecker(),
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+	})
+	defer manager.Close()
+
+	sessionOps := NewSessionOperations(manager)
+	if err := sessionOps.CreateSession("flaky"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	core := cores[0]
+	tmuxChecker.SetAlive(core.TmuxSession, false)
+
+	// Logging in again or waiting for the quota needs the user
+	if restarted, err := sessionOps.RestartCrashedSession(core.ID, types.NewSessionExit(time.Now(), 1, "Claude usage limit reached")); restarted || err != nil {
+		t.Errorf("quota exit: restarted = %v, err = %v; want it left alone", restarted, err)
+	}
+
+	crash := types.NewSessionExit(time.Now(), 1, "panic: boom")
+	types.SaveSessionExit(manager.GetDataDir(), core.ID, crash)
+	restarted, err := sessionOps.RestartCrashedSession(core.ID, crash)
+	if !restarted || err != nil {
+		t.Fatalf("crash: restarted = %v, err = %v; want a restart", restarted, err)
+	}
+
+	command := tmuxChecker.Respawned[core.TmuxSession]
+	if !strings.HasPrefix(command, "claude -r mock-session-") || !strings.Contains(command, "check the state of the task") {
+		t.Errorf("respawned with %q, want the conversation resumed with a recovery prompt", command)
+	}
+	if exit, _ := types.LoadSessionExit(manager.GetDataDir(), core.ID); exit != nil {
+		t.Error("the crash should be forgotten once the session runs again")
+	}
+
+	// The log also holds the session's creation
+	events, _ := types.LoadSessionEvents(manager.GetDataDir(), core.ID)
+	var recovered int
+	for _, event := range events {
+		if event.Type == RecoveredEvent {
+			recovered++
+		}
+	}
+	if recovered != 1 || events[len(events)-1].Type != RecoveredEvent {
+		t.Fatalf("events = %+v, want one recovered event", events)
+	}
+
+	// Crashing again right away isn't retried forever
+	tmuxChecker.Respawned = make(map[string]string)
+	again := types.NewSessionExit(time.Now(), 1, "panic: boom")
+	if restarted, err := sessionOps.RestartCrashedSession(core.ID, again); restarted || err != nil {
+		t.Errorf("crash loop: restarted = %v, err = %v; want it left dead", restarted, err)
+	}
+
+	again.Time = time.Now().Add(minRestartInterval + time.Minute)
+	if restarted, _ := sessionOps.RestartCrashedSession(core.ID, again); !restarted {
+		t.Error("a crash long after the last restart should be restarted")
+	}
+}
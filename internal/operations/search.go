@@ -0,0 +1,307 @@
+package operations
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// maxSearchFileSize skips large untracked files, which are rarely source
+const maxSearchFileSize = 1024 * 1024
+
+// SearchOptions controls what a search across sessions looks for and where
+type SearchOptions struct {
+	Pattern     string
+	Regex       bool   // Pattern is a regular expression instead of a plain string
+	IgnoreCase  bool   // Match regardless of case
+	Transcripts bool   // Also search the sessions' Claude transcripts
+	FilesOnly   bool   // Only match the paths of changed files, from the changes index
+	Base        string // Branch the sessions' changes are compared against
+}
+
+// SearchSource is where a search match was found
+type SearchSource string
+
+const (
+	SearchFile       SearchSource = "file"       // A changed file's path matches
+	SearchAdded      SearchSource = "added"      // A line the session added
+	SearchRemoved    SearchSource = "removed"    // A line the session removed
+	SearchTranscript SearchSource = "transcript" // A line of a Claude transcript
+)
+
+// SearchMatch is one place a session matched a search
+type SearchMatch struct {
+	Source SearchSource `json:"source"`
+	File   string       `json:"file"`           // Changed file, or the transcript's path
+	Line   int          `json:"line,omitempty"` // Line in the file or transcript entry
+	Text   string       `json:"text,omitempty"`
+}
+
+// SessionSearchResult holds the matches in one session
+type SessionSearchResult struct {
+	Session string        `json:"session"`
+	Matches []SearchMatch `json:"matches"`
+	Error   string        `json:"error,omitempty"` // Set when the session couldn't be searched
+}
+
+// newSearchMatcher returns a function reporting whether text matches the
+// search pattern
+func newSearchMatcher(opts SearchOptions) (func(string) bool, error) {
+	if opts.Pattern == "" {
+		return nil, fmt.Errorf("search pattern cannot be empty")
+	}
+
+	expr := opts.Pattern
+	if !opts.Regex {
+		if !opts.IgnoreCase {
+			pattern := opts.Pattern
+			return func(text string) bool { return strings.Contains(text, pattern) }, nil
+		}
+		expr = regexp.QuoteMeta(expr)
+	}
+	if opts.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search pattern: %w", err)
+	}
+	return re.MatchString, nil
+}
+
+// Search looks for a pattern in the changes every session made: the paths of
+// changed files, the lines added or removed since the session branched off
+// the base branch (committed or not, including untracked files), and
+// optionally what was said and done in its Claude transcripts. Only sessions
+// with matches are returned, in session order.
+func (s *SessionOperations) Search(opts SearchOptions) ([]SessionSearchResult, error) {
+	match, err := newSearchMatcher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.stateManager.CoreSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var scanner *claude.SessionScanner
+	if opts.Transcripts {
+		scanner = claude.NewSessionScanner()
+	}
+
+	// The changes index says which sessions changed anything at all, and
+	// which files, without running git in every worktree
+	changes, err := s.stateManager.ChangedFilesBySession()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SessionSearchResult
+	for _, session := range sessions {
+		result := SessionSearchResult{Session: session.Name}
+
+		files, indexed := changes[session.ID]
+		switch {
+		case opts.FilesOnly && !indexed:
+			result.Error = "failed to read changed files"
+		case opts.FilesOnly:
+			for _, file := range files {
+				if match(file.Path) {
+					result.Matches = append(result.Matches, SearchMatch{Source: SearchFile, File: file.Path})
+				}
+			}
+		case indexed && len(files) == 0:
+			// Nothing changed, so there is no diff to search
+		default:
+			matches, err := searchWorktreeChanges(session.WorktreePath, opts.Base, match)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Matches = matches
+		}
+
+		if scanner != nil {
+			matches, err := searchSessionTranscripts(scanner, session, match)
+			if err != nil && result.Error == "" {
+				result.Error = err.Error()
+			}
+			result.Matches = append(result.Matches, matches...)
+		}
+
+		if len(result.Matches) > 0 || result.Error != "" {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// searchWorktreeChanges matches the changes of a worktree against the point
+// it branched off base, so changes made on base since don't count
+func searchWorktreeChanges(worktreePath, base string, match func(string) bool) ([]SearchMatch, error) {
+	if _, err := os.Stat(worktreePath); err != nil {
+		return nil, fmt.Errorf("worktree not found: %s", worktreePath)
+	}
+
+	target := "HEAD"
+	if base != "" {
+		cmd := exec.Command("git", "merge-base", base, "HEAD")
+		cmd.Dir = worktreePath
+		if output, err := cmd.Output(); err == nil {
+			target = strings.TrimSpace(string(output))
+		}
+	}
+
+	cmd := exec.Command("git", "diff", "--no-color", "--no-ext-diff", "-U0", target)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %w", err)
+	}
+	matches := searchDiff(output, match)
+
+	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	cmd.Dir = worktreePath
+	output, err = cmd.Output()
+	if err != nil {
+		return matches, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, file := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if file != "" {
+			matches = append(matches, searchUntrackedFile(worktreePath, file, match)...)
+		}
+	}
+	return matches, nil
+}
+
+// searchDiff matches the file paths and changed lines of a unified diff
+func searchDiff(diff []byte, match func(string) bool) []SearchMatch {
+	var matches []SearchMatch
+	var file string
+	var oldLine, newLine int
+	inHeader := false // Between "diff --git" and the first hunk, where ---/+++ name the file
+
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 64*1024), maxSearchFileSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			file, inHeader = "", true
+		case inHeader && strings.HasPrefix(line, "--- "):
+			if path := diffPath(line[4:]); path != "" {
+				file = path
+			}
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			if path := diffPath(line[4:]); path != "" {
+				file = path
+			}
+			if match(file) {
+				matches = append(matches, SearchMatch{Source: SearchFile, File: file})
+			}
+		case strings.HasPrefix(line, "@@ "):
+			oldLine, newLine = parseHunkHeader(line)
+			inHeader = false
+		case strings.HasPrefix(line, "+"):
+			if match(line[1:]) {
+				matches = append(matches, SearchMatch{Source: SearchAdded, File: file, Line: newLine, Text: strings.TrimSpace(line[1:])})
+			}
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			if match(line[1:]) {
+				matches = append(matches, SearchMatch{Source: SearchRemoved, File: file, Line: oldLine, Text: strings.TrimSpace(line[1:])})
+			}
+			oldLine++
+		}
+	}
+	return matches
+}
+
+// diffPath returns the path of a diff's ---/+++ header, or "" for /dev/null
+func diffPath(header string) string {
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		header = unquoted
+	}
+	if header == "/dev/null" {
+		return ""
+	}
+	if len(header) > 2 && (header[:2] == "a/" || header[:2] == "b/") {
+		return header[2:]
+	}
+	return header
+}
+
+// parseHunkHeader returns the first old and new line numbers of a hunk
+// header like "@@ -12,3 +14,5 @@ func main() {"
+func parseHunkHeader(header string) (oldLine, newLine int) {
+	for _, field := range strings.Fields(header)[1:] {
+		if field == "@@" {
+			break
+		}
+		start, _, _ := strings.Cut(field[1:], ",")
+		n, _ := strconv.Atoi(start)
+		switch field[0] {
+		case '-':
+			oldLine = n
+		case '+':
+			newLine = n
+		}
+	}
+	return oldLine, newLine
+}
+
+// searchUntrackedFile matches the path and lines of a file git doesn't know
+// about yet, all of which the session added
+func searchUntrackedFile(worktreePath, file string, match func(string) bool) []SearchMatch {
+	var matches []SearchMatch
+	if match(file) {
+		matches = append(matches, SearchMatch{Source: SearchFile, File: file})
+	}
+
+	path := filepath.Join(worktreePath, file)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxSearchFileSize {
+		return matches
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 {
+		return matches // Unreadable or binary
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if match(line) {
+			matches = append(matches, SearchMatch{Source: SearchAdded, File: file, Line: i + 1, Text: strings.TrimSpace(line)})
+		}
+	}
+	return matches
+}
+
+// searchSessionTranscripts matches the Claude transcripts of a session's worktree
+func searchSessionTranscripts(scanner *claude.SessionScanner, session types.CoreSession, match func(string) bool) ([]SearchMatch, error) {
+	transcripts, err := scanner.FindSessionsForDirectory(session.WorktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []SearchMatch
+	for _, transcript := range transcripts {
+		found, err := claude.SearchTranscript(transcript.FilePath, match)
+		if err != nil {
+			return matches, err
+		}
+		for _, m := range found {
+			matches = append(matches, SearchMatch{Source: SearchTranscript, File: transcript.FilePath, Line: m.Entry, Text: m.Text})
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,135 @@
+package operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSearchDiff(t *testing.T) {
+	diff := `diff --git a/auth/login.go b/auth/login.go
+index 1111111..2222222 100644
+--- a/auth/login.go
++++ b/auth/login.go
+@@ -10 +10,2 @@ func Login() {
+-	// TODO(auth): check expiry
++	checkExpiry(token)
++	// TODO(auth): refresh tokens
+@@ -40,0 +42 @@ func Logout() {
++-- not a header
+diff --git a/old/auth.go b/old/auth.go
+deleted file mode 100644
+--- a/old/auth.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package auth
+`
+	match, err := newSearchMatcher(SearchOptions{Pattern: "auth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := searchDiff([]byte(diff), match)
+	want := []SearchMatch{
+		{Source: SearchFile, File: "auth/login.go"},
+		{Source: SearchRemoved, File: "auth/login.go", Line: 10, Text: "// TODO(auth): check expiry"},
+		{Source: SearchAdded, File: "auth/login.go", Line: 11, Text: "// TODO(auth): refresh tokens"},
+		{Source: SearchFile, File: "old/auth.go"},
+		{Source: SearchRemoved, File: "old/auth.go", Line: 1, Text: "package auth"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("searchDiff() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("match %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewSearchMatcher(t *testing.T) {
+	tests := []struct {
+		opts SearchOptions
+		text string
+		want bool
+	}{
+		{SearchOptions{Pattern: "TODO(auth)"}, "// TODO(auth): x", true},
+		{SearchOptions{Pattern: "todo(auth)"}, "// TODO(auth): x", false},
+		{SearchOptions{Pattern: "todo(auth)", IgnoreCase: true}, "// TODO(auth): x", true},
+		{SearchOptions{Pattern: `func (New|Make)Client`, Regex: true}, "func MakeClient() {", true},
+		{SearchOptions{Pattern: `func (New|Make)Client`}, "func MakeClient() {", false},
+	}
+	for _, tt := range tests {
+		match, err := newSearchMatcher(tt.opts)
+		if err != nil {
+			t.Fatalf("newSearchMatcher(%+v) error = %v", tt.opts, err)
+		}
+		if got := match(tt.text); got != tt.want {
+			t.Errorf("%+v matching %q = %v, want %v", tt.opts, tt.text, got, tt.want)
+		}
+	}
+
+	if _, err := newSearchMatcher(SearchOptions{Pattern: "("}); err != nil {
+		t.Errorf("a plain pattern should never be an invalid regex, got %v", err)
+	}
+	if _, err := newSearchMatcher(SearchOptions{Pattern: "(", Regex: true}); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+}
+
+func TestSearchWorktreeChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("main.go", "package main\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	git("checkout", "-q", "-b", "session")
+	write("main.go", "package main\n\nfunc validateToken() {}\n")
+	git("commit", "-q", "-am", "committed change")
+
+	// Changes made on main after the session branched off aren't the session's
+	git("checkout", "-q", "main")
+	write("other.go", "package main\n\nfunc validateToken2() {}\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "main change")
+	git("checkout", "-q", "session")
+	write("notes.md", "validateToken needs tests\n")
+
+	match, _ := newSearchMatcher(SearchOptions{Pattern: "validateToken"})
+	matches, err := searchWorktreeChanges(dir, "main", match)
+	if err != nil {
+		t.Fatalf("searchWorktreeChanges() error = %v", err)
+	}
+
+	want := []SearchMatch{
+		{Source: SearchAdded, File: "main.go", Line: 3, Text: "func validateToken() {}"},
+		{Source: SearchAdded, File: "notes.md", Line: 1, Text: "validateToken needs tests"},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("matches = %+v, want %+v", matches, want)
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("match %d = %+v, want %+v", i, matches[i], want[i])
+		}
+	}
+}
@@ -1,16 +1,22 @@
 package operations
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"strings"
 	"syscall"
 
+	"github.com/jlaneve/cwt-cli/internal/logging"
 	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
+// logger is the operations package's diagnostic log
+var logger = logging.For("operations")
+
 // SessionOperations provides business logic for session management
 type SessionOperations struct {
 	stateManager *state.Manager
@@ -28,11 +34,27 @@ func (s *SessionOperations) CreateSession(name string) error {
 	return s.stateManager.CreateSession(name)
 }
 
+// CreateSessionWithOptions creates a new session with a task and metadata
+func (s *SessionOperations) CreateSessionWithOptions(name string, opts state.CreateOptions) error {
+	return s.stateManager.CreateSessionWithOptions(name, opts)
+}
+
+// CreateSessionContext creates a new session, rolling it back if ctx is
+// cancelled before it's ready
+func (s *SessionOperations) CreateSessionContext(ctx context.Context, name string, opts state.CreateOptions) error {
+	return s.stateManager.CreateSessionContext(ctx, name, opts)
+}
+
 // DeleteSession deletes the session with the given ID
 func (s *SessionOperations) DeleteSession(sessionID string) error {
 	return s.stateManager.DeleteSession(sessionID)
 }
 
+// DeleteSessionWithOptions deletes a session, keeping its branch if asked
+func (s *SessionOperations) DeleteSessionWithOptions(sessionID string, opts state.DeleteOptions) (state.DeleteResult, error) {
+	return s.stateManager.DeleteSessionWithOptions(sessionID, opts)
+}
+
 // FindSessionByName finds a session by its name
 // Returns the session and its ID, or an error if not found
 func (s *SessionOperations) FindSessionByName(name string) (*types.Session, string, error) {
@@ -50,6 +72,41 @@ func (s *SessionOperations) FindSessionByName(name string) (*types.Session, stri
 	return nil, "", fmt.Errorf("session '%s' not found", name)
 }
 
+// MatchSessions selects the sessions named by patterns, in the order of
+// sessions. A pattern is a session name or a glob like "feat-*" (see
+// path.Match). It is an error for a name not to exist or a glob to match
+// nothing, so a typo can't silently select less than intended.
+func MatchSessions(sessions []types.Session, patterns []string) ([]types.Session, error) {
+	selected := make(map[string]bool)
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+
+		matched := false
+		for _, session := range sessions {
+			if ok, _ := path.Match(pattern, session.Core.Name); ok {
+				selected[session.Core.ID] = true
+				matched = true
+			}
+		}
+		if !matched {
+			if strings.ContainsAny(pattern, "*?[") {
+				return nil, fmt.Errorf("no sessions match '%s'", pattern)
+			}
+			return nil, fmt.Errorf("session '%s' not found", pattern)
+		}
+	}
+
+	var matches []types.Session
+	for _, session := range sessions {
+		if selected[session.Core.ID] {
+			matches = append(matches, session)
+		}
+	}
+	return matches, nil
+}
+
 // FindSessionByID finds a session by its ID
 func (s *SessionOperations) FindSessionByID(sessionID string) (*types.Session, error) {
 	sessions, err := s.stateManager.DeriveFreshSessions()
@@ -74,21 +131,34 @@ func (s *SessionOperations) GetAllSessions() ([]types.Session, error) {
 // RecreateDeadSession recreates a tmux session for a session that has died
 // This handles Claude session resumption if a previous session exists
 func (s *SessionOperations) RecreateDeadSession(session *types.Session) error {
-	claudeExec := FindClaudeExecutable()
-	if claudeExec == "" {
-		return fmt.Errorf("claude executable not found in PATH")
+	if session.Core.IsPaused() {
+		return s.stateManager.ResumeSession(session.Core.ID)
 	}
 
-	command := claudeExec
+	if s.stateManager.AgentExecutable(session.Core) == "" {
+		return fmt.Errorf("%s executable not found in PATH", session.Core.AgentName())
+	}
 
-	// Check if there's an existing Claude session to resume
-	if existingSessionID, err := s.stateManager.GetClaudeChecker().FindSessionID(session.Core.WorktreePath); err == nil && existingSessionID != "" {
-		command = fmt.Sprintf("%s -r %s", claudeExec, existingSessionID)
+	// Resume the existing conversation of the agent, if there is one
+	existingSessionID, err := s.stateManager.AgentChecker(session.Core).FindSessionID(session.Core.WorktreePath)
+	if err != nil {
+		existingSessionID = ""
 	}
+	command := s.stateManager.AgentLaunch(session.Core, existingSessionID, "").Command
 
 	// Create the tmux session
 	tmuxChecker := s.stateManager.GetTmuxChecker()
-	return tmuxChecker.CreateSession(session.Core.TmuxSession, session.Core.WorktreePath, command)
+	if err := tmuxChecker.CreateSession(session.Core.TmuxSession, session.Core.WorktreePath, command, s.stateManager.SessionEnv(session.Core)...); err != nil {
+		return err
+	}
+
+	// Forget how the previous run ended and record how this one does
+	types.RemoveSessionExit(s.stateManager.GetDataDir(), session.Core.ID)
+	s.stateManager.InstallExitHook(session.Core)
+
+	// The session is alive again; don't let a cached "dead" status linger
+	s.stateManager.InvalidateStatus(session.Core.ID)
+	return nil
 }
 
 // FindClaudeExecutable searches for the Claude CLI executable in common locations
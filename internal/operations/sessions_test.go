@@ -1,14 +1,17 @@
 package operations
 
 import (
+	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jlaneve/cwt-cli/internal/clients/claude"
 	"github.com/jlaneve/cwt-cli/internal/clients/git"
 	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
 	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
 func TestSessionOperations_CreateSession(t *testing.T) {
@@ -95,6 +98,45 @@ func TestSessionOperations_FindSessionByName(t *testing.T) {
 	}
 }
 
+func TestMatchSessions(t *testing.T) {
+	var sessions []types.Session
+	for i, name := range []string{"feat-login", "fix-crash", "feat-search"} {
+		sessions = append(sessions, types.Session{Core: types.CoreSession{ID: fmt.Sprint(i), Name: name}})
+	}
+	names := func(matches []types.Session) string {
+		var out []string
+		for _, session := range matches {
+			out = append(out, session.Core.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		patterns []string
+		want     string
+		wantErr  string
+	}{
+		{[]string{"fix-crash"}, "fix-crash", ""},
+		{[]string{"feat-*"}, "feat-login,feat-search", ""},
+		{[]string{"feat-search", "f*"}, "feat-login,fix-crash,feat-search", ""},
+		{[]string{"missing"}, "", "session 'missing' not found"},
+		{[]string{"bug-*"}, "", "no sessions match 'bug-*'"},
+		{[]string{"feat-["}, "", "invalid pattern"},
+	}
+	for _, tt := range tests {
+		matches, err := MatchSessions(sessions, tt.patterns)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("MatchSessions(%v) error = %v, want %q", tt.patterns, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || names(matches) != tt.want {
+			t.Errorf("MatchSessions(%v) = %s, %v; want %s", tt.patterns, names(matches), err, tt.want)
+		}
+	}
+}
+
 func TestSessionOperations_FindSessionByID(t *testing.T) {
 	tmpDir := t.TempDir()
 	dataDir := filepath.Join(tmpDir, ".cwt")
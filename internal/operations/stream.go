@@ -0,0 +1,197 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// EventStream turns session state into a stream of records: a snapshot of
+// every session, then the sessions added, updated and removed and the
+// timeline events recorded since, found by polling
+type EventStream struct {
+	sm      *state.Manager
+	dataDir string
+	seen    map[string]*streamedSession // By session ID
+}
+
+// streamedSession is what the stream last reported about a session
+type streamedSession struct {
+	name      string
+	output    []byte // SessionOutput as JSON, to spot changes
+	logOffset int64  // How much of its event log was streamed
+}
+
+// NewEventStream creates an EventStream for the sessions of sm
+func NewEventStream(sm *state.Manager) *EventStream {
+	return &EventStream{
+		sm:      sm,
+		dataDir: sm.GetDataDir(),
+		seen:    make(map[string]*streamedSession),
+	}
+}
+
+// Snapshot returns a record of every session. Changes are reported relative
+// to it: events already in the timelines are not streamed again.
+func (s *EventStream) Snapshot() (types.StreamRecord, error) {
+	sessions, err := s.sm.DeriveFreshSessions()
+	if err != nil {
+		return types.StreamRecord{}, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	record := s.newStreamRecord(types.StreamSnapshot)
+	s.seen = make(map[string]*streamedSession)
+	for _, session := range sessions {
+		output := types.NewSessionOutput(session)
+		record.Sessions = append(record.Sessions, output)
+
+		seen, err := s.track(session, output)
+		if err != nil {
+			return types.StreamRecord{}, err
+		}
+		if info, err := os.Stat(types.SessionEventLogPath(s.dataDir, session.Core.ID)); err == nil {
+			seen.logOffset = info.Size()
+		}
+	}
+	return record, nil
+}
+
+// Poll returns records for what changed since the snapshot or the last poll:
+// sessions removed, then sessions added or updated, each followed by the
+// events recorded for it
+func (s *EventStream) Poll() ([]types.StreamRecord, error) {
+	sessions, err := s.sm.DeriveFreshSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var records []types.StreamRecord
+	current := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		current[session.Core.ID] = true
+	}
+	var removed []string
+	for id := range s.seen {
+		if !current[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		record := s.newStreamRecord(types.StreamSessionRemoved)
+		record.SessionID, record.Name = id, s.seen[id].name
+		records = append(records, record)
+		delete(s.seen, id)
+	}
+
+	for _, session := range sessions {
+		output := types.NewSessionOutput(session)
+		var previous []byte
+		if seen, ok := s.seen[session.Core.ID]; ok {
+			previous = seen.output
+		}
+		seen, err := s.track(session, output)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case previous == nil:
+			records = append(records, s.sessionRecord(types.StreamSessionAdded, output))
+		case !bytes.Equal(previous, seen.output):
+			records = append(records, s.sessionRecord(types.StreamSessionUpdated, output))
+		}
+
+		events, offset, err := types.LoadSessionEventsAfter(s.dataDir, session.Core.ID, s.logStart(session.Core.ID, seen.logOffset))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read events of session '%s': %w", session.Core.Name, err)
+		}
+		seen.logOffset = offset
+		for i := range events {
+			record := s.newStreamRecord(types.StreamEvent)
+			record.SessionID, record.Name = session.Core.ID, session.Core.Name
+			record.Event = &events[i]
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// Follow emits the snapshot, then polls every interval and emits what
+// changed, until ctx is cancelled or emit fails
+func (s *EventStream) Follow(ctx context.Context, interval time.Duration, emit func(types.StreamRecord) error) error {
+	snapshot, err := s.Snapshot()
+	if err != nil {
+		return err
+	}
+	if err := emit(snapshot); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		records, err := s.Poll()
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// track remembers what was last reported about a session, keeping how much
+// of its event log was streamed
+func (s *EventStream) track(session types.Session, output types.SessionOutput) (*streamedSession, error) {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session '%s': %w", session.Core.Name, err)
+	}
+
+	seen, ok := s.seen[session.Core.ID]
+	if !ok {
+		seen = &streamedSession{}
+		s.seen[session.Core.ID] = seen
+	}
+	seen.name, seen.output = session.Core.Name, data
+	return seen, nil
+}
+
+// logStart is where to read on in a session's event log: where the stream
+// left off, or the start again if the log was replaced by a shorter one
+func (s *EventStream) logStart(sessionID string, offset int64) int64 {
+	info, err := os.Stat(types.SessionEventLogPath(s.dataDir, sessionID))
+	if err != nil || info.Size() < offset {
+		return 0
+	}
+	return offset
+}
+
+// newStreamRecord starts a record of the current time
+func (s *EventStream) newStreamRecord(recordType types.StreamRecordType) types.StreamRecord {
+	return types.StreamRecord{Version: types.OutputSchemaVersion, Type: recordType, Time: s.sm.Now()}
+}
+
+// sessionRecord reports a session's current state
+func (s *EventStream) sessionRecord(recordType types.StreamRecordType, output types.SessionOutput) types.StreamRecord {
+	record := s.newStreamRecord(recordType)
+	record.SessionID, record.Name = output.ID, output.Name
+	record.Session = &output
+	return record
+}
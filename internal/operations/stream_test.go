@@ -0,0 +1,92 @@
+package operations
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestEventStream(t *testing.T) {
+	manager := state.NewManager(state.Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("first"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	firstID := cores[0].ID
+
+	stream := NewEventStream(manager)
+	snapshot, err := stream.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if snapshot.Type != types.StreamSnapshot || snapshot.Version != types.OutputSchemaVersion ||
+		len(snapshot.Sessions) != 1 || snapshot.Sessions[0].Name != "first" {
+		t.Fatalf("Snapshot() = %+v, want a snapshot of the first session", snapshot)
+	}
+
+	// The snapshot covers what was already recorded
+	poll := func() []types.StreamRecord {
+		t.Helper()
+		records, err := stream.Poll()
+		if err != nil {
+			t.Fatalf("Poll() error = %v", err)
+		}
+		return records
+	}
+	if records := poll(); len(records) != 0 {
+		t.Fatalf("Poll() = %+v, want nothing new", records)
+	}
+
+	manager.RecordEvent(firstID, types.EventAttached, "Attached", nil)
+	manager.UpdateSession(firstID, func(core *types.CoreSession) { core.Task = "Add login" })
+	if err := manager.CreateSession("second"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	records := poll()
+	want := []struct {
+		recordType types.StreamRecordType
+		name       string
+		event      string
+	}{
+		{types.StreamSessionUpdated, "first", ""},
+		{types.StreamEvent, "first", types.EventAttached},
+		{types.StreamSessionAdded, "second", ""},
+		{types.StreamEvent, "second", types.EventCreated},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Poll() = %d records %+v, want %d", len(records), records, len(want))
+	}
+	for i, w := range want {
+		record := records[i]
+		if record.Type != w.recordType || record.Name != w.name {
+			t.Errorf("record %d = %s for %s, want %s for %s", i, record.Type, record.Name, w.recordType, w.name)
+		}
+		if w.event != "" && (record.Event == nil || record.Event.Type != w.event) {
+			t.Errorf("record %d event = %+v, want %s", i, record.Event, w.event)
+		}
+	}
+	if records[0].Session == nil || records[0].Session.Task != "Add login" {
+		t.Errorf("updated record = %+v, want the session's new state", records[0].Session)
+	}
+
+	if err := manager.DeleteSession(firstID); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	records = poll()
+	if len(records) != 1 || records[0].Type != types.StreamSessionRemoved || records[0].SessionID != firstID || records[0].Name != "first" {
+		t.Errorf("Poll() = %+v, want the first session removed", records)
+	}
+}
@@ -0,0 +1,154 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// SyncStrategy is how 'cwt sync' brings a session's branch up to date
+type SyncStrategy string
+
+const (
+	SyncRebase SyncStrategy = "rebase" // Replay the session's commits on the base branch
+	SyncMerge  SyncStrategy = "merge"  // Merge the base branch into the session's branch
+)
+
+// SyncStrategies are the valid sync strategies
+var SyncStrategies = []SyncStrategy{SyncRebase, SyncMerge}
+
+// SyncOutcome is what syncing did to one session
+type SyncOutcome string
+
+const (
+	SyncUpdated   SyncOutcome = "updated"    // The branch now has the base branch's commits
+	SyncUpToDate  SyncOutcome = "up to date" // The branch already had them
+	SyncConflicts SyncOutcome = "conflicts"  // Conflicts stopped it; it was undone
+	SyncSkipped   SyncOutcome = "skipped"    // The session couldn't be synced as it is
+	SyncFailed    SyncOutcome = "failed"
+)
+
+// SyncOptions are the settings of a sync
+type SyncOptions struct {
+	Strategy SyncStrategy
+	Only     []string // Names or globs of the sessions to sync; all when empty
+	Remote   string   // Remote to fetch first and whose base branch to sync with; none when empty
+}
+
+// SyncResult is what syncing did to one session
+type SyncResult struct {
+	Session string
+	Outcome SyncOutcome
+	Reason  string   // Why it was skipped
+	Files   []string // Files that conflicted
+	Err     error
+}
+
+// SyncSessions fetches the remote and brings the branch of every session
+// selected by opts up to date with the base branch: the remote's, when it
+// has one, or the local one. Sessions are synced one at a time. Conflicts
+// are undone at once, leaving the session as it was, so a sync never leaves
+// worktrees halfway through a rebase or merge; resolve them with 'cwt
+// rebase' instead. progress, if set, is called with each session's result.
+func (s *SessionOperations) SyncSessions(opts SyncOptions, progress func(SyncResult)) (string, []SyncResult, error) {
+	gitChecker := s.stateManager.GetGitChecker()
+	if opts.Strategy == "" {
+		opts.Strategy = SyncRebase
+	}
+
+	onto := s.stateManager.GetBaseBranch()
+	if opts.Remote != "" {
+		if err := gitChecker.Fetch(opts.Remote); err != nil {
+			return "", nil, err
+		}
+		remoteBase := opts.Remote + "/" + onto
+		if _, err := gitChecker.ResolveCommit(remoteBase); err == nil {
+			onto = remoteBase
+		}
+	}
+
+	sessions, err := s.stateManager.DeriveFreshSessions()
+	if err != nil {
+		return onto, nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	if len(opts.Only) > 0 {
+		if sessions, err = MatchSessions(sessions, opts.Only); err != nil {
+			return onto, nil, err
+		}
+	}
+
+	results := make([]SyncResult, 0, len(sessions))
+	for _, session := range sessions {
+		result := s.syncSession(session, onto, opts.Strategy)
+		logger.Info("session synced", "session", result.Session, "outcome", result.Outcome, "error", result.Err)
+		results = append(results, result)
+		if progress != nil {
+			progress(result)
+		}
+	}
+	return onto, results, nil
+}
+
+// syncSession brings one session's branch up to date with onto
+func (s *SessionOperations) syncSession(session types.Session, onto string, strategy SyncStrategy) SyncResult {
+	result := SyncResult{Session: session.Core.Name}
+	skip := func(reason string) SyncResult {
+		result.Outcome = SyncSkipped
+		result.Reason = reason
+		return result
+	}
+
+	status := session.GitStatus
+	switch {
+	case session.Core.IsPaused():
+		return skip("paused")
+	case status.WorktreeBroken():
+		return skip("worktree is broken")
+	case status.HasError():
+		return skip(status.Error)
+	case status.HasConflicts():
+		return skip("has unresolved conflicts")
+	case len(status.StagedFiles) > 0 || len(status.UnstagedFiles) > 0:
+		return skip("has uncommitted changes")
+	}
+
+	var update state.UpdateResult
+	var err error
+	if strategy == SyncMerge {
+		update, err = s.stateManager.MergeIntoSession(session.Core.ID, onto)
+	} else {
+		update, err = s.stateManager.RebaseSession(session.Core.ID, onto)
+	}
+
+	var rebaseConflict *git.RebaseConflictError
+	var mergeConflict *git.MergeConflictError
+	switch {
+	case errors.As(err, &rebaseConflict):
+		result.Outcome, result.Files = SyncConflicts, rebaseConflict.Files
+		result.Err = s.stateManager.AbortRebase(session.Core.ID)
+	case errors.As(err, &mergeConflict):
+		result.Outcome, result.Files = SyncConflicts, mergeConflict.Files
+		result.Err = s.stateManager.AbortMerge(session.Core.ID)
+	case err != nil:
+		result.Outcome, result.Err = SyncFailed, err
+	default:
+		result.Outcome = SyncUpdated
+		if !update.Updated {
+			result.Outcome = SyncUpToDate
+		}
+	}
+	return result
+}
+
+// ParseSyncStrategy checks a sync strategy named on the command line
+func ParseSyncStrategy(name string) (SyncStrategy, error) {
+	for _, strategy := range SyncStrategies {
+		if SyncStrategy(name) == strategy {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("invalid strategy %q (valid: %v)", name, SyncStrategies)
+}
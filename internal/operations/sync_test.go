@@ -0,0 +1,93 @@
+package operations
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestSessionOperations_SyncSessions(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := state.NewManager(state.Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	})
+	defer manager.Close()
+
+	sessionOps := NewSessionOperations(manager)
+	for _, name := range []string{"feat-auth", "feat-login", "wip"} {
+		if err := sessionOps.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+	}
+	worktrees := make(map[string]string)
+	cores, _ := manager.CoreSessions()
+	for _, core := range cores {
+		worktrees[core.Name] = core.WorktreePath
+	}
+	gitChecker.RebaseFails[worktrees["feat-login"]] = []string{"login.go"}
+	gitChecker.SetStatus(worktrees["wip"], types.GitStatus{HasChanges: true, UnstagedFiles: []string{"notes.md"}})
+
+	var progress []string
+	onto, results, err := sessionOps.SyncSessions(SyncOptions{Remote: "origin"}, func(result SyncResult) {
+		progress = append(progress, result.Session)
+	})
+	if err != nil {
+		t.Fatalf("SyncSessions() error = %v", err)
+	}
+	if onto != "origin/main" || len(gitChecker.Fetched) != 1 || gitChecker.Fetched[0] != "origin" {
+		t.Errorf("synced with %q after fetching %v, want origin/main after fetching origin", onto, gitChecker.Fetched)
+	}
+	if len(results) != 3 || len(progress) != 3 {
+		t.Fatalf("results = %+v, progress = %v; want one for each session", results, progress)
+	}
+
+	outcomes := make(map[string]SyncResult)
+	for _, result := range results {
+		outcomes[result.Session] = result
+	}
+	if outcomes["feat-auth"].Outcome != SyncUpdated || gitChecker.Rebased[worktrees["feat-auth"]] != "origin/main" {
+		t.Errorf("feat-auth = %+v, want it rebased onto origin/main", outcomes["feat-auth"])
+	}
+	if login := outcomes["feat-login"]; login.Outcome != SyncConflicts || len(login.Files) != 1 || login.Err != nil {
+		t.Errorf("feat-login = %+v, want its conflicts reported", login)
+	}
+	if len(gitChecker.Aborted) != 1 || gitChecker.Aborted[0] != worktrees["feat-login"] {
+		t.Errorf("aborted %v, want the conflicted rebase undone", gitChecker.Aborted)
+	}
+	if wip := outcomes["wip"]; wip.Outcome != SyncSkipped || wip.Reason != "has uncommitted changes" {
+		t.Errorf("wip = %+v, want it skipped for its uncommitted changes", wip)
+	}
+	if _, rebased := gitChecker.Rebased[worktrees["wip"]]; rebased {
+		t.Error("a worktree with uncommitted changes was rebased")
+	}
+
+	// Merging, only into the sessions asked for, with the local base branch
+	onto, results, err = sessionOps.SyncSessions(SyncOptions{Strategy: SyncMerge, Only: []string{"feat-*"}}, nil)
+	if err != nil {
+		t.Fatalf("SyncSessions(merge) error = %v", err)
+	}
+	if onto != "main" || len(results) != 2 || len(gitChecker.Fetched) != 1 {
+		t.Errorf("synced %d sessions with %q, want the 2 feat sessions with main and no fetch", len(results), onto)
+	}
+	if gitChecker.MergedFrom[worktrees["feat-login"]] != "main" || gitChecker.MergedFrom[worktrees["wip"]] != "" {
+		t.Errorf("merged %v, want main merged into the feat sessions only", gitChecker.MergedFrom)
+	}
+}
+
+func TestParseSyncStrategy(t *testing.T) {
+	if strategy, err := ParseSyncStrategy("merge"); err != nil || strategy != SyncMerge {
+		t.Errorf("ParseSyncStrategy(merge) = %q, %v", strategy, err)
+	}
+	if _, err := ParseSyncStrategy("squash"); err == nil {
+		t.Error("an unknown strategy should be rejected")
+	}
+}
@@ -0,0 +1,33 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// BrokenWorktreeError is returned when a session can't be worked with
+// because its worktree directory is gone or no longer a git worktree
+type BrokenWorktreeError struct {
+	Session string
+	Path    string
+	Kind    types.GitErrorKind
+}
+
+func (e *BrokenWorktreeError) Error() string {
+	problem := "is missing"
+	if e.Kind == types.GitErrorNotRepository {
+		problem = "is no longer a git worktree"
+	}
+	return fmt.Sprintf("the worktree of session '%s' %s (%s); recreate it from the session's branch with 'cwt repair %s'",
+		e.Session, problem, e.Path, e.Session)
+}
+
+// CheckWorktree returns a BrokenWorktreeError if a session's worktree is
+// broken
+func CheckWorktree(session types.Session) error {
+	if !session.GitStatus.WorktreeBroken() {
+		return nil
+	}
+	return &BrokenWorktreeError{Session: session.Core.Name, Path: session.Core.WorktreePath, Kind: session.GitStatus.ErrorKind}
+}
@@ -0,0 +1,43 @@
+package operations
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestCheckWorktree(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "feature", WorktreePath: ".cwt/worktrees/feature"}}
+	if err := CheckWorktree(session); err != nil {
+		t.Errorf("CheckWorktree() = %v, want nil for a healthy worktree", err)
+	}
+
+	// Failing git is not something recreating the worktree fixes
+	session.GitStatus.ErrorKind = types.GitErrorCommandFailed
+	if err := CheckWorktree(session); err != nil {
+		t.Errorf("CheckWorktree() = %v, want nil when git failed", err)
+	}
+
+	tests := []struct {
+		kind types.GitErrorKind
+		want string
+	}{
+		{types.GitErrorMissingWorktree, "is missing"},
+		{types.GitErrorNotRepository, "no longer a git worktree"},
+	}
+	for _, tt := range tests {
+		session.GitStatus.ErrorKind = tt.kind
+		err := CheckWorktree(session)
+		var brokenErr *BrokenWorktreeError
+		if !errors.As(err, &brokenErr) {
+			t.Fatalf("CheckWorktree() = %v, want a BrokenWorktreeError for %s", err, tt.kind)
+		}
+		for _, want := range []string{"'feature'", tt.want, "cwt repair feature"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q doesn't mention %q", err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package profile
+
+import (
+	"context"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/statusprovider"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// GitChecker times each call of a git checker as PhaseGit
+func GitChecker(inner git.Checker, recorder *Recorder) git.Checker {
+	return timedGit{inner: inner, recorder: recorder}
+}
+
+// TmuxChecker times each call of a tmux checker as PhaseTmux
+func TmuxChecker(inner tmux.Checker, recorder *Recorder) tmux.Checker {
+	return timedTmux{inner: inner, recorder: recorder}
+}
+
+// ClaudeChecker times each call of a Claude checker as PhaseClaude
+func ClaudeChecker(inner claude.Checker, recorder *Recorder) claude.Checker {
+	return timedClaude{inner: inner, recorder: recorder}
+}
+
+// StatusProviders times each call of a status provider checker as PhaseProviders
+func StatusProviders(inner statusprovider.Checker, recorder *Recorder) statusprovider.Checker {
+	return timedProviders{inner: inner, recorder: recorder}
+}
+
+type timedGit struct {
+	inner    git.Checker
+	recorder *Recorder
+}
+
+func (t timedGit) GetStatus(worktreePath string) (types.GitStatus, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.GetStatus(worktreePath)
+}
+
+func (t timedGit) CommittedChanges(worktreePath string) ([]types.ChangedFile, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.CommittedChanges(worktreePath)
+}
+
+func (t timedGit) CreateWorktree(ctx context.Context, branchName, worktreePath string, progress func(step string)) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.CreateWorktree(ctx, branchName, worktreePath, progress)
+}
+
+func (t timedGit) AddWorktree(branchName, worktreePath string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.AddWorktree(branchName, worktreePath)
+}
+
+func (t timedGit) AddDetachedWorktree(commit, worktreePath string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.AddDetachedWorktree(commit, worktreePath)
+}
+
+func (t timedGit) PopulateWorktree(ctx context.Context, worktreePath string, progress func(step string)) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.PopulateWorktree(ctx, worktreePath, progress)
+}
+
+func (t timedGit) InstallHooks(ctx context.Context, worktreePath, command string) (git.HookManager, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.InstallHooks(ctx, worktreePath, command)
+}
+
+func (t timedGit) RemoveWorktree(worktreePath string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.RemoveWorktree(worktreePath)
+}
+
+func (t timedGit) IsValidRepository(repoPath string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.IsValidRepository(repoPath)
+}
+
+func (t timedGit) ListWorktrees() ([]git.WorktreeInfo, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.ListWorktrees()
+}
+
+func (t timedGit) BranchExists(branchName string) bool {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.BranchExists(branchName)
+}
+
+func (t timedGit) ListBranches() ([]string, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.ListBranches()
+}
+
+func (t timedGit) DeleteBranch(branchName string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.DeleteBranch(branchName)
+}
+
+func (t timedGit) BranchMerged(branchName string) (bool, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.BranchMerged(branchName)
+}
+
+func (t timedGit) UnmergedCommits(branchName string) ([]types.CommitOutput, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.UnmergedCommits(branchName)
+}
+
+func (t timedGit) RenameBranch(branchName, newName string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.RenameBranch(branchName, newName)
+}
+
+func (t timedGit) CommitChanges(worktreePath, message string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.CommitChanges(worktreePath, message)
+}
+
+func (t timedGit) CommitStaged(worktreePath, message string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.CommitStaged(worktreePath, message)
+}
+
+func (t timedGit) ApplyToIndex(worktreePath, patch string, reverse bool) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.ApplyToIndex(worktreePath, patch, reverse)
+}
+
+func (t timedGit) StageFile(worktreePath, path string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.StageFile(worktreePath, path)
+}
+
+func (t timedGit) UnstageFile(worktreePath, path string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.UnstageFile(worktreePath, path)
+}
+
+func (t timedGit) HideLocalChanges(worktreePath string, paths []string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.HideLocalChanges(worktreePath, paths)
+}
+
+func (t timedGit) Rebase(worktreePath, onto string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.Rebase(worktreePath, onto)
+}
+
+func (t timedGit) AbortRebase(worktreePath string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.AbortRebase(worktreePath)
+}
+
+func (t timedGit) MergeInto(worktreePath, from string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.MergeInto(worktreePath, from)
+}
+
+func (t timedGit) AbortMerge(worktreePath string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.AbortMerge(worktreePath)
+}
+
+func (t timedGit) Fetch(remote string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.Fetch(remote)
+}
+
+func (t timedGit) PredictConflicts(branch, into string) ([]string, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.PredictConflicts(branch, into)
+}
+
+func (t timedGit) SnapshotWorktree(worktreePath string) (string, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.SnapshotWorktree(worktreePath)
+}
+
+func (t timedGit) PinSnapshot(tree, ref string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.PinSnapshot(tree, ref)
+}
+
+func (t timedGit) DeleteRef(ref string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.DeleteRef(ref)
+}
+
+func (t timedGit) CheckoutBranch(branchName string) error {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.CheckoutBranch(branchName)
+}
+
+func (t timedGit) GetCurrentBranch(worktreePath string) (string, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.GetCurrentBranch(worktreePath)
+}
+
+func (t timedGit) ResolveCommit(rev string) (string, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.ResolveCommit(rev)
+}
+
+func (t timedGit) BranchDiff(branchName string) (string, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.BranchDiff(branchName)
+}
+
+func (t timedGit) BranchLog(branchName string) (string, error) {
+	defer t.recorder.Track(PhaseGit)()
+	return t.inner.BranchLog(branchName)
+}
+
+type timedTmux struct {
+	inner    tmux.Checker
+	recorder *Recorder
+}
+
+func (t timedTmux) IsSessionAlive(sessionName string) bool {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.IsSessionAlive(sessionName)
+}
+
+func (t timedTmux) CheckSessionsAlive(sessionNames []string) (map[string]bool, error) {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.CheckSessionsAlive(sessionNames)
+}
+
+func (t timedTmux) CaptureOutput(sessionName string) (string, error) {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.CaptureOutput(sessionName)
+}
+
+func (t timedTmux) CaptureHistory(sessionName string, lines int) (string, error) {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.CaptureHistory(sessionName, lines)
+}
+
+func (t timedTmux) CreateSession(name, workdir, command string, env ...string) error {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.CreateSession(name, workdir, command, env...)
+}
+
+func (t timedTmux) KillSession(sessionName string) error {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.KillSession(sessionName)
+}
+
+func (t timedTmux) RenameSession(sessionName, newName string) error {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.RenameSession(sessionName, newName)
+}
+
+func (t timedTmux) ListSessions() ([]string, error) {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.ListSessions()
+}
+
+func (t timedTmux) SendKeys(sessionName, text string) error {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.SendKeys(sessionName, text)
+}
+
+func (t timedTmux) SetExitHook(sessionName, command string) error {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.SetExitHook(sessionName, command)
+}
+
+func (t timedTmux) RespawnSession(sessionName, workdir, command string, env ...string) error {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.RespawnSession(sessionName, workdir, command, env...)
+}
+
+func (t timedTmux) OpenWindow(sessionName string) error {
+	defer t.recorder.Track(PhaseTmux)()
+	return t.inner.OpenWindow(sessionName)
+}
+
+type timedClaude struct {
+	inner    claude.Checker
+	recorder *Recorder
+}
+
+func (t timedClaude) GetStatus(worktreePath string) types.ClaudeStatus {
+	defer t.recorder.Track(PhaseClaude)()
+	return t.inner.GetStatus(worktreePath)
+}
+
+func (t timedClaude) FindSessionID(worktreePath string) (string, error) {
+	defer t.recorder.Track(PhaseClaude)()
+	return t.inner.FindSessionID(worktreePath)
+}
+
+func (t timedClaude) TokenUsage(worktreePath, month string) int64 {
+	defer t.recorder.Track(PhaseClaude)()
+	return t.inner.TokenUsage(worktreePath, month)
+}
+
+func (t timedClaude) SessionUsage(worktreePath string) types.Usage {
+	defer t.recorder.Track(PhaseClaude)()
+	return t.inner.SessionUsage(worktreePath)
+}
+
+type timedProviders struct {
+	inner    statusprovider.Checker
+	recorder *Recorder
+}
+
+func (t timedProviders) GetFields(session types.Session) []types.StatusField {
+	defer t.recorder.Track(PhaseProviders)()
+	return t.inner.GetFields(session)
+}
@@ -0,0 +1,127 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Phase is a kind of work a command's time is spent on
+type Phase string
+
+// Phases timed, in the order reports list them
+const (
+	PhaseStateLoad = Phase("state load")       // Reading sessions, from disk or the daemon
+	PhaseGit       = Phase("git")              // Git calls, like status of a worktree
+	PhaseTmux      = Phase("tmux")             // Tmux calls, like which sessions are alive
+	PhaseClaude    = Phase("claude scan")      // Reading Claude's state and transcripts
+	PhaseProviders = Phase("status providers") // External programs adding status fields
+	PhaseRender    = Phase("render")           // Drawing the output
+)
+
+// Phases lists the phases in report order
+var Phases = []Phase{PhaseStateLoad, PhaseGit, PhaseTmux, PhaseClaude, PhaseProviders, PhaseRender}
+
+// Timing sums up the time spent in a phase
+type Timing struct {
+	Phase Phase
+	Calls int
+	Total time.Duration
+	Max   time.Duration // Longest call
+	Last  time.Duration // Latest call
+}
+
+// Recorder times the phases of a command. A nil Recorder records nothing,
+// so code can time its work without checking whether anyone is profiling.
+type Recorder struct {
+	mu      sync.Mutex
+	timings map[Phase]*Timing
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{timings: make(map[Phase]*Timing)}
+}
+
+// Track starts timing a call of a phase; the returned func ends it:
+//
+//	defer recorder.Track(profile.PhaseGit)()
+func (r *Recorder) Track(phase Phase) func() {
+	if r == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		r.add(phase, time.Since(start))
+	}
+}
+
+func (r *Recorder) add(phase Phase, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	timing := r.timings[phase]
+	if timing == nil {
+		timing = &Timing{Phase: phase}
+		r.timings[phase] = timing
+	}
+	timing.Calls++
+	timing.Total += elapsed
+	timing.Last = elapsed
+	timing.Max = max(timing.Max, elapsed)
+}
+
+// Snapshot returns the timings so far of the phases with calls, in report order
+func (r *Recorder) Snapshot() []Timing {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var timings []Timing
+	for _, phase := range Phases {
+		if timing := r.timings[phase]; timing != nil {
+			timings = append(timings, *timing)
+		}
+	}
+	return timings
+}
+
+// Since returns the calls made between an earlier snapshot and now, like
+// those of one refresh. Max and Last are those of the whole recording.
+func (r *Recorder) Since(before []Timing) []Timing {
+	var timings []Timing
+	for _, timing := range r.Snapshot() {
+		for _, earlier := range before {
+			if earlier.Phase == timing.Phase {
+				timing.Calls -= earlier.Calls
+				timing.Total -= earlier.Total
+			}
+		}
+		if timing.Calls > 0 {
+			timings = append(timings, timing)
+		}
+	}
+	return timings
+}
+
+// WriteReport prints a breakdown of the timings of a command that took
+// wall in total
+func WriteReport(w io.Writer, timings []Timing, wall time.Duration) {
+	fmt.Fprintf(w, "⏱️  %s total\n", Round(wall))
+	var tracked time.Duration
+	for _, timing := range timings {
+		fmt.Fprintf(w, "   %-17s %9s  %4d calls  max %s\n",
+			timing.Phase, Round(timing.Total), timing.Calls, Round(timing.Max))
+		tracked += timing.Total
+	}
+	// Phases can overlap when work runs concurrently, leaving nothing to attribute
+	if other := wall - tracked; other > 0 {
+		fmt.Fprintf(w, "   %-17s %9s\n", "other", Round(other))
+	}
+}
+
+// Round shortens a duration for reports, to hundredths of a millisecond
+func Round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,47 @@
+package profile
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.add(PhaseRender, 2*time.Millisecond)
+	recorder.add(PhaseGit, 3*time.Millisecond)
+	recorder.add(PhaseGit, 1*time.Millisecond)
+	before := recorder.Snapshot()
+	recorder.add(PhaseGit, 5*time.Millisecond)
+	recorder.Track(PhaseTmux)()
+
+	timings := recorder.Snapshot()
+	if len(timings) != 3 || timings[0].Phase != PhaseGit || timings[1].Phase != PhaseTmux || timings[2].Phase != PhaseRender {
+		t.Fatalf("Snapshot() = %+v, want git, tmux and render in report order", timings)
+	}
+	git := timings[0]
+	if git.Calls != 3 || git.Total != 9*time.Millisecond || git.Max != 5*time.Millisecond || git.Last != 5*time.Millisecond {
+		t.Errorf("git timing = %+v", git)
+	}
+
+	since := recorder.Since(before)
+	if len(since) != 2 || since[0].Calls != 1 || since[0].Total != 5*time.Millisecond || since[1].Phase != PhaseTmux {
+		t.Errorf("Since() = %+v, want the last git call and the tmux call", since)
+	}
+
+	var report strings.Builder
+	WriteReport(&report, timings, 20*time.Millisecond)
+	for _, want := range []string{"20ms total", "git", "3 calls", "max 5ms", "other"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report lacks %q:\n%s", want, report.String())
+		}
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var recorder *Recorder
+	recorder.Track(PhaseGit)()
+	if timings := recorder.Since(recorder.Snapshot()); timings != nil {
+		t.Errorf("a nil recorder should record nothing, got %+v", timings)
+	}
+}
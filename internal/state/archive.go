@@ -0,0 +1,336 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/agent"
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// archiveRecordFile is the metadata file inside each session's archive directory
+const archiveRecordFile = "session.json"
+
+// Files kept in each session's archive directory besides its metadata
+const (
+	ArchiveDiffFile       = "changes.diff"  // What the session's branch changed
+	ArchiveLogFile        = "commits.log"   // The commits on the session's branch
+	ArchiveTranscriptFile = "transcript.md" // Summaries of its Claude conversations
+	ArchiveEventsFile     = "events.jsonl"  // Its timeline, restored with it
+)
+
+// ArchiveDir returns the directory archived sessions are kept in
+func (m *Manager) ArchiveDir() string {
+	return filepath.Join(m.config.DataDir, "archive")
+}
+
+// ArchiveSession moves a session out of the active list. Its tmux session
+// and worktree are removed, while its branch and metadata are kept in the
+// archive along with its final diff, commit log and a summary of its Claude
+// transcripts. Sessions with uncommitted changes are refused, since removing
+// the worktree would lose them, and so are those whose git status can't be
+// read, unless their worktree is gone already.
+func (m *Manager) ArchiveSession(sessionID, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var archived *types.CoreSession
+	for _, core := range cores {
+		if core.ID == sessionID {
+			archived = &core
+		}
+	}
+	if archived == nil {
+		return fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	// Changes made since the status was cached would be lost too
+	m.cache.invalidate(sessionID)
+	session := m.deriveSession(*archived)
+	if session.GitStatus.HasChanges {
+		return fmt.Errorf("session '%s' has uncommitted changes; commit or discard them before archiving", archived.Name)
+	}
+	if session.GitStatus.HasError() && session.GitStatus.ErrorKind != types.GitErrorMissingWorktree {
+		return fmt.Errorf("session '%s' can't be archived while its git status can't be read: %s", archived.Name, session.GitStatus.Error)
+	}
+
+	branch, err := m.config.GitChecker.GetCurrentBranch(archived.WorktreePath)
+	if err != nil {
+		// Sessions are created on a branch named after them
+		branch = archived.Name
+	}
+
+	// Keep the conversation so a restored session can resume it
+	archived.ClaudeSessionID = m.conversationID(*archived)
+	archived.PausedAt = nil
+
+	record := types.ArchivedSession{
+		Core:         *archived,
+		Branch:       branch,
+		ArchivedAt:   m.Now(),
+		LastActivity: session.LastActivity,
+		Reason:       reason,
+	}
+	if err := m.writeArchiveRecord(record); err != nil {
+		return err
+	}
+	m.RecordEvent(sessionID, types.EventArchived, archivedMessage(reason), nil)
+	if err := m.writeArchiveHistory(record); err != nil {
+		os.RemoveAll(filepath.Join(m.ArchiveDir(), sessionID))
+		return err
+	}
+
+	m.cleanupExternalResources(*archived)
+
+	if err := m.removeCoreSession(sessionID); err != nil {
+		return fmt.Errorf("failed to save updated sessions: %w", err)
+	}
+
+	m.InvalidateStatus(sessionID)
+
+	m.eventBus.Publish(types.SessionArchived{
+		SessionID: sessionID,
+		Name:      archived.Name,
+		Reason:    reason,
+	})
+
+	return nil
+}
+
+// ArchivedSessions returns the archived sessions, oldest archive first
+func (m *Manager) ArchivedSessions() ([]types.ArchivedSession, error) {
+	entries, err := os.ReadDir(m.ArchiveDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	var archived []types.ArchivedSession
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		record, err := m.readArchiveRecord(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		archived = append(archived, record)
+	}
+
+	sort.Slice(archived, func(i, j int) bool {
+		return archived[i].ArchivedAt.Before(archived[j].ArchivedAt)
+	})
+	return archived, nil
+}
+
+// DeleteArchivedSession permanently removes an archived session and its
+// branch, whether or not the branch was merged
+func (m *Manager) DeleteArchivedSession(sessionID string) error {
+	record, err := m.readArchiveRecord(sessionID)
+	if err != nil {
+		return err
+	}
+
+	// A branch deleted by hand since archiving is not an error
+	branches, err := m.config.GitChecker.ListBranches()
+	if err != nil {
+		return err
+	}
+	if record.Branch != "" && slices.Contains(branches, record.Branch) {
+		if err := m.config.GitChecker.DeleteBranch(record.Branch); err != nil {
+			return fmt.Errorf("failed to delete branch of archived session '%s': %w", record.Core.Name, err)
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(m.ArchiveDir(), sessionID)); err != nil {
+		return fmt.Errorf("failed to remove archived session '%s': %w", record.Core.Name, err)
+	}
+	return nil
+}
+
+// RestoreArchivedSession moves an archived session back into the active
+// list. Its branch is checked out in a new worktree and Claude resumes the
+// conversation it had when it was archived.
+func (m *Manager) RestoreArchivedSession(sessionID string) error {
+	record, err := m.readArchiveRecord(sessionID)
+	if err != nil {
+		return err
+	}
+	core := record.Core
+
+	if err := m.checkDuplicateName(core.Name); err != nil {
+		return err
+	}
+	if core.WorktreePath, err = m.freeWorktreePath(core.WorktreePath); err != nil {
+		return err
+	}
+
+	branch := record.Branch
+	if branch == "" {
+		branch = core.Name
+	}
+	if err := m.config.GitChecker.AddWorktree(branch, core.WorktreePath); err != nil {
+		return fmt.Errorf("failed to restore worktree: %w", err)
+	}
+
+	if err := m.config.GitChecker.PopulateWorktree(context.Background(), core.WorktreePath, nil); err != nil {
+		m.config.GitChecker.RemoveWorktree(core.WorktreePath)
+		return fmt.Errorf("failed to populate restored worktree: %w", err)
+	}
+	if agent.IsClaude(core.Agent) {
+		if _, err := m.syncClaudeProject(core.WorktreePath); err != nil {
+			logger.Warn("failed to bring the project's Claude configuration into the worktree", "session", core.Name, "error", err)
+		}
+	}
+	// Not fatal: the project may just not run right away
+	if _, err := m.copyUntrackedFiles(core.WorktreePath, m.untrackedPatterns(core)); err != nil {
+		logger.Warn("failed to copy untracked files", "session", core.Name, "error", err)
+	}
+
+	if err := m.installGitHooks(context.Background(), core.WorktreePath, nil); err != nil {
+		m.config.GitChecker.RemoveWorktree(core.WorktreePath)
+		return err
+	}
+
+	if err := m.createClaudeSettings(core); err != nil {
+		m.config.GitChecker.RemoveWorktree(core.WorktreePath)
+		return fmt.Errorf("failed to create Claude settings: %w", err)
+	}
+	if err := m.linkContextFiles(core.WorktreePath); err != nil {
+		logger.Warn("failed to share context files", "session", core.Name, "error", err)
+	}
+
+	command := m.AgentLaunch(core, core.ClaudeSessionID, "").Command
+	if err := m.config.TmuxChecker.CreateSession(core.TmuxSession, core.WorktreePath, command, m.SessionEnv(core)...); err != nil {
+		m.config.GitChecker.RemoveWorktree(core.WorktreePath)
+		return fmt.Errorf("failed to create tmux session: %w", err)
+	}
+
+	m.installExitHookOrWarn(core)
+
+	if err := m.addCoreSession(core); err != nil {
+		m.cleanupExternalResources(core)
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	// Bring back the timeline before the archive holding it is removed
+	if events, err := os.ReadFile(filepath.Join(m.ArchiveDir(), sessionID, ArchiveEventsFile)); err == nil {
+		logPath := types.SessionEventLogPath(m.config.DataDir, sessionID)
+		if os.MkdirAll(filepath.Dir(logPath), 0755) == nil {
+			os.WriteFile(logPath, events, 0644)
+		}
+	}
+	m.RecordEvent(sessionID, types.EventRestored, "Restored from the archive", nil)
+
+	// The session is active again; a leftover archive would list it twice
+	if err := os.RemoveAll(filepath.Join(m.ArchiveDir(), sessionID)); err != nil {
+		return fmt.Errorf("failed to remove archived session '%s': %w", core.Name, err)
+	}
+
+	m.invalidateProvider(core.ID)
+	m.eventBus.Publish(types.SessionCreated{Session: m.deriveSession(core)})
+
+	return nil
+}
+
+// archivedMessage describes an archiving in a session's timeline
+func archivedMessage(reason string) string {
+	if reason == "" {
+		return "Archived"
+	}
+	return fmt.Sprintf("Archived: %s", reason)
+}
+
+// writeArchiveHistory keeps what a session did in its archive directory: the
+// diff and log of its branch, a summary of each of its Claude conversations,
+// oldest first, and its timeline. A file whose content git can't produce, such
+// as when the base branch is gone, is left out.
+func (m *Manager) writeArchiveHistory(record types.ArchivedSession) error {
+	dir := filepath.Join(m.ArchiveDir(), record.Core.ID)
+	files := make(map[string]string)
+
+	if diff, err := m.config.GitChecker.BranchDiff(record.Branch); err == nil {
+		files[ArchiveDiffFile] = diff
+	}
+	if log, err := m.config.GitChecker.BranchLog(record.Branch); err == nil {
+		files[ArchiveLogFile] = log
+	}
+
+	if transcripts, err := claude.NewSessionScanner().FindSessionsForDirectory(record.Core.WorktreePath); err == nil && len(transcripts) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Claude conversations of %s\n", record.Core.Name)
+		for i := len(transcripts) - 1; i >= 0; i-- {
+			summary, err := claude.SummarizeTranscript(transcripts[i].FilePath)
+			if err != nil {
+				continue
+			}
+			b.WriteString("\n")
+			b.WriteString(summary.Markdown())
+		}
+		files[ArchiveTranscriptFile] = b.String()
+	}
+
+	if events, err := os.ReadFile(types.SessionEventLogPath(m.config.DataDir, record.Core.ID)); err == nil {
+		files[ArchiveEventsFile] = string(events)
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write archived %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) writeArchiveRecord(record types.ArchivedSession) error {
+	dir := filepath.Join(m.ArchiveDir(), record.Core.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal archived session: %w", err)
+	}
+
+	path := filepath.Join(dir, archiveRecordFile)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write archived session: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to save archived session: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) readArchiveRecord(sessionID string) (types.ArchivedSession, error) {
+	var record types.ArchivedSession
+	data, err := os.ReadFile(filepath.Join(m.ArchiveDir(), sessionID, archiveRecordFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return record, fmt.Errorf("archived session with ID %s not found", sessionID)
+		}
+		return record, fmt.Errorf("failed to read archived session: %w", err)
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, fmt.Errorf("invalid archived session %s: %w", sessionID, err)
+	}
+	return record, nil
+}
@@ -0,0 +1,154 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_ArchiveSession(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	for _, name := range []string{"dirty", "forgotten"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession(%q) error = %v", name, err)
+		}
+	}
+	cores, err := manager.CoreSessions()
+	if err != nil {
+		t.Fatalf("CoreSessions() error = %v", err)
+	}
+	dirty, forgotten := cores[0], cores[1]
+
+	// Removing the worktree would lose uncommitted changes
+	gitChecker.Statuses[dirty.WorktreePath] = types.GitStatus{HasChanges: true}
+	if err := manager.ArchiveSession(dirty.ID, "idle"); err == nil {
+		t.Error("archiving a session with uncommitted changes should fail")
+	}
+
+	// Nor can it be told whether there are any when git fails
+	gitChecker.StatusErrors[forgotten.WorktreePath] = errors.New("fatal: unable to read index")
+	if err := manager.ArchiveSession(forgotten.ID, "idle"); err == nil {
+		t.Error("archiving a session whose git status can't be read should fail")
+	}
+	delete(gitChecker.StatusErrors, forgotten.WorktreePath)
+
+	gitChecker.Branches[forgotten.WorktreePath] = "forgotten"
+	if err := manager.ArchiveSession(forgotten.ID, "idle for 7 days"); err != nil {
+		t.Fatalf("ArchiveSession() error = %v", err)
+	}
+
+	remaining, _ := manager.CoreSessions()
+	if len(remaining) != 1 || remaining[0].ID != dirty.ID {
+		t.Errorf("active sessions = %v, want only the dirty one", remaining)
+	}
+	if gitChecker.Worktrees[forgotten.WorktreePath] {
+		t.Error("archiving should remove the worktree")
+	}
+
+	archived, err := manager.ArchivedSessions()
+	if err != nil {
+		t.Fatalf("ArchivedSessions() error = %v", err)
+	}
+	if len(archived) != 1 || archived[0].Core.Name != "forgotten" || archived[0].Branch != "forgotten" || archived[0].Reason != "idle for 7 days" {
+		t.Fatalf("archived = %+v, want the forgotten session and its branch", archived)
+	}
+
+	if err := manager.DeleteArchivedSession(forgotten.ID); err != nil {
+		t.Fatalf("DeleteArchivedSession() error = %v", err)
+	}
+	if len(gitChecker.Deleted) != 1 || gitChecker.Deleted[0] != "forgotten" {
+		t.Errorf("deleted branches = %v, want [forgotten]", gitChecker.Deleted)
+	}
+	if archived, _ := manager.ArchivedSessions(); len(archived) != 0 {
+		t.Errorf("archive should be empty, got %v", archived)
+	}
+	if err := manager.DeleteArchivedSession(forgotten.ID); err == nil {
+		t.Error("deleting a missing archived session should fail")
+	}
+}
+
+func TestManager_RestoreArchivedSession(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:          filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       gitChecker,
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+	gitChecker.Branches[auth.WorktreePath] = "auth"
+	gitChecker.Diffs["auth"] = "diff --git a/login.go b/login.go\n"
+	gitChecker.Logs["auth"] = "commit 1111\n\n    Add login\n"
+
+	if err := manager.ArchiveSession(auth.ID, "done"); err != nil {
+		t.Fatalf("ArchiveSession() error = %v", err)
+	}
+
+	dir := filepath.Join(manager.ArchiveDir(), auth.ID)
+	for name, want := range map[string]string{ArchiveDiffFile: gitChecker.Diffs["auth"], ArchiveLogFile: gitChecker.Logs["auth"]} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != want {
+			t.Errorf("archived %s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+
+	// The name is taken while another session uses it
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := manager.RestoreArchivedSession(auth.ID); err == nil {
+		t.Error("restoring over an active session's name should fail")
+	}
+	cores, _ = manager.CoreSessions()
+	if err := manager.DeleteSession(cores[0].ID); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+
+	if err := manager.RestoreArchivedSession(auth.ID); err != nil {
+		t.Fatalf("RestoreArchivedSession() error = %v", err)
+	}
+	cores, _ = manager.CoreSessions()
+	if len(cores) != 1 || cores[0].ID != auth.ID {
+		t.Fatalf("active sessions = %+v, want the restored one", cores)
+	}
+	if gitChecker.Branches[auth.WorktreePath] != "auth" || !gitChecker.Worktrees[auth.WorktreePath] {
+		t.Error("restoring should check the branch out in a new worktree")
+	}
+	if command := tmuxChecker.SessionCommands[auth.TmuxSession]; command != "claude -r mock-session-auth" {
+		t.Errorf("restored with %q, want the archived conversation resumed", command)
+	}
+	if archived, _ := manager.ArchivedSessions(); len(archived) != 0 {
+		t.Errorf("archive = %+v, want the restored session removed", archived)
+	}
+
+	// The timeline comes back with the session
+	events, _ := manager.Timeline(auth.ID)
+	var kinds []string
+	for _, event := range events {
+		kinds = append(kinds, event.Type)
+	}
+	if len(kinds) != 3 || kinds[0] != types.EventCreated || kinds[1] != types.EventArchived || kinds[2] != types.EventRestored {
+		t.Errorf("timeline = %v, want created, archived and restored", kinds)
+	}
+}
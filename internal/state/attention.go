@@ -0,0 +1,11 @@
+package state
+
+import "github.com/jlaneve/cwt-cli/internal/types"
+
+// SetSessionAttention puts a session on the watch list, mutes it, or with
+// an empty attention does neither
+func (m *Manager) SetSessionAttention(sessionID string, attention types.Attention) error {
+	return m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.Attention = attention
+	})
+}
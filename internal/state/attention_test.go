@@ -0,0 +1,48 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_SetSessionAttention(t *testing.T) {
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	id := cores[0].ID
+
+	if err := manager.SetSessionAttention(id, types.AttentionMuted); err != nil {
+		t.Fatalf("SetSessionAttention() error = %v", err)
+	}
+	cores, _ = manager.CoreSessions()
+	if !cores[0].IsMuted() || cores[0].IsWatched() {
+		t.Errorf("attention = %q, want muted", cores[0].Attention)
+	}
+
+	// Watching a muted session unmutes it
+	manager.SetSessionAttention(id, types.AttentionWatched)
+	cores, _ = manager.CoreSessions()
+	if !cores[0].IsWatched() || cores[0].IsMuted() {
+		t.Errorf("attention = %q, want watched", cores[0].Attention)
+	}
+
+	manager.SetSessionAttention(id, "")
+	cores, _ = manager.CoreSessions()
+	if cores[0].Attention != "" {
+		t.Errorf("attention = %q, want cleared", cores[0].Attention)
+	}
+}
@@ -0,0 +1,198 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clock"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// StatusCacheFileName is the file in the data directory that persists derived
+// session status between cwt invocations
+const StatusCacheFileName = "status-cache.json"
+
+// statusCacheEntry holds the externally derived state of one session.
+// WorktreePath and TmuxSession identify what the status was derived from, so
+// an entry is ignored once the session is renamed or moved.
+type statusCacheEntry struct {
+	WorktreePath        string              `json:"worktree_path"`
+	TmuxSession         string              `json:"tmux_session"`
+	IsAlive             bool                `json:"is_alive"`
+	GitStatus           types.GitStatus     `json:"git_status"`
+	Branch              string              `json:"branch,omitempty"`
+	ReviewTree          string              `json:"review_tree,omitempty"` // Snapshot ChangedSinceReview compared the worktree with
+	ChangedSinceReview  bool                `json:"changed_since_review,omitempty"`
+	PublishTree         string              `json:"publish_tree,omitempty"` // Snapshot ChangedSincePublish compared the worktree with
+	ChangedSincePublish bool                `json:"changed_since_publish,omitempty"`
+	ClaudeStatus        *types.ClaudeStatus `json:"claude_status,omitempty"` // Only set when derived by the Claude checker
+	DerivedAt           time.Time           `json:"derived_at"`
+}
+
+// statusCache is a TTL cache of derived session status, shared across
+// processes through a file in the data directory. A zero TTL disables it.
+type statusCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     atomic.Int64 // time.Duration; changed at runtime when config reloads
+	clock   clock.Clock
+	entries map[string]statusCacheEntry
+	loaded  bool
+	dirty   bool
+}
+
+func newStatusCache(dataDir string, ttl time.Duration, clock clock.Clock) *statusCache {
+	c := &statusCache{
+		path:    filepath.Join(dataDir, StatusCacheFileName),
+		clock:   clock,
+		entries: make(map[string]statusCacheEntry),
+	}
+	c.setTTL(ttl)
+	return c
+}
+
+func (c *statusCache) setTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+func (c *statusCache) getTTL() time.Duration {
+	return time.Duration(c.ttl.Load())
+}
+
+func (c *statusCache) enabled() bool {
+	return c.getTTL() > 0
+}
+
+// get returns the cached entry for a session if it is still fresh
+func (c *statusCache) get(core types.CoreSession) (statusCacheEntry, bool) {
+	if !c.enabled() {
+		return statusCacheEntry{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	entry, ok := c.entries[core.ID]
+	if !ok || entry.WorktreePath != core.WorktreePath || entry.TmuxSession != core.TmuxSession {
+		return statusCacheEntry{}, false
+	}
+	if c.clock.Now().Sub(entry.DerivedAt) > c.getTTL() {
+		return statusCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// put stores a freshly derived entry; call flush to persist it
+func (c *statusCache) put(sessionID string, entry statusCacheEntry) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	c.entries[sessionID] = entry
+	c.dirty = true
+}
+
+// invalidate drops the entry for a session, or every entry if sessionID is empty
+func (c *statusCache) invalidate(sessionID string) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	if sessionID == "" {
+		c.entries = make(map[string]statusCacheEntry)
+	} else {
+		delete(c.entries, sessionID)
+	}
+	c.dirty = true
+}
+
+// flush persists pending changes. Failures are ignored because the cache
+// only saves work; the next invocation simply re-derives.
+func (c *statusCache) flush() {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return
+	}
+	c.dirty = false
+
+	// Drop expired entries so the file does not grow with deleted sessions
+	for id, entry := range c.entries {
+		if c.clock.Now().Sub(entry.DerivedAt) > c.getTTL() {
+			delete(c.entries, id)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return
+	}
+
+	writeCacheFile(c.path, data)
+}
+
+// writeCacheFile atomically replaces a cache file in the data directory.
+// Every dashboard and command of the data directory writes the caches, so
+// each write goes through a temp file of its own.
+func writeCacheFile(path string, data []byte) {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+	tempFile := temp.Name()
+	_, writeErr := temp.Write(data)
+	if closeErr := temp.Close(); writeErr != nil || closeErr != nil {
+		os.Remove(tempFile)
+		return
+	}
+	os.Chmod(tempFile, 0644)
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+	}
+}
+
+// load reads the cache file once; the caller must hold c.mu
+func (c *statusCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	var entries map[string]statusCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return // Corrupt cache is treated as empty
+	}
+	for id, entry := range entries {
+		if _, ok := c.entries[id]; !ok {
+			c.entries[id] = entry
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/clock"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newCachedTestManager(t *testing.T, dataDir string, gitChecker *git.MockChecker, ttl time.Duration) *Manager {
+	t.Helper()
+	return NewManager(Config{
+		DataDir:        dataDir,
+		TmuxChecker:    tmux.NewMockChecker(),
+		GitChecker:     gitChecker,
+		ClaudeChecker:  claude.NewMockChecker(),
+		BaseBranch:     "main",
+		StatusCacheTTL: ttl,
+	})
+}
+
+func TestManager_StatusCache(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	gitChecker := git.NewMockChecker()
+	manager := newCachedTestManager(t, dataDir, gitChecker, time.Hour)
+
+	if err := manager.CreateSession("cached"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	core := sessions[0].Core
+	if sessions[0].GitStatus.HasChanges {
+		t.Fatal("Expected clean status on first derivation")
+	}
+
+	// A change within the TTL is not seen until the entry is invalidated
+	gitChecker.SetStatus(core.WorktreePath, types.GitStatus{HasChanges: true, ModifiedFiles: []string{"main.go"}})
+
+	session, err := manager.DeriveSession(core.ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if session.GitStatus.HasChanges {
+		t.Error("Expected cached clean status within TTL")
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, StatusCacheFileName)); err != nil {
+		t.Errorf("Expected cache file to be written: %v", err)
+	}
+
+	// Another process sharing the data dir reuses the persisted entry
+	other := newCachedTestManager(t, dataDir, gitChecker, time.Hour)
+	session, err = other.DeriveSession(core.ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if session.GitStatus.HasChanges {
+		t.Error("Expected persisted cache entry to be reused")
+	}
+
+	manager.InvalidateStatus(core.ID)
+	session, err = manager.DeriveSession(core.ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if !session.GitStatus.HasChanges {
+		t.Error("Expected fresh status after InvalidateStatus")
+	}
+}
+
+func TestManager_StatusCacheDisabled(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	gitChecker := git.NewMockChecker()
+	manager := newCachedTestManager(t, dataDir, gitChecker, 0)
+
+	if err := manager.CreateSession("uncached"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	core := sessions[0].Core
+
+	gitChecker.SetStatus(core.WorktreePath, types.GitStatus{HasChanges: true})
+
+	session, err := manager.DeriveSession(core.ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if !session.GitStatus.HasChanges {
+		t.Error("Expected fresh status with caching disabled")
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, StatusCacheFileName)); !os.IsNotExist(err) {
+		t.Error("Expected no cache file with caching disabled")
+	}
+}
+
+func TestManager_StatusCacheExpiry(t *testing.T) {
+	mockClock := clock.NewMockClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:        filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:    tmux.NewMockChecker(),
+		GitChecker:     gitChecker,
+		ClaudeChecker:  claude.NewMockChecker(),
+		StatusCacheTTL: time.Minute,
+		Clock:          mockClock,
+	})
+
+	if err := manager.CreateSession("expiring"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	core := sessions[0].Core
+	gitChecker.SetStatus(core.WorktreePath, types.GitStatus{HasChanges: true})
+
+	mockClock.Advance(time.Minute)
+	if session, _ := manager.DeriveSession(core.ID); session.GitStatus.HasChanges {
+		t.Error("Expected cached status at the end of the TTL")
+	}
+
+	mockClock.Advance(time.Second)
+	if session, _ := manager.DeriveSession(core.ID); !session.GitStatus.HasChanges {
+		t.Error("Expected fresh status once the TTL passed")
+	}
+}
@@ -0,0 +1,292 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// ChangesIndexFileName is the file in the data directory that persists the
+// files each session's commits changed between cwt invocations
+const ChangesIndexFileName = "changes-index.json"
+
+// changesIndexEntry holds the files changed by a session's commits.
+// HeadStamp fingerprints the worktree's HEAD when they were listed, so the
+// entry is reused until a commit, reset, rebase or checkout moves it.
+type changesIndexEntry struct {
+	WorktreePath string              `json:"worktree_path"`
+	BaseBranch   string              `json:"base_branch"`
+	HeadStamp    string              `json:"head_stamp"`
+	Committed    []types.ChangedFile `json:"committed"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// changesIndex caches the committed changes of every session, shared across
+// processes through a file in the data directory. Entries check themselves
+// against the worktree's HEAD, so unlike the status cache they need no TTL
+// or invalidation. Uncommitted changes come from the status cache, which
+// already follows the worktrees.
+type changesIndex struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]changesIndexEntry
+	loaded  bool
+	dirty   bool
+}
+
+func newChangesIndex(dataDir string) *changesIndex {
+	return &changesIndex{
+		path:    filepath.Join(dataDir, ChangesIndexFileName),
+		entries: make(map[string]changesIndexEntry),
+	}
+}
+
+// get returns the indexed committed changes of a session if its HEAD hasn't
+// moved since they were listed
+func (c *changesIndex) get(core types.CoreSession, baseBranch, stamp string) ([]types.ChangedFile, bool) {
+	if stamp == "" {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	entry, ok := c.entries[core.ID]
+	if !ok || entry.WorktreePath != core.WorktreePath || entry.BaseBranch != baseBranch || entry.HeadStamp != stamp {
+		return nil, false
+	}
+	return entry.Committed, true
+}
+
+// put stores freshly listed changes; call flush to persist them
+func (c *changesIndex) put(sessionID string, entry changesIndexEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	c.entries[sessionID] = entry
+	c.dirty = true
+}
+
+// retain drops the entries of sessions that no longer exist
+func (c *changesIndex) retain(cores []types.CoreSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	known := make(map[string]bool, len(cores))
+	for _, core := range cores {
+		known[core.ID] = true
+	}
+	for id := range c.entries {
+		if !known[id] {
+			delete(c.entries, id)
+			c.dirty = true
+		}
+	}
+}
+
+// flush persists pending changes. Failures are ignored because the index
+// only saves work; the next invocation simply lists the changes again.
+func (c *changesIndex) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return
+	}
+	c.dirty = false
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return
+	}
+
+	writeCacheFile(c.path, data)
+}
+
+// load reads the index file once; the caller must hold c.mu
+func (c *changesIndex) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	var entries map[string]changesIndexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return // Corrupt index is treated as empty
+	}
+	for id, entry := range entries {
+		if _, ok := c.entries[id]; !ok {
+			c.entries[id] = entry
+		}
+	}
+}
+
+// headStamp fingerprints where a worktree's HEAD points without running
+// git. HEAD and its reflog are written whenever a commit, reset, rebase or
+// checkout moves it. It returns "" when the worktree's git directory can't
+// be read, so the changes are listed again.
+func headStamp(worktreePath string) string {
+	data, err := os.ReadFile(filepath.Join(worktreePath, ".git"))
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+
+	var parts []string
+	for _, name := range []string{"HEAD", filepath.Join("logs", "HEAD")} {
+		info, err := os.Stat(filepath.Join(gitDir, name))
+		if err != nil {
+			return ""
+		}
+		parts = append(parts, fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(parts, "/")
+}
+
+// ChangedFiles returns the files a session changed relative to the base
+// branch: those changed by its commits, from the changes index, and those
+// changed in its worktree, from the cached git status
+func (m *Manager) ChangedFiles(sessionID string) ([]types.ChangedFile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	for _, core := range cores {
+		if core.ID == sessionID {
+			files, err := m.changedFiles(core)
+			m.changes.flush()
+			return files, err
+		}
+	}
+	return nil, fmt.Errorf("session with ID %s not found", sessionID)
+}
+
+// ChangedFilesBySession returns the files every session changed, keyed by
+// session ID, for queries across sessions such as which ones touch a file.
+// Sessions whose changes can't be read are left out.
+func (m *Manager) ChangedFilesBySession() (map[string][]types.ChangedFile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	changes := make(map[string][]types.ChangedFile, len(cores))
+	for _, core := range cores {
+		if files, err := m.changedFiles(core); err == nil {
+			changes[core.ID] = files
+		}
+	}
+	m.changes.retain(cores)
+	m.changes.flush()
+
+	return changes, nil
+}
+
+// changedFiles combines a session's committed changes with its worktree
+// status; a file changed in both is reported as it is in the worktree
+func (m *Manager) changedFiles(core types.CoreSession) ([]types.ChangedFile, error) {
+	committed, err := m.committedChanges(core)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := m.worktreeStatus(core)
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]types.ChangedFile)
+	for _, file := range committed {
+		byPath[file.Path] = file
+	}
+	worktree := []struct {
+		paths  []string
+		change types.FileChange
+	}{
+		{status.ModifiedFiles, types.FileModified},
+		{status.AddedFiles, types.FileAdded},
+		{status.DeletedFiles, types.FileDeleted},
+		{status.UntrackedFiles, types.FileUntracked},
+	}
+	for _, group := range worktree {
+		for _, path := range group.paths {
+			change := group.change
+			if byPath[path].Change == types.FileAdded && change == types.FileModified {
+				change = types.FileAdded // Still new relative to the base branch
+			}
+			byPath[path] = types.ChangedFile{Path: path, Change: change}
+		}
+	}
+
+	files := make([]types.ChangedFile, 0, len(byPath))
+	for _, file := range byPath {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// committedChanges returns the files changed by a session's commits,
+// listing them with git only when its HEAD has moved
+func (m *Manager) committedChanges(core types.CoreSession) ([]types.ChangedFile, error) {
+	stamp := headStamp(core.WorktreePath)
+	if files, ok := m.changes.get(core, m.config.BaseBranch, stamp); ok {
+		return files, nil
+	}
+
+	files, err := m.config.GitChecker.CommittedChanges(core.WorktreePath)
+	if err != nil {
+		return nil, err
+	}
+	if stamp != "" {
+		m.changes.put(core.ID, changesIndexEntry{
+			WorktreePath: core.WorktreePath,
+			BaseBranch:   m.config.BaseBranch,
+			HeadStamp:    stamp,
+			Committed:    files,
+			UpdatedAt:    m.Now(),
+		})
+	}
+	return files, nil
+}
+
+// worktreeStatus returns a session's git status, from the status cache
+// while it is fresh
+func (m *Manager) worktreeStatus(core types.CoreSession) (types.GitStatus, error) {
+	if entry, ok := m.cache.get(core); ok && !entry.GitStatus.HasError() {
+		return entry.GitStatus, nil
+	}
+	return m.config.GitChecker.GetStatus(core.WorktreePath)
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// fakeWorktreeGitDir makes worktreePath look like a linked worktree whose
+// HEAD reflog is the returned file
+func fakeWorktreeGitDir(t *testing.T, worktreePath string) string {
+	t.Helper()
+	gitDir := filepath.Join(t.TempDir(), "worktrees", "session")
+	if err := os.MkdirAll(filepath.Join(gitDir, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/session\n"), 0644)
+	reflog := filepath.Join(gitDir, "logs", "HEAD")
+	os.WriteFile(reflog, []byte("0000 1111 commit\n"), 0644)
+
+	if err := os.MkdirAll(worktreePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(worktreePath, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644)
+	return reflog
+}
+
+func TestManager_ChangedFiles(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	newManager := func() *Manager {
+		return NewManager(Config{
+			DataDir:       dataDir,
+			TmuxChecker:   tmux.NewMockChecker(),
+			GitChecker:    gitChecker,
+			ClaudeChecker: claude.NewMockChecker(),
+		})
+	}
+	manager := newManager()
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	core := cores[0]
+	reflog := fakeWorktreeGitDir(t, core.WorktreePath)
+
+	gitChecker.Committed[core.WorktreePath] = []types.ChangedFile{
+		{Path: "auth/login.go", Change: types.FileModified, Committed: true},
+		{Path: "auth/token.go", Change: types.FileAdded, Committed: true},
+	}
+	gitChecker.Statuses[core.WorktreePath] = types.GitStatus{
+		HasChanges:     true,
+		ModifiedFiles:  []string{"auth/token.go"},
+		UntrackedFiles: []string{"notes.md"},
+	}
+
+	files, err := manager.ChangedFiles(core.ID)
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	want := []types.ChangedFile{
+		{Path: "auth/login.go", Change: types.FileModified, Committed: true},
+		{Path: "auth/token.go", Change: types.FileAdded}, // Still new, though edited since
+		{Path: "notes.md", Change: types.FileUntracked},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("ChangedFiles() = %+v, want %+v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("file %d = %+v, want %+v", i, files[i], want[i])
+		}
+	}
+
+	// Another process reuses the index until HEAD moves
+	other := newManager()
+	defer other.Close()
+	calls := gitChecker.DiffCalls
+	if _, err := other.ChangedFilesBySession(); err != nil {
+		t.Fatalf("ChangedFilesBySession() error = %v", err)
+	}
+	if gitChecker.DiffCalls != calls {
+		t.Error("committed changes should come from the index while HEAD hasn't moved")
+	}
+
+	os.WriteFile(reflog, []byte("0000 1111 commit\n1111 2222 commit\n"), 0644)
+	gitChecker.Committed[core.WorktreePath] = nil
+	changes, _ := other.ChangedFilesBySession()
+	if gitChecker.DiffCalls != calls+1 {
+		t.Error("a commit should list the committed changes again")
+	}
+	if len(changes[core.ID]) != 2 {
+		t.Errorf("changes after the commit = %+v, want only the worktree's", changes[core.ID])
+	}
+}
@@ -0,0 +1,114 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+)
+
+// syncClaudeProject gives a worktree the Claude configuration of the main
+// checkout, so Claude behaves there as it does in the main checkout: the
+// instructions, settings, slash commands and subagents git doesn't track,
+// like CLAUDE.local.md or .claude/settings.local.json, are copied, and paths
+// in them and in the tracked ones are adjusted for where the worktree is.
+// Adjusted tracked files have their changes hidden from git in the worktree
+// so they aren't committed. It returns the files it copied.
+func (m *Manager) syncClaudeProject(worktreePath string) ([]string, error) {
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	worktreeDir, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve worktree path: %w", err)
+	}
+	if repoDir == worktreeDir {
+		return nil, nil
+	}
+
+	var copied, checkedOut []string
+	for _, entry := range claude.ProjectFiles {
+		source := filepath.Join(repoDir, filepath.FromSlash(entry))
+		err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(repoDir, path)
+			if err != nil {
+				return err
+			}
+			target := filepath.Join(worktreeDir, rel)
+			if _, err := os.Lstat(target); err == nil {
+				checkedOut = append(checkedOut, rel)
+				return nil
+			}
+			if err := copyTree(path, target); err != nil {
+				return fmt.Errorf("failed to copy %s into the worktree: %w", rel, err)
+			}
+			copied = append(copied, rel)
+			return nil
+		})
+		if err != nil {
+			return copied, err
+		}
+	}
+
+	// Paths are adjusted once everything is copied, so imports of copied
+	// files aren't taken for imports of files the worktree lacks
+	relocation := claude.Relocation{RepoDir: repoDir, WorktreeDir: worktreeDir}
+	for _, rel := range copied {
+		if _, err := relocateFile(relocation, worktreeDir, rel); err != nil {
+			return copied, err
+		}
+	}
+	var hidden []string
+	for _, rel := range checkedOut {
+		changed, err := relocateFile(relocation, worktreeDir, rel)
+		if err != nil {
+			return copied, err
+		}
+		if changed {
+			hidden = append(hidden, filepath.ToSlash(rel))
+		}
+	}
+	if err := m.config.GitChecker.HideLocalChanges(worktreeDir, hidden); err != nil {
+		return copied, err
+	}
+	return copied, nil
+}
+
+// relocateFile adjusts the paths in a Claude project file of the worktree,
+// reporting whether it changed. Symlinks are left alone: they point at the
+// file they share.
+func relocateFile(relocation claude.Relocation, worktreeDir, rel string) (bool, error) {
+	path := filepath.Join(worktreeDir, rel)
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	relocated, changed, err := relocation.Relocate(filepath.ToSlash(rel), data)
+	if err != nil {
+		return false, fmt.Errorf("failed to adjust paths in %s: %w", rel, err)
+	}
+	if !changed {
+		return false, nil
+	}
+	if err := os.WriteFile(path, relocated, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to adjust paths in %s: %w", rel, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_SyncClaudeProject(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	worktree := filepath.Join(repo, ".cwt", "worktrees", "auth")
+	write := func(path, contents string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(root, "shared.md"), "shared\n")
+	write(filepath.Join(repo, "CLAUDE.md"), "Read @../shared.md\n")
+	write(filepath.Join(repo, "CLAUDE.local.md"), "My notes\n")
+	write(filepath.Join(repo, ".claude", "settings.local.json"), `{"model": "opus"}`)
+	write(filepath.Join(repo, ".claude", "commands", "review.md"), "Review the diff\n")
+	// What git checked out in the worktree
+	write(filepath.Join(worktree, "CLAUDE.md"), "Read @../shared.md\n")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(repo, ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	copied, err := manager.syncClaudeProject(worktree)
+	if err != nil {
+		t.Fatalf("syncClaudeProject() error = %v", err)
+	}
+	slices.Sort(copied)
+	want := []string{"CLAUDE.local.md", ".claude/commands/review.md", ".claude/settings.local.json"}
+	slices.Sort(want)
+	if !slices.Equal(copied, want) {
+		t.Errorf("copied %v, want %v", copied, want)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(worktree, "CLAUDE.md"))
+	if string(data) != "Read @"+filepath.Join(root, "shared.md")+"\n" {
+		t.Errorf("the import leaving the repository should be made absolute, got %q", data)
+	}
+	if hidden := gitChecker.Hidden[worktree]; !slices.Equal(hidden, []string{"CLAUDE.md"}) {
+		t.Errorf("hidden %v, want the adjusted tracked CLAUDE.md", hidden)
+	}
+
+	// Sessions get it on creation
+	if err := manager.CreateSession("docs"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	settings, err := os.ReadFile(filepath.Join(cores[0].WorktreePath, ".claude", "settings.local.json"))
+	if err != nil || !strings.Contains(string(settings), "opus") {
+		t.Errorf("settings.local.json should be copied into a new session, got %q, %v", settings, err)
+	}
+	if _, err := os.Stat(filepath.Join(cores[0].WorktreePath, "CLAUDE.local.md")); err != nil {
+		t.Errorf("CLAUDE.local.md should be copied into a new session: %v", err)
+	}
+}
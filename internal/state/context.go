@@ -0,0 +1,255 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+)
+
+// ContextFileName is the file in the data directory listing the shared
+// context files every session's worktree gets
+const ContextFileName = "context.json"
+
+// ContextFiles lists the shared context files, relative to the repository,
+// in the order they were registered
+func (m *Manager) ContextFiles() ([]string, error) {
+	m.contextMu.Lock()
+	defer m.contextMu.Unlock()
+	return m.loadContextFiles()
+}
+
+// AddContextFiles registers shared context files and links them into the
+// worktrees of the existing sessions. Files are given relative to the
+// repository, or as absolute paths inside it, and must exist. It returns
+// the files as registered.
+func (m *Manager) AddContextFiles(files []string) ([]string, error) {
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	added := make([]string, 0, len(files))
+	for _, file := range files {
+		relative, err := contextPath(repoDir, file)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, relative)
+	}
+
+	m.contextMu.Lock()
+	registered, err := m.loadContextFiles()
+	if err == nil {
+		for _, file := range added {
+			if !slices.Contains(registered, file) {
+				registered = append(registered, file)
+			}
+		}
+		err = m.saveContextFiles(registered)
+	}
+	m.contextMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	m.syncContextFiles()
+	return added, nil
+}
+
+// RemoveContextFiles unregisters shared context files and takes them out
+// of the worktrees of the existing sessions
+func (m *Manager) RemoveContextFiles(files []string) error {
+	m.contextMu.Lock()
+	registered, err := m.loadContextFiles()
+	if err == nil {
+		for _, file := range files {
+			file = filepath.ToSlash(filepath.Clean(file))
+			index := slices.Index(registered, file)
+			if index < 0 {
+				err = fmt.Errorf("'%s' is not a shared context file", file)
+				break
+			}
+			registered = slices.Delete(registered, index, index+1)
+		}
+	}
+	if err == nil {
+		err = m.saveContextFiles(registered)
+	}
+	m.contextMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	m.syncContextFiles()
+	return nil
+}
+
+// contextPath checks a shared context file exists inside the repository
+// and returns its path relative to it
+func contextPath(repoDir, file string) (string, error) {
+	absolute := file
+	if !filepath.IsAbs(absolute) {
+		absolute = filepath.Join(repoDir, file)
+	}
+	relative, err := filepath.Rel(repoDir, absolute)
+	if err != nil || relative == "." || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("'%s' is not inside the repository", file)
+	}
+
+	info, err := os.Stat(absolute)
+	if err != nil {
+		return "", fmt.Errorf("context file '%s' not found: %w", file, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("context file '%s' is not a regular file", file)
+	}
+	return filepath.ToSlash(relative), nil
+}
+
+// ContextPrompt is what a session's initial prompt says about the shared
+// context files, pointing at their copies in its worktree
+func ContextPrompt(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = "- " + git.ContextDir + "/" + file
+	}
+	return "Before starting, read the shared project context, which every session follows:\n" + strings.Join(paths, "\n")
+}
+
+// initialPrompt is the prompt a session's Claude starts with: its task,
+// followed by where to find the shared context files
+func (m *Manager) initialPrompt(task string) string {
+	if task == "" {
+		return ""
+	}
+	files, err := m.ContextFiles()
+	if err != nil {
+		logger.Warn("failed to read shared context files", "error", err)
+	}
+	if prompt := ContextPrompt(files); prompt != "" {
+		return task + "\n\n" + prompt
+	}
+	return task
+}
+
+// syncContextFiles brings the shared context files of every session's
+// worktree up to date with the registered ones
+func (m *Manager) syncContextFiles() {
+	cores, err := m.CoreSessions()
+	if err != nil {
+		logger.Warn("failed to load sessions to share context files", "error", err)
+		return
+	}
+	for _, core := range cores {
+		if _, err := os.Stat(core.WorktreePath); err != nil {
+			continue
+		}
+		if err := m.linkContextFiles(core.WorktreePath); err != nil {
+			logger.Warn("failed to share context files", "session", core.Name, "error", err)
+		}
+	}
+}
+
+// linkContextFiles recreates the shared context directory of a worktree,
+// symlinking each registered file so edits to it reach every session, or
+// copying it where symlinks aren't supported
+func (m *Manager) linkContextFiles(worktreePath string) error {
+	files, err := m.ContextFiles()
+	if err != nil {
+		return err
+	}
+
+	contextDir := filepath.Join(worktreePath, git.ContextDir)
+	if err := os.RemoveAll(contextDir); err != nil {
+		return fmt.Errorf("failed to clear shared context directory: %w", err)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	for _, file := range files {
+		source := filepath.Join(repoDir, filepath.FromSlash(file))
+		target := filepath.Join(contextDir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create shared context directory: %w", err)
+		}
+		if err := os.Symlink(source, target); err != nil {
+			if err := copyFile(source, target); err != nil {
+				return fmt.Errorf("failed to share context file %s: %w", file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// copyFile copies a file's contents to a new file
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// loadContextFiles reads the context file; callers hold contextMu
+func (m *Manager) loadContextFiles() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(m.config.DataDir, ContextFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read context file: %w", err)
+	}
+	var files []string
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("context file corrupted: %w", err)
+	}
+	return files, nil
+}
+
+// saveContextFiles writes the context file atomically; callers hold
+// contextMu
+func (m *Manager) saveContextFiles(files []string) error {
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal context files: %w", err)
+	}
+
+	if err := os.MkdirAll(m.config.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	path := filepath.Join(m.config.DataDir, ContextFileName)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
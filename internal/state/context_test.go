@@ -0,0 +1,86 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_ContextFiles(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "docs", "architecture.md"), []byte("# Architecture\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:          filepath.Join(repo, ".cwt"),
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       git.NewMockChecker(),
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("before"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if _, err := manager.AddContextFiles([]string{"docs/missing.md"}); err == nil {
+		t.Error("AddContextFiles() should reject a file that doesn't exist")
+	}
+	if _, err := manager.AddContextFiles([]string{"../outside.md"}); err == nil {
+		t.Error("AddContextFiles() should reject a file outside the repository")
+	}
+
+	added, err := manager.AddContextFiles([]string{filepath.Join(repo, "docs", "architecture.md"), "docs/./architecture.md"})
+	if err != nil {
+		t.Fatalf("AddContextFiles() error = %v", err)
+	}
+	files, _ := manager.ContextFiles()
+	if len(added) != 2 || len(files) != 1 || files[0] != "docs/architecture.md" {
+		t.Fatalf("ContextFiles() = %v, want docs/architecture.md once", files)
+	}
+
+	// Existing sessions get the file, and it's left out of their commits
+	before := filepath.Join(repo, ".cwt", "worktrees", "before")
+	if data, err := os.ReadFile(filepath.Join(before, git.ContextDir, "docs", "architecture.md")); err != nil || string(data) != "# Architecture\n" {
+		t.Errorf("shared file = %q, %v, want the repository's", data, err)
+	}
+	if written := git.CwtWrittenFiles(before); len(written) != 2 || written[1] != git.ContextDir {
+		t.Errorf("CwtWrittenFiles() = %v, want the context directory", written)
+	}
+
+	// New sessions started with a task are told to read it
+	if err := manager.CreateSessionWithOptions("after", CreateOptions{Task: "Add login"}); err != nil {
+		t.Fatalf("CreateSessionWithOptions() error = %v", err)
+	}
+	command := tmuxChecker.SessionCommands["cwt-after"]
+	if !strings.Contains(command, "Add login") || !strings.Contains(command, git.ContextDir+"/docs/architecture.md") {
+		t.Errorf("command = %q, want the task and the shared file", command)
+	}
+
+	if err := manager.RemoveContextFiles([]string{"docs/other.md"}); err == nil {
+		t.Error("RemoveContextFiles() should reject a file that isn't shared")
+	}
+	if err := manager.RemoveContextFiles([]string{"docs/architecture.md"}); err != nil {
+		t.Fatalf("RemoveContextFiles() error = %v", err)
+	}
+	if files, _ := manager.ContextFiles(); len(files) != 0 {
+		t.Errorf("ContextFiles() = %v, want none", files)
+	}
+	if _, err := os.Lstat(filepath.Join(before, git.ContextDir)); !os.IsNotExist(err) {
+		t.Errorf("context directory still in the worktree: %v", err)
+	}
+}
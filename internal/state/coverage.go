@@ -0,0 +1,273 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// CoverageFileName is the file in the data directory that keeps the base
+// branch's coverage, so the tests run on each base commit only once
+const CoverageFileName = "coverage.json"
+
+// coverageBaselinesKept is how many base branch measurements the coverage
+// file keeps
+const coverageBaselinesKept = 20
+
+// coverageBaseline is the coverage a command measured on a commit of the
+// base branch
+type coverageBaseline struct {
+	Commit     string         `json:"commit"`
+	Command    string         `json:"command"`
+	Coverage   types.Coverage `json:"coverage"`
+	MeasuredAt time.Time      `json:"measured_at"`
+}
+
+// measureCoverage reads the coverage profile a follow-up command started at
+// started wrote in a session's worktree and measures the same command's
+// coverage on the base branch to compare it with. It leaves result alone
+// when no profile is configured or the command didn't write one.
+func (m *Manager) measureCoverage(ctx context.Context, worktree, command string, started time.Time, result *types.FollowUpResult) {
+	if m.config.CoverageProfile == "" {
+		return
+	}
+	coverage, err := m.readCoverage(worktree, started)
+	if err != nil {
+		logger.Warn("failed to read coverage profile", "worktree", worktree, "error", err)
+		return
+	}
+	if coverage == nil {
+		return
+	}
+	result.Coverage = coverage
+
+	base, err := m.baseCoverage(ctx, command)
+	if err != nil {
+		logger.Warn("failed to measure base branch coverage", "base", m.config.BaseBranch, "error", err)
+		return
+	}
+	result.BaseCoverage = base
+}
+
+// readCoverage reads the coverage profile in dir, returning nil when there
+// is none written since since
+func (m *Manager) readCoverage(dir string, since time.Time) (*types.Coverage, error) {
+	path := filepath.Join(dir, m.config.CoverageProfile)
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// A profile left over from an earlier run isn't this run's coverage
+	if info.ModTime().Before(since.Truncate(time.Second)) {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	coverage, err := parseCoverageProfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.config.CoverageProfile, err)
+	}
+	return &coverage, nil
+}
+
+// baseCoverage returns command's coverage on the base branch as it is now,
+// running it in a temporary worktree of the base branch unless it was
+// already measured on that commit. It returns nil when the command writes
+// no profile there.
+func (m *Manager) baseCoverage(ctx context.Context, command string) (*types.Coverage, error) {
+	commit, err := m.config.GitChecker.ResolveCommit(m.config.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	m.coverageMu.Lock()
+	defer m.coverageMu.Unlock()
+
+	baselines, err := m.loadCoverageBaselines()
+	if err != nil {
+		return nil, err
+	}
+	for _, baseline := range baselines {
+		if baseline.Commit == commit && baseline.Command == command {
+			return &baseline.Coverage, nil
+		}
+	}
+
+	logger.Info("measuring base branch coverage", "base", m.config.BaseBranch, "commit", commit, "command", command)
+	worktree := filepath.Join(m.config.DataDir, "coverage", fmt.Sprintf("%s-%d", shortCommit(commit), time.Now().UnixNano()))
+	if err := m.config.GitChecker.AddDetachedWorktree(commit, worktree); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := m.config.GitChecker.RemoveWorktree(worktree); err != nil {
+			logger.Warn("failed to remove coverage worktree", "path", worktree, "error", err)
+		}
+		os.RemoveAll(worktree)
+	}()
+	if err := m.config.GitChecker.PopulateWorktree(ctx, worktree, nil); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	run := m.runFollowUpCommand(ctx, worktree, command)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	coverage, err := m.readCoverage(worktree, started)
+	if err != nil || coverage == nil {
+		return nil, err
+	}
+	if run.ExitCode != 0 {
+		logger.Info("base branch tests failed, using their coverage anyway", "exit_code", run.ExitCode)
+	}
+
+	baselines = append(baselines, coverageBaseline{Commit: commit, Command: command, Coverage: *coverage, MeasuredAt: m.Now()})
+	if len(baselines) > coverageBaselinesKept {
+		baselines = baselines[len(baselines)-coverageBaselinesKept:]
+	}
+	if err := m.saveCoverageBaselines(baselines); err != nil {
+		return nil, err
+	}
+	return coverage, nil
+}
+
+// shortCommit abbreviates a commit hash
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+// loadCoverageBaselines reads the coverage file; callers hold coverageMu
+func (m *Manager) loadCoverageBaselines() ([]coverageBaseline, error) {
+	data, err := os.ReadFile(filepath.Join(m.config.DataDir, CoverageFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read coverage file: %w", err)
+	}
+	var baselines []coverageBaseline
+	if err := json.Unmarshal(data, &baselines); err != nil {
+		return nil, fmt.Errorf("coverage file corrupted: %w", err)
+	}
+	return baselines, nil
+}
+
+// saveCoverageBaselines writes the coverage file atomically; callers hold
+// coverageMu
+func (m *Manager) saveCoverageBaselines(baselines []coverageBaseline) error {
+	data, err := json.MarshalIndent(baselines, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal coverage: %w", err)
+	}
+
+	path := filepath.Join(m.config.DataDir, CoverageFileName)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// parseCoverageProfile totals a coverage profile: a Go cover profile, as
+// written by 'go test -coverprofile', or an LCOV report
+func parseCoverageProfile(data []byte) (types.Coverage, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("mode:")):
+		return parseGoCoverProfile(data)
+	case bytes.Contains(data, []byte("\nLF:")) || bytes.HasPrefix(data, []byte("LF:")):
+		return parseLCOV(data)
+	default:
+		return types.Coverage{}, fmt.Errorf("not a Go cover profile or LCOV report")
+	}
+}
+
+// parseGoCoverProfile counts the statements in a Go cover profile and those
+// run. Profiles merged from several packages list a block once for each; it
+// counts once, as covered if any of them ran it.
+func parseGoCoverProfile(data []byte) (types.Coverage, error) {
+	blocks := make(map[string]int64) // Statements of each block
+	covered := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// file.go:12.34,15.2 3 1
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return types.Coverage{}, fmt.Errorf("malformed cover profile line %q", line)
+		}
+		statements, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return types.Coverage{}, fmt.Errorf("malformed cover profile line %q", line)
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return types.Coverage{}, fmt.Errorf("malformed cover profile line %q", line)
+		}
+		blocks[fields[0]] = statements
+		if count > 0 {
+			covered[fields[0]] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return types.Coverage{}, err
+	}
+
+	var coverage types.Coverage
+	for block, statements := range blocks {
+		coverage.Total += statements
+		if covered[block] {
+			coverage.Covered += statements
+		}
+	}
+	return coverage, nil
+}
+
+// parseLCOV totals the lines found (LF) and hit (LH) by every record of an
+// LCOV report
+func parseLCOV(data []byte) (types.Coverage, error) {
+	var coverage types.Coverage
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || (key != "LF" && key != "LH") {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return types.Coverage{}, fmt.Errorf("malformed LCOV line %q", line)
+		}
+		if key == "LF" {
+			coverage.Total += n
+		} else {
+			coverage.Covered += n
+		}
+	}
+	return coverage, nil
+}
@@ -0,0 +1,130 @@
+package state
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestParseCoverageProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    types.Coverage
+		wantErr bool
+	}{
+		{
+			name:    "go cover profile",
+			profile: "mode: set\na.go:1.1,3.2 3 1\na.go:4.1,5.2 1 0\n",
+			want:    types.Coverage{Covered: 3, Total: 4},
+		},
+		{
+			// Merged from two packages' runs: covered if either ran it
+			name:    "go cover profile with repeated blocks",
+			profile: "mode: count\na.go:1.1,3.2 3 0\nb.go:1.1,2.2 2 0\na.go:1.1,3.2 3 4\n",
+			want:    types.Coverage{Covered: 3, Total: 5},
+		},
+		{
+			name:    "lcov",
+			profile: "TN:\nSF:src/a.js\nDA:1,1\nLF:10\nLH:7\nend_of_record\nSF:src/b.js\nLF:10\nLH:1\nend_of_record\n",
+			want:    types.Coverage{Covered: 8, Total: 20},
+		},
+		{name: "malformed go profile", profile: "mode: set\na.go:1.1,3.2 three 1\n", wantErr: true},
+		{name: "unknown format", profile: "<coverage line-rate=\"0.8\"/>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCoverageProfile([]byte(tt.profile))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCoverageProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseCoverageProfile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_FollowUpCoverage(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	gitChecker.Commits["main"] = "0123456789abcdef"
+	manager := NewManager(Config{
+		DataDir:         filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:     tmux.NewMockChecker(),
+		GitChecker:      gitChecker,
+		ClaudeChecker:   claude.NewMockChecker(),
+		BaseBranch:      "main",
+		CoverageProfile: "cover.out",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("tests"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	sessionID := cores[0].ID
+	worktree := t.TempDir()
+	manager.UpdateSession(sessionID, func(core *types.CoreSession) { core.WorktreePath = worktree })
+
+	// The session's tests cover 3 of 4 statements, the base branch's 1 of 2
+	if err := os.WriteFile(filepath.Join(worktree, "session-marker"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	command := `if [ -f session-marker ]; then printf 'mode: set\na.go:1.1,3.2 3 1\na.go:4.1,5.2 1 0\n'; ` +
+		`else printf 'mode: set\na.go:1.1,3.2 1 1\na.go:4.1,5.2 1 0\n'; fi > cover.out; exit 1`
+
+	run := func() *types.FollowUp {
+		t.Helper()
+		if err := manager.SetFollowUp(sessionID, types.FollowUpOnComplete, command); err != nil {
+			t.Fatalf("SetFollowUp() error = %v", err)
+		}
+		finished, err := manager.RunFollowUp(context.Background(), sessionID)
+		if err != nil || finished == nil {
+			t.Fatalf("RunFollowUp() = %+v, %v; want the follow-up run", finished, err)
+		}
+		return finished
+	}
+
+	// Failing tests still measure coverage
+	finished := run()
+	if got := finished.Result.Coverage; got == nil || *got != (types.Coverage{Covered: 3, Total: 4}) {
+		t.Errorf("coverage = %+v, want 3 of 4", got)
+	}
+	if got := finished.Result.BaseCoverage; got == nil || *got != (types.Coverage{Covered: 1, Total: 2}) {
+		t.Errorf("base coverage = %+v, want 1 of 2", got)
+	}
+	if got := finished.Result.Coverage.Describe(finished.Result.BaseCoverage, "main"); got != "75.0% (+25.0% vs main)" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if left, _ := os.ReadDir(filepath.Join(manager.GetDataDir(), "coverage")); len(left) != 0 {
+		t.Errorf("base branch worktrees left behind: %v", left)
+	}
+
+	// The base branch is measured once per commit
+	if got := run().Result.BaseCoverage; got == nil || got.Covered != 1 {
+		t.Errorf("base coverage = %+v, want it read from the coverage file", got)
+	}
+	if len(gitChecker.Detached) != 1 || gitChecker.Detached[0] != "0123456789abcdef" {
+		t.Errorf("checked out %v, want the base commit once", gitChecker.Detached)
+	}
+	baselines, _ := manager.loadCoverageBaselines()
+	if len(baselines) != 1 || baselines[0].Commit != "0123456789abcdef" {
+		t.Errorf("baselines = %+v, want one for the base commit", baselines)
+	}
+
+	// A profile the command didn't write this run isn't its coverage
+	command = "true"
+	longAgo := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(worktree, "cover.out"), longAgo, longAgo)
+	if got := run().Result.Coverage; got != nil {
+		t.Errorf("coverage = %+v, want none from a stale profile", got)
+	}
+}
@@ -0,0 +1,58 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// Environment variables every session's tmux session gets, so Claude and
+// the tools it runs can tell which session they are in
+const (
+	EnvSessionName = "CWT_SESSION_NAME"
+	EnvSessionID   = "CWT_SESSION_ID"
+	EnvBaseBranch  = "CWT_BASE_BRANCH"
+	EnvWorktree    = "CWT_WORKTREE" // Absolute path of the session's worktree
+)
+
+// SessionEnv returns the environment of a session's tmux session, as
+// KEY=value: the extra variables of the config, then those of the session,
+// then cwt's own, which the others can't override. Extra values can refer
+// to cwt's variables and the environment, like ${CWT_SESSION_NAME}.
+func (m *Manager) SessionEnv(core types.CoreSession) []string {
+	worktree, err := filepath.Abs(core.WorktreePath)
+	if err != nil {
+		worktree = core.WorktreePath
+	}
+	own := map[string]string{
+		EnvSessionName: core.Name,
+		EnvSessionID:   core.ID,
+		EnvBaseBranch:  m.config.BaseBranch,
+		EnvWorktree:    worktree,
+	}
+	expand := func(name string) string {
+		if value, ok := own[name]; ok {
+			return value
+		}
+		return os.Getenv(name)
+	}
+
+	merged := make(map[string]string)
+	for _, extra := range []map[string]string{m.config.Env, core.Env} {
+		for name, value := range extra {
+			merged[name] = os.Expand(value, expand)
+		}
+	}
+	for name, value := range own {
+		merged[name] = value
+	}
+
+	env := make([]string, 0, len(merged))
+	for name, value := range merged {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
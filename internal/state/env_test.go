@@ -0,0 +1,56 @@
+package state
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_SessionEnv(t *testing.T) {
+	t.Setenv("CWT_TEST_HOST", "db.local")
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		BaseBranch:    "develop",
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		Env: map[string]string{
+			"DATABASE_URL": "postgres://${CWT_TEST_HOST}/app_${CWT_SESSION_NAME}",
+			"NODE_ENV":     "development",
+		},
+	})
+	defer manager.Close()
+
+	opts := CreateOptions{Env: map[string]string{"NODE_ENV": "test", "CWT_SESSION_NAME": "spoofed"}}
+	if err := manager.CreateSessionWithOptions("auth", opts); err != nil {
+		t.Fatalf("CreateSessionWithOptions() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	core := cores[0]
+	if core.Env["NODE_ENV"] != "test" {
+		t.Errorf("the session's env should be stored, got %v", core.Env)
+	}
+
+	env := tmuxChecker.SessionEnv[core.TmuxSession]
+	worktree, _ := filepath.Abs(core.WorktreePath)
+	for _, want := range []string{
+		"CWT_BASE_BRANCH=develop",
+		"CWT_SESSION_ID=" + core.ID,
+		"CWT_SESSION_NAME=auth",
+		"CWT_WORKTREE=" + worktree,
+		"DATABASE_URL=postgres://db.local/app_auth",
+		"NODE_ENV=test",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("tmux session env %v lacks %s", env, want)
+		}
+	}
+	if len(env) != 6 {
+		t.Errorf("env = %v, want 6 variables", env)
+	}
+}
@@ -0,0 +1,138 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// SetFollowUp registers a command to run once in a session's worktree when
+// the session reaches a state, replacing any follow-up it already has. The
+// daemon runs it; see RunFollowUp.
+func (m *Manager) SetFollowUp(sessionID string, on types.FollowUpTrigger, command string) error {
+	if on != types.FollowUpOnComplete {
+		return fmt.Errorf("unknown follow-up trigger %q (supported: %s)", on, types.FollowUpOnComplete)
+	}
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("follow-up command cannot be empty")
+	}
+
+	return m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.FollowUp = &types.FollowUp{On: on, Command: command, CreatedAt: m.Now()}
+	})
+}
+
+// ClearFollowUp removes a session's follow-up and its result
+func (m *Manager) ClearFollowUp(sessionID string) error {
+	return m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.FollowUp = nil
+	})
+}
+
+// DueFollowUps returns the sessions whose follow-up is waiting for a state
+// they reached after it was registered. A session already complete when its
+// follow-up is registered runs it the next time it completes.
+func DueFollowUps(sessions []types.Session) []types.Session {
+	var due []types.Session
+	for _, session := range sessions {
+		followUp := session.Core.FollowUp
+		if followUp == nil || !followUp.Pending() {
+			continue
+		}
+		if followUp.On == types.FollowUpOnComplete && session.ClaudeStatus.State == types.ClaudeComplete &&
+			session.ClaudeStatus.LastMessage.After(followUp.CreatedAt) {
+			due = append(due, session)
+		}
+	}
+	return due
+}
+
+// RunFollowUp runs a session's pending follow-up in its worktree and records
+// the result, returning the finished follow-up. It returns nil when there is
+// no pending follow-up, so a follow-up runs once however often it is called.
+// Cancelling ctx kills the command.
+func (m *Manager) RunFollowUp(ctx context.Context, sessionID string) (*types.FollowUp, error) {
+	// Marking it started under the manager's lock claims it for this run
+	var started *types.FollowUp
+	var worktree, name string
+	err := m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		if core.FollowUp == nil || !core.FollowUp.Pending() {
+			return
+		}
+		now := m.Now()
+		followUp := *core.FollowUp
+		followUp.StartedAt = &now
+		core.FollowUp = &followUp
+		started = &followUp
+		worktree, name = core.WorktreePath, core.Name
+	})
+	if err != nil || started == nil {
+		return nil, err
+	}
+
+	logger.Info("running follow-up", "session", name, "command", started.Command)
+	result := m.runFollowUpCommand(ctx, worktree, started.Command)
+	m.measureCoverage(ctx, worktree, started.Command, *started.StartedAt, &result)
+
+	finished := *started
+	finished.Result = &result
+	err = m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		// Leave a follow-up registered while this one ran alone
+		if core.FollowUp != nil && core.FollowUp.CreatedAt.Equal(started.CreatedAt) {
+			core.FollowUp = &finished
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record follow-up result: %w", err)
+	}
+
+	m.RecordEvent(sessionID, types.EventFollowUp, fmt.Sprintf("%s: %s", finished.Command, finished.Summary()), map[string]interface{}{
+		"exit_code": result.ExitCode,
+	})
+	return &finished, nil
+}
+
+// RunCheck runs a command in a session's worktree the way a follow-up runs,
+// like a test gate before merging, and returns how it went. Unlike a
+// follow-up, nothing is saved with the session. Cancelling ctx kills the
+// command.
+func (m *Manager) RunCheck(ctx context.Context, sessionID, command string) (types.FollowUpResult, error) {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return types.FollowUpResult{}, err
+	}
+
+	logger.Info("running check", "session", core.Name, "command", command)
+	return m.runFollowUpCommand(ctx, core.WorktreePath, command), nil
+}
+
+// runFollowUpCommand runs a follow-up command with sh in a worktree, adding
+// env, as KEY=value, to cwt's environment
+func (m *Manager) runFollowUpCommand(ctx context.Context, dir, command string, env ...string) types.FollowUpResult {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	// Don't wait on children that outlive a killed command holding its output
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return types.NewFollowUpResult(m.Now(), 0, string(output), nil)
+	case ctx.Err() != nil:
+		return types.NewFollowUpResult(m.Now(), -1, string(output), fmt.Errorf("interrupted: %w", ctx.Err()))
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		return types.NewFollowUpResult(m.Now(), exitErr.ExitCode(), string(output), nil)
+	default:
+		return types.NewFollowUpResult(m.Now(), -1, string(output), err)
+	}
+}
@@ -0,0 +1,128 @@
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_FollowUp(t *testing.T) {
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("tests"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	sessionID := cores[0].ID
+	worktree := t.TempDir()
+	manager.UpdateSession(sessionID, func(core *types.CoreSession) { core.WorktreePath = worktree })
+
+	if err := manager.SetFollowUp(sessionID, "idle", "true"); err == nil {
+		t.Error("Expected error for an unknown trigger")
+	}
+	if err := manager.SetFollowUp(sessionID, types.FollowUpOnComplete, " "); err == nil {
+		t.Error("Expected error for an empty command")
+	}
+	if err := manager.SetFollowUp(sessionID, types.FollowUpOnComplete, "pwd; echo failing >&2; exit 3"); err != nil {
+		t.Fatalf("SetFollowUp() error = %v", err)
+	}
+
+	// Only a completion reported after registering makes it due
+	cores, _ = manager.CoreSessions()
+	followUp := cores[0].FollowUp
+	session := types.Session{Core: cores[0], ClaudeStatus: types.ClaudeStatus{
+		State:       types.ClaudeComplete,
+		LastMessage: followUp.CreatedAt.Add(-time.Minute),
+	}}
+	if due := DueFollowUps([]types.Session{session}); len(due) != 0 {
+		t.Errorf("DueFollowUps() = %d sessions, want none for an earlier completion", len(due))
+	}
+	session.ClaudeStatus.LastMessage = followUp.CreatedAt.Add(time.Minute)
+	if due := DueFollowUps([]types.Session{session}); len(due) != 1 {
+		t.Errorf("DueFollowUps() = %d sessions, want the completed one", len(due))
+	}
+	session.ClaudeStatus.State = types.ClaudeWorking
+	if due := DueFollowUps([]types.Session{session}); len(due) != 0 {
+		t.Errorf("DueFollowUps() = %d sessions, want none while working", len(due))
+	}
+
+	finished, err := manager.RunFollowUp(context.Background(), sessionID)
+	if err != nil || finished == nil {
+		t.Fatalf("RunFollowUp() = %+v, %v; want the follow-up run", finished, err)
+	}
+	if finished.Result.ExitCode != 3 || finished.Succeeded() {
+		t.Errorf("result = %+v, want exit status 3", finished.Result)
+	}
+	if tail := finished.Tail(2); len(tail) != 2 || tail[0] != worktree || tail[1] != "failing" {
+		t.Errorf("output = %q, want it run in the worktree with stderr kept", tail)
+	}
+
+	cores, _ = manager.CoreSessions()
+	if recorded := cores[0].FollowUp; recorded == nil || recorded.Result == nil || recorded.Result.ExitCode != 3 {
+		t.Errorf("recorded follow-up = %+v, want the result saved with the session", recorded)
+	}
+
+	// It runs only once
+	if again, err := manager.RunFollowUp(context.Background(), sessionID); err != nil || again != nil {
+		t.Errorf("RunFollowUp() again = %+v, %v; want nothing run", again, err)
+	}
+
+	events, _ := manager.Timeline(sessionID)
+	if len(events) == 0 || events[len(events)-1].Type != types.EventFollowUp {
+		t.Errorf("timeline = %+v, want the follow-up recorded last", events)
+	}
+
+	if err := manager.ClearFollowUp(sessionID); err != nil {
+		t.Fatalf("ClearFollowUp() error = %v", err)
+	}
+	cores, _ = manager.CoreSessions()
+	if cores[0].FollowUp != nil {
+		t.Error("ClearFollowUp() should remove the follow-up")
+	}
+}
+
+func TestManager_RunCheck(t *testing.T) {
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("tests"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	sessionID := cores[0].ID
+	worktree := t.TempDir()
+	manager.UpdateSession(sessionID, func(core *types.CoreSession) { core.WorktreePath = worktree })
+
+	result, err := manager.RunCheck(context.Background(), sessionID, "pwd; exit 2")
+	if err != nil {
+		t.Fatalf("RunCheck() error = %v", err)
+	}
+	if result.ExitCode != 2 || !strings.Contains(result.Output, filepath.Base(worktree)) {
+		t.Errorf("result = %+v, want exit 2 from the worktree", result)
+	}
+	if cores, _ := manager.CoreSessions(); cores[0].FollowUp != nil {
+		t.Error("a check should not be saved as a follow-up")
+	}
+
+	if _, err := manager.RunCheck(context.Background(), "missing", "true"); err == nil {
+		t.Error("Expected error for an unknown session")
+	}
+}
@@ -0,0 +1,172 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// ConflictForecastFileName is the file in the data directory that persists
+// the conflicts predicted for each session's branch between cwt invocations
+const ConflictForecastFileName = "conflict-forecast.json"
+
+// conflictForecastEntry holds the files that would conflict merging a
+// session's branch into the base branch, predicted when the branch and the
+// base branch were at BranchCommit and BaseCommit
+type conflictForecastEntry struct {
+	BranchCommit string    `json:"branch_commit"`
+	BaseCommit   string    `json:"base_commit"`
+	Files        []string  `json:"files,omitempty"`
+	PredictedAt  time.Time `json:"predicted_at"`
+}
+
+// conflictForecast caches the conflicts predicted for every session, shared
+// across processes through a file in the data directory. A merge of the same
+// two commits always goes the same way, so entries hold until either side
+// gets new commits and need no TTL or invalidation.
+type conflictForecast struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]conflictForecastEntry
+	loaded  bool
+	dirty   bool
+}
+
+func newConflictForecast(dataDir string) *conflictForecast {
+	return &conflictForecast{
+		path:    filepath.Join(dataDir, ConflictForecastFileName),
+		entries: make(map[string]conflictForecastEntry),
+	}
+}
+
+// get returns the conflicts predicted for a session if neither its branch
+// nor the base branch moved since
+func (c *conflictForecast) get(sessionID, branchCommit, baseCommit string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	entry, ok := c.entries[sessionID]
+	if !ok || entry.BranchCommit != branchCommit || entry.BaseCommit != baseCommit {
+		return nil, false
+	}
+	return entry.Files, true
+}
+
+// put stores a fresh prediction; call flush to persist it
+func (c *conflictForecast) put(sessionID string, entry conflictForecastEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	c.entries[sessionID] = entry
+	c.dirty = true
+}
+
+// retain drops the entries of sessions that no longer exist
+func (c *conflictForecast) retain(cores []types.CoreSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	known := make(map[string]bool, len(cores))
+	for _, core := range cores {
+		known[core.ID] = true
+	}
+	for id := range c.entries {
+		if !known[id] {
+			delete(c.entries, id)
+			c.dirty = true
+		}
+	}
+}
+
+// flush persists pending predictions. Failures are ignored because the
+// forecast only saves work; the next invocation simply predicts again.
+func (c *conflictForecast) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return
+	}
+	c.dirty = false
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return
+	}
+
+	writeCacheFile(c.path, data)
+}
+
+// load reads the forecast file once; the caller must hold c.mu
+func (c *conflictForecast) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	var entries map[string]conflictForecastEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return // Corrupt forecast is treated as empty
+	}
+	for id, entry := range entries {
+		if _, ok := c.entries[id]; !ok {
+			c.entries[id] = entry
+		}
+	}
+}
+
+// predictConflicts returns the files that would conflict merging a
+// session's branch into the base branch, so status can warn before anyone
+// tries. Only a branch that has diverged from the base branch, with commits
+// on both sides, can conflict with it. The prediction is reused until
+// either side gets new commits.
+func (m *Manager) predictConflicts(core types.CoreSession, branch string, status types.GitStatus) []string {
+	if status.HasError() || status.CommitCount == 0 || status.BehindCount == 0 {
+		return nil
+	}
+
+	branchCommit, err := m.config.GitChecker.ResolveCommit(branch)
+	if err != nil {
+		return nil
+	}
+	baseCommit, err := m.config.GitChecker.ResolveCommit(m.config.BaseBranch)
+	if err != nil {
+		return nil
+	}
+	if files, ok := m.forecast.get(core.ID, branchCommit, baseCommit); ok {
+		return files
+	}
+
+	files, err := m.config.GitChecker.PredictConflicts(branchCommit, baseCommit)
+	if err != nil {
+		if !errors.Is(err, git.ErrMergeTreeUnsupported) {
+			logger.Warn("failed to predict conflicts with base branch", "session", core.Name, "error", err)
+		}
+		return nil
+	}
+	m.forecast.put(core.ID, conflictForecastEntry{
+		BranchCommit: branchCommit,
+		BaseCommit:   baseCommit,
+		Files:        files,
+		PredictedAt:  m.Now(),
+	})
+	return files
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_PredictConflicts(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+	gitChecker.Commits["auth"] = "c1"
+	gitChecker.Predicted["c1"] = []string{"login.go"}
+
+	// A branch only ahead of main merges cleanly, so there's nothing to predict
+	gitChecker.SetStatus(auth.WorktreePath, types.GitStatus{CommitCount: 2})
+	session, _ := manager.DeriveSession(auth.ID)
+	if len(session.GitStatus.BaseConflicts) != 0 || gitChecker.Predictions != 0 {
+		t.Errorf("BaseConflicts = %v after %d predictions, want none for a branch main hasn't moved past", session.GitStatus.BaseConflicts, gitChecker.Predictions)
+	}
+
+	gitChecker.SetStatus(auth.WorktreePath, types.GitStatus{CommitCount: 2, BehindCount: 1})
+	manager.InvalidateStatus(auth.ID)
+	session, _ = manager.DeriveSession(auth.ID)
+	if len(session.GitStatus.BaseConflicts) != 1 || session.GitStatus.BaseConflicts[0] != "login.go" {
+		t.Errorf("BaseConflicts = %v, want login.go", session.GitStatus.BaseConflicts)
+	}
+
+	// The prediction holds until a side moves, across processes too
+	reopened := NewManager(Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	})
+	defer reopened.Close()
+	reopened.InvalidateStatus(auth.ID)
+	session, _ = reopened.DeriveSession(auth.ID)
+	if len(session.GitStatus.BaseConflicts) != 1 || gitChecker.Predictions != 1 {
+		t.Errorf("BaseConflicts = %v after %d predictions, want the saved prediction reused", session.GitStatus.BaseConflicts, gitChecker.Predictions)
+	}
+
+	gitChecker.Commits["auth"] = "c2"
+	reopened.InvalidateStatus(auth.ID)
+	session, _ = reopened.DeriveSession(auth.ID)
+	if len(session.GitStatus.BaseConflicts) != 0 || gitChecker.Predictions != 2 {
+		t.Errorf("BaseConflicts = %v after %d predictions, want a new commit predicted again", session.GitStatus.BaseConflicts, gitChecker.Predictions)
+	}
+}
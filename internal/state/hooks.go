@@ -0,0 +1,134 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// Points of a session's lifecycle hooks run at
+const (
+	HookPostCreate = "post_create" // In the new worktree, before Claude starts; a failure aborts the creation
+	HookPreDelete  = "pre_delete"  // Before the worktree is removed; a failure keeps the session
+	HookPostMerge  = "post_merge"  // After the session's branch was merged; a failure is only reported
+)
+
+// hookOutputLines is how much of a failed hook's output its error repeats
+const hookOutputLines = 5
+
+// Hooks are shell commands run in a session's worktree at points of its
+// lifecycle, like installing dependencies or copying a .env file into a new
+// worktree. Each runs with sh and the session's environment, in order.
+type Hooks struct {
+	PostCreate []string
+	PreDelete  []string
+	PostMerge  []string
+
+	Timeout       time.Duration // How long each command may run (0 for no limit)
+	WarnOnFailure bool          // Carry on when a post_create or pre_delete hook fails, only logging it
+}
+
+// commands returns the commands of a hook point
+func (h Hooks) commands(hook string) []string {
+	switch hook {
+	case HookPostCreate:
+		return h.PostCreate
+	case HookPreDelete:
+		return h.PreDelete
+	case HookPostMerge:
+		return h.PostMerge
+	}
+	return nil
+}
+
+// HookError is returned when a lifecycle hook fails
+type HookError struct {
+	Hook    string // HookPostCreate, HookPreDelete or HookPostMerge
+	Command string
+	Result  types.FollowUpResult
+}
+
+func (e *HookError) Error() string {
+	reason := fmt.Sprintf("exit status %d", e.Result.ExitCode)
+	if e.Result.Error != "" {
+		reason = e.Result.Error
+	}
+	msg := fmt.Sprintf("%s hook %q failed: %s", e.Hook, e.Command, reason)
+	for _, line := range e.Result.Tail(hookOutputLines) {
+		msg += "\n  " + line
+	}
+	return msg
+}
+
+// RunPostMergeHooks runs the post_merge hooks of a session just merged.
+// Unlike the other hooks, a failure can't undo anything; it is returned
+// for the caller to report.
+func (m *Manager) RunPostMergeHooks(sessionID string) error {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return err
+	}
+	return m.runHooks(context.Background(), core, HookPostMerge, nil)
+}
+
+// runHooks runs the commands of a hook point in a session's worktree, in
+// order, stopping at the first that fails. Each run is recorded in the
+// session's event log with the end of its output. Sessions whose worktree
+// is gone have nothing to run hooks in.
+func (m *Manager) runHooks(ctx context.Context, core types.CoreSession, hook string, report func(step string)) error {
+	commands := m.config.Hooks.commands(hook)
+	if len(commands) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(core.WorktreePath); err != nil {
+		logger.Debug("skipping hooks of a session without worktree", "hook", hook, "session", core.Name)
+		return nil
+	}
+
+	if report != nil {
+		report(fmt.Sprintf("Running %s hooks", hook))
+	}
+	env := m.SessionEnv(core)
+	for _, command := range commands {
+		result := m.runHookCommand(ctx, core.WorktreePath, command, env)
+		logger.Info("ran hook", "hook", hook, "session", core.Name, "command", command, "exit_code", result.ExitCode)
+		m.RecordEvent(core.ID, types.EventHook, fmt.Sprintf("%s: %s", hook, command), map[string]interface{}{
+			"hook":      hook,
+			"exit_code": result.ExitCode,
+			"output":    result.Output,
+		})
+		if result.ExitCode != 0 {
+			return &HookError{Hook: hook, Command: command, Result: result}
+		}
+	}
+	return nil
+}
+
+// runHookCommand runs one hook command, within the configured timeout
+func (m *Manager) runHookCommand(ctx context.Context, dir, command string, env []string) types.FollowUpResult {
+	if timeout := m.config.Hooks.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	result := m.runFollowUpCommand(ctx, dir, command, env...)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.Error = fmt.Sprintf("timed out after %s", m.config.Hooks.Timeout)
+	}
+	return result
+}
+
+// hookFailed decides what a failed post_create or pre_delete hook means:
+// the error to stop with, or nil when the config says to carry on
+func (m *Manager) hookFailed(err error, session string) error {
+	if err == nil || !m.config.Hooks.WarnOnFailure {
+		return err
+	}
+	logger.Warn("ignoring failed hook", "session", session, "error", strings.TrimSpace(err.Error()))
+	return nil
+}
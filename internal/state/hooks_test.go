@@ -0,0 +1,124 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newHooksManager(t *testing.T, hooks Hooks) (*Manager, *tmux.MockChecker) {
+	t.Helper()
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		Hooks:         hooks,
+	})
+	t.Cleanup(manager.Close)
+	return manager, tmuxChecker
+}
+
+func TestManager_PostCreateHooks(t *testing.T) {
+	manager, _ := newHooksManager(t, Hooks{
+		PostCreate: []string{"echo $CWT_SESSION_NAME > .env", "echo installed"},
+	})
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	data, err := os.ReadFile(filepath.Join(cores[0].WorktreePath, ".env"))
+	if err != nil || strings.TrimSpace(string(data)) != "auth" {
+		t.Errorf("the hook should run in the worktree with the session's env, got %q, %v", data, err)
+	}
+
+	events, _ := manager.Timeline(cores[0].ID)
+	var hooks []types.SessionEvent
+	for _, event := range events {
+		if event.Type == types.EventHook {
+			hooks = append(hooks, event)
+		}
+	}
+	if len(hooks) != 2 || hooks[1].Data["output"] != "installed" {
+		t.Errorf("each hook should be logged with its output, got %+v", hooks)
+	}
+}
+
+func TestManager_PostCreateHookFailureAborts(t *testing.T) {
+	manager, tmuxChecker := newHooksManager(t, Hooks{PostCreate: []string{"echo no lockfile; exit 3"}})
+
+	err := manager.CreateSession("auth")
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || hookErr.Hook != HookPostCreate || hookErr.Result.ExitCode != 3 {
+		t.Fatalf("CreateSession() error = %v, want the post_create hook's failure", err)
+	}
+	if !strings.Contains(err.Error(), "no lockfile") {
+		t.Errorf("the error should show the hook's output: %v", err)
+	}
+	if cores, _ := manager.CoreSessions(); len(cores) != 0 {
+		t.Errorf("the session should not be saved: %+v", cores)
+	}
+	if len(tmuxChecker.AliveSessions) != 0 {
+		t.Errorf("Claude should not start: %v", tmuxChecker.AliveSessions)
+	}
+}
+
+func TestManager_PostCreateHookFailureWarns(t *testing.T) {
+	manager, _ := newHooksManager(t, Hooks{PostCreate: []string{"false"}, WarnOnFailure: true})
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v, want the failure only logged", err)
+	}
+}
+
+func TestManager_PreDeleteHooks(t *testing.T) {
+	manager, _ := newHooksManager(t, Hooks{PreDelete: []string{"test -f done"}})
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	core := cores[0]
+
+	var hookErr *HookError
+	if _, err := manager.DeleteSessionWithOptions(core.ID, DeleteOptions{}); !errors.As(err, &hookErr) {
+		t.Fatalf("DeleteSessionWithOptions() error = %v, want the pre_delete hook's failure", err)
+	}
+	if cores, _ := manager.CoreSessions(); len(cores) != 1 {
+		t.Fatal("a failing pre_delete hook should keep the session")
+	}
+
+	if _, err := manager.DeleteSessionWithOptions(core.ID, DeleteOptions{IgnoreHooks: true}); err != nil {
+		t.Fatalf("DeleteSessionWithOptions(IgnoreHooks) error = %v", err)
+	}
+	if cores, _ := manager.CoreSessions(); len(cores) != 0 {
+		t.Error("IgnoreHooks should delete despite the hook")
+	}
+}
+
+func TestManager_RunPostMergeHooks(t *testing.T) {
+	manager, _ := newHooksManager(t, Hooks{PostMerge: []string{"touch merged", "exit 1", "touch never"}})
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	core := cores[0]
+
+	if err := manager.RunPostMergeHooks(core.ID); err == nil {
+		t.Error("RunPostMergeHooks() should report the failing hook")
+	}
+	if _, err := os.Stat(filepath.Join(core.WorktreePath, "merged")); err != nil {
+		t.Errorf("hooks before the failure should run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(core.WorktreePath, "never")); err == nil {
+		t.Error("hooks after a failure should not run")
+	}
+}
@@ -0,0 +1,318 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// UsageFileName is the file in the data directory that records session
+// creations and the tokens Claude used, so limits hold across processes and
+// the tokens of deleted sessions still count
+const UsageFileName = "usage.json"
+
+// usageMonthsKept is how many months of token usage the usage file keeps
+const usageMonthsKept = 12
+
+// Limits are guardrails against runaway agent spend, checked when a session
+// is created. A zero value turns that limit off.
+type Limits struct {
+	SessionsPerHour int     // Sessions that may be created in any hour
+	MaxWorking      int     // Sessions Claude may be working in when another is created
+	MonthlyTokens   int64   // Tokens Claude may use in a calendar month
+	WarnAt          float64 // Share of MonthlyTokens used at which to warn
+	SessionCost     float64 // Estimated US dollars a session may cost before it is flagged
+}
+
+// Enabled reports whether any limit is set. The session cost only flags
+// sessions, so it doesn't count.
+func (l Limits) Enabled() bool {
+	return l.SessionsPerHour > 0 || l.MaxWorking > 0 || l.MonthlyTokens > 0
+}
+
+// OverCost reports whether a session's usage is estimated to cost more than
+// SessionCost
+func (l Limits) OverCost(usage types.Usage) bool {
+	return l.SessionCost > 0 && usage.Cost > l.SessionCost
+}
+
+// LimitError is returned when creating a session would go over a limit
+type LimitError struct {
+	Limit   string // Config key of the limit, like "limits.sessions_per_hour"
+	Message string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message, e.Limit)
+}
+
+// usageLedger is the content of the usage file
+type usageLedger struct {
+	Created []time.Time                 `json:"created"` // Session creations in the last hour
+	Tokens  map[string]map[string]int64 `json:"tokens"`  // Tokens by month, then session ID
+}
+
+// SetLimits changes the limits, for when the configuration is reloaded
+func (m *Manager) SetLimits(limits Limits) {
+	m.limitsMu.Lock()
+	defer m.limitsMu.Unlock()
+	m.config.Limits = limits
+}
+
+// Limits returns the limits sessions are created under
+func (m *Manager) Limits() Limits {
+	m.limitsMu.Lock()
+	defer m.limitsMu.Unlock()
+	return m.config.Limits
+}
+
+// RecordTokenUsage records the tokens Claude used this month in each of
+// sessions and returns the month's usage against the budget. Usage recorded
+// for sessions since deleted keeps counting until the month ends.
+func (m *Manager) RecordTokenUsage(sessions []types.Session) (types.TokenBudget, error) {
+	month := claude.UsageMonth(m.Now())
+	usage := make(map[string]int64, len(sessions))
+	for _, session := range sessions {
+		usage[session.Core.ID] = m.AgentChecker(session.Core).TokenUsage(session.Core.WorktreePath, month)
+	}
+
+	m.limitsMu.Lock()
+	defer m.limitsMu.Unlock()
+
+	ledger, err := m.loadUsageLedger()
+	if err != nil {
+		return types.TokenBudget{}, err
+	}
+	recorded := ledger.Tokens[month]
+	if recorded == nil {
+		recorded = make(map[string]int64)
+		ledger.Tokens[month] = recorded
+	}
+	changed := false
+	for id, tokens := range usage {
+		// Transcripts Claude has since cleaned up don't lower the count
+		if tokens > recorded[id] {
+			recorded[id] = tokens
+			changed = true
+		}
+	}
+	if changed {
+		ledger.pruneMonths()
+		if err := m.saveUsageLedger(ledger); err != nil {
+			return types.TokenBudget{}, err
+		}
+	}
+	return m.budget(ledger, month), nil
+}
+
+// SessionUsage returns what a session's agent used over all its
+// conversations in the session's worktree
+func (m *Manager) SessionUsage(core types.CoreSession) types.Usage {
+	return m.AgentChecker(core).SessionUsage(core.WorktreePath)
+}
+
+// TokenBudget returns this month's recorded token usage against the budget,
+// without looking for new usage
+func (m *Manager) TokenBudget() (types.TokenBudget, error) {
+	m.limitsMu.Lock()
+	defer m.limitsMu.Unlock()
+
+	ledger, err := m.loadUsageLedger()
+	if err != nil {
+		return types.TokenBudget{}, err
+	}
+	return m.budget(ledger, claude.UsageMonth(m.Now())), nil
+}
+
+// RecentCreations returns how many sessions were created in the last hour
+func (m *Manager) RecentCreations() (int, error) {
+	m.limitsMu.Lock()
+	defer m.limitsMu.Unlock()
+
+	ledger, err := m.loadUsageLedger()
+	if err != nil {
+		return 0, err
+	}
+	ledger.pruneCreated(m.Now())
+	return len(ledger.Created), nil
+}
+
+// WorkingSessions counts the sessions Claude is working in
+func WorkingSessions(sessions []types.Session) int {
+	working := 0
+	for _, session := range sessions {
+		if session.ClaudeStatus.State == types.ClaudeWorking {
+			working++
+		}
+	}
+	return working
+}
+
+// reserveCreation checks the limits before a session is created and counts
+// the creation against the hourly limit. Call release if the creation fails
+// so it doesn't count.
+func (m *Manager) reserveCreation() (release func(), err error) {
+	limits := m.Limits()
+	release = func() {}
+	if !limits.Enabled() {
+		return release, nil
+	}
+
+	if limits.MaxWorking > 0 || limits.MonthlyTokens > 0 {
+		sessions, err := m.DeriveFreshSessions()
+		if err != nil {
+			return release, fmt.Errorf("failed to check limits: %w", err)
+		}
+		if working := WorkingSessions(sessions); limits.MaxWorking > 0 && working >= limits.MaxWorking {
+			return release, &LimitError{
+				Limit:   "limits.max_working",
+				Message: fmt.Sprintf("Claude is already working in %d session(s), the most allowed at once; wait for one to finish", working),
+			}
+		}
+		if limits.MonthlyTokens > 0 {
+			budget, err := m.RecordTokenUsage(sessions)
+			if err != nil {
+				return release, fmt.Errorf("failed to check token budget: %w", err)
+			}
+			if budget.Exceeded() {
+				return release, &LimitError{
+					Limit:   "limits.monthly_tokens",
+					Message: fmt.Sprintf("the monthly token budget is used up: %s", budget.Summary()),
+				}
+			}
+		}
+	}
+
+	if limits.SessionsPerHour <= 0 {
+		return release, nil
+	}
+
+	m.limitsMu.Lock()
+	defer m.limitsMu.Unlock()
+
+	ledger, err := m.loadUsageLedger()
+	if err != nil {
+		return release, fmt.Errorf("failed to check creation rate: %w", err)
+	}
+	now := m.Now()
+	ledger.pruneCreated(now)
+	if len(ledger.Created) >= limits.SessionsPerHour {
+		wait := ledger.Created[len(ledger.Created)-limits.SessionsPerHour].Add(time.Hour).Sub(now)
+		return release, &LimitError{
+			Limit: "limits.sessions_per_hour",
+			Message: fmt.Sprintf("%d session(s) were created in the last hour, the most allowed; try again in %d minute(s)",
+				len(ledger.Created), int(math.Ceil(wait.Minutes()))),
+		}
+	}
+	ledger.Created = append(ledger.Created, now)
+	if err := m.saveUsageLedger(ledger); err != nil {
+		return release, fmt.Errorf("failed to record creation: %w", err)
+	}
+
+	return func() {
+		m.limitsMu.Lock()
+		defer m.limitsMu.Unlock()
+
+		ledger, err := m.loadUsageLedger()
+		if err != nil {
+			return
+		}
+		for i, created := range ledger.Created {
+			if created.Equal(now) {
+				ledger.Created = append(ledger.Created[:i], ledger.Created[i+1:]...)
+				break
+			}
+		}
+		if err := m.saveUsageLedger(ledger); err != nil {
+			logger.Warn("failed to release session creation", "error", err)
+		}
+	}, nil
+}
+
+// budget totals a month's usage in ledger against the budget
+func (m *Manager) budget(ledger *usageLedger, month string) types.TokenBudget {
+	budget := types.TokenBudget{
+		Month:  month,
+		Budget: m.config.Limits.MonthlyTokens,
+		WarnAt: m.config.Limits.WarnAt,
+	}
+	for _, tokens := range ledger.Tokens[month] {
+		budget.Used += tokens
+	}
+	return budget
+}
+
+// pruneCreated drops the creations more than an hour before now
+func (l *usageLedger) pruneCreated(now time.Time) {
+	recent := l.Created[:0]
+	for _, created := range l.Created {
+		if now.Sub(created) < time.Hour {
+			recent = append(recent, created)
+		}
+	}
+	l.Created = recent
+}
+
+// pruneMonths drops all but the usageMonthsKept latest months of usage
+func (l *usageLedger) pruneMonths() {
+	months := make([]string, 0, len(l.Tokens))
+	for month := range l.Tokens {
+		months = append(months, month)
+	}
+	if len(months) <= usageMonthsKept {
+		return
+	}
+	sort.Strings(months)
+	for _, month := range months[:len(months)-usageMonthsKept] {
+		delete(l.Tokens, month)
+	}
+}
+
+// loadUsageLedger reads the usage file; callers hold limitsMu
+func (m *Manager) loadUsageLedger() (*usageLedger, error) {
+	ledger := &usageLedger{Tokens: make(map[string]map[string]int64)}
+	data, err := os.ReadFile(filepath.Join(m.config.DataDir, UsageFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ledger, nil
+		}
+		return nil, fmt.Errorf("failed to read usage file: %w", err)
+	}
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, fmt.Errorf("usage file corrupted: %w", err)
+	}
+	if ledger.Tokens == nil {
+		ledger.Tokens = make(map[string]map[string]int64)
+	}
+	return ledger, nil
+}
+
+// saveUsageLedger writes the usage file atomically; callers hold limitsMu
+func (m *Manager) saveUsageLedger(ledger *usageLedger) error {
+	if err := os.MkdirAll(m.config.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %w", err)
+	}
+
+	path := filepath.Join(m.config.DataDir, UsageFileName)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
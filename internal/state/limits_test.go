@@ -0,0 +1,115 @@
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_Limits(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	claudeChecker := claude.NewMockChecker()
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claudeChecker,
+		Limits:        Limits{SessionsPerHour: 2},
+	})
+	defer manager.Close()
+
+	expectLimit := func(err error, limit string) {
+		t.Helper()
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.Limit != limit {
+			t.Fatalf("error = %v, want the %s limit", err, limit)
+		}
+	}
+
+	for _, name := range []string{"first", "second"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+	}
+	expectLimit(manager.CreateSession("third"), "limits.sessions_per_hour")
+	if created, err := manager.RecentCreations(); err != nil || created != 2 {
+		t.Errorf("RecentCreations() = %d, %v; want the 2 creations", created, err)
+	}
+	// A failed creation doesn't count
+	manager.SetLimits(Limits{SessionsPerHour: 3})
+	tmuxChecker.ShouldFailCreate = true
+	if err := manager.CreateSession("third"); err == nil {
+		t.Fatal("Expected error for a failing tmux session")
+	}
+	tmuxChecker.ShouldFailCreate = false
+	if err := manager.CreateSession("third"); err != nil {
+		t.Fatalf("CreateSession() under a raised limit error = %v", err)
+	}
+
+	// Claude working in as many sessions as allowed blocks another
+	firstWorktree := filepath.Join(dataDir, "worktrees", "first")
+	claudeChecker.SetStatus(firstWorktree, types.ClaudeStatus{State: types.ClaudeWorking})
+	manager.SetLimits(Limits{MaxWorking: 1})
+	expectLimit(manager.CreateSession("fourth"), "limits.max_working")
+	claudeChecker.SetStatus(firstWorktree, types.ClaudeStatus{State: types.ClaudeComplete})
+	if err := manager.CreateSession("fourth"); err != nil {
+		t.Fatalf("CreateSession() after Claude finished error = %v", err)
+	}
+
+	// The budget warns, then refuses, and deleted sessions keep counting
+	manager.SetLimits(Limits{MonthlyTokens: 1000, WarnAt: 0.5})
+	claudeChecker.Usage[firstWorktree] = 600
+	sessions, _ := manager.DeriveFreshSessions()
+	budget, err := manager.RecordTokenUsage(sessions)
+	if err != nil {
+		t.Fatalf("RecordTokenUsage() error = %v", err)
+	}
+	if budget.Used != 600 || !budget.Warning() || budget.Exceeded() {
+		t.Errorf("budget = %+v, want a warning at 600 tokens", budget)
+	}
+	claudeChecker.Usage[filepath.Join(dataDir, "worktrees", "second")] = 400
+	expectLimit(manager.CreateSession("fifth"), "limits.monthly_tokens")
+
+	cores, _ := manager.CoreSessions()
+	if err := manager.DeleteSession(cores[0].ID); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	if budget, err := manager.TokenBudget(); err != nil || budget.Used != 1000 || !budget.Exceeded() {
+		t.Errorf("TokenBudget() = %+v, %v; want the deleted session's tokens counted", budget, err)
+	}
+}
+
+func TestManager_SessionUsage(t *testing.T) {
+	claudeChecker := claude.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claudeChecker,
+		Limits:        Limits{SessionCost: 5},
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	used := types.Usage{InputTokens: 1000, OutputTokens: 200, Cost: 6.5}
+	claudeChecker.Sessions[cores[0].WorktreePath] = used
+
+	if got := manager.SessionUsage(cores[0]); got != used {
+		t.Errorf("SessionUsage() = %+v, want %+v", got, used)
+	}
+	if limits := manager.Limits(); !limits.OverCost(used) || limits.OverCost(types.Usage{Cost: 4.99}) {
+		t.Error("expected only sessions costing more than the session cost to be over it")
+	}
+	if limits := manager.Limits(); limits.Enabled() {
+		t.Error("the session cost only flags sessions, so it shouldn't enable the creation limits")
+	}
+}
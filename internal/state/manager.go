@@ -1,22 +1,40 @@
 package state
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/agent"
 	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/forge"
 	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/statusprovider"
 	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/clock"
 	"github.com/jlaneve/cwt-cli/internal/events"
+	"github.com/jlaneve/cwt-cli/internal/logging"
+	"github.com/jlaneve/cwt-cli/internal/profile"
 	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
 )
 
+// logger is the state package's diagnostic log
+var logger = logging.For("state")
+
+// SessionsLockFileName is the file in the data directory cwt processes lock
+// while they change the sessions file
+const SessionsLockFileName = "sessions.lock"
+
 // Config holds configuration for the StateManager
 type Config struct {
 	DataDir       string         // Directory for storing session data (e.g., ".cwt")
@@ -24,6 +42,64 @@ type Config struct {
 	ClaudeChecker claude.Checker // Injectable Claude operations
 	GitChecker    git.Checker    // Injectable git operations
 	BaseBranch    string         // Base branch for creating worktrees (default: "main")
+	GitBackend    string         // Backend of the default GitChecker: "exec" (default) or "go-git"
+
+	ClaudeExecutable string            // Path to the claude CLI (default: auto-detected)
+	StatusCacheTTL   time.Duration     // How long derived status is reused (0 disables caching)
+	GitHooksInstall  string            // Hook install step for new worktrees: "" or "auto" detects it, "none" skips it
+	NoClaudeHooks    bool              // Don't write Claude hook settings into worktrees
+	CoverageProfile  string            // Coverage profile follow-up commands write, relative to the worktree ("" for none)
+	Limits           Limits            // Guardrails checked when creating sessions (default: none)
+	Forge            string            // Forge hosting origin, for pull request status ("" detects it)
+	Env              map[string]string // Extra environment of every session's tmux session
+	Hooks            Hooks             // Commands run in worktrees at points of a session's lifecycle (default: none)
+	CopyUntracked    []string          // Globs of files git doesn't check out to copy into new worktrees, like .env
+	ClaudeArgs       []string          // Arguments new sessions start Claude with, like --model opus
+
+	// Agent is the coding agent new sessions run unless told otherwise, one
+	// of agent.Names ("" for Claude)
+	Agent string
+
+	// Agents sets how agents besides Claude are started, by name
+	Agents map[string]AgentSettings
+
+	// AgentCheckers derive the status of agents besides Claude, by name
+	// (default: each agent's own)
+	AgentCheckers map[string]agent.Checker
+
+	// StatusProviders add external fields to session status (default: none)
+	StatusProviders statusprovider.Checker
+
+	// Forges returns the forge hosting a worktree's repository (default:
+	// the one origin's URL points at, or Forge)
+	Forges func(worktreePath string) (forge.Forge, error)
+
+	// Profile, if set, times loading sessions and every call of the checkers
+	Profile *profile.Recorder
+
+	// Provider serves already-derived sessions (e.g. a running daemon).
+	// When it fails, the manager falls back to deriving sessions itself.
+	Provider SessionProvider
+
+	// Clock tells the time sessions are created, derived and expired at
+	// (default: the system clock)
+	Clock clock.Clock
+
+	// IDs names new sessions (default: the nanosecond they were created at)
+	IDs clock.IDGenerator
+}
+
+// AgentSettings sets how an agent besides Claude is started
+type AgentSettings struct {
+	Executable string   // Command starting it (default: auto-detected)
+	Args       []string // Arguments new sessions start it with
+}
+
+// SessionProvider supplies derived sessions from outside this process
+type SessionProvider interface {
+	Sessions() ([]types.Session, error)
+	Session(sessionID string) (types.Session, error)
+	Invalidate(sessionID string) error // Re-derive a session ("" for all) before the next query
 }
 
 // Manager handles all session state operations
@@ -32,6 +108,18 @@ type Manager struct {
 	eventBus *events.Bus
 	mu       sync.RWMutex
 	dataFile string
+	cache    *statusCache
+	changes  *changesIndex
+	forecast *conflictForecast
+
+	providerMu sync.Mutex
+	provider   SessionProvider
+
+	limitsMu   sync.Mutex // Guards config.Limits and the usage file
+	coverageMu sync.Mutex // Serializes measuring the base branch's coverage
+	contextMu  sync.Mutex // Guards the shared context file
+
+	pullRequestMu sync.Mutex // Held while asking forges about pull requests
 }
 
 // NewManager creates a new StateManager with the given configuration
@@ -48,19 +136,57 @@ func NewManager(config Config) *Manager {
 		config.TmuxChecker = tmux.NewRealChecker()
 	}
 	if config.GitChecker == nil {
-		config.GitChecker = git.NewRealChecker(config.BaseBranch)
+		config.GitChecker = git.NewChecker(config.GitBackend, config.BaseBranch)
 	}
 	if config.ClaudeChecker == nil {
 		config.ClaudeChecker = claude.NewRealChecker(config.TmuxChecker)
 	}
+	if config.Clock == nil {
+		config.Clock = clock.NewRealClock()
+	}
+	if config.IDs == nil {
+		config.IDs = clock.NewTimestampIDs()
+	}
+	agentCheckers := make(map[string]agent.Checker)
+	for _, a := range agent.All {
+		if agent.IsClaude(a.Name()) {
+			continue // ClaudeChecker's
+		}
+		checker := config.AgentCheckers[a.Name()]
+		if checker == nil {
+			checker = a.NewChecker(config.TmuxChecker)
+		}
+		agentCheckers[a.Name()] = checker
+	}
+	config.AgentCheckers = agentCheckers
+	if config.Profile != nil {
+		config.TmuxChecker = profile.TmuxChecker(config.TmuxChecker, config.Profile)
+		config.GitChecker = profile.GitChecker(config.GitChecker, config.Profile)
+		config.ClaudeChecker = profile.ClaudeChecker(config.ClaudeChecker, config.Profile)
+		for name, checker := range config.AgentCheckers {
+			config.AgentCheckers[name] = profile.ClaudeChecker(checker, config.Profile)
+		}
+		if config.StatusProviders != nil {
+			config.StatusProviders = profile.StatusProviders(config.StatusProviders, config.Profile)
+		}
+	}
 
 	return &Manager{
 		config:   config,
 		eventBus: events.NewBus(),
 		dataFile: filepath.Join(config.DataDir, "sessions.json"),
+		cache:    newStatusCache(config.DataDir, config.StatusCacheTTL, config.Clock),
+		changes:  newChangesIndex(config.DataDir),
+		forecast: newConflictForecast(config.DataDir),
+		provider: config.Provider,
 	}
 }
 
+// Now returns the current time of the manager's clock
+func (m *Manager) Now() time.Time {
+	return m.config.Clock.Now()
+}
+
 // EventBus returns the event bus for subscribing to events
 func (m *Manager) EventBus() <-chan types.Event {
 	return m.eventBus.Subscribe()
@@ -68,6 +194,16 @@ func (m *Manager) EventBus() <-chan types.Event {
 
 // DeriveFreshSessions loads core sessions and derives complete state from external systems
 func (m *Manager) DeriveFreshSessions() ([]types.Session, error) {
+	if provider := m.currentProvider(); provider != nil {
+		done := m.config.Profile.Track(profile.PhaseStateLoad)
+		sessions, err := provider.Sessions()
+		done()
+		if err == nil {
+			return sessions, nil
+		}
+		m.dropProvider(err)
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -76,20 +212,174 @@ func (m *Manager) DeriveFreshSessions() ([]types.Session, error) {
 		return nil, fmt.Errorf("failed to load core sessions: %w", err)
 	}
 
+	alive := m.batchLiveness(cores)
+
 	sessions := make([]types.Session, len(cores))
 	for i, core := range cores {
-		sessions[i] = m.deriveSession(core)
+		sessions[i] = m.deriveSessionWith(core, alive)
 	}
+	m.cache.flush()
+	m.forecast.retain(cores)
+	m.forecast.flush()
+	m.addProviderFields(sessions)
 
 	return sessions, nil
 }
 
+// DeriveSession loads a single core session and derives its complete state
+func (m *Manager) DeriveSession(sessionID string) (types.Session, error) {
+	if provider := m.currentProvider(); provider != nil {
+		done := m.config.Profile.Track(profile.PhaseStateLoad)
+		session, err := provider.Session(sessionID)
+		done()
+		if err == nil {
+			return session, nil
+		}
+		m.dropProvider(err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return types.Session{}, fmt.Errorf("failed to load core sessions: %w", err)
+	}
+
+	for _, core := range cores {
+		if core.ID == sessionID {
+			sessions := []types.Session{m.deriveSession(core)}
+			m.cache.flush()
+			m.forecast.flush()
+			m.addProviderFields(sessions)
+			return sessions[0], nil
+		}
+	}
+
+	return types.Session{}, fmt.Errorf("session with ID %s not found", sessionID)
+}
+
+// addProviderFields asks the status providers about each session, all
+// sessions at once since providers often wait on a network service
+func (m *Manager) addProviderFields(sessions []types.Session) {
+	if m.config.StatusProviders == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for i := range sessions {
+		wg.Add(1)
+		go func(session *types.Session) {
+			defer wg.Done()
+			session.Extra = m.config.StatusProviders.GetFields(*session)
+		}(&sessions[i])
+	}
+	wg.Wait()
+}
+
+// InvalidateStatus discards cached status for a session so the next
+// derivation queries git, tmux and Claude again. An empty sessionID
+// invalidates every session.
+func (m *Manager) InvalidateStatus(sessionID string) {
+	m.cache.invalidate(sessionID)
+	m.cache.flush()
+	m.invalidateProvider(sessionID)
+}
+
+// SetStatusCacheTTL changes how long derived status is reused, for when the
+// configuration is reloaded. A zero TTL disables the cache.
+func (m *Manager) SetStatusCacheTTL(ttl time.Duration) {
+	m.cache.setTTL(ttl)
+}
+
+// NotifyConfigReloaded tells event subscribers which settings changed when
+// the configuration was reloaded
+func (m *Manager) NotifyConfigReloaded(changed []string) {
+	m.eventBus.Publish(types.ConfigReloaded{Changed: changed})
+}
+
+// UsingProvider reports whether sessions are currently served by a provider
+func (m *Manager) UsingProvider() bool {
+	return m.currentProvider() != nil
+}
+
+func (m *Manager) currentProvider() SessionProvider {
+	m.providerMu.Lock()
+	defer m.providerMu.Unlock()
+	return m.provider
+}
+
+// dropProvider stops using a provider that failed, so the remaining calls in
+// this process derive sessions directly
+func (m *Manager) dropProvider(err error) {
+	logger.Info("session provider failed, deriving sessions directly", "error", err)
+	m.providerMu.Lock()
+	defer m.providerMu.Unlock()
+	m.provider = nil
+}
+
+// invalidateProvider tells the provider that a session changed in this process
+func (m *Manager) invalidateProvider(sessionID string) {
+	if provider := m.currentProvider(); provider != nil {
+		if err := provider.Invalidate(sessionID); err != nil {
+			m.dropProvider(err)
+		}
+	}
+}
+
+// CreateOptions holds optional settings for a new session
+type CreateOptions struct {
+	Task      string         // Task description sent to Claude as its initial prompt
+	CreatedBy string         // Creator to record (default: git user.name, then OS user)
+	Source    string         // Issue or PR link the session was created from
+	Template  string         // Template the session was created from
+	Priority  types.Priority // Triage priority (default: normal)
+
+	FollowUp    string            // Command run in the worktree when Claude completes, as with 'cwt on'
+	IdleTimeout time.Duration     // Idle time before the expiry policy archives it, if longer than the policy's
+	Env         map[string]string // Extra environment of its tmux session, like a recipe's
+
+	CopyUntracked []string // Globs of files to copy into its worktree besides the config's
+	Agent         string   // Coding agent it runs, one of agent.Names (default: the config's)
+	ClaudeArgs    []string // Arguments to start its agent with after the config's
+
+	// Progress, if set, is called with each step of the creation and the
+	// lines git prints while checking out the worktree, which can take a
+	// while in big repositories or ones with submodules or LFS files
+	Progress func(step string)
+}
+
 // CreateSession creates a new session with all required resources
 func (m *Manager) CreateSession(name string) error {
+	return m.CreateSessionWithOptions(name, CreateOptions{})
+}
+
+// CreateSessionWithOptions creates a new session, applying the given options
+func (m *Manager) CreateSessionWithOptions(name string, opts CreateOptions) error {
+	return m.CreateSessionContext(context.Background(), name, opts)
+}
+
+// CreateSessionContext creates a new session like CreateSessionWithOptions.
+// Cancelling ctx stops the creation and rolls back what was created so far;
+// the returned error then wraps context.Canceled.
+func (m *Manager) CreateSessionContext(ctx context.Context, name string, opts CreateOptions) error {
 	// Validate session name
 	if err := validateSessionName(name); err != nil {
 		return fmt.Errorf("invalid session name: %w", err)
 	}
+	agentName := opts.Agent
+	if agentName == "" {
+		agentName = m.config.Agent
+	}
+	sessionAgent, err := agent.Lookup(agentName)
+	if err != nil {
+		return err
+	}
+	claudeArgs := slices.Concat(m.defaultAgentArgs(sessionAgent), opts.ClaudeArgs)
+	if err := agent.ValidateArgs(sessionAgent, claudeArgs); err != nil {
+		return err
+	}
+
+	logger.Info("creating session", "name", name, "base", m.config.BaseBranch)
 
 	// Emit immediate event for UI feedback
 	m.eventBus.Publish(types.SessionCreationStarted{
@@ -98,11 +388,30 @@ func (m *Manager) CreateSession(name string) error {
 
 	// Generate core session
 	core := types.CoreSession{
-		ID:           generateSessionID(),
+		ID:           m.config.IDs.NewID(),
 		Name:         name,
 		WorktreePath: filepath.Join(m.config.DataDir, "worktrees", name),
 		TmuxSession:  fmt.Sprintf("cwt-%s", name),
-		CreatedAt:    time.Now(),
+		CreatedAt:    m.Now(),
+		Task:         strings.TrimSpace(opts.Task),
+		CreatedBy:    opts.CreatedBy,
+		Source:       opts.Source,
+		Template:     opts.Template,
+		Priority:     storedPriority(opts.Priority),
+		IdleTimeout:  opts.IdleTimeout,
+		Env:          opts.Env,
+
+		CopyUntracked: opts.CopyUntracked,
+		ClaudeArgs:    claudeArgs,
+	}
+	if !agent.IsClaude(sessionAgent.Name()) {
+		core.Agent = sessionAgent.Name()
+	}
+	if opts.FollowUp != "" {
+		core.FollowUp = &types.FollowUp{On: types.FollowUpOnComplete, Command: opts.FollowUp, CreatedAt: core.CreatedAt}
+	}
+	if core.CreatedBy == "" {
+		core.CreatedBy = currentUser()
 	}
 
 	// Check for duplicate session name
@@ -113,9 +422,31 @@ func (m *Manager) CreateSession(name string) error {
 		})
 		return err
 	}
+	if core.WorktreePath, err = m.freeWorktreePath(core.WorktreePath); err != nil {
+		m.eventBus.Publish(types.SessionCreationFailed{
+			Name:  name,
+			Error: err.Error(),
+		})
+		return err
+	}
+
+	release, err := m.reserveCreation()
+	if err != nil {
+		logger.Info("session creation refused", "name", name, "error", err)
+		m.eventBus.Publish(types.SessionCreationFailed{
+			Name:  name,
+			Error: err.Error(),
+		})
+		return err
+	}
 
 	// Create external resources with rollback on failure
-	if err := m.createExternalResources(core); err != nil {
+	if err := m.createExternalResources(ctx, core, opts.Progress); err != nil {
+		release()
+		if ctx.Err() != nil {
+			err = fmt.Errorf("creation of session '%s' was cancelled: %w", name, ctx.Err())
+		}
+		logger.Info("session creation failed", "name", name, "error", err)
 		m.eventBus.Publish(types.SessionCreationFailed{
 			Name:  name,
 			Error: err.Error(),
@@ -127,6 +458,7 @@ func (m *Manager) CreateSession(name string) error {
 	if err := m.addCoreSession(core); err != nil {
 		// Rollback external resources
 		m.cleanupExternalResources(core)
+		release()
 		m.eventBus.Publish(types.SessionCreationFailed{
 			Name:  name,
 			Error: err.Error(),
@@ -134,6 +466,10 @@ func (m *Manager) CreateSession(name string) error {
 		return fmt.Errorf("failed to save session: %w", err)
 	}
 
+	m.invalidateProvider(core.ID)
+	m.RecordEvent(core.ID, types.EventCreated, fmt.Sprintf("Created from %s", m.config.BaseBranch), nil)
+	logger.Info("session created", "name", name, "id", core.ID, "worktree", core.WorktreePath)
+
 	// Emit success event with derived session
 	session := m.deriveSession(core)
 	m.eventBus.Publish(types.SessionCreated{Session: session})
@@ -141,25 +477,138 @@ func (m *Manager) CreateSession(name string) error {
 	return nil
 }
 
-// DeleteSession removes a session and all its resources
+// DeleteOptions control what deleting a session removes besides its tmux
+// session, worktree and metadata
+type DeleteOptions struct {
+	KeepBranch   bool // Keep the session's branch even when it is merged
+	Force        bool // Delete even when the session has work not merged into the base branch
+	DeleteBranch bool // Delete the branch even when the base branch lacks its commits, as after a squash merge
+	IgnoreHooks  bool // Delete even when its pre_delete hook fails
+}
+
+// DeleteResult describes what became of a deleted session's branch
+type DeleteResult struct {
+	Branch         string // The session's branch
+	BranchDeleted  bool
+	BranchUnmerged bool // Kept because it has commits the base branch lacks
+}
+
+// UnmergedWork is the work of a session that isn't on the base branch:
+// uncommitted changes, which deleting the session loses, and commits its
+// branch has, which are kept on the branch
+type UnmergedWork struct {
+	Branch      string
+	Uncommitted []string             // Files with uncommitted changes
+	Unchecked   string               // Why the worktree couldn't be checked for uncommitted changes
+	Commits     []types.CommitOutput // Commits not merged into the base branch, oldest first
+}
+
+// Empty reports whether the session has no work outside the base branch.
+// A worktree that couldn't be checked may have some.
+func (w UnmergedWork) Empty() bool {
+	return len(w.Uncommitted) == 0 && w.Unchecked == "" && len(w.Commits) == 0
+}
+
+// Describe summarizes the work, like "2 uncommitted change(s) and 1 commit(s)
+// not merged into main"
+func (w UnmergedWork) Describe(baseBranch string) string {
+	var parts []string
+	if len(w.Uncommitted) > 0 {
+		parts = append(parts, fmt.Sprintf("%d uncommitted change(s)", len(w.Uncommitted)))
+	}
+	if w.Unchecked != "" {
+		parts = append(parts, fmt.Sprintf("a worktree that can't be checked for uncommitted changes (%s)", w.Unchecked))
+	}
+	if len(w.Commits) > 0 {
+		parts = append(parts, fmt.Sprintf("%d commit(s) not merged into %s", len(w.Commits), baseBranch))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// UnmergedWorkError is returned when deleting a session without Force would
+// delete work that isn't on the base branch
+type UnmergedWorkError struct {
+	Session    string
+	BaseBranch string
+	Work       UnmergedWork
+}
+
+func (e *UnmergedWorkError) Error() string {
+	return fmt.Sprintf("session '%s' has %s", e.Session, e.Work.Describe(e.BaseBranch))
+}
+
+// UnmergedWork finds the work of a session that isn't on the base branch.
+// A worktree or branch that no longer exists has none, while a worktree
+// whose status can't be read is reported as unchecked.
+func (m *Manager) UnmergedWork(sessionID string) (UnmergedWork, error) {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return UnmergedWork{}, err
+	}
+	return m.unmergedWork(core), nil
+}
+
+func (m *Manager) unmergedWork(core types.CoreSession) UnmergedWork {
+	branch, _ := m.sessionBranch(core, core.Name)
+	work := UnmergedWork{Branch: branch}
+
+	status, err := m.config.GitChecker.GetStatus(core.WorktreePath)
+	var statusErr *git.StatusError
+	switch {
+	case err == nil:
+		for _, files := range [][]string{status.ModifiedFiles, status.AddedFiles, status.DeletedFiles, status.UntrackedFiles} {
+			work.Uncommitted = append(work.Uncommitted, files...)
+		}
+		// A merge in progress may have changed nothing else
+		for _, conflicted := range status.ConflictedFiles {
+			if !slices.Contains(work.Uncommitted, conflicted.Path) {
+				work.Uncommitted = append(work.Uncommitted, conflicted.Path)
+			}
+		}
+	case errors.As(err, &statusErr) && statusErr.Kind == types.GitErrorMissingWorktree:
+		// Nothing is left in it to lose
+	default:
+		work.Unchecked = err.Error()
+	}
+
+	// Commits that can't be listed aren't lost either: a branch that can't be
+	// checked is kept
+	if commits, err := m.config.GitChecker.UnmergedCommits(branch); err == nil {
+		work.Commits = commits
+	} else {
+		logger.Debug("failed to list unmerged commits", "branch", branch, "error", err)
+	}
+	return work
+}
+
+// DeleteSession removes a session and all its resources. Its branch is
+// deleted too when the base branch already has all of its commits. It
+// refuses, with an UnmergedWorkError, when the session has uncommitted
+// changes or unmerged commits.
 func (m *Manager) DeleteSession(sessionID string) error {
+	_, err := m.DeleteSessionWithOptions(sessionID, DeleteOptions{})
+	return err
+}
+
+// DeleteSessionWithOptions removes a session's tmux session, worktree and
+// metadata. Its branch is deleted only when it is merged into the base
+// branch, so unmerged work is never lost, and kept with opts.KeepBranch.
+// Unless opts.Force is set, a session with work that isn't on the base
+// branch is left alone and an UnmergedWorkError returned.
+func (m *Manager) DeleteSessionWithOptions(sessionID string, opts DeleteOptions) (DeleteResult, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	cores, err := m.loadCoreSessions()
 	if err != nil {
-		return fmt.Errorf("failed to load sessions: %w", err)
+		return DeleteResult{}, fmt.Errorf("failed to load sessions: %w", err)
 	}
 
 	// Find session to delete
 	var sessionToDelete *types.CoreSession
-	var newCores []types.CoreSession
-
 	for _, core := range cores {
 		if core.ID == sessionID {
 			sessionToDelete = &core
-		} else {
-			newCores = append(newCores, core)
 		}
 	}
 
@@ -169,29 +618,145 @@ func (m *Manager) DeleteSession(sessionID string) error {
 			SessionID: sessionID,
 			Error:     err.Error(),
 		})
-		return err
+		return DeleteResult{}, err
+	}
+
+	if !opts.Force {
+		if work := m.unmergedWork(*sessionToDelete); !work.Empty() {
+			return DeleteResult{}, &UnmergedWorkError{Session: sessionToDelete.Name, BaseBranch: m.config.BaseBranch, Work: work}
+		}
 	}
 
+	// A failing pre_delete hook keeps the session, unless told otherwise
+	if err := m.runHooks(context.Background(), *sessionToDelete, HookPreDelete, nil); err != nil {
+		if opts.IgnoreHooks {
+			logger.Warn("deleting session despite failed hook", "name", sessionToDelete.Name, "error", err)
+		} else if err := m.hookFailed(err, sessionToDelete.Name); err != nil {
+			return DeleteResult{}, fmt.Errorf("%w (delete it anyway with --force)", err)
+		}
+	}
+
+	// The branch can only go once no worktree has it checked out
+	branch, _ := m.sessionBranch(*sessionToDelete, sessionToDelete.Name)
+	result := DeleteResult{Branch: branch}
+
 	// Clean up external resources
+	logger.Info("deleting session", "name", sessionToDelete.Name, "id", sessionID)
 	m.cleanupExternalResources(*sessionToDelete)
 
+	switch {
+	case opts.DeleteBranch:
+		if err := m.config.GitChecker.DeleteBranch(result.Branch); err != nil {
+			logger.Warn("failed to delete branch", "branch", result.Branch, "error", err)
+		} else {
+			result.BranchDeleted = true
+		}
+	case !opts.KeepBranch:
+		result.BranchDeleted, result.BranchUnmerged = m.deleteMergedBranch(result.Branch)
+	}
+
 	// Save updated session list
-	if err := m.saveCoreSessions(newCores); err != nil {
+	if err := m.removeCoreSession(sessionID); err != nil {
 		err := fmt.Errorf("failed to save updated sessions: %w", err)
 		m.eventBus.Publish(types.SessionDeletionFailed{
 			SessionID: sessionID,
 			Error:     err.Error(),
 		})
-		return err
+		return DeleteResult{}, err
 	}
 
+	m.InvalidateStatus(sessionID)
+
 	// Emit success event
 	m.eventBus.Publish(types.SessionDeleted{SessionID: sessionID})
 
+	return result, nil
+}
+
+// deleteMergedBranch deletes a branch the base branch has every commit of,
+// reporting whether it did and whether it was kept for having other commits
+func (m *Manager) deleteMergedBranch(branch string) (deleted, unmerged bool) {
+	merged, err := m.config.GitChecker.BranchMerged(branch)
+	if err != nil {
+		// Usually the branch is already gone
+		logger.Debug("failed to check whether branch is merged", "branch", branch, "error", err)
+		return false, false
+	}
+	if !merged {
+		logger.Info("keeping unmerged branch", "branch", branch)
+		return false, true
+	}
+	if err := m.config.GitChecker.DeleteBranch(branch); err != nil {
+		logger.Warn("failed to delete merged branch", "branch", branch, "error", err)
+		return false, false
+	}
+	return true, false
+}
+
+// UpdateSession applies mutate to the stored metadata of a session and
+// persists the result atomically. The session ID cannot be changed, and a
+// changed name must be valid and unique.
+func (m *Manager) UpdateSession(sessionID string, mutate func(*types.CoreSession)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lock, err := m.lockSessionsFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	index := -1
+	for i, core := range cores {
+		if core.ID == sessionID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	previous := cores[index]
+	updated := previous
+	mutate(&updated)
+
+	if updated.ID != previous.ID {
+		return fmt.Errorf("session ID cannot be changed")
+	}
+	if updated.Name != previous.Name {
+		if err := validateSessionName(updated.Name); err != nil {
+			return fmt.Errorf("invalid session name: %w", err)
+		}
+		for i, core := range cores {
+			if i != index && core.Name == updated.Name {
+				return fmt.Errorf("session with name '%s' already exists", updated.Name)
+			}
+		}
+	}
+
+	cores[index] = updated
+	if err := m.saveCoreSessions(cores); err != nil {
+		return fmt.Errorf("failed to save updated sessions: %w", err)
+	}
+
+	m.invalidateProvider(sessionID)
+
+	m.eventBus.Publish(types.SessionUpdated{
+		Session:  m.deriveSession(updated),
+		Previous: previous,
+	})
+
 	return nil
 }
 
-// FindStaleSessions returns sessions that have dead tmux sessions
+// FindStaleSessions returns sessions that have dead tmux sessions. Paused
+// sessions are stopped on purpose and aren't stale.
 func (m *Manager) FindStaleSessions() ([]types.Session, error) {
 	sessions, err := m.DeriveFreshSessions()
 	if err != nil {
@@ -200,7 +765,7 @@ func (m *Manager) FindStaleSessions() ([]types.Session, error) {
 
 	var stale []types.Session
 	for _, session := range sessions {
-		if !session.IsAlive {
+		if !session.IsAlive && !session.Core.IsPaused() {
 			stale = append(stale, session)
 		}
 	}
@@ -210,27 +775,141 @@ func (m *Manager) FindStaleSessions() ([]types.Session, error) {
 
 // Private methods
 
+// deriveSession builds the full session state, reusing cached tmux, git and
+// Claude checker results while they are within the cache TTL
 func (m *Manager) deriveSession(core types.CoreSession) types.Session {
+	return m.deriveSessionWith(core, nil)
+}
+
+// deriveSessionWith is deriveSession using pre-fetched tmux liveness where
+// available, falling back to a per-session check
+func (m *Manager) deriveSessionWith(core types.CoreSession, alive map[string]bool) types.Session {
+	entry, cached := m.cache.get(core)
+	if !cached {
+		entry = m.deriveStatus(core, alive)
+	}
+	// Reviewing and publishing change what the worktree is compared with,
+	// not the worktree
+	if entry.ReviewTree != reviewTree(core) || entry.PublishTree != publishTree(core) {
+		m.compareSnapshots(core, &entry)
+		cached = false
+	}
+
 	session := types.Session{
-		Core:      core,
-		IsAlive:   m.config.TmuxChecker.IsSessionAlive(core.TmuxSession),
-		GitStatus: m.config.GitChecker.GetStatus(core.WorktreePath),
+		Core:               core,
+		IsAlive:            entry.IsAlive,
+		GitStatus:          entry.GitStatus,
+		Branch:             entry.Branch,
+		BaseBranch:         m.config.BaseBranch,
+		ChangedSinceReview: entry.ChangedSinceReview,
+
+		ChangedSincePublish: entry.ChangedSincePublish,
 	}
 
-	// Load Claude status from session state file (preferred) or fallback to checker
-	if sessionState, err := types.LoadSessionState(m.config.DataDir, core.ID); err == nil && sessionState != nil {
+	// Load Claude status from session state file (preferred) or fallback to checker.
+	// The state file is cheap to read and changes with every hook, so it is never cached.
+	// A log of lifecycle events alone says nothing about Claude.
+	if sessionState, err := types.LoadSessionState(m.config.DataDir, core.ID); err == nil && sessionState != nil && sessionState.LastEvent != "" {
 		session.ClaudeStatus = types.GetClaudeStatusFromState(sessionState)
 	} else {
 		// Fallback to old JSONL scanning if no session state
-		session.ClaudeStatus = m.config.ClaudeChecker.GetStatus(core.WorktreePath)
+		if entry.ClaudeStatus == nil {
+			claudeStatus := m.AgentChecker(core).GetStatus(core.WorktreePath)
+			entry.ClaudeStatus = &claudeStatus
+			cached = false
+		}
+		session.ClaudeStatus = *entry.ClaudeStatus
+	}
+	if session.ClaudeStatus.State == types.ClaudeNotStarted && !m.agentInstalled(core) {
+		session.ClaudeStatus.State = types.ClaudeNoAgent
+	}
+
+	if !cached {
+		m.cache.put(core.ID, entry)
 	}
 
 	// Calculate last activity from available timestamps
 	session.LastActivity = m.calculateLastActivity(session)
 
+	// Say why a dead session stopped, if its exit hook recorded it
+	if !session.IsAlive {
+		session.Exit, _ = types.LoadSessionExit(m.config.DataDir, core.ID)
+	}
+
 	return session
 }
 
+// deriveStatus queries tmux and git for the current state of a session
+func (m *Manager) deriveStatus(core types.CoreSession, alive map[string]bool) statusCacheEntry {
+	entry := statusCacheEntry{
+		WorktreePath: core.WorktreePath,
+		TmuxSession:  core.TmuxSession,
+		DerivedAt:    m.Now(),
+	}
+
+	if isAlive, ok := alive[core.TmuxSession]; ok {
+		entry.IsAlive = isAlive
+	} else {
+		entry.IsAlive = m.config.TmuxChecker.IsSessionAlive(core.TmuxSession)
+	}
+
+	// Surface git failures as an error state rather than a misleading "clean"
+	gitStatus, err := m.config.GitChecker.GetStatus(core.WorktreePath)
+	if err != nil {
+		gitStatus = types.GitStatus{
+			Error:     err.Error(),
+			ErrorKind: types.GitErrorCommandFailed,
+		}
+		var statusErr *git.StatusError
+		if errors.As(err, &statusErr) {
+			gitStatus.ErrorKind = statusErr.Kind
+		}
+	}
+	entry.Branch = m.branchOf(core, gitStatus)
+	gitStatus.BaseConflicts = m.predictConflicts(core, entry.Branch, gitStatus)
+	entry.GitStatus = gitStatus
+	m.compareSnapshots(core, &entry)
+
+	return entry
+}
+
+// branchOf returns the branch checked out in a session's worktree or, when
+// the worktree is gone or detached, the branch the session was created on:
+// one named after it, or "cwt-" and its name for older sessions
+func (m *Manager) branchOf(core types.CoreSession, status types.GitStatus) string {
+	if status.Branch != "" {
+		return status.Branch
+	}
+	if branch, err := m.config.GitChecker.GetCurrentBranch(core.WorktreePath); err == nil && branch != "" && branch != "HEAD" {
+		return branch
+	}
+	if legacy := "cwt-" + core.Name; m.config.GitChecker.BranchExists(legacy) {
+		return legacy
+	}
+	return core.Name
+}
+
+// batchLiveness checks every uncached session's tmux liveness with a single
+// tmux call. It returns nil if nothing needs checking or the batch call
+// fails, in which case sessions are checked individually.
+func (m *Manager) batchLiveness(cores []types.CoreSession) map[string]bool {
+	var names []string
+	for _, core := range cores {
+		if _, cached := m.cache.get(core); !cached {
+			names = append(names, core.TmuxSession)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	alive, err := m.config.TmuxChecker.CheckSessionsAlive(names)
+	if err != nil {
+		return nil
+	}
+	return alive
+}
+
 func (m *Manager) calculateLastActivity(session types.Session) time.Time {
 	lastActivity := session.Core.CreatedAt
 
@@ -245,7 +924,17 @@ func (m *Manager) calculateLastActivity(session types.Session) time.Time {
 	return lastActivity
 }
 
+// CoreSessions returns the persisted sessions without deriving any status,
+// for callers such as shell completion that only need names and IDs
+func (m *Manager) CoreSessions() ([]types.CoreSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.loadCoreSessions()
+}
+
 func (m *Manager) loadCoreSessions() ([]types.CoreSession, error) {
+	defer m.config.Profile.Track(profile.PhaseStateLoad)()
+
 	if _, err := os.Stat(m.dataFile); os.IsNotExist(err) {
 		return []types.CoreSession{}, nil
 	}
@@ -289,15 +978,62 @@ func (m *Manager) saveCoreSessions(sessions []types.CoreSession) error {
 	return nil
 }
 
+// lockSessionsFile locks the sessions file against the other cwt processes
+// sharing the data directory, which m.mu doesn't reach. Sessions loaded
+// after locking it can be changed and saved without dropping a change
+// another process made in between.
+func (m *Manager) lockSessionsFile() (*utils.FileLock, error) {
+	lock, err := utils.LockFile(filepath.Join(m.config.DataDir, SessionsLockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock sessions: %w", err)
+	}
+	return lock, nil
+}
+
+// removeCoreSession drops a session from the sessions file, keeping the
+// sessions other processes changed since it was loaded
+func (m *Manager) removeCoreSession(sessionID string) error {
+	lock, err := m.lockSessionsFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return err
+	}
+	return m.saveCoreSessions(slices.DeleteFunc(cores, func(core types.CoreSession) bool {
+		return core.ID == sessionID
+	}))
+}
+
 func (m *Manager) addCoreSession(core types.CoreSession) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	lock, err := m.lockSessionsFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	sessions, err := m.loadCoreSessions()
 	if err != nil {
 		return err
 	}
 
+	// Another process may have saved a session of the same name or worktree
+	// since the caller checked
+	if err := duplicateName(sessions, core.Name); err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if filepath.Clean(session.WorktreePath) == filepath.Clean(core.WorktreePath) {
+			return fmt.Errorf("worktree %s is already used by session '%s'", core.WorktreePath, session.Name)
+		}
+	}
+
 	sessions = append(sessions, core)
 	return m.saveCoreSessions(sessions)
 }
@@ -307,162 +1043,234 @@ func (m *Manager) checkDuplicateName(name string) error {
 	if err != nil {
 		return err
 	}
+	return duplicateName(sessions, name)
+}
 
+// duplicateName returns an error if one of sessions is named name
+func duplicateName(sessions []types.CoreSession, name string) error {
 	for _, session := range sessions {
 		if session.Name == name {
 			return fmt.Errorf("session with name '%s' already exists", name)
 		}
 	}
-
 	return nil
 }
 
-func (m *Manager) createExternalResources(core types.CoreSession) error {
+// freeWorktreePath returns path, or the first of path-2, path-3 and so on
+// no session has its worktree at. Renamed sessions keep the worktree of
+// their old name, which a new session of that name can't share.
+func (m *Manager) freeWorktreePath(path string) (string, error) {
+	sessions, err := m.loadCoreSessions()
+	if err != nil {
+		return "", err
+	}
+
+	taken := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		taken[filepath.Clean(session.WorktreePath)] = true
+	}
+	free := path
+	for i := 2; taken[filepath.Clean(free)]; i++ {
+		free = fmt.Sprintf("%s-%d", path, i)
+	}
+	return free, nil
+}
+
+func (m *Manager) createExternalResources(ctx context.Context, core types.CoreSession, progress func(step string)) error {
+	report := func(step string) {
+		if progress != nil {
+			progress(step)
+		}
+	}
+
 	// Validate git repository first
 	if err := m.config.GitChecker.IsValidRepository(""); err != nil {
 		return fmt.Errorf("git repository validation failed: %w", err)
 	}
 
-	// Create git worktree
-	if err := m.config.GitChecker.CreateWorktree(core.Name, core.WorktreePath); err != nil {
+	// Create git worktree; it cleans up after itself when it fails
+	report("Creating worktree")
+	if err := m.config.GitChecker.CreateWorktree(ctx, core.Name, core.WorktreePath, progress); err != nil {
 		return fmt.Errorf("failed to create git worktree: %w", err)
 	}
 
+	// Check out submodules and LFS files, which git worktree add leaves out
+	if err := m.config.GitChecker.PopulateWorktree(ctx, core.WorktreePath, progress); err != nil {
+		m.rollbackWorktree(core)
+		return fmt.Errorf("failed to populate git worktree: %w", err)
+	}
+
+	// Not fatal: Claude just goes without the project's local configuration
+	if agent.IsClaude(core.Agent) {
+		if copied, err := m.syncClaudeProject(core.WorktreePath); err != nil {
+			logger.Warn("failed to bring the project's Claude configuration into the worktree", "session", core.Name, "error", err)
+		} else if len(copied) > 0 {
+			logger.Info("copied Claude configuration", "session", core.Name, "files", copied)
+		}
+	}
+
+	// Files git leaves out, like .env, that the project needs to run
+	if patterns := m.untrackedPatterns(core); len(patterns) > 0 {
+		report("Copying untracked files")
+		copied, err := m.copyUntrackedFiles(core.WorktreePath, patterns)
+		if err != nil {
+			m.rollbackWorktree(core)
+			return err
+		}
+		logger.Info("copied untracked files", "session", core.Name, "files", copied)
+	}
+
+	// Commits made in the worktree must run the repository's git hooks
+	if err := m.installGitHooks(ctx, core.WorktreePath, report); err != nil {
+		m.rollbackWorktree(core)
+		return err
+	}
+
 	// Create Claude settings with hooks in the worktree
-	if err := m.createClaudeSettings(core.WorktreePath, core.ID); err != nil {
-		// Rollback git worktree
-		m.config.GitChecker.RemoveWorktree(core.WorktreePath)
+	if err := m.createClaudeSettings(core); err != nil {
+		m.rollbackWorktree(core)
 		return fmt.Errorf("failed to create Claude settings: %w", err)
 	}
 
-	// Create tmux session
-	// Check if claude is available, otherwise create session without it
-	var command string
-	if claudeExec := findClaudeExecutable(); claudeExec != "" {
-		command = claudeExec
+	// Not fatal: the session just goes without the shared context
+	if err := m.linkContextFiles(core.WorktreePath); err != nil {
+		logger.Warn("failed to share context files", "session", core.Name, "error", err)
+	}
+
+	// Set the worktree up, like installing dependencies, before the agent starts
+	if err := m.hookFailed(m.runHooks(ctx, core, HookPostCreate, report), core.Name); err != nil {
+		m.rollbackWorktree(core)
+		return err
+	}
+
+	// Last chance to cancel before the agent starts
+	if err := ctx.Err(); err != nil {
+		m.rollbackWorktree(core)
+		return err
 	}
 
-	err := m.config.TmuxChecker.CreateSession(core.TmuxSession, core.WorktreePath, command)
+	// Create tmux session, without the agent if it isn't installed. The task
+	// is the agent's initial prompt, pointing at the shared context.
+	report("Starting tmux session")
+	launch := m.AgentLaunch(core, "", m.initialPrompt(core.Task))
+	err := m.config.TmuxChecker.CreateSession(core.TmuxSession, core.WorktreePath, launch.Command, m.SessionEnv(core)...)
 	if err != nil {
-		// Rollback git worktree
-		m.config.GitChecker.RemoveWorktree(core.WorktreePath)
+		m.rollbackWorktree(core)
 		return fmt.Errorf("failed to create tmux session: %w", err)
 	}
+	m.TypePrompt(core, launch)
+
+	m.installExitHookOrWarn(core)
 
 	return nil
 }
 
-func (m *Manager) cleanupExternalResources(core types.CoreSession) {
-	// Kill tmux session (ignore errors)
-	m.config.TmuxChecker.KillSession(core.TmuxSession)
-
-	// Remove git worktree (ignore errors)
-	m.config.GitChecker.RemoveWorktree(core.WorktreePath)
-
-	// Remove session state file (ignore errors)
-	types.RemoveSessionState(m.config.DataDir, core.ID)
-}
-
-func generateSessionID() string {
-	return fmt.Sprintf("session-%d", time.Now().UnixNano())
-}
-
-// createClaudeSettings creates a settings.json file in the worktree with CWT hooks configured
-func (m *Manager) createClaudeSettings(worktreePath, sessionID string) error {
-	claudeDir := filepath.Join(worktreePath, ".claude")
-	settingsPath := filepath.Join(claudeDir, "settings.json")
-
-	// Ensure .claude directory exists
-	if err := os.MkdirAll(claudeDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .claude directory: %w", err)
-	}
-
-	// Get the current cwt executable path
-	cwtPath := m.getCwtExecutablePath()
-
-	settings := map[string]interface{}{
-		"hooks": map[string]interface{}{
-			"Notification": []map[string]interface{}{
-				{
-					"matcher": "",
-					"hooks": []map[string]interface{}{
-						{
-							"type":    "command",
-							"command": fmt.Sprintf("%s __hook %s notification", cwtPath, sessionID),
-						},
-					},
-				},
-			},
-			"Stop": []map[string]interface{}{
-				{
-					"matcher": "",
-					"hooks": []map[string]interface{}{
-						{
-							"type":    "command",
-							"command": fmt.Sprintf("%s __hook %s stop", cwtPath, sessionID),
-						},
-					},
-				},
-			},
-			"PreToolUse": []map[string]interface{}{
-				{
-					"matcher": "",
-					"hooks": []map[string]interface{}{
-						{
-							"type":    "command",
-							"command": fmt.Sprintf("%s __hook %s pre_tool_use", cwtPath, sessionID),
-						},
-					},
-				},
-			},
-			"PostToolUse": []map[string]interface{}{
-				{
-					"matcher": "",
-					"hooks": []map[string]interface{}{
-						{
-							"type":    "command",
-							"command": fmt.Sprintf("%s __hook %s post_tool_use", cwtPath, sessionID),
-						},
-					},
-				},
-			},
-			"SubagentStop": []map[string]interface{}{
-				{
-					"matcher": "",
-					"hooks": []map[string]interface{}{
-						{
-							"type":    "command",
-							"command": fmt.Sprintf("%s __hook %s subagent_stop", cwtPath, sessionID),
-						},
-					},
-				},
-			},
-			"PreCompact": []map[string]interface{}{
-				{
-					"matcher": "",
-					"hooks": []map[string]interface{}{
-						{
-							"type":    "command",
-							"command": fmt.Sprintf("%s __hook %s pre_compact", cwtPath, sessionID),
-						},
-					},
-				},
-			},
-		},
-	}
-
-	data, err := json.MarshalIndent(settings, "", "  ")
+// rollbackWorktree removes the worktree and branch of a session whose
+// creation failed, so creating it again doesn't trip over them
+func (m *Manager) rollbackWorktree(core types.CoreSession) {
+	logger.Info("rolling back session creation", "name", core.Name)
+	if err := m.config.GitChecker.RemoveWorktree(core.WorktreePath); err != nil {
+		logger.Warn("failed to remove worktree during rollback", "path", core.WorktreePath, "error", err)
+	}
+	if err := m.config.GitChecker.DeleteBranch(core.Name); err != nil {
+		logger.Warn("failed to delete branch during rollback", "branch", core.Name, "error", err)
+	}
+	// Hooks may already have logged events for it
+	if err := types.RemoveSessionState(m.config.DataDir, core.ID); err != nil {
+		logger.Debug("failed to remove session state during rollback", "id", core.ID, "error", err)
+	}
+}
+
+// InstallExitHook makes tmux record how a session's pane exits, so a dead
+// session can show why Claude stopped instead of just "dead"
+func (m *Manager) InstallExitHook(core types.CoreSession) error {
+	dataDir, err := filepath.Abs(m.config.DataDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve data directory: %w", err)
+	}
+
+	// Session paths are relative to the repository, so run from there too
+	repoDir, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to marshal Claude settings: %w", err)
+		return fmt.Errorf("failed to resolve repository directory: %w", err)
 	}
 
-	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write Claude settings file: %w", err)
+	command := fmt.Sprintf("cd %s && %s --data-dir %s __session-exited %s %s %s",
+		utils.ShellQuote(repoDir), m.getCwtExecutablePath(), utils.ShellQuote(dataDir), utils.ShellQuote(core.ID), utils.ShellQuote(core.TmuxSession), utils.ShellQuote("#{pane_dead_status}"))
+	return m.config.TmuxChecker.SetExitHook(core.TmuxSession, command)
+}
+
+// installExitHookOrWarn installs a session's exit hook, only logging a
+// failure: without the hook a dead session just can't say why it died
+func (m *Manager) installExitHookOrWarn(core types.CoreSession) {
+	if err := m.InstallExitHook(core); err != nil {
+		logger.Warn("failed to install tmux exit hook", "session", core.TmuxSession, "error", err)
 	}
+}
 
+// installGitHooks installs the repository's git hooks in a new worktree as
+// configured
+func (m *Manager) installGitHooks(ctx context.Context, worktreePath string, report func(step string)) error {
+	command := m.config.GitHooksInstall
+	switch command {
+	case "none":
+		return nil
+	case "auto":
+		command = ""
+	}
+
+	if report != nil {
+		report("Installing git hooks")
+	}
+	if _, err := m.config.GitChecker.InstallHooks(ctx, worktreePath, command); err != nil {
+		return fmt.Errorf("failed to install git hooks (set git_hooks.install to \"none\" to skip): %w", err)
+	}
 	return nil
 }
 
+func (m *Manager) cleanupExternalResources(core types.CoreSession) {
+	// Errors are only logged: a resource may already be gone
+	if err := m.config.TmuxChecker.KillSession(core.TmuxSession); err != nil {
+		logger.Debug("failed to kill tmux session", "session", core.TmuxSession, "error", err)
+	}
+	// Whatever of the worktree outlives this shouldn't call back into a
+	// session that is gone
+	removeClaudeSettings(core.WorktreePath)
+	if err := m.config.GitChecker.RemoveWorktree(core.WorktreePath); err != nil {
+		logger.Debug("failed to remove worktree", "path", core.WorktreePath, "error", err)
+	}
+	if err := types.RemoveSessionState(m.config.DataDir, core.ID); err != nil {
+		logger.Debug("failed to remove session state", "id", core.ID, "error", err)
+	}
+	if core.Review != nil {
+		if err := m.config.GitChecker.DeleteRef(reviewRef(core.ID)); err != nil {
+			logger.Debug("failed to delete review snapshot", "id", core.ID, "error", err)
+		}
+	}
+}
+
+// createClaudeSettings adds cwt's hooks to the Claude settings in the
+// worktree of a session running Claude, merged with any the project has
+func (m *Manager) createClaudeSettings(core types.CoreSession) error {
+	// Without hooks Claude's state is read from its transcripts and tmux
+	// alone, and the worktree is left as it was checked out. Other agents
+	// don't read Claude's settings.
+	if m.config.NoClaudeHooks || !agent.IsClaude(core.Agent) {
+		return nil
+	}
+	settingsPath := filepath.Join(core.WorktreePath, git.SettingsFile)
+	_, err := claude.MergeHookSettings(settingsPath, m.getCwtExecutablePath(), core.ID)
+	return err
+}
+
+// removeClaudeSettings takes cwt's hooks back out of the Claude settings in
+// a worktree, leaving the project's settings as they were
+func removeClaudeSettings(worktreePath string) {
+	if err := claude.RemoveHookSettings(filepath.Join(worktreePath, git.SettingsFile)); err != nil {
+		logger.Debug("failed to remove cwt hooks from Claude settings", "path", worktreePath, "error", err)
+	}
+}
+
 // getCwtExecutablePath determines the best path to use for cwt executable
 func (m *Manager) getCwtExecutablePath() string {
 	// First, try to find cwt in PATH (most reliable for installed binaries)
@@ -492,16 +1300,22 @@ func (m *Manager) getCwtExecutablePath() string {
 	return "cwt"
 }
 
-// findClaudeExecutable searches for claude in common installation paths
-func findClaudeExecutable() string {
-	claudePaths := []string{
-		"claude",
-		os.ExpandEnv("$HOME/.claude/local/claude"),
-		os.ExpandEnv("$HOME/.claude/local/node_modules/.bin/claude"),
-		"/usr/local/bin/claude",
+// currentUser returns the git user name, falling back to the OS user
+func currentUser() string {
+	if output, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(output)); name != "" {
+			return name
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
 	}
+	return ""
+}
 
-	for _, path := range claudePaths {
+// findClaudeExecutable searches for claude in common installation paths
+func findClaudeExecutable() string {
+	for _, path := range agent.Claude.Executables() {
 		cmd := exec.Command(path, "--version")
 		if err := cmd.Run(); err == nil {
 			return path
@@ -511,16 +1325,118 @@ func findClaudeExecutable() string {
 	return ""
 }
 
+// ClaudeExecutable returns the configured claude executable, or searches
+// common installation paths when none is configured
+func (m *Manager) ClaudeExecutable() string {
+	if m.config.ClaudeExecutable != "" {
+		return m.config.ClaudeExecutable
+	}
+	return findClaudeExecutable()
+}
+
+// agentOf returns the coding agent a session runs, Claude when it
+// names one this cwt doesn't know
+func agentOf(core types.CoreSession) agent.Agent {
+	found, err := agent.Lookup(core.Agent)
+	if err != nil {
+		logger.Warn("unknown agent, running Claude", "session", core.Name, "agent", core.Agent)
+		return agent.Claude
+	}
+	return found
+}
+
+// AgentExecutable returns the command starting the agent a session runs,
+// as configured or found in common installation paths, or ""
+func (m *Manager) AgentExecutable(core types.CoreSession) string {
+	a := agentOf(core)
+	if agent.IsClaude(a.Name()) {
+		return m.ClaudeExecutable()
+	}
+	if executable := m.config.Agents[a.Name()].Executable; executable != "" {
+		return executable
+	}
+	return agent.FindExecutable(a)
+}
+
+// defaultAgentArgs returns the configured arguments new sessions start an agent with
+func (m *Manager) defaultAgentArgs(a agent.Agent) []string {
+	if agent.IsClaude(a.Name()) {
+		return m.config.ClaudeArgs
+	}
+	return m.config.Agents[a.Name()].Args
+}
+
+// AgentLaunch returns how to start the agent of a session with its
+// arguments, resuming conversationID and sending prompt when they aren't
+// empty; its command is "" when the agent isn't installed. Sessions created
+// before their arguments were recorded get the configured ones.
+func (m *Manager) AgentLaunch(core types.CoreSession, conversationID, prompt string) agent.Launch {
+	executable := m.AgentExecutable(core)
+	if executable == "" {
+		return agent.Launch{}
+	}
+	args := core.ClaudeArgs
+	if args == nil {
+		args = m.defaultAgentArgs(agentOf(core))
+	}
+	return agentOf(core).Launch(executable, args, conversationID, prompt)
+}
+
+// TypePrompt types the prompt of a launch into a session's tmux session,
+// for agents whose command line can't take one
+func (m *Manager) TypePrompt(core types.CoreSession, launch agent.Launch) {
+	if launch.Command == "" || launch.Typed == "" {
+		return
+	}
+	if err := m.config.TmuxChecker.SendKeys(core.TmuxSession, launch.Typed); err != nil {
+		logger.Warn("failed to type the prompt into the agent", "session", core.Name, "error", err)
+	}
+}
+
+// AgentChecker returns the checker deriving the status of the agent a session runs
+func (m *Manager) AgentChecker(core types.CoreSession) agent.Checker {
+	a := agentOf(core)
+	if agent.IsClaude(a.Name()) {
+		return m.config.ClaudeChecker
+	}
+	return m.config.AgentCheckers[a.Name()]
+}
+
+// agentInstalled reports whether the executable of the agent a session runs can be found
+func (m *Manager) agentInstalled(core types.CoreSession) bool {
+	command := strings.Fields(m.AgentExecutable(core))
+	if len(command) == 0 {
+		return false
+	}
+	_, err := exec.LookPath(command[0])
+	return err == nil
+}
+
+// Profile returns the recorder timing the manager's work, or nil
+func (m *Manager) Profile() *profile.Recorder {
+	return m.config.Profile
+}
+
 // GetDataDir returns the data directory path
 func (m *Manager) GetDataDir() string {
 	return m.config.DataDir
 }
 
+// GetBaseBranch returns the branch sessions are created from
+func (m *Manager) GetBaseBranch() string {
+	return m.config.BaseBranch
+}
+
 // GetTmuxChecker returns the tmux checker for direct access
 func (m *Manager) GetTmuxChecker() tmux.Checker {
 	return m.config.TmuxChecker
 }
 
+// GetGitChecker returns the git checker for direct access
+func (m *Manager) GetGitChecker() git.Checker {
+	return m.config.GitChecker
+}
+
 // GetClaudeChecker returns the claude checker for direct access
 func (m *Manager) GetClaudeChecker() claude.Checker {
 	return m.config.ClaudeChecker
@@ -1,13 +1,23 @@
 package state
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/agent"
 	"github.com/jlaneve/cwt-cli/internal/clients/claude"
 	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/statusprovider"
 	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/clock"
+	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
 func TestManager_CreateSession(t *testing.T) {
@@ -47,6 +57,39 @@ func TestManager_CreateSession(t *testing.T) {
 	}
 }
 
+func TestManager_CreateSession_Clock(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		Clock:         clock.NewMockClock(created),
+		IDs:           clock.NewSequentialIDs(),
+	})
+
+	for _, name := range []string{"first", "second"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+	}
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+
+	ids := map[string]string{}
+	for _, session := range sessions {
+		ids[session.Core.Name] = session.Core.ID
+		if !session.Core.CreatedAt.Equal(created) || !session.LastActivity.Equal(created) {
+			t.Errorf("%s created %v, last active %v, want both %v", session.Core.Name, session.Core.CreatedAt, session.LastActivity, created)
+		}
+	}
+	if ids["first"] != "session-1" || ids["second"] != "session-2" {
+		t.Errorf("IDs = %v, want session-1 and session-2 in order of creation", ids)
+	}
+}
+
 func TestManager_CreateSession_InvalidName(t *testing.T) {
 	tmpDir := t.TempDir()
 	dataDir := filepath.Join(tmpDir, ".cwt")
@@ -192,3 +235,884 @@ func TestManager_LoadCoreSessions_CorruptedJSON(t *testing.T) {
 		t.Error("Expected nil sessions for corrupted JSON")
 	}
 }
+
+func TestManager_DeriveSession(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, ".cwt")
+
+	config := Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	}
+
+	manager := NewManager(config)
+
+	if err := manager.CreateSession("test-session"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+
+	session, err := manager.DeriveSession(sessions[0].Core.ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if session.Core.Name != "test-session" {
+		t.Errorf("Expected session name 'test-session', got %v", session.Core.Name)
+	}
+
+	if _, err := manager.DeriveSession("missing"); err == nil {
+		t.Error("DeriveSession() should return error for unknown ID")
+	}
+}
+
+func TestManager_DeriveSession_Branch(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "develop",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	core := cores[0]
+
+	tests := []struct {
+		name       string
+		fromStatus string // Branch git status reported
+		checkedOut string // Branch a separate lookup finds
+		fail       bool
+		want       string
+	}{
+		// The status already names the branch, so there's no second lookup
+		{name: "branch from status", fromStatus: "feature/auth", checkedOut: "stale", want: "feature/auth"},
+		{name: "checked out branch", checkedOut: "feature/auth", want: "feature/auth"},
+		{name: "detached HEAD", checkedOut: "HEAD", want: "auth"},
+		{name: "worktree gone", fail: true, want: "auth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitChecker.Statuses[core.WorktreePath] = types.GitStatus{Branch: tt.fromStatus}
+			gitChecker.Branches[core.WorktreePath] = tt.checkedOut
+			gitChecker.ShouldFail[core.WorktreePath] = tt.fail
+
+			session := manager.deriveSession(core)
+			if session.BranchName() != tt.want || session.BaseBranch != "develop" {
+				t.Errorf("branch = %q into %q, want %q into develop", session.BranchName(), session.BaseBranch, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_DeriveSession_NoAgent(t *testing.T) {
+	claudeChecker := claude.NewMockChecker()
+	config := Config{
+		DataDir:          filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:      tmux.NewMockChecker(),
+		GitChecker:       git.NewMockChecker(),
+		ClaudeChecker:    claudeChecker,
+		ClaudeExecutable: "go",
+	}
+	manager := NewManager(config)
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	claudeChecker.Statuses[cores[0].WorktreePath] = types.ClaudeStatus{State: types.ClaudeNotStarted}
+
+	if state := manager.deriveSession(cores[0]).ClaudeStatus.State; state != types.ClaudeNotStarted {
+		t.Errorf("state = %s, want %s with claude installed", state, types.ClaudeNotStarted)
+	}
+
+	config.ClaudeExecutable = filepath.Join(t.TempDir(), "claude")
+	manager = NewManager(config)
+	defer manager.Close()
+	if state := manager.deriveSession(cores[0]).ClaudeStatus.State; state != types.ClaudeNoAgent {
+		t.Errorf("state = %s, want %s without claude installed", state, types.ClaudeNoAgent)
+	}
+}
+
+func TestManager_DeriveSession_GitError(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, ".cwt")
+	gitChecker := git.NewMockChecker()
+
+	config := Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	}
+
+	manager := NewManager(config)
+
+	if err := manager.CreateSession("broken"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+
+	worktreePath := sessions[0].Core.WorktreePath
+	gitChecker.SetStatusError(worktreePath, &git.StatusError{
+		Kind: types.GitErrorNotRepository,
+		Path: worktreePath,
+	})
+
+	session, err := manager.DeriveSession(sessions[0].Core.ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if !session.GitStatus.HasError() {
+		t.Fatal("Expected git status to carry an error")
+	}
+	if session.GitStatus.ErrorKind != types.GitErrorNotRepository {
+		t.Errorf("Expected error kind %q, got %q", types.GitErrorNotRepository, session.GitStatus.ErrorKind)
+	}
+	if session.GitStatus.Error == "" {
+		t.Error("Expected error message to be set")
+	}
+}
+
+func TestManager_DeriveFreshSessions_BatchedLiveness(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, ".cwt")
+	tmuxChecker := tmux.NewMockChecker()
+
+	config := Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	}
+
+	manager := NewManager(config)
+
+	for _, name := range []string{"one", "two", "three"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+	}
+	tmuxChecker.SetSessionAlive("cwt-two", false)
+
+	tmuxChecker.ListCalls = 0
+	tmuxChecker.AliveCalls = 0
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+
+	if tmuxChecker.ListCalls != 1 {
+		t.Errorf("Expected 1 tmux list call, got %d", tmuxChecker.ListCalls)
+	}
+	if tmuxChecker.AliveCalls != 0 {
+		t.Errorf("Expected no per-session tmux calls, got %d", tmuxChecker.AliveCalls)
+	}
+
+	for _, session := range sessions {
+		wantAlive := session.Core.Name != "two"
+		if session.IsAlive != wantAlive {
+			t.Errorf("Session %s IsAlive = %v, want %v", session.Core.Name, session.IsAlive, wantAlive)
+		}
+	}
+}
+
+func TestManager_CreateSessionWithTask(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, ".cwt")
+	tmuxChecker := tmux.NewMockChecker()
+
+	config := Config{
+		DataDir:          dataDir,
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       git.NewMockChecker(),
+		ClaudeChecker:    claude.NewMockChecker(),
+		BaseBranch:       "main",
+		ClaudeExecutable: "claude",
+	}
+
+	manager := NewManager(config)
+
+	err := manager.CreateSessionWithOptions("task-session", CreateOptions{
+		Task:      "Fix the user's login bug",
+		CreatedBy: "alice",
+		Source:    "https://github.com/org/repo/issues/7",
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionWithOptions() error = %v", err)
+	}
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	if sessions[0].Core.Task != "Fix the user's login bug" {
+		t.Errorf("Expected task to be persisted, got %q", sessions[0].Core.Task)
+	}
+	if sessions[0].Core.CreatedBy != "alice" || sessions[0].Core.Source != "https://github.com/org/repo/issues/7" {
+		t.Errorf("Expected metadata to be persisted, got %+v", sessions[0].Core)
+	}
+
+	expected := `claude 'Fix the user'\''s login bug'`
+	if got := tmuxChecker.SessionCommands["cwt-task-session"]; got != expected {
+		t.Errorf("Expected tmux command %q, got %q", expected, got)
+	}
+}
+
+func TestManager_CreateSession_ClaudeArgs(t *testing.T) {
+	tmuxChecker := tmux.NewMockChecker()
+	config := Config{
+		DataDir:          filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       git.NewMockChecker(),
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+		ClaudeArgs:       []string{"--model", "opus"},
+	}
+	manager := NewManager(config)
+
+	err := manager.CreateSessionWithOptions("args", CreateOptions{
+		Task:       "Plan it",
+		ClaudeArgs: []string{"--append-system-prompt", "Don't guess"},
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionWithOptions() error = %v", err)
+	}
+	expected := `claude '--model' 'opus' '--append-system-prompt' 'Don'\''t guess' 'Plan it'`
+	if got := tmuxChecker.SessionCommands["cwt-args"]; got != expected {
+		t.Errorf("Expected tmux command %q, got %q", expected, got)
+	}
+
+	// The session keeps its arguments when the config changes
+	config.ClaudeArgs = []string{"--model", "haiku"}
+	manager = NewManager(config)
+	cores, _ := manager.CoreSessions()
+	expected = `claude '--model' 'opus' '--append-system-prompt' 'Don'\''t guess' -r abc`
+	if got := manager.AgentLaunch(cores[0], "abc", "").Command; got != expected {
+		t.Errorf("AgentLaunch() = %q, expected %q", got, expected)
+	}
+
+	err = manager.CreateSessionWithOptions("resumed", CreateOptions{ClaudeArgs: []string{"--resume=abc"}})
+	if err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Errorf("Expected cwt's own flags to be rejected, got %v", err)
+	}
+}
+
+func TestManager_CreateSession_Agent(t *testing.T) {
+	tmuxChecker := tmux.NewMockChecker()
+	aiderChecker := claude.NewMockChecker()
+	config := Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		Agents: map[string]AgentSettings{
+			"aider": {Executable: "aider", Args: []string{"--model", "sonnet"}},
+		},
+		AgentCheckers: map[string]agent.Checker{"aider": aiderChecker},
+	}
+	manager := NewManager(config)
+
+	err := manager.CreateSessionWithOptions("port", CreateOptions{Task: "Port the parser", Agent: "aider"})
+	if err != nil {
+		t.Fatalf("CreateSessionWithOptions() error = %v", err)
+	}
+	if got := tmuxChecker.SessionCommands["cwt-port"]; got != `aider '--model' 'sonnet'` {
+		t.Errorf("Expected aider to start with its configured args, got %q", got)
+	}
+	if sent := tmuxChecker.SentKeys["cwt-port"]; len(sent) != 1 || sent[0] != "Port the parser" {
+		t.Errorf("Expected the task to be typed into aider, got %q", sent)
+	}
+
+	cores, _ := manager.CoreSessions()
+	if cores[0].Agent != "aider" {
+		t.Errorf("Expected the session to record its agent, got %q", cores[0].Agent)
+	}
+	if _, err := os.Stat(filepath.Join(cores[0].WorktreePath, ".claude", "settings.json")); err == nil {
+		t.Error("Claude's hook settings should be left out of an aider session's worktree")
+	}
+
+	// Its status comes from the agent's checker
+	aiderChecker.SetStatus(cores[0].WorktreePath, types.ClaudeStatus{State: types.ClaudeWaiting})
+	manager.InvalidateStatus(cores[0].ID)
+	session, err := manager.DeriveSession(cores[0].ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if session.ClaudeStatus.State != types.ClaudeWaiting {
+		t.Errorf("Expected aider's status, got %s", session.ClaudeStatus.State)
+	}
+	if got := manager.AgentLaunch(cores[0], "h", "").Command; got != `aider '--model' 'sonnet' '--restore-chat-history'` {
+		t.Errorf("AgentLaunch() = %q", got)
+	}
+
+	err = manager.CreateSessionWithOptions("other", CreateOptions{Agent: "cursor"})
+	if err == nil || !strings.Contains(err.Error(), "unknown agent") {
+		t.Errorf("Expected an unknown agent to be rejected, got %v", err)
+	}
+	err = manager.CreateSessionWithOptions("quiet", CreateOptions{Agent: "aider", ClaudeArgs: []string{"--message=hi"}})
+	if err == nil || !strings.Contains(err.Error(), "--message") {
+		t.Errorf("Expected aider's managed flags to be rejected, got %v", err)
+	}
+}
+
+func TestManager_UpdateSession(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, ".cwt")
+
+	config := Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	}
+
+	manager := NewManager(config)
+	events := manager.EventBus()
+
+	if err := manager.CreateSession("first"); err != nil {
+		t.Fatalf("CreateSession(first) error = %v", err)
+	}
+	if err := manager.CreateSession("second"); err != nil {
+		t.Fatalf("CreateSession(second) error = %v", err)
+	}
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	sessionID := sessions[0].Core.ID
+
+	// Drain creation events
+	for len(events) > 0 {
+		<-events
+	}
+
+	err = manager.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.Task = "updated task"
+	})
+	if err != nil {
+		t.Fatalf("UpdateSession() error = %v", err)
+	}
+
+	session, err := manager.DeriveSession(sessionID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if session.Core.Task != "updated task" {
+		t.Errorf("Expected task to be persisted, got %q", session.Core.Task)
+	}
+
+	select {
+	case event := <-events:
+		updated, ok := event.(types.SessionUpdated)
+		if !ok {
+			t.Fatalf("Expected SessionUpdated event, got %T", event)
+		}
+		if updated.Previous.Task != "" || updated.Session.Core.Task != "updated task" {
+			t.Errorf("Unexpected event contents: %+v", updated)
+		}
+	default:
+		t.Error("Expected SessionUpdated event to be published")
+	}
+
+	// Changing the ID is rejected
+	err = manager.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.ID = "other"
+	})
+	if err == nil {
+		t.Error("UpdateSession() should reject ID changes")
+	}
+
+	// Renaming to an existing name is rejected
+	err = manager.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.Name = "second"
+	})
+	if err == nil {
+		t.Error("UpdateSession() should reject duplicate names")
+	}
+
+	// Unknown sessions are reported
+	if err := manager.UpdateSession("missing", func(*types.CoreSession) {}); err == nil {
+		t.Error("UpdateSession() should return error for unknown ID")
+	}
+}
+
+func TestManager_UpdateSession_SharedDataDir(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	newManager := func() *Manager {
+		return NewManager(Config{
+			DataDir:       dataDir,
+			TmuxChecker:   tmux.NewMockChecker(),
+			GitChecker:    git.NewMockChecker(),
+			ClaudeChecker: claude.NewMockChecker(),
+			BaseBranch:    "main",
+		})
+	}
+	// Two managers stand for two cwt processes, like a pair of dashboards
+	first, second := newManager(), newManager()
+	if err := first.CreateSession("shared"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	sessions, err := first.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	sessionID := sessions[0].Core.ID
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		manager := first
+		if i%2 == 1 {
+			manager = second
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := manager.UpdateSession(sessionID, func(core *types.CoreSession) {
+				core.Tags = append(core.Tags, strconv.Itoa(i))
+			})
+			if err != nil {
+				t.Errorf("UpdateSession() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cores, err := second.CoreSessions()
+	if err != nil {
+		t.Fatalf("CoreSessions() error = %v", err)
+	}
+	if len(cores) != 1 || len(cores[0].Tags) != 20 {
+		t.Errorf("sessions = %+v, want one session with every update's tag", cores)
+	}
+}
+
+// fakeProvider is a SessionProvider that serves fixed sessions or fails
+type fakeProvider struct {
+	sessions    []types.Session
+	err         error
+	invalidated []string
+}
+
+func (p *fakeProvider) Sessions() ([]types.Session, error) {
+	return p.sessions, p.err
+}
+
+func (p *fakeProvider) Session(sessionID string) (types.Session, error) {
+	return types.Session{}, p.err
+}
+
+func (p *fakeProvider) Invalidate(sessionID string) error {
+	p.invalidated = append(p.invalidated, sessionID)
+	return p.err
+}
+
+func TestManager_Provider(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, ".cwt")
+	provider := &fakeProvider{
+		sessions: []types.Session{{Core: types.CoreSession{ID: "from-daemon", Name: "daemon-session"}}},
+	}
+
+	config := Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+		Provider:      provider,
+	}
+
+	manager := NewManager(config)
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Core.ID != "from-daemon" {
+		t.Errorf("Expected sessions from provider, got %+v", sessions)
+	}
+
+	if err := manager.CreateSession("local"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if len(provider.invalidated) == 0 {
+		t.Error("Expected CreateSession to invalidate the provider")
+	}
+
+	// A failing provider is dropped and sessions are derived directly
+	provider.err = os.ErrDeadlineExceeded
+	sessions, err = manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Core.Name != "local" {
+		t.Errorf("Expected directly derived sessions, got %+v", sessions)
+	}
+	if manager.UsingProvider() {
+		t.Error("Expected failing provider to be dropped")
+	}
+}
+
+func TestManager_DeriveSession_Exit(t *testing.T) {
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("quota"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	core := cores[0]
+
+	hook := tmuxChecker.ExitHooks[core.TmuxSession]
+	if !strings.Contains(hook, "__session-exited") || !strings.Contains(hook, core.ID) {
+		t.Errorf("exit hook = %q, want it to report the session's exit", hook)
+	}
+
+	exit := types.NewSessionExit(time.Now(), 1, "Claude usage limit reached\n")
+	if err := types.SaveSessionExit(manager.GetDataDir(), core.ID, exit); err != nil {
+		t.Fatalf("SaveSessionExit() error = %v", err)
+	}
+
+	// A recorded exit is only shown while the pane is gone
+	session, _ := manager.DeriveSession(core.ID)
+	if session.Exit != nil {
+		t.Error("a live session should not report an exit")
+	}
+
+	tmuxChecker.AliveSessions[core.TmuxSession] = false
+	manager.InvalidateStatus(core.ID)
+	session, _ = manager.DeriveSession(core.ID)
+	if session.Exit == nil || session.Exit.Cause != types.ExitQuota {
+		t.Errorf("Exit = %+v, want the recorded quota error", session.Exit)
+	}
+}
+
+func TestManager_CreateSession_GitHooks(t *testing.T) {
+	tests := []struct {
+		install     string
+		wantCommand string
+		wantRun     bool
+	}{
+		{"", "", true},
+		{"auto", "", true},
+		{"none", "", false},
+		{"make hooks", "make hooks", true},
+	}
+	for _, tt := range tests {
+		gitChecker := git.NewMockChecker()
+		manager := NewManager(Config{
+			DataDir:         filepath.Join(t.TempDir(), ".cwt"),
+			TmuxChecker:     tmux.NewMockChecker(),
+			GitChecker:      gitChecker,
+			ClaudeChecker:   claude.NewMockChecker(),
+			BaseBranch:      "main",
+			GitHooksInstall: tt.install,
+		})
+
+		if err := manager.CreateSession("hooks"); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		command, ran := gitChecker.HookInstalls[filepath.Join(manager.GetDataDir(), "worktrees", "hooks")]
+		if ran != tt.wantRun || command != tt.wantCommand {
+			t.Errorf("install %q ran %q (%v), want %q (%v)", tt.install, command, ran, tt.wantCommand, tt.wantRun)
+		}
+	}
+
+	// A failed install rolls the worktree back
+	gitChecker := git.NewMockChecker()
+	gitChecker.FailHooks = true
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	})
+	if err := manager.CreateSession("hooks"); err == nil {
+		t.Fatal("CreateSession() should fail when the hooks can't be installed")
+	}
+	if len(gitChecker.Worktrees) != 0 {
+		t.Errorf("worktrees = %v, want the worktree removed", gitChecker.Worktrees)
+	}
+}
+
+func TestManager_CreateSession_ClaudeHooks(t *testing.T) {
+	for _, noHooks := range []bool{false, true} {
+		manager := NewManager(Config{
+			DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+			TmuxChecker:   tmux.NewMockChecker(),
+			GitChecker:    git.NewMockChecker(),
+			ClaudeChecker: claude.NewMockChecker(),
+			NoClaudeHooks: noHooks,
+		})
+
+		if err := manager.CreateSession("hooks"); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		worktree := filepath.Join(manager.GetDataDir(), "worktrees", "hooks")
+		_, err := os.Stat(filepath.Join(worktree, git.SettingsFile))
+		if written := err == nil; written == noHooks {
+			t.Errorf("with NoClaudeHooks = %v, settings written = %v", noHooks, written)
+		}
+		if noHooks && len(git.CwtWrittenFiles(worktree)) != 0 {
+			t.Errorf("CwtWrittenFiles() = %v, want none without hooks", git.CwtWrittenFiles(worktree))
+		}
+		if !noHooks && len(git.CwtWrittenFiles(worktree)) != 1 {
+			t.Errorf("CwtWrittenFiles() = %v, want the hook settings", git.CwtWrittenFiles(worktree))
+		}
+	}
+}
+
+func TestManager_CreateSessionContext_Cancelled(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	gitChecker.Delay = time.Second
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	var steps []string
+	err := manager.CreateSessionContext(ctx, "slow", CreateOptions{
+		Progress: func(step string) { steps = append(steps, step) },
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateSessionContext() error = %v, want context.Canceled", err)
+	}
+	if len(steps) == 0 || steps[0] != "Creating worktree" {
+		t.Errorf("progress steps = %v, want worktree creation reported", steps)
+	}
+
+	sessions, err := manager.CoreSessions()
+	if err != nil {
+		t.Fatalf("CoreSessions() error = %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("sessions = %v, want none saved", sessions)
+	}
+	if len(gitChecker.Worktrees) != 0 {
+		t.Errorf("worktrees = %v, want none left", gitChecker.Worktrees)
+	}
+}
+
+func TestManager_CreateSession_ConcurrentDuplicate(t *testing.T) {
+	// Two processes sharing the data directory, each with its own checkers
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	newManager := func(gitChecker *git.MockChecker) *Manager {
+		return NewManager(Config{
+			DataDir:       dataDir,
+			TmuxChecker:   tmux.NewMockChecker(),
+			GitChecker:    gitChecker,
+			ClaudeChecker: claude.NewMockChecker(),
+			BaseBranch:    "main",
+		})
+	}
+	slowGit := git.NewMockChecker()
+	slowGit.Delay = 200 * time.Millisecond
+	slow, fast := newManager(slowGit), newManager(git.NewMockChecker())
+	defer slow.Close()
+	defer fast.Close()
+
+	// The slow one checks the name, then the fast one saves it meanwhile
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- slow.CreateSessionContext(context.Background(), "auth", CreateOptions{
+			Progress: func(step string) {
+				if step == "Creating worktree" {
+					close(started)
+				}
+			},
+		})
+	}()
+	<-started
+	if err := fast.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if err := <-done; err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("CreateSessionContext() error = %v, want the duplicate refused when saving", err)
+	}
+	cores, err := slow.CoreSessions()
+	if err != nil || len(cores) != 1 {
+		t.Fatalf("CoreSessions() = %v, %v, want only the first session saved", cores, err)
+	}
+	if len(slowGit.Worktrees) != 0 {
+		t.Errorf("worktrees = %v, want the refused session's worktree removed", slowGit.Worktrees)
+	}
+}
+
+func TestManager_StatusProviders(t *testing.T) {
+	providers := statusprovider.NewMockChecker()
+	providers.Fields["ticket"] = []types.StatusField{{Provider: "jira", Name: "state", Value: "In Review"}}
+	manager := NewManager(Config{
+		DataDir:         filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:     tmux.NewMockChecker(),
+		GitChecker:      git.NewMockChecker(),
+		ClaudeChecker:   claude.NewMockChecker(),
+		StatusProviders: providers,
+	})
+	defer manager.Close()
+
+	for _, name := range []string{"ticket", "plain"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+
+	sessions, err := manager.DeriveFreshSessions()
+	if err != nil {
+		t.Fatalf("DeriveFreshSessions() error = %v", err)
+	}
+	for _, session := range sessions {
+		want := len(providers.Fields[session.Core.Name])
+		if len(session.Extra) != want {
+			t.Errorf("session %s Extra = %+v, want %d field(s)", session.Core.Name, session.Extra, want)
+		}
+	}
+
+	session, err := manager.DeriveSession(sessions[0].Core.ID)
+	if err != nil {
+		t.Fatalf("DeriveSession() error = %v", err)
+	}
+	if session.Core.Name == "ticket" && (len(session.Extra) != 1 || session.Extra[0].Value != "In Review") {
+		t.Errorf("Extra = %+v, want the provider's field", session.Extra)
+	}
+}
+
+func TestManager_DeleteSessionWithOptions_Branch(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	for _, name := range []string{"merged", "unmerged", "kept", "squashed"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+	gitChecker.Unmerged["unmerged"] = true
+	gitChecker.Unmerged["squashed"] = true
+	cores, _ := manager.CoreSessions()
+
+	tests := []struct {
+		opts DeleteOptions
+		want DeleteResult
+	}{
+		{DeleteOptions{}, DeleteResult{Branch: "merged", BranchDeleted: true}},
+		{DeleteOptions{}, DeleteResult{Branch: "unmerged", BranchUnmerged: true}},
+		{DeleteOptions{KeepBranch: true}, DeleteResult{Branch: "kept"}},
+		{DeleteOptions{Force: true, DeleteBranch: true}, DeleteResult{Branch: "squashed", BranchDeleted: true}},
+	}
+	for i, tt := range tests {
+		result, err := manager.DeleteSessionWithOptions(cores[i].ID, tt.opts)
+		if err != nil {
+			t.Fatalf("DeleteSessionWithOptions(%s) error = %v", cores[i].Name, err)
+		}
+		if result != tt.want {
+			t.Errorf("DeleteSessionWithOptions(%s) = %+v, want %+v", cores[i].Name, result, tt.want)
+		}
+	}
+	if strings.Join(gitChecker.Deleted, ",") != "merged,squashed" {
+		t.Errorf("deleted branches = %v, want the merged one and the one deleted regardless", gitChecker.Deleted)
+	}
+}
+
+func TestManager_DeleteSession_UnmergedWork(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	for _, name := range []string{"dirty", "ahead"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+	cores, _ := manager.CoreSessions()
+	dirty, ahead := cores[0], cores[1]
+	gitChecker.Statuses[dirty.WorktreePath] = types.GitStatus{HasChanges: true, ModifiedFiles: []string{"a.go"}, UntrackedFiles: []string{"b.go"}}
+	gitChecker.Ahead["ahead"] = []types.CommitOutput{{Hash: "abc", Subject: "Add login"}}
+
+	work, err := manager.UnmergedWork(dirty.ID)
+	if err != nil || len(work.Uncommitted) != 2 || len(work.Commits) != 0 {
+		t.Errorf("UnmergedWork(dirty) = %+v, %v; want its two uncommitted files", work, err)
+	}
+
+	// A worktree git can't read may hold changes, one that is gone doesn't
+	gitChecker.StatusErrors[ahead.WorktreePath] = errors.New("fatal: unable to read index")
+	if work, err := manager.UnmergedWork(ahead.ID); err != nil || work.Unchecked == "" {
+		t.Errorf("UnmergedWork() = %+v, %v; want the worktree unchecked", work, err)
+	}
+	gitChecker.StatusErrors[ahead.WorktreePath] = &git.StatusError{Kind: types.GitErrorMissingWorktree, Path: ahead.WorktreePath}
+	if work, err := manager.UnmergedWork(ahead.ID); err != nil || work.Unchecked != "" || len(work.Uncommitted) != 0 {
+		t.Errorf("UnmergedWork() = %+v, %v; want no uncommitted changes in a missing worktree", work, err)
+	}
+	delete(gitChecker.StatusErrors, ahead.WorktreePath)
+
+	// So may a worktree in the middle of a merge
+	gitChecker.Statuses[ahead.WorktreePath] = types.GitStatus{ConflictedFiles: []types.ConflictedFile{{Path: "auth.go", Kind: types.ConflictBothModified}}}
+	if work, _ := manager.UnmergedWork(ahead.ID); len(work.Uncommitted) != 1 || work.Uncommitted[0] != "auth.go" {
+		t.Errorf("UnmergedWork() = %+v, want the conflicted file uncommitted", work)
+	}
+	delete(gitChecker.Statuses, ahead.WorktreePath)
+
+	for _, core := range []types.CoreSession{dirty, ahead} {
+		err := manager.DeleteSession(core.ID)
+		var unmerged *UnmergedWorkError
+		if !errors.As(err, &unmerged) || unmerged.Work.Empty() {
+			t.Errorf("DeleteSession(%s) error = %v, want an UnmergedWorkError", core.Name, err)
+		}
+	}
+	if remaining, _ := manager.CoreSessions(); len(remaining) != 2 {
+		t.Fatalf("sessions = %d, want both kept", len(remaining))
+	}
+
+	if _, err := manager.DeleteSessionWithOptions(ahead.ID, DeleteOptions{Force: true}); err != nil {
+		t.Fatalf("DeleteSessionWithOptions(Force) error = %v", err)
+	}
+	if remaining, _ := manager.CoreSessions(); len(remaining) != 1 {
+		t.Errorf("sessions = %d, want the forced deletion done", len(remaining))
+	}
+}
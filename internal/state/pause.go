@@ -0,0 +1,107 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// PauseSession stops a session's tmux session and Claude while keeping its
+// worktree and metadata. The Claude conversation ID is captured first, so
+// ResumeSession can pick the conversation back up however long it is paused.
+func (m *Manager) PauseSession(sessionID string) error {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if core.IsPaused() {
+		return fmt.Errorf("session '%s' is already paused", core.Name)
+	}
+
+	conversationID := m.conversationID(core)
+
+	if m.config.TmuxChecker.IsSessionAlive(core.TmuxSession) {
+		if err := m.config.TmuxChecker.KillSession(core.TmuxSession); err != nil {
+			return fmt.Errorf("failed to stop session '%s': %w", core.Name, err)
+		}
+	}
+
+	// A paused session didn't die, so there is no exit to report
+	types.RemoveSessionExit(m.config.DataDir, sessionID)
+	m.InvalidateStatus(sessionID)
+
+	pausedAt := m.Now()
+	if err := m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.PausedAt = &pausedAt
+		if conversationID != "" {
+			core.ClaudeSessionID = conversationID
+		}
+	}); err != nil {
+		return err
+	}
+
+	m.RecordEvent(sessionID, types.EventPaused, "Paused", nil)
+	return nil
+}
+
+// ResumeSession starts a paused session's tmux session again, resuming the
+// Claude conversation captured when it was paused
+func (m *Manager) ResumeSession(sessionID string) error {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if !core.IsPaused() {
+		return fmt.Errorf("session '%s' is not paused", core.Name)
+	}
+	if m.config.TmuxChecker.IsSessionAlive(core.TmuxSession) {
+		return fmt.Errorf("tmux session '%s' is already running", core.TmuxSession)
+	}
+
+	command := m.AgentLaunch(core, core.ClaudeSessionID, "").Command
+
+	if err := m.config.TmuxChecker.CreateSession(core.TmuxSession, core.WorktreePath, command, m.SessionEnv(core)...); err != nil {
+		return fmt.Errorf("failed to start tmux session: %w", err)
+	}
+
+	m.installExitHookOrWarn(core)
+	m.InvalidateStatus(sessionID)
+
+	if err := m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.PausedAt = nil
+	}); err != nil {
+		return err
+	}
+
+	m.RecordEvent(sessionID, types.EventResumed, "Resumed", nil)
+	return nil
+}
+
+// findCoreSession returns the stored metadata of a session
+func (m *Manager) findCoreSession(sessionID string) (types.CoreSession, error) {
+	cores, err := m.CoreSessions()
+	if err != nil {
+		return types.CoreSession{}, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	for _, core := range cores {
+		if core.ID == sessionID {
+			return core, nil
+		}
+	}
+	return types.CoreSession{}, fmt.Errorf("session with ID %s not found", sessionID)
+}
+
+// conversationID returns the ID of the agent's conversation running in a
+// session: the most recent one in its worktree, or else the one Claude's
+// hooks last reported, or else the one captured when it was last paused
+func (m *Manager) conversationID(core types.CoreSession) string {
+	if id, err := m.AgentChecker(core).FindSessionID(core.WorktreePath); err == nil && id != "" {
+		return id
+	}
+	if sessionState, err := types.LoadSessionState(m.config.DataDir, core.ID); err == nil && sessionState != nil {
+		if id, ok := sessionState.LastEventData["session_id"].(string); ok && id != "" {
+			return id
+		}
+	}
+	return core.ClaudeSessionID
+}
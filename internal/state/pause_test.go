@@ -0,0 +1,66 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_PauseAndResumeSession(t *testing.T) {
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:          filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       git.NewMockChecker(),
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+
+	if err := manager.ResumeSession(auth.ID); err == nil {
+		t.Error("resuming a session that isn't paused should fail")
+	}
+
+	if err := manager.PauseSession(auth.ID); err != nil {
+		t.Fatalf("PauseSession() error = %v", err)
+	}
+	if tmuxChecker.AliveSessions[auth.TmuxSession] {
+		t.Error("pausing should stop the tmux session")
+	}
+	cores, _ = manager.CoreSessions()
+	paused := cores[0]
+	if !paused.IsPaused() || paused.ClaudeSessionID != "mock-session-auth" {
+		t.Fatalf("paused session = %+v, want it paused with its conversation ID", paused)
+	}
+	if err := manager.PauseSession(auth.ID); err == nil {
+		t.Error("pausing a paused session should fail")
+	}
+
+	stale, _ := manager.FindStaleSessions()
+	if len(stale) != 0 {
+		t.Errorf("stale sessions = %d, want paused sessions left out", len(stale))
+	}
+
+	if err := manager.ResumeSession(auth.ID); err != nil {
+		t.Fatalf("ResumeSession() error = %v", err)
+	}
+	if command := tmuxChecker.SessionCommands[auth.TmuxSession]; command != "claude -r mock-session-auth" {
+		t.Errorf("resumed with %q, want the captured conversation resumed", command)
+	}
+	if _, ok := tmuxChecker.ExitHooks[auth.TmuxSession]; !ok {
+		t.Error("the resumed tmux session should get an exit hook")
+	}
+	cores, _ = manager.CoreSessions()
+	if cores[0].IsPaused() || cores[0].ClaudeSessionID != "mock-session-auth" {
+		t.Errorf("resumed session = %+v, want it active, keeping the conversation ID", cores[0])
+	}
+}
@@ -0,0 +1,19 @@
+package state
+
+import "github.com/jlaneve/cwt-cli/internal/types"
+
+// SetSessionPriority changes the triage priority of a session
+func (m *Manager) SetSessionPriority(sessionID string, priority types.Priority) error {
+	return m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.Priority = storedPriority(priority)
+	})
+}
+
+// storedPriority is how a priority is saved: normal, the default, is left
+// out of sessions.json
+func storedPriority(priority types.Priority) types.Priority {
+	if priority == types.PriorityNormal {
+		return ""
+	}
+	return priority
+}
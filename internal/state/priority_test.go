@@ -0,0 +1,37 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_SetSessionPriority(t *testing.T) {
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSessionWithOptions("auth", CreateOptions{Priority: types.PriorityHigh}); err != nil {
+		t.Fatalf("CreateSessionWithOptions() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	if cores[0].SessionPriority() != types.PriorityHigh {
+		t.Errorf("priority = %q, want high from creation", cores[0].Priority)
+	}
+
+	if err := manager.SetSessionPriority(cores[0].ID, types.PriorityNormal); err != nil {
+		t.Fatalf("SetSessionPriority() error = %v", err)
+	}
+	cores, _ = manager.CoreSessions()
+	if cores[0].Priority != "" || cores[0].SessionPriority() != types.PriorityNormal {
+		t.Errorf("priority = %q, want normal left out of sessions.json", cores[0].Priority)
+	}
+}
@@ -0,0 +1,36 @@
+package state
+
+import "github.com/jlaneve/cwt-cli/internal/types"
+
+// RecordPublish records a push of a session's branch at commit, and the
+// pull request it opened or updated, if any. The worktree is snapshotted
+// too, so ChangedSincePublish tells when it holds work the push didn't.
+func (m *Manager) RecordPublish(sessionID, commit, pullRequest string) (types.Publish, error) {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return types.Publish{}, err
+	}
+
+	publish := types.Publish{Commit: commit, PullRequest: pullRequest, At: m.Now()}
+	// Without a snapshot the push is still recorded; drift just isn't told
+	if tree, err := m.config.GitChecker.SnapshotWorktree(core.WorktreePath); err == nil {
+		publish.Tree = tree
+	} else {
+		logger.Debug("failed to snapshot published worktree", "session", core.Name, "error", err)
+	}
+
+	err = m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		publish.Revision = len(core.Publishes) + 1
+		core.Publishes = append(core.Publishes, publish)
+	})
+	m.InvalidateStatus(sessionID)
+	return publish, err
+}
+
+// publishTree returns the snapshot a session was last pushed at, or ""
+func publishTree(core types.CoreSession) string {
+	if last := core.LastPublish(); last != nil {
+		return last.Tree
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_RecordPublish(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:        filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:    tmux.NewMockChecker(),
+		GitChecker:     gitChecker,
+		ClaudeChecker:  claude.NewMockChecker(),
+		StatusCacheTTL: time.Minute,
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+
+	gitChecker.Snapshots[auth.WorktreePath] = "t1"
+	if session, _ := manager.DeriveSession(auth.ID); session.ChangedSincePublish {
+		t.Error("a session never published has nothing new since")
+	}
+
+	publish, err := manager.RecordPublish(auth.ID, "c1", "")
+	if err != nil || publish.Revision != 1 || publish.Tree != "t1" {
+		t.Fatalf("RecordPublish() = %+v, %v", publish, err)
+	}
+	if session, _ := manager.DeriveSession(auth.ID); session.ChangedSincePublish {
+		t.Error("nothing changed since the push")
+	}
+
+	// Claude keeps working, and the change is flagged despite the cache
+	gitChecker.Snapshots[auth.WorktreePath] = "t2"
+	manager.InvalidateStatus(auth.ID)
+	if session, _ := manager.DeriveSession(auth.ID); !session.ChangedSincePublish || session.ChangedSinceReview {
+		t.Error("changes made since the push should be flagged, and only those")
+	}
+
+	publish, err = manager.RecordPublish(auth.ID, "c2", "https://github.com/o/r/pull/12")
+	if err != nil || publish.Revision != 2 {
+		t.Fatalf("RecordPublish() = %+v, %v", publish, err)
+	}
+	session, _ := manager.DeriveSession(auth.ID)
+	if session.ChangedSincePublish {
+		t.Error("publishing again should clear the flag")
+	}
+	if len(session.Core.Publishes) != 2 || session.Core.LastPublish().Commit != "c2" ||
+		session.Core.LastPublish().PullRequest != "https://github.com/o/r/pull/12" {
+		t.Errorf("publishes = %+v", session.Core.Publishes)
+	}
+}
@@ -0,0 +1,106 @@
+package state
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/forge"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// PullRequestCheckInterval is how long the status of an open pull request
+// is trusted before its forge is asked again
+const PullRequestCheckInterval = 2 * time.Minute
+
+// SetPullRequest records the pull request a session was published to.
+// Nil forgets it.
+func (m *Manager) SetPullRequest(sessionID string, pr *types.PullRequest) error {
+	return m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.PullRequest = pr
+	})
+}
+
+// RefreshPullRequests asks the forges where the open pull requests of the
+// sessions stand, when last asked more than maxAge ago, and returns how
+// many changed. Only one refresh runs at a time; others return 0 at once.
+func (m *Manager) RefreshPullRequests(maxAge time.Duration) int {
+	if !m.pullRequestMu.TryLock() {
+		return 0
+	}
+	defer m.pullRequestMu.Unlock()
+
+	cores, err := m.CoreSessions()
+	if err != nil {
+		logger.Warn("failed to load sessions to check pull requests", "error", err)
+		return 0
+	}
+
+	// Forges are network services, so all pull requests are asked at once
+	now := m.Now()
+	var changed atomic.Int32
+	var wg sync.WaitGroup
+	for _, core := range cores {
+		if core.PullRequest == nil || !core.PullRequest.Due(now, maxAge) {
+			continue
+		}
+		wg.Add(1)
+		go func(core types.CoreSession) {
+			defer wg.Done()
+			if m.refreshPullRequest(core) {
+				changed.Add(1)
+			}
+		}(core)
+	}
+	wg.Wait()
+	return int(changed.Load())
+}
+
+// refreshPullRequest asks the forge about a session's pull request and
+// records the answer, reporting whether its status changed
+func (m *Manager) refreshPullRequest(core types.CoreSession) bool {
+	url := core.PullRequest.URL
+	var status types.PullRequestStatus
+	host, err := m.forgeFor(core.WorktreePath)
+	if err == nil {
+		status, err = host.PullRequestStatus(core.WorktreePath, url)
+	}
+	if err != nil {
+		logger.Debug("failed to check pull request", "session", core.Name, "url", url, "error", err)
+	}
+
+	changed := false
+	checked := m.Now()
+	m.UpdateSession(core.ID, func(core *types.CoreSession) {
+		// The session may have been published to another pull request since
+		if core.PullRequest == nil || core.PullRequest.URL != url {
+			return
+		}
+		pr := *core.PullRequest
+		pr.CheckedAt = &checked
+		if err != nil {
+			pr.Error = err.Error()
+		} else {
+			changed = pr.Status != status
+			pr.Status, pr.Error = status, ""
+		}
+		core.PullRequest = &pr
+	})
+	return changed
+}
+
+// forgeFor returns the forge hosting the repository checked out in a
+// worktree
+func (m *Manager) forgeFor(worktreePath string) (forge.Forge, error) {
+	if m.config.Forges != nil {
+		return m.config.Forges(worktreePath)
+	}
+	host, err := forge.ForRepository(worktreePath, m.config.Forge)
+	if err != nil {
+		return nil, err
+	}
+	if err := host.Available(); err != nil {
+		return nil, err
+	}
+	return host, nil
+}
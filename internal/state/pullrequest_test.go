@@ -0,0 +1,90 @@
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/forge"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// fakeForge answers pull request status from a map of URLs
+type fakeForge struct {
+	statuses map[string]types.PullRequestStatus
+	asked    int
+}
+
+func (f *fakeForge) Name() string     { return "Fake" }
+func (f *fakeForge) Available() error { return nil }
+func (f *fakeForge) CreatePullRequest(dir string, pr forge.PullRequest) (string, error) {
+	return "", errors.New("not supported")
+}
+func (f *fakeForge) PullRequestURL(dir, branch string) (string, error) {
+	return "", errors.New("not supported")
+}
+func (f *fakeForge) PullRequestStatus(dir, url string) (types.PullRequestStatus, error) {
+	f.asked++
+	status, ok := f.statuses[url]
+	if !ok {
+		return status, errors.New("not found")
+	}
+	return status, nil
+}
+
+func TestManager_RefreshPullRequests(t *testing.T) {
+	const url = "https://github.com/acme/widgets/pull/12"
+	host := &fakeForge{statuses: map[string]types.PullRequestStatus{
+		url: {State: types.PullRequestOpen, Review: types.ReviewApproved, Checks: types.ChecksPassing},
+	}}
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		Forges:        func(string) (forge.Forge, error) { return host, nil },
+	})
+	defer manager.Close()
+
+	manager.CreateSession("auth")
+	manager.CreateSession("other")
+	cores, _ := manager.CoreSessions()
+	if err := manager.SetPullRequest(cores[0].ID, types.NewPullRequest(url)); err != nil {
+		t.Fatalf("SetPullRequest() error = %v", err)
+	}
+
+	if changed := manager.RefreshPullRequests(time.Minute); changed != 1 || host.asked != 1 {
+		t.Fatalf("RefreshPullRequests() = %d after %d question(s), want the one pull request changed", changed, host.asked)
+	}
+	cores, _ = manager.CoreSessions()
+	pr := cores[0].PullRequest
+	if pr.Number != 12 || pr.Status != host.statuses[url] || pr.CheckedAt == nil {
+		t.Errorf("pull request = %+v, want the forge's status recorded", pr)
+	}
+
+	// A status checked lately is trusted
+	if changed := manager.RefreshPullRequests(time.Minute); changed != 0 || host.asked != 1 {
+		t.Errorf("RefreshPullRequests() asked the forge again: %d time(s)", host.asked)
+	}
+
+	// Failing to ask keeps the last status known
+	delete(host.statuses, url)
+	manager.RefreshPullRequests(0)
+	cores, _ = manager.CoreSessions()
+	if pr := cores[0].PullRequest; pr.Error == "" || pr.Status.Review != types.ReviewApproved {
+		t.Errorf("pull request = %+v, want the error next to the last status", pr)
+	}
+
+	// A merged pull request is left alone
+	host.statuses[url] = types.PullRequestStatus{State: types.PullRequestMerged}
+	manager.RefreshPullRequests(0)
+	asked := host.asked
+	manager.RefreshPullRequests(0)
+	if host.asked != asked {
+		t.Error("RefreshPullRequests() should stop asking about a merged pull request")
+	}
+}
@@ -0,0 +1,119 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// UpdateResult describes how a session's branch was brought up to date
+// with a rebase or merge
+type UpdateResult struct {
+	Onto    string // Branch or commit the session's branch was rebased onto or merged with
+	Behind  int    // Commits of the base branch the session's branch was missing
+	Commits int    // Commits of the session's branch
+	Updated bool   // Whether the branch moved; false when it already had onto's commits
+}
+
+// RebaseSession rebases a session's branch onto onto, or the base branch
+// when onto is empty, in the session's worktree. Uncommitted changes would
+// get in the way, so the worktree must be clean. When conflicts stop the
+// rebase it is left in progress and a *git.RebaseConflictError is returned;
+// resolve it in the worktree or undo it with AbortRebase.
+func (m *Manager) RebaseSession(sessionID, onto string) (UpdateResult, error) {
+	return m.updateSession(sessionID, onto, "rebase", m.config.GitChecker.Rebase,
+		types.EventRebased, "Rebased onto %s")
+}
+
+// MergeIntoSession merges from, or the base branch when from is empty, into
+// a session's branch in its worktree, like RebaseSession but keeping the
+// branch's commits as they are. When conflicts stop the merge it is left in
+// progress and a *git.MergeConflictError is returned; resolve it in the
+// worktree or undo it with AbortMerge.
+func (m *Manager) MergeIntoSession(sessionID, from string) (UpdateResult, error) {
+	return m.updateSession(sessionID, from, "merge", m.config.GitChecker.MergeInto,
+		types.EventMergedBase, "Merged %s")
+}
+
+// updateSession brings a session's branch up to date with onto using
+// update, recording event when it succeeds
+func (m *Manager) updateSession(sessionID, onto, verb string, update func(worktreePath, onto string) error, event, message string) (UpdateResult, error) {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return UpdateResult{}, err
+	}
+	if onto == "" {
+		onto = m.config.BaseBranch
+	}
+
+	m.InvalidateStatus(sessionID)
+	session := m.deriveSession(core)
+	status := session.GitStatus
+	result := UpdateResult{Onto: onto, Commits: status.CommitCount}
+	if onto == m.config.BaseBranch {
+		result.Behind = status.BehindCount
+	}
+
+	switch {
+	case status.HasError():
+		return result, fmt.Errorf("can't %s session '%s': %s", verb, core.Name, status.Error)
+	case status.HasConflicts():
+		return result, fmt.Errorf("session '%s' has unresolved conflicts; resolve or abort them in its worktree first", core.Name)
+	case len(status.StagedFiles) > 0 || len(status.UnstagedFiles) > 0:
+		return result, fmt.Errorf("session '%s' has uncommitted changes; commit or stash them first", core.Name)
+	}
+
+	logger.Info("updating session branch", "session", core.Name, "strategy", verb, "onto", onto)
+	before, _ := m.config.GitChecker.ResolveCommit(session.BranchName())
+	err = update(core.WorktreePath, onto)
+	m.InvalidateStatus(sessionID)
+	if err != nil {
+		if errors.As(err, new(*git.RebaseConflictError)) || errors.As(err, new(*git.MergeConflictError)) {
+			m.eventBus.Publish(types.SessionUpdated{Session: m.deriveSession(core), Previous: core})
+		}
+		return result, err
+	}
+
+	after, _ := m.config.GitChecker.ResolveCommit(session.BranchName())
+	result.Updated = before == "" || after != before
+	if !result.Updated {
+		return result, nil
+	}
+	m.RecordEvent(sessionID, event, fmt.Sprintf(message, onto), map[string]interface{}{
+		"behind":  result.Behind,
+		"commits": result.Commits,
+	})
+	m.eventBus.Publish(types.SessionUpdated{Session: m.deriveSession(core), Previous: core})
+
+	return result, nil
+}
+
+// AbortRebase abandons a rebase stopped on conflicts in a session's
+// worktree, putting its branch back where it was before the rebase
+func (m *Manager) AbortRebase(sessionID string) error {
+	return m.abortUpdate(sessionID, "rebase", m.config.GitChecker.AbortRebase)
+}
+
+// AbortMerge abandons a merge stopped on conflicts in a session's worktree,
+// putting its branch back where it was before the merge
+func (m *Manager) AbortMerge(sessionID string) error {
+	return m.abortUpdate(sessionID, "merge", m.config.GitChecker.AbortMerge)
+}
+
+// abortUpdate abandons a rebase or merge in a session's worktree with abort
+func (m *Manager) abortUpdate(sessionID, verb string, abort func(worktreePath string) error) error {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	err = abort(core.WorktreePath)
+	m.InvalidateStatus(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to abort %s of session '%s': %w", verb, core.Name, err)
+	}
+	m.eventBus.Publish(types.SessionUpdated{Session: m.deriveSession(core), Previous: core})
+	return nil
+}
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_RebaseSession(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+		BaseBranch:    "main",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+
+	gitChecker.SetStatus(auth.WorktreePath, types.GitStatus{HasChanges: true, UnstagedFiles: []string{"login.go"}, BehindCount: 3})
+	if _, err := manager.RebaseSession(auth.ID, ""); err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Errorf("RebaseSession() = %v, want uncommitted changes refused", err)
+	}
+	if _, rebased := gitChecker.Rebased[auth.WorktreePath]; rebased {
+		t.Error("a worktree with uncommitted changes was rebased")
+	}
+
+	gitChecker.SetStatus(auth.WorktreePath, types.GitStatus{BehindCount: 3, CommitCount: 2})
+	result, err := manager.RebaseSession(auth.ID, "")
+	if err != nil {
+		t.Fatalf("RebaseSession() error = %v", err)
+	}
+	if result.Onto != "main" || result.Behind != 3 || result.Commits != 2 {
+		t.Errorf("RebaseSession() = %+v, want 2 commits rebased onto main, 3 behind", result)
+	}
+	if gitChecker.Rebased[auth.WorktreePath] != "main" {
+		t.Errorf("rebased onto %q, want main", gitChecker.Rebased[auth.WorktreePath])
+	}
+	events, _ := manager.Timeline(auth.ID)
+	if last := events[len(events)-1]; last.Type != types.EventRebased || last.Message != "Rebased onto main" {
+		t.Errorf("last event = %+v, want the rebase recorded", last)
+	}
+
+	// Conflicts leave the rebase in progress to be resolved or aborted
+	gitChecker.RebaseFails[auth.WorktreePath] = []string{"login.go"}
+	_, err = manager.RebaseSession(auth.ID, "develop")
+	var conflictErr *git.RebaseConflictError
+	if !errors.As(err, &conflictErr) || conflictErr.Onto != "develop" || len(conflictErr.Files) != 1 {
+		t.Fatalf("RebaseSession() = %v, want conflicts in login.go rebasing onto develop", err)
+	}
+	if err := manager.AbortRebase(auth.ID); err != nil {
+		t.Fatalf("AbortRebase() error = %v", err)
+	}
+	if len(gitChecker.Aborted) != 1 || gitChecker.Aborted[0] != auth.WorktreePath {
+		t.Errorf("aborted %v, want the session's worktree", gitChecker.Aborted)
+	}
+
+	if _, err := manager.RebaseSession("missing", ""); err == nil {
+		t.Error("RebaseSession() of a missing session should fail")
+	}
+}
@@ -0,0 +1,243 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// DriftKind is a way sessions.json and the resources it describes disagree
+type DriftKind string
+
+const (
+	DriftDeadTmux         DriftKind = "dead_tmux"         // A session's tmux session isn't running, though it isn't paused
+	DriftMissingWorktree  DriftKind = "missing_worktree"  // A session's worktree is gone or no longer a git worktree
+	DriftMissingBranch    DriftKind = "missing_branch"    // A session's worktree and branch are both gone
+	DriftOrphanedTmux     DriftKind = "orphaned_tmux"     // A cwt tmux session no session owns
+	DriftOrphanedWorktree DriftKind = "orphaned_worktree" // A worktree directory no session owns
+)
+
+// Discrepancy is one thing found wrong by Reconcile
+type Discrepancy struct {
+	Kind      DriftKind
+	SessionID string // Session it concerns; empty for orphaned resources
+	Session   string // Name of that session
+	Resource  string // The tmux session, worktree or branch concerned
+	Fix       string // What FixDiscrepancy does about it; empty when it has to be fixed by hand
+}
+
+// Describe says what is wrong, in a line
+func (d Discrepancy) Describe() string {
+	switch d.Kind {
+	case DriftDeadTmux:
+		return fmt.Sprintf("%s: tmux session %s is not running", d.Session, d.Resource)
+	case DriftMissingWorktree:
+		return fmt.Sprintf("%s: worktree %s is missing", d.Session, d.Resource)
+	case DriftMissingBranch:
+		return fmt.Sprintf("%s: worktree and branch %s are both gone", d.Session, d.Resource)
+	case DriftOrphanedTmux:
+		return fmt.Sprintf("tmux session %s belongs to no session", d.Resource)
+	case DriftOrphanedWorktree:
+		if d.Fix == "" {
+			return fmt.Sprintf("worktree %s belongs to no session and has uncommitted changes", d.Resource)
+		}
+		return fmt.Sprintf("worktree %s belongs to no session", d.Resource)
+	}
+	return fmt.Sprintf("%s: %s", d.Kind, d.Resource)
+}
+
+// ReconcileReport lists the discrepancies between sessions.json and the
+// tmux sessions, worktrees and branches it describes
+type ReconcileReport struct {
+	Discrepancies []Discrepancy
+}
+
+// Empty reports whether everything matched
+func (r ReconcileReport) Empty() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// Fixable returns the discrepancies FixDiscrepancy can fix, optionally only
+// those of some kinds
+func (r ReconcileReport) Fixable(kinds ...DriftKind) []Discrepancy {
+	var fixable []Discrepancy
+	for _, d := range r.Discrepancies {
+		if d.Fix == "" || (len(kinds) > 0 && !slices.Contains(kinds, d.Kind)) {
+			continue
+		}
+		fixable = append(fixable, d)
+	}
+	return fixable
+}
+
+// Reconcile compares the sessions in sessions.json with the tmux sessions,
+// worktrees and branches that actually exist, so drift, like a tmux server
+// that was restarted or a worktree deleted by hand, is found in one pass
+// instead of piecemeal. Paused sessions are stopped on purpose, and
+// directories a repair moved aside are kept on purpose, so neither is
+// reported.
+func (m *Manager) Reconcile() (ReconcileReport, error) {
+	sessions, err := m.DeriveFreshSessions()
+	if err != nil {
+		return ReconcileReport{}, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var report ReconcileReport
+	ownedTmux := make(map[string]bool)
+	ownedWorktrees := make(map[string]bool)
+	for _, session := range sessions {
+		core := session.Core
+		ownedTmux[core.TmuxSession] = true
+		ownedWorktrees[filepath.Base(core.WorktreePath)] = true
+
+		switch {
+		case session.GitStatus.WorktreeBroken():
+			branch := session.BranchName()
+			if m.config.GitChecker.BranchExists(branch) {
+				report.add(Discrepancy{Kind: DriftMissingWorktree, SessionID: core.ID, Session: core.Name,
+					Resource: core.WorktreePath, Fix: "recreate the worktree from " + branch})
+			} else {
+				report.add(Discrepancy{Kind: DriftMissingBranch, SessionID: core.ID, Session: core.Name,
+					Resource: branch, Fix: "delete the session, moving what is left of its worktree aside"})
+			}
+		case !session.IsAlive && !core.IsPaused():
+			report.add(Discrepancy{Kind: DriftDeadTmux, SessionID: core.ID, Session: core.Name,
+				Resource: core.TmuxSession, Fix: "restart it, resuming Claude's conversation"})
+		}
+	}
+
+	tmuxSessions, err := m.config.TmuxChecker.ListSessions()
+	if err != nil {
+		logger.Debug("failed to list tmux sessions", "error", err)
+	}
+	for _, name := range tmuxSessions {
+		if strings.HasPrefix(name, "cwt-") && !ownedTmux[name] {
+			report.add(Discrepancy{Kind: DriftOrphanedTmux, Resource: name, Fix: "kill it"})
+		}
+	}
+
+	worktreesDir := filepath.Join(m.config.DataDir, "worktrees")
+	entries, err := os.ReadDir(worktreesDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return report, fmt.Errorf("failed to read worktrees directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || ownedWorktrees[entry.Name()] || strings.Contains(entry.Name(), ".broken-") {
+			continue
+		}
+		path := filepath.Join(worktreesDir, entry.Name())
+		d := Discrepancy{Kind: DriftOrphanedWorktree, Resource: path, Fix: "remove it"}
+		// Its changes would be lost; leave those to be looked at
+		if status, err := m.config.GitChecker.GetStatus(path); err == nil && status.HasChanges {
+			d.Fix = ""
+		}
+		report.add(d)
+	}
+
+	sort.SliceStable(report.Discrepancies, func(i, j int) bool {
+		return report.Discrepancies[i].Session < report.Discrepancies[j].Session
+	})
+	return report, nil
+}
+
+func (r *ReconcileReport) add(d Discrepancy) {
+	r.Discrepancies = append(r.Discrepancies, d)
+}
+
+// FixDiscrepancy fixes what Reconcile found, as its Fix says: restarting a
+// dead session, recreating a missing worktree, deleting a session whose
+// worktree and branch are both gone, or removing an orphaned resource
+func (m *Manager) FixDiscrepancy(d Discrepancy) error {
+	if d.Fix == "" {
+		return fmt.Errorf("%s has to be fixed by hand", d.Describe())
+	}
+	logger.Info("fixing discrepancy", "kind", d.Kind, "session", d.Session, "resource", d.Resource)
+
+	switch d.Kind {
+	case DriftDeadTmux:
+		return m.RestartSession(d.SessionID)
+	case DriftMissingWorktree:
+		_, err := m.RepairWorktree(d.SessionID)
+		return err
+	case DriftMissingBranch:
+		return m.dropBrokenSession(d.SessionID)
+	case DriftOrphanedTmux:
+		return m.config.TmuxChecker.KillSession(d.Resource)
+	case DriftOrphanedWorktree:
+		if err := m.config.GitChecker.RemoveWorktree(d.Resource); err != nil {
+			// Not a worktree git knows of any more, just a directory
+			logger.Debug("git failed to remove orphaned worktree", "path", d.Resource, "error", err)
+		}
+		return os.RemoveAll(d.Resource)
+	}
+	return fmt.Errorf("unknown discrepancy %q", d.Kind)
+}
+
+// dropBrokenSession deletes a session whose worktree and branch are both
+// gone. Whatever is left of the worktree's directory is moved aside, as a
+// repair would, since it may hold work that was never committed.
+func (m *Manager) dropBrokenSession(sessionID string) error {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(core.WorktreePath); err == nil {
+		removeClaudeSettings(core.WorktreePath)
+		aside := fmt.Sprintf("%s.broken-%s", core.WorktreePath, m.Now().Format("20060102-150405"))
+		if err := os.Rename(core.WorktreePath, aside); err != nil {
+			return fmt.Errorf("failed to move broken worktree aside: %w", err)
+		}
+	}
+	// There is no branch to keep, nor a worktree for hooks to run in
+	_, err = m.DeleteSessionWithOptions(sessionID, DeleteOptions{Force: true, KeepBranch: true, IgnoreHooks: true})
+	return err
+}
+
+// FixDiscrepancies fixes each discrepancy in turn, returning how many it
+// fixed and what went wrong with the others
+func (m *Manager) FixDiscrepancies(discrepancies []Discrepancy) (int, error) {
+	var fixed int
+	var errs []error
+	for _, d := range discrepancies {
+		if err := m.FixDiscrepancy(d); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.Describe(), err))
+			continue
+		}
+		fixed++
+	}
+	m.InvalidateStatus("")
+	return fixed, errors.Join(errs...)
+}
+
+// RestartSession starts the tmux session of a session that isn't running
+// again, say after the tmux server was restarted, resuming Claude's
+// conversation when there is one. Paused sessions are resumed with
+// ResumeSession instead.
+func (m *Manager) RestartSession(sessionID string) error {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if core.IsPaused() {
+		return fmt.Errorf("session '%s' is paused; resume it instead", core.Name)
+	}
+	if m.config.TmuxChecker.IsSessionAlive(core.TmuxSession) {
+		return fmt.Errorf("tmux session '%s' is already running", core.TmuxSession)
+	}
+
+	command := m.AgentLaunch(core, m.conversationID(core), "").Command
+	if err := m.config.TmuxChecker.CreateSession(core.TmuxSession, core.WorktreePath, command, m.SessionEnv(core)...); err != nil {
+		return fmt.Errorf("failed to recreate tmux session: %w", err)
+	}
+
+	m.installExitHookOrWarn(core)
+	types.RemoveSessionExit(m.config.DataDir, sessionID)
+	m.InvalidateStatus(sessionID)
+	return nil
+}
@@ -0,0 +1,107 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_Reconcile(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+	tmuxChecker := tmux.NewMockChecker()
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:          dataDir,
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       gitChecker,
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+	})
+	defer manager.Close()
+
+	for _, name := range []string{"auth", "docs", "gone", "healthy", "paused"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+	ids := make(map[string]types.CoreSession)
+	cores, _ := manager.CoreSessions()
+	for _, core := range cores {
+		ids[core.Name] = core
+	}
+
+	tmuxChecker.SetAlive(ids["auth"].TmuxSession, false)
+	for _, name := range []string{"docs", "gone"} {
+		path := ids[name].WorktreePath
+		gitChecker.SetStatusError(path, &git.StatusError{Kind: types.GitErrorNotRepository, Path: path})
+	}
+	gitChecker.Existing["docs"] = true
+	if err := manager.PauseSession(ids["paused"].ID); err != nil {
+		t.Fatalf("PauseSession() error = %v", err)
+	}
+	tmuxChecker.SetAlive("cwt-old", true)
+	tmuxChecker.SetAlive("dev", true)
+	for _, dir := range []string{"stray", "dirty", "auth.broken-20250101-120000"} {
+		if err := os.MkdirAll(filepath.Join(dataDir, "worktrees", dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirty := filepath.Join(dataDir, "worktrees", "dirty")
+	gitChecker.SetStatus(dirty, types.GitStatus{HasChanges: true})
+	manager.InvalidateStatus("")
+
+	report, err := manager.Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	found := make(map[DriftKind][]string)
+	for _, d := range report.Discrepancies {
+		found[d.Kind] = append(found[d.Kind], d.Session+d.Resource)
+	}
+	want := map[DriftKind]int{
+		DriftDeadTmux:         1,
+		DriftMissingWorktree:  1,
+		DriftMissingBranch:    1,
+		DriftOrphanedTmux:     1,
+		DriftOrphanedWorktree: 2,
+	}
+	for kind, n := range want {
+		if len(found[kind]) != n {
+			t.Errorf("%s: found %v, want %d", kind, found[kind], n)
+		}
+	}
+	if len(report.Discrepancies) != 6 {
+		t.Errorf("unexpected discrepancies: %+v", report.Discrepancies)
+	}
+	if got := report.Fixable(DriftOrphanedWorktree); len(got) != 1 || got[0].Resource == dirty {
+		t.Errorf("a worktree with changes should be left to fix by hand, got %+v", got)
+	}
+
+	fixed, err := manager.FixDiscrepancies(report.Fixable())
+	if err != nil || fixed != 5 {
+		t.Fatalf("FixDiscrepancies() = %d, %v", fixed, err)
+	}
+	if !tmuxChecker.AliveSessions[ids["auth"].TmuxSession] ||
+		tmuxChecker.SessionCommands[ids["auth"].TmuxSession] != "claude -r mock-session-auth" {
+		t.Error("the dead session should be restarted, resuming its conversation")
+	}
+	if tmuxChecker.AliveSessions["cwt-old"] || !tmuxChecker.AliveSessions["dev"] {
+		t.Error("only the orphaned cwt tmux session should be killed")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "worktrees", "stray")); err == nil {
+		t.Error("the orphaned worktree should be removed")
+	}
+
+	report, err = manager.Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].Resource != dirty {
+		t.Errorf("only the dirty worktree should be left, got %+v", report.Discrepancies)
+	}
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RefreshFileName is the broadcast file written after CLI mutations so that
+// running TUIs refresh immediately instead of waiting for the next poll
+const RefreshFileName = "refresh.json"
+
+// RefreshSignal describes what changed in the last CLI mutation
+type RefreshSignal struct {
+	Session string    `json:"session,omitempty"` // Session name; empty means refresh everything
+	Reason  string    `json:"reason"`            // Command that triggered the refresh (e.g. "merge")
+	Time    time.Time `json:"time"`
+}
+
+// WriteRefreshSignal atomically writes the refresh broadcast file, stamped
+// with the signal's Time
+func WriteRefreshSignal(dataDir string, signal RefreshSignal) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.Marshal(signal)
+	if err != nil {
+		return fmt.Errorf("failed to marshal refresh signal: %w", err)
+	}
+
+	refreshFile := filepath.Join(dataDir, RefreshFileName)
+	tempFile := refreshFile + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write refresh signal: %w", err)
+	}
+
+	if err := os.Rename(tempFile, refreshFile); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename refresh signal: %w", err)
+	}
+
+	return nil
+}
+
+// ReadRefreshSignal reads the last refresh broadcast, returning nil if none exists
+func ReadRefreshSignal(dataDir string) (*RefreshSignal, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, RefreshFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read refresh signal: %w", err)
+	}
+
+	var signal RefreshSignal
+	if err := json.Unmarshal(data, &signal); err != nil {
+		return nil, fmt.Errorf("refresh signal corrupted: %w", err)
+	}
+
+	return &signal, nil
+}
+
+// NotifyRefresh broadcasts that a session (or everything, if sessionName is
+// empty) changed and drops its cached status. Failures are ignored since
+// refresh is best-effort.
+func (m *Manager) NotifyRefresh(sessionName, reason string) {
+	m.invalidateStatusByName(sessionName)
+
+	WriteRefreshSignal(m.config.DataDir, RefreshSignal{
+		Session: sessionName,
+		Reason:  reason,
+		Time:    m.Now(),
+	})
+}
+
+// invalidateStatusByName drops cached status for the named session, or for
+// every session if name is empty
+func (m *Manager) invalidateStatusByName(name string) {
+	if name == "" {
+		m.InvalidateStatus("")
+		return
+	}
+
+	m.mu.RLock()
+	cores, err := m.loadCoreSessions()
+	m.mu.RUnlock()
+	if err != nil {
+		m.InvalidateStatus("")
+		return
+	}
+
+	for _, core := range cores {
+		if core.Name == name {
+			m.InvalidateStatus(core.ID)
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRefreshSignalRoundTrip(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".cwt")
+
+	signal, err := ReadRefreshSignal(dataDir)
+	if err != nil {
+		t.Fatalf("ReadRefreshSignal on missing file failed: %v", err)
+	}
+	if signal != nil {
+		t.Fatalf("Expected nil signal when no file exists, got %+v", signal)
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := WriteRefreshSignal(dataDir, RefreshSignal{Session: "feature", Reason: "merge", Time: now}); err != nil {
+		t.Fatalf("WriteRefreshSignal failed: %v", err)
+	}
+
+	signal, err = ReadRefreshSignal(dataDir)
+	if err != nil {
+		t.Fatalf("ReadRefreshSignal failed: %v", err)
+	}
+	if signal == nil || signal.Session != "feature" || signal.Reason != "merge" {
+		t.Errorf("Unexpected signal: %+v", signal)
+	}
+	if !signal.Time.Equal(now) {
+		t.Errorf("signal time = %v, want %v", signal.Time, now)
+	}
+}
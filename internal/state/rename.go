@@ -0,0 +1,142 @@
+package state
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// RenameSession renames a session everywhere its name is used: its git
+// branch, tmux session and stored metadata. The steps are undone in reverse
+// if any of them fails, leaving the session as it was.
+//
+// The worktree stays where it is: Claude files its conversations under the
+// worktree's path, so moving it would lose them, and a running Claude would
+// be left in a directory that is gone.
+func (m *Manager) RenameSession(sessionID, newName string) error {
+	if err := validateSessionName(newName); err != nil {
+		return fmt.Errorf("invalid session name: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	index := -1
+	for i, core := range cores {
+		if core.ID == sessionID {
+			index = i
+		} else if core.Name == newName {
+			return fmt.Errorf("session with name '%s' already exists", newName)
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("session with ID %s not found", sessionID)
+	}
+
+	previous := cores[index]
+	if previous.Name == newName {
+		return nil
+	}
+	renamed := previous
+	renamed.Name = newName
+	renamed.TmuxSession = fmt.Sprintf("cwt-%s", newName)
+
+	var undo []func()
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+
+	// A branch that is gone (or was never created) is simply not renamed
+	branches, err := m.config.GitChecker.ListBranches()
+	if err != nil {
+		return err
+	}
+	branch, newBranch := m.sessionBranch(previous, newName)
+	if slices.Contains(branches, branch) {
+		if slices.Contains(branches, newBranch) {
+			return fmt.Errorf("branch '%s' already exists", newBranch)
+		}
+		if err := m.config.GitChecker.RenameBranch(branch, newBranch); err != nil {
+			return err
+		}
+		undo = append(undo, func() { m.config.GitChecker.RenameBranch(newBranch, branch) })
+	}
+
+	alive := m.config.TmuxChecker.IsSessionAlive(previous.TmuxSession)
+	if alive {
+		if err := m.config.TmuxChecker.RenameSession(previous.TmuxSession, renamed.TmuxSession); err != nil {
+			rollback()
+			return err
+		}
+		undo = append(undo, func() { m.config.TmuxChecker.RenameSession(renamed.TmuxSession, previous.TmuxSession) })
+	}
+
+	if err := m.replaceCoreSession(renamed); err != nil {
+		rollback()
+		return fmt.Errorf("failed to save updated sessions: %w", err)
+	}
+
+	if alive {
+		// The hooks name the session, so they need updating
+		m.installExitHookOrWarn(renamed)
+	}
+
+	m.invalidateProvider(sessionID)
+	m.RecordEvent(sessionID, types.EventRenamed, fmt.Sprintf("Renamed from %s", previous.Name), map[string]interface{}{
+		"previous_name": previous.Name,
+	})
+
+	m.eventBus.Publish(types.SessionUpdated{
+		Session:  m.deriveSession(renamed),
+		Previous: previous,
+	})
+
+	return nil
+}
+
+// replaceCoreSession saves a changed session over the stored one with its
+// ID, keeping the sessions other processes changed since it was loaded
+func (m *Manager) replaceCoreSession(changed types.CoreSession) error {
+	lock, err := m.lockSessionsFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	cores, err := m.loadCoreSessions()
+	if err != nil {
+		return err
+	}
+	index := -1
+	for i, core := range cores {
+		if core.ID == changed.ID {
+			index = i
+		} else if core.Name == changed.Name {
+			return fmt.Errorf("session with name '%s' already exists", changed.Name)
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("session with ID %s not found", changed.ID)
+	}
+	cores[index] = changed
+	return m.saveCoreSessions(cores)
+}
+
+// sessionBranch returns the branch of a session and what it becomes when
+// the session is renamed. Sessions are created on a branch named after them;
+// a "cwt-" prefixed branch keeps its prefix.
+func (m *Manager) sessionBranch(core types.CoreSession, newName string) (branch, newBranch string) {
+	current, err := m.config.GitChecker.GetCurrentBranch(core.WorktreePath)
+	if err == nil && current == "cwt-"+core.Name {
+		return current, "cwt-" + newName
+	}
+	return core.Name, newName
+}
@@ -0,0 +1,131 @@
+package state
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_RenameSession(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	for _, name := range []string{"auth", "taken"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession(%q) error = %v", name, err)
+		}
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+	gitChecker.Branches[auth.WorktreePath] = "auth"
+
+	if err := manager.RenameSession(auth.ID, "taken"); err == nil {
+		t.Error("renaming to an existing session's name should fail")
+	}
+	if err := manager.RenameSession(auth.ID, "bad name"); err == nil {
+		t.Error("renaming to an invalid name should fail")
+	}
+
+	if err := manager.RenameSession(auth.ID, "login"); err != nil {
+		t.Fatalf("RenameSession() error = %v", err)
+	}
+
+	cores, _ = manager.CoreSessions()
+	renamed := cores[0]
+	if renamed.ID != auth.ID || renamed.Name != "login" || renamed.TmuxSession != "cwt-login" {
+		t.Fatalf("renamed session = %+v", renamed)
+	}
+	if gitChecker.Renamed["auth"] != "login" {
+		t.Errorf("renamed branches = %v, want auth renamed to login", gitChecker.Renamed)
+	}
+	// Claude's conversations are filed under the worktree's path
+	if renamed.WorktreePath != auth.WorktreePath || !gitChecker.Worktrees[auth.WorktreePath] {
+		t.Errorf("worktree = %s, want it kept at %s", renamed.WorktreePath, auth.WorktreePath)
+	}
+	if !tmuxChecker.AliveSessions["cwt-login"] || tmuxChecker.AliveSessions["cwt-auth"] {
+		t.Errorf("alive tmux sessions = %v, want cwt-login instead of cwt-auth", tmuxChecker.AliveSessions)
+	}
+	if hook := tmuxChecker.ExitHooks["cwt-login"]; !strings.Contains(hook, "'cwt-login'") {
+		t.Errorf("exit hook = %q, want it to name the renamed tmux session", hook)
+	}
+
+	// The old name is free again, though its directory isn't
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() of the old name error = %v", err)
+	}
+	cores, _ = manager.CoreSessions()
+	if fresh := cores[len(cores)-1]; fresh.Name != "auth" || fresh.WorktreePath != auth.WorktreePath+"-2" {
+		t.Errorf("new session = %+v, want its worktree beside the renamed one's", fresh)
+	}
+}
+
+func TestManager_RenameSession_Rollback(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+	gitChecker.Branches[auth.WorktreePath] = "auth"
+
+	tmuxChecker.ShouldFailRename = true
+	if err := manager.RenameSession(auth.ID, "login"); err == nil {
+		t.Fatal("RenameSession() should fail when tmux can't rename the session")
+	}
+
+	cores, _ = manager.CoreSessions()
+	if !reflect.DeepEqual(cores[0], auth) {
+		t.Errorf("session = %+v, want it unchanged", cores[0])
+	}
+	if branch := gitChecker.Branches[auth.WorktreePath]; branch != "auth" {
+		t.Errorf("worktree %s has branch %q, want the rename undone", auth.WorktreePath, branch)
+	}
+	if !gitChecker.Worktrees[auth.WorktreePath] {
+		t.Error("the worktree should be kept")
+	}
+}
+
+func TestManager_RenameSession_PrefixedBranch(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	gitChecker.Branches[cores[0].WorktreePath] = "cwt-auth"
+
+	if err := manager.RenameSession(cores[0].ID, "login"); err != nil {
+		t.Fatalf("RenameSession() error = %v", err)
+	}
+	if gitChecker.Renamed["cwt-auth"] != "cwt-login" {
+		t.Errorf("renamed branches = %v, want cwt-auth renamed to cwt-login", gitChecker.Renamed)
+	}
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/agent"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// RepairResult describes how a session's worktree was repaired
+type RepairResult struct {
+	Branch     string // Branch the worktree was recreated from
+	MovedAside string // Where the broken worktree directory was moved, if it was still there
+	Restarted  bool   // Whether the session's tmux session was restarted in the new worktree
+}
+
+// RepairWorktree recreates the worktree of a session whose directory went
+// missing or stopped being a git worktree, say after it was deleted by hand
+// or restored from a backup without its .git link, by checking the session's
+// branch out again. A leftover directory is moved aside rather than deleted,
+// since it may hold work that was never committed. A running tmux session
+// is stuck in the old directory, so it is restarted in the new one.
+func (m *Manager) RepairWorktree(sessionID string) (RepairResult, error) {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return RepairResult{}, err
+	}
+
+	m.InvalidateStatus(sessionID)
+	session := m.deriveSession(core)
+	if !session.GitStatus.WorktreeBroken() {
+		return RepairResult{}, fmt.Errorf("worktree of session '%s' is not broken", core.Name)
+	}
+
+	result := RepairResult{Branch: session.BranchName()}
+	if !m.config.GitChecker.BranchExists(result.Branch) {
+		return result, fmt.Errorf("branch '%s' of session '%s' no longer exists, so its worktree can't be recreated; delete the session with 'cwt delete %s'",
+			result.Branch, core.Name, core.Name)
+	}
+
+	alive := m.config.TmuxChecker.IsSessionAlive(core.TmuxSession)
+	conversationID := m.conversationID(core)
+	if alive {
+		if err := m.config.TmuxChecker.KillSession(core.TmuxSession); err != nil {
+			return result, fmt.Errorf("failed to stop session '%s': %w", core.Name, err)
+		}
+	}
+
+	if _, err := os.Lstat(core.WorktreePath); err == nil {
+		aside := fmt.Sprintf("%s.broken-%s", core.WorktreePath, m.Now().Format("20060102-150405"))
+		if err := os.Rename(core.WorktreePath, aside); err != nil {
+			return result, fmt.Errorf("failed to move broken worktree aside: %w", err)
+		}
+		result.MovedAside = aside
+	}
+
+	logger.Info("repairing worktree", "session", core.Name, "branch", result.Branch, "path", core.WorktreePath)
+	if err := m.config.GitChecker.AddWorktree(result.Branch, core.WorktreePath); err != nil {
+		return result, fmt.Errorf("failed to recreate worktree: %w", err)
+	}
+	if err := m.config.GitChecker.PopulateWorktree(context.Background(), core.WorktreePath, nil); err != nil {
+		return result, fmt.Errorf("failed to populate recreated worktree: %w", err)
+	}
+	if agent.IsClaude(core.Agent) {
+		if _, err := m.syncClaudeProject(core.WorktreePath); err != nil {
+			logger.Warn("failed to bring the project's Claude configuration into the worktree", "session", core.Name, "error", err)
+		}
+	}
+	// Not fatal: the project may just not run right away
+	if _, err := m.copyUntrackedFiles(core.WorktreePath, m.untrackedPatterns(core)); err != nil {
+		logger.Warn("failed to copy untracked files", "session", core.Name, "error", err)
+	}
+	if err := m.installGitHooks(context.Background(), core.WorktreePath, nil); err != nil {
+		return result, err
+	}
+	if err := m.createClaudeSettings(core); err != nil {
+		return result, fmt.Errorf("failed to create Claude settings: %w", err)
+	}
+	if err := m.linkContextFiles(core.WorktreePath); err != nil {
+		logger.Warn("failed to share context files", "session", core.Name, "error", err)
+	}
+
+	if alive {
+		command := m.AgentLaunch(core, conversationID, "").Command
+		if err := m.config.TmuxChecker.CreateSession(core.TmuxSession, core.WorktreePath, command, m.SessionEnv(core)...); err != nil {
+			return result, fmt.Errorf("failed to restart tmux session: %w", err)
+		}
+		m.installExitHookOrWarn(core)
+		result.Restarted = true
+	}
+
+	m.InvalidateStatus(sessionID)
+	m.RecordEvent(sessionID, types.EventRepaired, fmt.Sprintf("Worktree recreated from %s", result.Branch), map[string]interface{}{
+		"moved_aside": result.MovedAside,
+	})
+	m.eventBus.Publish(types.SessionUpdated{Session: m.deriveSession(core), Previous: core})
+
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_RepairWorktree(t *testing.T) {
+	tmuxChecker := tmux.NewMockChecker()
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:          filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       gitChecker,
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+
+	if _, err := manager.RepairWorktree(auth.ID); err == nil || !strings.Contains(err.Error(), "not broken") {
+		t.Errorf("RepairWorktree() = %v, want a healthy worktree left alone", err)
+	}
+
+	// The worktree was restored from a backup without its .git link
+	if err := os.Remove(filepath.Join(auth.WorktreePath, ".claude", "settings.json")); err != nil {
+		t.Fatalf("failed to damage worktree: %v", err)
+	}
+	gitChecker.SetStatusError(auth.WorktreePath, &git.StatusError{Kind: types.GitErrorNotRepository, Path: auth.WorktreePath})
+	manager.InvalidateStatus(auth.ID)
+	if session, _ := manager.DeriveFreshSessions(); !session[0].GitStatus.WorktreeBroken() {
+		t.Fatalf("git status = %+v, want the worktree broken", session[0].GitStatus)
+	}
+
+	if _, err := manager.RepairWorktree(auth.ID); err == nil || !strings.Contains(err.Error(), "cwt delete auth") {
+		t.Errorf("RepairWorktree() = %v, want it to fail without the branch", err)
+	}
+
+	gitChecker.Existing["auth"] = true
+	result, err := manager.RepairWorktree(auth.ID)
+	if err != nil {
+		t.Fatalf("RepairWorktree() error = %v", err)
+	}
+	if result.Branch != "auth" || !result.Restarted {
+		t.Errorf("RepairWorktree() = %+v, want it recreated from auth and restarted", result)
+	}
+	if !strings.HasPrefix(result.MovedAside, auth.WorktreePath+".broken-") {
+		t.Errorf("moved aside to %q, want next to the worktree", result.MovedAside)
+	}
+	if _, err := os.Stat(filepath.Join(result.MovedAside, ".claude")); err != nil {
+		t.Errorf("the broken directory should be kept: %v", err)
+	}
+
+	if gitChecker.Branches[auth.WorktreePath] != "auth" || !gitChecker.Populated[auth.WorktreePath] {
+		t.Error("the worktree should be checked out again from its branch")
+	}
+	if _, err := os.Stat(filepath.Join(auth.WorktreePath, ".claude", "settings.json")); err != nil {
+		t.Errorf("Claude settings should be recreated: %v", err)
+	}
+	if command := tmuxChecker.SessionCommands[auth.TmuxSession]; command != "claude -r mock-session-auth" {
+		t.Errorf("restarted with %q, want the conversation resumed", command)
+	}
+
+	sessions, _ := manager.DeriveFreshSessions()
+	if sessions[0].GitStatus.HasError() {
+		t.Errorf("git status = %+v, want the repaired worktree readable", sessions[0].GitStatus)
+	}
+	events, _ := manager.Timeline(auth.ID)
+	if last := events[len(events)-1]; last.Type != types.EventRepaired {
+		t.Errorf("last event = %+v, want the repair recorded", last)
+	}
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// reviewRef is the ref that keeps a session's last reviewed snapshot from
+// being garbage collected
+func reviewRef(sessionID string) string {
+	return "refs/cwt/reviewed/" + sessionID
+}
+
+// MarkReviewed records what a session's worktree holds now as read, after
+// its diff was viewed, so later changes can be shown on their own with
+// ReviewedChanges
+func (m *Manager) MarkReviewed(sessionID string) error {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	tree, err := m.config.GitChecker.SnapshotWorktree(core.WorktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to mark session '%s' reviewed: %w", core.Name, err)
+	}
+	if err := m.config.GitChecker.PinSnapshot(tree, reviewRef(sessionID)); err != nil {
+		return fmt.Errorf("failed to mark session '%s' reviewed: %w", core.Name, err)
+	}
+
+	m.InvalidateStatus(sessionID)
+	return m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.Review = &types.Review{Tree: tree, At: m.Now()}
+	})
+}
+
+// Approve marks a session reviewed and records that its changes were
+// approved for merging
+func (m *Manager) Approve(sessionID string) error {
+	if err := m.MarkReviewed(sessionID); err != nil {
+		return err
+	}
+	m.RecordEvent(sessionID, types.EventApproved, "Approved for merging", nil)
+	return nil
+}
+
+// ReviewedChanges returns the snapshot of a session's worktree taken when
+// it was last reviewed and one of the worktree now, for git diff to compare.
+// It fails if the session was never reviewed.
+func (m *Manager) ReviewedChanges(sessionID string) (reviewed, current string, err error) {
+	core, err := m.findCoreSession(sessionID)
+	if err != nil {
+		return "", "", err
+	}
+	if core.Review == nil {
+		return "", "", fmt.Errorf("session '%s' hasn't been reviewed yet; view its diff with 'cwt diff %s' first", core.Name, core.Name)
+	}
+
+	current, err = m.config.GitChecker.SnapshotWorktree(core.WorktreePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to snapshot session '%s': %w", core.Name, err)
+	}
+	return core.Review.Tree, current, nil
+}
+
+// reviewTree returns the snapshot a session was last reviewed at, or ""
+func reviewTree(core types.CoreSession) string {
+	if core.Review == nil {
+		return ""
+	}
+	return core.Review.Tree
+}
+
+// compareSnapshots records in a cache entry whether a session's worktree
+// holds anything its last review, or its last push, didn't. The worktree is
+// snapshotted once for both, and only when the session was reviewed or
+// published.
+func (m *Manager) compareSnapshots(core types.CoreSession, entry *statusCacheEntry) {
+	entry.ReviewTree, entry.PublishTree = reviewTree(core), publishTree(core)
+	entry.ChangedSinceReview, entry.ChangedSincePublish = false, false
+	if (entry.ReviewTree == "" && entry.PublishTree == "") || entry.GitStatus.HasError() {
+		return
+	}
+	tree, err := m.config.GitChecker.SnapshotWorktree(core.WorktreePath)
+	if err != nil {
+		logger.Debug("failed to snapshot worktree", "session", core.Name, "error", err)
+		return
+	}
+	entry.ChangedSinceReview = entry.ReviewTree != "" && tree != entry.ReviewTree
+	entry.ChangedSincePublish = entry.PublishTree != "" && tree != entry.PublishTree
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_MarkReviewed(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:        filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:    tmux.NewMockChecker(),
+		GitChecker:     gitChecker,
+		ClaudeChecker:  claude.NewMockChecker(),
+		StatusCacheTTL: time.Minute,
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	auth := cores[0]
+
+	if _, _, err := manager.ReviewedChanges(auth.ID); err == nil {
+		t.Error("ReviewedChanges() should fail for a session never reviewed")
+	}
+	gitChecker.Snapshots[auth.WorktreePath] = "t1"
+	if session, _ := manager.DeriveSession(auth.ID); session.ChangedSinceReview {
+		t.Error("a session never reviewed has nothing new since")
+	}
+
+	if err := manager.MarkReviewed(auth.ID); err != nil {
+		t.Fatalf("MarkReviewed() error = %v", err)
+	}
+	if gitChecker.Pinned[reviewRef(auth.ID)] != "t1" {
+		t.Errorf("pinned %v, want the reviewed snapshot kept", gitChecker.Pinned)
+	}
+	session, _ := manager.DeriveSession(auth.ID)
+	if session.Core.Review == nil || session.Core.Review.Tree != "t1" || session.ChangedSinceReview {
+		t.Errorf("review = %+v, changed = %v; want t1 reviewed and nothing new", session.Core.Review, session.ChangedSinceReview)
+	}
+
+	// Claude keeps working
+	gitChecker.Snapshots[auth.WorktreePath] = "t2"
+	manager.InvalidateStatus(auth.ID)
+	if session, _ := manager.DeriveSession(auth.ID); !session.ChangedSinceReview {
+		t.Error("changes made since the review should be flagged")
+	}
+	reviewed, current, err := manager.ReviewedChanges(auth.ID)
+	if err != nil || reviewed != "t1" || current != "t2" {
+		t.Errorf("ReviewedChanges() = %q, %q, %v; want t1 to t2", reviewed, current, err)
+	}
+
+	// Reviewing again clears the flag at once, cached status or not
+	if err := manager.MarkReviewed(auth.ID); err != nil {
+		t.Fatalf("MarkReviewed() error = %v", err)
+	}
+	if session, _ := manager.DeriveSession(auth.ID); session.ChangedSinceReview {
+		t.Error("a session just reviewed has nothing new")
+	}
+
+	if err := manager.DeleteSession(auth.ID); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	if len(gitChecker.Pinned) != 0 {
+		t.Errorf("pinned %v after deleting the session, want its snapshot released", gitChecker.Pinned)
+	}
+}
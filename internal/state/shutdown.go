@@ -0,0 +1,59 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// RefreshShutdown is the reason of the refresh broadcast 'cwt shutdown'
+// sends, on which running TUIs exit
+const RefreshShutdown = "shutdown"
+
+// ShutdownResult lists what stopping every session did
+type ShutdownResult struct {
+	Stopped []string // Sessions whose tmux session was paused or killed
+	Skipped []string // Sessions already paused or not running
+}
+
+// ShutdownSessions stops the tmux session of every running session, like
+// before a reboot: it pauses them, so 'cwt resume' continues their Claude
+// conversations, or with kill just ends them, leaving 'cwt attach' to start
+// them again. A session that can't be stopped doesn't stop the others; the
+// error lists every failure.
+func (m *Manager) ShutdownSessions(kill bool) (ShutdownResult, error) {
+	var result ShutdownResult
+	cores, err := m.CoreSessions()
+	if err != nil {
+		return result, err
+	}
+
+	var failures []error
+	for _, core := range cores {
+		if core.IsPaused() || !m.config.TmuxChecker.IsSessionAlive(core.TmuxSession) {
+			result.Skipped = append(result.Skipped, core.Name)
+			continue
+		}
+
+		if kill {
+			err = m.config.TmuxChecker.KillSession(core.TmuxSession)
+			if err == nil {
+				// A stopped session didn't die, so there is no exit to report
+				types.RemoveSessionExit(m.config.DataDir, core.ID)
+				m.RecordEvent(core.ID, types.EventStopped, "Stopped by shutdown", nil)
+			}
+		} else {
+			err = m.PauseSession(core.ID)
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", core.Name, err))
+			continue
+		}
+		result.Stopped = append(result.Stopped, core.Name)
+	}
+
+	// Whatever was cached describes sessions that just stopped
+	m.InvalidateStatus("")
+	return result, errors.Join(failures...)
+}
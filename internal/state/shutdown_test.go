@@ -0,0 +1,88 @@
+package state
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func newShutdownManager(t *testing.T) (*Manager, *tmux.MockChecker) {
+	t.Helper()
+	tmuxChecker := tmux.NewMockChecker()
+	manager := NewManager(Config{
+		DataDir:          filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:      tmuxChecker,
+		GitChecker:       git.NewMockChecker(),
+		ClaudeChecker:    claude.NewMockChecker(),
+		ClaudeExecutable: "claude",
+	})
+	t.Cleanup(manager.Close)
+
+	for _, name := range []string{"auth", "docs", "idle"} {
+		if err := manager.CreateSession(name); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+	cores, _ := manager.CoreSessions()
+	for _, core := range cores {
+		switch core.Name {
+		case "docs":
+			if err := manager.PauseSession(core.ID); err != nil {
+				t.Fatalf("PauseSession() error = %v", err)
+			}
+		case "idle":
+			tmuxChecker.SetAlive(core.TmuxSession, false)
+		}
+	}
+	return manager, tmuxChecker
+}
+
+func TestManager_ShutdownSessions_Pauses(t *testing.T) {
+	manager, tmuxChecker := newShutdownManager(t)
+
+	result, err := manager.ShutdownSessions(false)
+	if err != nil {
+		t.Fatalf("ShutdownSessions() error = %v", err)
+	}
+	if !slices.Equal(result.Stopped, []string{"auth"}) || len(result.Skipped) != 2 {
+		t.Errorf("result = %+v, want only auth stopped", result)
+	}
+
+	cores, _ := manager.CoreSessions()
+	for _, core := range cores {
+		if core.Name == "auth" && (!core.IsPaused() || tmuxChecker.AliveSessions[core.TmuxSession]) {
+			t.Errorf("auth should be paused with its tmux session stopped: %+v", core)
+		}
+	}
+}
+
+func TestManager_ShutdownSessions_Kills(t *testing.T) {
+	manager, tmuxChecker := newShutdownManager(t)
+
+	result, err := manager.ShutdownSessions(true)
+	if err != nil {
+		t.Fatalf("ShutdownSessions() error = %v", err)
+	}
+	if !slices.Equal(result.Stopped, []string{"auth"}) {
+		t.Errorf("stopped = %v, want auth", result.Stopped)
+	}
+
+	cores, _ := manager.CoreSessions()
+	for _, core := range cores {
+		if core.Name != "auth" {
+			continue
+		}
+		if core.IsPaused() || !slices.Contains(tmuxChecker.KilledSessions, core.TmuxSession) {
+			t.Errorf("auth should have its tmux session killed without being paused: %+v", core)
+		}
+		events, _ := manager.Timeline(core.ID)
+		if len(events) == 0 || events[len(events)-1].Type != types.EventStopped {
+			t.Errorf("timeline = %+v, want it to end with the stop", events)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+const (
+	maxTags      = 10 // Tags a session may have
+	maxTagLength = 30
+)
+
+// tagPattern is what a tag may look like once lowercased
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+
+// ParseTags splits tags typed as one line, separated by commas or spaces
+func ParseTags(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// NormalizeTags validates tags and returns them lowercased, without a
+// leading '#', sorted and without duplicates
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag '%s' too long (max %d characters)", tag, maxTagLength)
+		}
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag '%s': use letters, digits, '.', '_', '/' and '-'", tag)
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("too many tags (max %d)", maxTags)
+	}
+	return normalized, nil
+}
+
+// SetSessionTags replaces the tags of a session. No tags clears them.
+func (m *Manager) SetSessionTags(sessionID string, tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+	return m.UpdateSession(sessionID, func(core *types.CoreSession) {
+		core.Tags = normalized
+	})
+}
@@ -0,0 +1,82 @@
+package state
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{input: "", want: []string{}},
+		{input: "backend, #Auth  backend", want: []string{"auth", "backend"}},
+		{input: "team/platform,v1.2", want: []string{"team/platform", "v1.2"}},
+		{input: "-draft", wantErr: "invalid tag '-draft'"},
+		{input: "needs:review", wantErr: "invalid tag"},
+		{input: strings.Repeat("x", 31), wantErr: "too long"},
+		{input: "a b c d e f g h i j k", wantErr: "too many tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeTags(ParseTags(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NormalizeTags() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeTags() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_SetSessionTags(t *testing.T) {
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	id := cores[0].ID
+
+	if err := manager.SetSessionTags(id, []string{"Backend", "urgent", "backend"}); err != nil {
+		t.Fatalf("SetSessionTags() error = %v", err)
+	}
+	cores, _ = manager.CoreSessions()
+	if want := []string{"backend", "urgent"}; !reflect.DeepEqual(cores[0].Tags, want) {
+		t.Errorf("tags = %q, want %q", cores[0].Tags, want)
+	}
+
+	if err := manager.SetSessionTags(id, []string{"bad tag?"}); err == nil {
+		t.Error("SetSessionTags() should reject an invalid tag")
+	}
+
+	if err := manager.SetSessionTags(id, nil); err != nil {
+		t.Fatalf("SetSessionTags() error = %v", err)
+	}
+	cores, _ = manager.CoreSessions()
+	if cores[0].Tags != nil {
+		t.Errorf("tags = %q, want them cleared", cores[0].Tags)
+	}
+}
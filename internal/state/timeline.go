@@ -0,0 +1,38 @@
+package state
+
+import (
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// RecordEvent appends a lifecycle event, like types.EventMerged, to a
+// session's event log, which makes up its timeline. Failures are ignored
+// since the timeline is only informational.
+func (m *Manager) RecordEvent(sessionID, eventType, message string, data map[string]interface{}) {
+	types.AppendSessionEvent(m.config.DataDir, sessionID, types.SessionEvent{
+		Type:    eventType,
+		Time:    m.Now(),
+		Message: message,
+		Data:    data,
+	})
+}
+
+// RecordEventByName records a lifecycle event for the session with a name,
+// if there is one
+func (m *Manager) RecordEventByName(sessionName, eventType, message string, data map[string]interface{}) {
+	cores, err := m.CoreSessions()
+	if err != nil {
+		return
+	}
+	for _, core := range cores {
+		if core.Name == sessionName {
+			m.RecordEvent(core.ID, eventType, message, data)
+			return
+		}
+	}
+}
+
+// Timeline returns everything recorded for a session, oldest first: its
+// lifecycle events and the events Claude's hooks sent
+func (m *Manager) Timeline(sessionID string) ([]types.SessionEvent, error) {
+	return types.LoadSessionEvents(m.config.DataDir, sessionID)
+}
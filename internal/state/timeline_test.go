@@ -0,0 +1,77 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/clock"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestManager_Timeline(t *testing.T) {
+	claudeChecker := claude.NewMockChecker()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	manager := NewManager(Config{
+		Clock:         clock.NewMockClock(now),
+		DataDir:       filepath.Join(t.TempDir(), ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claudeChecker,
+		BaseBranch:    "main",
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	id := cores[0].ID
+
+	if err := manager.RenameSession(id, "login"); err != nil {
+		t.Fatalf("RenameSession() error = %v", err)
+	}
+	if err := manager.PauseSession(id); err != nil {
+		t.Fatalf("PauseSession() error = %v", err)
+	}
+	manager.RecordEventByName("login", types.EventMerged, "Merged into main", nil)
+	manager.RecordEventByName("missing", types.EventMerged, "Merged into main", nil)
+
+	events, err := manager.Timeline(id)
+	if err != nil {
+		t.Fatalf("Timeline() error = %v", err)
+	}
+	var kinds []string
+	for _, event := range events {
+		kinds = append(kinds, event.Type)
+	}
+	want := []string{types.EventCreated, types.EventRenamed, types.EventPaused, types.EventMerged}
+	if len(kinds) != len(want) {
+		t.Fatalf("timeline = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("timeline = %v, want %v", kinds, want)
+		}
+	}
+	if events[1].Message != "Renamed from auth" {
+		t.Errorf("rename recorded as %q", events[1].Message)
+	}
+	for _, event := range events {
+		if !event.Time.Equal(now) {
+			t.Errorf("%s event recorded at %v, want the manager's clock %v", event.Type, event.Time, now)
+		}
+	}
+
+	// Without hook events, Claude's status still comes from its transcripts
+	claudeChecker.Statuses = map[string]types.ClaudeStatus{
+		cores[0].WorktreePath: {State: types.ClaudeWorking},
+	}
+	session := manager.deriveSession(cores[0])
+	if session.ClaudeStatus.State == types.ClaudeUnknown {
+		t.Error("lifecycle events alone shouldn't hide Claude's status")
+	}
+}
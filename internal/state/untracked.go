@@ -0,0 +1,113 @@
+package state
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// untrackedPatterns returns the patterns of files copied into a session's
+// worktree: the config's, then those it was created with
+func (m *Manager) untrackedPatterns(core types.CoreSession) []string {
+	return append(append([]string(nil), m.config.CopyUntracked...), core.CopyUntracked...)
+}
+
+// copyUntrackedFiles copies files git doesn't check out, like .env or
+// local config, from the main checkout into a new worktree, so the project
+// runs there right away. patterns are globs relative to the repository, as
+// with filepath.Match; a matching directory is copied whole, and symlinks
+// are copied as symlinks. Files the worktree already has, like tracked
+// ones, are left alone. It returns the paths it copied.
+func (m *Manager) copyUntrackedFiles(worktreePath string, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	dataDir, err := filepath.Abs(m.config.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve data directory: %w", err)
+	}
+
+	var copied []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(repoDir, filepath.FromSlash(pattern)))
+		if err != nil {
+			return copied, fmt.Errorf("invalid copy_untracked pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			logger.Debug("no files to copy into worktree", "pattern", pattern)
+		}
+		for _, source := range matches {
+			rel, err := filepath.Rel(repoDir, source)
+			if err != nil || !filepath.IsLocal(rel) || rel == ".git" || withinDir(source, dataDir) {
+				continue
+			}
+			target := filepath.Join(worktreePath, rel)
+			if _, err := os.Lstat(target); err == nil {
+				continue
+			}
+			if err := copyTree(source, target); err != nil {
+				return copied, fmt.Errorf("failed to copy %s into the worktree: %w", rel, err)
+			}
+			copied = append(copied, filepath.ToSlash(rel))
+		}
+	}
+	return copied, nil
+}
+
+// withinDir reports whether path is dir or inside it
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && (rel == "." || !strings.HasPrefix(rel, ".."))
+}
+
+// copyTree copies a file, symlink or directory with everything in it,
+// keeping file modes and copying symlinks as they are
+func copyTree(source, target string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(target, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			return os.Symlink(link, dest)
+		case entry.IsDir():
+			return os.MkdirAll(dest, info.Mode().Perm())
+		case !info.Mode().IsRegular():
+			// Sockets and the like can't be copied
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := copyFile(path, dest); err != nil {
+			return err
+		}
+		return os.Chmod(dest, info.Mode().Perm())
+	})
+}
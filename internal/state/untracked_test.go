@@ -0,0 +1,85 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+)
+
+func TestManager_CopyUntrackedFiles(t *testing.T) {
+	repo := t.TempDir()
+	shared := t.TempDir()
+	for path, contents := range map[string]string{
+		".env":             "SECRET=1\n",
+		".env.local":       "LOCAL=1\n",
+		"config/local.yml": "debug: true\n",
+		"README.md":        "tracked\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(repo, path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, path), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(shared, filepath.Join(repo, "node_modules")); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	manager := NewManager(Config{
+		DataDir:       filepath.Join(repo, ".cwt"),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		CopyUntracked: []string{".env*", "node_modules", ".cwt"},
+	})
+	defer manager.Close()
+
+	if err := manager.CreateSessionWithOptions("api", CreateOptions{CopyUntracked: []string{"config"}}); err != nil {
+		t.Fatalf("CreateSessionWithOptions() error = %v", err)
+	}
+	cores, _ := manager.CoreSessions()
+	worktree := cores[0].WorktreePath
+
+	for _, path := range []string{".env", ".env.local", "config/local.yml"} {
+		info, err := os.Stat(filepath.Join(worktree, path))
+		if err != nil {
+			t.Errorf("%s should be copied: %v", path, err)
+		} else if info.Mode().Perm() != 0600 {
+			t.Errorf("%s should keep its mode, got %v", path, info.Mode())
+		}
+	}
+	if link, err := os.Readlink(filepath.Join(worktree, "node_modules")); err != nil || link != shared {
+		t.Errorf("node_modules should be copied as a symlink, got %q, %v", link, err)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, ".cwt")); err == nil {
+		t.Error("the data directory should never be copied")
+	}
+
+	// Files the worktree has, like tracked ones, are left alone
+	if err := os.WriteFile(filepath.Join(worktree, "README.md"), []byte("checked out\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	copied, err := manager.copyUntrackedFiles(worktree, []string{"*.md", ".env"})
+	if err != nil || len(copied) != 0 {
+		t.Errorf("copyUntrackedFiles() = %v, %v, want nothing copied", copied, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktree, "README.md")); string(data) != "checked out\n" {
+		t.Errorf("README.md was overwritten: %q", data)
+	}
+
+	if _, err := manager.copyUntrackedFiles(worktree, []string{"[bad"}); err == nil {
+		t.Error("expected an invalid pattern to be rejected")
+	}
+	if !slices.Equal(cores[0].CopyUntracked, []string{"config"}) {
+		t.Errorf("the session should remember its own patterns, got %v", cores[0].CopyUntracked)
+	}
+}
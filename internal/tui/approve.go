@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// approvalArchiveReason is the archive reason of a session approved and merged
+const approvalArchiveReason = "approved and merged"
+
+// stepState is how far a step of the approve-and-merge flow got
+type stepState int
+
+const (
+	stepPending stepState = iota
+	stepRunning
+	stepDone
+	stepSkipped
+	stepFailed
+)
+
+// errSkipStep is returned by an approval step with nothing to do; its
+// detail says why
+var errSkipStep = errors.New("skipped")
+
+// approvalStep is one step of the approve-and-merge flow. run returns a
+// short detail, like the commit it made, or an error that stops the flow.
+type approvalStep struct {
+	label  string
+	run    func(ctx context.Context) (string, error)
+	state  stepState
+	detail string
+}
+
+// approval is an approve-and-merge flow running in the background
+type approval struct {
+	session string
+	steps   []approvalStep
+	cancel  context.CancelFunc // Stops the flow before its next step
+}
+
+type (
+	approvalStartedMsg struct {
+		run   *approval
+		start tea.Cmd // Runs the steps
+	}
+	approvalProgressMsg struct{ steps []approvalStep }
+	approvalDoneMsg     struct {
+		session string
+		steps   []approvalStep
+	}
+)
+
+// approveAndMerge asks to ship a session in one go: approve it, run the
+// test gate, commit its changes, squash merge it into the base branch and
+// archive it, stopping at the first step that fails
+func (m Model) approveAndMerge(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		session := m.findSession(sessionID)
+		if session == nil {
+			return errorMsg{err: fmt.Errorf("session not found")}
+		}
+
+		if err := operations.CheckWorktree(*session); err != nil {
+			return errorMsg{err: err}
+		}
+		if !session.GitStatus.HasChanges && session.GitStatus.CommitCount == 0 {
+			return errorMsg{err: fmt.Errorf("session '%s' has no changes to merge", session.Core.Name)}
+		}
+		if err := operations.CheckConflicts(*session); err != nil {
+			return errorMsg{err: err}
+		}
+
+		dialog := showConfirmDialogMsg{
+			message: fmt.Sprintf("Approve '%s', squash merge it into %s and archive it?", session.Core.Name, session.BaseBranch),
+			onYes: func() tea.Cmd {
+				return m.startApproval(*session)
+			},
+			onNo: func() tea.Cmd { return nil },
+		}
+		if m.protected() {
+			dialog.phrase = operations.MergeOperation(session.Core.Name, session.BranchName(), session.BaseBranch, true).Phrase
+		}
+		return dialog
+	}
+}
+
+// startApproval runs the approve-and-merge steps of a session in the
+// background, reporting each step's progress through the event channel
+func (m Model) startApproval(session types.Session) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := m.approvalSteps(session)
+	eventChan := m.eventChan
+	report := func(steps []approvalStep) {
+		select {
+		case eventChan <- approvalProgressMsg{steps: steps}:
+		default: // Drop progress rather than block the flow; the end reports every step
+		}
+	}
+
+	// The steps start once the checklist is up, so they can't finish before it
+	return func() tea.Msg {
+		return approvalStartedMsg{
+			run: &approval{session: session.Core.Name, steps: steps, cancel: cancel},
+			start: func() tea.Msg {
+				defer cancel()
+				done := runApprovalSteps(ctx, steps, report)
+				return approvalDoneMsg{session: session.Core.Name, steps: done}
+			},
+		}
+	}
+}
+
+// approvalSteps lists what approving and merging a session takes
+func (m Model) approvalSteps(session types.Session) []approvalStep {
+	id, name, base, branch := session.Core.ID, session.Core.Name, session.BaseBranch, session.BranchName()
+	testCommand := m.config.TestCommand
+
+	return []approvalStep{
+		{
+			label: "Mark approved",
+			run: func(ctx context.Context) (string, error) {
+				return "", m.stateManager.Approve(id)
+			},
+		},
+		{
+			label: "Run tests",
+			run: func(ctx context.Context) (string, error) {
+				if testCommand == "" {
+					return "no test_command configured", errSkipStep
+				}
+				result, err := m.stateManager.RunCheck(ctx, id, testCommand)
+				if err != nil {
+					return "", err
+				}
+				if result.ExitCode != 0 {
+					failure := fmt.Sprintf("%s exited with %d", testCommand, result.ExitCode)
+					if result.Error != "" {
+						failure = fmt.Sprintf("%s failed: %s", testCommand, result.Error)
+					}
+					if result.Output != "" {
+						failure += "\n" + result.Output
+					}
+					return "", errors.New(failure)
+				}
+				return testCommand, nil
+			},
+		},
+		{
+			label: "Commit changes",
+			run: func(ctx context.Context) (string, error) {
+				result := types.NewPublishResultOutput(name, "")
+				if err := utils.ExecuteCWTCommandJSON(&result, "publish", name, "--local", "--json"); err != nil {
+					return "", err
+				}
+				if result.Commit == nil {
+					return "nothing to commit", errSkipStep
+				}
+				return fmt.Sprintf("%s %s", shortHash(result.Commit.Hash), result.Commit.Subject), nil
+			},
+		},
+		{
+			label: "Squash merge into " + base,
+			run: func(ctx context.Context) (string, error) {
+				args := []string{name, "--squash", "--target", base, "--yes", "--json"}
+				if m.protected() {
+					// The phrase typed when approving confirms the merge; the
+					// token is taken now, after the commit moved the branch
+					args = append(args, "--confirm", operations.MergeOperation(name, branch, base, true).Token())
+				}
+				result := types.NewMergeResultOutput(name, "", base, true)
+				err := utils.ExecuteCWTCommandJSON(&result, "merge", args...)
+				if len(result.Conflicts) > 0 {
+					return "", fmt.Errorf("conflicts in %s", strings.Join(result.Conflicts, ", "))
+				}
+				if err != nil {
+					return "", err
+				}
+				return "commit " + shortHash(result.Commit), nil
+			},
+		},
+		{
+			label: "Archive session",
+			run: func(ctx context.Context) (string, error) {
+				return "", m.stateManager.ArchiveSession(id, approvalArchiveReason)
+			},
+		},
+	}
+}
+
+// runApprovalSteps runs steps in order until one fails or ctx is cancelled,
+// calling report with a copy of the steps as each starts and finishes. It
+// returns the steps as they ended; those never reached stay pending.
+func runApprovalSteps(ctx context.Context, steps []approvalStep, report func([]approvalStep)) []approvalStep {
+	steps = append([]approvalStep(nil), steps...)
+	update := func(i int, state stepState, detail string) {
+		steps[i].state, steps[i].detail = state, detail
+		report(append([]approvalStep(nil), steps...))
+	}
+
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			update(i, stepFailed, "aborted")
+			break
+		}
+
+		update(i, stepRunning, "")
+		detail, err := step.run(ctx)
+		switch {
+		case errors.Is(err, errSkipStep):
+			update(i, stepSkipped, detail)
+		case err != nil:
+			update(i, stepFailed, err.Error())
+			return steps
+		default:
+			update(i, stepDone, detail)
+		}
+	}
+	return steps
+}
+
+// approvalChecklist renders approval steps one per line, followed by the
+// output of the step that failed, if any
+func approvalChecklist(steps []approvalStep) []string {
+	var lines, output []string
+	for _, step := range steps {
+		detail, rest, _ := strings.Cut(step.detail, "\n")
+		switch step.state {
+		case stepPending:
+			lines = append(lines, idleStyle.Render("○ "+step.label))
+		case stepRunning:
+			lines = append(lines, workingStyle.Render("⟳ "+step.label+"..."))
+		case stepDone:
+			lines = append(lines, aliveStyle.Render("✓ "+step.label)+describeStep(detail))
+		case stepSkipped:
+			lines = append(lines, idleStyle.Render("– "+step.label)+describeStep(detail))
+		case stepFailed:
+			lines = append(lines, deadStyle.Render("✗ "+step.label)+describeStep(detail))
+			if rest != "" {
+				output = strings.Split(rest, "\n")
+			}
+		}
+	}
+
+	if len(output) > resultListLimit {
+		output = output[len(output)-resultListLimit:]
+	}
+	if len(output) > 0 {
+		lines = append(lines, "")
+		for _, line := range output {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+// describeStep appends a step's detail to its checklist line
+func describeStep(detail string) string {
+	if detail == "" {
+		return ""
+	}
+	return ": " + detail
+}
+
+// approvalResultPanel describes how an approve-and-merge flow ended
+func approvalResultPanel(session string, steps []approvalStep) *ResultPanel {
+	panel := &ResultPanel{
+		Title: fmt.Sprintf("Approved and merged '%s'", session),
+		Lines: approvalChecklist(steps),
+	}
+	for _, step := range steps {
+		if step.state == stepFailed {
+			panel.Title = fmt.Sprintf("Approving '%s' stopped at: %s", session, step.label)
+			panel.Failed = true
+		}
+	}
+	return panel
+}
+
+// handleApprovalKeys lets esc abort a running approve-and-merge flow; it
+// stops before its next step, as a step under way can't be undone halfway
+func (m Model) handleApprovalKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.approval.cancel()
+	}
+	return m, nil
+}
+
+// renderApproval renders the checklist of a running approve-and-merge flow
+func (m Model) renderApproval() string {
+	title := workingStyle.Render(fmt.Sprintf("Approving and merging '%s'", m.approval.session))
+	return m.renderPanel(title, approvalChecklist(m.approval.steps), []string{"esc: abort"})
+}
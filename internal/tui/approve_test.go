@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunApprovalSteps(t *testing.T) {
+	var ran []string
+	step := func(label, detail string, err error) approvalStep {
+		return approvalStep{label: label, run: func(ctx context.Context) (string, error) {
+			ran = append(ran, label)
+			return detail, err
+		}}
+	}
+	steps := []approvalStep{
+		step("approve", "", nil),
+		step("test", "no test_command configured", errSkipStep),
+		step("commit", "0123456 Add login", nil),
+		step("merge", "", errors.New("conflicts in login.go")),
+		step("archive", "", nil),
+	}
+
+	var reports int
+	done := runApprovalSteps(context.Background(), steps, func([]approvalStep) { reports++ })
+	if strings.Join(ran, ",") != "approve,test,commit,merge" {
+		t.Errorf("ran %v, want the flow stopped at the failing merge", ran)
+	}
+	want := []stepState{stepDone, stepSkipped, stepDone, stepFailed, stepPending}
+	for i, step := range done {
+		if step.state != want[i] {
+			t.Errorf("step %s = %v, want %v", step.label, step.state, want[i])
+		}
+	}
+	if reports != 8 {
+		t.Errorf("reported %d times, want each step's start and end", reports)
+	}
+	if steps[0].state != stepPending {
+		t.Error("the steps passed in should be left alone")
+	}
+
+	panel := approvalResultPanel("auth", done)
+	if !panel.Failed || !strings.Contains(panel.Title, "stopped at: merge") {
+		t.Errorf("panel = %+v, want the failing step named", panel)
+	}
+	body := strings.Join(panel.Lines, "\n")
+	for _, want := range []string{"commit: 0123456 Add login", "test: no test_command configured", "merge: conflicts in login.go", "○ archive"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("lines = %q, want %q", body, want)
+		}
+	}
+}
+
+func TestRunApprovalSteps_Aborted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := []approvalStep{
+		{label: "approve", run: func(context.Context) (string, error) {
+			cancel() // Esc pressed while the step runs
+			return "", nil
+		}},
+		{label: "merge", run: func(context.Context) (string, error) {
+			t.Error("no step should start once the flow is aborted")
+			return "", nil
+		}},
+	}
+
+	done := runApprovalSteps(ctx, steps, func([]approvalStep) {})
+	if done[0].state != stepDone || done[1].state != stepFailed || done[1].detail != "aborted" {
+		t.Errorf("steps = %+v, want the step under way finished and the next aborted", done)
+	}
+}
+
+func TestApprovalChecklist_FailureOutput(t *testing.T) {
+	output := make([]string, resultListLimit+2)
+	for i := range output {
+		output[i] = "line"
+	}
+	output[len(output)-1] = "FAIL: TestLogin"
+	steps := []approvalStep{{label: "Run tests", state: stepFailed, detail: "make test exited with 2\n" + strings.Join(output, "\n")}}
+
+	lines := approvalChecklist(steps)
+	if !strings.Contains(lines[0], "Run tests: make test exited with 2") {
+		t.Errorf("first line = %q, want the failure summarized", lines[0])
+	}
+	if len(lines) != 2+resultListLimit || lines[len(lines)-1] != "  FAIL: TestLogin" {
+		t.Errorf("lines = %q, want the end of the output below the checklist", lines)
+	}
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// toggleAttention watches or mutes the selected session, or undoes that
+// when it already is; the toast's refresh re-sorts the list
+func (m Model) toggleAttention(attention types.Attention) tea.Cmd {
+	session := m.findSession(m.getSelectedSessionID())
+	if session == nil {
+		return nil
+	}
+	id, name := session.Core.ID, session.Core.Name
+	if session.Core.Attention == attention {
+		attention = ""
+	}
+	message := attentionToast(name, session.Core.Attention, attention)
+
+	return func() tea.Msg {
+		if err := m.stateManager.SetSessionAttention(id, attention); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to update '%s': %w", name, err)}
+		}
+		return successToastMsg{message: message}
+	}
+}
+
+// attentionToast says what changing a session's attention did
+func attentionToast(name string, from, to types.Attention) string {
+	switch {
+	case to == types.AttentionWatched:
+		return fmt.Sprintf("Watching '%s'", name)
+	case to == types.AttentionMuted:
+		return fmt.Sprintf("Muted '%s' (z shows muted sessions)", name)
+	case from == types.AttentionMuted:
+		return fmt.Sprintf("Unmuted '%s'", name)
+	default:
+		return fmt.Sprintf("Took '%s' off the watch list", name)
+	}
+}
+
+// toggleShowMuted lists or collapses muted sessions, keeping the selected
+// session selected when it stays listed
+func (m Model) toggleShowMuted() Model {
+	selectedID := m.getSelectedSessionID()
+	m.showMuted = !m.showMuted
+	return m.reselect(selectedID)
+}
+
+// attentionBadge returns the marker shown after the name of a watched
+// session, and its visual width
+func attentionBadge(core types.CoreSession) (string, int) {
+	if core.IsWatched() {
+		return " " + waitingStyle.Render("★"), 2
+	}
+	return "", 0
+}
+
+// alertsFor reports whether a session waiting for input should raise an
+// alert: watched and high-priority sessions do, unless muted
+func alertsFor(session types.Session) bool {
+	if session.Core.IsMuted() {
+		return false
+	}
+	return session.Core.IsWatched() || session.Core.SessionPriority() == types.PriorityHigh
+}
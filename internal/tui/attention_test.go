@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func attentionTestSessions() []types.Session {
+	return []types.Session{
+		{Core: types.CoreSession{ID: "1", Name: "quiet", Attention: types.AttentionMuted}},
+		{Core: types.CoreSession{ID: "2", Name: "plain"}},
+		{Core: types.CoreSession{ID: "3", Name: "pinned", Attention: types.AttentionWatched}},
+	}
+}
+
+func sessionIDs(sessions []types.Session) string {
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.Core.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSortSessions_WatchedFirstMutedLast(t *testing.T) {
+	for _, order := range []string{config.SortCreated, config.SortName} {
+		sessions := attentionTestSessions()
+		sortSessions(sessions, order)
+		if got := sessionIDs(sessions); got != "3,2,1" {
+			t.Errorf("sortSessions(%s) = %s, want watched first and muted last", order, got)
+		}
+	}
+}
+
+func TestVisibleSessions_CollapsesMuted(t *testing.T) {
+	m := Model{sessions: attentionTestSessions(), sortOrder: config.SortCreated}
+	if got := sessionIDs(m.visibleSessions()); got != "3,2" || m.hiddenMutedCount() != 1 {
+		t.Errorf("visible = %s with %d hidden, want the muted session collapsed", got, m.hiddenMutedCount())
+	}
+
+	m.filterQuery = "qui"
+	if got := sessionIDs(m.visibleSessions()); got != "1" {
+		t.Errorf("filtered = %s, want the filter to find muted sessions", got)
+	}
+
+	m.filterQuery = ""
+	m = m.toggleShowMuted()
+	if got := sessionIDs(m.visibleSessions()); got != "3,2,1" || m.hiddenMutedCount() != 0 {
+		t.Errorf("visible = %s, want muted sessions shown after z", got)
+	}
+}
+
+func TestAlertsFor(t *testing.T) {
+	tests := []struct {
+		core types.CoreSession
+		want bool
+	}{
+		{types.CoreSession{}, false},
+		{types.CoreSession{Attention: types.AttentionWatched}, true},
+		{types.CoreSession{Priority: types.PriorityHigh}, true},
+		{types.CoreSession{Priority: types.PriorityHigh, Attention: types.AttentionMuted}, false},
+	}
+	for _, tt := range tests {
+		if got := alertsFor(types.Session{Core: tt.core}); got != tt.want {
+			t.Errorf("alertsFor(%+v) = %v, want %v", tt.core, got, tt.want)
+		}
+	}
+}
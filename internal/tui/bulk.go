@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// bulkAction is an action that can run on every marked session at once
+type bulkAction struct {
+	verb string // "Delete", shown in the confirmation
+	done string // "Deleted", shown in the result
+
+	// protected actions need each session's phrase typed in protected
+	// mode, so they don't run in bulk then
+	protected bool
+
+	// skip returns why a session can't take part, or "" if it can
+	skip func(session types.Session) string
+	run  func(m Model, session types.Session) error
+}
+
+var (
+	bulkDelete = bulkAction{
+		verb: "Delete",
+		done: "Deleted",
+		skip: func(types.Session) string { return "" },
+		run: func(m Model, session types.Session) error {
+			return m.stateManager.DeleteSession(session.Core.ID)
+		},
+	}
+
+	bulkCleanup = bulkAction{
+		verb: "Clean up",
+		done: "Cleaned up",
+		skip: func(session types.Session) string {
+			if session.IsAlive {
+				return "still running"
+			}
+			return ""
+		},
+		run: func(m Model, session types.Session) error {
+			return m.stateManager.DeleteSession(session.Core.ID)
+		},
+	}
+
+	bulkPublish = bulkAction{
+		verb: "Publish",
+		done: "Published",
+		skip: skipWithoutChanges,
+		run: func(m Model, session types.Session) error {
+			return utils.ExecuteCWTCommand("publish", session.Core.Name)
+		},
+	}
+
+	bulkMerge = bulkAction{
+		verb:      "Merge",
+		done:      "Merged",
+		protected: true,
+		skip:      skipWithoutChanges,
+		run: func(m Model, session types.Session) error {
+			// The bulk confirmation covers every session
+			return utils.ExecuteCWTCommand("merge", session.Core.Name, "--yes")
+		},
+	}
+)
+
+func skipWithoutChanges(session types.Session) string {
+	if !session.GitStatus.HasChanges {
+		return "no changes"
+	}
+	return ""
+}
+
+// toggleMark marks or unmarks the selected session for bulk actions
+func (m Model) toggleMark() Model {
+	sessionID := m.getSelectedSessionID()
+	if sessionID == "" {
+		return m
+	}
+
+	marked := make(map[string]bool, len(m.marked)+1)
+	for id := range m.marked {
+		marked[id] = true
+	}
+	if marked[sessionID] {
+		delete(marked, sessionID)
+	} else {
+		marked[sessionID] = true
+	}
+	m.marked = marked
+	return m
+}
+
+// markedSessions returns the marked sessions that still exist in list order,
+// including any the filter currently hides
+func (m Model) markedSessions() []types.Session {
+	var marked []types.Session
+	for _, session := range m.sessions {
+		if m.marked[session.Core.ID] {
+			marked = append(marked, session)
+		}
+	}
+	sortSessions(marked, m.sortOrder)
+	return marked
+}
+
+// confirmBulk asks once for confirmation to run action on all marked sessions,
+// listing the sessions it will run on and those it will skip
+func (m Model) confirmBulk(action bulkAction) tea.Cmd {
+	if action.protected && m.protected() {
+		return func() tea.Msg {
+			return errorMsg{err: fmt.Errorf("protected mode: %s marked sessions one at a time", strings.ToLower(action.verb))}
+		}
+	}
+
+	var targets []types.Session
+	var lines, skipped []string
+	for _, session := range m.markedSessions() {
+		if reason := action.skip(session); reason != "" {
+			skipped = append(skipped, fmt.Sprintf("  • %s (%s)", session.Core.Name, reason))
+			continue
+		}
+		targets = append(targets, session)
+		lines = append(lines, "  • "+session.Core.Name)
+	}
+
+	if len(targets) == 0 {
+		return func() tea.Msg {
+			return errorMsg{err: fmt.Errorf("none of the marked sessions can be %s", strings.ToLower(action.done))}
+		}
+	}
+
+	message := fmt.Sprintf("%s %d marked %s?\n\n%s", action.verb, len(targets), pluralize(len(targets), "session"), strings.Join(lines, "\n"))
+	if len(skipped) > 0 {
+		message += "\n\nSkipping:\n" + strings.Join(skipped, "\n")
+	}
+
+	return func() tea.Msg {
+		return showConfirmDialogMsg{
+			message: message,
+			onYes: func() tea.Cmd {
+				return m.runBulk(action, targets)
+			},
+			onNo: func() tea.Cmd { return nil },
+		}
+	}
+}
+
+// runBulk runs action on each session in turn, carrying on past failures
+func (m Model) runBulk(action bulkAction, targets []types.Session) tea.Cmd {
+	return func() tea.Msg {
+		var failed []string
+		for _, session := range targets {
+			if err := action.run(m, session); err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%v)", session.Core.Name, err))
+			}
+		}
+
+		succeeded := len(targets) - len(failed)
+		if len(failed) > 0 {
+			return bulkCompleteMsg{err: fmt.Errorf("%s %d of %d sessions; failed: %s",
+				action.done, succeeded, len(targets), strings.Join(failed, ", "))}
+		}
+		return bulkCompleteMsg{message: fmt.Sprintf("%s %d %s", action.done, succeeded, pluralize(succeeded, "session"))}
+	}
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return noun
+	}
+	return noun + "s"
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+)
+
+func TestToggleMark(t *testing.T) {
+	m := Model{sessions: filterTestSessions(), sortOrder: config.SortName}
+
+	// Sorted by name: feature-search, fix-login, refactor-db
+	m.selectedIndex = 1
+	m = m.toggleMark()
+	m.selectedIndex = 2
+	m = m.toggleMark()
+
+	var names []string
+	for _, session := range m.markedSessions() {
+		names = append(names, session.Core.Name)
+	}
+	if got := strings.Join(names, ","); got != "fix-login,refactor-db" {
+		t.Errorf("marked sessions = %s, want fix-login,refactor-db", got)
+	}
+
+	m = m.toggleMark()
+	if got := len(m.markedSessions()); got != 1 {
+		t.Errorf("after unmarking, %d sessions marked, want 1", got)
+	}
+
+	// Marks on sessions that no longer exist are ignored
+	m.sessions = m.sessions[1:]
+	if got := len(m.markedSessions()); got != 0 {
+		t.Errorf("after fix-login was removed, %d sessions marked, want 0", got)
+	}
+}
+
+func TestConfirmBulk(t *testing.T) {
+	m := Model{
+		sessions:  filterTestSessions(),
+		sortOrder: config.SortName,
+		marked:    map[string]bool{"1": true, "2": true, "3": true},
+	}
+
+	msg := m.confirmBulk(bulkCleanup)()
+	dialog, ok := msg.(showConfirmDialogMsg)
+	if !ok {
+		t.Fatalf("confirmBulk returned %T, want showConfirmDialogMsg", msg)
+	}
+	if !strings.HasPrefix(dialog.message, "Clean up 1 marked session?") {
+		t.Errorf("message should count only closed sessions, got:\n%s", dialog.message)
+	}
+	for _, want := range []string{"• refactor-db", "• fix-login (still running)", "• feature-search (still running)"} {
+		if !strings.Contains(dialog.message, want) {
+			t.Errorf("message missing %q, got:\n%s", want, dialog.message)
+		}
+	}
+
+	// Nothing eligible reports an error instead of asking
+	m.marked = map[string]bool{"1": true}
+	if _, ok := m.confirmBulk(bulkMerge)().(errorMsg); !ok {
+		t.Error("merging only sessions without changes should report an error")
+	}
+}
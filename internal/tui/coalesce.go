@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+)
+
+// eventCoalescer batches file events before they reach the TUI. Identical
+// events within a burst are delivered once, and a burst ends after a quiet
+// period (debounce) or, during a continuous storm, after maxDelay. Each batch
+// is delivered in the configured priority order.
+type eventCoalescer struct {
+	out chan tea.Msg
+
+	mu         sync.Mutex
+	debounce   time.Duration
+	maxDelay   time.Duration
+	priority   map[string]int
+	pending    []tea.Msg
+	seen       map[tea.Msg]bool
+	burstStart time.Time
+	timer      *time.Timer
+}
+
+func newEventCoalescer(out chan tea.Msg, cfg config.FileEvents) *eventCoalescer {
+	c := &eventCoalescer{
+		out:  out,
+		seen: make(map[tea.Msg]bool),
+	}
+	c.configure(cfg)
+	return c
+}
+
+// configure applies new batching settings, taking effect from the next event
+func (c *eventCoalescer) configure(cfg config.FileEvents) {
+	priority := make(map[string]int, len(cfg.Priority))
+	for i, kind := range cfg.Priority {
+		priority[kind] = i
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.debounce = cfg.Debounce
+	c.maxDelay = cfg.MaxDelay
+	c.priority = priority
+}
+
+// add queues a file event, dropping it if an identical one is already pending
+func (c *eventCoalescer) add(msg tea.Msg) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.pending) == 0 {
+		c.burstStart = now
+	}
+	if !c.seen[msg] {
+		c.seen[msg] = true
+		c.pending = append(c.pending, msg)
+	}
+
+	// Wait for a quiet period, but never past the burst's deadline
+	delay := c.debounce
+	if remaining := c.maxDelay - now.Sub(c.burstStart); remaining < delay {
+		delay = remaining
+	}
+	if delay < 0 {
+		delay = 0
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.timer = time.AfterFunc(delay, c.flush)
+}
+
+// flush delivers the pending batch, highest priority first
+func (c *eventCoalescer) flush() {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.seen = make(map[tea.Msg]bool)
+	priority := c.priority
+	c.mu.Unlock()
+
+	sort.SliceStable(batch, func(i, j int) bool {
+		return rankEvent(priority, batch[i]) < rankEvent(priority, batch[j])
+	})
+
+	for _, msg := range batch {
+		logger.Debug("sending coalesced event", "type", fmt.Sprintf("%T", msg))
+		select {
+		case c.out <- msg:
+		default: // Channel full, skip this event
+			logger.Warn("event channel full, dropping event", "type", fmt.Sprintf("%T", msg))
+		}
+	}
+}
+
+// rankEvent returns the delivery position of a message; unlisted kinds go last
+func rankEvent(priority map[string]int, msg tea.Msg) int {
+	if rank, ok := priority[fileEventKind(msg)]; ok {
+		return rank
+	}
+	return len(priority)
+}
+
+// fileEventKind maps a file event message to its configurable kind
+func fileEventKind(msg tea.Msg) string {
+	switch msg.(type) {
+	case sessionStateChangedMsg:
+		return config.FileEventSessionState
+	case sessionListChangedMsg:
+		return config.FileEventSessionList
+	case refreshRequestedMsg, shutdownRequestedMsg:
+		return config.FileEventRefresh
+	case gitIndexChangedMsg:
+		return config.FileEventGitIndex
+	case dataDirChangedMsg:
+		return config.FileEventDataDir
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+)
+
+func TestEventCoalescer_DeduplicatesAndOrders(t *testing.T) {
+	out := make(chan tea.Msg, 10)
+	coalescer := newEventCoalescer(out, config.FileEvents{
+		Debounce: 20 * time.Millisecond,
+		MaxDelay: time.Second,
+		Priority: config.DefaultEventPriority,
+	})
+
+	// A storm of git index events plus one hook event
+	for i := 0; i < 100; i++ {
+		coalescer.add(gitIndexChangedMsg{sessionID: "a"})
+	}
+	coalescer.add(gitIndexChangedMsg{sessionID: "b"})
+	coalescer.add(dataDirChangedMsg{})
+	coalescer.add(sessionStateChangedMsg{})
+
+	want := []tea.Msg{
+		sessionStateChangedMsg{},
+		gitIndexChangedMsg{sessionID: "a"},
+		gitIndexChangedMsg{sessionID: "b"},
+		dataDirChangedMsg{},
+	}
+	for i, expected := range want {
+		select {
+		case got := <-out:
+			if got != expected {
+				t.Errorf("message %d = %#v, want %#v", i, got, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+	}
+
+	select {
+	case extra := <-out:
+		t.Errorf("unexpected extra message %#v", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventCoalescer_MaxDelay(t *testing.T) {
+	out := make(chan tea.Msg, 10)
+	coalescer := newEventCoalescer(out, config.FileEvents{
+		Debounce: 50 * time.Millisecond,
+		MaxDelay: 100 * time.Millisecond,
+		Priority: config.DefaultEventPriority,
+	})
+
+	// Events arriving faster than the debounce must still be delivered by MaxDelay
+	start := time.Now()
+	done := time.After(300 * time.Millisecond)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-out:
+			if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+				t.Errorf("first delivery after %v, want within max delay", elapsed)
+			}
+			return
+		case <-ticker.C:
+			coalescer.add(dataDirChangedMsg{})
+		case <-done:
+			t.Fatal("continuous events were never delivered")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,7 +14,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fsnotify/fsnotify"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/clipboard"
+	"github.com/jlaneve/cwt-cli/internal/daemon"
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/jlaneve/cwt-cli/internal/utils"
 )
 
 // startEventChannelListener creates a command that listens for file events
@@ -32,39 +40,28 @@ func (m Model) setupFileWatching() tea.Cmd {
 			return errorMsg{err: fmt.Errorf("failed to create file watcher: %w", err)}
 		}
 
-		// Watch session state directory for hook events
-		sessionStateDir := filepath.Join(m.stateManager.GetDataDir(), "session-state")
-		if err := os.MkdirAll(sessionStateDir, 0755); err == nil {
-			if err := watcher.Add(sessionStateDir); err != nil {
-				return errorMsg{err: fmt.Errorf("failed to watch session state directory: %w", err)}
-			}
-			if debugLogger != nil {
-				debugLogger.Printf("Watching session state directory: %s", sessionStateDir)
-			}
-		}
+		dataDir := m.stateManager.GetDataDir()
 
-		// Watch sessions.json for session CRUD
-		sessionsFile := filepath.Join(m.stateManager.GetDataDir(), "sessions.json")
-		if _, err := os.Stat(sessionsFile); err == nil {
-			if err := watcher.Add(sessionsFile); err != nil {
-				return errorMsg{err: fmt.Errorf("failed to watch sessions file: %w", err)}
-			}
-			if debugLogger != nil {
-				debugLogger.Printf("Watching sessions file: %s", sessionsFile)
-			}
+		// Ensure the session state directory exists so hook events are seen
+		// even before the first hook fires
+		os.MkdirAll(filepath.Join(dataDir, "session-state"), 0755)
+
+		// Watch the whole data directory: sessions.json, session-state,
+		// the refresh broadcast file and anything CLI commands write there
+		if err := addDataDirWatches(watcher, dataDir, dataDir); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to watch data directory: %w", err)}
 		}
+		logger.Debug("watching data directory", "path", dataDir)
 
 		// Watch git index files for each session
 		for _, session := range m.sessions {
 			m.addSessionWatches(watcher, session)
-			if debugLogger != nil {
-				gitIndexPath := filepath.Join(session.Core.WorktreePath, ".git", "index")
-				debugLogger.Printf("Watching git index for session %s: %s", session.Core.Name, gitIndexPath)
-			}
+			logger.Debug("watching git index", "session", session.Core.Name, "path", filepath.Join(session.Core.WorktreePath, ".git", "index"))
 		}
 
 		// Store the eventChan in the watcher context
 		eventChan := m.eventChan
+		coalescer := newEventCoalescer(eventChan, m.config.FileEvents)
 
 		// Start listening for file events
 		go func() {
@@ -76,59 +73,18 @@ func (m Model) setupFileWatching() tea.Cmd {
 					}
 
 					// Debug logging for file events
-					if debugLogger != nil {
-						debugLogger.Printf("File event: %s %s", event.Op, event.Name)
+					logger.Debug("file event", "op", event.Op.String(), "path", event.Name)
+
+					// New directories inside the data dir need their own watches
+					if event.Has(fsnotify.Create) {
+						if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+							addDataDirWatches(watcher, dataDir, event.Name)
+						}
 					}
 
 					// Determine event type based on file path and send appropriate message
-					if filepath.Base(filepath.Dir(event.Name)) == "session-state" {
-						// Session state change (hook event)
-						go func() {
-							time.Sleep(100 * time.Millisecond) // Debounce
-							if debugLogger != nil {
-								debugLogger.Printf("Sending sessionStateChangedMsg for: %s", event.Name)
-							}
-							select {
-							case eventChan <- sessionStateChangedMsg{}:
-							default: // Channel full, skip this event
-								if debugLogger != nil {
-									debugLogger.Printf("Event channel full, skipping sessionStateChangedMsg")
-								}
-							}
-						}()
-					} else if filepath.Base(event.Name) == "sessions.json" {
-						// Session list change
-						go func() {
-							time.Sleep(100 * time.Millisecond) // Debounce
-							if debugLogger != nil {
-								debugLogger.Printf("Sending sessionListChangedMsg for: %s", event.Name)
-							}
-							select {
-							case eventChan <- sessionListChangedMsg{}:
-							default: // Channel full, skip this event
-								if debugLogger != nil {
-									debugLogger.Printf("Event channel full, skipping sessionListChangedMsg")
-								}
-							}
-						}()
-					} else if filepath.Base(event.Name) == "index" {
-						// Git index change
-						sessionID := m.getSessionIDFromPath(event.Name)
-						if sessionID != "" {
-							go func(sID string) {
-								time.Sleep(100 * time.Millisecond) // Debounce
-								if debugLogger != nil {
-									debugLogger.Printf("Sending gitIndexChangedMsg for session: %s", sID)
-								}
-								select {
-								case eventChan <- gitIndexChangedMsg{sessionID: sID}:
-								default: // Channel full, skip this event
-									if debugLogger != nil {
-										debugLogger.Printf("Event channel full, skipping gitIndexChangedMsg")
-									}
-								}
-							}(sessionID)
-						}
+					if msg := m.classifyFileEvent(dataDir, event.Name); msg != nil {
+						coalescer.add(msg)
 					}
 
 				case err, ok := <-watcher.Errors:
@@ -145,8 +101,75 @@ func (m Model) setupFileWatching() tea.Cmd {
 		}()
 
 		// Return the watcher setup message so the model can store it
-		return fileWatcherSetupMsg{watcher: watcher}
+		return fileWatcherSetupMsg{watcher: watcher, coalescer: coalescer}
+	}
+}
+
+// addDataDirWatches watches root and all directories below it, skipping
+// the worktrees directory (worktrees are tracked through their git index)
+func addDataDirWatches(watcher *fsnotify.Watcher, dataDir, root string) error {
+	worktreesDir := filepath.Join(dataDir, "worktrees")
+
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if filepath.Clean(path) == filepath.Clean(worktreesDir) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// classifyFileEvent maps a changed file to the TUI message that refreshes it,
+// or nil if the change is irrelevant
+func (m Model) classifyFileEvent(dataDir, path string) tea.Msg {
+	base := filepath.Base(path)
+
+	// Ignore temp files from atomic writes; the rename produces its own event.
+	// The status cache, usage file, daemon socket, lock files and log never
+	// describe session changes, and every hook event appended to a log is
+	// followed by a snapshot write.
+	if strings.HasSuffix(base, ".tmp") || strings.HasSuffix(base, ".lock") || base == state.StatusCacheFileName ||
+		base == state.UsageFileName || base == daemon.SocketFileName || base == LogFileName ||
+		base == types.SessionEventLogName {
+		return nil
+	}
+
+	switch {
+	case base == "index":
+		// Git index change
+		if sessionID := m.getSessionIDFromPath(path); sessionID != "" {
+			return gitIndexChangedMsg{sessionID: sessionID}
+		}
+		return nil
+
+	case base == "sessions.json":
+		// Session list change
+		return sessionListChangedMsg{}
+
+	case base == state.RefreshFileName:
+		// Explicit refresh broadcast from another cwt process
+		signal, err := state.ReadRefreshSignal(dataDir)
+		if err == nil && signal != nil && signal.Reason == state.RefreshShutdown {
+			return shutdownRequestedMsg{}
+		}
+		if err != nil || signal == nil || signal.Session == "" {
+			return sessionListChangedMsg{}
+		}
+		return refreshRequestedMsg{sessionName: signal.Session}
+
+	case filepath.Base(filepath.Dir(path)) == "session-state":
+		// Session state change (hook event)
+		return sessionStateChangedMsg{}
 	}
+
+	// Any other change inside the data directory (notes, events, ...)
+	if rel, err := filepath.Rel(dataDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return dataDirChangedMsg{}
+	}
+
+	return nil
 }
 
 // Helper to add git index watching for a session
@@ -178,40 +201,47 @@ func (m Model) getSessionIDFromPath(path string) string {
 
 // Polling commands
 func (m Model) startGitPolling() tea.Cmd {
-	return tea.Every(10*time.Second, func(time.Time) tea.Msg {
+	return tea.Every(m.config.Polling.GitInterval, func(time.Time) tea.Msg {
 		return gitStatusRefreshMsg{}
 	})
 }
 
 func (m Model) startTmuxPolling() tea.Cmd {
-	return tea.Every(30*time.Second, func(time.Time) tea.Msg {
+	return tea.Every(m.config.Polling.TmuxInterval, func(time.Time) tea.Msg {
 		return tmuxStatusRefreshMsg{}
 	})
 }
 
 // Session management commands
 func (m Model) refreshSessions() tea.Cmd {
+	return m.deriveTimed
+}
+
+// forceRefreshSessions re-derives every session, bypassing the status cache
+func (m Model) forceRefreshSessions() tea.Cmd {
 	return func() tea.Msg {
-		sessions, err := m.stateManager.DeriveFreshSessions()
-		if err != nil {
-			return errorMsg{err: fmt.Errorf("failed to refresh sessions: %w", err)}
-		}
-		return refreshCompleteMsg{sessions: sessions}
+		m.stateManager.InvalidateStatus("")
+		return m.deriveTimed()
 	}
 }
 
-func (m Model) refreshSessionGitStatus(sessionID string) tea.Cmd {
+// refreshSession re-derives a single session without touching the others.
+// It is triggered by a known change, so the session's cached status is dropped.
+func (m Model) refreshSession(sessionID string) tea.Cmd {
 	return func() tea.Msg {
-		// Refresh just git status for specific session
-		// For now, refresh all sessions (optimize later)
-		sessions, err := m.stateManager.DeriveFreshSessions()
+		m.stateManager.InvalidateStatus(sessionID)
+		session, err := m.stateManager.DeriveSession(sessionID)
 		if err != nil {
-			return errorMsg{err: fmt.Errorf("failed to refresh git status: %w", err)}
+			return errorMsg{err: fmt.Errorf("failed to refresh session: %w", err)}
 		}
-		return refreshCompleteMsg{sessions: sessions}
+		return sessionRefreshedMsg{session: session}
 	}
 }
 
+func (m Model) refreshSessionGitStatus(sessionID string) tea.Cmd {
+	return m.refreshSession(sessionID)
+}
+
 func (m Model) refreshAllGitStatus() tea.Cmd {
 	return m.refreshSessions() // For now, just refresh everything
 }
@@ -224,29 +254,21 @@ func (m Model) refreshTmuxStatus() tea.Cmd {
 func (m Model) handleAttach(sessionID string) tea.Cmd {
 	// Get access to the logger from model.go
 	return func() tea.Msg {
-		if debugLogger != nil {
-			debugLogger.Printf("handleAttach: Called with sessionID: %s", sessionID)
-		}
+		logger.Debug("attach requested", "session_id", sessionID)
 
 		session := m.findSession(sessionID)
 		if session == nil {
-			if debugLogger != nil {
-				debugLogger.Printf("handleAttach: Session not found for ID: %s", sessionID)
-			}
+			logger.Debug("attach: session not found", "session_id", sessionID)
 			return errorMsg{err: fmt.Errorf("session not found")}
 		}
 
-		if debugLogger != nil {
-			debugLogger.Printf("handleAttach: Found session: %s, IsAlive: %v", session.Core.Name, session.IsAlive)
-		}
+		logger.Debug("attach: found session", "session", session.Core.Name, "alive", session.IsAlive)
 
 		if !session.IsAlive {
-			if debugLogger != nil {
-				debugLogger.Printf("handleAttach: Session %s is dead, showing confirmation dialog", session.Core.Name)
-			}
+			logger.Debug("attach: session is dead, asking to recreate it", "session", session.Core.Name)
 			// Return a command to show confirmation dialog
 			return showConfirmDialogMsg{
-				message: fmt.Sprintf("Session '%s' tmux is not running. Recreate it?", session.Core.Name),
+				message: recreatePrompt(*session),
 				onYes: func() tea.Cmd {
 					return m.recreateAndAttach(sessionID)
 				},
@@ -256,14 +278,20 @@ func (m Model) handleAttach(sessionID string) tea.Cmd {
 			}
 		}
 
-		if debugLogger != nil {
-			debugLogger.Printf("handleAttach: Session %s is alive, proceeding to attach", session.Core.Name)
-		}
+		logger.Debug("attach: session is alive", "session", session.Core.Name)
 		// Attach to alive session
 		return m.attachToSession(sessionID)
 	}
 }
 
+// recreatePrompt asks whether to restart a session that isn't running
+func recreatePrompt(session types.Session) string {
+	if session.Core.IsPaused() {
+		return fmt.Sprintf("Session '%s' is paused. Resume it?", session.Core.Name)
+	}
+	return fmt.Sprintf("Session '%s' tmux is not running. Recreate it?", session.Core.Name)
+}
+
 func (m Model) recreateAndAttach(sessionID string) tea.Cmd {
 	return func() tea.Msg {
 		session := m.findSession(sessionID)
@@ -271,32 +299,18 @@ func (m Model) recreateAndAttach(sessionID string) tea.Cmd {
 			return errorMsg{err: fmt.Errorf("session not found")}
 		}
 
-		// Recreate the tmux session directly (worktree already exists)
-		// Find claude executable
-		claudeExec := m.findClaudeExecutable()
-		var command string
-		if claudeExec != "" {
-			// Check if there's an existing Claude session to resume for this worktree
-			if existingSessionID, err := m.stateManager.GetClaudeChecker().FindSessionID(session.Core.WorktreePath); err == nil && existingSessionID != "" {
-				command = fmt.Sprintf("%s -r %s", claudeExec, existingSessionID)
-				if debugLogger != nil {
-					debugLogger.Printf("Resuming Claude session %s for worktree %s", existingSessionID, session.Core.WorktreePath)
-				}
-			} else {
-				command = claudeExec
-				if debugLogger != nil {
-					debugLogger.Printf("Starting new Claude session for worktree %s", session.Core.WorktreePath)
-				}
+		// A paused session continues the conversation captured when it was paused
+		if session.Core.IsPaused() {
+			if err := m.stateManager.ResumeSession(sessionID); err != nil {
+				return errorMsg{err: fmt.Errorf("failed to resume session: %w", err)}
 			}
+			return attachRequestMsg{sessionName: session.Core.TmuxSession}
 		}
 
-		// Create new tmux session
-		if err := m.stateManager.GetTmuxChecker().CreateSession(
-			session.Core.TmuxSession,
-			session.Core.WorktreePath,
-			command,
-		); err != nil {
-			return errorMsg{err: fmt.Errorf("failed to recreate tmux session: %w", err)}
+		// Recreate the tmux session directly (worktree already exists),
+		// resuming Claude's conversation if it has one
+		if err := m.stateManager.RestartSession(sessionID); err != nil {
+			return errorMsg{err: err}
 		}
 
 		// Now request attachment
@@ -304,23 +318,28 @@ func (m Model) recreateAndAttach(sessionID string) tea.Cmd {
 	}
 }
 
+// recordAttach records attaching to a tmux session in the timeline of the
+// session it belongs to
+func (m Model) recordAttach(tmuxSession string) {
+	for _, session := range m.sessions {
+		if session.Core.TmuxSession == tmuxSession {
+			m.stateManager.RecordEvent(session.Core.ID, types.EventAttached, "Attached from the dashboard", nil)
+			return
+		}
+	}
+}
+
 func (m Model) attachToSession(sessionID string) tea.Cmd {
 	return func() tea.Msg {
-		if debugLogger != nil {
-			debugLogger.Printf("attachToSession: Called with sessionID: %s", sessionID)
-		}
+		logger.Debug("attachToSession called", "session_id", sessionID)
 
 		session := m.findSession(sessionID)
 		if session == nil {
-			if debugLogger != nil {
-				debugLogger.Printf("attachToSession: Session not found for ID: %s", sessionID)
-			}
+			logger.Debug("attachToSession: session not found", "session_id", sessionID)
 			return errorMsg{err: fmt.Errorf("session not found")}
 		}
 
-		if debugLogger != nil {
-			debugLogger.Printf("attachToSession: Returning attachRequestMsg for tmux session: %s", session.Core.TmuxSession)
-		}
+		logger.Debug("attachToSession: attaching", "tmux_session", session.Core.TmuxSession)
 
 		// Return a special message that tells the TUI to exit and attach
 		return attachRequestMsg{sessionName: session.Core.TmuxSession}
@@ -336,34 +355,85 @@ func (m Model) confirmDelete(sessionID string) tea.Cmd {
 			return errorMsg{err: fmt.Errorf("session not found")}
 		}
 
+		keepBranch := &ConfirmToggle{Key: "b", Label: "Keep the branch even if it is merged"}
 		return showConfirmDialogMsg{
-			message: fmt.Sprintf("Delete session '%s' and all its resources?", session.Core.Name),
+			message: fmt.Sprintf("Delete session '%s' and all its resources?\nIts branch is deleted only if it is merged.", session.Core.Name),
 			onYes: func() tea.Cmd {
-				return m.deleteSession(sessionID)
+				return m.deleteSession(sessionID, state.DeleteOptions{KeepBranch: keepBranch.On})
 			},
 			onNo: func() tea.Cmd {
 				return nil
 			},
+			toggle: keepBranch,
 		}
 	}
 }
 
-func (m Model) deleteSession(sessionID string) tea.Cmd {
+// deleteSession deletes a session. When it has work that isn't merged, it
+// asks again, listing that work, and deletes it only once confirmed.
+func (m Model) deleteSession(sessionID string, opts state.DeleteOptions) tea.Cmd {
 	return func() tea.Msg {
-		if err := m.stateManager.DeleteSession(sessionID); err != nil {
-			return errorMsg{err: fmt.Errorf("failed to delete session: %w", err)}
+		name := sessionID
+		if session := m.findSession(sessionID); session != nil {
+			name = session.Core.Name
 		}
 
-		// Refresh session list after deletion
-		sessions, err := m.stateManager.DeriveFreshSessions()
+		result, err := m.stateManager.DeleteSessionWithOptions(sessionID, opts)
+		var unmerged *state.UnmergedWorkError
+		if errors.As(err, &unmerged) {
+			forced := opts
+			forced.Force = true
+			return showConfirmDialogMsg{
+				message: unmergedWorkMessage(unmerged),
+				onYes: func() tea.Cmd {
+					return m.deleteSession(sessionID, forced)
+				},
+				onNo: func() tea.Cmd {
+					return nil
+				},
+			}
+		}
 		if err != nil {
-			return errorMsg{err: fmt.Errorf("failed to refresh after deletion: %w", err)}
+			return errorMsg{err: fmt.Errorf("failed to delete session: %w", err)}
 		}
 
-		return refreshCompleteMsg{sessions: sessions}
+		// The toast refreshes the session list
+		message := fmt.Sprintf("Deleted '%s'", name)
+		switch {
+		case result.BranchDeleted:
+			message += fmt.Sprintf(" and its merged branch '%s'", result.Branch)
+		case result.BranchUnmerged:
+			message += fmt.Sprintf(", keeping unmerged branch '%s'", result.Branch)
+		case opts.KeepBranch:
+			message += fmt.Sprintf(", keeping branch '%s'", result.Branch)
+		}
+		return successToastMsg{message: message}
 	}
 }
 
+// unmergedWorkMessage asks whether to delete a session despite its work that
+// isn't merged, saying what becomes of that work
+func unmergedWorkMessage(err *state.UnmergedWorkError) string {
+	lines := []string{fmt.Sprintf("'%s' has work not merged into %s:", err.Session, err.BaseBranch), ""}
+	if files := err.Work.Uncommitted; len(files) > 0 {
+		lines = append(lines, fmt.Sprintf("%d uncommitted change(s), which will be lost:", len(files)))
+		lines = append(lines, limitedList(files)...)
+	}
+	if err.Work.Unchecked != "" {
+		lines = append(lines, "Its worktree can't be checked for uncommitted changes, which would be lost:", "  "+err.Work.Unchecked)
+	}
+	if commits := err.Work.Commits; len(commits) > 0 {
+		subjects := make([]string, len(commits))
+		for i, commit := range commits {
+			subjects[i] = fmt.Sprintf("%s %s", shortHash(commit.Hash), commit.Subject)
+		}
+		lines = append(lines, fmt.Sprintf("%d unmerged commit(s), kept on branch '%s':", len(commits), err.Work.Branch))
+		lines = append(lines, limitedList(subjects)...)
+	}
+	lines = append(lines, "", "Delete it anyway?")
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) runCleanup() tea.Cmd {
 	return func() tea.Msg {
 		// Find and clean up stale sessions
@@ -393,24 +463,239 @@ func (m Model) runCleanup() tea.Cmd {
 	}
 }
 
-// findClaudeExecutable searches for claude in common installation paths
-func (m Model) findClaudeExecutable() string {
-	// Check common installation paths
-	claudePaths := []string{
-		"claude",
-		os.ExpandEnv("$HOME/.claude/local/claude"),
-		os.ExpandEnv("$HOME/.claude/local/node_modules/.bin/claude"),
-		"/usr/local/bin/claude",
+// Toast quick actions
+
+// toastDuration returns how long a toast stays visible
+func toastDuration(action *ToastAction) time.Duration {
+	if action != nil {
+		return ToastActionDuration
+	}
+	return 3 * time.Second
+}
+
+// attachSessionAction offers to attach to a newly created session
+func attachSessionAction(name string) *ToastAction {
+	return &ToastAction{
+		Label: "attach",
+		Run: func(m Model) (Model, tea.Cmd) {
+			for _, session := range m.sessions {
+				if session.Core.Name != name {
+					continue
+				}
+				if !session.IsAlive {
+					return m, m.recreateAndAttach(session.Core.ID)
+				}
+				m.attachOnExit = session.Core.TmuxSession
+				return m, tea.Quit
+			}
+			return m, func() tea.Msg {
+				return errorMsg{err: fmt.Errorf("session '%s' not found", name)}
+			}
+		},
+	}
+}
+
+// retryCreateSessionAction reopens the new session dialog with the failed name
+func retryCreateSessionAction(name string) *ToastAction {
+	return &ToastAction{
+		Label: "retry",
+		Run: func(m Model) (Model, tea.Cmd) {
+			m.newSessionDialog = &NewSessionDialog{NameInput: name}
+			return m, nil
+		},
+	}
+}
+
+// deleteSessionAction offers to delete a session whose work has been merged
+func deleteSessionAction(sessionID string) *ToastAction {
+	return &ToastAction{
+		Label: "delete session",
+		Run: func(m Model) (Model, tea.Cmd) {
+			return m, m.confirmDelete(sessionID)
+		},
+	}
+}
+
+// copyPRURLAction offers to copy the pull request URL of a published session
+func copyPRURLAction(sessionID string) *ToastAction {
+	return &ToastAction{
+		Label: "copy PR URL",
+		Run: func(m Model) (Model, tea.Cmd) {
+			session := m.findSession(sessionID)
+			if session == nil {
+				return m, func() tea.Msg {
+					return errorMsg{err: fmt.Errorf("session not found")}
+				}
+			}
+			return m, m.copySessionPRURL(*session)
+		},
+	}
+}
+
+// viewConflictsAction offers to list the conflicted files of a failed merge
+func viewConflictsAction() *ToastAction {
+	return &ToastAction{
+		Label: "view conflicts",
+		Run: func(m Model) (Model, tea.Cmd) {
+			return m, m.showMergeConflicts()
+		},
+	}
+}
+
+// showMergeConflicts lists unmerged files and offers to abort the merge
+func (m Model) showMergeConflicts() tea.Cmd {
+	return func() tea.Msg {
+		output, err := exec.Command("git", "diff", "--name-only", "--diff-filter=U").Output()
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to list conflicts: %w", err)}
+		}
+
+		files := strings.Fields(strings.TrimSpace(string(output)))
+		if len(files) == 0 {
+			return successToastMsg{message: "No merge conflicts remaining"}
+		}
+
+		var lines []string
+		lines = append(lines, fmt.Sprintf("Merge conflicts in %d file(s):", len(files)))
+		for _, file := range files {
+			lines = append(lines, "  "+file)
+		}
+		lines = append(lines, "")
+		lines = append(lines, "Abort the merge?")
+
+		return showConfirmDialogMsg{
+			message: strings.Join(lines, "\n"),
+			onYes: func() tea.Cmd {
+				return func() tea.Msg {
+					// cwt undoes its own merges, squash merges included;
+					// git aborts any other
+					if err := utils.ExecuteCWTCommand("merge", "--abort"); err != nil {
+						if err := executeCommand("git", "merge", "--abort"); err != nil {
+							return errorMsg{err: fmt.Errorf("failed to abort merge: %w", err)}
+						}
+					}
+					return successToastMsg{message: "Merge aborted"}
+				}
+			},
+			onNo: func() tea.Cmd { return nil },
+		}
 	}
+}
 
-	for _, path := range claudePaths {
-		cmd := exec.Command(path, "--version")
-		if err := cmd.Run(); err == nil {
-			return path
+// Clipboard commands
+
+// sendPrompt types a prompt into the session's Claude pane as if the user
+// had attached and entered it
+func (m Model) sendPrompt(session types.Session, prompt string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.stateManager.GetTmuxChecker().SendKeys(session.Core.TmuxSession, prompt); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to send prompt: %w", err)}
 		}
+		return successToastMsg{message: fmt.Sprintf("Sent prompt to '%s'", session.Core.Name)}
 	}
+}
 
-	return ""
+// openSessionWindow opens a session as a window of the tmux session the
+// dashboard runs in, so the dashboard keeps running alongside it
+func (m Model) openSessionWindow(session types.Session) tea.Cmd {
+	return func() tea.Msg {
+		err := m.stateManager.GetTmuxChecker().OpenWindow(session.Core.TmuxSession)
+		if errors.Is(err, tmux.ErrNotInTmux) {
+			return errorMsg{err: fmt.Errorf("opening a session in a window needs cwt to run inside tmux; press enter to attach instead")}
+		}
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return successToastMsg{message: fmt.Sprintf("Opened '%s' in a tmux window", session.Core.Name)}
+	}
+}
+
+// copyText copies text to the clipboard and reports the result
+func copyText(label, text string) tea.Msg {
+	if err := clipboard.Copy(text); err != nil {
+		return errorMsg{err: fmt.Errorf("failed to copy %s: %w", label, err)}
+	}
+	return copiedMsg{label: label}
+}
+
+// copySessionPath copies the absolute worktree path of a session
+func (m Model) copySessionPath(session types.Session) tea.Cmd {
+	return func() tea.Msg {
+		path, err := filepath.Abs(session.Core.WorktreePath)
+		if err != nil {
+			path = session.Core.WorktreePath
+		}
+		return copyText("worktree path", path)
+	}
+}
+
+// copySessionBranch copies the branch checked out in the session worktree
+func (m Model) copySessionBranch(session types.Session) tea.Cmd {
+	return func() tea.Msg {
+		return copyText("branch name", session.BranchName())
+	}
+}
+
+// copySessionPRURL copies the pull request URL for the session branch
+func (m Model) copySessionPRURL(session types.Session) tea.Cmd {
+	return func() tea.Msg {
+		url, err := operations.GetPullRequestURL(session.Core.WorktreePath, session.BranchName(), m.config.Forge)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return copyText("PR URL", url)
+	}
+}
+
+// copyCurrentHunk copies the diff hunk at the top of the diff view as a patch
+func (m Model) copyCurrentHunk() tea.Cmd {
+	if m.diffMode == nil || len(m.diffMode.diffLines) == 0 {
+		return nil
+	}
+
+	patch := extractHunkPatch(m.diffMode.visibleDiffLines(), m.diffMode.scrollOffset)
+	return func() tea.Msg {
+		if patch == "" {
+			return errorMsg{err: fmt.Errorf("no diff hunk in view")}
+		}
+		return copyText("diff hunk", patch)
+	}
+}
+
+// extractHunkPatch returns the hunk containing the line at index, prefixed
+// with its file headers so the result can be applied with git apply
+func extractHunkPatch(lines []DiffLine, index int) string {
+	if index < 0 || index >= len(lines) {
+		return ""
+	}
+
+	// When positioned on file headers, use the first hunk that follows
+	for index < len(lines) && lines[index].HunkID == 0 {
+		index++
+	}
+	if index >= len(lines) {
+		return ""
+	}
+
+	hunkID := lines[index].HunkID
+	var headers, hunk []string
+	for _, line := range lines {
+		if line.HunkID == hunkID {
+			hunk = append(hunk, line.Content)
+		}
+	}
+
+	// Collect the file headers preceding this hunk
+	for i := index; i >= 0; i-- {
+		if lines[i].Type == DiffLineFileHeader {
+			for j := i; j < len(lines) && lines[j].Type != DiffLineHunkHeader; j++ {
+				headers = append(headers, lines[j].Content)
+			}
+			break
+		}
+	}
+
+	return strings.Join(append(headers, hunk...), "\n") + "\n"
 }
 
 // loadDiffData loads diff data for the current session
@@ -439,10 +724,14 @@ func (m Model) loadDiffData() tea.Cmd {
 
 		// Build git diff command
 		var cmd *exec.Cmd
-		if m.diffMode.cached {
-			cmd = exec.Command("git", "diff", "--cached", "--no-color")
-		} else {
-			cmd = exec.Command("git", "diff", m.diffMode.target, "--no-color")
+		switch m.diffMode.view {
+		case diffViewUnstaged:
+			cmd = exec.Command("git", append([]string{"diff", "--no-color"}, git.WorkPathspec(".")...)...)
+		case diffViewStaged:
+			cmd = exec.Command("git", append([]string{"diff", "--cached", "--no-color"}, git.WorkPathspec(".")...)...)
+		default:
+			args := append([]string{"diff"}, m.diffMode.mode.Args(m.diffMode.target)...)
+			cmd = exec.Command("git", append(append(args, "--no-color"), git.WorkPathspec(".")...)...)
 		}
 
 		output, err := cmd.Output()
@@ -452,6 +741,13 @@ func (m Model) loadDiffData() tea.Cmd {
 
 		// Parse diff output into DiffLine structures
 		diffLines := parseDiffOutput(string(output))
+
+		// What was shown counts as reviewed, so later changes stand out
+		if m.diffMode.view == diffViewTarget {
+			if err := m.stateManager.MarkReviewed(m.diffMode.session.Core.ID); err != nil {
+				logger.Debug("failed to mark session reviewed", "session", m.diffMode.session.Core.Name, "error", err)
+			}
+		}
 		return diffLoadedMsg{diffLines: diffLines}
 	}
 }
@@ -484,6 +780,7 @@ func parseDiffOutput(output string) []DiffLine {
 			if len(parts) >= 4 {
 				currentFile = strings.TrimPrefix(parts[3], "b/")
 			}
+			diffLine.FileName = currentFile
 
 		case strings.HasPrefix(line, "index "):
 			diffLine.Type = DiffLineHeader
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestOpenSessionWindow(t *testing.T) {
+	tmuxChecker := tmux.NewMockChecker()
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmuxChecker,
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	tmuxChecker.SetAlive("cwt-live", true)
+	m := Model{stateManager: sm, sessions: []types.Session{
+		{Core: types.CoreSession{ID: "1", Name: "live", TmuxSession: "cwt-live"}, IsAlive: true},
+		{Core: types.CoreSession{ID: "2", Name: "gone", TmuxSession: "cwt-gone"}},
+	}}
+	open := func(m Model) (Model, tea.Cmd) {
+		return m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	}
+
+	m, cmd := open(m)
+	if msg, ok := cmd().(successToastMsg); !ok || !strings.Contains(msg.message, "live") {
+		t.Errorf("opening a live session = %#v, want a success toast", msg)
+	}
+	if len(tmuxChecker.OpenedWindows) != 1 || tmuxChecker.OpenedWindows[0] != "cwt-live" {
+		t.Errorf("opened windows = %v, want cwt-live", tmuxChecker.OpenedWindows)
+	}
+
+	// Outside tmux there is no window to open it in
+	tmuxChecker.NotInTmux = true
+	_, cmd = open(m)
+	if msg, ok := cmd().(errorMsg); !ok || !strings.Contains(msg.err.Error(), "inside tmux") {
+		t.Errorf("opening outside tmux = %#v, want an error suggesting attach", msg)
+	}
+
+	m.selectedIndex = 1
+	m, cmd = open(m)
+	if cmd != nil || !strings.Contains(m.lastError, "not running") {
+		t.Errorf("opening a dead session: cmd = %v, error = %q", cmd, m.lastError)
+	}
+}
+
+func TestConfirmDialogToggle(t *testing.T) {
+	keepBranch := &ConfirmToggle{Key: "b", Label: "Keep the branch"}
+	m := Model{confirmDialog: &ConfirmDialog{Message: "Delete?", Toggle: keepBranch}}
+	press := func(m Model, key string) Model {
+		m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return m
+	}
+
+	m = press(m, "b")
+	if !keepBranch.On {
+		t.Fatal("pressing the toggle's key should switch it on")
+	}
+	if view := m.renderWithConfirmDialog(""); !strings.Contains(view, "[x] Keep the branch (b)") {
+		t.Errorf("dialog = %q, want the checked toggle", view)
+	}
+	if m = press(m, "b"); keepBranch.On {
+		t.Error("pressing the toggle's key again should switch it off")
+	}
+	if m = press(m, "x"); keepBranch.On || m.confirmDialog == nil {
+		t.Error("other keys should leave the toggle and dialog alone")
+	}
+}
+
+func TestDeleteSession_UnmergedWork(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	if err := sm.CreateSession("dirty"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := sm.CoreSessions()
+	gitChecker.Statuses[cores[0].WorktreePath] = types.GitStatus{HasChanges: true, ModifiedFiles: []string{"auth.go"}}
+	m := Model{stateManager: sm, sessions: []types.Session{{Core: cores[0]}}}
+
+	// The first attempt asks again, listing what would be lost
+	msg, ok := m.deleteSession(cores[0].ID, state.DeleteOptions{})().(showConfirmDialogMsg)
+	if !ok || !strings.Contains(msg.message, "auth.go") {
+		t.Fatalf("deleteSession() = %#v, want a confirmation listing the uncommitted file", msg)
+	}
+	if remaining, _ := sm.CoreSessions(); len(remaining) != 1 {
+		t.Fatal("the session should be kept until the deletion is confirmed")
+	}
+
+	if result, ok := msg.onYes()().(successToastMsg); !ok || !strings.Contains(result.message, "dirty") {
+		t.Errorf("confirming = %#v, want the session deleted", result)
+	}
+	if remaining, _ := sm.CoreSessions(); len(remaining) != 0 {
+		t.Error("confirming should delete the session")
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// handleCommitDialogKeys handles keyboard input for the commit message dialog
+func (m Model) handleCommitDialogKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	dialog := m.commitDialog
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.commitDialog = nil
+		return m, nil
+
+	case tea.KeyEnter:
+		message := strings.TrimSpace(dialog.Input)
+		if message == "" {
+			dialog.Error = "Commit message is required"
+			return m, nil
+		}
+		m.commitDialog = nil
+		return m, m.commitStaged(message)
+
+	case tea.KeyBackspace:
+		if runes := []rune(dialog.Input); len(runes) > 0 {
+			dialog.Input = string(runes[:len(runes)-1])
+		}
+		dialog.Error = ""
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		// Runes may hold several characters when text is pasted
+		dialog.Input += string(msg.Runes)
+		dialog.Error = ""
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// commitStaged commits the changes staged in the diff view's session worktree
+func (m Model) commitStaged(message string) tea.Cmd {
+	if m.diffMode == nil {
+		return nil
+	}
+	session := m.diffMode.session
+	sm := m.stateManager
+	checker := sm.GetGitChecker()
+
+	return func() tea.Msg {
+		if err := checker.CommitStaged(session.Core.WorktreePath, message); err != nil {
+			return errorMsg{err: err}
+		}
+		sm.RecordEvent(session.Core.ID, types.EventCommitted, message, nil)
+		return diffCommittedMsg{message: fmt.Sprintf("Committed staged changes in '%s'", session.Core.Name)}
+	}
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestCommitDialog(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	m := Model{stateManager: sm, showDiffMode: true}
+	m.diffMode = &DiffMode{
+		session: types.Session{Core: types.CoreSession{Name: "feature", WorktreePath: "/wt"}},
+	}
+
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if m.commitDialog == nil {
+		t.Fatal("C should open the commit dialog")
+	}
+
+	// An empty message is refused and keeps the dialog open
+	m, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || m.commitDialog == nil || m.commitDialog.Error == "" {
+		t.Fatalf("an empty message should be refused, got dialog %#v", m.commitDialog)
+	}
+
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Fix tests")})
+	m, cmd = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.commitDialog != nil {
+		t.Error("submitting should close the dialog")
+	}
+	if msg, ok := cmd().(diffCommittedMsg); !ok || msg.message != "Committed staged changes in 'feature'" {
+		t.Errorf("commit = %#v, want a success message", msg)
+	}
+
+	gitChecker.ShouldFail["/wt"] = true
+	if _, ok := m.commitStaged("Fix tests")().(errorMsg); !ok {
+		t.Error("a git failure should be reported")
+	}
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// detailWheelStep is how many lines the mouse wheel scrolls the detail panel
+const detailWheelStep = 3
+
+// detailPanelSize returns the size of the right panel, matching the layout
+// computed by View and renderMiddlePanel
+func (m Model) detailPanelSize() (width, height int) {
+	height = m.height - 5 - 1
+	if m.lastError != "" || m.successMessage != "" {
+		height -= 2
+	}
+	return m.width - 40 - 1, height
+}
+
+// wrapDetailLines wraps detail content to the panel's inner width, so each
+// returned row is exactly one line on screen
+func wrapDetailLines(lines []string, width int) []string {
+	inner := width - 2 // Padding on both sides
+	if inner < 1 {
+		return lines
+	}
+	wrapped := lipgloss.NewStyle().Width(inner).Render(strings.Join(lines, "\n"))
+	return strings.Split(wrapped, "\n")
+}
+
+// detailOffset returns how far the detail panel is scrolled. The offset
+// belongs to the session it was scrolled on, so selecting another session
+// starts back at the top.
+func (m Model) detailOffset() int {
+	if m.detailScrollID == "" || m.detailScrollID != m.getSelectedSessionID() {
+		return 0
+	}
+	return m.detailScroll
+}
+
+// detailRows returns the wrapped detail rows of the selected session and
+// how many of them fit in the panel
+func (m Model) detailRows() (rows []string, visible int) {
+	session := m.findSession(m.getSelectedSessionID())
+	if session == nil {
+		return nil, 0
+	}
+	width, height := m.detailPanelSize()
+	return wrapDetailLines(m.detailLines(*session, width), width), height - 2
+}
+
+// scrollDetail scrolls the detail panel of the selected session by delta
+// rows, stopping at either end of its content
+func (m Model) scrollDetail(delta int) Model {
+	rows, visible := m.detailRows()
+	if rows == nil {
+		return m
+	}
+
+	offset := m.detailOffset() + delta
+	offset = min(offset, len(rows)-visible)
+	offset = max(offset, 0)
+
+	m.detailScroll = offset
+	m.detailScrollID = m.getSelectedSessionID()
+	return m
+}
+
+// visibleDetailRows returns the rows of the detail panel that fit in
+// visible lines at the current scroll offset. When content is hidden, the
+// first or last row says how much, so it's clear the panel scrolls.
+func (m Model) visibleDetailRows(rows []string, visible int) []string {
+	if visible < 1 || len(rows) <= visible {
+		return rows
+	}
+
+	offset := min(m.detailOffset(), len(rows)-visible)
+	window := append([]string(nil), rows[offset:offset+visible]...)
+
+	if offset > 0 {
+		window[0] = idleStyle.Render(fmt.Sprintf("↑ %d more (PgUp)", offset+1))
+	}
+	if below := len(rows) - offset - visible; below > 0 && visible > 1 {
+		window[len(window)-1] = idleStyle.Render(fmt.Sprintf("↓ %d more (PgDn)", below+1))
+	}
+	return window
+}
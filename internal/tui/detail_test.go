@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jlaneve/cwt-cli/internal/types"
+	"github.com/mattn/go-runewidth"
+)
+
+func TestScrollDetail(t *testing.T) {
+	var files []string
+	for i := 0; i < 60; i++ {
+		files = append(files, fmt.Sprintf("file%02d.go", i))
+	}
+	m := Model{
+		width:  120,
+		height: 30,
+		sessions: []types.Session{
+			{Core: types.CoreSession{ID: "1", Name: "busy"},
+				GitStatus: types.GitStatus{HasChanges: true, ModifiedFiles: files}},
+			{Core: types.CoreSession{ID: "2", Name: "quiet"}},
+		},
+	}
+
+	rows, visible := m.detailRows()
+	if len(rows) <= visible {
+		t.Fatalf("%d rows fit in %d lines, want the panel to overflow", len(rows), visible)
+	}
+
+	// The bottom row says there is more instead of cutting off silently
+	window := m.visibleDetailRows(rows, visible)
+	if len(window) != visible || !strings.Contains(window[len(window)-1], "more (PgDn)") {
+		t.Errorf("last visible row = %q, want a scroll hint", window[len(window)-1])
+	}
+
+	m = m.scrollDetail(-5)
+	if m.detailOffset() != 0 {
+		t.Errorf("scrolling above the top gave offset %d", m.detailOffset())
+	}
+
+	m, _ = m.handleMouseEvent(tea.MouseMsg{X: 60, Button: tea.MouseButtonWheelDown, Type: tea.MouseWheelDown})
+	if m.detailOffset() != detailWheelStep || m.selectedIndex != 0 {
+		t.Errorf("wheel over details: offset = %d, selected = %d; want the panel scrolled", m.detailOffset(), m.selectedIndex)
+	}
+
+	m = m.scrollDetail(1000)
+	if got, want := m.detailOffset(), len(rows)-visible; got != want {
+		t.Errorf("scrolling past the end gave offset %d, want %d", got, want)
+	}
+	window = m.visibleDetailRows(rows, visible)
+	if window[len(window)-1] != rows[len(rows)-1] {
+		t.Errorf("scrolled to the end, last row = %q, want %q", window[len(window)-1], rows[len(rows)-1])
+	}
+
+	// Each session's details start at the top
+	m, _ = m.handleMouseEvent(tea.MouseMsg{X: 10, Button: tea.MouseButtonWheelDown, Type: tea.MouseWheelDown})
+	if m.selectedIndex != 1 || m.detailOffset() != 0 {
+		t.Errorf("wheel over the list: selected = %d, offset = %d; want the next session at the top", m.selectedIndex, m.detailOffset())
+	}
+}
+
+func TestFocusSwitching(t *testing.T) {
+	var files []string
+	for i := 0; i < 60; i++ {
+		files = append(files, fmt.Sprintf("file%02d.go", i))
+	}
+	m := Model{
+		width:  120,
+		height: 30,
+		sessions: []types.Session{
+			{Core: types.CoreSession{ID: "1", Name: "busy"},
+				GitStatus: types.GitStatus{HasChanges: true, ModifiedFiles: files}},
+			{Core: types.CoreSession{ID: "2", Name: "quiet"}},
+		},
+	}
+	key := func(m Model, k tea.KeyType) Model {
+		m, _ = m.handleKeyPress(tea.KeyMsg{Type: k})
+		return m
+	}
+
+	m = key(m, tea.KeyTab)
+	if !m.detailFocused {
+		t.Fatal("tab should focus the detail panel")
+	}
+
+	// Arrow keys scroll the focused details instead of changing the selection
+	m = key(m, tea.KeyDown)
+	m = key(m, tea.KeyDown)
+	if m.selectedIndex != 0 || m.detailOffset() != 2 {
+		t.Errorf("down in details: selected = %d, offset = %d; want the details scrolled", m.selectedIndex, m.detailOffset())
+	}
+
+	// The wheel follows focus even over the session list
+	m, _ = m.handleMouseEvent(tea.MouseMsg{X: 10, Button: tea.MouseButtonWheelDown, Type: tea.MouseWheelDown})
+	if m.selectedIndex != 0 || m.detailOffset() != 2+detailWheelStep {
+		t.Errorf("wheel with details focused: selected = %d, offset = %d", m.selectedIndex, m.detailOffset())
+	}
+
+	m = key(m, tea.KeyShiftTab)
+	m = key(m, tea.KeyDown)
+	if m.detailFocused || m.selectedIndex != 1 {
+		t.Errorf("shift+tab then down: focused = %v, selected = %d; want the list navigated", m.detailFocused, m.selectedIndex)
+	}
+}
+
+func TestSessionDetailLines_TruncatesTaskByWidth(t *testing.T) {
+	session := types.Session{Core: types.CoreSession{Name: "auth", Task: strings.Repeat("修复登录", 10)}}
+	for _, line := range sessionDetailLines(session, 24) {
+		if !strings.HasPrefix(line, "Task: ") {
+			continue
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("task line %q was cut inside a character", line)
+		}
+		if width := runewidth.StringWidth(line); width > 20 {
+			t.Errorf("task line %q is %d columns wide, want at most 20", line, width)
+		}
+		return
+	}
+	t.Error("expected a task line")
+}
@@ -0,0 +1,121 @@
+package tui
+
+// DiffFile summarizes one file in the diff for the file list sidebar
+type DiffFile struct {
+	Name    string
+	Added   int
+	Removed int
+	Line    int // Index of the file's header in the visible diff lines
+}
+
+// visibleDiffLines returns the diff lines shown in the diff view: collapsed
+// files keep only their "diff --git" header line
+func (d *DiffMode) visibleDiffLines() []DiffLine {
+	if len(d.collapsed) == 0 {
+		return d.diffLines
+	}
+
+	var visible []DiffLine
+	for _, line := range d.diffLines {
+		if d.collapsed[line.FileName] && line.Type != DiffLineFileHeader {
+			continue
+		}
+		visible = append(visible, line)
+	}
+	return visible
+}
+
+// files returns the files in the diff in order, with their line counts and
+// where they start in the visible diff lines
+func (d *DiffMode) files() []DiffFile {
+	var files []DiffFile
+	visibleIndex := 0
+	for _, line := range d.diffLines {
+		if line.Type == DiffLineFileHeader {
+			files = append(files, DiffFile{Name: line.FileName, Line: visibleIndex})
+		}
+		if len(files) > 0 {
+			switch line.Type {
+			case DiffLineAdded:
+				files[len(files)-1].Added++
+			case DiffLineRemoved:
+				files[len(files)-1].Removed++
+			}
+		}
+		if !d.collapsed[line.FileName] || line.Type == DiffLineFileHeader {
+			visibleIndex++
+		}
+	}
+	return files
+}
+
+// currentFile returns the index of the file at the top of the view, or -1
+// when the diff has no files
+func (d *DiffMode) currentFile() int {
+	files := d.files()
+	if len(files) == 0 {
+		return -1
+	}
+
+	current := 0
+	for i, file := range files {
+		if file.Line > d.scrollOffset {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+// jumpToFile scrolls so the file at index starts the view
+func (d *DiffMode) jumpToFile(index int) {
+	files := d.files()
+	if index < 0 || index >= len(files) {
+		return
+	}
+	d.scrollOffset = files[index].Line
+}
+
+// toggleCollapsed collapses or expands the file at the top of the view,
+// keeping its header at the top
+func (d *DiffMode) toggleCollapsed() {
+	index := d.currentFile()
+	if index < 0 {
+		return
+	}
+	name := d.files()[index].Name
+
+	if d.collapsed == nil {
+		d.collapsed = make(map[string]bool)
+	}
+	if d.collapsed[name] {
+		delete(d.collapsed, name)
+	} else {
+		d.collapsed[name] = true
+	}
+	d.jumpToFile(index)
+}
+
+// jumpToHunk scrolls to the next (direction 1) or previous (direction -1)
+// hunk header in the visible diff lines, reporting whether there was one
+func (d *DiffMode) jumpToHunk(direction int) bool {
+	lines := d.visibleDiffLines()
+	for i := d.scrollOffset + direction; i >= 0 && i < len(lines); i += direction {
+		if lines[i].Type == DiffLineHunkHeader {
+			d.scrollOffset = i
+			return true
+		}
+	}
+	return false
+}
+
+// clampScroll keeps the scroll offset within the visible diff lines, for
+// when collapsing or reloading shortened the diff
+func (d *DiffMode) clampScroll() {
+	if last := len(d.visibleDiffLines()) - 1; d.scrollOffset > last {
+		d.scrollOffset = last
+	}
+	if d.scrollOffset < 0 {
+		d.scrollOffset = 0
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import "testing"
+
+const navTestDiff = `diff --git a/a.go b/a.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/a.go
+@@ -1,2 +1,2 @@
+-old
++new
+ same
+@@ -10,1 +10,2 @@
+ ten
++eleven
+diff --git a/b.go b/b.go
+index 3333333..4444444 100644
+--- a/b.go
++++ b/b.go
+@@ -1,1 +1,1 @@
+-gone
++here
+`
+
+func TestDiffModeFiles(t *testing.T) {
+	d := &DiffMode{diffLines: parseDiffOutput(navTestDiff)}
+
+	files := d.files()
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+	if files[0] != (DiffFile{Name: "a.go", Added: 2, Removed: 1, Line: 0}) {
+		t.Errorf("first file = %+v", files[0])
+	}
+	if files[1] != (DiffFile{Name: "b.go", Added: 1, Removed: 1, Line: 11}) {
+		t.Errorf("second file = %+v", files[1])
+	}
+}
+
+func TestDiffModeNavigation(t *testing.T) {
+	d := &DiffMode{diffLines: parseDiffOutput(navTestDiff)}
+
+	d.jumpToFile(1)
+	if d.scrollOffset != 11 || d.currentFile() != 1 {
+		t.Errorf("after jumping to b.go, offset = %d, current file = %d", d.scrollOffset, d.currentFile())
+	}
+
+	// Hunks are found in both directions, across files
+	if !d.jumpToHunk(-1) || d.scrollOffset != 8 {
+		t.Errorf("previous hunk offset = %d, want 8", d.scrollOffset)
+	}
+	if !d.jumpToHunk(1) || d.scrollOffset != 15 {
+		t.Errorf("next hunk offset = %d, want 15", d.scrollOffset)
+	}
+	if d.jumpToHunk(1) {
+		t.Error("there should be no hunk after the last one")
+	}
+
+	// Collapsing a.go leaves only its header line visible
+	d.scrollOffset = 5
+	d.toggleCollapsed()
+	if got := len(d.visibleDiffLines()); got != 8 {
+		t.Errorf("visible lines with a.go collapsed = %d, want 8", got)
+	}
+	if d.scrollOffset != 0 {
+		t.Errorf("collapsing should scroll to the file header, offset = %d", d.scrollOffset)
+	}
+	if files := d.files(); files[1].Line != 1 {
+		t.Errorf("b.go should start right after a.go's header, got line %d", files[1].Line)
+	}
+	if !d.jumpToHunk(1) || d.scrollOffset != 5 {
+		t.Errorf("next hunk should skip collapsed a.go, offset = %d", d.scrollOffset)
+	}
+
+	d.scrollOffset = 0
+	d.toggleCollapsed()
+	if got := len(d.visibleDiffLines()); got != len(d.diffLines) {
+		t.Errorf("expanding should show all %d lines, got %d", len(d.diffLines), got)
+	}
+}
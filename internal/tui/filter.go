@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// visibleSessions returns the sessions matching the current filter, in the
+// current sort order. Muted sessions are left out while collapsed, unless
+// the filter finds them.
+func (m Model) visibleSessions() []types.Session {
+	var visible []types.Session
+	for _, session := range m.sessions {
+		if m.mutedHidden(session) {
+			continue
+		}
+		if m.filterQuery == "" || matchesFilter(session, m.filterQuery) {
+			visible = append(visible, session)
+		}
+	}
+	sortSessions(visible, m.sortOrder)
+	return visible
+}
+
+// mutedHidden reports whether a session is muted and collapsed out of the list
+func (m Model) mutedHidden(session types.Session) bool {
+	return session.Core.IsMuted() && !m.showMuted && m.filterQuery == ""
+}
+
+// hiddenMutedCount returns how many muted sessions are collapsed
+func (m Model) hiddenMutedCount() int {
+	var hidden int
+	for _, session := range m.sessions {
+		if m.mutedHidden(session) {
+			hidden++
+		}
+	}
+	return hidden
+}
+
+// totalItems returns the number of rows in the session list, including
+// sessions still being created
+func (m Model) totalItems() int {
+	return len(m.visibleSessions()) + len(m.creatingSessions)
+}
+
+// matchesFilter reports whether every word of the query fuzzily matches
+// one of the session's searchable fields
+func matchesFilter(session types.Session, query string) bool {
+	fields := filterFields(session)
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		matched := false
+		for _, field := range fields {
+			if fuzzyMatch(term, field) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// filterFields returns the lowercased text a session can be found by:
+// its name (which is also its branch), tags, Claude state and git status
+func filterFields(session types.Session) []string {
+	gitState := "clean"
+	switch {
+	case session.GitStatus.WorktreeBroken():
+		gitState = "broken"
+	case session.GitStatus.HasError():
+		gitState = "error"
+	case session.GitStatus.HasChanges:
+		gitState = "changes"
+	}
+
+	tmuxState := "alive"
+	if !session.IsAlive {
+		tmuxState = "closed"
+	}
+
+	return append([]string{
+		strings.ToLower(session.Core.Name),
+		string(session.ClaudeStatus.State),
+		gitState,
+		tmuxState,
+	}, session.Core.Tags...)
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in text in order
+func fuzzyMatch(pattern, text string) bool {
+	remaining := []rune(pattern)
+	for _, r := range text {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
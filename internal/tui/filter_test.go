@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func filterTestSessions() []types.Session {
+	return []types.Session{
+		{Core: types.CoreSession{ID: "1", Name: "fix-login"}, IsAlive: true,
+			ClaudeStatus: types.ClaudeStatus{State: types.ClaudeWaiting}},
+		{Core: types.CoreSession{ID: "2", Name: "feature-search"}, IsAlive: true,
+			ClaudeStatus: types.ClaudeStatus{State: types.ClaudeWorking},
+			GitStatus:    types.GitStatus{HasChanges: true}},
+		{Core: types.CoreSession{ID: "3", Name: "refactor-db", Tags: []string{"backend"}},
+			ClaudeStatus: types.ClaudeStatus{State: types.ClaudeComplete}},
+	}
+}
+
+func TestMatchesFilter(t *testing.T) {
+	sessions := filterTestSessions()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"1", "2", "3"}},
+		{query: "fr", want: []string{"2", "3"}}, // f...r in feature-search and refactor-db
+		{query: "FIX", want: []string{"1"}},
+		{query: "waiting", want: []string{"1"}},
+		{query: "changes", want: []string{"2"}},
+		{query: "closed", want: []string{"3"}},
+		{query: "backend", want: []string{"3"}},
+		{query: "feat working", want: []string{"2"}},
+		{query: "feat waiting", want: nil},
+	}
+
+	for _, tt := range tests {
+		m := Model{sessions: sessions, filterQuery: tt.query}
+		var got []string
+		for _, session := range m.visibleSessions() {
+			got = append(got, session.Core.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("query %q matched %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("query %q matched %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSetFilter_KeepsSelection(t *testing.T) {
+	m := Model{sessions: filterTestSessions(), selectedIndex: 2}
+
+	m = m.setFilter("re")
+	if got := m.getSelectedSessionID(); got != "3" {
+		t.Errorf("selected %q after filtering, want the still-matching session 3", got)
+	}
+
+	m = m.setFilter("login")
+	if got := m.getSelectedSessionID(); got != "1" {
+		t.Errorf("selected %q after filtering, want the first match", got)
+	}
+
+	m = m.setFilter("")
+	if got := m.getSelectedSessionID(); got != "1" {
+		t.Errorf("selected %q after clearing the filter, want session 1", got)
+	}
+}
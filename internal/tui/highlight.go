@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// highlightStyleName is the chroma color scheme used for diff content
+const highlightStyleName = "monokai"
+
+// syntaxHighlighter colors diff line content by the language of its file.
+// Lexing is the slow part, so each rendered line is cached until the diff
+// is reloaded. Lines are lexed on their own, which can miscolor the middle
+// of a multi-line string or comment but keeps scrolling cheap.
+type syntaxHighlighter struct {
+	style   *chroma.Style
+	lexers  map[string]chroma.Lexer // By file name; nil when the language is unknown
+	results map[string]string
+}
+
+func newSyntaxHighlighter() *syntaxHighlighter {
+	return &syntaxHighlighter{
+		style:   styles.Get(highlightStyleName),
+		lexers:  make(map[string]chroma.Lexer),
+		results: make(map[string]string),
+	}
+}
+
+// clear drops the cached lines, for when the diff is reloaded
+func (h *syntaxHighlighter) clear() {
+	h.results = make(map[string]string)
+}
+
+// lexer returns the lexer for a file, or nil if its language is unknown
+func (h *syntaxHighlighter) lexer(fileName string) chroma.Lexer {
+	lexer, ok := h.lexers[fileName]
+	if !ok {
+		if lexer = lexers.Match(fileName); lexer != nil {
+			lexer = chroma.Coalesce(lexer)
+		}
+		h.lexers[fileName] = lexer
+	}
+	return lexer
+}
+
+// render colors code from fileName on top of base, the style for lines of
+// kind, keeping its background so added and removed lines stay
+// recognizable. ok is false when the file's language is unknown.
+func (h *syntaxHighlighter) render(fileName, code string, kind DiffLineType, base lipgloss.Style) (string, bool) {
+	lexer := h.lexer(fileName)
+	if lexer == nil {
+		return "", false
+	}
+
+	key := fmt.Sprintf("%s\x00%d\x00%s", fileName, kind, code)
+	if result, ok := h.results[key]; ok {
+		return result, true
+	}
+
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, token := range iterator.Tokens() {
+		text := strings.TrimRight(token.Value, "\n")
+		if text == "" {
+			continue
+		}
+		style := base
+		if entry := h.style.Get(token.Type); entry.Colour.IsSet() {
+			style = style.Foreground(lipgloss.Color(entry.Colour.String()))
+		}
+		b.WriteString(style.Render(text))
+	}
+
+	result := b.String()
+	h.results[key] = result
+	return result, true
+}
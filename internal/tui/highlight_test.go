@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+)
+
+func TestSyntaxHighlighter(t *testing.T) {
+	h := newSyntaxHighlighter()
+
+	code := `	fmt.Println("hello") // greet`
+	rendered, ok := h.render("main.go", code, DiffLineAdded, diffAddedStyle)
+	if !ok {
+		t.Fatal("Go files should be highlighted")
+	}
+	if got, want := ansi.Strip(rendered), ansi.Strip(diffAddedStyle.Render(code)); got != want {
+		t.Errorf("highlighting changed the text: %q, want %q", got, want)
+	}
+	if len(h.results) != 1 {
+		t.Errorf("rendered lines should be cached, got %d entries", len(h.results))
+	}
+	h.clear()
+	if len(h.results) != 0 {
+		t.Error("clear should drop cached lines")
+	}
+
+	if _, ok := h.render("notes.unknownext", "plain text", DiffLineContext, diffContextStyle); ok {
+		t.Error("files in unknown languages should fall back to plain rendering")
+	}
+}
+
+func TestSyntaxHighlightingConfig(t *testing.T) {
+	cfg := config.Default()
+	m := Model{config: cfg, diffMode: &DiffMode{highlighter: newSyntaxHighlighter()}}
+	if !m.syntaxHighlighting() {
+		t.Error("syntax highlighting should be on by default")
+	}
+
+	cfg.TUI.SyntaxHighlight = false
+	if m.syntaxHighlighting() {
+		t.Error("the config option should turn syntax highlighting off")
+	}
+}
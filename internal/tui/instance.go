@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/utils"
+)
+
+// InstanceLockFile is the file in the data directory the dashboard that
+// manages sessions holds locked. Further dashboards of the same data
+// directory find it held and only show sessions.
+const InstanceLockFile = "tui.lock"
+
+// InstanceRetryInterval is how often a read-only dashboard checks whether
+// the one managing sessions has exited
+const InstanceRetryInterval = 3 * time.Second
+
+// instanceLock is the dashboard's claim on its data directory, kept across
+// the attach cycles of Run
+type instanceLock struct {
+	path string
+
+	mu   sync.Mutex
+	lock *utils.FileLock // Nil while another dashboard holds it
+}
+
+func newInstanceLock(dataDir string) *instanceLock {
+	return &instanceLock{path: filepath.Join(dataDir, InstanceLockFile)}
+}
+
+// acquire takes the lock if it is free, recording this process as its
+// holder, and reports whether this dashboard holds it
+func (i *instanceLock) acquire() (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.lock != nil {
+		return true, nil
+	}
+	lock, err := utils.TryLockFile(i.path)
+	if errors.Is(err, utils.ErrLocked) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	file := lock.File()
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	i.lock = lock
+	return true, nil
+}
+
+// holder returns the process ID of the dashboard holding the lock, or 0
+// if it can't be told
+func (i *instanceLock) holder() int {
+	data, err := os.ReadFile(i.path)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
+
+// release gives the lock up for another dashboard to take
+func (i *instanceLock) release() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.lock.Unlock()
+	i.lock = nil
+}
+
+// instanceRetryMsg asks a read-only dashboard to try taking over
+type instanceRetryMsg struct{}
+
+// instanceCheckedMsg reports whether a read-only dashboard took over
+type instanceCheckedMsg struct{ held bool }
+
+// waitForInstance schedules the next attempt of a read-only dashboard to
+// take over
+func waitForInstance() tea.Cmd {
+	return tea.Tick(InstanceRetryInterval, func(time.Time) tea.Msg {
+		return instanceRetryMsg{}
+	})
+}
+
+// retryInstance tries to take over from the dashboard managing sessions
+func (m Model) retryInstance() tea.Cmd {
+	instance := m.instance
+	return func() tea.Msg {
+		held, err := instance.acquire()
+		if err != nil {
+			logger.Warn("failed to take the dashboard lock", "error", err)
+		}
+		return instanceCheckedMsg{held: held}
+	}
+}
+
+// handleInstanceChecked leaves read-only mode once the other dashboard is
+// gone, doing the startup work it did in this one's place
+func (m Model) handleInstanceChecked(msg instanceCheckedMsg) (Model, tea.Cmd) {
+	if !msg.held {
+		return m, waitForInstance()
+	}
+	if !m.readOnly {
+		return m, nil
+	}
+	m.readOnly = false
+	logger.Info("took over managing sessions from another dashboard")
+	m.successMessage = "The other dashboard exited; sessions can be managed here now"
+	return m, tea.Batch(
+		m.checkPullRequests(),
+		m.reconcileOnStart(),
+		m.refreshSessions(),
+		tea.Tick(3*time.Second, func(time.Time) tea.Msg { return clearSuccessMsg{} }),
+	)
+}
+
+// readOnlyKeys are the keys of the session list that change sessions,
+// which a read-only dashboard refuses
+var readOnlyKeys = map[string]bool{
+	"n": true, "d": true, "c": true, "s": true, "m": true, "M": true, "u": true,
+	"p": true, "R": true, "T": true, "P": true, "w": true, "W": true, "F": true,
+}
+
+// readOnlyDiffKeys are the keys of the diff view that stage, unstage or
+// commit changes, which a read-only dashboard refuses
+var readOnlyDiffKeys = map[string]bool{
+	"s": true, "S": true, "u": true, "U": true, "C": true,
+}
+
+// refuseReadOnly shows why a read-only dashboard ignored a key for a while
+func (m Model) refuseReadOnly() (Model, tea.Cmd) {
+	m.lastError = m.readOnlyRefusal()
+	return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearErrorMsg{}
+	})
+}
+
+// readOnlyRefusal explains why a read-only dashboard ignored a key
+func (m Model) readOnlyRefusal() string {
+	if m.instance == nil {
+		return "Read-only: another dashboard manages sessions"
+	}
+	if pid := m.instance.holder(); pid != 0 {
+		return fmt.Sprintf("Read-only: the dashboard in process %d manages sessions", pid)
+	}
+	return "Read-only: another dashboard manages sessions"
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+func TestReadOnlyDashboard(t *testing.T) {
+	dataDir := t.TempDir()
+	sm := state.NewManager(state.Config{
+		DataDir:       dataDir,
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+	if err := sm.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	sessions, _ := sm.DeriveFreshSessions()
+
+	// The first dashboard holds the lock
+	primary := newInstanceLock(dataDir)
+	if held, err := primary.acquire(); err != nil || !held {
+		t.Fatalf("acquire() = %v, %v, want the free lock taken", held, err)
+	}
+	secondary := newInstanceLock(dataDir)
+	if held, err := secondary.acquire(); err != nil || held {
+		t.Fatalf("acquire() = %v, %v, want the held lock left alone", held, err)
+	}
+
+	m := Model{stateManager: sm, sessions: sessions, instance: secondary, readOnly: true}
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.renameDialog != nil {
+		t.Error("a read-only dashboard should not rename sessions")
+	}
+	if !strings.Contains(m.lastError, strconv.Itoa(os.Getpid())) {
+		t.Errorf("lastError = %q, want it to name the process managing sessions", m.lastError)
+	}
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if !m.showTimeline {
+		t.Error("a read-only dashboard should still show timelines")
+	}
+	m.showTimeline = false
+
+	// Sorting works, but the order isn't saved to the config
+	order := m.sortOrder
+	m, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if m.sortOrder == order || cmd != nil {
+		t.Errorf("S: sort order %q, cmd %v; want the sessions re-sorted without saving", m.sortOrder, cmd)
+	}
+
+	// Quick actions and the diff view's staging keys are refused too
+	m.toastAction = retryCreateSessionAction("auth")
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(ToastActionKey)})
+	if m.newSessionDialog != nil {
+		t.Error("a read-only dashboard should not run quick actions")
+	}
+	m.showDiffMode = true
+	m.diffMode = &DiffMode{}
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if m.commitDialog != nil {
+		t.Error("a read-only dashboard should not commit from the diff view")
+	}
+	m.showDiffMode = false
+	m.diffMode = nil
+
+	// It keeps checking while the other dashboard runs...
+	if _, cmd := m.handleInstanceChecked(m.retryInstance()().(instanceCheckedMsg)); cmd == nil {
+		t.Error("expected another check to be scheduled")
+	}
+
+	// ...and takes over once it exits
+	primary.release()
+	m, _ = m.handleInstanceChecked(m.retryInstance()().(instanceCheckedMsg))
+	if m.readOnly {
+		t.Fatal("expected the dashboard to take over once the other one exited")
+	}
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.renameDialog == nil {
+		t.Error("expected sessions to be renamed once the dashboard took over")
+	}
+	secondary.release()
+}
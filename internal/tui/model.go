@@ -1,48 +1,53 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fsnotify/fsnotify"
 
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/logging"
+	"github.com/jlaneve/cwt-cli/internal/operations"
 	"github.com/jlaneve/cwt-cli/internal/state"
 	"github.com/jlaneve/cwt-cli/internal/types"
 	"github.com/jlaneve/cwt-cli/internal/utils"
 )
 
-// Global logger for debugging
-var debugLogger *log.Logger
+// logger is the dashboard's diagnostic log
+var logger = logging.For("tui")
 
 // Constants for UI behavior
 const (
 	ScrollAmount = 10 // Number of lines to scroll in diff view
-)
 
-func init() {
-	// Create debug log file
-	logFile, err := os.OpenFile("cwt-tui-debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err == nil {
-		debugLogger = log.New(logFile, "[TUI-DEBUG] ", log.LstdFlags|log.Lshortfile)
-		debugLogger.Println("=== TUI Debug Session Started ===")
-	}
-}
+	ToastActionKey      = "o"             // Key that runs the quick action offered by a toast
+	ToastActionDuration = 8 * time.Second // Toasts with a quick action stay visible longer
+)
 
 // Model represents the main TUI state
 type Model struct {
 	stateManager     *state.Manager
+	config           *config.Config
 	sessions         []types.Session
 	fileWatcher      *fsnotify.Watcher
 	showHelp         bool
 	confirmDialog    *ConfirmDialog
 	newSessionDialog *NewSessionDialog
+	sendPromptDialog *SendPromptDialog
+	commitDialog     *CommitDialog
+	renameDialog     *RenameDialog
+	tagsDialog       *TagsDialog
 	lastError        string
-	successMessage   string // For success toast notifications
+	successMessage   string       // For success toast notifications
+	toastAction      *ToastAction // Quick follow-up offered by the current toast
 	ready            bool
 	attachOnExit     string // Session name to attach to when exiting TUI
 
@@ -51,10 +56,26 @@ type Model struct {
 	height int
 
 	// Split-pane state
-	selectedIndex int // Which session is selected in the left panel
+	selectedIndex  int    // Which session is selected in the left panel
+	detailScroll   int    // First visible row of the right panel
+	detailScrollID string // Session the right panel was scrolled on
+	detailFocused  bool   // Whether keys scroll the right panel instead of the list
+
+	// Timeline shown in the right panel instead of the session's details
+	showTimeline bool
+	timeline     *timelineCache // Parsed event log of the timeline shown
+
+	// Provider panel shown in the right panel instead of the session's details
+	showPanel       bool
+	panelGeneration int         // Bumped on each toggle to retire older panel ticks
+	panels          *panelCache // Output of the panel's provider for each session
+
+	// Overlay breaking down where refreshes and drawing spend their time
+	showTimings    bool
+	refreshTimings *refreshTimings // Of the last full refresh; nil before one was timed
 
 	// Session creation tracking
-	creatingSessions map[string]bool // Track sessions being created
+	creatingSessions map[string]*sessionCreation // Sessions being created, by name
 
 	// Event channel for file watching
 	eventChan chan tea.Msg
@@ -62,6 +83,34 @@ type Model struct {
 	// Diff mode state
 	diffMode     *DiffMode
 	showDiffMode bool
+
+	// Clipboard copy menu
+	showCopyMenu bool
+
+	// Outcome of the last merge or publish, while it is shown
+	resultPanel *ResultPanel
+
+	// Approve-and-merge flow under way, if any
+	approval *approval
+
+	// Session list filter and order
+	filterQuery string // Sessions shown must fuzzily match this
+	filtering   bool   // Whether keys are being typed into the filter
+	sortOrder   string // One of config.SortOrders
+	showMuted   bool   // Whether muted sessions are listed rather than collapsed
+
+	// Sessions marked for bulk actions, by ID
+	marked map[string]bool
+
+	// Live config reload; reloadConfig is nil when the config can't change
+	reloadConfig  func() (*config.Config, error)
+	configWatcher *config.Watcher
+	coalescer     *eventCoalescer
+
+	// Dashboards of the same data directory take turns managing sessions;
+	// while another holds the instance lock, this one only shows them
+	instance *instanceLock
+	readOnly bool
 }
 
 // ConfirmDialog represents a yes/no confirmation dialog
@@ -69,6 +118,27 @@ type ConfirmDialog struct {
 	Message string
 	OnYes   func() tea.Cmd
 	OnNo    func() tea.Cmd
+	Toggle  *ConfirmToggle // Option switched with its key before answering, if any
+
+	// Phrase, when set, must be typed to confirm instead of answering yes,
+	// for operations protected mode guards
+	Phrase string
+	Typed  string
+}
+
+// ConfirmToggle is an on/off option of a confirmation dialog. OnYes reads
+// On through the same pointer, so it sees the choice made in the dialog.
+type ConfirmToggle struct {
+	Key   string
+	Label string
+	On    bool
+}
+
+// ToastAction is a follow-up offered alongside a toast notification,
+// triggered by pressing ToastActionKey while the toast is visible
+type ToastAction struct {
+	Label string
+	Run   func(m Model) (Model, tea.Cmd)
 }
 
 // NewSessionDialog represents a new session creation dialog
@@ -77,14 +147,49 @@ type NewSessionDialog struct {
 	Error     string
 }
 
+// SendPromptDialog represents the dialog for typing a prompt to send to a
+// session's Claude without attaching
+type SendPromptDialog struct {
+	SessionID   string
+	SessionName string
+	Input       string
+	Error       string
+}
+
+// CommitDialog represents the dialog for typing a commit message for the
+// changes staged in the diff view's session
+type CommitDialog struct {
+	Input string
+	Error string
+}
+
+// RenameDialog represents the dialog for typing a session's new name
+type RenameDialog struct {
+	SessionID   string
+	SessionName string
+	Input       string
+	Error       string
+}
+
+// TagsDialog represents the dialog for editing a session's tags
+type TagsDialog struct {
+	SessionID   string
+	SessionName string
+	Input       string
+	Error       string
+}
+
 // DiffMode represents the diff viewer state
 type DiffMode struct {
 	session      types.Session
 	diffLines    []DiffLine
 	scrollOffset int
 	selectedLine int
-	target       string // comparison target (branch)
-	cached       bool   // show staged changes only
+	target       string          // comparison target (branch)
+	mode         git.DiffMode    // what the target view compares
+	view         diffView        // what the diff compares
+	collapsed    map[string]bool // files whose hunks are hidden, by name
+	highlighter  *syntaxHighlighter
 }
 
 // DiffLine represents a single line in the diff view
@@ -116,6 +221,9 @@ type (
 	sessionStateChangedMsg struct{}
 	sessionListChangedMsg  struct{}
 	gitIndexChangedMsg     struct{ sessionID string }
+	dataDirChangedMsg      struct{}
+	refreshRequestedMsg    struct{ sessionName string }
+	shutdownRequestedMsg   struct{} // 'cwt shutdown' asked running TUIs to exit
 
 	// Polling events
 	gitStatusRefreshMsg  struct{}
@@ -127,27 +235,54 @@ type (
 	createSessionMsg struct{ name string }
 
 	// Internal events
-	refreshCompleteMsg struct{ sessions []types.Session }
-	errorMsg           struct{ err error }
-	confirmYesMsg      struct{}
-	confirmNoMsg       struct{}
+	refreshCompleteMsg struct {
+		sessions []types.Session
+		timings  *refreshTimings // Set when the refresh was timed
+	}
+	sessionRefreshedMsg struct{ session types.Session }
+	errorMsg            struct{ err error }
+	confirmYesMsg       struct{}
+	confirmNoMsg        struct{}
 
 	// Session creation status
-	sessionCreatingMsg       struct{ name string }
-	sessionCreatedMsg        struct{ name string }
-	sessionCreationFailedMsg struct {
+	sessionCreatingMsg struct {
+		name   string
+		cancel context.CancelFunc
+	}
+	sessionCreationProgressMsg struct{ name, step string }
+	sessionCreatedMsg          struct{ name string }
+	sessionCreationFailedMsg   struct {
 		name string
 		err  error
 	}
 
 	// Toast messages
 	clearSuccessMsg struct{}
+	successToastMsg struct {
+		message string
+		action  *ToastAction
+	}
+	errorToastMsg struct {
+		err    error
+		action *ToastAction
+	}
+
+	// Clipboard events
+	copiedMsg struct{ label string }
+
+	// Bulk action result, once every marked session has been handled
+	bulkCompleteMsg struct {
+		message string
+		err     error
+	}
 
 	// Dialog events
 	showConfirmDialogMsg struct {
 		message string
 		onYes   func() tea.Cmd
 		onNo    func() tea.Cmd
+		toggle  *ConfirmToggle
+		phrase  string
 	}
 
 	// New session dialog events
@@ -163,62 +298,86 @@ type (
 	attachRequestMsg struct{ sessionName string }
 
 	// File watcher setup
-	fileWatcherSetupMsg struct{ watcher *fsnotify.Watcher }
+	fileWatcherSetupMsg struct {
+		watcher   *fsnotify.Watcher
+		coalescer *eventCoalescer
+	}
+
+	// Config reload events
+	configWatcherSetupMsg struct{ watcher *config.Watcher }
+	configChangedMsg      struct{}
 
 	// Diff mode events
 	showDiffModeMsg   struct{ sessionID string }
 	hideDiffModeMsg   struct{}
 	diffLoadedMsg     struct{ diffLines []DiffLine }
+	diffStagedMsg     struct{ message string }
+	diffCommittedMsg  struct{ message string }
 	diffErrorMsg      struct{ err error }
 	diffScrollUpMsg   struct{}
 	diffScrollDownMsg struct{}
 )
 
 // NewModel creates a new TUI model
-func NewModel(stateManager *state.Manager) (*Model, error) {
-	if debugLogger != nil {
-		debugLogger.Println("NewModel: Starting TUI model creation")
+func NewModel(stateManager *state.Manager, cfg *config.Config) (*Model, error) {
+	if cfg == nil {
+		cfg = config.Default()
 	}
 
+	logger.Debug("creating TUI model")
+
 	// Load initial sessions
 	sessions, err := stateManager.DeriveFreshSessions()
 	if err != nil {
-		if debugLogger != nil {
-			debugLogger.Printf("NewModel: Failed to load sessions: %v", err)
-		}
+		logger.Error("failed to load sessions", "error", err)
 		return nil, fmt.Errorf("failed to load initial sessions: %w", err)
 	}
 
-	if debugLogger != nil {
-		debugLogger.Printf("NewModel: Loaded %d sessions", len(sessions))
-		for i, s := range sessions {
-			debugLogger.Printf("NewModel: Session %d: ID=%s, Name=%s, IsAlive=%v", i, s.Core.ID, s.Core.Name, s.IsAlive)
-		}
-	}
-
-	if debugLogger != nil {
-		debugLogger.Printf("NewModel: No table needed for split-pane layout")
+	logger.Debug("loaded sessions", "count", len(sessions))
+	for _, s := range sessions {
+		logger.Debug("session", "id", s.Core.ID, "name", s.Core.Name, "alive", s.IsAlive)
 	}
 
 	return &Model{
 		stateManager:     stateManager,
+		config:           cfg,
 		sessions:         sessions,
 		ready:            false,
-		creatingSessions: make(map[string]bool),
+		creatingSessions: make(map[string]*sessionCreation),
 		eventChan:        make(chan tea.Msg, 100), // Buffered channel for file events
+		sortOrder:        cfg.TUI.Sort,
+		timeline:         &timelineCache{},
+		panels:           &panelCache{entries: make(map[string]*panelEntry)},
 	}, nil
 }
 
 // Init initializes the TUI model with necessary setup
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		tea.EnableMouseCellMotion, // Enable mouse support including scroll events
-		m.setupFileWatching(),
 		m.startEventChannelListener(),
 		m.startGitPolling(),
 		m.startTmuxPolling(),
 		func() tea.Msg { return refreshCompleteMsg{sessions: m.sessions} },
-	)
+	}
+
+	// Checking pull requests and reconciling change sessions, which the
+	// dashboard managing them does
+	if m.readOnly {
+		cmds = append(cmds, waitForInstance())
+	} else {
+		cmds = append(cmds, m.checkPullRequests(), m.reconcileOnStart())
+	}
+
+	// File watching can be disabled in config, leaving only polling
+	if m.config.AutoRefresh {
+		cmds = append(cmds, m.setupFileWatching())
+	}
+	if m.reloadConfig != nil {
+		cmds = append(cmds, m.setupConfigWatching())
+	}
+
+	return tea.Batch(cmds...)
 }
 
 // Update handles all TUI events and state changes
@@ -242,11 +401,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			oldSessionIDs[session.Core.ID] = true
 		}
 
-		// Update sessions
+		// Update sessions, following the selected session if sorting moved it
+		selectedID := m.getSelectedSessionID()
 		m.sessions = msg.sessions
+		if msg.timings != nil {
+			m.refreshTimings = msg.timings
+		}
+		if selectedID != "" && m.findSession(selectedID) != nil {
+			m = m.reselect(selectedID)
+		}
 
 		// Ensure selectedIndex is within bounds
-		totalItems := len(m.sessions) + len(m.creatingSessions)
+		totalItems := m.totalItems()
 		if m.selectedIndex >= totalItems {
 			m.selectedIndex = totalItems - 1
 		}
@@ -282,6 +448,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.startEventChannelListener(), // Restart listener
 		)
 
+	case refreshRequestedMsg:
+		// High priority: Another cwt process changed a specific session
+		for _, session := range m.sessions {
+			if session.Core.Name == msg.sessionName {
+				return m, tea.Batch(
+					m.refreshSession(session.Core.ID),
+					m.startEventChannelListener(), // Restart listener
+				)
+			}
+		}
+		return m, tea.Batch(
+			m.forceRefreshSessions(),
+			m.startEventChannelListener(), // Restart listener
+		)
+
+	case shutdownRequestedMsg:
+		logger.Info("quitting for cwt shutdown")
+		return m, tea.Quit
+
+	case dataDirChangedMsg:
+		// Medium priority: Other data written by CLI commands
+		return m, tea.Batch(
+			m.refreshSessions(),
+			m.startEventChannelListener(), // Restart listener
+		)
+
+	case sessionRefreshedMsg:
+		// Replace just the refreshed session
+		selectedID := m.getSelectedSessionID()
+		for i := range m.sessions {
+			if m.sessions[i].Core.ID == msg.session.Core.ID {
+				m.sessions[i] = msg.session
+				break
+			}
+		}
+		if selectedID != "" {
+			m = m.reselect(selectedID)
+		}
+		return m, nil
+
 	case gitIndexChangedMsg:
 		// Medium priority: Git staging operations
 		return m, tea.Batch(
@@ -291,14 +497,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case gitStatusRefreshMsg:
 		// Low priority: Working tree changes (polling)
-		return m, m.refreshAllGitStatus()
+		return m, tea.Batch(m.refreshAllGitStatus(), m.startGitPolling())
 
 	case tmuxStatusRefreshMsg:
 		// Low priority: Tmux status (polling)
-		return m, m.refreshTmuxStatus()
+		return m, tea.Batch(m.refreshTmuxStatus(), m.startTmuxPolling())
+
+	case pullRequestPollMsg:
+		// Lowest priority: pull request status, asked of the forge
+		return m, m.checkPullRequests()
+
+	case pullRequestCheckedMsg:
+		return m.handlePullRequestChecked(msg)
 
 	case errorMsg:
 		m.lastError = msg.err.Error()
+		m.toastAction = nil
 		// Clear error after a few seconds and restart event listener if it was from file watcher
 		return m, tea.Batch(
 			tea.Tick(3*time.Second, func(time.Time) tea.Msg {
@@ -309,12 +523,77 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case clearErrorMsg:
 		m.lastError = ""
+		m.toastAction = nil
+		return m, nil
+
+	case panelTickMsg:
+		return m.handlePanelTick(msg)
+
+	case panelLoadedMsg:
+		m.panels.store(msg)
 		return m, nil
 
 	case clearSuccessMsg:
 		m.successMessage = ""
+		m.toastAction = nil
 		return m, nil
 
+	case bulkCompleteMsg:
+		m.marked = nil
+		if msg.err != nil {
+			return m, tea.Batch(m.forceRefreshSessions(), func() tea.Msg { return errorMsg{err: msg.err} })
+		}
+		return m, func() tea.Msg { return successToastMsg{message: msg.message} }
+
+	case successToastMsg:
+		m.lastError = ""
+		m.successMessage = msg.message
+		m.toastAction = msg.action
+		return m, tea.Batch(
+			m.forceRefreshSessions(), // The action changed git or tmux state
+			tea.Tick(toastDuration(msg.action), func(time.Time) tea.Msg {
+				return clearSuccessMsg{}
+			}),
+		)
+
+	case resultPanelMsg:
+		m.lastError = ""
+		m.successMessage = ""
+		m.toastAction = nil
+		m.resultPanel = msg.panel
+		return m, m.forceRefreshSessions() // The operation changed git state
+
+	case approvalStartedMsg:
+		m.approval = msg.run
+		return m, msg.start
+
+	case approvalProgressMsg:
+		if m.approval != nil {
+			m.approval.steps = msg.steps
+		}
+		return m, m.startEventChannelListener() // Restart listener
+
+	case approvalDoneMsg:
+		m.approval = nil
+		return m, func() tea.Msg {
+			return resultPanelMsg{panel: approvalResultPanel(msg.session, msg.steps)}
+		}
+
+	case errorToastMsg:
+		m.successMessage = ""
+		m.lastError = msg.err.Error()
+		m.toastAction = msg.action
+		return m, tea.Tick(toastDuration(msg.action), func(time.Time) tea.Msg {
+			return clearErrorMsg{}
+		})
+
+	case copiedMsg:
+		m.successMessage = fmt.Sprintf("Copied %s to clipboard", msg.label)
+		m.toastAction = nil
+		return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+			return clearSuccessMsg{}
+		})
+
 	case confirmYesMsg:
 		if m.confirmDialog != nil && m.confirmDialog.OnYes != nil {
 			cmd := m.confirmDialog.OnYes()
@@ -348,16 +627,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case sessionCreatingMsg:
 		// Mark session as being created
-		m.creatingSessions[msg.name] = true
+		m.creatingSessions[msg.name] = &sessionCreation{cancel: msg.cancel}
 		return m, nil
 
+	case sessionCreationProgressMsg:
+		if creation := m.creatingSessions[msg.name]; creation != nil {
+			creation.step = msg.step
+		}
+		return m, m.startEventChannelListener() // Restart listener
+
 	case sessionCreatedMsg:
 		// Remove from creating list, show success message, and refresh
 		delete(m.creatingSessions, msg.name)
 		m.successMessage = fmt.Sprintf("Session '%s' created successfully", msg.name)
+		m.toastAction = attachSessionAction(msg.name)
 		return m, tea.Batch(
 			m.refreshSessions(),
-			tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+			tea.Tick(ToastActionDuration, func(time.Time) tea.Msg {
 				return clearSuccessMsg{}
 			}),
 		)
@@ -365,27 +651,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case sessionCreationFailedMsg:
 		// Remove from creating list and show error
 		delete(m.creatingSessions, msg.name)
+		if errors.Is(msg.err, context.Canceled) {
+			m.successMessage = fmt.Sprintf("Cancelled creating session '%s'", msg.name)
+			return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+				return clearSuccessMsg{}
+			})
+		}
 		m.lastError = fmt.Sprintf("Failed to create session '%s': %s", msg.name, msg.err.Error())
-		return m, tea.Tick(5*time.Second, func(time.Time) tea.Msg {
+		m.toastAction = retryCreateSessionAction(msg.name)
+		return m, tea.Tick(ToastActionDuration, func(time.Time) tea.Msg {
 			return clearErrorMsg{}
 		})
 
 	case attachRequestMsg:
-		if debugLogger != nil {
-			debugLogger.Printf("Update: Received attachRequestMsg for session: %s", msg.sessionName)
-		}
+		logger.Debug("received attach request", "session", msg.sessionName)
 		// Store the session to attach to and quit
 		m.attachOnExit = msg.sessionName
-		if debugLogger != nil {
-			debugLogger.Printf("Update: Set attachOnExit=%s, calling tea.Quit", msg.sessionName)
-		}
+		logger.Debug("quitting to attach", "session", msg.sessionName)
 		return m, tea.Quit
 
 	case fileWatcherSetupMsg:
 		// Store the file watcher in the model
 		m.fileWatcher = msg.watcher
+		m.coalescer = msg.coalescer
 		return m, m.startEventChannelListener()
 
+	case configWatcherSetupMsg:
+		m.configWatcher = msg.watcher
+		return m, waitForConfigChange(msg.watcher)
+
+	case instanceRetryMsg:
+		return m, m.retryInstance()
+
+	case instanceCheckedMsg:
+		return m.handleInstanceChecked(msg)
+
+	case configChangedMsg:
+		next, err := m.reloadConfig()
+		if err != nil {
+			return m, tea.Batch(
+				waitForConfigChange(m.configWatcher),
+				func() tea.Msg { return errorMsg{err: fmt.Errorf("keeping the current config: %w", err)} },
+			)
+		}
+		m, cmd := m.applyReloadedConfig(next)
+		return m, tea.Batch(waitForConfigChange(m.configWatcher), cmd)
+
 	case showDiffModeMsg:
 		return m.handleShowDiffMode(msg.sessionID)
 
@@ -397,9 +708,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case diffLoadedMsg:
 		if m.diffMode != nil {
 			m.diffMode.diffLines = msg.diffLines
+			m.diffMode.clampScroll()
+			if m.diffMode.highlighter != nil {
+				m.diffMode.highlighter.clear()
+			}
 		}
 		return m, nil
 
+	case diffStagedMsg:
+		// Show the diff without what just moved in or out of the index
+		return m, tea.Batch(
+			m.loadDiffData(),
+			func() tea.Msg { return successToastMsg{message: msg.message} },
+		)
+
+	case diffCommittedMsg:
+		// The toast refreshes the sessions, so git status shows the commit
+		return m, tea.Batch(
+			m.loadDiffData(),
+			func() tea.Msg { return successToastMsg{message: msg.message} },
+		)
+
 	case diffErrorMsg:
 		m.lastError = fmt.Sprintf("Diff error: %s", msg.err.Error())
 		return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
@@ -418,27 +747,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyPress processes keyboard input
 func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
-	if debugLogger != nil {
-		debugLogger.Printf("handleKeyPress: Key pressed: '%s'", msg.String())
-	}
+	logger.Debug("key pressed", "key", msg.String())
 
 	// Handle confirmation dialog first
 	if m.confirmDialog != nil {
-		if debugLogger != nil {
-			debugLogger.Printf("handleKeyPress: In confirmation dialog, key: '%s'", msg.String())
+		logger.Debug("key in confirmation dialog", "key", msg.String())
+		if m.confirmDialog.Phrase != "" {
+			return m.handleConfirmPhraseKeys(msg)
 		}
 		switch msg.String() {
 		case "y", "Y", "enter":
-			if debugLogger != nil {
-				debugLogger.Println("handleKeyPress: Confirmation Yes")
-			}
+			logger.Debug("confirmation accepted")
 			return m, func() tea.Msg { return confirmYesMsg{} }
 		case "n", "N", "esc":
-			if debugLogger != nil {
-				debugLogger.Println("handleKeyPress: Confirmation No")
-			}
+			logger.Debug("confirmation declined")
 			return m, func() tea.Msg { return confirmNoMsg{} }
 		}
+		if toggle := m.confirmDialog.Toggle; toggle != nil && msg.String() == toggle.Key {
+			toggle.On = !toggle.On
+		}
 		return m, nil
 	}
 
@@ -447,11 +774,44 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m.handleNewSessionDialogKeys(msg)
 	}
 
+	// Handle send prompt dialog
+	if m.sendPromptDialog != nil {
+		return m.handleSendPromptDialogKeys(msg)
+	}
+
+	// Handle rename dialog
+	if m.renameDialog != nil {
+		return m.handleRenameDialogKeys(msg)
+	}
+
+	// Handle tags dialog
+	if m.tagsDialog != nil {
+		return m.handleTagsDialogKeys(msg)
+	}
+
+	// Handle typing into the session filter
+	if m.filtering {
+		return m.handleFilterKeys(msg)
+	}
+
+	// Handle clipboard copy menu
+	if m.showCopyMenu {
+		return m.handleCopyMenuKeys(msg)
+	}
+
+	// Handle a running approve-and-merge
+	if m.approval != nil {
+		return m.handleApprovalKeys(msg)
+	}
+
+	// Handle the result of a merge or publish
+	if m.resultPanel != nil {
+		return m.handleResultPanelKeys(msg)
+	}
+
 	// Handle help overlay
 	if m.showHelp {
-		if debugLogger != nil {
-			debugLogger.Printf("handleKeyPress: In help overlay, key: '%s'", msg.String())
-		}
+		logger.Debug("key in help overlay", "key", msg.String())
 		switch msg.String() {
 		case "?", "esc", "q":
 			m.showHelp = false
@@ -459,42 +819,58 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle commit message dialog
+	if m.commitDialog != nil {
+		return m.handleCommitDialogKeys(msg)
+	}
+
 	// Handle diff mode
 	if m.showDiffMode {
 		return m.handleDiffModeKeys(msg)
 	}
 
+	// Handle quick action offered by the current toast
+	if m.toastAction != nil && msg.String() == ToastActionKey {
+		if m.readOnly {
+			return m.refuseReadOnly()
+		}
+		action := m.toastAction
+		m.toastAction = nil
+		m.lastError = ""
+		m.successMessage = ""
+		return action.Run(m)
+	}
+
 	// Handle action keys first (before table navigation)
-	if debugLogger != nil {
-		debugLogger.Printf("handleKeyPress: Processing action key: '%s', sessions: %d", msg.String(), len(m.sessions))
+	logger.Debug("action key", "key", msg.String(), "sessions", len(m.sessions))
+
+	if m.readOnly && readOnlyKeys[msg.String()] {
+		return m.refuseReadOnly()
 	}
 
 	switch msg.String() {
-	case "q", "ctrl+c":
-		if debugLogger != nil {
-			debugLogger.Println("handleKeyPress: Quit requested")
+	case "ctrl+c":
+		// Quitting mid-creation would leave half-created sessions behind
+		if len(m.creatingSessions) > 0 {
+			return m.cancelCreations(), nil
 		}
 		return m, tea.Quit
 
+	case "q":
+		logger.Debug("quit requested")
+		return m, tea.Quit
+
 	case "enter", "a":
-		if debugLogger != nil {
-			debugLogger.Printf("handleKeyPress: Attach requested, sessions available: %d", len(m.sessions))
-		}
+		logger.Debug("attach requested", "sessions", len(m.sessions))
 		if len(m.sessions) > 0 {
 			sessionID := m.getSelectedSessionID()
-			if debugLogger != nil {
-				debugLogger.Printf("handleKeyPress: Selected session ID: '%s'", sessionID)
-			}
+			logger.Debug("attach: selected session", "session_id", sessionID)
 			if sessionID == "" {
-				if debugLogger != nil {
-					debugLogger.Println("handleKeyPress: No session selected - setting error")
-				}
+				logger.Debug("attach: no session selected")
 				m.lastError = "No session selected"
 				return m, nil
 			}
-			if debugLogger != nil {
-				debugLogger.Printf("handleKeyPress: Processing attach directly for session: %s", sessionID)
-			}
+			logger.Debug("attach: attaching directly", "session_id", sessionID)
 
 			// Handle attach directly instead of through a command
 			session := m.findSession(sessionID)
@@ -503,17 +879,17 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 				return m, nil
 			}
 
-			if debugLogger != nil {
-				debugLogger.Printf("handleKeyPress: Found session %s, IsAlive: %v", session.Core.Name, session.IsAlive)
-			}
+			logger.Debug("attach: found session", "session", session.Core.Name, "alive", session.IsAlive)
 
 			if !session.IsAlive {
-				// Show confirmation dialog for dead sessions
-				if debugLogger != nil {
-					debugLogger.Printf("handleKeyPress: Session %s is dead, showing dialog", session.Core.Name)
+				if m.readOnly {
+					m.lastError = m.readOnlyRefusal()
+					return m, nil
 				}
+				// Show confirmation dialog for dead sessions
+				logger.Debug("attach: session is dead, asking to recreate it", "session", session.Core.Name)
 				m.confirmDialog = &ConfirmDialog{
-					Message: fmt.Sprintf("Session '%s' tmux is not running. Recreate it?", session.Core.Name),
+					Message: recreatePrompt(*session),
 					OnYes: func() tea.Cmd {
 						return m.recreateAndAttach(sessionID)
 					},
@@ -525,28 +901,47 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 			}
 
 			// Alive session - exit and attach
-			if debugLogger != nil {
-				debugLogger.Printf("handleKeyPress: Session %s is alive, setting attachOnExit", session.Core.Name)
-			}
+			logger.Debug("attach: session is alive, quitting to attach", "session", session.Core.Name)
 			m.attachOnExit = session.Core.TmuxSession
 			return m, tea.Quit
 		}
-		if debugLogger != nil {
-			debugLogger.Println("handleKeyPress: No sessions available")
-		}
+		logger.Debug("attach: no sessions")
 		m.lastError = "No sessions available"
 		return m, nil
 
+	case "A":
+		// Open the session in a tmux window, keeping the dashboard running
+		session := m.findSession(m.getSelectedSessionID())
+		if session == nil {
+			return m, nil
+		}
+		if !session.IsAlive {
+			m.lastError = fmt.Sprintf("Session '%s' is not running; press enter to recreate it", session.Core.Name)
+			return m, nil
+		}
+		return m, m.openSessionWindow(*session)
+
 	case "n":
 		return m, func() tea.Msg { return showNewSessionDialogMsg{} }
 
+	case " ":
+		// Mark the selected session for a bulk action
+		m = m.toggleMark()
+		return m, nil
+
 	case "d":
+		if len(m.markedSessions()) > 0 {
+			return m, m.confirmBulk(bulkDelete)
+		}
 		if len(m.sessions) > 0 {
 			return m, m.confirmDelete(m.getSelectedSessionID())
 		}
 		return m, nil
 
 	case "c":
+		if len(m.markedSessions()) > 0 {
+			return m, m.confirmBulk(bulkCleanup)
+		}
 		return m, m.runCleanup()
 
 	case "?":
@@ -554,7 +949,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 
 	case "r":
-		return m, m.refreshSessions()
+		return m, m.forceRefreshSessions()
 
 	case "s":
 		// Switch to session branch
@@ -565,13 +960,26 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 	case "m":
 		// Merge session changes
+		if len(m.markedSessions()) > 0 {
+			return m, m.confirmBulk(bulkMerge)
+		}
 		if len(m.sessions) > 0 {
 			return m, m.mergeSessionChanges(m.getSelectedSessionID())
 		}
 		return m, nil
 
+	case "M":
+		// Approve, test, commit, squash merge and archive the session
+		if sessionID := m.getSelectedSessionID(); sessionID != "" {
+			return m, m.approveAndMerge(sessionID)
+		}
+		return m, nil
+
 	case "u":
 		// Publish (commit + push) session
+		if len(m.markedSessions()) > 0 {
+			return m, m.confirmBulk(bulkPublish)
+		}
 		if len(m.sessions) > 0 {
 			return m, m.publishSession(m.getSelectedSessionID())
 		}
@@ -587,6 +995,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 					return m, func() tea.Msg { return showDiffModeMsg{sessionID: sessionID} }
 				}
 				m.lastError = "Session has no changes to view"
+				if session != nil {
+					if err := operations.CheckWorktree(*session); err != nil {
+						m.lastError = err.Error()
+					}
+				}
 				return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
 					return clearErrorMsg{}
 				})
@@ -594,16 +1007,114 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "p":
+		// Type a prompt for the selected session's Claude
+		return m.handleShowSendPromptDialog()
+
+	case "R":
+		// Rename the selected session
+		return m.handleShowRenameDialog()
+
+	case "T":
+		// Edit the selected session's tags
+		return m.handleShowTagsDialog()
+
+	case "P":
+		// Move the selected session to the next priority
+		return m, m.cyclePriority()
+
+	case "w":
+		// Put the selected session on the watch list, or take it off
+		return m, m.toggleAttention(types.AttentionWatched)
+
+	case "W":
+		// Mute or unmute the selected session
+		return m, m.toggleAttention(types.AttentionMuted)
+
+	case "z":
+		// Show or collapse muted sessions
+		return m.toggleShowMuted(), nil
+
+	case "F":
+		// Recreate the selected session's broken worktree
+		if sessionID := m.getSelectedSessionID(); sessionID != "" {
+			return m, m.repairSession(sessionID)
+		}
+		return m, nil
+
+	case "y":
+		// Open clipboard copy menu for selected session
+		if m.getSelectedSessionID() != "" {
+			m.showCopyMenu = true
+		}
+		return m, nil
+
+	case "S":
+		// Cycle the session list sort order
+		return m.cycleSort()
+
 	case "t":
 		// Toggle between detailed/compact view (placeholder for now)
 		return m, nil
 
+	case "l":
+		// Swap the selected session's details for its timeline
+		m.showTimeline = !m.showTimeline
+		m.showPanel = false
+		m.detailScroll = 0
+		return m, nil
+
+	case "i":
+		// Swap the selected session's details for the provider panel
+		return m.togglePanel()
+
+	case "D":
+		// Show where refreshes and drawing spend their time
+		m.showTimings = !m.showTimings
+		return m, nil
+
+	case "pgup", "pgdown":
+		// Page through the selected session's details
+		_, visible := m.detailRows()
+		page := max(visible-2, 1)
+		if msg.String() == "pgup" {
+			page = -page
+		}
+		return m.scrollDetail(page), nil
+
 	case "/":
-		// Search/filter sessions (placeholder for now)
+		// Filter sessions, editing the current filter if there is one
+		m.filtering = true
+		return m, nil
+
+	case "esc":
+		// Clear an applied filter, then any marks
+		if m.filterQuery != "" {
+			m = m.setFilter("")
+		} else {
+			m.marked = nil
+		}
+		return m, nil
+	}
+
+	// Handle navigation keys for whichever panel has focus
+	switch msg.String() {
+	case "tab", "shift+tab":
+		// With two panels, both directions just move focus to the other one
+		m.detailFocused = !m.detailFocused
+		return m, nil
+	}
+
+	if m.detailFocused {
+		switch msg.String() {
+		case "up", "k":
+			return m.scrollDetail(-1), nil
+		case "down", "j":
+			return m.scrollDetail(1), nil
+		}
 		return m, nil
 	}
 
-	// Handle navigation keys for the left panel
 	switch msg.String() {
 	case "up", "k":
 		if m.selectedIndex > 0 {
@@ -611,7 +1122,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 	case "down", "j":
-		totalItems := len(m.sessions) + len(m.creatingSessions)
+		totalItems := m.totalItems()
 		if m.selectedIndex < totalItems-1 {
 			m.selectedIndex++
 		}
@@ -636,7 +1147,18 @@ func (m Model) handleMouseEvent(msg tea.MouseMsg) (Model, tea.Cmd) {
 	}
 
 	// Handle scroll events in main session list (optional enhancement)
-	if !m.showDiffMode && !m.showHelp && m.confirmDialog == nil && m.newSessionDialog == nil {
+	if !m.showDiffMode && !m.showHelp && m.confirmDialog == nil && m.newSessionDialog == nil && m.sendPromptDialog == nil && m.renameDialog == nil && m.tagsDialog == nil {
+		// The wheel scrolls the focused panel or the one the pointer is
+		// over; the left panel is 40 columns plus its border
+		if m.detailFocused || msg.X >= 42 {
+			switch msg.Type {
+			case tea.MouseWheelUp:
+				return m.scrollDetail(-detailWheelStep), nil
+			case tea.MouseWheelDown:
+				return m.scrollDetail(detailWheelStep), nil
+			}
+		}
+
 		switch msg.Type {
 		case tea.MouseWheelUp:
 			// Scroll up in session list
@@ -646,7 +1168,7 @@ func (m Model) handleMouseEvent(msg tea.MouseMsg) (Model, tea.Cmd) {
 			return m, nil
 		case tea.MouseWheelDown:
 			// Scroll down in session list
-			totalItems := len(m.sessions) + len(m.creatingSessions)
+			totalItems := m.totalItems()
 			if m.selectedIndex < totalItems-1 {
 				m.selectedIndex++
 			}
@@ -659,44 +1181,33 @@ func (m Model) handleMouseEvent(msg tea.MouseMsg) (Model, tea.Cmd) {
 
 // Session selection helpers
 func (m Model) getSelectedSessionID() string {
-	if debugLogger != nil {
-		debugLogger.Printf("getSelectedSessionID: Sessions count: %d, Creating: %d", len(m.sessions), len(m.creatingSessions))
-	}
+	logger.Debug("finding selected session", "sessions", len(m.sessions), "creating", len(m.creatingSessions))
 
-	totalItems := len(m.sessions) + len(m.creatingSessions)
+	totalItems := m.totalItems()
 	if totalItems == 0 {
-		if debugLogger != nil {
-			debugLogger.Println("getSelectedSessionID: No sessions available")
-		}
+		logger.Debug("no sessions to select")
 		return ""
 	}
 
 	selectedIdx := m.selectedIndex
-	if debugLogger != nil {
-		debugLogger.Printf("getSelectedSessionID: Selected index: %d", selectedIdx)
-	}
+	logger.Debug("selected index", "index", selectedIdx)
 
 	// Check if selecting a creating session
 	if selectedIdx < len(m.creatingSessions) {
-		if debugLogger != nil {
-			debugLogger.Println("getSelectedSessionID: Selected creating session, returning empty")
-		}
+		logger.Debug("selected a session being created")
 		return ""
 	}
 
 	// Adjust for regular sessions
+	sessions := m.visibleSessions()
 	sessionIndex := selectedIdx - len(m.creatingSessions)
-	if sessionIndex >= len(m.sessions) {
-		if debugLogger != nil {
-			debugLogger.Printf("getSelectedSessionID: Adjusted index %d >= sessions %d", sessionIndex, len(m.sessions))
-		}
+	if sessionIndex >= len(sessions) {
+		logger.Debug("selected index out of range", "index", sessionIndex, "sessions", len(sessions))
 		return ""
 	}
 
-	sessionID := m.sessions[sessionIndex].Core.ID
-	if debugLogger != nil {
-		debugLogger.Printf("getSelectedSessionID: Returning session ID: %s (name: %s)", sessionID, m.sessions[sessionIndex].Core.Name)
-	}
+	sessionID := sessions[sessionIndex].Core.ID
+	logger.Debug("selected session", "id", sessionID, "name", sessions[sessionIndex].Core.Name)
 
 	return sessionID
 }
@@ -738,8 +1249,10 @@ func (m Model) handleShowDiffMode(sessionID string) (Model, tea.Cmd) {
 		session:      *session,
 		scrollOffset: 0,
 		selectedLine: 0,
-		target:       "origin/main", // default comparison target
-		cached:       false,
+		target:       m.stateManager.GetBaseBranch(),
+		mode:         git.DiffIncludingBase,
+		view:         diffViewTarget,
+		highlighter:  newSyntaxHighlighter(),
 	}
 	m.showDiffMode = true
 
@@ -752,26 +1265,87 @@ func (m Model) handleDiffModeKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 	if m.diffMode == nil {
 		return m, nil
 	}
+	if m.readOnly && readOnlyDiffKeys[msg.String()] {
+		return m.refuseReadOnly()
+	}
 
 	switch msg.String() {
 	case "esc", "q":
 		return m, func() tea.Msg { return hideDiffModeMsg{} }
 
-	case "up", "k":
+	case "up":
 		return m, func() tea.Msg { return diffScrollUpMsg{} }
 
-	case "down", "j":
+	case "down":
 		return m, func() tea.Msg { return diffScrollDownMsg{} }
 
+	case "j":
+		// Next file
+		m.diffMode.jumpToFile(m.diffMode.currentFile() + 1)
+		return m, nil
+
+	case "k":
+		// Previous file, or the start of this one when scrolled into it
+		current := m.diffMode.currentFile()
+		if current >= 0 && m.diffMode.files()[current].Line == m.diffMode.scrollOffset {
+			current--
+		}
+		m.diffMode.jumpToFile(current)
+		return m, nil
+
+	case "enter", " ":
+		// Collapse or expand the current file
+		m.diffMode.toggleCollapsed()
+		return m, nil
+
+	case "n":
+		m.diffMode.jumpToHunk(1)
+		return m, nil
+
+	case "N":
+		m.diffMode.jumpToHunk(-1)
+		return m, nil
+
 	case "r":
 		// Refresh diff
 		return m, m.loadDiffData()
 
 	case "c":
-		// Toggle cached/working tree view
-		m.diffMode.cached = !m.diffMode.cached
+		// Cycle between the target, unstaged and staged views
+		m.diffMode.view = m.diffMode.view.next()
+		m.diffMode.scrollOffset = 0
+		return m, m.loadDiffData()
+
+	case "m":
+		// Cycle what the target view compares, switching to it
+		if m.diffMode.view == diffViewTarget {
+			m.diffMode.mode = m.diffMode.mode.Next()
+		}
+		m.diffMode.view = diffViewTarget
+		m.diffMode.scrollOffset = 0
 		return m, m.loadDiffData()
 
+	case "s":
+		return m, m.stageCurrent(false)
+
+	case "S":
+		return m, m.stageCurrent(true)
+
+	case "u":
+		return m, m.unstageCurrent(false)
+
+	case "U":
+		return m, m.unstageCurrent(true)
+
+	case "C":
+		// Commit what's staged
+		m.commitDialog = &CommitDialog{}
+		return m, nil
+
+	case "y":
+		// Copy the hunk at the top of the view
+		return m, m.copyCurrentHunk()
+
 	case "pgup":
 		if m.diffMode.scrollOffset > ScrollAmount {
 			m.diffMode.scrollOffset -= ScrollAmount
@@ -781,7 +1355,7 @@ func (m Model) handleDiffModeKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 
 	case "pgdn":
-		maxScroll := len(m.diffMode.diffLines) - (m.height - 6)
+		maxScroll := len(m.diffMode.visibleDiffLines()) - (m.height - 6)
 		if maxScroll < 0 {
 			maxScroll = 0
 		}
@@ -796,6 +1370,40 @@ func (m Model) handleDiffModeKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleCopyMenuKeys handles keyboard input for the clipboard copy menu
+func (m Model) handleCopyMenuKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	session := m.findSession(m.getSelectedSessionID())
+
+	switch msg.String() {
+	case "esc", "q", "y":
+		m.showCopyMenu = false
+		return m, nil
+
+	case "p":
+		m.showCopyMenu = false
+		if session == nil {
+			return m, nil
+		}
+		return m, m.copySessionPath(*session)
+
+	case "b":
+		m.showCopyMenu = false
+		if session == nil {
+			return m, nil
+		}
+		return m, m.copySessionBranch(*session)
+
+	case "u":
+		m.showCopyMenu = false
+		if session == nil {
+			return m, nil
+		}
+		return m, m.copySessionPRURL(*session)
+	}
+
+	return m, nil
+}
+
 // handleDiffScrollUp scrolls up in diff view
 func (m Model) handleDiffScrollUp() (Model, tea.Cmd) {
 	if m.diffMode != nil && m.diffMode.scrollOffset > 0 {
@@ -807,7 +1415,7 @@ func (m Model) handleDiffScrollUp() (Model, tea.Cmd) {
 // handleDiffScrollDown scrolls down in diff view
 func (m Model) handleDiffScrollDown() (Model, tea.Cmd) {
 	if m.diffMode != nil {
-		maxScroll := len(m.diffMode.diffLines) - (m.height - 6)
+		maxScroll := len(m.diffMode.visibleDiffLines()) - (m.height - 6)
 		if maxScroll < 0 {
 			maxScroll = 0
 		}
@@ -824,6 +1432,8 @@ func (m Model) handleShowConfirmDialog(msg showConfirmDialogMsg) (Model, tea.Cmd
 		Message: msg.message,
 		OnYes:   msg.onYes,
 		OnNo:    msg.onNo,
+		Toggle:  msg.toggle,
+		Phrase:  msg.phrase,
 	}
 	return m, nil
 }
@@ -905,15 +1515,26 @@ func (m Model) handleNewSessionDialogSubmit() (Model, tea.Cmd) {
 	// Clear the dialog
 	m.newSessionDialog = nil
 
-	// Create session using state manager
+	// Create session using state manager; ctrl+c cancels it
+	ctx, cancel := context.WithCancel(context.Background())
+	eventChan := m.eventChan
+	opts := state.CreateOptions{
+		Progress: func(step string) {
+			select {
+			case eventChan <- sessionCreationProgressMsg{name: name, step: step}:
+			default: // Drop progress rather than block the creation
+			}
+		},
+	}
 	return m, tea.Batch(
 		// Show immediate "creating" status
 		func() tea.Msg {
-			return sessionCreatingMsg{name: name}
+			return sessionCreatingMsg{name: name, cancel: cancel}
 		},
 		// Create session in background
 		func() tea.Msg {
-			err := m.stateManager.CreateSession(name)
+			defer cancel()
+			err := m.stateManager.CreateSessionContext(ctx, name, opts)
 			if err != nil {
 				return sessionCreationFailedMsg{name: name, err: err}
 			}
@@ -930,6 +1551,154 @@ func (m Model) handleNewSessionDialogCancel() (Model, tea.Cmd) {
 	return m, nil
 }
 
+// sessionCreation is a session being created in the background
+type sessionCreation struct {
+	step   string             // Latest progress reported, like git's checkout meter
+	cancel context.CancelFunc // Stops the creation and rolls it back
+}
+
+// cancelCreations cancels every session being created. Each stays listed
+// until its rollback finishes and reports back.
+func (m Model) cancelCreations() Model {
+	var names []string
+	for name, creation := range m.creatingSessions {
+		if creation.cancel != nil {
+			creation.cancel()
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	m.lastError = ""
+	m.successMessage = fmt.Sprintf("Cancelling creation of %s...", strings.Join(names, ", "))
+	return m
+}
+
+// handleFilterKeys handles keyboard input while typing a session filter.
+// The list narrows as the user types; enter keeps the filter, esc clears it.
+func (m Model) handleFilterKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.filtering = false
+		return m.setFilter(""), nil
+
+	case tea.KeyEnter:
+		m.filtering = false
+		return m, nil
+
+	case tea.KeyUp, tea.KeyDown:
+		// Allow picking a match without leaving the filter
+		m.filtering = false
+		m, cmd := m.handleKeyPress(msg)
+		m.filtering = true
+		return m, cmd
+
+	case tea.KeyBackspace:
+		if runes := []rune(m.filterQuery); len(runes) > 0 {
+			return m.setFilter(string(runes[:len(runes)-1])), nil
+		}
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		return m.setFilter(m.filterQuery + string(msg.Runes)), nil
+	}
+
+	return m, nil
+}
+
+// setFilter changes the session filter, keeping the selected session
+// selected when it still matches
+func (m Model) setFilter(query string) Model {
+	selectedID := m.getSelectedSessionID()
+	m.filterQuery = query
+	return m.reselect(selectedID)
+}
+
+// reselect moves the selection to the session with the given ID after the
+// list changed, falling back to the first row
+func (m Model) reselect(selectedID string) Model {
+	m.selectedIndex = 0
+	for i, session := range m.visibleSessions() {
+		if session.Core.ID == selectedID {
+			m.selectedIndex = len(m.creatingSessions) + i
+			break
+		}
+	}
+	return m
+}
+
+// handleShowSendPromptDialog opens the send prompt dialog for the selected session
+func (m Model) handleShowSendPromptDialog() (Model, tea.Cmd) {
+	session := m.findSession(m.getSelectedSessionID())
+	if session == nil {
+		return m, nil
+	}
+
+	if !session.IsAlive {
+		m.lastError = fmt.Sprintf("Session '%s' is not running", session.Core.Name)
+		return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+			return clearErrorMsg{}
+		})
+	}
+
+	m.sendPromptDialog = &SendPromptDialog{
+		SessionID:   session.Core.ID,
+		SessionName: session.Core.Name,
+	}
+	return m, nil
+}
+
+// handleSendPromptDialogKeys handles keyboard input for the send prompt dialog
+func (m Model) handleSendPromptDialogKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	dialog := m.sendPromptDialog
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.sendPromptDialog = nil
+		return m, nil
+
+	case tea.KeyEnter:
+		return m.handleSendPromptDialogSubmit()
+
+	case tea.KeyBackspace:
+		if runes := []rune(dialog.Input); len(runes) > 0 {
+			dialog.Input = string(runes[:len(runes)-1])
+		}
+		dialog.Error = ""
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		// Runes may hold several characters when text is pasted
+		dialog.Input += string(msg.Runes)
+		dialog.Error = ""
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleSendPromptDialogSubmit sends the typed prompt and returns to the dashboard
+func (m Model) handleSendPromptDialogSubmit() (Model, tea.Cmd) {
+	dialog := m.sendPromptDialog
+	if dialog == nil {
+		return m, nil
+	}
+
+	prompt := strings.TrimSpace(dialog.Input)
+	if prompt == "" {
+		dialog.Error = "Prompt is required"
+		return m, nil
+	}
+
+	session := m.findSession(dialog.SessionID)
+	if session == nil {
+		dialog.Error = "Session no longer exists"
+		return m, nil
+	}
+
+	m.sendPromptDialog = nil
+	return m, m.sendPrompt(*session, prompt)
+}
+
 // switchToSessionBranch switches to a session's branch
 func (m Model) switchToSessionBranch(sessionID string) tea.Cmd {
 	return func() tea.Msg {
@@ -938,21 +1707,31 @@ func (m Model) switchToSessionBranch(sessionID string) tea.Cmd {
 			return errorMsg{err: fmt.Errorf("session not found")}
 		}
 
-		// Show confirmation dialog
-		return showConfirmDialogMsg{
+		// In protected mode the typed phrase confirms, and the token passes
+		// it on to the command
+		op := operations.SwitchOperation(session.BranchName())
+		dialog := showConfirmDialogMsg{
 			message: fmt.Sprintf("Switch to session '%s' branch?", session.Core.Name),
 			onYes: func() tea.Cmd {
 				return func() tea.Msg {
-					// Execute cwt switch command
-					if err := utils.ExecuteCWTCommand("switch", session.Core.Name); err != nil {
+					args := []string{session.Core.Name}
+					if m.protected() {
+						args = append(args, "--confirm", op.Token())
+					}
+					if err := utils.ExecuteCWTCommand("switch", args...); err != nil {
 						return errorMsg{err: fmt.Errorf("failed to switch: %w", err)}
 					}
-					m.successMessage = fmt.Sprintf("Switched to session '%s' branch", session.Core.Name)
-					return clearSuccessMsg{}
+					return successToastMsg{
+						message: fmt.Sprintf("Switched to session '%s' branch", session.Core.Name),
+					}
 				}
 			},
 			onNo: func() tea.Cmd { return nil },
 		}
+		if m.protected() {
+			dialog.phrase = op.Phrase
+		}
+		return dialog
 	}
 }
 
@@ -964,21 +1743,45 @@ func (m Model) mergeSessionChanges(sessionID string) tea.Cmd {
 			return errorMsg{err: fmt.Errorf("session not found")}
 		}
 
+		if err := operations.CheckWorktree(*session); err != nil {
+			return errorMsg{err: err}
+		}
 		if !session.GitStatus.HasChanges {
 			return errorMsg{err: fmt.Errorf("session '%s' has no changes to merge", session.Core.Name)}
 		}
+		if err := operations.CheckConflicts(*session); err != nil {
+			return errorMsg{err: err}
+		}
 
-		// Show confirmation dialog
+		if !m.protected() {
+			return showConfirmDialogMsg{
+				message: fmt.Sprintf("Merge session '%s' into current branch?", session.Core.Name),
+				onYes: func() tea.Cmd {
+					return func() tea.Msg {
+						// The dialog was the confirmation, so merge without asking again
+						result := types.NewMergeResultOutput(session.Core.Name, "", "", false)
+						err := utils.ExecuteCWTCommandJSON(&result, "merge", session.Core.Name, "--yes", "--json")
+						return resultPanelMsg{panel: mergeResultPanel(sessionID, result, err)}
+					}
+				},
+				onNo: func() tea.Cmd { return nil },
+			}
+		}
+
+		// In protected mode the phrase names the target, so it is fixed now
+		target, err := m.stateManager.GetGitChecker().GetCurrentBranch(".")
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to get current branch: %w", err)}
+		}
+		op := operations.MergeOperation(session.Core.Name, session.BranchName(), target, false)
 		return showConfirmDialogMsg{
-			message: fmt.Sprintf("Merge session '%s' into current branch?", session.Core.Name),
+			message: fmt.Sprintf("Merge session '%s' into %s?", session.Core.Name, target),
+			phrase:  op.Phrase,
 			onYes: func() tea.Cmd {
 				return func() tea.Msg {
-					// Execute cwt merge command
-					if err := utils.ExecuteCWTCommand("merge", session.Core.Name); err != nil {
-						return errorMsg{err: fmt.Errorf("failed to merge: %w", err)}
-					}
-					m.successMessage = fmt.Sprintf("Merged session '%s'", session.Core.Name)
-					return clearSuccessMsg{}
+					result := types.NewMergeResultOutput(session.Core.Name, "", target, false)
+					err := utils.ExecuteCWTCommandJSON(&result, "merge", session.Core.Name, "--target", target, "--confirm", op.Token(), "--json")
+					return resultPanelMsg{panel: mergeResultPanel(sessionID, result, err)}
 				}
 			},
 			onNo: func() tea.Cmd { return nil },
@@ -994,21 +1797,24 @@ func (m Model) publishSession(sessionID string) tea.Cmd {
 			return errorMsg{err: fmt.Errorf("session not found")}
 		}
 
+		if err := operations.CheckWorktree(*session); err != nil {
+			return errorMsg{err: err}
+		}
 		if !session.GitStatus.HasChanges {
 			return errorMsg{err: fmt.Errorf("session '%s' has no changes to publish", session.Core.Name)}
 		}
+		if err := operations.CheckConflicts(*session); err != nil {
+			return errorMsg{err: err}
+		}
 
 		// Show confirmation dialog
 		return showConfirmDialogMsg{
 			message: fmt.Sprintf("Publish session '%s' (commit + push)?", session.Core.Name),
 			onYes: func() tea.Cmd {
 				return func() tea.Msg {
-					// Execute cwt publish command
-					if err := utils.ExecuteCWTCommand("publish", session.Core.Name); err != nil {
-						return errorMsg{err: fmt.Errorf("failed to publish: %w", err)}
-					}
-					m.successMessage = fmt.Sprintf("Published session '%s'", session.Core.Name)
-					return clearSuccessMsg{}
+					result := types.NewPublishResultOutput(session.Core.Name, "")
+					err := utils.ExecuteCWTCommandJSON(&result, "publish", session.Core.Name, "--json")
+					return resultPanelMsg{panel: publishResultPanel(sessionID, result, err)}
 				}
 			},
 			onNo: func() tea.Cmd { return nil },
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/statusprovider"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// panelCheckInterval is how often the shown provider panel checks whether
+// the selected session's output is missing or due for a refresh
+const panelCheckInterval = time.Second
+
+// panelCache keeps the provider panel output of each session. It is shared
+// by the copies of the Model bubbletea passes around.
+type panelCache struct {
+	mu      sync.Mutex
+	entries map[string]*panelEntry // By session ID
+}
+
+// panelEntry is a provider's panel output for a session
+type panelEntry struct {
+	text    string
+	err     error
+	at      time.Time // When it was produced; zero while the first run is going
+	loading bool
+}
+
+// panelTickMsg checks whether the provider panel needs refreshing. Ticks
+// started before the panel was last toggled carry an older generation and
+// are dropped, so only one chain of them runs.
+type panelTickMsg struct {
+	generation int
+}
+
+// panelLoadedMsg delivers a provider's panel output for a session
+type panelLoadedMsg struct {
+	sessionID string
+	text      string
+	err       error
+}
+
+// entry returns a copy of a session's panel output, if there is any
+func (c *panelCache) entry(sessionID string) (panelEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[sessionID]
+	if !ok {
+		return panelEntry{}, false
+	}
+	return *entry, true
+}
+
+// startLoading marks a session's output as being refreshed, reporting false
+// when it is fresh enough or a refresh is already running
+func (c *panelCache) startLoading(sessionID string, interval time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[sessionID]
+	if !ok {
+		entry = &panelEntry{}
+		c.entries[sessionID] = entry
+	}
+	if entry.loading || (!entry.at.IsZero() && time.Since(entry.at) < interval) {
+		return false
+	}
+	entry.loading = true
+	return true
+}
+
+// store records a session's panel output
+func (c *panelCache) store(msg panelLoadedMsg) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[msg.sessionID] = &panelEntry{text: msg.text, err: msg.err, at: time.Now()}
+}
+
+// panelEnabled reports whether a provider panel is configured
+func (m Model) panelEnabled() bool {
+	return m.config.TUI.Panel.Provider != ""
+}
+
+// panelTitle is the provider panel's heading
+func (m Model) panelTitle() string {
+	if title := m.config.TUI.Panel.Title; title != "" {
+		return title
+	}
+	return m.config.TUI.Panel.Provider
+}
+
+// togglePanel swaps the selected session's details for the provider panel
+func (m Model) togglePanel() (Model, tea.Cmd) {
+	if !m.panelEnabled() {
+		return m, func() tea.Msg {
+			return errorMsg{err: fmt.Errorf("no provider panel configured; set tui.panel.provider")}
+		}
+	}
+
+	m.showPanel = !m.showPanel
+	m.showTimeline = false
+	m.detailScroll = 0
+	m.panelGeneration++
+	if !m.showPanel {
+		return m, nil
+	}
+	return m, tea.Batch(m.refreshPanel(), m.schedulePanelCheck())
+}
+
+// schedulePanelCheck arms the next panel check
+func (m Model) schedulePanelCheck() tea.Cmd {
+	generation := m.panelGeneration
+	return tea.Tick(panelCheckInterval, func(time.Time) tea.Msg {
+		return panelTickMsg{generation: generation}
+	})
+}
+
+// handlePanelTick refreshes the panel when due and arms the next check,
+// until the panel is hidden
+func (m Model) handlePanelTick(msg panelTickMsg) (Model, tea.Cmd) {
+	if !m.showPanel || msg.generation != m.panelGeneration {
+		return m, nil
+	}
+	return m, tea.Batch(m.refreshPanel(), m.schedulePanelCheck())
+}
+
+// refreshPanel runs the panel's provider for the selected session when it
+// has no output yet or its output is older than the configured interval
+func (m Model) refreshPanel() tea.Cmd {
+	session := m.findSession(m.getSelectedSessionID())
+	if session == nil || m.panels == nil {
+		return nil
+	}
+	if !m.panels.startLoading(session.Core.ID, m.config.TUI.Panel.Interval) {
+		return nil
+	}
+
+	name := m.config.TUI.Panel.Provider
+	commands := m.config.StatusProviders.Commands
+	timeout := m.config.StatusProviders.Timeout
+	selected := *session
+	return func() tea.Msg {
+		msg := panelLoadedMsg{sessionID: selected.Core.ID}
+		provider, err := statusprovider.FindProvider(name, commands)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.text, msg.err = statusprovider.RunPanel(provider, selected, timeout)
+		return msg
+	}
+}
+
+// panelLines renders the provider panel for a session
+func (m Model) panelLines(session types.Session) []string {
+	lines := []string{
+		fmt.Sprintf("%s: %s", m.panelTitle(), session.Core.Name),
+		idleStyle.Render("i: back to details"),
+		"",
+	}
+
+	var entry panelEntry
+	var ok bool
+	if m.panels != nil {
+		entry, ok = m.panels.entry(session.Core.ID)
+	}
+	switch {
+	case !ok || entry.at.IsZero():
+		return append(lines, idleStyle.Render("Loading..."))
+	case entry.err != nil:
+		lines = append(lines, deadStyle.Render("✗ "+sanitizeMessage(entry.err.Error())))
+	case strings.TrimSpace(entry.text) == "":
+		lines = append(lines, idleStyle.Render("Nothing to show"))
+	default:
+		lines = append(lines, renderPanelText(entry.text)...)
+	}
+
+	updated := "updated " + formatActivity(entry.at)
+	if entry.loading {
+		updated += ", refreshing..."
+	}
+	return append(lines, "", idleStyle.Render(updated))
+}
+
+// renderPanelText renders a provider's panel output, plain text or light
+// markdown: headings are bold, list items get bullets and code fences are
+// dropped. Escape sequences are stripped so output can't redraw the screen.
+func renderPanelText(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line = strings.ReplaceAll(ansi.Strip(strings.TrimRight(line, "\r")), "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			lines = append(lines, lipgloss.NewStyle().Bold(true).Render(heading))
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+			lines = append(lines, indent+"• "+trimmed[2:])
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestRenderPanelText(t *testing.T) {
+	lines := renderPanelText("# Coverage\n\n```\n  - state.go\t91%\n* \x1b[31mtui.go\x1b[0m 64%\n```\n")
+	want := []string{"Coverage", "", "  • state.go    91%", "• tui.go 64%"}
+	if len(lines) != len(want) {
+		t.Fatalf("renderPanelText() = %q, want %q", lines, want)
+	}
+	for i := range want {
+		if !strings.Contains(lines[i], want[i]) || strings.Contains(lines[i], "\x1b[31m") {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestProviderPanel(t *testing.T) {
+	dir := t.TempDir()
+	provider := filepath.Join(dir, "cwt-status-coverage")
+	// Prints the session name it was given, and only when asked for a panel
+	script := "#!/bin/sh\n[ \"$1\" = panel ] || exit 1\necho \"# Coverage for $(sed -n 's/.*\"name\":\"\\([^\"]*\\)\".*/\\1/p')\"\n"
+	if err := os.WriteFile(provider, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write provider: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.StatusProviders.Commands = []string{provider}
+	m := Model{
+		config:   cfg,
+		sessions: []types.Session{{Core: types.CoreSession{ID: "1", Name: "feature"}}},
+		panels:   &panelCache{entries: make(map[string]*panelEntry)},
+	}
+
+	// Without a configured panel, the key explains how to set one up
+	m, cmd := m.togglePanel()
+	if m.showPanel || cmd == nil {
+		t.Fatal("togglePanel() without tui.panel should not show the panel")
+	}
+	if msg, ok := cmd().(errorMsg); !ok || !strings.Contains(msg.err.Error(), "tui.panel.provider") {
+		t.Errorf("togglePanel() message = %v, want an error naming the setting", msg)
+	}
+
+	cfg.TUI.Panel.Provider = "coverage"
+	m.showTimeline = true
+	m, _ = m.togglePanel()
+	if !m.showPanel || m.showTimeline {
+		t.Fatalf("showPanel = %v, showTimeline = %v; want the panel in place of the timeline", m.showPanel, m.showTimeline)
+	}
+	if lines := m.detailLines(m.sessions[0], 80); !strings.Contains(strings.Join(lines, "\n"), "Loading") {
+		t.Errorf("detailLines() = %q, want a loading note before the provider answers", lines)
+	}
+
+	// refreshPanel is what the toggle and each tick run
+	m.panels = &panelCache{entries: make(map[string]*panelEntry)}
+	cmd = m.refreshPanel()
+	if cmd == nil {
+		t.Fatal("refreshPanel() = nil, want the provider run")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	if lines := strings.Join(m.detailLines(m.sessions[0], 80), "\n"); !strings.Contains(lines, "Coverage for feature") {
+		t.Errorf("detailLines() = %q, want the provider's output", lines)
+	}
+
+	// Fresh output isn't fetched again until the interval passes
+	if m.refreshPanel() != nil {
+		t.Error("refreshPanel() ran the provider again within the interval")
+	}
+
+	// Ticks from before the panel was toggled off stop
+	generation := m.panelGeneration
+	m, _ = m.togglePanel()
+	if _, cmd := m.handlePanelTick(panelTickMsg{generation: generation}); cmd != nil {
+		t.Error("a stale panel tick should not schedule another")
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// priorityCycle is the order the priority key moves a session through
+var priorityCycle = []types.Priority{types.PriorityNormal, types.PriorityHigh, types.PriorityLow}
+
+// nextPriority returns the priority after current in the cycle
+func nextPriority(current types.Priority) types.Priority {
+	for i, priority := range priorityCycle {
+		if priority == current {
+			return priorityCycle[(i+1)%len(priorityCycle)]
+		}
+	}
+	return priorityCycle[0]
+}
+
+// cyclePriority moves the selected session to the next priority; the
+// toast's refresh re-sorts the list
+func (m Model) cyclePriority() tea.Cmd {
+	session := m.findSession(m.getSelectedSessionID())
+	if session == nil {
+		return nil
+	}
+	id, name := session.Core.ID, session.Core.Name
+	priority := nextPriority(session.Core.SessionPriority())
+
+	return func() tea.Msg {
+		if err := m.stateManager.SetSessionPriority(id, priority); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to set priority of '%s': %w", name, err)}
+		}
+		return successToastMsg{message: fmt.Sprintf("'%s' is now %s priority", name, operations.NewStatusFormat().FormatPriority(priority))}
+	}
+}
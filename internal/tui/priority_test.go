@@ -0,0 +1,20 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestNextPriority_Cycles(t *testing.T) {
+	got := []types.Priority{types.PriorityNormal}
+	for range priorityCycle {
+		got = append(got, nextPriority(got[len(got)-1]))
+	}
+	want := []types.Priority{types.PriorityNormal, types.PriorityHigh, types.PriorityLow, types.PriorityNormal}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("priority cycle = %v, want %v", got, want)
+		}
+	}
+}
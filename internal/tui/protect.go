@@ -0,0 +1,44 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// protected reports whether merges and switches need their phrase typed,
+// like the CLI asks for in protected mode
+func (m Model) protected() bool {
+	return m.config != nil && m.config.Protected
+}
+
+// handleConfirmPhraseKeys handles typing the phrase of a protected
+// operation into a confirmation dialog; only the exact phrase confirms
+func (m Model) handleConfirmPhraseKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	dialog := m.confirmDialog
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m, func() tea.Msg { return confirmNoMsg{} }
+
+	case tea.KeyEnter:
+		if dialog.Typed != dialog.Phrase {
+			return m, nil
+		}
+		return m, func() tea.Msg { return confirmYesMsg{} }
+
+	case tea.KeyBackspace:
+		if runes := []rune(dialog.Typed); len(runes) > 0 {
+			dialog.Typed = string(runes[:len(runes)-1])
+		}
+		return m, nil
+
+	case tea.KeySpace:
+		dialog.Typed += " "
+		return m, nil
+
+	case tea.KeyRunes:
+		dialog.Typed += string(msg.Runes)
+		return m, nil
+	}
+
+	return m, nil
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+)
+
+func TestProtectedConfirmation(t *testing.T) {
+	cfg := config.Default()
+	cfg.Protected = true
+	m := Model{sessions: filterTestSessions(), sortOrder: config.SortName, config: cfg}
+	session := m.sessions[0]
+
+	msg := m.switchToSessionBranch(session.Core.ID)()
+	dialog, ok := msg.(showConfirmDialogMsg)
+	if !ok {
+		t.Fatalf("switchToSessionBranch returned %T, want showConfirmDialogMsg", msg)
+	}
+	if want := "switch to " + session.BranchName(); dialog.phrase != want {
+		t.Fatalf("phrase = %q, want %q", dialog.phrase, want)
+	}
+	m, _ = m.handleShowConfirmDialog(dialog)
+
+	// A plain yes doesn't confirm; only the exact phrase does
+	key := func(m Model, msg tea.KeyMsg) (Model, tea.Msg) {
+		m, cmd := m.handleKeyPress(msg)
+		if cmd == nil {
+			return m, nil
+		}
+		return m, cmd()
+	}
+	for _, k := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("y")}, {Type: tea.KeyEnter}} {
+		var msg tea.Msg
+		if m, msg = key(m, k); msg != nil {
+			t.Fatalf("%s confirmed a protected switch: %T", k, msg)
+		}
+	}
+	m.confirmDialog.Typed = ""
+	words := strings.Fields(dialog.phrase)
+	for i, word := range words {
+		m, _ = key(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(word)})
+		if i < len(words)-1 {
+			m, _ = key(m, tea.KeyMsg{Type: tea.KeySpace})
+		}
+	}
+	if _, msg := key(m, tea.KeyMsg{Type: tea.KeyEnter}); msg != (confirmYesMsg{}) {
+		t.Errorf("typing %q and enter = %T, want it confirmed", dialog.phrase, msg)
+	}
+
+	// Without protected mode the usual yes/no dialog is shown
+	m.config = config.Default()
+	if dialog := m.switchToSessionBranch(session.Core.ID)().(showConfirmDialogMsg); dialog.phrase != "" {
+		t.Errorf("phrase = %q without protected mode, want none", dialog.phrase)
+	}
+}
+
+func TestConfirmBulk_Protected(t *testing.T) {
+	cfg := config.Default()
+	cfg.Protected = true
+	m := Model{
+		sessions:  filterTestSessions(),
+		sortOrder: config.SortName,
+		marked:    map[string]bool{"1": true, "2": true},
+		config:    cfg,
+	}
+
+	msg, ok := m.confirmBulk(bulkMerge)().(errorMsg)
+	if !ok || !strings.Contains(msg.err.Error(), "one at a time") {
+		t.Errorf("bulk merge in protected mode = %v, want it refused", msg)
+	}
+	if _, ok := m.confirmBulk(bulkPublish)().(showConfirmDialogMsg); !ok {
+		t.Error("publishing isn't protected, so it should still run in bulk")
+	}
+}
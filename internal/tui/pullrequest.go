@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+type (
+	pullRequestPollMsg    struct{}
+	pullRequestCheckedMsg struct{ changed int }
+)
+
+// checkPullRequests asks the forges about the open pull requests not
+// checked lately, by the daemon or another cwt
+func (m Model) checkPullRequests() tea.Cmd {
+	return func() tea.Msg {
+		return pullRequestCheckedMsg{changed: m.stateManager.RefreshPullRequests(state.PullRequestCheckInterval)}
+	}
+}
+
+// startPullRequestPolling checks the pull requests again once their status
+// is due
+func (m Model) startPullRequestPolling() tea.Cmd {
+	return tea.Tick(state.PullRequestCheckInterval, func(time.Time) tea.Msg {
+		return pullRequestPollMsg{}
+	})
+}
+
+// handlePullRequestChecked refreshes the sessions whose pull request
+// changed status, and schedules the next check
+func (m Model) handlePullRequestChecked(msg pullRequestCheckedMsg) (Model, tea.Cmd) {
+	if msg.changed > 0 {
+		return m, tea.Batch(m.refreshSessions(), m.startPullRequestPolling())
+	}
+	return m, m.startPullRequestPolling()
+}
+
+// pullRequestBadge marks a session's pull request in the session list, in
+// red when its checks fail or changes are requested, in green once
+// approved or merged, and its visual width
+func pullRequestBadge(pr *types.PullRequest) (string, int) {
+	if pr == nil {
+		return "", 0
+	}
+	status := pr.Status
+	switch {
+	case status.State == types.PullRequestMerged:
+		return " " + aliveStyle.Render("PR✓"), 4
+	case status.State == types.PullRequestClosed:
+		return " " + idleStyle.Render("PR✗"), 4
+	case status.Checks == types.ChecksFailing || status.Review == types.ReviewChangesRequested:
+		return " " + deadStyle.Render("PR"), 3
+	case status.Review == types.ReviewApproved && status.Checks != types.ChecksPending:
+		return " " + aliveStyle.Render("PR"), 3
+	case status.Review == types.ReviewRequested || status.Checks == types.ChecksPending:
+		return " " + waitingStyle.Render("PR"), 3
+	}
+	return " " + idleStyle.Render("PR"), 3
+}
+
+// pullRequestLines describe a session's pull request in the details panel
+func pullRequestLines(pr *types.PullRequest) []string {
+	if pr == nil {
+		return nil
+	}
+	formatter := operations.NewStatusFormat()
+	lines := []string{"Pull request: " + formatter.FormatPullRequest(*pr), idleStyle.Render("  " + pr.URL)}
+	if pr.Error != "" {
+		lines = append(lines, deadStyle.Render("  Couldn't check it: "+sanitizeMessage(pr.Error)))
+	} else if pr.CheckedAt != nil {
+		lines = append(lines, idleStyle.Render("  Checked "+formatter.FormatActivity(*pr.CheckedAt)))
+	}
+	return append(lines, "")
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+// reconcileOnStart compares the sessions with the tmux sessions, worktrees
+// and branches there are, and reports what drifted apart while the
+// dashboard wasn't running, with keys that fix it
+func (m Model) reconcileOnStart() tea.Cmd {
+	return func() tea.Msg {
+		report, err := m.stateManager.Reconcile()
+		if err != nil {
+			logger.Warn("startup reconciliation failed", "error", err)
+			return nil
+		}
+		if report.Empty() {
+			return nil
+		}
+		return resultPanelMsg{panel: reconcileResultPanel(report)}
+	}
+}
+
+// reconcileResultPanel lists the discrepancies of a reconciliation, offering
+// to fix them all or those of a kind
+func reconcileResultPanel(report state.ReconcileReport) *ResultPanel {
+	panel := &ResultPanel{
+		Title:  fmt.Sprintf("Found %d problem(s) with your sessions", len(report.Discrepancies)),
+		Failed: true,
+	}
+	var manual bool
+	lines := make([]string, len(report.Discrepancies))
+	for i, d := range report.Discrepancies {
+		lines[i] = d.Describe()
+		if d.Fix == "" {
+			lines[i] += " (fix by hand)"
+			manual = true
+		}
+	}
+	panel.Lines = limitedList(lines)
+	if manual {
+		panel.Lines = append(panel.Lines, "", "Commit or copy what you need from those, then run: cwt cleanup")
+	}
+
+	groups := []struct {
+		key, label string
+		kinds      []state.DriftKind
+	}{
+		{"f", "fix all", nil},
+		{"r", "restart dead sessions", []state.DriftKind{state.DriftDeadTmux}},
+		{"w", "recreate worktrees", []state.DriftKind{state.DriftMissingWorktree}},
+		{"x", "remove orphans", []state.DriftKind{state.DriftOrphanedTmux, state.DriftOrphanedWorktree}},
+	}
+	for _, group := range groups {
+		fixable := report.Fixable(group.kinds...)
+		if len(fixable) == 0 {
+			continue
+		}
+		panel.Actions = append(panel.Actions, ResultAction{
+			Key:   group.key,
+			Label: fmt.Sprintf("%s (%d)", group.label, len(fixable)),
+			Run: func(m Model) (Model, tea.Cmd) {
+				return m, m.fixDiscrepancies(fixable)
+			},
+		})
+	}
+	return panel
+}
+
+// fixDiscrepancies fixes discrepancies in the background, reporting the
+// ones it couldn't fix in a result panel
+func (m Model) fixDiscrepancies(discrepancies []state.Discrepancy) tea.Cmd {
+	return func() tea.Msg {
+		fixed, err := m.stateManager.FixDiscrepancies(discrepancies)
+		if err == nil {
+			return successToastMsg{message: fmt.Sprintf("Fixed %d problem(s)", fixed)}
+		}
+
+		panel := &ResultPanel{
+			Title:  fmt.Sprintf("Fixed %d of %d problem(s)", fixed, len(discrepancies)),
+			Failed: true,
+		}
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, err := range joined.Unwrap() {
+				panel.Lines = append(panel.Lines, sanitizeMessage(err.Error()))
+			}
+		} else {
+			panel.Lines = append(panel.Lines, sanitizeMessage(err.Error()))
+		}
+		return resultPanelMsg{panel: panel}
+	}
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+// setupConfigWatching starts watching the config files so edits apply
+// without restarting the dashboard
+func (m Model) setupConfigWatching() tea.Cmd {
+	return func() tea.Msg {
+		watcher, err := config.NewWatcher(m.stateManager.GetDataDir())
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return configWatcherSetupMsg{watcher: watcher}
+	}
+}
+
+// waitForConfigChange delivers the next change to the config files
+func waitForConfigChange(watcher *config.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-watcher.Changes(); !ok {
+			return nil
+		}
+		return configChangedMsg{}
+	}
+}
+
+// applyReloadedConfig applies the settings that can change at runtime:
+// polling intervals take effect from the next poll, file event batching
+// from the next event, and the status cache TTL from the next derivation
+func (m Model) applyReloadedConfig(next *config.Config) (Model, tea.Cmd) {
+	// The sort key saves its choice to the config, which is already applied
+	current := *m.config
+	current.TUI.Sort = m.sortOrder
+
+	cfg, changed := current.Reloaded(next)
+	if len(changed) == 0 {
+		return m, nil
+	}
+	m.config = cfg
+
+	if m.coalescer != nil {
+		m.coalescer.configure(cfg.FileEvents)
+	}
+	m.stateManager.SetStatusCacheTTL(cfg.StatusCacheTTL)
+	m.stateManager.SetLimits(state.Limits{
+		SessionsPerHour: cfg.Limits.SessionsPerHour,
+		MaxWorking:      cfg.Limits.MaxWorking,
+		MonthlyTokens:   cfg.Limits.MonthlyTokens,
+		WarnAt:          cfg.Limits.WarnAt,
+		SessionCost:     cfg.Limits.SessionCost,
+	})
+	if slices.Contains(changed, "tui") && cfg.TUI.Sort != m.sortOrder {
+		selectedID := m.getSelectedSessionID()
+		m.sortOrder = cfg.TUI.Sort
+		m = m.reselect(selectedID)
+	}
+	m.stateManager.NotifyConfigReloaded(changed)
+
+	return m, func() tea.Msg {
+		return successToastMsg{message: fmt.Sprintf("Reloaded config: %s", strings.Join(changed, ", "))}
+	}
+}
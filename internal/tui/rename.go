@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleShowRenameDialog opens the rename dialog for the selected session,
+// starting from its current name
+func (m Model) handleShowRenameDialog() (Model, tea.Cmd) {
+	session := m.findSession(m.getSelectedSessionID())
+	if session == nil {
+		return m, nil
+	}
+
+	m.renameDialog = &RenameDialog{
+		SessionID:   session.Core.ID,
+		SessionName: session.Core.Name,
+		Input:       session.Core.Name,
+	}
+	return m, nil
+}
+
+// handleRenameDialogKeys handles keyboard input for the rename dialog
+func (m Model) handleRenameDialogKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	dialog := m.renameDialog
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.renameDialog = nil
+		return m, nil
+
+	case tea.KeyEnter:
+		name := strings.TrimSpace(dialog.Input)
+		if name == "" {
+			dialog.Error = "Session name is required"
+			return m, nil
+		}
+		if name == dialog.SessionName {
+			m.renameDialog = nil
+			return m, nil
+		}
+		if m.findSession(dialog.SessionID) == nil {
+			dialog.Error = "Session no longer exists"
+			return m, nil
+		}
+		m.renameDialog = nil
+		return m, m.renameSession(dialog.SessionID, dialog.SessionName, name)
+
+	case tea.KeyBackspace:
+		if runes := []rune(dialog.Input); len(runes) > 0 {
+			dialog.Input = string(runes[:len(runes)-1])
+		}
+		dialog.Error = ""
+		return m, nil
+
+	case tea.KeyRunes:
+		// Runes may hold several characters when text is pasted; session
+		// names can't contain spaces, so there is no case for KeySpace
+		dialog.Input += string(msg.Runes)
+		dialog.Error = ""
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// renameSession renames a session along with its branch and tmux session;
+// the state manager undoes a partial rename if a step fails
+func (m Model) renameSession(sessionID, oldName, newName string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.stateManager.RenameSession(sessionID, newName); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to rename '%s': %w", oldName, err)}
+		}
+		return successToastMsg{message: fmt.Sprintf("Renamed '%s' to '%s'", oldName, newName)}
+	}
+}
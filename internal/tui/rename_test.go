@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+func TestRenameDialog(t *testing.T) {
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	if err := sm.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	sessions, _ := sm.DeriveFreshSessions()
+	m := Model{stateManager: sm, sessions: sessions}
+	press := func(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+		return m.handleKeyPress(msg)
+	}
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.renameDialog == nil || m.renameDialog.Input != "auth" {
+		t.Fatalf("rename dialog = %+v, want it to start from the current name", m.renameDialog)
+	}
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-login")})
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.renameDialog != nil || cmd == nil {
+		t.Fatal("enter should close the dialog and rename the session")
+	}
+	if msg, ok := cmd().(successToastMsg); !ok || !strings.Contains(msg.message, "'auth-login'") {
+		t.Errorf("rename = %#v, want a success toast", msg)
+	}
+
+	cores, _ := sm.CoreSessions()
+	if cores[0].Name != "auth-login" {
+		t.Errorf("session name = %q, want auth-login", cores[0].Name)
+	}
+}
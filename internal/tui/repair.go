@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// repairSession asks to recreate the broken worktree of a session from its
+// branch, then repairs it
+func (m Model) repairSession(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		session := m.findSession(sessionID)
+		if session == nil {
+			return errorMsg{err: fmt.Errorf("session not found")}
+		}
+		if !session.GitStatus.WorktreeBroken() {
+			return errorMsg{err: fmt.Errorf("the worktree of session '%s' is not broken", session.Core.Name)}
+		}
+
+		name := session.Core.Name
+		return showConfirmDialogMsg{
+			message: fmt.Sprintf("Recreate the worktree of '%s' from branch %s?", name, session.BranchName()),
+			onYes: func() tea.Cmd {
+				return func() tea.Msg {
+					result, err := m.stateManager.RepairWorktree(sessionID)
+					if err != nil {
+						return errorMsg{err: fmt.Errorf("failed to repair '%s': %w", name, err)}
+					}
+					message := fmt.Sprintf("Recreated the worktree of '%s' from %s", name, result.Branch)
+					if result.MovedAside != "" {
+						message += "; the broken directory was moved aside"
+					}
+					return successToastMsg{message: message}
+				}
+			},
+			onNo: func() tea.Cmd { return nil },
+		}
+	}
+}
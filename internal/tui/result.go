@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// resultListLimit is how many commits or files a result panel lists
+const resultListLimit = 8
+
+// ResultPanel shows what an operation run from the dashboard did, like the
+// commits a merge brought in, with the follow-ups that make sense next
+type ResultPanel struct {
+	Title   string
+	Failed  bool
+	Lines   []string
+	Actions []ResultAction
+}
+
+// ResultAction is a follow-up offered by a result panel, run with its key
+type ResultAction struct {
+	Key   string
+	Label string
+	Run   func(m Model) (Model, tea.Cmd)
+}
+
+// resultPanelMsg opens a result panel
+type resultPanelMsg struct {
+	panel *ResultPanel
+}
+
+// mergeResultPanel describes a merge run from the dashboard
+func mergeResultPanel(sessionID string, result types.MergeResultOutput, err error) *ResultPanel {
+	panel := &ResultPanel{}
+	switch {
+	case result.Merged:
+		panel.Title = fmt.Sprintf("Merged '%s' into %s", result.Session, result.Target)
+	case len(result.Conflicts) > 0:
+		panel.Title = fmt.Sprintf("Merging '%s' into %s stopped on conflicts", result.Session, result.Target)
+		panel.Failed = true
+	default:
+		panel.Title = fmt.Sprintf("Failed to merge '%s'", result.Session)
+		panel.Failed = true
+	}
+
+	if result.Commit != "" {
+		panel.Lines = append(panel.Lines, fmt.Sprintf("Created commit %s", shortHash(result.Commit)), "")
+	}
+	if len(result.Conflicts) > 0 {
+		panel.Lines = append(panel.Lines, fmt.Sprintf("Conflicts in %d file(s):", len(result.Conflicts)))
+		panel.Lines = append(panel.Lines, limitedList(result.Conflicts)...)
+		panel.Lines = append(panel.Lines, "")
+	} else if err != nil {
+		panel.Lines = append(panel.Lines, sanitizeMessage(err.Error()), "")
+	}
+	if len(result.Commits) > 0 {
+		commits := make([]string, len(result.Commits))
+		for i, commit := range result.Commits {
+			commits[i] = fmt.Sprintf("%s %s", shortHash(commit.Hash), commit.Subject)
+		}
+		panel.Lines = append(panel.Lines, fmt.Sprintf("%d commit(s):", len(commits)))
+		panel.Lines = append(panel.Lines, limitedList(commits)...)
+	}
+	if len(result.Files) > 0 {
+		panel.Lines = append(panel.Lines, fmt.Sprintf("%d file(s) changed:", len(result.Files)))
+		panel.Lines = append(panel.Lines, limitedList(result.Files)...)
+	}
+
+	switch {
+	case result.Merged:
+		panel.Actions = append(panel.Actions, ResultAction{
+			Key:   "d",
+			Label: "delete session",
+			Run:   deleteSessionAction(sessionID).Run,
+		})
+	case len(result.Conflicts) > 0:
+		panel.Actions = append(panel.Actions, ResultAction{
+			Key:   "v",
+			Label: "view conflicts",
+			Run:   viewConflictsAction().Run,
+		})
+	}
+	return panel
+}
+
+// publishResultPanel describes a publish run from the dashboard
+func publishResultPanel(sessionID string, result types.PublishResultOutput, err error) *ResultPanel {
+	panel := &ResultPanel{Title: fmt.Sprintf("Published '%s'", result.Session)}
+	if err != nil {
+		panel.Title = fmt.Sprintf("Failed to publish '%s'", result.Session)
+		panel.Failed = true
+		panel.Lines = append(panel.Lines, sanitizeMessage(err.Error()), "")
+	}
+
+	if result.Commit != nil {
+		panel.Lines = append(panel.Lines, fmt.Sprintf("Committed %s %s", shortHash(result.Commit.Hash), result.Commit.Subject))
+		if len(result.Files) > 0 {
+			panel.Lines = append(panel.Lines, fmt.Sprintf("%d file(s):", len(result.Files)))
+			panel.Lines = append(panel.Lines, limitedList(result.Files)...)
+		}
+	} else if err == nil {
+		panel.Lines = append(panel.Lines, "No uncommitted changes to commit")
+	}
+	if result.Pushed {
+		panel.Lines = append(panel.Lines, fmt.Sprintf("Pushed %s to %s", result.Branch, result.Remote))
+	}
+	if result.PRURL != "" {
+		panel.Lines = append(panel.Lines, fmt.Sprintf("Pull request: %s", result.PRURL))
+	}
+	for _, warning := range result.Warnings {
+		panel.Lines = append(panel.Lines, "⚠ "+warning)
+	}
+
+	if result.PRURL != "" {
+		url := result.PRURL
+		panel.Actions = append(panel.Actions, ResultAction{
+			Key:   "u",
+			Label: "copy PR URL",
+			Run: func(m Model) (Model, tea.Cmd) {
+				return m, func() tea.Msg { return copyText("PR URL", url) }
+			},
+		})
+	} else if result.Pushed {
+		panel.Actions = append(panel.Actions, ResultAction{
+			Key:   "u",
+			Label: "copy PR URL",
+			Run:   copyPRURLAction(sessionID).Run,
+		})
+	}
+	if result.Pushed || result.Commit != nil {
+		branch := result.Branch
+		panel.Actions = append(panel.Actions, ResultAction{
+			Key:   "b",
+			Label: "copy branch name",
+			Run: func(m Model) (Model, tea.Cmd) {
+				return m, func() tea.Msg { return copyText("branch name", branch) }
+			},
+		})
+	}
+	return panel
+}
+
+// handleResultPanelKeys runs a result panel's follow-ups, or closes it
+func (m Model) handleResultPanelKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	panel := m.resultPanel
+	switch msg.String() {
+	case "esc", "enter", "q":
+		m.resultPanel = nil
+		return m, nil
+	}
+
+	for _, action := range panel.Actions {
+		if msg.String() == action.Key {
+			m.resultPanel = nil
+			return action.Run(m)
+		}
+	}
+	return m, nil
+}
+
+// renderWithResultPanel renders a result panel on a clean screen
+func (m Model) renderWithResultPanel(content string) string {
+	panel := m.resultPanel
+
+	title := aliveStyle.Render("✓ " + panel.Title)
+	if panel.Failed {
+		title = deadStyle.Render("✗ " + panel.Title)
+	}
+	var keys []string
+	for _, action := range panel.Actions {
+		keys = append(keys, fmt.Sprintf("%s: %s", action.Key, action.Label))
+	}
+	keys = append(keys, "esc: close")
+	return m.renderPanel(title, panel.Lines, keys)
+}
+
+// renderPanel renders a titled box of lines with the keys it takes below,
+// centered on a clean screen
+func (m Model) renderPanel(title string, body, keys []string) string {
+	lines := []string{title, ""}
+	lines = append(lines, body...)
+
+	lines = append(lines, "")
+	lines = append(lines, idleStyle.Render(strings.Join(keys, "  ")))
+
+	dialogBox := confirmStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		dialogBox,
+	)
+}
+
+// limitedList indents items for a result panel, listing at most
+// resultListLimit of them
+func limitedList(items []string) []string {
+	var lines []string
+	for i, item := range items {
+		if i == resultListLimit {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(items)-resultListLimit))
+			break
+		}
+		lines = append(lines, "  "+item)
+	}
+	return lines
+}
+
+// shortHash abbreviates a commit hash
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func panelKeys(panel *ResultPanel) string {
+	var keys []string
+	for _, action := range panel.Actions {
+		keys = append(keys, action.Key)
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestMergeResultPanel(t *testing.T) {
+	merged := types.NewMergeResultOutput("auth", "cwt-auth", "main", false)
+	merged.Merged = true
+	merged.Commit = "0123456789abcdef"
+	merged.Commits = []types.CommitOutput{{Hash: "fedcba9876543210", Subject: "Add login"}}
+	merged.Files = []string{"login.go"}
+
+	panel := mergeResultPanel("1", merged, nil)
+	if panel.Failed || !strings.Contains(panel.Title, "Merged 'auth' into main") {
+		t.Errorf("panel = %+v, want a successful merge", panel)
+	}
+	body := strings.Join(panel.Lines, "\n")
+	for _, want := range []string{"Created commit 0123456", "fedcba9 Add login", "  login.go"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("lines = %q, want %q", body, want)
+		}
+	}
+	if keys := panelKeys(panel); keys != "d" {
+		t.Errorf("actions = %q, want delete offered", keys)
+	}
+
+	conflicted := types.NewMergeResultOutput("auth", "cwt-auth", "main", false)
+	conflicted.Conflicts = []string{"login.go"}
+	panel = mergeResultPanel("1", conflicted, errors.New("merge failed: conflicts"))
+	if !panel.Failed || !strings.Contains(panel.Title, "conflicts") {
+		t.Errorf("panel = %+v, want the conflicts reported", panel)
+	}
+	if keys := panelKeys(panel); keys != "v" {
+		t.Errorf("actions = %q, want view conflicts offered", keys)
+	}
+}
+
+func TestPublishResultPanel(t *testing.T) {
+	result := types.NewPublishResultOutput("auth", "cwt-auth")
+	result.Commit = &types.CommitOutput{Hash: "0123456789abcdef", Subject: "feat(auth): login"}
+	result.Pushed, result.Remote = true, "origin"
+	result.PRURL = "https://github.com/o/r/pull/7"
+
+	panel := publishResultPanel("1", result, nil)
+	body := strings.Join(panel.Lines, "\n")
+	for _, want := range []string{"Committed 0123456 feat(auth): login", "Pushed cwt-auth to origin", "pull/7"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("lines = %q, want %q", body, want)
+		}
+	}
+	if keys := panelKeys(panel); keys != "u,b" {
+		t.Errorf("actions = %q, want copying the PR URL and branch offered", keys)
+	}
+
+	local := types.NewPublishResultOutput("auth", "cwt-auth")
+	local.Warnings = []string{"No remote repository configured, so the branch was not pushed"}
+	panel = publishResultPanel("1", local, nil)
+	if body := strings.Join(panel.Lines, "\n"); !strings.Contains(body, "No uncommitted changes") || !strings.Contains(body, "No remote") {
+		t.Errorf("lines = %q, want nothing committed and the warning", body)
+	}
+	if len(panel.Actions) != 0 {
+		t.Errorf("actions = %q, want none with nothing published", panelKeys(panel))
+	}
+}
+
+func TestHandleResultPanelKeys(t *testing.T) {
+	ran := false
+	m := Model{resultPanel: &ResultPanel{Actions: []ResultAction{{
+		Key: "d",
+		Run: func(m Model) (Model, tea.Cmd) { ran = true; return m, nil },
+	}}}}
+
+	m, _ = m.handleResultPanelKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m.resultPanel == nil || ran {
+		t.Error("an unbound key should leave the panel open")
+	}
+	m, _ = m.handleResultPanelKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if m.resultPanel != nil || !ran {
+		t.Error("an action key should close the panel and run the action")
+	}
+
+	m.resultPanel = &ResultPanel{}
+	m, _ = m.handleResultPanelKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.resultPanel != nil {
+		t.Error("esc should close the panel")
+	}
+}
+
+func TestReconcileResultPanel(t *testing.T) {
+	report := state.ReconcileReport{Discrepancies: []state.Discrepancy{
+		{Kind: state.DriftDeadTmux, Session: "auth", Resource: "cwt-auth", Fix: "restart it"},
+		{Kind: state.DriftOrphanedTmux, Resource: "cwt-old", Fix: "kill it"},
+		{Kind: state.DriftOrphanedWorktree, Resource: ".cwt/worktrees/dirty"},
+	}}
+
+	panel := reconcileResultPanel(report)
+	if !panel.Failed || !strings.Contains(panel.Title, "3 problem(s)") {
+		t.Errorf("panel = %+v, want the problems reported", panel)
+	}
+	if keys := panelKeys(panel); keys != "f,r,x" {
+		t.Errorf("actions = %q, want fix all, restart and remove orphans", keys)
+	}
+	if body := strings.Join(panel.Lines, "\n"); !strings.Contains(body, "dirty belongs to no session and has uncommitted changes (fix by hand)") {
+		t.Errorf("lines = %q, want the worktree with changes left to fix by hand", body)
+	}
+}
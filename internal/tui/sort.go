@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// claudeStateRank orders Claude states by how much they need the user
+var claudeStateRank = map[types.ClaudeState]int{
+	types.ClaudeWaiting:  0,
+	types.ClaudeWorking:  1,
+	types.ClaudeComplete: 2,
+	types.ClaudeIdle:     3,
+
+	types.ClaudeNoAgent:    4,
+	types.ClaudeNotStarted: 5,
+	types.ClaudeUnknown:    6,
+}
+
+// sortSessions orders sessions in place, keeping watched sessions at the
+// top and muted ones at the bottom. Ties keep their creation order.
+func sortSessions(sessions []types.Session, order string) {
+	var less func(a, b types.Session) bool
+	switch order {
+	case config.SortName:
+		less = func(a, b types.Session) bool { return a.Core.Name < b.Core.Name }
+	case config.SortActivity:
+		less = func(a, b types.Session) bool { return a.LastActivity.After(b.LastActivity) }
+	case config.SortClaude:
+		less = func(a, b types.Session) bool {
+			return claudeStateRank[a.ClaudeStatus.State] < claudeStateRank[b.ClaudeStatus.State]
+		}
+	case config.SortChanges:
+		less = func(a, b types.Session) bool { return changedFileCount(a) > changedFileCount(b) }
+	case config.SortPriority:
+		less = func(a, b types.Session) bool {
+			return a.Core.SessionPriority().Rank() < b.Core.SessionPriority().Rank()
+		}
+	default:
+		// sessions.json is already in creation order
+		less = func(a, b types.Session) bool { return false }
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if a, b := sessions[i].Core.AttentionRank(), sessions[j].Core.AttentionRank(); a != b {
+			return a < b
+		}
+		return less(sessions[i], sessions[j])
+	})
+}
+
+// changedFileCount returns how many files a session has changed
+func changedFileCount(session types.Session) int {
+	status := session.GitStatus
+	return len(status.ModifiedFiles) + len(status.AddedFiles) + len(status.DeletedFiles) + len(status.UntrackedFiles)
+}
+
+// nextSortOrder returns the sort order after current in the cycle
+func nextSortOrder(current string) string {
+	for i, order := range config.SortOrders {
+		if order == current {
+			return config.SortOrders[(i+1)%len(config.SortOrders)]
+		}
+	}
+	return config.SortOrders[0]
+}
+
+// cycleSort switches to the next sort order, keeping the selected session
+// selected, and saves the choice to the user config unless the dashboard is
+// read-only
+func (m Model) cycleSort() (Model, tea.Cmd) {
+	selectedID := m.getSelectedSessionID()
+	m.sortOrder = nextSortOrder(m.sortOrder)
+	m = m.reselect(selectedID)
+
+	// A read-only dashboard sorts without writing the config
+	if m.readOnly {
+		return m, nil
+	}
+	order := m.sortOrder
+	return m, func() tea.Msg {
+		if err := config.SetUserValue([]string{"tui", "sort"}, order); err != nil {
+			return errorMsg{err: err}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func sortTestSessions() []types.Session {
+	now := time.Now()
+	return []types.Session{
+		{Core: types.CoreSession{ID: "1", Name: "zeta"}, LastActivity: now.Add(-time.Hour),
+			ClaudeStatus: types.ClaudeStatus{State: types.ClaudeIdle},
+			GitStatus:    types.GitStatus{ModifiedFiles: []string{"a.go"}}},
+		{Core: types.CoreSession{ID: "2", Name: "alpha", Priority: types.PriorityLow}, LastActivity: now,
+			ClaudeStatus: types.ClaudeStatus{State: types.ClaudeWorking}},
+		{Core: types.CoreSession{ID: "3", Name: "mid", Priority: types.PriorityHigh}, LastActivity: now.Add(-time.Minute),
+			ClaudeStatus: types.ClaudeStatus{State: types.ClaudeWaiting},
+			GitStatus:    types.GitStatus{ModifiedFiles: []string{"a.go"}, UntrackedFiles: []string{"b.go"}}},
+	}
+}
+
+func TestSortSessions(t *testing.T) {
+	tests := []struct {
+		order string
+		want  string
+	}{
+		{order: config.SortCreated, want: "1,2,3"},
+		{order: config.SortName, want: "2,3,1"},
+		{order: config.SortActivity, want: "2,3,1"},
+		{order: config.SortClaude, want: "3,2,1"},
+		{order: config.SortChanges, want: "3,1,2"},
+		{order: config.SortPriority, want: "3,1,2"},
+	}
+
+	for _, tt := range tests {
+		sessions := sortTestSessions()
+		sortSessions(sessions, tt.order)
+
+		var ids []string
+		for _, session := range sessions {
+			ids = append(ids, session.Core.ID)
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("sortSessions(%s) = %s, want %s", tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestCycleSort_KeepsSelectionAndPersists(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+
+	m := Model{sessions: sortTestSessions(), sortOrder: config.SortCreated, selectedIndex: 0}
+
+	m, cmd := m.cycleSort()
+	if m.sortOrder != config.SortName {
+		t.Fatalf("sortOrder = %q, want %q", m.sortOrder, config.SortName)
+	}
+	if got := m.getSelectedSessionID(); got != "1" {
+		t.Errorf("selected %q after sorting, want session 1 to stay selected", got)
+	}
+
+	if msg := cmd(); msg != nil {
+		t.Fatalf("saving sort order returned %v", msg)
+	}
+	cfg, err := config.Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	if cfg.TUI.Sort != config.SortName {
+		t.Errorf("persisted sort = %q, want %q", cfg.TUI.Sort, config.SortName)
+	}
+}
+
+func TestNextSortOrder_Cycles(t *testing.T) {
+	order := config.SortCreated
+	for range config.SortOrders {
+		order = nextSortOrder(order)
+	}
+	if order != config.SortCreated {
+		t.Errorf("cycling through all orders ended at %q, want %q", order, config.SortCreated)
+	}
+}
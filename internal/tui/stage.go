@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// diffView is what the diff view compares
+type diffView int
+
+const (
+	diffViewTarget   diffView = iota // Working tree against the comparison target
+	diffViewUnstaged                 // Working tree against the index; changes can be staged
+	diffViewStaged                   // Index against HEAD; changes can be unstaged
+)
+
+// next returns the view the view toggle switches to
+func (v diffView) next() diffView {
+	return (v + 1) % 3
+}
+
+// stageCurrent stages the hunk at the top of the unstaged view, or its whole
+// file when wholeFile is set
+func (m Model) stageCurrent(wholeFile bool) tea.Cmd {
+	if m.diffMode == nil || m.diffMode.view != diffViewUnstaged {
+		return errorCmd(fmt.Errorf("press 'c' for the unstaged view to stage changes"))
+	}
+	return m.updateIndex(wholeFile, false)
+}
+
+// unstageCurrent unstages the hunk at the top of the staged view, or its
+// whole file when wholeFile is set
+func (m Model) unstageCurrent(wholeFile bool) tea.Cmd {
+	if m.diffMode == nil || m.diffMode.view != diffViewStaged {
+		return errorCmd(fmt.Errorf("press 'c' for the staged view to unstage changes"))
+	}
+	return m.updateIndex(wholeFile, true)
+}
+
+// updateIndex moves the current hunk or file into the index, or out of it
+// when unstage is set, without touching the worktree's files
+func (m Model) updateIndex(wholeFile, unstage bool) tea.Cmd {
+	lines := m.diffMode.visibleDiffLines()
+	fileIndex := m.diffMode.currentFile()
+	if fileIndex < 0 {
+		return errorCmd(fmt.Errorf("no changes to stage"))
+	}
+	file := m.diffMode.files()[fileIndex].Name
+	worktreePath := m.diffMode.session.Core.WorktreePath
+	checker := m.stateManager.GetGitChecker()
+
+	verb := "Staged"
+	if unstage {
+		verb = "Unstaged"
+	}
+
+	if wholeFile {
+		return func() tea.Msg {
+			var err error
+			if unstage {
+				err = checker.UnstageFile(worktreePath, file)
+			} else {
+				err = checker.StageFile(worktreePath, file)
+			}
+			if err != nil {
+				return errorMsg{err: err}
+			}
+			return diffStagedMsg{message: fmt.Sprintf("%s %s", verb, file)}
+		}
+	}
+
+	// A collapsed file shows no hunks, so the next one would be another file's
+	if m.diffMode.collapsed[file] {
+		return errorCmd(fmt.Errorf("expand %s to pick a hunk, or use S/U for the whole file", file))
+	}
+	patch := extractHunkPatch(lines, m.diffMode.scrollOffset)
+	if patch == "" {
+		return errorCmd(fmt.Errorf("no diff hunk in view"))
+	}
+	return func() tea.Msg {
+		if err := checker.ApplyToIndex(worktreePath, patch, unstage); err != nil {
+			return errorMsg{err: err}
+		}
+		return diffStagedMsg{message: fmt.Sprintf("%s hunk in %s", verb, file)}
+	}
+}
+
+func errorCmd(err error) tea.Cmd {
+	return func() tea.Msg { return errorMsg{err: err} }
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+func TestStageCurrent(t *testing.T) {
+	gitChecker := git.NewMockChecker()
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    gitChecker,
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	m := Model{stateManager: sm}
+	m.diffMode = &DiffMode{
+		session:   types.Session{Core: types.CoreSession{WorktreePath: "/wt"}},
+		diffLines: parseDiffOutput(navTestDiff),
+	}
+
+	// Hunks can only be staged from the unstaged view
+	if msg, ok := m.stageCurrent(false)().(errorMsg); !ok || !strings.Contains(msg.err.Error(), "unstaged view") {
+		t.Errorf("staging from the target view should fail, got %#v", msg)
+	}
+
+	m.diffMode.view = diffViewUnstaged
+	m.diffMode.scrollOffset = 8 // a.go's second hunk
+	if msg, ok := m.stageCurrent(false)().(diffStagedMsg); !ok || msg.message != "Staged hunk in a.go" {
+		t.Errorf("stageCurrent() = %#v, want hunk in a.go staged", msg)
+	}
+	if msg, ok := m.unstageCurrent(true)().(errorMsg); !ok {
+		t.Errorf("unstaging from the unstaged view should fail, got %#v", msg)
+	}
+
+	m.diffMode.view = m.diffMode.view.next()
+	m.diffMode.jumpToFile(1)
+	if msg, ok := m.unstageCurrent(true)().(diffStagedMsg); !ok || msg.message != "Unstaged b.go" {
+		t.Errorf("unstageCurrent() = %#v, want b.go unstaged", msg)
+	}
+
+	gitChecker.ShouldFail["/wt"] = true
+	if _, ok := m.unstageCurrent(false)().(errorMsg); !ok {
+		t.Error("a git failure should be reported")
+	}
+
+	if m.diffMode.view.next() != diffViewTarget {
+		t.Error("the view toggle should cycle back to the target view")
+	}
+}
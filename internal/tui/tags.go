@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+// handleShowTagsDialog opens the tags dialog for the selected session,
+// starting from its current tags
+func (m Model) handleShowTagsDialog() (Model, tea.Cmd) {
+	session := m.findSession(m.getSelectedSessionID())
+	if session == nil {
+		return m, nil
+	}
+
+	m.tagsDialog = &TagsDialog{
+		SessionID:   session.Core.ID,
+		SessionName: session.Core.Name,
+		Input:       strings.Join(session.Core.Tags, ", "),
+	}
+	return m, nil
+}
+
+// handleTagsDialogKeys handles keyboard input for the tags dialog
+func (m Model) handleTagsDialogKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	dialog := m.tagsDialog
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.tagsDialog = nil
+		return m, nil
+
+	case tea.KeyEnter:
+		// Validate here so a typo keeps the dialog open to fix it
+		tags, err := state.NormalizeTags(state.ParseTags(dialog.Input))
+		if err != nil {
+			dialog.Error = err.Error()
+			return m, nil
+		}
+		if m.findSession(dialog.SessionID) == nil {
+			dialog.Error = "Session no longer exists"
+			return m, nil
+		}
+		m.tagsDialog = nil
+		return m, m.setSessionTags(dialog.SessionID, dialog.SessionName, tags)
+
+	case tea.KeyBackspace:
+		if runes := []rune(dialog.Input); len(runes) > 0 {
+			dialog.Input = string(runes[:len(runes)-1])
+		}
+		dialog.Error = ""
+		return m, nil
+
+	case tea.KeySpace:
+		dialog.Input += " "
+		dialog.Error = ""
+		return m, nil
+
+	case tea.KeyRunes:
+		dialog.Input += string(msg.Runes)
+		dialog.Error = ""
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// setSessionTags replaces a session's tags; the toast's refresh shows them
+func (m Model) setSessionTags(sessionID, name string, tags []string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.stateManager.SetSessionTags(sessionID, tags); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to tag '%s': %w", name, err)}
+		}
+		if len(tags) == 0 {
+			return successToastMsg{message: fmt.Sprintf("Cleared the tags of '%s'", name)}
+		}
+		return successToastMsg{message: fmt.Sprintf("Tagged '%s' %s", name, strings.Join(tags, ", "))}
+	}
+}
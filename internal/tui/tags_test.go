@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+func TestTagsDialog(t *testing.T) {
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+	})
+	defer sm.Close()
+
+	if err := sm.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cores, _ := sm.CoreSessions()
+	if err := sm.SetSessionTags(cores[0].ID, []string{"backend"}); err != nil {
+		t.Fatalf("SetSessionTags() error = %v", err)
+	}
+	sessions, _ := sm.DeriveFreshSessions()
+	m := Model{stateManager: sm, sessions: sessions}
+	press := func(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+		return m.handleKeyPress(msg)
+	}
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if m.tagsDialog == nil || m.tagsDialog.Input != "backend" {
+		t.Fatalf("tags dialog = %+v, want it to start from the current tags", m.tagsDialog)
+	}
+
+	// An invalid tag keeps the dialog open
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(",wip?")})
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.tagsDialog == nil || cmd != nil || !strings.Contains(m.tagsDialog.Error, "invalid tag") {
+		t.Fatalf("tags dialog = %+v, want it open with an error", m.tagsDialog)
+	}
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeySpace})
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Urgent")})
+	m, cmd = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.tagsDialog != nil || cmd == nil {
+		t.Fatal("enter should close the dialog and save the tags")
+	}
+	if msg, ok := cmd().(successToastMsg); !ok || !strings.Contains(msg.message, "backend, urgent, wip") {
+		t.Errorf("tagging = %#v, want a success toast", msg)
+	}
+
+	cores, _ = sm.CoreSessions()
+	if want := []string{"backend", "urgent", "wip"}; !reflect.DeepEqual(cores[0].Tags, want) {
+		t.Errorf("tags = %q, want %q", cores[0].Tags, want)
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/types"
+)
+
+// timelineTimeFormat is how the timeline panel prints the time of each event
+const timelineTimeFormat = "01-02 15:04"
+
+// timelineCache keeps the parsed event log of the session whose timeline is
+// shown, so it's only read again when the log grows. It is shared by the
+// copies of the Model bubbletea passes around.
+type timelineCache struct {
+	sessionID string
+	size      int64
+	events    []types.SessionEvent
+}
+
+// load returns a session's timeline, reading its event log only if it
+// changed since the last call
+func (c *timelineCache) load(dataDir, sessionID string) []types.SessionEvent {
+	var size int64 = -1
+	if info, err := os.Stat(types.SessionEventLogPath(dataDir, sessionID)); err == nil {
+		size = info.Size()
+	}
+	if c.sessionID == sessionID && c.size == size {
+		return c.events
+	}
+
+	events, err := types.LoadSessionEvents(dataDir, sessionID)
+	if err != nil {
+		events = nil
+	}
+	c.sessionID, c.size, c.events = sessionID, size, events
+	return events
+}
+
+// detailLines returns the right panel content for a session: its details,
+// or its timeline or the provider panel when one is toggled on
+func (m Model) detailLines(session types.Session, width int) []string {
+	if m.showPanel {
+		return m.panelLines(session)
+	}
+	if !m.showTimeline {
+		return sessionDetailLines(session, width)
+	}
+
+	cache := m.timeline
+	if cache == nil {
+		cache = &timelineCache{}
+	}
+	return timelineLines(session, cache.load(m.stateManager.GetDataDir(), session.Core.ID))
+}
+
+// timelineLines renders a session's timeline, newest event first
+func timelineLines(session types.Session, events []types.SessionEvent) []string {
+	lines := []string{
+		fmt.Sprintf("Timeline: %s", session.Core.Name),
+		idleStyle.Render("l: back to details"),
+		"",
+	}
+	if len(events) == 0 {
+		return append(lines, idleStyle.Render("Nothing recorded yet"))
+	}
+
+	formatter := operations.NewStatusFormat()
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		line := fmt.Sprintf("%s %s", event.Time.Local().Format(timelineTimeFormat), formatter.FormatTimelineEvent(event))
+		if !types.IsLifecycleEvent(event.Type) && event.Type != operations.RecoveredEvent {
+			line = idleStyle.Render(line) // Claude's events are the background
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jlaneve/cwt-cli/internal/profile"
+)
+
+// timingsStyle frames the timings overlay
+var timingsStyle = lipgloss.NewStyle().
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(lipgloss.Color("8")).
+	Padding(0, 1)
+
+// refreshTimings breaks down where a refresh of every session spent its time
+type refreshTimings struct {
+	took   time.Duration
+	phases []profile.Timing // Render excluded, as drawing goes on during the refresh
+}
+
+// deriveTimed derives every session like a refresh, timing it when the
+// state manager has a recorder
+func (m Model) deriveTimed() tea.Msg {
+	recorder := m.stateManager.Profile()
+	before := recorder.Snapshot()
+	start := time.Now()
+
+	sessions, err := m.stateManager.DeriveFreshSessions()
+	if err != nil {
+		return errorMsg{err: fmt.Errorf("failed to refresh sessions: %w", err)}
+	}
+
+	msg := refreshCompleteMsg{sessions: sessions}
+	if recorder != nil {
+		msg.timings = &refreshTimings{took: time.Since(start)}
+		for _, timing := range recorder.Since(before) {
+			if timing.Phase != profile.PhaseRender {
+				msg.timings.phases = append(msg.timings.phases, timing)
+			}
+		}
+	}
+	return msg
+}
+
+// renderTimings renders the timings overlay: the breakdown of the last
+// refresh, and how long the screen took to draw
+func (m Model) renderTimings() string {
+	var lines []string
+	if m.refreshTimings == nil {
+		lines = append(lines, "Waiting for a refresh to time…")
+	} else {
+		// Work running alongside, like pull request checks, counts too
+		lines = append(lines, fmt.Sprintf("Last refresh: %s, calls made meanwhile:", profile.Round(m.refreshTimings.took)))
+		for _, timing := range m.refreshTimings.phases {
+			lines = append(lines, fmt.Sprintf("  %-17s %9s  %4d calls  max %s",
+				timing.Phase, profile.Round(timing.Total), timing.Calls, profile.Round(timing.Max)))
+		}
+	}
+	for _, timing := range m.stateManager.Profile().Snapshot() {
+		if timing.Phase == profile.PhaseRender {
+			lines = append(lines, fmt.Sprintf("  %-17s %9s  last draw, max %s",
+				timing.Phase, profile.Round(timing.Last), profile.Round(timing.Max)))
+		}
+	}
+	lines = append(lines, idleStyle.Render("D: hide timings"))
+	return timingsStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jlaneve/cwt-cli/internal/clients/claude"
+	"github.com/jlaneve/cwt-cli/internal/clients/git"
+	"github.com/jlaneve/cwt-cli/internal/clients/tmux"
+	"github.com/jlaneve/cwt-cli/internal/profile"
+	"github.com/jlaneve/cwt-cli/internal/state"
+)
+
+func TestTimingsOverlay(t *testing.T) {
+	sm := state.NewManager(state.Config{
+		DataDir:       t.TempDir(),
+		TmuxChecker:   tmux.NewMockChecker(),
+		GitChecker:    git.NewMockChecker(),
+		ClaudeChecker: claude.NewMockChecker(),
+		Profile:       profile.NewRecorder(),
+	})
+	defer sm.Close()
+	if err := sm.CreateSession("auth"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	m := Model{stateManager: sm, width: 100, height: 30}
+	m, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	if !m.showTimings || !strings.Contains(m.renderTimings(), "Waiting for a refresh") {
+		t.Fatalf("D should show the timings overlay, waiting for a refresh")
+	}
+
+	msg, ok := m.refreshSessions()().(refreshCompleteMsg)
+	if !ok || msg.timings == nil {
+		t.Fatalf("refresh = %+v, want it timed", msg)
+	}
+	model, _ := m.Update(msg)
+	m = model.(Model)
+	m.View()
+
+	overlay := m.renderTimings()
+	for _, want := range []string{"Last refresh", "state load", "git", "tmux", "claude scan", "render"} {
+		if !strings.Contains(overlay, want) {
+			t.Errorf("overlay lacks %q:\n%s", want, overlay)
+		}
+	}
+}
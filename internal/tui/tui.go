@@ -2,23 +2,50 @@ package tui
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/jlaneve/cwt-cli/internal/config"
+	"github.com/jlaneve/cwt-cli/internal/logging"
 	"github.com/jlaneve/cwt-cli/internal/state"
 )
 
-// Run starts the TUI with the given state manager, creating a seamless loop
-func Run(stateManager *state.Manager) error {
+// LogFileName is the file in the data directory the log goes to while the
+// dashboard runs, unless it was sent to a file already
+const LogFileName = "cwt.log"
+
+// Run starts the TUI with the given state manager, creating a seamless loop.
+// When reload is set, the dashboard re-reads the config with it whenever the
+// config files change and applies the settings that can change at runtime.
+func Run(stateManager *state.Manager, cfg *config.Config, reload func() (*config.Config, error)) error {
+	// Log lines written to stderr would draw over the dashboard
+	if err := logging.ToFileIfStderr(filepath.Join(stateManager.GetDataDir(), LogFileName)); err != nil {
+		return err
+	}
+
+	// The first dashboard of a data directory manages its sessions; others
+	// only show them until it exits
+	instance := newInstanceLock(stateManager.GetDataDir())
+	readOnly := false
+	if held, err := instance.acquire(); err != nil {
+		logger.Warn("failed to take the dashboard lock", "error", err)
+	} else {
+		readOnly = !held
+	}
+	defer instance.release()
+
 	for {
 		// Create the TUI model
-		model, err := NewModel(stateManager)
+		model, err := NewModel(stateManager, cfg)
 		if err != nil {
 			return fmt.Errorf("failed to create TUI model: %w", err)
 		}
+		model.reloadConfig = reload
+		model.instance = instance
+		model.readOnly = readOnly
 
 		// Configure the program
 		p := tea.NewProgram(
@@ -35,17 +62,24 @@ func Run(stateManager *state.Manager) error {
 
 		// Check if we need to attach to a session after TUI exit
 		if m, ok := finalModel.(Model); ok {
+			// Carry reloaded settings and the sort order into the next
+			// dashboard after attaching
+			next := *m.config
+			next.TUI.Sort = m.sortOrder
+			cfg = &next
+			readOnly = m.readOnly
+			if m.configWatcher != nil {
+				m.configWatcher.Close()
+			}
+			if m.fileWatcher != nil {
+				m.fileWatcher.Close()
+			}
+
 			if sessionName := m.GetAttachOnExit(); sessionName != "" {
-				// Create logger for this function (reuse same log file)
-				logFile, err := os.OpenFile("cwt-tui-debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-				if err == nil {
-					logger := log.New(logFile, "[TUI-DEBUG] ", log.LstdFlags|log.Lshortfile)
-					logger.Printf("Run: TUI exited with attachOnExit: %s", sessionName)
-					logger.Printf("Run: Calling attachToTmuxSession")
-					logFile.Close()
-				}
+				logger.Debug("TUI exited to attach", "tmux_session", sessionName)
 
 				// Attach to tmux session
+				m.recordAttach(sessionName)
 				if err := attachToTmuxSession(sessionName); err != nil {
 					return err
 				}
@@ -6,7 +6,11 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
 
+	"github.com/jlaneve/cwt-cli/internal/operations"
+	"github.com/jlaneve/cwt-cli/internal/profile"
 	"github.com/jlaneve/cwt-cli/internal/types"
 )
 
@@ -43,6 +47,9 @@ var (
 	cleanStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 	idleStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 
+	// Border of the panel with keyboard focus
+	focusedBorderColor = lipgloss.Color("6")
+
 	// Diff view styles
 	diffHeaderStyle = lipgloss.NewStyle().
 			Bold(true).
@@ -79,6 +86,7 @@ func (m Model) View() string {
 	if !m.ready {
 		return "Loading sessions..."
 	}
+	defer m.stateManager.Profile().Track(profile.PhaseRender)()
 
 	// HEADER - Dashboard info
 	header := m.renderHeader()
@@ -86,6 +94,13 @@ func (m Model) View() string {
 	// Calculate exact middle height (no separate status area now)
 	middleHeight := m.height - 5 - 1 // header=3, actions=1
 
+	// TIMINGS - Under the header, taking room from the panels
+	var timings string
+	if m.showTimings {
+		timings = m.renderTimings()
+		middleHeight -= lipgloss.Height(timings)
+	}
+
 	// MIDDLE PANEL - Combined left and right panels
 	middle := m.renderMiddlePanel(m.width, middleHeight)
 
@@ -93,12 +108,11 @@ func (m Model) View() string {
 	actions := m.renderActions()
 
 	// Assemble everything
-	content := lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		middle,
-		actions,
-	)
+	parts := []string{header}
+	if timings != "" {
+		parts = append(parts, timings)
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, append(parts, middle, actions)...)
 
 	// Overlay dialogs
 	if m.confirmDialog != nil {
@@ -109,6 +123,33 @@ func (m Model) View() string {
 		return m.renderWithNewSessionDialog(content)
 	}
 
+	if m.sendPromptDialog != nil {
+		return m.renderWithSendPromptDialog(content)
+	}
+
+	if m.commitDialog != nil {
+		return m.renderWithCommitDialog(content)
+	}
+
+	if m.renameDialog != nil {
+		return m.renderWithRenameDialog(content)
+	}
+	if m.tagsDialog != nil {
+		return m.renderWithTagsDialog(content)
+	}
+
+	if m.showCopyMenu {
+		return m.renderWithCopyMenu(content)
+	}
+
+	if m.approval != nil {
+		return m.renderApproval()
+	}
+
+	if m.resultPanel != nil {
+		return m.renderWithResultPanel(content)
+	}
+
 	if m.showHelp {
 		return m.renderWithHelp(content)
 	}
@@ -126,12 +167,17 @@ func (m Model) renderHeader() string {
 	activeSessions := 0
 	needsAttention := 0
 
+	var urgent []string // Watched and high-priority sessions waiting for input, the ones worth an alert
+
 	for _, session := range m.sessions {
 		if session.IsAlive {
 			activeSessions++
 		}
-		if session.ClaudeStatus.State == types.ClaudeWaiting {
+		if session.ClaudeStatus.State == types.ClaudeWaiting && !session.Core.IsMuted() {
 			needsAttention++
+			if alertsFor(session) {
+				urgent = append(urgent, session.Core.Name)
+			}
 		}
 	}
 
@@ -139,6 +185,26 @@ func (m Model) renderHeader() string {
 	if needsAttention > 0 {
 		summary += fmt.Sprintf(", %d need attention", needsAttention)
 	}
+	if len(urgent) > 0 {
+		summary += "  " + deadStyle.Render(fmt.Sprintf("[🔔 waiting on you: %s]", strings.Join(urgent, ", ")))
+	}
+
+	// Filter indicator, with a cursor while the filter is being typed
+	if m.filtering || m.filterQuery != "" {
+		filter := "/" + m.filterQuery
+		if m.filtering {
+			filter += "_"
+		}
+		summary += "  " + waitingStyle.Render(fmt.Sprintf("[filter: %s  %d/%d]", filter, len(m.visibleSessions()), totalSessions))
+	}
+
+	if marked := len(m.markedSessions()); marked > 0 {
+		summary += "  " + workingStyle.Render(fmt.Sprintf("[%d marked]", marked))
+	}
+
+	if m.readOnly {
+		summary += "  " + waitingStyle.Render("[read-only: another dashboard is open]")
+	}
 
 	// Header with proper styling and natural height
 	return lipgloss.NewStyle().
@@ -180,24 +246,38 @@ func (m Model) renderMiddlePanel(width int, height int) string {
 
 // renderLeftPanel renders the session list on the left side
 func (m Model) renderLeftPanel(width int, height int) string {
-	totalItems := len(m.sessions) + len(m.creatingSessions)
+	sessions := m.visibleSessions()
+	totalItems := len(sessions) + len(m.creatingSessions)
 	if totalItems == 0 {
 		content := "No sessions found.\n\nPress 'n' to create a new session."
-		return lipgloss.NewStyle().
-			Width(width).
-			Height(height).
-			Border(lipgloss.NormalBorder()).
-			Padding(1).
-			Render(content)
+		if m.filterQuery != "" {
+			content = fmt.Sprintf("No sessions match '%s'.\n\nPress Esc to clear the filter.", m.filterQuery)
+		} else if hidden := m.hiddenMutedCount(); hidden > 0 {
+			content = fmt.Sprintf("All %d sessions are muted.\n\nPress 'z' to show them.", hidden)
+		}
+		return panelStyle(width, height, !m.detailFocused).Render(content)
 	}
 
 	var lines []string
-	lines = append(lines, "Sessions:")
+	lines = append(lines, fmt.Sprintf("Sessions (by %s):", m.sortOrder))
 	lines = append(lines, "")
 
 	// Track current item index for selection
 	itemIndex := 0
 
+	// Marked sessions get a check mark column while any are marked
+	showMarks := len(m.markedSessions()) > 0
+	markColumn := func(marked bool) (string, int) {
+		switch {
+		case !showMarks:
+			return "", 0
+		case marked:
+			return workingStyle.Render("✓") + " ", 2
+		default:
+			return "  ", 2
+		}
+	}
+
 	// Show creating sessions first
 	for name := range m.creatingSessions {
 		// Selection indicator on the far left
@@ -215,11 +295,12 @@ func (m Model) renderLeftPanel(width int, height int) string {
 		sessionName := name + " (creating...)"
 
 		// Build the session line
-		sessionPart := fmt.Sprintf("%s %s %s", selectionIndicator, creatingIndicator, sessionName)
+		mark, markVisual := markColumn(false)
+		sessionPart := fmt.Sprintf("%s %s%s %s", selectionIndicator, mark, creatingIndicator, sessionName)
 
 		// Calculate spacing - no git indicator for creating sessions
-		contentWidth := width - 4                             // Account for border and padding
-		sessionPartVisual := 1 + 1 + 1 + 1 + len(sessionName) // selection + space + indicator + space + name
+		contentWidth := width - 4                                          // Account for border and padding
+		sessionPartVisual := 1 + 1 + markVisual + 1 + 1 + len(sessionName) // selection + space + mark + indicator + space + name
 
 		spacesNeeded := contentWidth - sessionPartVisual
 		if spacesNeeded < 0 {
@@ -232,7 +313,7 @@ func (m Model) renderLeftPanel(width int, height int) string {
 	}
 
 	// Show existing sessions
-	for _, session := range m.sessions {
+	for _, session := range sessions {
 		// Selection indicator on the far left
 		var selectionIndicator string
 		if itemIndex == m.selectedIndex {
@@ -253,12 +334,28 @@ func (m Model) renderLeftPanel(width int, height int) string {
 		// Git changes indicator on the right
 		gitIndicator := getGitIndicator(session.GitStatus)
 
-		// Build the session part with selection and claude indicators
-		sessionPart := fmt.Sprintf("%s %s %s", selectionIndicator, claudeIndicator, name)
+		// Build the session part with selection, mark and claude indicators,
+		// and the follow-up badge after the name
+		mark, markVisual := markColumn(m.marked[session.Core.ID])
+		badge, badgeVisual := followUpBadge(session.Core.FollowUp)
+		if pr, prVisual := pullRequestBadge(session.Core.PullRequest); pr != "" {
+			badge, badgeVisual = pr+badge, prVisual+badgeVisual
+		}
+		if priority, priorityVisual := priorityBadge(session.Core.SessionPriority()); priority != "" {
+			badge, badgeVisual = priority+badge, priorityVisual+badgeVisual
+		}
+		if watched, watchedVisual := attentionBadge(session.Core); watched != "" {
+			badge, badgeVisual = watched+badge, watchedVisual+badgeVisual
+		}
+		shownName := name
+		if session.Core.IsMuted() {
+			shownName = idleStyle.Render(name)
+		}
+		sessionPart := fmt.Sprintf("%s %s%s %s%s", selectionIndicator, mark, claudeIndicator, shownName, badge)
 
 		// Calculate spacing for right-aligned git indicator
-		contentWidth := width - 4                      // Account for border and padding
-		sessionPartVisual := 1 + 1 + 1 + 1 + len(name) // selection + space + claude + space + name
+		contentWidth := width - 4                                                 // Account for border and padding
+		sessionPartVisual := 1 + 1 + markVisual + 1 + 1 + len(name) + badgeVisual // selection + space + mark + claude + space + name + badge
 		gitIndicatorVisual := getGitIndicatorVisualLength(session.GitStatus)
 
 		spacesNeeded := contentWidth - sessionPartVisual - gitIndicatorVisual
@@ -271,19 +368,19 @@ func (m Model) renderLeftPanel(width int, height int) string {
 		itemIndex++
 	}
 
+	if hidden := m.hiddenMutedCount(); hidden > 0 {
+		lines = append(lines, "", idleStyle.Render(fmt.Sprintf("  🔇 %d muted (z to show)", hidden)))
+	}
+
 	content := strings.Join(lines, "\n")
 
-	return lipgloss.NewStyle().
-		Width(width).
-		Height(height).
-		Border(lipgloss.NormalBorder()).
-		Padding(1).
-		Render(content)
+	return panelStyle(width, height, !m.detailFocused).Render(content)
 }
 
 // renderRightPanel renders the detailed view of the selected session
 func (m Model) renderRightPanel(width int, height int) string {
-	totalItems := len(m.sessions) + len(m.creatingSessions)
+	sessions := m.visibleSessions()
+	totalItems := len(sessions) + len(m.creatingSessions)
 	if totalItems == 0 || m.selectedIndex >= totalItems {
 		var lines []string
 		lines = append(lines, "No session selected")
@@ -308,12 +405,7 @@ func (m Model) renderRightPanel(width int, height int) string {
 		}
 
 		content := strings.Join(lines, "\n")
-		return lipgloss.NewStyle().
-			Width(width).
-			Height(height).
-			Border(lipgloss.NormalBorder()).
-			Padding(1).
-			Render(content)
+		return panelStyle(width, height, m.detailFocused).Render(content)
 	}
 
 	// Check if we're selecting a creating session
@@ -333,11 +425,16 @@ func (m Model) renderRightPanel(width int, height int) string {
 		lines = append(lines, fmt.Sprintf("Session: %s", creatingName))
 		lines = append(lines, "")
 		lines = append(lines, "Status: Creating session...")
+		if creation := m.creatingSessions[creatingName]; creation != nil && creation.step != "" {
+			lines = append(lines, sanitizeMessage(creation.step))
+		}
 		lines = append(lines, "")
 		lines = append(lines, "Please wait while the session is being set up with:")
 		lines = append(lines, "• Git worktree")
 		lines = append(lines, "• Claude configuration")
 		lines = append(lines, "• Tmux session")
+		lines = append(lines, "")
+		lines = append(lines, idleStyle.Render("ctrl+c: cancel and remove what was created"))
 
 		// Add status area at the bottom
 		if m.lastError != "" {
@@ -359,17 +456,12 @@ func (m Model) renderRightPanel(width int, height int) string {
 		}
 
 		content := strings.Join(lines, "\n")
-		return lipgloss.NewStyle().
-			Width(width).
-			Height(height).
-			Border(lipgloss.NormalBorder()).
-			Padding(1).
-			Render(content)
+		return panelStyle(width, height, m.detailFocused).Render(content)
 	}
 
 	// Regular session - adjust index to account for creating sessions
 	sessionIndex := m.selectedIndex - len(m.creatingSessions)
-	if sessionIndex >= len(m.sessions) {
+	if sessionIndex >= len(sessions) {
 		var lines []string
 		lines = append(lines, "Session not found")
 
@@ -393,20 +485,76 @@ func (m Model) renderRightPanel(width int, height int) string {
 		}
 
 		content := strings.Join(lines, "\n")
-		return lipgloss.NewStyle().
-			Width(width).
-			Height(height).
-			Border(lipgloss.NormalBorder()).
-			Padding(1).
-			Render(content)
+		return panelStyle(width, height, m.detailFocused).Render(content)
 	}
 
-	session := m.sessions[sessionIndex]
+	session := sessions[sessionIndex]
 
+	// Long change lists scroll within the panel, leaving a row at the top
+	// and bottom for padding
+	rows := wrapDetailLines(m.detailLines(session, width), width)
+	content := strings.Join(m.visibleDetailRows(rows, height-2), "\n")
+
+	return panelStyle(width, height, m.detailFocused).Render(content)
+}
+
+// panelStyle frames one of the dashboard panels, highlighting the border of
+// the panel that has keyboard focus
+func panelStyle(width, height int, focused bool) lipgloss.Style {
+	style := lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		Border(lipgloss.NormalBorder()).
+		Padding(1)
+	if focused {
+		style = style.BorderForeground(focusedBorderColor)
+	}
+	return style
+}
+
+// sessionDetailLines returns the detail panel content for a session
+func sessionDetailLines(session types.Session, width int) []string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("Session: %s", session.Core.Name))
 	lines = append(lines, fmt.Sprintf("ID: %s", session.Core.ID))
 	lines = append(lines, fmt.Sprintf("Created: %s", session.Core.CreatedAt.Format("2006-01-02 15:04:05")))
+	if session.Core.Task != "" {
+		task := "Task: " + sanitizeMessage(session.Core.Task)
+		maxWidth := width - 4 // Account for border and padding
+		if maxWidth > 3 {
+			task = runewidth.Truncate(task, maxWidth, "...")
+		}
+		lines = append(lines, task)
+	}
+	if session.Core.CreatedBy != "" {
+		lines = append(lines, fmt.Sprintf("Created by: %s", session.Core.CreatedBy))
+	}
+	if session.Core.Source != "" {
+		lines = append(lines, fmt.Sprintf("Source: %s", session.Core.Source))
+	}
+	if session.Core.Template != "" {
+		lines = append(lines, fmt.Sprintf("Template: %s", session.Core.Template))
+	}
+	if len(session.Core.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("Tags: %s", strings.Join(session.Core.Tags, ", ")))
+	}
+	if priority := session.Core.SessionPriority(); priority != types.PriorityNormal {
+		lines = append(lines, "Priority: "+operations.NewStatusFormat().FormatPriority(priority))
+	}
+	branch := session.BranchName()
+	if session.BaseBranch != "" {
+		branch += " → " + session.BaseBranch
+	}
+	if !session.GitStatus.HasError() {
+		branch += " " + idleStyle.Render("("+operations.NewStatusFormat().FormatBranchSync(session)+")")
+	}
+	lines = append(lines, "Branch: "+branch)
+	if files := session.GitStatus.BaseConflicts; len(files) > 0 {
+		lines = append(lines, deadStyle.Render(fmt.Sprintf("  Will conflict with %s (%d):", session.BaseBranch, len(files))))
+		for _, file := range files {
+			lines = append(lines, "    "+truncateFileName(file, width-10))
+		}
+	}
 	lines = append(lines, "")
 
 	// Tmux status
@@ -417,6 +565,13 @@ func (m Model) renderRightPanel(width int, height int) string {
 		tmuxStatus = aliveStyle.Render("alive")
 	}
 	lines = append(lines, fmt.Sprintf("Tmux: %s (%s)", tmuxStatus, session.Core.TmuxSession))
+	if exit := session.Exit; exit != nil {
+		lines = append(lines, fmt.Sprintf("Exited: %s, %s", deadStyle.Render(exit.Summary()), formatActivity(exit.Time)))
+		// The last words before exiting usually say why
+		for _, line := range exit.Tail(5) {
+			lines = append(lines, idleStyle.Render("  │ "+ansi.Truncate(sanitizeMessage(line), max(width-10, 10), "…")))
+		}
+	}
 	lines = append(lines, "")
 
 	// Claude status
@@ -428,21 +583,79 @@ func (m Model) renderRightPanel(width int, height int) string {
 	if !session.ClaudeStatus.LastMessage.IsZero() {
 		lines = append(lines, fmt.Sprintf("Last activity: %s", formatActivity(session.ClaudeStatus.LastMessage)))
 	}
+	switch session.ClaudeStatus.State {
+	case types.ClaudeNotStarted:
+		lines = append(lines, idleStyle.Render("Press a to attach and start claude"))
+	case types.ClaudeNoAgent:
+		lines = append(lines, idleStyle.Render("Claude is not installed; install it or set claude_executable"))
+	}
 	lines = append(lines, "")
 
+	if followUp := session.Core.FollowUp; followUp != nil {
+		lines = append(lines, fmt.Sprintf("Follow-up: %s (on %s: %s)", followUpStatus(*followUp), followUp.On, sanitizeMessage(followUp.Command)))
+		if coverage := operations.NewStatusFormat().FormatCoverage(session); coverage != "" {
+			lines = append(lines, "Coverage: "+coverage)
+		}
+		if followUp.Result != nil && !followUp.Succeeded() {
+			for _, line := range followUp.Tail(5) {
+				lines = append(lines, idleStyle.Render("  │ "+ansi.Truncate(sanitizeMessage(line), max(width-10, 10), "…")))
+			}
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, pullRequestLines(session.Core.PullRequest)...)
+
+	// Fields from status providers
+	for _, field := range session.Extra {
+		lines = append(lines, fmt.Sprintf("%s: %s", field.Label(), sanitizeMessage(field.Value)))
+	}
+	if len(session.Extra) > 0 {
+		lines = append(lines, "")
+	}
+
 	// Git status
 	gitStatus := "clean"
-	if session.GitStatus.HasChanges {
+	if session.GitStatus.WorktreeBroken() {
+		gitStatus = deadStyle.Render("💔 broken worktree")
+	} else if session.GitStatus.HasError() {
+		gitStatus = deadStyle.Render("error")
+	} else if session.GitStatus.HasConflicts() {
+		gitStatus = deadStyle.Render("⚠ conflicts")
+	} else if session.GitStatus.HasChanges {
 		gitStatus = changesStyle.Render("has changes")
 	} else {
 		gitStatus = cleanStyle.Render("clean")
 	}
+	if staging := operations.NewStatusFormat().FormatStaging(session.GitStatus); staging != "" {
+		gitStatus += " " + idleStyle.Render("("+staging+")")
+	}
 	lines = append(lines, fmt.Sprintf("Git: %s", gitStatus))
+	if session.ChangedSinceReview {
+		lines = append(lines, changesStyle.Render("  🆕 New changes since you last viewed the diff"))
+	}
+	if session.ChangedSincePublish {
+		lines = append(lines, changesStyle.Render(fmt.Sprintf("  ⚠ Changed since revision %d was pushed", session.Core.LastPublish().Revision)))
+	}
+	if session.GitStatus.HasError() {
+		lines = append(lines, fmt.Sprintf("  %s", sanitizeMessage(session.GitStatus.Error)))
+	}
+	if session.GitStatus.WorktreeBroken() {
+		lines = append(lines, idleStyle.Render("  Press F to recreate the worktree from "+session.BranchName()))
+	}
 
 	if session.GitStatus.HasChanges {
 		// Calculate available width for file names (account for border, padding, and git prefix)
 		availableWidth := width - 10 // Border(2) + Padding(2) + Indentation(4) + GitPrefix(2)
 
+		if len(session.GitStatus.ConflictedFiles) > 0 {
+			lines = append(lines, deadStyle.Render(fmt.Sprintf("  Conflicts (%d):", len(session.GitStatus.ConflictedFiles))))
+			for _, file := range session.GitStatus.ConflictedFiles {
+				kind := " (" + file.Kind.Describe() + ")"
+				displayFile := truncateFileName(file.Path, availableWidth-len(kind))
+				lines = append(lines, fmt.Sprintf("    U %s%s", displayFile, idleStyle.Render(kind)))
+			}
+		}
 		if len(session.GitStatus.ModifiedFiles) > 0 {
 			lines = append(lines, fmt.Sprintf("  Modified (%d):", len(session.GitStatus.ModifiedFiles)))
 			for _, file := range session.GitStatus.ModifiedFiles {
@@ -476,14 +689,7 @@ func (m Model) renderRightPanel(width int, height int) string {
 	lines = append(lines, "")
 	lines = append(lines, fmt.Sprintf("Worktree: %s", session.Core.WorktreePath))
 
-	content := strings.Join(lines, "\n")
-
-	return lipgloss.NewStyle().
-		Width(width).
-		Height(height).
-		Border(lipgloss.NormalBorder()).
-		Padding(1).
-		Render(content)
+	return lines
 }
 
 // renderStatusArea renders the status/notification area between main content and actions
@@ -500,8 +706,8 @@ func (m Model) renderStatusArea() string {
 
 // renderErrorMessage handles error message rendering with 2-line support
 func (m Model) renderErrorMessage() string {
-	maxWidth := m.width - 10       // Leave some margin
-	errorMsg := "✗ " + m.lastError // Don't sanitize - preserve newlines for wrapping
+	maxWidth := m.width - 10                             // Leave some margin
+	errorMsg := "✗ " + m.lastError + m.toastActionHint() // Don't sanitize - preserve newlines for wrapping
 
 	// Split message into words for intelligent wrapping
 	words := strings.Fields(errorMsg)
@@ -557,7 +763,7 @@ func (m Model) renderErrorMessage() string {
 func (m Model) renderSuccessMessage() string {
 	// Keep success messages as single line
 	maxWidth := m.width - 10 // Leave some margin
-	successMsg := "✓ " + sanitizeMessage(m.successMessage)
+	successMsg := "✓ " + sanitizeMessage(m.successMessage) + m.toastActionHint()
 	if len(successMsg) > maxWidth {
 		successMsg = successMsg[:maxWidth-3] + "..."
 	}
@@ -566,6 +772,14 @@ func (m Model) renderSuccessMessage() string {
 	return successStyle.Height(2).Render(successMsg)
 }
 
+// toastActionHint returns the key hint for the current toast's quick action
+func (m Model) toastActionHint() string {
+	if m.toastAction == nil {
+		return ""
+	}
+	return fmt.Sprintf("  [%s: %s]", ToastActionKey, m.toastAction.Label)
+}
+
 // renderErrorMessageForPanel renders error message for right panel with 2-line support
 func (m Model) renderErrorMessageForPanel(maxWidth int) []string {
 	errorMsg := "✗ " + m.lastError
@@ -633,7 +847,16 @@ func sanitizeMessage(msg string) string {
 
 // renderActions renders the action bar at the bottom
 func (m Model) renderActions() string {
-	content := "↑↓: navigate  a/enter: attach  v: diff  s: switch  m: merge  u: publish  n: new  d: delete  c: cleanup  r: refresh  ?: help  q: quit"
+	content := "↑↓: navigate  tab: focus details  a/enter: attach  A: open in window  v: diff  s: switch  m: merge  M: approve+merge  u: publish  p: prompt  y: copy  l: timeline  i: panel  R: rename  T: tags  P: priority  w/W: watch/mute  F: repair  n: new  d: delete  c: cleanup  r: refresh  /: filter  S: sort  ?: help  q: quit"
+	if marked := len(m.markedSessions()); marked > 0 {
+		content = fmt.Sprintf("%d marked  space: mark/unmark  d: delete  c: cleanup  u: publish  m: merge  esc: clear marks  ↑↓: navigate  q: quit", marked)
+	}
+	if m.detailFocused {
+		content = "↑↓/PgUp/PgDn: scroll details  tab: focus sessions  a/enter: attach  v: diff  p: prompt  y: copy  l: timeline  i: panel  ?: help  q: quit"
+	}
+	if m.filtering {
+		content = "Type to filter by name, Claude state or git status  ↑↓: navigate  enter: apply  esc: clear"
+	}
 	return lipgloss.NewStyle().
 		Height(1).
 		Width(m.width).
@@ -643,7 +866,20 @@ func (m Model) renderActions() string {
 
 // renderWithConfirmDialog renders content with a confirmation dialog overlay
 func (m Model) renderWithConfirmDialog(content string) string {
-	dialog := fmt.Sprintf("%s\n\n[Y]es / [Enter] / [N]o", m.confirmDialog.Message)
+	dialog := m.confirmDialog.Message
+	if toggle := m.confirmDialog.Toggle; toggle != nil {
+		check := "[ ]"
+		if toggle.On {
+			check = "[x]"
+		}
+		dialog += fmt.Sprintf("\n\n%s %s (%s)", check, toggle.Label, toggle.Key)
+	}
+	if phrase := m.confirmDialog.Phrase; phrase != "" {
+		dialog += fmt.Sprintf("\n\n🔒 Protected mode. Type '%s' to continue:\n%s_", phrase, m.confirmDialog.Typed)
+		dialog += "\n\n[Enter] confirm / [Esc] cancel"
+	} else {
+		dialog += "\n\n[Y]es / [Enter] / [N]o"
+	}
 	dialogBox := confirmStyle.Render(dialog)
 
 	// Center the dialog on a clean screen
@@ -689,6 +925,160 @@ func (m Model) renderWithNewSessionDialog(content string) string {
 	)
 }
 
+// renderWithSendPromptDialog renders the send prompt dialog on a clean screen
+func (m Model) renderWithSendPromptDialog(content string) string {
+	dialog := m.sendPromptDialog
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Send Prompt to '%s'", dialog.SessionName))
+	lines = append(lines, "")
+
+	// Show what Claude is asking so the prompt can be answered without attaching
+	if session := m.findSession(dialog.SessionID); session != nil && session.ClaudeStatus.StatusMessage != "" {
+		lines = append(lines, "Claude: "+sanitizeMessage(session.ClaudeStatus.StatusMessage))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Prompt:")
+	lines = append(lines, dialog.Input+"_") // Show cursor
+	lines = append(lines, "")
+
+	if dialog.Error != "" {
+		lines = append(lines, errorStyle.Render("Error: "+dialog.Error))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Enter: send  Esc: cancel")
+
+	dialogBox := confirmStyle.Render(strings.Join(lines, "\n"))
+
+	// Center the dialog on a clean screen
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		dialogBox,
+	)
+}
+
+// renderWithRenameDialog renders the rename dialog on a clean screen
+func (m Model) renderWithRenameDialog(content string) string {
+	dialog := m.renameDialog
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Rename Session '%s'", dialog.SessionName))
+	lines = append(lines, "")
+	lines = append(lines, "Renames its branch, worktree directory and tmux session too.")
+	lines = append(lines, "")
+	lines = append(lines, "New name:")
+	lines = append(lines, dialog.Input+"_") // Show cursor
+	lines = append(lines, "")
+
+	if dialog.Error != "" {
+		lines = append(lines, errorStyle.Render("Error: "+dialog.Error))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Enter: rename  Esc: cancel")
+
+	dialogBox := confirmStyle.Render(strings.Join(lines, "\n"))
+
+	// Center the dialog on a clean screen
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		dialogBox,
+	)
+}
+
+// renderWithTagsDialog renders the tag editing dialog on a clean screen
+func (m Model) renderWithTagsDialog(content string) string {
+	dialog := m.tagsDialog
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Tags of '%s'", dialog.SessionName))
+	lines = append(lines, "")
+	lines = append(lines, "Separate tags with commas or spaces; leave empty to clear them.")
+	lines = append(lines, "")
+	lines = append(lines, "Tags:")
+	lines = append(lines, dialog.Input+"_") // Show cursor
+	lines = append(lines, "")
+
+	if dialog.Error != "" {
+		lines = append(lines, errorStyle.Render("Error: "+dialog.Error))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Enter: save  Esc: cancel")
+
+	dialogBox := confirmStyle.Render(strings.Join(lines, "\n"))
+
+	// Center the dialog on a clean screen
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		dialogBox,
+	)
+}
+
+// renderWithCommitDialog renders the commit message dialog on a clean screen
+func (m Model) renderWithCommitDialog(content string) string {
+	dialog := m.commitDialog
+
+	var lines []string
+	if m.diffMode != nil {
+		lines = append(lines, fmt.Sprintf("Commit Staged Changes in '%s'", m.diffMode.session.Core.Name))
+	} else {
+		lines = append(lines, "Commit Staged Changes")
+	}
+	lines = append(lines, "")
+	lines = append(lines, "Message:")
+	lines = append(lines, dialog.Input+"_") // Show cursor
+	lines = append(lines, "")
+
+	if dialog.Error != "" {
+		lines = append(lines, errorStyle.Render("Error: "+dialog.Error))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Enter: commit  Esc: cancel")
+
+	dialogBox := confirmStyle.Render(strings.Join(lines, "\n"))
+
+	// Center the dialog on a clean screen
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		dialogBox,
+	)
+}
+
+// renderWithCopyMenu renders the clipboard copy menu on a clean screen
+func (m Model) renderWithCopyMenu(content string) string {
+	var lines []string
+	lines = append(lines, "Copy to Clipboard")
+	lines = append(lines, "")
+
+	if session := m.findSession(m.getSelectedSessionID()); session != nil {
+		lines = append(lines, fmt.Sprintf("Session: %s", session.Core.Name))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "  p  Worktree path")
+	lines = append(lines, "  b  Branch name")
+	lines = append(lines, "  u  Pull request URL")
+	lines = append(lines, "")
+	lines = append(lines, "Esc: cancel")
+
+	dialogBox := confirmStyle.Render(strings.Join(lines, "\n"))
+
+	// Center the dialog on a clean screen
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		dialogBox,
+	)
+}
+
 // Removed complex toast overlay system in favor of simpler status area
 
 // renderWithHelp renders content with help overlay
@@ -698,27 +1088,58 @@ func (m Model) renderWithHelp(content string) string {
 Navigation:
   ↑/k       Move up
   ↓/j       Move down
-  Scroll    Mouse wheel scrolling
+  Scroll    Mouse wheel scrolling (over details scrolls them)
+  Tab       Switch focus between session list and details
+  PgUp/PgDn Scroll session details
   Enter/a   Attach to session
+  A         Open session in a tmux window, keeping the dashboard (inside tmux)
   
 Session Actions:
   v         View diff for session changes
   s         Switch to session branch
   m         Merge session into current branch
+  M         Approve, run test_command, commit, squash merge into the
+            base branch and archive, stopping at the first failure
   u         Publish session (commit + push)
+  p         Send a prompt to Claude without attaching
+  y         Copy path, branch or PR URL
+  l         Show the session's timeline instead of its details
+  i         Show the provider panel (tui.panel) instead of the details
+  D         Show timings of the last refresh: state load, git, tmux, Claude
+  R         Rename session, its branch, worktree and tmux session
+  T         Edit session tags
+  P         Cycle session priority: normal, high, low
+  w         Watch session: keep it on top and alert when it waits
+  W         Mute session: list it collapsed and never alert
+  z         Show or collapse muted sessions
+  F         Recreate a broken worktree from the session's branch
+  Space     Mark session; d/c/u/m then act on all marked
   
 Management:
   n         Create new session
   d         Delete session
   c         Cleanup orphaned resources
   r         Refresh session list
+  /         Filter sessions (Esc clears)
+  S         Cycle sort: created, name, activity, Claude state, changes,
+            priority
+  o         Run quick action shown in a notification
   ?         Toggle this help
   q         Quit
 
 Diff View (press 'v' on session with changes):
-  ↑↓/jk     Scroll through diff
+  ↑↓        Scroll through diff
+  j/k       Next/previous file
+  Enter     Collapse/expand current file
+  n/N       Next/previous hunk
   Scroll    Mouse wheel scrolling
-  c         Toggle cached/working tree view
+  c         Cycle views: vs target, unstaged, staged
+  m         Cycle what the target view compares: including base,
+            session only, uncommitted
+  s/S       Stage hunk/file (unstaged view)
+  u/U       Unstage hunk/file (staged view)
+  C         Commit staged changes
+  y         Copy current hunk to clipboard
   r         Refresh diff
   PgUp/PgDn Fast scroll
   Esc/q     Return to main view
@@ -731,6 +1152,7 @@ Session Status:
   ✅ complete Claude task finished
   📝 changes  Git working tree has changes
   ✨ clean    Git working tree clean
+  ⚠ conflicts Merge conflicts waiting to be resolved
 
 Press ? or Esc to close help`
 
@@ -770,12 +1192,19 @@ func formatClaudeStatus(status types.ClaudeStatus) string {
 		return "complete"
 	case types.ClaudeIdle:
 		return idleStyle.Render("idle")
+	case types.ClaudeNotStarted:
+		return idleStyle.Render("not started")
+	case types.ClaudeNoAgent:
+		return deadStyle.Render("no claude")
 	default:
 		return idleStyle.Render("unknown")
 	}
 }
 
 func formatGitStatus(status types.GitStatus) string {
+	if status.HasConflicts() {
+		return deadStyle.Render("conflicts")
+	}
 	if status.HasChanges {
 		return changesStyle.Render("changes")
 	}
@@ -815,12 +1244,24 @@ func getClaudeIndicator(state types.ClaudeState) string {
 		return "◉"
 	case types.ClaudeIdle:
 		return idleStyle.Render("○")
+	case types.ClaudeNotStarted:
+		return idleStyle.Render("◌")
+	case types.ClaudeNoAgent:
+		return deadStyle.Render("✗")
 	default:
 		return idleStyle.Render("○")
 	}
 }
 
 func getGitIndicator(status types.GitStatus) string {
+	if status.HasError() {
+		return deadStyle.Render("!")
+	}
+
+	if status.HasConflicts() {
+		return deadStyle.Render(fmt.Sprintf("⚠%d", len(status.ConflictedFiles)))
+	}
+
 	if !status.HasChanges {
 		return cleanStyle.Render("◦")
 	}
@@ -834,6 +1275,48 @@ func getGitIndicator(status types.GitStatus) string {
 	return changesStyle.Render(fmt.Sprintf("+%d", total))
 }
 
+// followUpBadge marks a session's follow-up command in the session list:
+// waiting, running, passed or failed. It returns the badge and its width.
+func followUpBadge(followUp *types.FollowUp) (string, int) {
+	if followUp == nil {
+		return "", 0
+	}
+	switch {
+	case followUp.Pending():
+		return " " + idleStyle.Render("◷"), 2
+	case followUp.Running():
+		return " " + workingStyle.Render("⟳"), 2
+	case followUp.Succeeded():
+		return " " + aliveStyle.Render("✓"), 2
+	default:
+		return " " + deadStyle.Render("✗"), 2
+	}
+}
+
+// priorityBadge returns the marker shown after the names of sessions with
+// a priority other than normal, and its visual width
+func priorityBadge(priority types.Priority) (string, int) {
+	switch priority {
+	case types.PriorityHigh:
+		return " " + deadStyle.Render("!"), 2
+	case types.PriorityLow:
+		return " " + idleStyle.Render("↓"), 2
+	}
+	return "", 0
+}
+
+// followUpStatus describes a session's follow-up command for the details panel
+func followUpStatus(followUp types.FollowUp) string {
+	switch {
+	case followUp.Pending(), followUp.Running():
+		return idleStyle.Render(followUp.Summary())
+	case followUp.Succeeded():
+		return aliveStyle.Render(followUp.Summary())
+	default:
+		return deadStyle.Render(followUp.Summary())
+	}
+}
+
 func formatClaudeStatusDetail(status types.ClaudeStatus) string {
 	switch status.State {
 	case types.ClaudeWorking:
@@ -844,12 +1327,24 @@ func formatClaudeStatusDetail(status types.ClaudeStatus) string {
 		return "complete"
 	case types.ClaudeIdle:
 		return idleStyle.Render("idle")
+	case types.ClaudeNotStarted:
+		return idleStyle.Render("not started")
+	case types.ClaudeNoAgent:
+		return deadStyle.Render("no claude")
 	default:
 		return idleStyle.Render("unknown")
 	}
 }
 
 func getGitIndicatorVisualLength(status types.GitStatus) int {
+	if status.HasError() {
+		return 1 // "!"
+	}
+
+	if status.HasConflicts() {
+		return 1 + len(fmt.Sprint(len(status.ConflictedFiles))) // "⚠N"
+	}
+
 	if !status.HasChanges {
 		return 1 // "◦"
 	}
@@ -896,15 +1391,25 @@ func (m Model) renderDiffMode() string {
 
 	// Header
 	header := fmt.Sprintf("📋 Diff View: %s", m.diffMode.session.Core.Name)
-	if m.diffMode.cached {
+	switch m.diffMode.view {
+	case diffViewUnstaged:
+		header += " (unstaged changes)"
+	case diffViewStaged:
 		header += " (staged changes)"
-	} else {
-		header += fmt.Sprintf(" (vs %s)", m.diffMode.target)
+	default:
+		header += fmt.Sprintf(" (%s)", m.diffMode.mode.Describe(m.diffMode.target))
 	}
 	lines = append(lines, diffHeaderStyle.Render(header))
 
 	// Controls help
-	controls := "↑↓/jk/scroll: navigate  c: cached/working  r: refresh  esc/q: back"
+	controls := "↑↓/scroll: scroll  j/k: next/prev file  enter: collapse/expand  n/N: next/prev hunk  c: target/unstaged/staged  m: diff mode"
+	switch m.diffMode.view {
+	case diffViewUnstaged:
+		controls += "  s/S: stage hunk/file"
+	case diffViewStaged:
+		controls += "  u/U: unstage hunk/file"
+	}
+	controls += "  C: commit staged  y: copy hunk  r: refresh  esc/q: back"
 	lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(controls))
 	lines = append(lines, "")
 
@@ -912,16 +1417,87 @@ func (m Model) renderDiffMode() string {
 	headerLines := 3                            // header + controls + blank
 	contentHeight := m.height - headerLines - 1 // minus 1 for potential bottom margin
 
-	// Render unified diff content
+	// File list on the left when there's room for it beside the diff
+	sidebarWidth := 0
+	if m.width >= 80 && len(m.diffMode.files()) > 0 {
+		sidebarWidth = m.width / 4
+		if sidebarWidth > 40 {
+			sidebarWidth = 40
+		}
+	}
+
+	diffWidth := m.width - sidebarWidth
 	contentLines := m.renderDiffUnified(contentHeight)
-	lines = append(lines, contentLines...)
+	if diffWidth > 0 {
+		for i, line := range contentLines {
+			contentLines[i] = lipgloss.NewStyle().MaxWidth(diffWidth).Render(line)
+		}
+	}
+
+	if sidebarWidth == 0 {
+		lines = append(lines, contentLines...)
+		return strings.Join(lines, "\n")
+	}
+
+	sidebar := lipgloss.NewStyle().
+		Width(sidebarWidth - 2).
+		MarginRight(2).
+		Render(strings.Join(m.renderDiffFileList(sidebarWidth-2, contentHeight), "\n"))
+	content := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, strings.Join(contentLines, "\n"))
+
+	return strings.Join(lines, "\n") + "\n" + content
+}
+
+// renderDiffFileList renders the files in the diff with their line counts,
+// highlighting the file at the top of the view
+func (m Model) renderDiffFileList(width int, maxLines int) []string {
+	files := m.diffMode.files()
+	current := m.diffMode.currentFile()
+
+	// Keep the current file in view when there are more files than lines
+	start := 0
+	if current >= maxLines {
+		start = current - maxLines + 1
+	}
+
+	var lines []string
+	for i := start; i < len(files) && len(lines) < maxLines; i++ {
+		file := files[i]
 
-	return strings.Join(lines, "\n")
+		selection := " "
+		if i == current {
+			selection = "▶"
+		}
+		fold := "▾"
+		if m.diffMode.collapsed[file.Name] {
+			fold = "▸"
+		}
+
+		counts := fmt.Sprintf("+%d -%d", file.Added, file.Removed)
+		nameWidth := width - 4 - len(counts) - 1 // selection, fold and spaces, counts
+		if nameWidth < 4 {
+			nameWidth = 4
+		}
+		name := truncateFileName(file.Name, nameWidth)
+		padding := width - 4 - len(name) - len(counts)
+		if padding < 1 {
+			padding = 1
+		}
+
+		line := fmt.Sprintf("%s %s %s%s%s", selection, fold, name, strings.Repeat(" ", padding),
+			diffAddedStyle.Render(fmt.Sprintf("+%d", file.Added))+" "+diffRemovedStyle.Render(fmt.Sprintf("-%d", file.Removed)))
+		if i == current {
+			line = lipgloss.NewStyle().Bold(true).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
 }
 
 // renderDiffUnified renders the unified diff view
 func (m Model) renderDiffUnified(maxLines int) []string {
-	if len(m.diffMode.diffLines) == 0 {
+	diffLines := m.diffMode.visibleDiffLines()
+	if len(diffLines) == 0 {
 		return []string{"No diff data available"}
 	}
 
@@ -929,19 +1505,22 @@ func (m Model) renderDiffUnified(maxLines int) []string {
 	start := m.diffMode.scrollOffset
 	end := start + maxLines
 
-	if end > len(m.diffMode.diffLines) {
-		end = len(m.diffMode.diffLines)
+	if end > len(diffLines) {
+		end = len(diffLines)
 	}
 
 	for i := start; i < end; i++ {
-		line := m.diffMode.diffLines[i]
+		line := diffLines[i]
 		renderedLine := m.renderDiffLine(line, true)
+		if line.Type == DiffLineFileHeader && m.diffMode.collapsed[line.FileName] {
+			renderedLine += lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("  (collapsed)")
+		}
 		lines = append(lines, renderedLine)
 	}
 
 	// Show scroll indicator if there's more content
-	if m.diffMode.scrollOffset > 0 || end < len(m.diffMode.diffLines) {
-		scrollInfo := fmt.Sprintf("Lines %d-%d of %d", start+1, end, len(m.diffMode.diffLines))
+	if m.diffMode.scrollOffset > 0 || end < len(diffLines) {
+		scrollInfo := fmt.Sprintf("Lines %d-%d of %d", start+1, end, len(diffLines))
 		lines = append(lines, "")
 		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(scrollInfo))
 	}
@@ -978,6 +1557,13 @@ func (m Model) renderDiffLine(line DiffLine, showLineNumbers bool) string {
 		content = content[1:]
 	}
 
+	rendered := style.Render(content)
+	if prefix != "" && m.syntaxHighlighting() {
+		if highlighted, ok := m.diffMode.highlighter.render(line.FileName, content, line.Type, style); ok {
+			rendered = highlighted
+		}
+	}
+
 	var lineNumStr string
 	if showLineNumbers && line.Type != DiffLineFileHeader && line.Type != DiffLineHeader && line.Type != DiffLineHunkHeader {
 		switch line.Type {
@@ -993,8 +1579,14 @@ func (m Model) renderDiffLine(line DiffLine, showLineNumbers bool) string {
 	}
 
 	if lineNumStr != "" {
-		return lineNumStr + " " + prefix + style.Render(content)
+		return lineNumStr + " " + prefix + rendered
 	}
 
-	return prefix + style.Render(content)
+	return prefix + rendered
+}
+
+// syntaxHighlighting reports whether diff content is colored by language
+func (m Model) syntaxHighlighting() bool {
+	return m.config != nil && m.config.TUI.SyntaxHighlight &&
+		m.diffMode != nil && m.diffMode.highlighter != nil
 }
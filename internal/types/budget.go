@@ -0,0 +1,99 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TokenBudget is how much of its monthly token budget a project has used
+type TokenBudget struct {
+	Month  string  `json:"month"` // Calendar month, like "2026-10"
+	Used   int64   `json:"used"`
+	Budget int64   `json:"budget"`            // 0 when there is no budget
+	WarnAt float64 `json:"warn_at,omitempty"` // Share of Budget used at which to warn
+}
+
+// Exceeded reports whether the budget is used up
+func (b TokenBudget) Exceeded() bool {
+	return b.Budget > 0 && b.Used >= b.Budget
+}
+
+// Warning reports whether enough of the budget is used to warn about it,
+// without it being used up yet
+func (b TokenBudget) Warning() bool {
+	return b.Budget > 0 && !b.Exceeded() && float64(b.Used) >= b.WarnAt*float64(b.Budget)
+}
+
+// Percent returns the share of the budget used, in percent
+func (b TokenBudget) Percent() int {
+	if b.Budget <= 0 {
+		return 0
+	}
+	return int(b.Used * 100 / b.Budget)
+}
+
+// Summary describes the usage in a few words, like "4.2M of 5M tokens (84%) in 2026-10"
+func (b TokenBudget) Summary() string {
+	if b.Budget <= 0 {
+		return fmt.Sprintf("%s tokens in %s", FormatTokens(b.Used), b.Month)
+	}
+	return fmt.Sprintf("%s of %s tokens (%d%%) in %s", FormatTokens(b.Used), FormatTokens(b.Budget), b.Percent(), b.Month)
+}
+
+// Usage is what a session's agent used over all its conversations
+type Usage struct {
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	CacheWriteTokens int64   `json:"cache_write_tokens"`
+	CacheReadTokens  int64   `json:"cache_read_tokens"`
+	Cost             float64 `json:"cost_usd"` // Estimated, in US dollars; 0 when the agent's prices are unknown
+}
+
+// Add returns the sum of two usages
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:      u.InputTokens + other.InputTokens,
+		OutputTokens:     u.OutputTokens + other.OutputTokens,
+		CacheWriteTokens: u.CacheWriteTokens + other.CacheWriteTokens,
+		CacheReadTokens:  u.CacheReadTokens + other.CacheReadTokens,
+		Cost:             u.Cost + other.Cost,
+	}
+}
+
+// Tokens returns the tokens used, counted like the monthly budget counts
+// them: reads from the prompt cache are left out
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheWriteTokens
+}
+
+// Empty reports whether nothing was used
+func (u Usage) Empty() bool {
+	return u.Tokens() == 0 && u.CacheReadTokens == 0 && u.Cost == 0
+}
+
+// FormatCost formats an estimated cost in US dollars, like $4.20 or <$0.01
+func FormatCost(cost float64) string {
+	if cost > 0 && cost < 0.01 {
+		return "<$0.01"
+	}
+	return fmt.Sprintf("$%.2f", cost)
+}
+
+// FormatTokens abbreviates a token count, like 850, 12k or 4.2M
+func FormatTokens(tokens int64) string {
+	switch {
+	case tokens >= 1_000_000:
+		return trimDecimal(float64(tokens)/1_000_000) + "M"
+	case tokens >= 10_000:
+		return strconv.FormatInt(tokens/1000, 10) + "k"
+	case tokens >= 1000:
+		return trimDecimal(float64(tokens)/1000) + "k"
+	default:
+		return strconv.FormatInt(tokens, 10)
+	}
+}
+
+// trimDecimal formats n with one decimal, dropping it when it is zero
+func trimDecimal(n float64) string {
+	return strconv.FormatFloat(float64(int64(n*10))/10, 'f', -1, 64)
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// Coverage is how much of the code a test run covered: statements for Go
+// coverage profiles, lines for LCOV reports
+type Coverage struct {
+	Covered int64 `json:"covered"`
+	Total   int64 `json:"total"`
+}
+
+// Percent returns the share of the code covered, from 0 to 100
+func (c Coverage) Percent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(c.Covered) * 100 / float64(c.Total)
+}
+
+// Describe formats the coverage, with how it changed from base's when
+// there is one, like "81.2% (+3.4% vs main)"
+func (c Coverage) Describe(base *Coverage, baseBranch string) string {
+	text := fmt.Sprintf("%.1f%%", c.Percent())
+	if base == nil {
+		return text
+	}
+
+	delta := c.Percent() - base.Percent()
+	change := fmt.Sprintf("%+.1f%%", delta)
+	if math.Abs(delta) < 0.05 {
+		change = "±0.0%"
+	}
+	if baseBranch == "" {
+		return fmt.Sprintf("%s (%s)", text, change)
+	}
+	return fmt.Sprintf("%s (%s vs %s)", text, change, baseBranch)
+}
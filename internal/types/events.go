@@ -29,6 +29,14 @@ type SessionCreationFailed struct {
 
 func (e SessionCreationFailed) EventType() string { return "session_creation_failed" }
 
+// SessionUpdated is emitted when a session's persistent metadata changes
+type SessionUpdated struct {
+	Session  Session     `json:"session"`
+	Previous CoreSession `json:"previous"`
+}
+
+func (e SessionUpdated) EventType() string { return "session_updated" }
+
 // SessionDeleted is emitted when session deletion completes
 type SessionDeleted struct {
 	SessionID string `json:"session_id"`
@@ -36,6 +44,15 @@ type SessionDeleted struct {
 
 func (e SessionDeleted) EventType() string { return "session_deleted" }
 
+// SessionArchived is emitted when a session is moved to the archive
+type SessionArchived struct {
+	SessionID string `json:"session_id"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (e SessionArchived) EventType() string { return "session_archived" }
+
 // SessionDeletionFailed is emitted when session deletion fails
 type SessionDeletionFailed struct {
 	SessionID string `json:"session_id"`
@@ -78,3 +95,10 @@ type RefreshCompleted struct {
 }
 
 func (e RefreshCompleted) EventType() string { return "refresh_completed" }
+
+// ConfigReloaded is emitted when a running process applies changed config files
+type ConfigReloaded struct {
+	Changed []string `json:"changed"` // Top-level settings that changed, like "polling"
+}
+
+func (e ConfigReloaded) EventType() string { return "config_reloaded" }
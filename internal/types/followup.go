@@ -0,0 +1,100 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FollowUpTrigger is the session state a follow-up command waits for
+type FollowUpTrigger string
+
+const (
+	FollowUpOnComplete FollowUpTrigger = "complete" // Claude reports its task complete
+)
+
+// followUpOutputLines is how many lines of a follow-up's output are kept
+const followUpOutputLines = 40
+
+// FollowUp is a one-shot command the daemon runs in a session's worktree
+// once the session reaches a state, like running the tests when Claude is done
+type FollowUp struct {
+	On        FollowUpTrigger `json:"on"`
+	Command   string          `json:"command"` // Run with sh -c
+	CreatedAt time.Time       `json:"created_at"`
+	StartedAt *time.Time      `json:"started_at,omitempty"` // Nil until the trigger fires
+	Result    *FollowUpResult `json:"result,omitempty"`     // Nil until the command finishes
+}
+
+// FollowUpResult records how a follow-up command ended
+type FollowUpResult struct {
+	FinishedAt time.Time `json:"finished_at"`
+	ExitCode   int       `json:"exit_code"`        // -1 when it couldn't run or was killed
+	Error      string    `json:"error,omitempty"`  // Why it couldn't run or finish
+	Output     string    `json:"output,omitempty"` // Last lines of its combined output
+
+	Coverage     *Coverage `json:"coverage,omitempty"`      // Read from the coverage profile the command wrote
+	BaseCoverage *Coverage `json:"base_coverage,omitempty"` // The same command's coverage on the base branch
+}
+
+// NewFollowUpResult records a follow-up that finished at a time, keeping the
+// end of its output
+func NewFollowUpResult(finishedAt time.Time, exitCode int, output string, err error) FollowUpResult {
+	result := FollowUpResult{
+		FinishedAt: finishedAt,
+		ExitCode:   exitCode,
+		Output:     lastLines(output, followUpOutputLines),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Pending reports whether the follow-up is still waiting for its trigger
+func (f FollowUp) Pending() bool {
+	return f.StartedAt == nil
+}
+
+// Running reports whether the follow-up has started and not yet finished
+func (f FollowUp) Running() bool {
+	return f.StartedAt != nil && f.Result == nil
+}
+
+// Succeeded reports whether the follow-up finished with exit status 0
+func (f FollowUp) Succeeded() bool {
+	return f.Result != nil && f.Result.ExitCode == 0 && f.Result.Error == ""
+}
+
+// Summary describes the follow-up's progress in a few words, like
+// "failed (exit status 2)"
+func (f FollowUp) Summary() string {
+	switch {
+	case f.Pending():
+		return fmt.Sprintf("waiting for %s", f.On)
+	case f.Running():
+		return "running"
+	case f.Succeeded():
+		return "passed"
+	case f.Result.Error != "":
+		return "failed: " + f.Result.Error
+	default:
+		return fmt.Sprintf("failed (exit status %d)", f.Result.ExitCode)
+	}
+}
+
+// Tail returns up to n of the last lines of the follow-up's output
+func (f FollowUp) Tail(n int) []string {
+	if f.Result == nil {
+		return nil
+	}
+	return f.Result.Tail(n)
+}
+
+// Tail returns up to n of the last lines of the command's output
+func (r FollowUpResult) Tail(n int) []string {
+	if r.Output == "" {
+		return nil
+	}
+	return strings.Split(lastLines(r.Output, n), "\n")
+}
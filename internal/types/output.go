@@ -0,0 +1,335 @@
+package types
+
+import (
+	"time"
+)
+
+// OutputSchemaVersion is the version of the machine-readable output format.
+// Bump it whenever a field is renamed or removed so scripts can detect it.
+const OutputSchemaVersion = 1
+
+// SessionListOutput is the top-level JSON document emitted by
+// `cwt list --json` and `cwt status --json`
+type SessionListOutput struct {
+	Version  int                  `json:"version"`
+	Sessions []SessionOutput      `json:"sessions"`
+	Summary  *StatusSummaryOutput `json:"summary,omitempty"`
+}
+
+// SessionOutput is the stable, machine-readable view of a session.
+// It is decoupled from Session so internal refactors don't break consumers.
+type SessionOutput struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	WorktreePath string             `json:"worktree_path"`
+	TmuxSession  string             `json:"tmux_session"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Task         string             `json:"task,omitempty"`
+	CreatedBy    string             `json:"created_by,omitempty"`
+	Source       string             `json:"source,omitempty"`
+	Template     string             `json:"template,omitempty"`
+	Tags         []string           `json:"tags,omitempty"`
+	Priority     Priority           `json:"priority"`
+	Attention    Attention          `json:"attention,omitempty"`
+	Branch       string             `json:"branch"`
+	BaseBranch   string             `json:"base_branch,omitempty"`
+	TmuxAlive    bool               `json:"tmux_alive"`
+	Git          GitStatusOutput    `json:"git"`
+	Claude       ClaudeStatusOutput `json:"claude"`
+	LastActivity *time.Time         `json:"last_activity,omitempty"`
+	Exit         *ExitOutput        `json:"exit,omitempty"`
+	Extra        []StatusField      `json:"extra,omitempty"` // Fields added by status providers
+	FollowUp     *FollowUp          `json:"follow_up,omitempty"`
+	PullRequest  *PullRequest       `json:"pull_request,omitempty"`
+	Agent        string             `json:"agent"`
+	ClaudeArgs   []string           `json:"claude_args,omitempty"` // Arguments its agent is started with
+
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty"` // When the session's diff was last viewed
+	ChangedSinceReview bool       `json:"changed_since_review"`
+
+	Publishes           []Publish `json:"publishes,omitempty"` // Pushes of its branch, oldest first
+	ChangedSincePublish bool      `json:"changed_since_publish"`
+
+	Usage *Usage `json:"usage,omitempty"` // What its agent used, where the command reads it
+}
+
+// ExitOutput is the machine-readable record of how a dead session's pane exited
+type ExitOutput struct {
+	Time   time.Time `json:"time"`
+	Status int       `json:"status"`
+	Cause  ExitCause `json:"cause"`
+	Output string    `json:"output,omitempty"`
+}
+
+// GitStatusOutput is the machine-readable git working tree status
+type GitStatusOutput struct {
+	HasChanges     bool         `json:"has_changes"`
+	ModifiedFiles  []string     `json:"modified_files"`
+	AddedFiles     []string     `json:"added_files"`
+	DeletedFiles   []string     `json:"deleted_files"`
+	UntrackedFiles []string     `json:"untracked_files"`
+	CommitCount    int          `json:"commit_count"`
+	Error          string       `json:"error,omitempty"`
+	ErrorKind      GitErrorKind `json:"error_kind,omitempty"`
+
+	ConflictedFiles []ConflictedFile `json:"conflicted_files"`
+	StagedFiles     []string         `json:"staged_files"`
+	UnstagedFiles   []string         `json:"unstaged_files"`
+
+	Branch         string   `json:"branch,omitempty"`
+	BehindCount    int      `json:"behind_count"`
+	Upstream       string   `json:"upstream,omitempty"`
+	UpstreamAhead  int      `json:"upstream_ahead"`
+	UpstreamBehind int      `json:"upstream_behind"`
+	BaseConflicts  []string `json:"base_conflicts"`
+}
+
+// ClaudeStatusOutput is the machine-readable Claude activity status
+type ClaudeStatusOutput struct {
+	State         ClaudeState  `json:"state"`
+	Availability  Availability `json:"availability"`
+	LastMessage   *time.Time   `json:"last_message,omitempty"`
+	SessionID     string       `json:"session_id,omitempty"`
+	StatusMessage string       `json:"status_message,omitempty"`
+}
+
+// StatusSummaryOutput aggregates statistics across all sessions
+type StatusSummaryOutput struct {
+	Total         int `json:"total"`
+	Active        int `json:"active"`
+	Inactive      int `json:"inactive"`
+	WithChanges   int `json:"with_changes"`
+	Clean         int `json:"clean"`
+	GitErrors     int `json:"git_errors"`
+	WithConflicts int `json:"with_conflicts"`
+	Broken        int `json:"broken"`        // Sessions whose worktree is missing or no longer a git worktree
+	Behind        int `json:"behind"`        // Sessions whose branch is missing commits of the base branch
+	WillConflict  int `json:"will_conflict"` // Sessions whose branch would conflict merging into the base branch
+	Published     int `json:"published"`
+	Merged        int `json:"merged"`
+	ModifiedFiles int `json:"modified_files"`
+	AddedFiles    int `json:"added_files"`
+	DeletedFiles  int `json:"deleted_files"`
+
+	ChangedSinceReview int `json:"changed_since_review"` // Sessions with changes made since their diff was viewed
+
+	Usage    Usage `json:"usage"`     // What the agents of all sessions used
+	OverCost int   `json:"over_cost"` // Sessions estimated to cost more than limits.session_cost
+}
+
+// CommitOutput is the machine-readable identity of a commit
+type CommitOutput struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+}
+
+// MergeResultOutput is the JSON document emitted by `cwt merge --json`,
+// describing what the merge did or why it stopped
+type MergeResultOutput struct {
+	Version       int            `json:"version"`
+	Session       string         `json:"session"`
+	SessionBranch string         `json:"session_branch"`
+	Target        string         `json:"target"`
+	Squash        bool           `json:"squash"`
+	DryRun        bool           `json:"dry_run"`
+	Merged        bool           `json:"merged"`
+	Commit        string         `json:"commit,omitempty"`        // Merge (or squash) commit created on the target
+	Commits       []CommitOutput `json:"commits"`                 // Session commits brought into the target
+	Files         []string       `json:"files"`                   // Files the merge changes
+	Conflicts     []string       `json:"conflicts"`               // Files left unmerged when conflicts stopped the merge
+	ConfirmToken  string         `json:"confirm_token,omitempty"` // Token a protected-mode dry run issued
+	Cleanup       *CleanupOutput `json:"cleanup,omitempty"`       // What --cleanup removed once the merge was done
+	Error         string         `json:"error,omitempty"`
+}
+
+// CleanupOutput describes the session 'cwt merge --cleanup' deleted after
+// merging it
+type CleanupOutput struct {
+	Deleted     bool   `json:"deleted"`
+	TmuxSession string `json:"tmux_session"`
+	Worktree    string `json:"worktree"`
+	Branch      string `json:"branch,omitempty"`  // Deleted branch; empty when it was kept
+	Skipped     string `json:"skipped,omitempty"` // Why the session was kept
+}
+
+// PublishResultOutput is the JSON document emitted by `cwt publish --json`
+type PublishResultOutput struct {
+	Version  int           `json:"version"`
+	Session  string        `json:"session"`
+	Branch   string        `json:"branch"`
+	Commit   *CommitOutput `json:"commit,omitempty"` // Commit made of the session's uncommitted changes
+	Files    []string      `json:"files"`            // Files in that commit
+	Pushed   bool          `json:"pushed"`
+	Remote   string        `json:"remote,omitempty"`
+	PRURL    string        `json:"pr_url,omitempty"`
+	Warnings []string      `json:"warnings"` // Steps skipped, like pushing without a remote
+	Error    string        `json:"error,omitempty"`
+}
+
+// StreamRecordType says what a record of the event stream reports
+type StreamRecordType string
+
+const (
+	StreamSnapshot       StreamRecordType = "snapshot"        // Every session, first in the stream
+	StreamSessionAdded   StreamRecordType = "session_added"   // A session was created
+	StreamSessionUpdated StreamRecordType = "session_updated" // A session's status changed
+	StreamSessionRemoved StreamRecordType = "session_removed" // A session was deleted or archived
+	StreamEvent          StreamRecordType = "event"           // An event was added to a session's timeline
+)
+
+// StreamRecord is one line of the NDJSON stream emitted by
+// `cwt events --json`. The first record is a snapshot of every session;
+// those after it report changes since.
+type StreamRecord struct {
+	Version   int              `json:"version"`
+	Type      StreamRecordType `json:"type"`
+	Time      time.Time        `json:"time"`
+	SessionID string           `json:"session_id,omitempty"`
+	Name      string           `json:"name,omitempty"`     // Session name
+	Sessions  []SessionOutput  `json:"sessions,omitempty"` // Snapshot records
+	Session   *SessionOutput   `json:"session,omitempty"`  // Added and updated sessions
+	Event     *SessionEvent    `json:"event,omitempty"`    // Event records
+}
+
+// NewMergeResultOutput starts the result of merging a session branch
+func NewMergeResultOutput(session, sessionBranch, target string, squash bool) MergeResultOutput {
+	return MergeResultOutput{
+		Version:       OutputSchemaVersion,
+		Session:       session,
+		SessionBranch: sessionBranch,
+		Target:        target,
+		Squash:        squash,
+		Commits:       []CommitOutput{},
+		Files:         []string{},
+		Conflicts:     []string{},
+	}
+}
+
+// NewPublishResultOutput starts the result of publishing a session branch
+func NewPublishResultOutput(session, branch string) PublishResultOutput {
+	return PublishResultOutput{
+		Version:  OutputSchemaVersion,
+		Session:  session,
+		Branch:   branch,
+		Files:    []string{},
+		Warnings: []string{},
+	}
+}
+
+// NewSessionOutput converts a session into its stable output representation
+func NewSessionOutput(session Session) SessionOutput {
+	return SessionOutput{
+		ID:           session.Core.ID,
+		Name:         session.Core.Name,
+		WorktreePath: session.Core.WorktreePath,
+		TmuxSession:  session.Core.TmuxSession,
+		CreatedAt:    session.Core.CreatedAt,
+		Task:         session.Core.Task,
+		CreatedBy:    session.Core.CreatedBy,
+		Source:       session.Core.Source,
+		Template:     session.Core.Template,
+		Agent:        session.Core.AgentName(),
+		ClaudeArgs:   session.Core.ClaudeArgs,
+		Tags:         session.Core.Tags,
+		Priority:     session.Core.SessionPriority(),
+		Attention:    session.Core.Attention,
+		Branch:       session.BranchName(),
+		BaseBranch:   session.BaseBranch,
+		TmuxAlive:    session.IsAlive,
+		Git: GitStatusOutput{
+			HasChanges:     session.GitStatus.HasChanges,
+			ModifiedFiles:  nonNilStrings(session.GitStatus.ModifiedFiles),
+			AddedFiles:     nonNilStrings(session.GitStatus.AddedFiles),
+			DeletedFiles:   nonNilStrings(session.GitStatus.DeletedFiles),
+			UntrackedFiles: nonNilStrings(session.GitStatus.UntrackedFiles),
+			CommitCount:    session.GitStatus.CommitCount,
+			Error:          session.GitStatus.Error,
+			ErrorKind:      session.GitStatus.ErrorKind,
+
+			ConflictedFiles: nonNilConflicts(session.GitStatus.ConflictedFiles),
+			StagedFiles:     nonNilStrings(session.GitStatus.StagedFiles),
+			UnstagedFiles:   nonNilStrings(session.GitStatus.UnstagedFiles),
+
+			Branch:         session.GitStatus.Branch,
+			BehindCount:    session.GitStatus.BehindCount,
+			Upstream:       session.GitStatus.Upstream,
+			UpstreamAhead:  session.GitStatus.UpstreamAhead,
+			UpstreamBehind: session.GitStatus.UpstreamBehind,
+			BaseConflicts:  nonNilStrings(session.GitStatus.BaseConflicts),
+		},
+		Claude: ClaudeStatusOutput{
+			State:         session.ClaudeStatus.State,
+			Availability:  session.ClaudeStatus.Availability,
+			LastMessage:   optionalTime(session.ClaudeStatus.LastMessage),
+			SessionID:     session.ClaudeStatus.SessionID,
+			StatusMessage: session.ClaudeStatus.StatusMessage,
+		},
+		LastActivity: optionalTime(session.LastActivity),
+		Exit:         newExitOutput(session.Exit),
+		Extra:        session.Extra,
+		FollowUp:     session.Core.FollowUp,
+		PullRequest:  session.Core.PullRequest,
+
+		ReviewedAt:         reviewedAt(session.Core.Review),
+		ChangedSinceReview: session.ChangedSinceReview,
+
+		Publishes:           session.Core.Publishes,
+		ChangedSincePublish: session.ChangedSincePublish,
+	}
+}
+
+// reviewedAt returns when a session was last reviewed, or nil if never
+func reviewedAt(review *Review) *time.Time {
+	if review == nil {
+		return nil
+	}
+	return &review.At
+}
+
+// newExitOutput converts a recorded exit, returning nil if there is none
+func newExitOutput(exit *SessionExit) *ExitOutput {
+	if exit == nil {
+		return nil
+	}
+	return &ExitOutput{
+		Time:   exit.Time,
+		Status: exit.Status,
+		Cause:  exit.Cause,
+		Output: exit.Output,
+	}
+}
+
+// NewSessionListOutput converts sessions into a versioned output document
+func NewSessionListOutput(sessions []Session) SessionListOutput {
+	output := SessionListOutput{
+		Version:  OutputSchemaVersion,
+		Sessions: make([]SessionOutput, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		output.Sessions = append(output.Sessions, NewSessionOutput(session))
+	}
+	return output
+}
+
+// nonNilStrings ensures empty lists serialize as [] rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nonNilConflicts(files []ConflictedFile) []ConflictedFile {
+	if files == nil {
+		return []ConflictedFile{}
+	}
+	return files
+}
+
+// optionalTime returns nil for zero times so they are omitted from output
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSessionOutput(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	session := Session{
+		Core: CoreSession{
+			ID:           "abc",
+			Name:         "feature",
+			WorktreePath: ".cwt/worktrees/feature",
+			TmuxSession:  "cwt-feature",
+			CreatedAt:    created,
+		},
+		IsAlive: true,
+		GitStatus: GitStatus{
+			HasChanges:    true,
+			ModifiedFiles: []string{"main.go"},
+		},
+		ClaudeStatus: ClaudeStatus{
+			State:        ClaudeWaiting,
+			Availability: AvailCurrent,
+		},
+	}
+
+	output := NewSessionOutput(session)
+
+	if output.ID != "abc" || output.Name != "feature" || !output.TmuxAlive {
+		t.Errorf("Core fields not copied: %+v", output)
+	}
+	if output.Git.AddedFiles == nil || output.Git.UntrackedFiles == nil {
+		t.Error("Expected nil file lists to be converted to empty slices")
+	}
+	if output.Claude.LastMessage != nil {
+		t.Error("Expected zero LastMessage to be omitted")
+	}
+	if output.LastActivity != nil {
+		t.Error("Expected zero LastActivity to be omitted")
+	}
+}
+
+func TestNewSessionListOutputJSON(t *testing.T) {
+	data, err := json.Marshal(NewSessionListOutput(nil))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	got := string(data)
+	if !strings.Contains(got, `"version":1`) {
+		t.Errorf("Expected schema version in output, got %s", got)
+	}
+	if !strings.Contains(got, `"sessions":[]`) {
+		t.Errorf("Expected empty sessions array, got %s", got)
+	}
+	if strings.Contains(got, "summary") {
+		t.Errorf("Expected summary to be omitted, got %s", got)
+	}
+}
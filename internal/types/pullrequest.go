@@ -0,0 +1,79 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// States of a pull request
+const (
+	PullRequestOpen   = "open"
+	PullRequestMerged = "merged"
+	PullRequestClosed = "closed"
+)
+
+// Review decisions of a pull request
+const (
+	ReviewRequested        = "review_requested"
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+)
+
+// States of a pull request's CI checks
+const (
+	ChecksPending = "pending"
+	ChecksPassing = "passing"
+	ChecksFailing = "failing"
+)
+
+// PullRequest is the pull request a session was published to, with what
+// the forge said about it when last asked
+type PullRequest struct {
+	URL       string            `json:"url"`
+	Number    int               `json:"number,omitempty"`
+	Status    PullRequestStatus `json:"status"`
+	CheckedAt *time.Time        `json:"checked_at,omitempty"` // Nil until the forge is first asked
+	Error     string            `json:"error,omitempty"`      // Why the forge couldn't be asked last time
+}
+
+// PullRequestStatus is where a pull request stands on its forge
+type PullRequestStatus struct {
+	State  string `json:"state,omitempty"` // PullRequestOpen, PullRequestMerged or PullRequestClosed
+	Draft  bool   `json:"draft,omitempty"`
+	Review string `json:"review,omitempty"` // Review decision, "" when none is pending or made
+	Checks string `json:"checks,omitempty"` // CI checks, "" when there are none
+}
+
+// NewPullRequest records a pull request just opened at a URL
+func NewPullRequest(url string) *PullRequest {
+	return &PullRequest{
+		URL:    url,
+		Number: PullRequestNumber(url),
+		Status: PullRequestStatus{State: PullRequestOpen},
+	}
+}
+
+// PullRequestNumber reads the number at the end of a pull request's URL,
+// as forges write them: .../pull/12, .../merge_requests/12 or
+// .../pull-requests/12. It returns 0 when there is none.
+func PullRequestNumber(url string) int {
+	url = strings.TrimRight(url, "/")
+	number, err := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil || number < 0 {
+		return 0
+	}
+	return number
+}
+
+// Closed reports whether the pull request was merged or closed, so its
+// status won't change anymore
+func (p PullRequest) Closed() bool {
+	return p.Status.State == PullRequestMerged || p.Status.State == PullRequestClosed
+}
+
+// Due reports whether the pull request's status should be asked for again,
+// being open and older than maxAge
+func (p PullRequest) Due(now time.Time, maxAge time.Duration) bool {
+	return !p.Closed() && (p.CheckedAt == nil || now.Sub(*p.CheckedAt) >= maxAge)
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPullRequestNumber(t *testing.T) {
+	tests := map[string]int{
+		"https://github.com/acme/widgets/pull/12":                12,
+		"https://gitlab.com/acme/widgets/-/merge_requests/7/":    7,
+		"https://bitbucket.org/acme/widgets/pull-requests/3":     3,
+		"https://github.com/acme/widgets/compare/main...feature": 0,
+	}
+	for url, want := range tests {
+		if got := PullRequestNumber(url); got != want {
+			t.Errorf("PullRequestNumber(%q) = %d, want %d", url, got, want)
+		}
+	}
+}
+
+func TestPullRequest_Due(t *testing.T) {
+	now := time.Now()
+	pr := NewPullRequest("https://github.com/acme/widgets/pull/12")
+	if !pr.Due(now, time.Minute) {
+		t.Error("a pull request never checked should be due")
+	}
+
+	checked := now.Add(-30 * time.Second)
+	pr.CheckedAt = &checked
+	if pr.Due(now, time.Minute) || !pr.Due(now, 0) {
+		t.Error("a pull request should be due once older than the max age")
+	}
+
+	pr.Status.State = PullRequestMerged
+	if pr.Due(now, 0) {
+		t.Error("a merged pull request shouldn't be checked again")
+	}
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -13,6 +15,143 @@ type CoreSession struct {
 	WorktreePath string    `json:"worktree_path"`
 	TmuxSession  string    `json:"tmux_session"`
 	CreatedAt    time.Time `json:"created_at"`
+	Task         string    `json:"task,omitempty"`       // Initial prompt sent to Claude
+	CreatedBy    string    `json:"created_by,omitempty"` // User who created the session
+	Source       string    `json:"source,omitempty"`     // Issue or PR link the session was created from
+	Template     string    `json:"template,omitempty"`   // Template the session was created from
+	Tags         []string  `json:"tags,omitempty"`       // Labels for finding and grouping sessions
+	Priority     Priority  `json:"priority,omitempty"`   // Triage priority, normal when empty
+	Attention    Attention `json:"attention,omitempty"`  // On the watch list, muted, or neither when empty
+
+	ClaudeSessionID string     `json:"claude_session_id,omitempty"` // Conversation to resume, captured when paused
+	PausedAt        *time.Time `json:"paused_at,omitempty"`         // When the session was paused, nil while active
+
+	FollowUp    *FollowUp     `json:"follow_up,omitempty"`    // Command to run when the session reaches a state
+	IdleTimeout time.Duration `json:"idle_timeout,omitempty"` // Idle time before the expiry policy archives it, if longer than the policy's (nanoseconds)
+
+	Env           map[string]string `json:"env,omitempty"`            // Extra environment of its tmux session, like its recipe's
+	CopyUntracked []string          `json:"copy_untracked,omitempty"` // Globs of files copied into its worktree besides the config's
+	Agent         string            `json:"agent,omitempty"`          // Coding agent it runs, like aider; "" for Claude
+	ClaudeArgs    []string          `json:"claude_args,omitempty"`    // Arguments its agent is started with, like --model, the same on every restart
+
+	PullRequest *PullRequest `json:"pull_request,omitempty"` // Pull request the session was published to
+	Publishes   []Publish    `json:"publishes,omitempty"`    // Pushes of its branch, oldest first
+
+	Review *Review `json:"review,omitempty"` // What the worktree held when its diff was last viewed
+}
+
+// Review marks what a session's worktree held when its diff was last viewed,
+// so later changes can be told apart from those already read
+type Review struct {
+	Tree string    `json:"tree"` // Git tree of the worktree's files, committed or not
+	At   time.Time `json:"at"`
+}
+
+// Publish records one push of a session's branch, so what its pull request
+// holds can be told apart from what its worktree holds now
+type Publish struct {
+	Revision    int       `json:"revision"`               // 1 for the first push, counting up
+	Commit      string    `json:"commit"`                 // Commit pushed
+	Tree        string    `json:"tree,omitempty"`         // Snapshot of the worktree's files when pushed
+	PullRequest string    `json:"pull_request,omitempty"` // URL of the pull request the push opened or updated
+	At          time.Time `json:"at"`
+}
+
+// LastPublish returns the latest push of the session's branch, or nil if
+// it was never pushed
+func (c CoreSession) LastPublish() *Publish {
+	if len(c.Publishes) == 0 {
+		return nil
+	}
+	return &c.Publishes[len(c.Publishes)-1]
+}
+
+// IsPaused reports whether the session's tmux session was stopped on
+// purpose and should be resumed rather than cleaned up
+func (c CoreSession) IsPaused() bool {
+	return c.PausedAt != nil
+}
+
+// AgentName returns the name of the coding agent the session runs, claude
+// when it was never set
+func (c CoreSession) AgentName() string {
+	if c.Agent == "" {
+		return "claude"
+	}
+	return c.Agent
+}
+
+// Priority ranks a session for triage when many run at once
+type Priority string
+
+const (
+	PriorityHigh   Priority = "high"
+	PriorityNormal Priority = "normal"
+	PriorityLow    Priority = "low"
+)
+
+// Priorities lists the priorities from the most to the least urgent
+var Priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// ParsePriority reads a priority by name; empty means normal
+func ParsePriority(value string) (Priority, error) {
+	switch priority := Priority(strings.ToLower(strings.TrimSpace(value))); priority {
+	case "":
+		return PriorityNormal, nil
+	case PriorityHigh, PriorityNormal, PriorityLow:
+		return priority, nil
+	}
+	return "", fmt.Errorf("invalid priority %q (valid: high, normal, low)", value)
+}
+
+// Rank orders priorities for sorting, the most urgent first
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	}
+	return 1
+}
+
+// SessionPriority returns the session's priority, normal when never set
+func (c CoreSession) SessionPriority() Priority {
+	if c.Priority == "" {
+		return PriorityNormal
+	}
+	return c.Priority
+}
+
+// Attention is how much a session should draw the eye: watched sessions
+// stay at the top of lists and alert when they wait for input, muted ones
+// sink to the bottom and never alert
+type Attention string
+
+const (
+	AttentionWatched Attention = "watched"
+	AttentionMuted   Attention = "muted"
+)
+
+// IsWatched reports whether the session is on the watch list
+func (c CoreSession) IsWatched() bool {
+	return c.Attention == AttentionWatched
+}
+
+// IsMuted reports whether the session is muted
+func (c CoreSession) IsMuted() bool {
+	return c.Attention == AttentionMuted
+}
+
+// AttentionRank orders sessions for lists: watched first, muted last
+func (c CoreSession) AttentionRank() int {
+	switch c.Attention {
+	case AttentionWatched:
+		return 0
+	case AttentionMuted:
+		return 2
+	}
+	return 1
 }
 
 // Session represents the complete session state with both persistent
@@ -22,7 +161,42 @@ type Session struct {
 	IsAlive      bool         `json:"is_alive"`
 	ClaudeStatus ClaudeStatus `json:"claude_status"`
 	GitStatus    GitStatus    `json:"git_status"`
+	Branch       string       `json:"branch,omitempty"`      // Branch checked out in the worktree
+	BaseBranch   string       `json:"base_branch,omitempty"` // Branch the session was created from and merges into
 	LastActivity time.Time    `json:"last_activity"`
+	Exit         *SessionExit `json:"exit,omitempty"` // How the tmux pane ended, for dead sessions
+
+	// ChangedSinceReview reports whether the worktree's files changed since
+	// its diff was last viewed; false for sessions never reviewed
+	ChangedSinceReview bool `json:"changed_since_review,omitempty"`
+
+	// ChangedSincePublish reports whether the worktree's files changed since
+	// its branch was last pushed; false for sessions never published
+	ChangedSincePublish bool `json:"changed_since_publish,omitempty"`
+
+	Extra []StatusField `json:"extra,omitempty"` // Fields added by external status providers
+}
+
+// BranchName returns the session's branch, falling back to the branch
+// sessions are created on when it couldn't be determined
+func (s Session) BranchName() string {
+	if s.Branch != "" {
+		return s.Branch
+	}
+	return s.Core.Name
+}
+
+// StatusField is a piece of session status reported by an external status
+// provider, like the state of the session's Jira ticket
+type StatusField struct {
+	Provider string `json:"provider"` // Provider name, "jira" for cwt-status-jira
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+}
+
+// Label names the field for display, qualified by its provider
+func (f StatusField) Label() string {
+	return f.Provider + "." + f.Name
 }
 
 // ClaudeState represents the current activity state of Claude
@@ -34,6 +208,9 @@ const (
 	ClaudeComplete ClaudeState = "complete" // Task completed
 	ClaudeIdle     ClaudeState = "idle"     // Idle but ready
 	ClaudeUnknown  ClaudeState = "unknown"  // Cannot determine state
+
+	ClaudeNotStarted ClaudeState = "not_started" // No transcript or hook event yet
+	ClaudeNoAgent    ClaudeState = "no_agent"    // Not started, and Claude isn't installed to start
 )
 
 // Availability represents how recent the Claude activity is
@@ -55,14 +232,108 @@ type ClaudeStatus struct {
 	StatusMessage string       `json:"status_message,omitempty"` // Human-readable status from Claude
 }
 
+// GitErrorKind classifies why the git status of a worktree could not be read
+type GitErrorKind string
+
+const (
+	GitErrorMissingWorktree GitErrorKind = "missing_worktree" // Worktree directory does not exist
+	GitErrorNotRepository   GitErrorKind = "not_a_repository" // Directory is not a git worktree
+	GitErrorCommandFailed   GitErrorKind = "git_failure"      // git exited with an unexpected error
+)
+
 // GitStatus represents the git working tree status
 type GitStatus struct {
-	HasChanges     bool     `json:"has_changes"`
-	ModifiedFiles  []string `json:"modified_files"`
-	AddedFiles     []string `json:"added_files"`
-	DeletedFiles   []string `json:"deleted_files"`
-	UntrackedFiles []string `json:"untracked_files"`
-	CommitCount    int      `json:"commit_count"`
+	HasChanges     bool         `json:"has_changes"`
+	ModifiedFiles  []string     `json:"modified_files"`
+	AddedFiles     []string     `json:"added_files"`
+	DeletedFiles   []string     `json:"deleted_files"`
+	UntrackedFiles []string     `json:"untracked_files"`
+	CommitCount    int          `json:"commit_count"`
+	Error          string       `json:"error,omitempty"`      // Why the status could not be read
+	ErrorKind      GitErrorKind `json:"error_kind,omitempty"` // Classification of Error
+
+	// ConflictedFiles are left unmerged by a merge, rebase or cherry-pick
+	ConflictedFiles []ConflictedFile `json:"conflicted_files,omitempty"`
+
+	// StagedFiles have changes in the index and UnstagedFiles changes in
+	// the working tree yet to be staged; a file partly staged is in both
+	StagedFiles   []string `json:"staged_files,omitempty"`
+	UnstagedFiles []string `json:"unstaged_files,omitempty"`
+
+	// Where HEAD stands. CommitCount is the commits it is ahead of the base
+	// branch, BehindCount those on the base branch it doesn't have yet.
+	Branch         string `json:"branch,omitempty"` // Branch checked out, empty when HEAD is detached
+	BehindCount    int    `json:"behind_count,omitempty"`
+	Upstream       string `json:"upstream,omitempty"` // Remote branch the branch tracks, if any
+	UpstreamAhead  int    `json:"upstream_ahead,omitempty"`
+	UpstreamBehind int    `json:"upstream_behind,omitempty"`
+
+	// BaseConflicts are the files that would conflict if the branch were
+	// merged into the base branch now, predicted without touching the
+	// worktree. Only committed changes are considered.
+	BaseConflicts []string `json:"base_conflicts,omitempty"`
+}
+
+// HasError reports whether the status could not be determined
+func (g GitStatus) HasError() bool {
+	return g.ErrorKind != ""
+}
+
+// WorktreeBroken reports whether the worktree directory is gone or no longer
+// a git worktree, so it has to be recreated from its branch to be used again
+func (g GitStatus) WorktreeBroken() bool {
+	return g.ErrorKind == GitErrorMissingWorktree || g.ErrorKind == GitErrorNotRepository
+}
+
+// HasConflicts reports whether files are waiting for merge conflicts to be resolved
+func (g GitStatus) HasConflicts() bool {
+	return len(g.ConflictedFiles) > 0
+}
+
+// ConflictKind is how the two sides of a merge clashed over a file
+type ConflictKind string
+
+const (
+	ConflictBothModified  ConflictKind = "both_modified"   // UU
+	ConflictBothAdded     ConflictKind = "both_added"      // AA
+	ConflictBothDeleted   ConflictKind = "both_deleted"    // DD
+	ConflictAddedByUs     ConflictKind = "added_by_us"     // AU
+	ConflictAddedByThem   ConflictKind = "added_by_them"   // UA
+	ConflictDeletedByUs   ConflictKind = "deleted_by_us"   // DU
+	ConflictDeletedByThem ConflictKind = "deleted_by_them" // UD
+)
+
+// Describe returns the kind in git's words, like "both modified"
+func (k ConflictKind) Describe() string {
+	return strings.ReplaceAll(string(k), "_", " ")
+}
+
+// ConflictedFile is a file with unresolved merge conflicts
+type ConflictedFile struct {
+	Path string       `json:"path"`
+	Kind ConflictKind `json:"kind"`
+}
+
+// String describes the file and its conflict, like "main.go (both modified)"
+func (f ConflictedFile) String() string {
+	return fmt.Sprintf("%s (%s)", f.Path, f.Kind.Describe())
+}
+
+// FileChange describes how a session changed a file
+type FileChange string
+
+const (
+	FileAdded     FileChange = "added"
+	FileModified  FileChange = "modified"
+	FileDeleted   FileChange = "deleted"
+	FileUntracked FileChange = "untracked"
+)
+
+// ChangedFile is a file a session changed relative to the base branch
+type ChangedFile struct {
+	Path      string     `json:"path"`
+	Change    FileChange `json:"change"`
+	Committed bool       `json:"committed"` // Changed by the session's commits rather than only in its worktree
 }
 
 // ClaudeMessage represents a parsed JSONL message from Claude
@@ -83,3 +354,13 @@ type Content struct {
 type SessionData struct {
 	Sessions []CoreSession `json:"sessions"`
 }
+
+// ArchivedSession is a session moved out of the active list. Its worktree and
+// tmux session are removed, but its branch and metadata are kept.
+type ArchivedSession struct {
+	Core         CoreSession `json:"core"`
+	Branch       string      `json:"branch"`
+	ArchivedAt   time.Time   `json:"archived_at"`
+	LastActivity time.Time   `json:"last_activity"`
+	Reason       string      `json:"reason,omitempty"` // Why it was archived, like "idle for 7d"
+}
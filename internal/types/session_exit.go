@@ -0,0 +1,170 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExitCause classifies why the process in a session's tmux pane exited
+type ExitCause string
+
+const (
+	ExitNormal ExitCause = "exited" // Exited with status 0
+	ExitCrash  ExitCause = "crash"  // Exited with an error
+	ExitLogout ExitCause = "logout" // Claude needs the user to log in again
+	ExitQuota  ExitCause = "quota"  // Usage or rate limit reached
+)
+
+// exitOutputLines is how many lines of pane output are kept with an exit
+const exitOutputLines = 40
+
+// exitPatterns map telltale output to a cause, checked in order
+var exitPatterns = []struct {
+	cause    ExitCause
+	patterns []string
+}{
+	{ExitQuota, []string{"usage limit", "rate limit", "quota", "credit balance", "limit reached"}},
+	{ExitLogout, []string{"/login", "invalid api key", "not logged in", "logged out", "authentication", "oauth token"}},
+}
+
+// SessionExit records how a session's tmux pane ended, captured by the
+// pane-died hook the moment it happened
+type SessionExit struct {
+	Time   time.Time `json:"time"`
+	Status int       `json:"status"` // -1 when the process was killed by a signal
+	Cause  ExitCause `json:"cause"`
+	Output string    `json:"output,omitempty"` // Last lines shown in the pane
+}
+
+// NewSessionExit diagnoses an exit at a time from the pane's exit status and
+// output
+func NewSessionExit(at time.Time, status int, output string) SessionExit {
+	output = lastLines(output, exitOutputLines)
+	return SessionExit{
+		Time:   at,
+		Status: status,
+		Cause:  diagnoseExit(status, output),
+		Output: output,
+	}
+}
+
+func diagnoseExit(status int, output string) ExitCause {
+	lower := strings.ToLower(output)
+	for _, entry := range exitPatterns {
+		for _, pattern := range entry.patterns {
+			if strings.Contains(lower, pattern) {
+				return entry.cause
+			}
+		}
+	}
+	if status != 0 {
+		return ExitCrash
+	}
+	return ExitNormal
+}
+
+// Summary describes the exit in a few words, like "quota error (exit status 1)"
+func (e SessionExit) Summary() string {
+	var description string
+	switch e.Cause {
+	case ExitNormal:
+		description = "exited normally"
+	case ExitCrash:
+		description = "crashed"
+	case ExitLogout:
+		description = "logged out"
+	case ExitQuota:
+		description = "quota error"
+	default:
+		description = "exited"
+	}
+	if e.Status < 0 {
+		return description + " (killed by a signal)"
+	}
+	return fmt.Sprintf("%s (exit status %d)", description, e.Status)
+}
+
+// Tail returns up to n of the last non-empty lines of captured output
+func (e SessionExit) Tail(n int) []string {
+	if e.Output == "" {
+		return nil
+	}
+	return strings.Split(lastLines(e.Output, n), "\n")
+}
+
+// lastLines returns the last n lines of output, ignoring trailing blank
+// lines and tmux's own "Pane is dead" notice
+func lastLines(output string, n int) string {
+	lines := strings.Split(output, "\n")
+	end := len(lines)
+	for end > 0 {
+		line := strings.TrimSpace(lines[end-1])
+		if line != "" && !strings.HasPrefix(line, "Pane is dead") {
+			break
+		}
+		end--
+	}
+	start := end - n
+	if start < 0 {
+		start = 0
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// sessionExitPath returns the path of a session's recorded exit
+func sessionExitPath(dataDir, sessionID string) string {
+	return filepath.Join(dataDir, "sessions", sessionID, "exit.json")
+}
+
+// SaveSessionExit records how a session's pane exited
+func SaveSessionExit(dataDir, sessionID string, exit SessionExit) error {
+	path := sessionExitPath(dataDir, sessionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(exit, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session exit: %w", err)
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session exit: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to save session exit: %w", err)
+	}
+	return nil
+}
+
+// LoadSessionExit returns the recorded exit of a session, or nil if none was recorded
+func LoadSessionExit(dataDir, sessionID string) (*SessionExit, error) {
+	data, err := os.ReadFile(sessionExitPath(dataDir, sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session exit: %w", err)
+	}
+
+	var exit SessionExit
+	if err := json.Unmarshal(data, &exit); err != nil {
+		return nil, fmt.Errorf("failed to parse session exit: %w", err)
+	}
+	return &exit, nil
+}
+
+// RemoveSessionExit forgets a session's recorded exit, once it runs again
+func RemoveSessionExit(dataDir, sessionID string) error {
+	err := os.Remove(sessionExitPath(dataDir, sessionID))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
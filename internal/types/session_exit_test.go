@@ -0,0 +1,87 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSessionExit(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		output      string
+		wantCause   ExitCause
+		wantSummary string
+	}{
+		{"clean exit", 0, "Goodbye!", ExitNormal, "exited normally (exit status 0)"},
+		{"crash", 1, "panic: something broke", ExitCrash, "crashed (exit status 1)"},
+		{"signal", -1, "", ExitCrash, "crashed (killed by a signal)"},
+		{"quota", 1, "Claude usage limit reached. Your limit will reset at 5pm.", ExitQuota, "quota error (exit status 1)"},
+		{"logout", 1, "Invalid API key · Please run /login", ExitLogout, "logged out (exit status 1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exit := NewSessionExit(time.Now(), tt.status, tt.output)
+			if exit.Cause != tt.wantCause {
+				t.Errorf("Cause = %q, want %q", exit.Cause, tt.wantCause)
+			}
+			if got := exit.Summary(); got != tt.wantSummary {
+				t.Errorf("Summary() = %q, want %q", got, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestSessionExit_Tail(t *testing.T) {
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, strings.Repeat("x", i+1))
+	}
+	output := strings.Join(lines, "\n") + "\n\n\nPane is dead (status 1, Thu Oct 16 10:00:00 2026)\n"
+
+	exit := NewSessionExit(time.Now(), 1, output)
+	if got := len(strings.Split(exit.Output, "\n")); got != exitOutputLines {
+		t.Errorf("kept %d lines, want %d", got, exitOutputLines)
+	}
+
+	tail := exit.Tail(2)
+	if len(tail) != 2 || tail[1] != lines[49] || tail[0] != lines[48] {
+		t.Errorf("Tail(2) = %q, want the last two lines of output", tail)
+	}
+
+	if tail := (SessionExit{}).Tail(5); tail != nil {
+		t.Errorf("Tail() without output = %q, want nil", tail)
+	}
+}
+
+func TestSessionExitRoundTrip(t *testing.T) {
+	dataDir := t.TempDir()
+
+	if exit, err := LoadSessionExit(dataDir, "missing"); exit != nil || err != nil {
+		t.Fatalf("LoadSessionExit() of an unrecorded exit = %v, %v; want nil, nil", exit, err)
+	}
+
+	want := NewSessionExit(time.Now(), 1, "usage limit reached")
+	if err := SaveSessionExit(dataDir, "abc", want); err != nil {
+		t.Fatalf("SaveSessionExit() error = %v", err)
+	}
+	got, err := LoadSessionExit(dataDir, "abc")
+	if err != nil || got == nil {
+		t.Fatalf("LoadSessionExit() = %v, %v", got, err)
+	}
+	if got.Cause != want.Cause || got.Status != want.Status || got.Output != want.Output || !got.Time.Equal(want.Time) {
+		t.Errorf("LoadSessionExit() = %+v, want %+v", got, want)
+	}
+
+	if err := RemoveSessionExit(dataDir, "abc"); err != nil {
+		t.Fatalf("RemoveSessionExit() error = %v", err)
+	}
+	if err := RemoveSessionExit(dataDir, "abc"); err != nil {
+		t.Errorf("removing a missing exit should not fail, got %v", err)
+	}
+	if exit, _ := LoadSessionExit(dataDir, "abc"); exit != nil {
+		t.Error("exit should be gone after RemoveSessionExit()")
+	}
+}
@@ -1,16 +1,20 @@
 package types
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 )
 
-// SessionState represents real-time state for a session
-// This is updated by hooks and other external events
+// SessionState represents real-time state for a session.
+// It is a snapshot derived from the session's append-only event log, which
+// hooks and other external events write to.
 type SessionState struct {
 	SessionID     string                 `json:"session_id"`
 	ClaudeState   string                 `json:"claude_state"` // "working", "waiting_for_input", "complete", "idle"
@@ -19,13 +23,238 @@ type SessionState struct {
 	LastEventData map[string]interface{} `json:"last_event_data,omitempty"`
 	LastMessage   string                 `json:"last_message,omitempty"` // Human-readable message from Claude
 	LastUpdated   time.Time              `json:"last_updated"`
+	EventCount    int                    `json:"event_count,omitempty"` // Events folded into this snapshot
+	LogSize       int64                  `json:"log_size,omitempty"`    // Bytes of the event log folded into this snapshot
 }
 
-// LoadSessionState loads session state from the dedicated state file
+// SessionEvent is one entry in a session's append-only event log
+type SessionEvent struct {
+	Type        string                 `json:"type"` // "notification", "stop", "preToolUse", etc.
+	Time        time.Time              `json:"time"`
+	ClaudeState string                 `json:"claude_state,omitempty"`
+	Message     string                 `json:"message,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+}
+
+// Lifecycle events cwt records in a session's event log, next to the ones
+// Claude's hooks send, so the log doubles as the session's timeline
+const (
+	EventCreated    = "created"
+	EventAttached   = "attached"
+	EventCommitted  = "committed"
+	EventMerged     = "merged"
+	EventPublished  = "published"
+	EventPaused     = "paused"
+	EventResumed    = "resumed"
+	EventRenamed    = "renamed"
+	EventArchived   = "archived"
+	EventRestored   = "restored"
+	EventRepaired   = "repaired"
+	EventRebased    = "rebased"
+	EventMergedBase = "merged_base" // The base branch was merged into the session's branch
+	EventFollowUp   = "follow_up"   // A follow-up command finished
+	EventApproved   = "approved"    // The session's changes were approved for merging
+	EventStopped    = "stopped"     // Its tmux session was ended by 'cwt shutdown --kill-tmux'
+	EventHook       = "hook"        // A lifecycle hook from the config ran
+)
+
+// lifecycleEvents are the event types that say what happened to a session
+// rather than what Claude is doing
+var lifecycleEvents = map[string]bool{
+	EventCreated:    true,
+	EventAttached:   true,
+	EventCommitted:  true,
+	EventMerged:     true,
+	EventPublished:  true,
+	EventPaused:     true,
+	EventResumed:    true,
+	EventRenamed:    true,
+	EventArchived:   true,
+	EventRestored:   true,
+	EventRepaired:   true,
+	EventRebased:    true,
+	EventMergedBase: true,
+	EventFollowUp:   true,
+	EventApproved:   true,
+	EventStopped:    true,
+	EventHook:       true,
+}
+
+// IsLifecycleEvent reports whether an event type is one cwt records about a
+// session, as opposed to a Claude hook event
+func IsLifecycleEvent(eventType string) bool {
+	return lifecycleEvents[eventType]
+}
+
+// Apply folds an event into the snapshot. Lifecycle events are counted but
+// leave Claude's state alone.
+func (s *SessionState) Apply(event SessionEvent) {
+	if IsLifecycleEvent(event.Type) {
+		s.EventCount++
+		return
+	}
+	if event.ClaudeState != "" {
+		s.ClaudeState = event.ClaudeState
+	}
+	s.LastEvent = event.Type
+	s.LastEventTime = event.Time
+	s.LastEventData = event.Data
+	s.LastMessage = event.Message
+	s.LastUpdated = event.Time
+	s.EventCount++
+}
+
+// SessionEventLogName is the file name of each session's event log
+const SessionEventLogName = "events.jsonl"
+
+// SessionEventLogPath returns the path of a session's event log
+func SessionEventLogPath(dataDir, sessionID string) string {
+	return filepath.Join(dataDir, "sessions", sessionID, SessionEventLogName)
+}
+
+// sessionStatePath returns the path of a session's derived snapshot
+func sessionStatePath(dataDir, sessionID string) string {
+	return filepath.Join(dataDir, "session-state", sessionID+".json")
+}
+
+// LoadSessionState loads the derived snapshot for a session, folding in any
+// events appended to the log since the snapshot was written
 func LoadSessionState(dataDir, sessionID string) (*SessionState, error) {
-	stateFile := filepath.Join(dataDir, "session-state", sessionID+".json")
+	state, err := loadSnapshot(dataDir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(SessionEventLogPath(dataDir, sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil // Snapshot written before event logs existed, or nothing yet
+		}
+		return nil, fmt.Errorf("failed to stat session event log: %w", err)
+	}
+
+	if state == nil || state.LogSize > info.Size() {
+		// No snapshot, or the log was replaced; rebuild from the start
+		state = &SessionState{SessionID: sessionID}
+	}
+	if state.LogSize == info.Size() {
+		return state, nil
+	}
+
+	if err := replayEvents(dataDir, sessionID, state.LogSize, func(event SessionEvent, end int64) {
+		state.Apply(event)
+		state.LogSize = end
+	}); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// LoadSessionEvents returns every event recorded for a session, oldest first
+func LoadSessionEvents(dataDir, sessionID string) ([]SessionEvent, error) {
+	events := []SessionEvent{}
+	err := replayEvents(dataDir, sessionID, 0, func(event SessionEvent, _ int64) {
+		events = append(events, event)
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return events, nil
+}
 
-	data, err := os.ReadFile(stateFile)
+// LoadSessionEventsAfter returns the events appended to a session's log past
+// offset, along with the offset to read on from. A session without a log has
+// no events yet.
+func LoadSessionEventsAfter(dataDir, sessionID string, offset int64) ([]SessionEvent, int64, error) {
+	events := []SessionEvent{}
+	err := replayEvents(dataDir, sessionID, offset, func(event SessionEvent, end int64) {
+		events = append(events, event)
+		offset = end
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, offset, err
+	}
+	return events, offset, nil
+}
+
+// AppendSessionEvent appends an event to the session's log and refreshes the
+// derived snapshot. Appends from concurrent hooks never overwrite each other;
+// a snapshot that lost a race is caught up by the next load. The caller
+// stamps the event's Time.
+func AppendSessionEvent(dataDir, sessionID string, event SessionEvent) (*SessionState, error) {
+	logPath := SessionEventLogPath(dataDir, sessionID)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create session event directory: %w", err)
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session event: %w", err)
+	}
+
+	// A single O_APPEND write keeps each event on its own line even with
+	// several writers
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session event log: %w", err)
+	}
+	_, writeErr := file.Write(append(line, '\n'))
+	closeErr := file.Close()
+	if writeErr != nil {
+		return nil, fmt.Errorf("failed to append session event: %w", writeErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to append session event: %w", closeErr)
+	}
+
+	state, err := LoadSessionState(dataDir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := SaveSessionState(dataDir, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// replayEvents calls fn for each complete event in the log after offset,
+// along with the offset just past that event
+func replayEvents(dataDir, sessionID string, offset int64, fn func(event SessionEvent, end int64)) error {
+	file, err := os.Open(SessionEventLogPath(dataDir, sessionID))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek session event log: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			offset += int64(len(line))
+
+			var event SessionEvent
+			if jsonErr := json.Unmarshal(line, &event); jsonErr == nil {
+				fn(event, offset)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil // A trailing partial line is still being written
+			}
+			return fmt.Errorf("failed to read session event log: %w", err)
+		}
+	}
+}
+
+// loadSnapshot reads the derived snapshot file, returning nil if it doesn't exist
+func loadSnapshot(dataDir, sessionID string) (*SessionState, error) {
+	data, err := os.ReadFile(sessionStatePath(dataDir, sessionID))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No state file yet
@@ -41,25 +270,33 @@ func LoadSessionState(dataDir, sessionID string) (*SessionState, error) {
 	return &state, nil
 }
 
-// SaveSessionState saves session state to the dedicated state file
+// SaveSessionState saves the derived snapshot to the dedicated state file
 func SaveSessionState(dataDir string, state *SessionState) error {
 	stateDir := filepath.Join(dataDir, "session-state")
 	if err := os.MkdirAll(stateDir, 0755); err != nil {
 		return fmt.Errorf("failed to create session state directory: %w", err)
 	}
 
-	stateFile := filepath.Join(stateDir, state.SessionID+".json")
+	stateFile := sessionStatePath(dataDir, state.SessionID)
 
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal session state: %w", err)
 	}
 
-	// Atomic write using temporary file
-	tempFile := stateFile + ".tmp"
-	if err := os.WriteFile(tempFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp state file: %w", err)
+	// Atomic write using a unique temporary file, since several hooks may save at once
+	temp, err := os.CreateTemp(stateDir, state.SessionID+".json.*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+	tempFile := temp.Name()
+	_, writeErr := temp.Write(data)
+	closeErr := temp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write temp state file: %w", errors.Join(writeErr, closeErr))
 	}
+	os.Chmod(tempFile, 0644)
 
 	if err := os.Rename(tempFile, stateFile); err != nil {
 		os.Remove(tempFile) // Cleanup temp file
@@ -69,14 +306,13 @@ func SaveSessionState(dataDir string, state *SessionState) error {
 	return nil
 }
 
-// RemoveSessionState removes the session state file
+// RemoveSessionState removes the session state snapshot and event log
 func RemoveSessionState(dataDir, sessionID string) error {
-	stateFile := filepath.Join(dataDir, "session-state", sessionID+".json")
-	err := os.Remove(stateFile)
-	if os.IsNotExist(err) {
-		return nil // Already removed
+	err := os.Remove(sessionStatePath(dataDir, sessionID))
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
-	return err
+	return os.RemoveAll(filepath.Dir(SessionEventLogPath(dataDir, sessionID)))
 }
 
 // ParseClaudeStateFromEvent determines Claude state from hook event data
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAppendSessionEvent(t *testing.T) {
+	dataDir := t.TempDir()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []SessionEvent{
+		{Type: "preToolUse", Time: now, ClaudeState: "working"},
+		{Type: "notification", Time: now.Add(time.Second), ClaudeState: "waiting_for_input", Message: "Claude needs your permission"},
+	}
+	for _, event := range events {
+		if _, err := AppendSessionEvent(dataDir, "session-1", event); err != nil {
+			t.Fatalf("AppendSessionEvent() error = %v", err)
+		}
+	}
+
+	state, err := LoadSessionState(dataDir, "session-1")
+	if err != nil {
+		t.Fatalf("LoadSessionState() error = %v", err)
+	}
+	if state.ClaudeState != "waiting_for_input" || state.LastEvent != "notification" {
+		t.Errorf("state = %s/%s, want waiting_for_input/notification", state.ClaudeState, state.LastEvent)
+	}
+	if state.LastMessage != "Claude needs your permission" {
+		t.Errorf("LastMessage = %q", state.LastMessage)
+	}
+	if state.EventCount != 2 {
+		t.Errorf("EventCount = %d, want 2", state.EventCount)
+	}
+
+	history, err := LoadSessionEvents(dataDir, "session-1")
+	if err != nil {
+		t.Fatalf("LoadSessionEvents() error = %v", err)
+	}
+	if len(history) != 2 || history[0].Type != "preToolUse" {
+		t.Errorf("LoadSessionEvents() = %+v, want both events in order", history)
+	}
+	if !history[0].Time.Equal(now) {
+		t.Errorf("event time = %v, want %v", history[0].Time, now)
+	}
+}
+
+func TestAppendSessionEvent_Lifecycle(t *testing.T) {
+	dataDir := t.TempDir()
+
+	AppendSessionEvent(dataDir, "session-1", SessionEvent{Type: "stop", ClaudeState: "complete", Message: "Done"})
+	state, err := AppendSessionEvent(dataDir, "session-1", SessionEvent{Type: EventMerged, Message: "Merged into main"})
+	if err != nil {
+		t.Fatalf("AppendSessionEvent() error = %v", err)
+	}
+
+	// Merging says nothing about what Claude is doing
+	if state.ClaudeState != "complete" || state.LastEvent != "stop" || state.LastMessage != "Done" {
+		t.Errorf("state = %s/%s/%q, want Claude's last event kept", state.ClaudeState, state.LastEvent, state.LastMessage)
+	}
+	if state.EventCount != 2 {
+		t.Errorf("EventCount = %d, want 2", state.EventCount)
+	}
+
+	history, _ := LoadSessionEvents(dataDir, "session-1")
+	if len(history) != 2 || history[1].Type != EventMerged {
+		t.Errorf("LoadSessionEvents() = %+v, want the merge in the timeline", history)
+	}
+}
+
+func TestLoadSessionState_CatchesUpStaleSnapshot(t *testing.T) {
+	dataDir := t.TempDir()
+
+	if _, err := AppendSessionEvent(dataDir, "session-1", SessionEvent{Type: "preToolUse", ClaudeState: "working"}); err != nil {
+		t.Fatalf("AppendSessionEvent() error = %v", err)
+	}
+
+	// Append directly, as if another hook's snapshot write lost the race
+	line, _ := json.Marshal(SessionEvent{Type: "stop", ClaudeState: "complete", Time: time.Now()})
+	file, err := os.OpenFile(SessionEventLogPath(dataDir, "session-1"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	file.Write(append(line, '\n'))
+	file.Close()
+
+	state, err := LoadSessionState(dataDir, "session-1")
+	if err != nil {
+		t.Fatalf("LoadSessionState() error = %v", err)
+	}
+	if state.ClaudeState != "complete" || state.EventCount != 2 {
+		t.Errorf("state = %s with %d events, want complete with 2", state.ClaudeState, state.EventCount)
+	}
+}
+
+func TestAppendSessionEvent_Concurrent(t *testing.T) {
+	dataDir := t.TempDir()
+	const writers = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := AppendSessionEvent(dataDir, "session-1", SessionEvent{Type: "preToolUse", ClaudeState: "working"}); err != nil {
+				t.Errorf("AppendSessionEvent() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	state, err := LoadSessionState(dataDir, "session-1")
+	if err != nil {
+		t.Fatalf("LoadSessionState() error = %v", err)
+	}
+	if state.EventCount != writers {
+		t.Errorf("EventCount = %d, want %d (no event may be lost)", state.EventCount, writers)
+	}
+}
+
+func TestLoadSessionState_LegacySnapshot(t *testing.T) {
+	dataDir := t.TempDir()
+
+	legacy := &SessionState{SessionID: "old", ClaudeState: "idle", LastEvent: "postToolUse"}
+	if err := SaveSessionState(dataDir, legacy); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+
+	state, err := LoadSessionState(dataDir, "old")
+	if err != nil {
+		t.Fatalf("LoadSessionState() error = %v", err)
+	}
+	if state == nil || state.LastEvent != "postToolUse" {
+		t.Errorf("LoadSessionState() = %+v, want legacy snapshot", state)
+	}
+
+	if err := RemoveSessionState(dataDir, "old"); err != nil {
+		t.Fatalf("RemoveSessionState() error = %v", err)
+	}
+	if state, _ := LoadSessionState(dataDir, "old"); state != nil {
+		t.Errorf("LoadSessionState() after remove = %+v, want nil", state)
+	}
+}
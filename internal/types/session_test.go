@@ -0,0 +1,22 @@
+package types
+
+import "testing"
+
+func TestParsePriority(t *testing.T) {
+	tests := map[string]Priority{"": PriorityNormal, "high": PriorityHigh, " Low ": PriorityLow, "normal": PriorityNormal}
+	for value, want := range tests {
+		if got, err := ParsePriority(value); err != nil || got != want {
+			t.Errorf("ParsePriority(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParsePriority("urgent"); err == nil {
+		t.Error("ParsePriority(urgent) should fail")
+	}
+
+	if (CoreSession{}).SessionPriority() != PriorityNormal {
+		t.Error("a session without a priority should be normal")
+	}
+	if PriorityHigh.Rank() >= PriorityNormal.Rank() || PriorityNormal.Rank() >= PriorityLow.Rank() {
+		t.Error("ranks should order high, normal, low")
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -66,6 +68,35 @@ func ExecuteCWTCommand(subcommand string, args ...string) error {
 	return nil
 }
 
+// ExecuteCWTCommandJSON executes a CWT command that writes a JSON result to
+// stdout, decoding it into result. The result is decoded even when the
+// command fails, since it describes how far the command got; the error then
+// carries what the command printed to stderr.
+func ExecuteCWTCommandJSON(result interface{}, subcommand string, args ...string) error {
+	baseCmd := GetCWTCommand()
+	fullArgs := append(baseCmd[1:], subcommand)
+	fullArgs = append(fullArgs, args...)
+
+	cmd := exec.Command(baseCmd[0], fullArgs...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if stdout.Len() > 0 {
+		if err := json.Unmarshal(stdout.Bytes(), result); err != nil && runErr == nil {
+			return fmt.Errorf("failed to decode output of cwt %s: %w", subcommand, err)
+		}
+	}
+	if runErr != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%s: %w", message, runErr)
+		}
+		return runErr
+	}
+	return nil
+}
+
 // GetCWTExecutablePath returns just the executable path/command for CWT
 func GetCWTExecutablePath() string {
 	cmd := GetCWTCommand()
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrLocked is returned by TryLockFile when another holder has the lock
+var ErrLocked = errors.New("file is locked")
+
+// FileLock is an exclusive advisory lock on a file, shared by every process
+// that locks the same path. The operating system releases it when its
+// holder exits, so a crashed holder never leaves it behind.
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile locks path, creating it and its directory, and waits for the
+// current holder to unlock it
+func LockFile(path string) (*FileLock, error) {
+	return lockFile(path, true)
+}
+
+// TryLockFile locks path like LockFile, but returns ErrLocked instead of
+// waiting when it is held already
+func TryLockFile(path string) (*FileLock, error) {
+	return lockFile(path, false)
+}
+
+func lockFile(path string, wait bool) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lock(file, wait); err != nil {
+		file.Close()
+		if errors.Is(err, ErrLocked) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", filepath.Base(path), err)
+	}
+	return &FileLock{file: file}, nil
+}
+
+// File returns the locked file, for holders that record who they are in it
+func (l *FileLock) File() *os.File {
+	return l.file
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	err := l.file.Close() // Closing the file releases the lock
+	l.file = nil
+	return err
+}
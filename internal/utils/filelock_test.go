@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestTryLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "test.lock")
+	held, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("LockFile() error = %v", err)
+	}
+
+	if _, err := TryLockFile(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("TryLockFile() of a held lock = %v, want ErrLocked", err)
+	}
+
+	if err := held.Unlock(); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	lock, err := TryLockFile(path)
+	if err != nil {
+		t.Fatalf("TryLockFile() after unlocking = %v", err)
+	}
+	lock.Unlock()
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lock takes an exclusive flock on file, returning ErrLocked when wait is
+// false and another holder has it
+func lock(file *os.File, wait bool) error {
+	how := syscall.LOCK_EX
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+	for {
+		err := syscall.Flock(int(file.Fd()), how)
+		if errors.Is(err, syscall.EINTR) {
+			continue
+		}
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return ErrLocked
+		}
+		return err
+	}
+}
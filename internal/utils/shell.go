@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// envNamePattern matches the names POSIX shells accept for variables
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ShellQuote quotes s for safe use as a single argument in a POSIX shell command
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// IsEnvName reports whether name can name an environment variable
+func IsEnvName(name string) bool {
+	return envNamePattern.MatchString(name)
+}
+
+// ShellQuoteArgs quotes each of args for a POSIX shell command, separated by spaces
+func ShellQuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ShellSplit splits s into arguments the way a POSIX shell does, honoring
+// single and double quotes and backslash escapes, without expanding anything
+func ShellSplit(s string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var inArg bool
+	var quote rune
+	var escaped bool
+	for _, r := range s {
+		switch {
+		case escaped:
+			// A backslash in double quotes only escapes what it can
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
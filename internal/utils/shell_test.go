@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"simple", "'simple'"},
+		{"with spaces", "'with spaces'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME; rm -rf /", "'$HOME; rm -rf /'"},
+		{"", "''"},
+	}
+
+	for _, tt := range tests {
+		if got := ShellQuote(tt.input); got != tt.expected {
+			t.Errorf("ShellQuote(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestIsEnvName(t *testing.T) {
+	for _, name := range []string{"PATH", "_private", "NODE_ENV2"} {
+		if !IsEnvName(name) {
+			t.Errorf("IsEnvName(%q) = false", name)
+		}
+	}
+	for _, name := range []string{"", "2FA", "NODE-ENV", "A B", "A=B"} {
+		if IsEnvName(name) {
+			t.Errorf("IsEnvName(%q) = true", name)
+		}
+	}
+}
+
+func TestShellSplit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"--model opus", []string{"--model", "opus"}},
+		{`  --append-system-prompt "Be terse, don't guess"  `, []string{"--append-system-prompt", "Be terse, don't guess"}},
+		{`--allowedTools 'Bash(git log:*)' Edit`, []string{"--allowedTools", "Bash(git log:*)", "Edit"}},
+		{`a\ b "c\"d" "e\f" ''`, []string{"a b", `c"d`, `e\f`, ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := ShellSplit(tt.input)
+		if err != nil || !slices.Equal(got, tt.expected) {
+			t.Errorf("ShellSplit(%q) = %q, %v, expected %q", tt.input, got, err, tt.expected)
+		}
+		if err == nil && len(got) > 0 {
+			if again, _ := ShellSplit(ShellQuoteArgs(got)); !slices.Equal(again, got) {
+				t.Errorf("ShellSplit(ShellQuoteArgs(%q)) = %q", got, again)
+			}
+		}
+	}
+
+	for _, input := range []string{`"open`, `it's`, `trailing\`} {
+		if _, err := ShellSplit(input); err == nil {
+			t.Errorf("ShellSplit(%q) should fail", input)
+		}
+	}
+}